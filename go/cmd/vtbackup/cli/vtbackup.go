@@ -69,6 +69,7 @@ const (
 	phaseNameInitialBackup               = "InitialBackup"
 	phaseNameRestoreLastBackup           = "RestoreLastBackup"
 	phaseNameTakeNewBackup               = "TakeNewBackup"
+	phaseNameVerifyBackup                = "VerifyBackup"
 	phaseStatusCatchupReplicationStalled = "Stalled"
 	phaseStatusCatchupReplicationStopped = "Stopped"
 
@@ -87,6 +88,8 @@ var (
 	initSQLTabletTypes  []topodatapb.TabletType
 	initSQLTimeout      time.Duration
 	initSQLFailOnError  bool
+	verifyOnly          bool
+	verifyBackupName    string
 
 	// vttablet-like flags
 	initDbNameOverride string
@@ -135,6 +138,7 @@ var (
 		phaseNameInitialBackup,
 		phaseNameRestoreLastBackup,
 		phaseNameTakeNewBackup,
+		phaseNameVerifyBackup,
 	}
 	phaseStatus = stats.NewGaugesWithMultiLabels(
 		"PhaseStatus",
@@ -195,7 +199,14 @@ to the handling of the query path.
 The command-line parameters to vtbackup specify a policy for when a new backup
 is needed, and when old backups should be removed. If the existing backups
 already satisfy the policy, then vtbackup will do nothing and return success
-immediately.`,
+immediately.
+
+With --verify-only, vtbackup neither takes nor removes backups. Instead it
+restores the most recent full backup (or the one named by --verify-backup-name)
+into a temporary mysqld, checks that the restored replication position matches
+the backup MANIFEST, runs CHECK TABLE and CHECKSUM TABLE on all tables, and
+records the verification time in the backup. This can be run periodically to
+prove that backups are restorable.`,
 		Version: servenv.AppVersion.String(),
 		Args:    cobra.NoArgs,
 		PreRunE: servenv.CobraPreRunE,
@@ -221,6 +232,8 @@ func init() {
 	Main.Flags().Var((*topoproto.TabletTypeListFlag)(&initSQLTabletTypes), "init-backup-tablet-types", "Tablet types used for the backup where the init SQL queries (--init-backup-sql-queries) will be executed after catch-up replication, before initializing the backup")
 	Main.Flags().DurationVar(&initSQLTimeout, "init-backup-sql-timeout", initSQLTimeout, "At what point should we time out the init SQL query (--init-backup-sql-queries) work and either fail the backup job (--init-backup-sql-fail-on-error) or continue on with the backup")
 	Main.Flags().BoolVar(&initSQLFailOnError, "init-backup-sql-fail-on-error", false, "Whether or not to fail the backup if the init SQL queries (--init-backup-sql-queries) fail, which includes if they fail to complete before the specified timeout (--init-backup-sql-timeout)")
+	Main.Flags().BoolVar(&verifyOnly, "verify-only", verifyOnly, "Instead of taking a new backup and pruning old ones, restore a full backup into a temporary mysqld, check the restored data, and record the verification in the backup.")
+	Main.Flags().StringVar(&verifyBackupName, "verify-backup-name", verifyBackupName, "Name of the full backup to verify with --verify-only. Defaults to the most recent full backup.")
 
 	// vttablet-like flags
 	utils.SetFlagStringVar(Main.Flags(), &initDbNameOverride, "init-db-name-override", initDbNameOverride, "(init parameter) override the name of the db used by vttablet")
@@ -297,6 +310,14 @@ func run(cc *cobra.Command, args []string) error {
 		}
 	}
 
+	if verifyOnly {
+		if err := verifyBackup(ctx); err != nil {
+			return fmt.Errorf("Failed to verify backup: %w", err)
+		}
+		log.Info("Exiting.")
+		return nil
+	}
+
 	// Try to take a backup, if it's been long enough since the last one.
 	// Skip pruning if backup wasn't fully successful. We don't want to be
 	// deleting things if the backup process is not healthy.
@@ -493,6 +514,77 @@ func takeBackup(ctx context.Context, topoServer *topo.Server, backupStorage back
 	return nil
 }
 
+// verifyBackup restores a full backup into a temporary mysqld, checks the
+// restored data, and records the verification in the backup.
+func verifyBackup(ctx context.Context) error {
+	phase.Set(phaseNameVerifyBackup, int64(1))
+	defer phase.Set(phaseNameVerifyBackup, int64(0))
+
+	// As in takeBackup, the random UID keeps the data dir unique.
+	bigN, err := rand.Int(rand.Reader, big.NewInt(math.MaxUint32))
+	if err != nil {
+		return fmt.Errorf("can't generate random tablet UID: %v", err)
+	}
+	tabletAlias := &topodatapb.TabletAlias{
+		Cell: "vtbackup",
+		Uid:  uint32(bigN.Uint64()),
+	}
+
+	tabletDir := mysqlctl.TabletDir(tabletAlias.Uid)
+	defer func() {
+		log.Info(fmt.Sprintf("Removing temporary tablet directory: %v", tabletDir))
+		if err := os.RemoveAll(tabletDir); err != nil {
+			log.Warn(fmt.Sprintf("Failed to remove temporary tablet directory: %v", err))
+		}
+	}()
+
+	mysqld, mycnf, err := mysqlctl.CreateMysqldAndMycnf(tabletAlias.Uid, mysqlSocket, mysqlPort, collationEnv)
+	if err != nil {
+		return fmt.Errorf("failed to initialize mysql config: %v", err)
+	}
+
+	initCtx, initCancel := context.WithTimeout(ctx, mysqlTimeout)
+	defer initCancel()
+	if err := mysqld.Init(initCtx, mycnf, initDBSQLFile); err != nil {
+		return fmt.Errorf("failed to initialize mysql data dir and start mysqld: %v", err)
+	}
+	defer func() {
+		mysqlShutdownCtx, mysqlShutdownCancel := context.WithTimeout(context.Background(), mysqlShutdownTimeout+10*time.Second)
+		defer mysqlShutdownCancel()
+		if err := mysqld.Shutdown(mysqlShutdownCtx, mycnf, false, mysqlShutdownTimeout); err != nil {
+			log.Error(fmt.Sprintf("failed to shutdown mysqld: %v", err))
+		}
+	}()
+
+	dbName := initDbNameOverride
+	if dbName == "" {
+		dbName = "vt_" + initKeyspace
+	}
+
+	params := mysqlctl.RestoreParams{
+		Cnf:    mycnf,
+		Mysqld: mysqld,
+		Logger: logutil.NewConsoleLogger(),
+		HookExtraEnv: map[string]string{
+			"TABLET_ALIAS": topoproto.TabletAliasString(tabletAlias),
+		},
+		Concurrency:          concurrency,
+		DeleteBeforeRestore:  true,
+		DbName:               dbName,
+		Keyspace:             initKeyspace,
+		Shard:                initShard,
+		Stats:                backupstats.RestoreStats(),
+		MysqlShutdownTimeout: mysqlShutdownTimeout,
+		BackupName:           verifyBackupName,
+	}
+	verification, err := mysqlctl.VerifyBackup(ctx, params)
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Verified backup %v at replication position %v", verification.BackupName, verification.Position))
+	return nil
+}
+
 // restoreForBackup runs the restore step and returns the position the rest of
 // the backup job should use.
 func restoreForBackup(ctx context.Context, mysqlTermHandler *mySQLTermHandler, topoServer *topo.Server, mysqld mysqlctl.MysqlDaemon, mycnf *mysqlctl.Mycnf, dbName string, extraEnv map[string]string) (replication.Position, error) {
@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/utils"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var backupRetentionInterval time.Duration
+
+func init() {
+	utils.SetFlagDurationVar(Main.Flags(), &backupRetentionInterval, "backup-retention-interval", backupRetentionInterval, "How often the backup retention policies of keyspaces are applied, removing the backups they do not keep. Zero disables it.")
+}
+
+func initBackupRetention(ctx context.Context) {
+	// Start backup retention enforcement if needed.
+	if backupRetentionInterval <= 0 {
+		return
+	}
+
+	timer := timer.NewTimer(backupRetentionInterval)
+	timer.Start(func() {
+		applyBackupRetention(ctx)
+	})
+	servenv.OnClose(func() { timer.Stop() })
+}
+
+// applyBackupRetention applies the backup retention policy of every keyspace
+// that has one.
+func applyBackupRetention(ctx context.Context) {
+	keyspaces, err := ts.GetKeyspaces(ctx)
+	if err != nil {
+		log.Error(fmt.Sprintf("Backup retention failed to list keyspaces, error: %v", err))
+		return
+	}
+
+	server := grpcvtctldserver.NewVtctldServer(env, ts)
+	for _, keyspace := range keyspaces {
+		ki, err := ts.GetKeyspace(ctx, keyspace)
+		if err != nil {
+			log.Error(fmt.Sprintf("Backup retention failed to get keyspace %v, error: %v", keyspace, err))
+			continue
+		}
+		if ki.BackupRetentionPolicy == nil {
+			continue
+		}
+
+		resp, err := server.ApplyBackupRetention(ctx, &vtctldatapb.ApplyBackupRetentionRequest{Keyspace: keyspace})
+		if err != nil {
+			log.Error(fmt.Sprintf("Backup retention failed for keyspace %v, error: %v", keyspace, err))
+			continue
+		}
+		log.Info(fmt.Sprintf("Backup retention removed %d backups of keyspace %v", len(resp.RemovedBackups), keyspace))
+	}
+}
@@ -36,6 +36,21 @@ import (
 )
 
 var (
+	// ApplyBackupRetention makes an ApplyBackupRetention gRPC call to a vtctld.
+	ApplyBackupRetention = &cobra.Command{
+		Use:   "ApplyBackupRetention [--shard <shard>] [--dry-run] [--json] <keyspace>",
+		Short: "Removes the backups of a keyspace that its backup retention policy does not keep.",
+		Long: `Removes the backups of a keyspace that its backup retention policy does not keep.
+
+The policy is set with SetKeyspaceBackupRetentionPolicy. The most recent full backup is always kept,
+as are incremental backups taken after the oldest kept full backup, along with every backup on their
+point in time recovery path. Backups whose MANIFEST cannot be read, such as backups in progress, are kept.
+
+With --dry-run, the backups that would be removed are listed, and nothing is removed.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandApplyBackupRetention,
+	}
 	// Backup makes a Backup gRPC call to a vtctld.
 	Backup = &cobra.Command{
 		Use:                   "Backup [--concurrency <concurrency>] [--allow-primary] [--incremental-from-pos=<pos>|<backup-name>|auto] [--upgrade-safe] [--backup-engine=enginename] <tablet_alias>",
@@ -57,8 +72,11 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 	}
 	// GetBackups makes a GetBackups gRPC call to a vtctld.
 	GetBackups = &cobra.Command{
-		Use:                   "GetBackups [--limit <limit>] [--json] <keyspace/shard>",
-		Short:                 "Lists backups for the given shard.",
+		Use:   "GetBackups [--limit <limit>] [--detailed] [--json] <keyspace/shard>",
+		Short: "Lists backups for the given shard.",
+		Long: `Lists backups for the given shard.
+
+With --detailed, each backup is listed with the time it was last verified by VerifyBackup, or as unverified.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetBackups,
@@ -79,8 +97,66 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRestoreFromBackup,
 	}
+	// VerifyBackup makes a VerifyBackup gRPC call to a vtctld.
+	VerifyBackup = &cobra.Command{
+		Use:   "VerifyBackup [--backup-name <backup name>] [--concurrency <concurrency>] <tablet_alias>",
+		Short: "Uses the given tablet to restore a backup into a scratch mysqld, checks the restored data, and records the verification in the backup.",
+		Long: `Uses the given tablet to restore a backup into a scratch mysqld, checks the restored data, and records the verification in the backup.
+
+The backup is the most recent full backup of the tablet's shard, unless --backup-name is given.
+The scratch mysqld runs next to the tablet's own mysqld, in a separate data directory which is removed afterwards,
+so the tablet keeps serving. The restored replication position is compared with the one in the backup MANIFEST,
+and CHECK TABLE and CHECKSUM TABLE are run on all tables. The verification time is shown by GetBackups --detailed.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandVerifyBackup,
+	}
 )
 
+var applyBackupRetentionOptions = struct {
+	Shard      string
+	DryRun     bool
+	OutputJSON bool
+}{}
+
+func commandApplyBackupRetention(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.ApplyBackupRetention(commandCtx, &vtctldatapb.ApplyBackupRetentionRequest{
+		Keyspace: keyspace,
+		Shard:    applyBackupRetentionOptions.Shard,
+		DryRun:   applyBackupRetentionOptions.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if applyBackupRetentionOptions.OutputJSON {
+		data, err := cli.MarshalJSON(resp)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+
+	action := "Removed"
+	if applyBackupRetentionOptions.DryRun {
+		action = "Would remove"
+	}
+	for _, b := range resp.RemovedBackups {
+		fmt.Printf("%s %s/%s/%s\n", action, b.Keyspace, b.Shard, b.Name)
+	}
+	for _, b := range resp.KeptBackups {
+		fmt.Printf("Kept %s/%s/%s\n", b.Keyspace, b.Shard, b.Name)
+	}
+
+	return nil
+}
+
 var backupOptions = struct {
 	AllowPrimary         bool
 	BackupEngine         string
@@ -207,6 +283,7 @@ func commandBackupShard(cmd *cobra.Command, args []string) error {
 
 var getBackupsOptions = struct {
 	Limit      uint32
+	Detailed   bool
 	OutputJSON bool
 }{}
 
@@ -222,6 +299,7 @@ func commandGetBackups(cmd *cobra.Command, args []string) error {
 		Keyspace: keyspace,
 		Shard:    shard,
 		Limit:    getBackupsOptions.Limit,
+		Detailed: getBackupsOptions.Detailed,
 	})
 	if err != nil {
 		return err
@@ -240,6 +318,13 @@ func commandGetBackups(cmd *cobra.Command, args []string) error {
 	names := make([]string, len(resp.Backups))
 	for i, b := range resp.Backups {
 		names[i] = b.Name
+		if getBackupsOptions.Detailed {
+			if verifiedTime := protoutil.TimeFromProto(b.VerifiedTime); !verifiedTime.IsZero() {
+				names[i] += " (verified " + mysqlctl.FormatRFC3339(verifiedTime) + ")"
+			} else {
+				names[i] += " (unverified)"
+			}
+		}
 	}
 
 	fmt.Printf("%s\n", strings.Join(names, "\n"))
@@ -328,7 +413,47 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 	}
 }
 
+var verifyBackupOptions = struct {
+	BackupName  string
+	Concurrency int32
+}{}
+
+func commandVerifyBackup(cmd *cobra.Command, args []string) error {
+	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	stream, err := client.VerifyBackup(commandCtx, &vtctldatapb.VerifyBackupRequest{
+		TabletAlias: alias,
+		BackupName:  verifyBackupOptions.BackupName,
+		Concurrency: verifyBackupOptions.Concurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		switch err {
+		case nil:
+			fmt.Printf("%s/%s (%s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), resp.Event)
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
 func init() {
+	ApplyBackupRetention.Flags().StringVar(&applyBackupRetentionOptions.Shard, "shard", "", "Only apply the policy to the backups of this shard. Omit to apply it to all shards of the keyspace.")
+	ApplyBackupRetention.Flags().BoolVar(&applyBackupRetentionOptions.DryRun, "dry-run", false, "Only list the backups that would be removed, do not remove them.")
+	ApplyBackupRetention.Flags().BoolVarP(&applyBackupRetentionOptions.OutputJSON, "json", "j", false, "Output the removed and kept backups in JSON format.")
+	Root.AddCommand(ApplyBackupRetention)
+
 	Backup.Flags().BoolVar(&backupOptions.AllowPrimary, "allow-primary", false, "Allow the primary of a shard to be used for the backup. WARNING: If using the builtin backup engine, this will shutdown mysqld on the primary and stop writes for the duration of the backup.")
 	Backup.Flags().Int32Var(&backupOptions.Concurrency, "concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously.")
 	Backup.Flags().StringVar(&backupOptions.IncrementalFromPos, "incremental-from-pos", "", "Position, or name of backup from which to create an incremental backup. Default: empty. If given, then this backup becomes an incremental backup from given position or given backup. If value is 'auto', this backup will be taken from the last successful backup position.")
@@ -348,6 +473,7 @@ func init() {
 	Root.AddCommand(BackupShard)
 
 	GetBackups.Flags().Uint32VarP(&getBackupsOptions.Limit, "limit", "l", 0, "Retrieve only the most recent N backups.")
+	GetBackups.Flags().BoolVar(&getBackupsOptions.Detailed, "detailed", false, "Include the last verification time of each backup.")
 	GetBackups.Flags().BoolVarP(&getBackupsOptions.OutputJSON, "json", "j", false, "Output backup info in JSON format rather than a list of backups.")
 	Root.AddCommand(GetBackups)
 
@@ -359,6 +485,10 @@ func init() {
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().BoolVar(&restoreFromBackupOptions.DryRun, "dry-run", false, "Only validate restore steps, do not actually restore data")
 	Root.AddCommand(RestoreFromBackup)
+
+	VerifyBackup.Flags().StringVar(&verifyBackupOptions.BackupName, "backup-name", "", "Name of the full backup to verify. Omit to verify the most recent full backup.")
+	VerifyBackup.Flags().Int32Var(&verifyBackupOptions.Concurrency, "concurrency", 0, "Number of files to restore concurrently. Defaults to the restore concurrency of the tablet.")
+	Root.AddCommand(VerifyBackup)
 }
 
 func addInitSQLFlags(cmd *cobra.Command) {
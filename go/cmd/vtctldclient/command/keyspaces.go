@@ -95,6 +95,21 @@ Otherwise, the keyspace must be empty (have no shards), or returns an error.`,
 		Args:                  cobra.ExactArgs(2),
 		RunE:                  commandRemoveKeyspaceCell,
 	}
+	// SetKeyspaceBackupRetentionPolicy makes a SetKeyspaceBackupRetentionPolicy gRPC call to a vtctld.
+	SetKeyspaceBackupRetentionPolicy = &cobra.Command{
+		Use:   "SetKeyspaceBackupRetentionPolicy [--keep-last <n>] [--keep-daily-days <days>] [--keep-weekly-weeks <weeks>] [--clear] <keyspace name>",
+		Short: "Sets the backup retention policy of the specified keyspace.",
+		Long: `Sets the backup retention policy of the specified keyspace.
+The policy is applied with ApplyBackupRetention, and periodically by vtctld when --backup-retention-interval is set.
+Full backups are kept if they are among the --keep-last most recent ones, the first one of a day within the last
+--keep-daily-days days, or the first one of a week within the last --keep-weekly-weeks weeks. Weeks start on Mondays, in UTC.
+
+To keep the 3 most recent backups, daily backups for a week and weekly backups for 4 weeks of the customer keyspace, you would use the following command:
+SetKeyspaceBackupRetentionPolicy --keep-last=3 --keep-daily-days=7 --keep-weekly-weeks=4 customer`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandSetKeyspaceBackupRetentionPolicy,
+	}
 	// SetKeyspaceDurabilityPolicy makes a SetKeyspaceDurabilityPolicy gRPC call to a vtcltd.
 	SetKeyspaceDurabilityPolicy = &cobra.Command{
 		Use:   "SetKeyspaceDurabilityPolicy [--durability-policy=policy_name] <keyspace name>",
@@ -313,6 +328,44 @@ func commandRemoveKeyspaceCell(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var setKeyspaceBackupRetentionPolicyOptions = struct {
+	KeepLast        int32
+	KeepDailyDays   int32
+	KeepWeeklyWeeks int32
+	Clear           bool
+}{}
+
+func commandSetKeyspaceBackupRetentionPolicy(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+
+	var retentionPolicy *topodatapb.BackupRetentionPolicy
+	if !setKeyspaceBackupRetentionPolicyOptions.Clear {
+		retentionPolicy = &topodatapb.BackupRetentionPolicy{
+			KeepLast:        setKeyspaceBackupRetentionPolicyOptions.KeepLast,
+			KeepDailyDays:   setKeyspaceBackupRetentionPolicyOptions.KeepDailyDays,
+			KeepWeeklyWeeks: setKeyspaceBackupRetentionPolicyOptions.KeepWeeklyWeeks,
+		}
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.SetKeyspaceBackupRetentionPolicy(commandCtx, &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+		Keyspace:              keyspace,
+		BackupRetentionPolicy: retentionPolicy,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 var setKeyspaceDurabilityPolicyOptions = struct {
 	DurabilityPolicy string
 }{}
@@ -380,6 +433,15 @@ func init() {
 	RemoveKeyspaceCell.Flags().BoolVarP(&removeKeyspaceCellOptions.Recursive, "recursive", "r", false, "Also delete all tablets in that cell beloning to the specified keyspace.")
 	Root.AddCommand(RemoveKeyspaceCell)
 
+	SetKeyspaceBackupRetentionPolicy.Flags().Int32Var(&setKeyspaceBackupRetentionPolicyOptions.KeepLast, "keep-last", 0, "Number of most recent full backups to keep.")
+	SetKeyspaceBackupRetentionPolicy.Flags().Int32Var(&setKeyspaceBackupRetentionPolicyOptions.KeepDailyDays, "keep-daily-days", 0, "Number of days, including today, for which the first full backup of each day is kept.")
+	SetKeyspaceBackupRetentionPolicy.Flags().Int32Var(&setKeyspaceBackupRetentionPolicyOptions.KeepWeeklyWeeks, "keep-weekly-weeks", 0, "Number of weeks, including the current one, for which the first full backup of each week is kept.")
+	SetKeyspaceBackupRetentionPolicy.Flags().BoolVar(&setKeyspaceBackupRetentionPolicyOptions.Clear, "clear", false, "Remove the backup retention policy of the keyspace.")
+	SetKeyspaceBackupRetentionPolicy.MarkFlagsMutuallyExclusive("clear", "keep-last")
+	SetKeyspaceBackupRetentionPolicy.MarkFlagsMutuallyExclusive("clear", "keep-daily-days")
+	SetKeyspaceBackupRetentionPolicy.MarkFlagsMutuallyExclusive("clear", "keep-weekly-weeks")
+	Root.AddCommand(SetKeyspaceBackupRetentionPolicy)
+
 	SetKeyspaceDurabilityPolicy.Flags().StringVar(&setKeyspaceDurabilityPolicyOptions.DurabilityPolicy, "durability-policy", policy.DurabilityNone, "Type of durability to enforce for this keyspace. Default is none. Other values include 'semi_sync' and others as dictated by registered plugins.")
 	Root.AddCommand(SetKeyspaceDurabilityPolicy)
 
@@ -17,13 +17,16 @@ limitations under the License.
 package command
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -49,6 +52,22 @@ var (
 		RunE:                  commandSetVtorcEmergencyReparent,
 	}
 
+	// SetVtorcRecoveryMaintenance silences VTOrc recoveries of a keyspace, shard or tablet until the maintenance expires.
+	SetVtorcRecoveryMaintenance = &cobra.Command{
+		Use:   "SetVtorcRecoveryMaintenance {--duration <duration> [--reason <reason>] | --clear} {<keyspace> [<shard>] | --tablet <alias>}",
+		Short: "Silences VTOrc recoveries of a keyspace, shard or tablet until the maintenance expires, or clears the maintenance.",
+		Long: `Silences VTOrc recoveries of a keyspace, shard or tablet until the maintenance expires, or clears the maintenance.
+
+Recoveries of the other keyspaces, shards and tablets are not affected.`,
+		Example: `SetVtorcRecoveryMaintenance --duration 2h --reason "resharding" commerce -80
+SetVtorcRecoveryMaintenance --clear commerce -80
+SetVtorcRecoveryMaintenance --duration 30m --reason "disk replacement" --tablet zone1-101`,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"setvtorcrecoverymaintenance"},
+		Args:                  cobra.RangeArgs(0, 2),
+		RunE:                  commandSetVtorcRecoveryMaintenance,
+	}
+
 	// WriteTopologyPath writes the contents of a local file to a path
 	// in the topology server.
 	WriteTopologyPath = &cobra.Command{
@@ -138,6 +157,66 @@ func commandSetVtorcEmergencyReparent(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var setVtorcRecoveryMaintenanceOptions = struct {
+	Duration time.Duration
+	Reason   string
+	Clear    bool
+	Tablet   string
+}{}
+
+func commandSetVtorcRecoveryMaintenance(cmd *cobra.Command, args []string) error {
+	req := &vtctldatapb.SetVtorcRecoveryMaintenanceRequest{
+		Keyspace: cmd.Flags().Arg(0),
+		Shard:    cmd.Flags().Arg(1),
+		Reason:   setVtorcRecoveryMaintenanceOptions.Reason,
+		Clear:    setVtorcRecoveryMaintenanceOptions.Clear,
+	}
+	target := topoproto.KeyspaceShardString(req.Keyspace, req.Shard)
+	if req.Shard == "" {
+		target = req.Keyspace
+	}
+	if setVtorcRecoveryMaintenanceOptions.Tablet != "" {
+		if len(args) > 0 {
+			return errors.New("--tablet cannot be used with a keyspace or shard")
+		}
+		alias, err := topoproto.ParseTabletAlias(setVtorcRecoveryMaintenanceOptions.Tablet)
+		if err != nil {
+			return err
+		}
+		req.TabletAlias = alias
+		target = setVtorcRecoveryMaintenanceOptions.Tablet
+	} else if len(args) == 0 {
+		return errors.New("a keyspace or --tablet is required")
+	}
+	if req.Clear == (setVtorcRecoveryMaintenanceOptions.Duration > 0) {
+		return errors.New("exactly one of --duration and --clear is required")
+	}
+	if !req.Clear {
+		req.Duration = protoutil.DurationToProto(setVtorcRecoveryMaintenanceOptions.Duration)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.SetVtorcRecoveryMaintenance(commandCtx, req)
+	if err != nil {
+		return fmt.Errorf("SetVtorcRecoveryMaintenance(%v) error: %w", target, err)
+	}
+
+	if req.Clear {
+		fmt.Printf("Successfully cleared the recovery maintenance of %v.\n", target)
+		return nil
+	}
+
+	data, err := cli.MarshalJSONPretty(resp.RecoveryMaintenance)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
 var writeTopologyPathOptions = struct {
 	// The cell to use for the copy. Defaults to the global cell.
 	cell string
@@ -177,6 +256,12 @@ func init() {
 	SetVtorcEmergencyReparent.Flags().BoolVarP(&setVtorcEmergencyReparentOptions.Disable, "disable", "d", false, "Disable the use of EmergencyReparentShard in recoveries.")
 	SetVtorcEmergencyReparent.Flags().BoolVarP(&setVtorcEmergencyReparentOptions.Enable, "enable", "e", false, "Enable the use of EmergencyReparentShard in recoveries.")
 
+	Root.AddCommand(SetVtorcRecoveryMaintenance)
+	SetVtorcRecoveryMaintenance.Flags().DurationVar(&setVtorcRecoveryMaintenanceOptions.Duration, "duration", 0, "How long to silence the recoveries for.")
+	SetVtorcRecoveryMaintenance.Flags().StringVar(&setVtorcRecoveryMaintenanceOptions.Reason, "reason", "", "Why the recoveries are silenced.")
+	SetVtorcRecoveryMaintenance.Flags().BoolVar(&setVtorcRecoveryMaintenanceOptions.Clear, "clear", false, "Clear the recovery maintenance instead.")
+	SetVtorcRecoveryMaintenance.Flags().StringVar(&setVtorcRecoveryMaintenanceOptions.Tablet, "tablet", "", "Alias of the tablet to silence the recoveries of, instead of a keyspace or shard.")
+
 	WriteTopologyPath.Flags().StringVar(&writeTopologyPathOptions.cell, "cell", topo.GlobalCell, "Topology server cell to copy the file to.")
 	Root.AddCommand(WriteTopologyPath)
 }
@@ -99,6 +99,14 @@ var (
 		Keyspace string
 	}{}
 
+	verifyOptions = struct {
+		Keyspace      string
+		UUID          string
+		Repair        bool
+		BatchSize     int64
+		MaxSampleRows int64
+	}{}
+
 	parseAndValidateCreate = func(cmd *cobra.Command, args []string) error {
 		if createOptions.ParamsFile != "" {
 			if createOptions.TableOwner != "" {
@@ -353,6 +361,19 @@ var (
 		Args:                  cobra.NoArgs,
 		RunE:                  commandShow,
 	}
+
+	// verify makes a LookupVindexVerify call to a vtctld.
+	verify = &cobra.Command{
+		Use:   "verify",
+		Short: "Start a VDiff of the lookup table against its owner table, or report the missing, orphaned and mismatched rows that it found and optionally repair them.",
+		Example: `vtctldclient --server localhost:15999 LookupVindex --name corder_lookup_vdx --table-keyspace customer verify --keyspace customer
+vtctldclient --server localhost:15999 LookupVindex --name corder_lookup_vdx --table-keyspace customer verify --keyspace customer --uuid a37d4cc2-5c62-4de1-b2f7-3d2c6ca6dfa7 --repair`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Verify"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandVerify,
+	}
 )
 
 func commandCancel(cmd *cobra.Command, args []string) error {
@@ -496,6 +517,37 @@ func commandShow(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func commandVerify(cmd *cobra.Command, args []string) error {
+	if verifyOptions.Keyspace == "" {
+		verifyOptions.Keyspace = baseOptions.TableKeyspace
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().LookupVindexVerify(common.GetCommandCtx(), &vtctldatapb.LookupVindexVerifyRequest{
+		Keyspace: verifyOptions.Keyspace,
+		// The name of the lookup vindex.
+		Name: baseOptions.Name,
+		// Where the lookup table was created.
+		TableKeyspace: baseOptions.TableKeyspace,
+		Uuid:          verifyOptions.UUID,
+		Repair:        verifyOptions.Repair,
+		BatchSize:     verifyOptions.BatchSize,
+		MaxSampleRows: verifyOptions.MaxSampleRows,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSONPretty(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
 func registerCommands(root *cobra.Command) {
 	base.PersistentFlags().StringVar(&baseOptions.Name, "name", "", "The name of the Lookup Vindex to create. This will also be the name of the VReplication workflow created to backfill the Lookup Vindex. This will be used only for the workflow name if params-file is used.")
 	base.MarkPersistentFlagRequired("name")
@@ -538,6 +590,13 @@ func registerCommands(root *cobra.Command) {
 	complete.Flags().StringVar(&completeOptions.Keyspace, "keyspace", "", "The keyspace containing the Lookup Vindex. If no value is specified then the table-keyspace will be used.")
 	base.AddCommand(complete)
 
+	verify.Flags().StringVar(&verifyOptions.Keyspace, "keyspace", "", "The keyspace containing the Lookup Vindex. If no value is specified then the table-keyspace will be used.")
+	verify.Flags().StringVar(&verifyOptions.UUID, "uuid", "", "The UUID of the VDiff started by an earlier verify, whose progress and differences are reported. If no value is specified then a new VDiff is started.")
+	verify.Flags().BoolVar(&verifyOptions.Repair, "repair", false, "Repair the lookup table by inserting the missing rows, deleting the orphaned rows and updating the mismatched rows found by the completed VDiff.")
+	verify.Flags().Int64Var(&verifyOptions.BatchSize, "batch-size", 1000, "The number of rows to repair at a time.")
+	verify.Flags().Int64Var(&verifyOptions.MaxSampleRows, "max-sample-rows", 1000, "The maximum number of rows of each kind of difference that the VDiff records on each shard, which are the rows that can be repaired.")
+	base.AddCommand(verify)
+
 	// The cancel command deletes the VReplication workflow used
 	// to backfill the lookup vindex. It ends up making a
 	// WorkflowDelete VtctldServer call.
@@ -116,6 +116,7 @@ const (
 	EROperandColumns                = ErrorCode(1241)
 	ERSubqueryNo1Row                = ErrorCode(1242)
 	ERUnknownStmtHandler            = ErrorCode(1243)
+	ERCutValueGroupConcat           = ErrorCode(1260)
 	ERWarnDataOutOfRange            = ErrorCode(1264)
 	ERNonUpdateableTable            = ErrorCode(1288)
 	ERFeatureDisabled               = ErrorCode(1289)
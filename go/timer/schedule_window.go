@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleWindow is a daily time-of-day window during which some work is allowed, such as copying rows in a
+// VReplication workflow or cutting over an Online DDL migration. It is written as "HH:MM-HH:MM", optionally
+// followed by a space and an IANA time zone name, e.g. "22:00-06:00 America/New_York". The window is in UTC
+// when no time zone is given, and it wraps around midnight when the end is before the start.
+type ScheduleWindow struct {
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+	spec     string
+}
+
+// ParseScheduleWindow parses a schedule window. An empty string returns a nil window, which is always open.
+func ParseScheduleWindow(spec string) (*ScheduleWindow, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	window := &ScheduleWindow{location: time.UTC, spec: spec}
+	times, zone, hasZone := strings.Cut(spec, " ")
+	if hasZone {
+		location, err := time.LoadLocation(strings.TrimSpace(zone))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule window %q: %v", spec, err)
+		}
+		window.location = location
+	}
+	start, end, ok := strings.Cut(times, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid schedule window %q: expected HH:MM-HH:MM", spec)
+	}
+	var err error
+	if window.start, err = parseTimeOfDay(start); err != nil {
+		return nil, fmt.Errorf("invalid schedule window %q: %v", spec, err)
+	}
+	if window.end, err = parseTimeOfDay(end); err != nil {
+		return nil, fmt.Errorf("invalid schedule window %q: %v", spec, err)
+	}
+	if window.start == window.end {
+		return nil, fmt.Errorf("invalid schedule window %q: the start and end times must differ", spec)
+	}
+	return window, nil
+}
+
+// parseTimeOfDay parses a HH:MM time of day and returns it as the duration since midnight.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// IsOpen returns true if the given time is within the window.
+func (w *ScheduleWindow) IsOpen(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	tod := w.timeOfDay(t)
+	if w.start < w.end {
+		return tod >= w.start && tod < w.end
+	}
+	return tod >= w.start || tod < w.end
+}
+
+// UntilOpen returns how long it is from the given time until the window opens, or zero if it is open.
+func (w *ScheduleWindow) UntilOpen(t time.Time) time.Duration {
+	if w.IsOpen(t) {
+		return 0
+	}
+	return untilTimeOfDay(w.timeOfDay(t), w.start)
+}
+
+// UntilClose returns how long it is from the given time until the window closes, or zero if it is closed.
+// It must not be called on a nil window, which never closes.
+func (w *ScheduleWindow) UntilClose(t time.Time) time.Duration {
+	if !w.IsOpen(t) {
+		return 0
+	}
+	return untilTimeOfDay(w.timeOfDay(t), w.end)
+}
+
+// OpenDuration returns how long the window is open between the given times. A nil window is always open.
+func (w *ScheduleWindow) OpenDuration(from, to time.Time) time.Duration {
+	if w == nil {
+		return max(to.Sub(from), 0)
+	}
+	var open time.Duration
+	for t := from; t.Before(to); {
+		if w.IsOpen(t) {
+			d := min(w.UntilClose(t), to.Sub(t))
+			open += d
+			t = t.Add(d)
+		} else {
+			t = t.Add(w.UntilOpen(t))
+		}
+	}
+	return open
+}
+
+// String returns the window as it was specified.
+func (w *ScheduleWindow) String() string {
+	if w == nil {
+		return ""
+	}
+	return w.spec
+}
+
+// timeOfDay returns the duration since midnight of the given time, in the location of the window.
+func (w *ScheduleWindow) timeOfDay(t time.Time) time.Duration {
+	t = t.In(w.location)
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
+
+// untilTimeOfDay returns how long it is from one time of day until the next occurrence of another one.
+func untilTimeOfDay(from, to time.Duration) time.Duration {
+	d := to - from
+	if d <= 0 {
+		d += 24 * time.Hour
+	}
+	return d
+}
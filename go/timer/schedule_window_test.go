@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScheduleWindow(t *testing.T) {
+	for _, spec := range []string{"22:00", "22:00-", "22:00-24:00", "10:00-10:00", "aa:00-06:00", "22:00-06:00 Mars/Olympus_Mons"} {
+		_, err := ParseScheduleWindow(spec)
+		assert.ErrorContains(t, err, "invalid schedule window", spec)
+	}
+
+	window, err := ParseScheduleWindow("")
+	require.NoError(t, err)
+	assert.Nil(t, window)
+	assert.True(t, window.IsOpen(time.Now()))
+	assert.Zero(t, window.UntilOpen(time.Now()))
+	assert.Empty(t, window.String())
+}
+
+func TestScheduleWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 3, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec       string
+		at         time.Time
+		open       bool
+		untilOpen  time.Duration
+		untilClose time.Duration
+	}{
+		{spec: "09:00-17:00", at: at(8, 30), untilOpen: 30 * time.Minute},
+		{spec: "09:00-17:00", at: at(9, 0), open: true, untilClose: 8 * time.Hour},
+		{spec: "09:00-17:00", at: at(17, 0), untilOpen: 16 * time.Hour},
+		{spec: "22:00-06:00", at: at(23, 0), open: true, untilClose: 7 * time.Hour},
+		{spec: "22:00-06:00", at: at(5, 45), open: true, untilClose: 15 * time.Minute},
+		{spec: "22:00-06:00", at: at(12, 0), untilOpen: 10 * time.Hour},
+		// 22:00-06:00 in New York is 03:00-11:00 in UTC in March, before daylight saving time starts.
+		{spec: "22:00-06:00 America/New_York", at: at(2, 0), untilOpen: time.Hour},
+		{spec: "22:00-06:00 America/New_York", at: at(10, 0), open: true, untilClose: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec+" at "+tt.at.Format("15:04"), func(t *testing.T) {
+			window, err := ParseScheduleWindow(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.spec, window.String())
+			assert.Equal(t, tt.open, window.IsOpen(tt.at))
+			assert.Equal(t, tt.untilOpen, window.UntilOpen(tt.at))
+			assert.Equal(t, tt.untilClose, window.UntilClose(tt.at))
+		})
+	}
+}
+
+func TestScheduleWindowOpenDuration(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec     string
+		from, to time.Time
+		open     time.Duration
+	}{
+		{spec: "", from: at(1, 8, 0), to: at(1, 9, 30), open: 90 * time.Minute},
+		{spec: "09:00-17:00", from: at(1, 8, 0), to: at(1, 8, 30)},
+		{spec: "09:00-17:00", from: at(1, 8, 0), to: at(1, 9, 30), open: 30 * time.Minute},
+		{spec: "09:00-17:00", from: at(1, 10, 0), to: at(1, 11, 0), open: time.Hour},
+		{spec: "09:00-17:00", from: at(1, 16, 0), to: at(2, 10, 0), open: 2 * time.Hour},
+		{spec: "09:00-17:00", from: at(1, 8, 0), to: at(4, 8, 0), open: 24 * time.Hour},
+		{spec: "22:00-06:00", from: at(1, 12, 0), to: at(2, 12, 0), open: 8 * time.Hour},
+		{spec: "22:00-06:00", from: at(1, 23, 0), to: at(2, 1, 0), open: 2 * time.Hour},
+		{spec: "09:00-17:00", from: at(1, 10, 0), to: at(1, 9, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec+" from "+tt.from.Format("02 15:04")+" to "+tt.to.Format("02 15:04"), func(t *testing.T) {
+			window, err := ParseScheduleWindow(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.open, window.OpenDuration(tt.from, tt.to))
+		})
+	}
+}
@@ -326,6 +326,23 @@ func (dbcfgs *DBConfigs) Clone() *DBConfigs {
 	return &result
 }
 
+// CloneWithSocket returns a clone of the DBConfigs with the same users, whose
+// connection parameters are initialized to go through the given socket file.
+// It is used to connect to a throwaway mysqld next to the one the DBConfigs
+// was initialized for.
+func (dbcfgs *DBConfigs) CloneWithSocket(socketFile string, collationEnv *collations.Environment) *DBConfigs {
+	result := dbcfgs.Clone()
+	result.Socket = ""
+	result.Host = ""
+	result.Port = 0
+	for _, userKey := range All {
+		_, cp := result.getParams(userKey)
+		*cp = mysql.ConnParams{}
+	}
+	result.InitWithSocket(socketFile, collationEnv)
+	return result
+}
+
 // InitWithSocket will initialize all the necessary connection parameters.
 // Precedence is as follows: if UserConfig settings are set,
 // they supersede all other settings.
@@ -440,6 +440,18 @@ func (bs *AZBlobBackupStorage) RemoveBackup(ctx context.Context, dir, name strin
 	return err
 }
 
+// WriteFile implements backupstorage.FileWriter.
+func (bs *AZBlobBackupStorage) WriteFile(ctx context.Context, dir, name, filename string, data []byte) error {
+	containerURL, err := bs.containerURL()
+	if err != nil {
+		return err
+	}
+
+	blockBlobURL := containerURL.NewBlockBlobURL(objName(dir, name, filename))
+	_, err = azblob.UploadBufferToBlockBlob(ctx, data, blockBlobURL, azblob.UploadToBlockBlobOptions{})
+	return err
+}
+
 // Close implements BackupStorage.
 func (bs *AZBlobBackupStorage) Close() error {
 	// This function is a No-op
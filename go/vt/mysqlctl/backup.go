@@ -55,7 +55,7 @@ const (
 	backupData                  = "Data"
 
 	// backupManifestFileName is the MANIFEST file name within a backup.
-	backupManifestFileName = "MANIFEST"
+	backupManifestFileName = backupstorage.ManifestFileName
 	// RestoreState is the name of the sentinel file used to detect whether a previous restore
 	// terminated abnormally
 	RestoreState = "restore_in_progress"
@@ -434,6 +434,9 @@ func Restore(ctx context.Context, params RestoreParams) (*BackupManifest, error)
 		// This condition should not happen; but we validate for sanity
 		return nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "empty restore path")
 	}
+	if err := restorePath.checkEncryption(); err != nil {
+		return nil, err
+	}
 	bh := restorePath.FullBackupHandle()
 	re, err := GetRestoreEngine(ctx, bh)
 	if err != nil {
@@ -156,6 +156,9 @@ type RestoreParams struct {
 	MysqlShutdownTimeout time.Duration
 	// AllowedBackupEngines if present will filter out any backups taken with engines not included in the list
 	AllowedBackupEngines []string
+	// BackupName, if set, is the name of the full backup to restore, instead of the most recent one.
+	// It cannot be used for point in time recoveries.
+	BackupName string
 }
 
 func (p *RestoreParams) Copy() RestoreParams {
@@ -175,6 +178,7 @@ func (p *RestoreParams) Copy() RestoreParams {
 		DryRun:               p.DryRun,
 		Stats:                p.Stats,
 		MysqlShutdownTimeout: p.MysqlShutdownTimeout,
+		BackupName:           p.BackupName,
 	}
 }
 
@@ -346,6 +350,10 @@ type BackupManifest struct {
 
 	// IncrementalDetails is nil for non-incremental backups
 	IncrementalDetails *IncrementalBackupDetails
+
+	// Encryption describes how the backup files were encrypted. It is nil for
+	// backups that are not encrypted.
+	Encryption *backupstorage.EncryptionInfo `json:",omitempty"`
 }
 
 func (m *BackupManifest) HashKey() string {
@@ -421,6 +429,16 @@ func (p *RestorePath) IncrementalBackupHandles() []backupstorage.BackupHandle {
 	return p.manifestHandleMap.Handles(p.manifests[1:])
 }
 
+// checkEncryption verifies that the files of the encrypted backups in the path can be decrypted.
+func (p *RestorePath) checkEncryption() error {
+	for _, m := range p.manifests {
+		if m.Encryption != nil && !backupstorage.IsEncrypted(p.manifestHandleMap.Handle(m)) {
+			return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "backup %v is encrypted, --backup-storage-encryption-key-provider must be set to restore it", m.BackupName)
+		}
+	}
+	return nil
+}
+
 func (p *RestorePath) String() string {
 	var sb strings.Builder
 	sb.WriteString("RestorePath: [")
@@ -561,6 +579,9 @@ func FindBackupToRestore(ctx context.Context, params RestoreParams, bhs []backup
 					continue
 				}
 				bh := manifestHandleMap.Handle(bm)
+				if params.BackupName != "" && bh.Name() != params.BackupName {
+					continue
+				}
 
 				// check if the backup can be used with this MySQL version.
 				if bm.MySQLVersion != "" {
@@ -591,6 +612,9 @@ func FindBackupToRestore(ctx context.Context, params RestoreParams, bhs []backup
 			return -1
 		}()
 		if fullBackupIndex < 0 {
+			if params.BackupName != "" {
+				return nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "no valid full backup %q found in %v", params.BackupName, backupDir)
+			}
 			if checkBackupTime {
 				params.Logger.Errorf("No valid backup found before time %v", params.StartTime.Format(BackupTimestampFormat))
 			}
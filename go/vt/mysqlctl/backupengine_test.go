@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 )
 
 func TestValidateMySQLVersionUpgradeCompatible(t *testing.T) {
@@ -212,3 +214,20 @@ func TestValidateMySQLVersionUpgradeCompatible(t *testing.T) {
 		})
 	}
 }
+
+func TestRestorePathCheckEncryption(t *testing.T) {
+	plain := &BackupManifest{BackupName: "plain", BackupTime: "1"}
+	encrypted := &BackupManifest{BackupName: "encrypted", BackupTime: "2", Encryption: &backupstorage.EncryptionInfo{}}
+
+	path := &RestorePath{manifestHandleMap: NewManifestHandleMap()}
+	for _, m := range []*BackupManifest{plain, encrypted} {
+		path.Add(m)
+		path.manifestHandleMap.Map(m, &FakeBackupHandle{NameV: m.BackupName})
+	}
+	assert.ErrorContains(t, path.checkEncryption(), "backup encrypted is encrypted, --backup-storage-encryption-key-provider must be set to restore it")
+
+	path = &RestorePath{manifestHandleMap: NewManifestHandleMap()}
+	path.Add(plain)
+	path.manifestHandleMap.Map(plain, &FakeBackupHandle{NameV: plain.BackupName})
+	assert.NoError(t, path.checkEncryption())
+}
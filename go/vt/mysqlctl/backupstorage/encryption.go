@@ -0,0 +1,586 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/utils"
+)
+
+const (
+	// ManifestFileName is the name of the file that describes a backup. It is
+	// never encrypted, so that backups can be listed and inspected without
+	// access to the encryption keys.
+	ManifestFileName = "MANIFEST"
+
+	// VerificationFileName is the name of the file that records the last
+	// successful verification of a backup. Like the MANIFEST, it is never
+	// encrypted.
+	VerificationFileName = "VERIFICATION"
+
+	// EncryptionAlgorithm is the algorithm the backup files are encrypted with.
+	EncryptionAlgorithm = "AES-256-GCM"
+
+	// encryptionMagic starts every encrypted file.
+	encryptionMagic    = "VTBE"
+	encryptionKeySize  = 32
+	encryptionSaltSize = 32
+	encryptionVersion  = 1
+	// encryptionSegmentSize is the size of the plaintext segments that get
+	// encrypted and authenticated independently.
+	encryptionSegmentSize = 64 * 1024
+	encryptionHeaderSize  = len(encryptionMagic) + 1 + encryptionSaltSize
+	encryptionFileKeyInfo = "vitess backup file encryption"
+)
+
+var (
+	// encryptionKeyProvider is the name of the KeyProvider used to encrypt new
+	// backups. New backups are not encrypted when empty.
+	encryptionKeyProvider string
+
+	// encryptionKeyFile is the key file used by the file KeyProvider.
+	encryptionKeyFile string
+)
+
+func registerEncryptionFlags(fs *pflag.FlagSet) {
+	utils.SetFlagStringVar(fs, &encryptionKeyProvider, "backup-storage-encryption-key-provider", "", "Key provider used to encrypt the files of new backups with a data key per backup, before they are written to the backup storage. New backups are not encrypted when empty. Supported values: 'file'.")
+	utils.SetFlagStringVar(fs, &encryptionKeyFile, "backup-storage-encryption-key-file", "", "Path to the file holding the 32 bytes key, raw or hex encoded, that the 'file' backup encryption key provider wraps the backup data keys with.")
+}
+
+func init() {
+	servenv.OnParseFor("vtbackup", registerEncryptionFlags)
+	servenv.OnParseFor("vttablet", registerEncryptionFlags)
+}
+
+// EncryptionInfo describes how the files of a backup are encrypted. It is
+// recorded in the MANIFEST of encrypted backups.
+type EncryptionInfo struct {
+	// Algorithm is the encryption algorithm, EncryptionAlgorithm.
+	Algorithm string
+
+	// KeyProvider is the name of the KeyProvider that wrapped the data key.
+	KeyProvider string
+
+	// KeyRef identifies the key the data key was wrapped with.
+	KeyRef string
+
+	// WrappedKey is the data key of the backup, wrapped by the KeyProvider.
+	WrappedKey []byte
+}
+
+// GetEncryptionInfo returns how the files of a backup started with bh are
+// encrypted, or nil if they are not encrypted. Backup engines record it in
+// the MANIFEST, so that the backup can be decrypted on restore.
+func GetEncryptionInfo(bh BackupHandle) *EncryptionInfo {
+	ebh, ok := bh.(*encryptedBackupHandle)
+	if !ok {
+		return nil
+	}
+	return ebh.info
+}
+
+// IsEncrypted returns true if files read through bh are decrypted, i.e. if bh
+// was obtained from a BackupStorage configured with a backup encryption key provider.
+func IsEncrypted(bh BackupHandle) bool {
+	_, ok := bh.(*encryptedBackupHandle)
+	return ok
+}
+
+// NewEncryptedBackupStorage returns a BackupStorage that encrypts the files
+// of new backups, except for the MANIFEST, with a data key per backup wrapped
+// by the named KeyProvider. Files of existing backups are decrypted with the
+// KeyProvider recorded in their MANIFEST, or read as is for backups that are
+// not encrypted.
+func NewEncryptedBackupStorage(bs BackupStorage, keyProvider string) (BackupStorage, error) {
+	if _, ok := KeyProviderMap[keyProvider]; !ok {
+		return nil, fmt.Errorf("unknown backup encryption key provider %q", keyProvider)
+	}
+	return &encryptedBackupStorage{bs: bs, keyProvider: keyProvider}, nil
+}
+
+// encryptedBackupStorage implements BackupStorage, wrapping another BackupStorage.
+type encryptedBackupStorage struct {
+	bs          BackupStorage
+	keyProvider string
+}
+
+// ListBackups is part of the BackupStorage interface.
+func (ebs *encryptedBackupStorage) ListBackups(ctx context.Context, dir string) ([]BackupHandle, error) {
+	bhs, err := ebs.bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	for i, bh := range bhs {
+		bhs[i] = &encryptedBackupHandle{BackupHandle: bh}
+	}
+	return bhs, nil
+}
+
+// StartBackup is part of the BackupStorage interface.
+func (ebs *encryptedBackupStorage) StartBackup(ctx context.Context, dir, name string) (BackupHandle, error) {
+	dataKey := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	wrappedKey, keyRef, err := KeyProviderMap[ebs.keyProvider].WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot wrap backup data key: %w", err)
+	}
+
+	bh, err := ebs.bs.StartBackup(ctx, dir, name)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedBackupHandle{
+		BackupHandle: bh,
+		info: &EncryptionInfo{
+			Algorithm:   EncryptionAlgorithm,
+			KeyProvider: ebs.keyProvider,
+			KeyRef:      keyRef,
+			WrappedKey:  wrappedKey,
+		},
+		dataKey: dataKey,
+	}, nil
+}
+
+// RemoveBackup is part of the BackupStorage interface.
+func (ebs *encryptedBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
+	return ebs.bs.RemoveBackup(ctx, dir, name)
+}
+
+// Close is part of the BackupStorage interface.
+func (ebs *encryptedBackupStorage) Close() error {
+	return ebs.bs.Close()
+}
+
+// WithParams is part of the BackupStorage interface.
+func (ebs *encryptedBackupStorage) WithParams(params Params) BackupStorage {
+	return &encryptedBackupStorage{bs: ebs.bs.WithParams(params), keyProvider: ebs.keyProvider}
+}
+
+// WriteFile is part of the FileWriter interface. Only the files that are never
+// encrypted can be written, and they are passed to the wrapped BackupStorage.
+func (ebs *encryptedBackupStorage) WriteFile(ctx context.Context, dir, name, filename string, data []byte) error {
+	if !isPlaintextFile(filename) {
+		return fmt.Errorf("cannot write %v into a completed backup", filename)
+	}
+	fw, ok := ebs.bs.(FileWriter)
+	if !ok {
+		return fmt.Errorf("backup storage %T cannot write files into completed backups", ebs.bs)
+	}
+	return fw.WriteFile(ctx, dir, name, filename, data)
+}
+
+// isPlaintextFile returns true for the backup files that are never encrypted.
+func isPlaintextFile(filename string) bool {
+	return filename == ManifestFileName || filename == VerificationFileName
+}
+
+// encryptedBackupHandle implements BackupHandle, encrypting and decrypting the
+// files of the wrapped BackupHandle.
+type encryptedBackupHandle struct {
+	BackupHandle
+
+	// mu protects info and dataKey. They are set by StartBackup for new
+	// backups, and loaded from the MANIFEST on the first read otherwise.
+	mu      sync.Mutex
+	loaded  bool
+	info    *EncryptionInfo
+	dataKey []byte
+}
+
+// AddFile is part of the BackupHandle interface.
+func (ebh *encryptedBackupHandle) AddFile(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	if isPlaintextFile(filename) {
+		return ebh.BackupHandle.AddFile(ctx, filename, filesize)
+	}
+	if ebh.dataKey == nil {
+		return nil, errors.New("AddFile cannot be called on read-only backup")
+	}
+	wc, err := ebh.BackupHandle.AddFile(ctx, filename, encryptedFileSize(filesize))
+	if err != nil {
+		return nil, err
+	}
+	ew, err := newEncryptingWriter(wc, ebh.dataKey, filename)
+	if err != nil {
+		wc.Close()
+		return nil, err
+	}
+	return ew, nil
+}
+
+// ReadFile is part of the BackupHandle interface.
+func (ebh *encryptedBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	return ebh.ReadFileAt(ctx, filename, 0)
+}
+
+// ReadFileAt is part of the RangeReader interface. When the wrapped BackupHandle
+// is not a RangeReader, the file is read from the start and decrypted up to offset.
+func (ebh *encryptedBackupHandle) ReadFileAt(ctx context.Context, filename string, offset int64) (io.ReadCloser, error) {
+	if isPlaintextFile(filename) {
+		return ebh.readInnerAt(ctx, filename, offset)
+	}
+	dataKey, err := ebh.loadDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if dataKey == nil {
+		// The backup is not encrypted.
+		return ebh.readInnerAt(ctx, filename, offset)
+	}
+
+	segment := offset / encryptionSegmentSize
+	skip := int(offset % encryptionSegmentSize)
+	if _, ok := ebh.BackupHandle.(RangeReader); !ok || segment == 0 {
+		rc, err := ebh.BackupHandle.ReadFile(ctx, filename)
+		if err != nil {
+			return nil, err
+		}
+		dr, err := newDecryptingReader(rc, dataKey, filename, nil)
+		if err != nil {
+			rc.Close()
+			return nil, err
+		}
+		if _, err := io.CopyN(io.Discard, dr, offset); err != nil {
+			dr.Close()
+			return nil, err
+		}
+		return dr, nil
+	}
+
+	// Read the header for the salt, then start reading at the segment holding offset.
+	header, err := ebh.readInnerAt(ctx, filename, 0)
+	if err != nil {
+		return nil, err
+	}
+	salt, err := readEncryptionHeader(header)
+	header.Close()
+	if err != nil {
+		return nil, fmt.Errorf("backup file %s: %w", filename, err)
+	}
+	rc, err := ebh.readInnerAt(ctx, filename, int64(encryptionHeaderSize)+segment*encryptedSegmentSize)
+	if err != nil {
+		return nil, err
+	}
+	dr, err := newDecryptingReader(rc, dataKey, filename, salt)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	dr.counter = uint64(segment)
+	dr.skip = skip
+	return dr, nil
+}
+
+// readInnerAt reads a file of the wrapped BackupHandle, starting at offset.
+func (ebh *encryptedBackupHandle) readInnerAt(ctx context.Context, filename string, offset int64) (io.ReadCloser, error) {
+	if rr, ok := ebh.BackupHandle.(RangeReader); ok {
+		return rr.ReadFileAt(ctx, filename, offset)
+	}
+	rc, err := ebh.BackupHandle.ReadFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.CopyN(io.Discard, rc, offset); err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return rc, nil
+}
+
+// loadDataKey returns the data key of the backup, unwrapping it with the key
+// provider recorded in the MANIFEST. It returns a nil key for backups that
+// are not encrypted.
+func (ebh *encryptedBackupHandle) loadDataKey(ctx context.Context) ([]byte, error) {
+	ebh.mu.Lock()
+	defer ebh.mu.Unlock()
+	if ebh.dataKey != nil || ebh.loaded {
+		return ebh.dataKey, nil
+	}
+
+	rc, err := ebh.BackupHandle.ReadFile(ctx, ManifestFileName)
+	if err != nil {
+		return nil, fmt.Errorf("can't read MANIFEST for the backup encryption key: %w", err)
+	}
+	defer rc.Close()
+	var manifest struct {
+		Encryption *EncryptionInfo
+	}
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("can't decode MANIFEST for the backup encryption key: %w", err)
+	}
+
+	info := manifest.Encryption
+	if info != nil {
+		if info.Algorithm != EncryptionAlgorithm {
+			return nil, fmt.Errorf("unsupported backup encryption algorithm %q", info.Algorithm)
+		}
+		kp, ok := KeyProviderMap[info.KeyProvider]
+		if !ok {
+			return nil, fmt.Errorf("unknown backup encryption key provider %q", info.KeyProvider)
+		}
+		ebh.dataKey, err = kp.UnwrapKey(ctx, info.WrappedKey, info.KeyRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ebh.info = info
+	ebh.loaded = true
+	return ebh.dataKey, nil
+}
+
+var (
+	_ BackupStorage = (*encryptedBackupStorage)(nil)
+	_ BackupHandle  = (*encryptedBackupHandle)(nil)
+	_ RangeReader   = (*encryptedBackupHandle)(nil)
+)
+
+// Encrypted files start with a header made of encryptionMagic, the format
+// version and a random salt, which is used to derive the key of the file from
+// the data key of the backup. It's followed by the plaintext in segments of
+// encryptionSegmentSize bytes, each encrypted with AES-GCM and authenticated
+// along with the file name. The nonce of a segment is its index, with a flag
+// on the last segment, so that segments can't be reordered or dropped without
+// failing authentication.
+
+// encryptedSegmentSize is the size of an encrypted segment, except for the last one.
+const encryptedSegmentSize = encryptionSegmentSize + 16
+
+// encryptedFileSize returns the size of a file of size bytes once encrypted.
+func encryptedFileSize(size int64) int64 {
+	if size < 0 {
+		return size
+	}
+	// The last segment is written on close, even when empty, so there is always at least one.
+	segments := max((size+encryptionSegmentSize-1)/encryptionSegmentSize, 1)
+	return int64(encryptionHeaderSize) + size + segments*(encryptedSegmentSize-encryptionSegmentSize)
+}
+
+// newFileAEAD returns the cipher for a file, derived from the data key and the salt of the file.
+func newFileAEAD(dataKey, salt []byte) (cipher.AEAD, error) {
+	fileKey, err := hkdf.Key(sha256.New, dataKey, salt, encryptionFileKeyInfo, encryptionKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// segmentNonce returns the nonce of a segment.
+func segmentNonce(nonce []byte, counter uint64, last bool) []byte {
+	binary.BigEndian.PutUint64(nonce, counter)
+	nonce[8], nonce[9], nonce[10], nonce[11] = 0, 0, 0, 0
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+func readEncryptionHeader(r io.Reader) (salt []byte, err error) {
+	header := make([]byte, encryptionHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("cannot read encryption header: %w", err)
+	}
+	if !bytes.HasPrefix(header, []byte(encryptionMagic)) {
+		return nil, errors.New("file is not encrypted")
+	}
+	if version := header[len(encryptionMagic)]; version != encryptionVersion {
+		return nil, fmt.Errorf("unsupported encryption format version %d", version)
+	}
+	return header[len(encryptionMagic)+1:], nil
+}
+
+// encryptingWriter encrypts the data written to it, and writes it to the wrapped WriteCloser.
+type encryptingWriter struct {
+	w       io.WriteCloser
+	aead    cipher.AEAD
+	aad     []byte
+	nonce   []byte
+	counter uint64
+	// buf holds the plaintext of the current segment. A full segment is only
+	// encrypted when more data is written, since the last segment is flagged.
+	buf    []byte
+	sealed []byte
+}
+
+func newEncryptingWriter(w io.WriteCloser, dataKey []byte, filename string) (*encryptingWriter, error) {
+	header := make([]byte, 0, encryptionHeaderSize)
+	header = append(header, encryptionMagic...)
+	header = append(header, encryptionVersion)
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	header = append(header, salt...)
+
+	aead, err := newFileAEAD(dataKey, salt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptingWriter{
+		w:      w,
+		aead:   aead,
+		aad:    []byte(filename),
+		nonce:  make([]byte, aead.NonceSize()),
+		buf:    make([]byte, 0, encryptionSegmentSize),
+		sealed: make([]byte, 0, encryptedSegmentSize),
+	}, nil
+}
+
+func (ew *encryptingWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if len(ew.buf) == encryptionSegmentSize {
+			if err := ew.writeSegment(false); err != nil {
+				return n, err
+			}
+		}
+		nn := copy(ew.buf[len(ew.buf):encryptionSegmentSize], p)
+		ew.buf = ew.buf[:len(ew.buf)+nn]
+		p = p[nn:]
+		n += nn
+	}
+	return n, nil
+}
+
+func (ew *encryptingWriter) writeSegment(last bool) error {
+	ew.sealed = ew.aead.Seal(ew.sealed[:0], segmentNonce(ew.nonce, ew.counter, last), ew.buf, ew.aad)
+	ew.counter++
+	ew.buf = ew.buf[:0]
+	_, err := ew.w.Write(ew.sealed)
+	return err
+}
+
+// Close encrypts the last segment, and closes the wrapped WriteCloser.
+func (ew *encryptingWriter) Close() error {
+	if err := ew.writeSegment(true); err != nil {
+		return errors.Join(err, ew.w.Close())
+	}
+	return ew.w.Close()
+}
+
+// decryptingReader decrypts the data read from the wrapped ReadCloser.
+type decryptingReader struct {
+	r       *bufio.Reader
+	closer  io.Closer
+	aead    cipher.AEAD
+	aad     []byte
+	nonce   []byte
+	counter uint64
+	// skip is the number of plaintext bytes to skip in the first segment.
+	skip    int
+	buf     []byte
+	pending []byte
+	done    bool
+}
+
+// newDecryptingReader returns a reader decrypting rc. If salt is nil, it is
+// read from the header at the start of rc, otherwise rc must start at a segment.
+func newDecryptingReader(rc io.ReadCloser, dataKey []byte, filename string, salt []byte) (*decryptingReader, error) {
+	r := bufio.NewReader(rc)
+	if salt == nil {
+		var err error
+		if salt, err = readEncryptionHeader(r); err != nil {
+			return nil, fmt.Errorf("backup file %s: %w", filename, err)
+		}
+	}
+	aead, err := newFileAEAD(dataKey, salt)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptingReader{
+		r:      r,
+		closer: rc,
+		aead:   aead,
+		aad:    []byte(filename),
+		nonce:  make([]byte, aead.NonceSize()),
+		buf:    make([]byte, encryptedSegmentSize),
+	}, nil
+}
+
+func (dr *decryptingReader) Read(p []byte) (int, error) {
+	for len(dr.pending) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.readSegment(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.pending)
+	dr.pending = dr.pending[n:]
+	return n, nil
+}
+
+func (dr *decryptingReader) readSegment() error {
+	n, err := io.ReadFull(dr.r, dr.buf)
+	last := false
+	switch {
+	case errors.Is(err, io.EOF):
+		// The last segment is never empty, the file was truncated.
+		return fmt.Errorf("backup file %s is truncated: %w", dr.aad, io.ErrUnexpectedEOF)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, err := dr.r.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	plaintext, err := dr.aead.Open(dr.buf[:0], segmentNonce(dr.nonce, dr.counter, last), dr.buf[:n], dr.aad)
+	if err != nil {
+		return fmt.Errorf("backup file %s: cannot decrypt segment %d: %w", dr.aad, dr.counter, err)
+	}
+	dr.counter++
+	dr.done = last
+
+	skip := min(dr.skip, len(plaintext))
+	dr.skip -= skip
+	dr.pending = plaintext[skip:]
+	return nil
+}
+
+func (dr *decryptingReader) Close() error {
+	return dr.closer.Close()
+}
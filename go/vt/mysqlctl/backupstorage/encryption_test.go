@@ -0,0 +1,451 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mysqlctlerrors "vitess.io/vitess/go/vt/mysqlctl/errors"
+)
+
+// memoryBackupStorage is a BackupStorage keeping the files in memory.
+type memoryBackupStorage struct {
+	mu      sync.Mutex
+	backups map[string]map[string][]byte
+}
+
+func newMemoryBackupStorage() *memoryBackupStorage {
+	return &memoryBackupStorage{backups: map[string]map[string][]byte{}}
+}
+
+func (bs *memoryBackupStorage) ListBackups(ctx context.Context, dir string) ([]BackupHandle, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	var bhs []BackupHandle
+	for path := range bs.backups {
+		if d, name, _ := strings.Cut(path, "|"); d == dir {
+			bhs = append(bhs, &memoryBackupHandle{bs: bs, dir: dir, name: name, readOnly: true})
+		}
+	}
+	return bhs, nil
+}
+
+func (bs *memoryBackupStorage) StartBackup(ctx context.Context, dir, name string) (BackupHandle, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	bs.backups[dir+"|"+name] = map[string][]byte{}
+	return &memoryBackupHandle{bs: bs, dir: dir, name: name}, nil
+}
+
+func (bs *memoryBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	delete(bs.backups, dir+"|"+name)
+	return nil
+}
+
+func (bs *memoryBackupStorage) WriteFile(ctx context.Context, dir, name, filename string, data []byte) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	files, ok := bs.backups[dir+"|"+name]
+	if !ok {
+		return fmt.Errorf("backup %s/%s not found", dir, name)
+	}
+	files[filename] = data
+	return nil
+}
+
+func (bs *memoryBackupStorage) Close() error                    { return nil }
+func (bs *memoryBackupStorage) WithParams(Params) BackupStorage { return bs }
+
+// files returns the files of a backup, as stored.
+func (bs *memoryBackupStorage) files(dir, name string) map[string][]byte {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	return bs.backups[dir+"|"+name]
+}
+
+type memoryBackupHandle struct {
+	mysqlctlerrors.PerFileErrorRecorder
+	bs        *memoryBackupStorage
+	dir, name string
+	readOnly  bool
+}
+
+func (bh *memoryBackupHandle) Directory() string { return bh.dir }
+func (bh *memoryBackupHandle) Name() string      { return bh.name }
+
+type memoryFile struct {
+	bytes.Buffer
+	close func([]byte)
+}
+
+func (f *memoryFile) Close() error {
+	f.close(f.Bytes())
+	return nil
+}
+
+func (bh *memoryBackupHandle) AddFile(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	if bh.readOnly {
+		return nil, errors.New("AddFile cannot be called on read-only backup")
+	}
+	return &memoryFile{close: func(data []byte) {
+		bh.bs.mu.Lock()
+		defer bh.bs.mu.Unlock()
+		bh.bs.backups[bh.dir+"|"+bh.name][filename] = data
+	}}, nil
+}
+
+func (bh *memoryBackupHandle) EndBackup(ctx context.Context) error   { return nil }
+func (bh *memoryBackupHandle) AbortBackup(ctx context.Context) error { return nil }
+
+func (bh *memoryBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	data, ok := bh.bs.files(bh.dir, bh.name)[filename]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found", filename)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// rangeMemoryBackupHandle is a memoryBackupHandle that implements RangeReader.
+type rangeMemoryBackupHandle struct {
+	*memoryBackupHandle
+	rangeReads int
+}
+
+func (bh *rangeMemoryBackupHandle) ReadFileAt(ctx context.Context, filename string, offset int64) (io.ReadCloser, error) {
+	bh.rangeReads++
+	rc, err := bh.ReadFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.CopyN(io.Discard, rc, offset)
+	return rc, err
+}
+
+// setupEncryptionKey writes a random key file, and configures the file key provider with it.
+func setupEncryptionKey(t *testing.T) {
+	key := make([]byte, encryptionKeySize)
+	for i := range key {
+		key[i] = byte(rand.IntN(256))
+	}
+	path := filepath.Join(t.TempDir(), "backup.key")
+	require.NoError(t, os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0o600))
+
+	oldKeyFile := encryptionKeyFile
+	encryptionKeyFile = path
+	t.Cleanup(func() { encryptionKeyFile = oldKeyFile })
+}
+
+// writeEncryptedBackup writes files to a new encrypted backup, and a MANIFEST with its encryption info.
+func writeEncryptedBackup(t *testing.T, bs BackupStorage, files map[string][]byte) {
+	ctx := t.Context()
+	bh, err := bs.StartBackup(ctx, "ks/0", "backup")
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(bh))
+
+	for name, data := range files {
+		wc, err := bh.AddFile(ctx, name, int64(len(data)))
+		require.NoError(t, err)
+		// Write in uneven pieces to cover writes spanning segments.
+		for p := data; len(p) > 0; {
+			n := min(len(p), 1000+rand.IntN(100000))
+			_, err := wc.Write(p[:n])
+			require.NoError(t, err)
+			p = p[n:]
+		}
+		require.NoError(t, wc.Close())
+	}
+
+	manifest, err := json.Marshal(struct{ Encryption *EncryptionInfo }{GetEncryptionInfo(bh)})
+	require.NoError(t, err)
+	wc, err := bh.AddFile(ctx, ManifestFileName, FileSizeUnknown)
+	require.NoError(t, err)
+	_, err = wc.Write(manifest)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+	require.NoError(t, bh.EndBackup(ctx))
+}
+
+func readBackupFile(t *testing.T, bs BackupStorage, name string) ([]byte, error) {
+	bhs, err := bs.ListBackups(t.Context(), "ks/0")
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+	rc, err := bhs[0].ReadFile(t.Context(), name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func randomData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(rand.IntN(256))
+	}
+	return data
+}
+
+func TestEncryptedBackupStorage(t *testing.T) {
+	setupEncryptionKey(t)
+	mbs := newMemoryBackupStorage()
+	bs, err := NewEncryptedBackupStorage(mbs, FileKeyProviderName)
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for i, size := range []int{0, 1, encryptionSegmentSize - 1, encryptionSegmentSize, encryptionSegmentSize + 1, 3*encryptionSegmentSize + 5} {
+		files[fmt.Sprint(i)] = randomData(size)
+	}
+	writeEncryptedBackup(t, bs, files)
+
+	stored := mbs.files("ks/0", "backup")
+	// The MANIFEST is not encrypted.
+	var manifest struct{ Encryption *EncryptionInfo }
+	require.NoError(t, json.Unmarshal(stored[ManifestFileName], &manifest))
+	require.NotNil(t, manifest.Encryption)
+	assert.Equal(t, EncryptionAlgorithm, manifest.Encryption.Algorithm)
+	assert.Equal(t, FileKeyProviderName, manifest.Encryption.KeyProvider)
+	assert.True(t, strings.HasPrefix(manifest.Encryption.KeyRef, "sha256:"))
+
+	for name, data := range files {
+		t.Run(name, func(t *testing.T) {
+			assert.Len(t, stored[name], int(encryptedFileSize(int64(len(data)))))
+			if len(data) > 16 {
+				assert.False(t, bytes.Contains(stored[name], data[:16]), "data is stored in the clear")
+			}
+
+			got, err := readBackupFile(t, bs, name)
+			require.NoError(t, err)
+			assert.Equal(t, len(data), len(got))
+			assert.True(t, bytes.Equal(data, got))
+
+			// Without the encryption, the data is unreadable.
+			got, err = readBackupFile(t, mbs, name)
+			require.NoError(t, err)
+			assert.False(t, bytes.Equal(data, got))
+		})
+	}
+}
+
+func TestEncryptedFileSize(t *testing.T) {
+	dataKey := randomData(encryptionKeySize)
+	for _, size := range []int{0, 1, 16, encryptionSegmentSize - 1, encryptionSegmentSize, encryptionSegmentSize + 1, 2 * encryptionSegmentSize, 2*encryptionSegmentSize + 17} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			var buf nopCloserBuffer
+			ew, err := newEncryptingWriter(&buf, dataKey, "file")
+			require.NoError(t, err)
+			_, err = ew.Write(randomData(size))
+			require.NoError(t, err)
+			require.NoError(t, ew.Close())
+			assert.EqualValues(t, buf.Len(), encryptedFileSize(int64(size)))
+		})
+	}
+	assert.EqualValues(t, -1, encryptedFileSize(-1))
+}
+
+// nopCloserBuffer is a bytes.Buffer with a no-op Close.
+type nopCloserBuffer struct {
+	bytes.Buffer
+}
+
+func (*nopCloserBuffer) Close() error { return nil }
+
+func TestEncryptedBackupStorageReadFileAt(t *testing.T) {
+	setupEncryptionKey(t)
+	mbs := newMemoryBackupStorage()
+	bs, err := NewEncryptedBackupStorage(mbs, FileKeyProviderName)
+	require.NoError(t, err)
+
+	data := randomData(3*encryptionSegmentSize + 5)
+	writeEncryptedBackup(t, bs, map[string][]byte{"0": data})
+
+	for _, rangeReader := range []bool{false, true} {
+		for _, offset := range []int64{0, 10, encryptionSegmentSize, 2*encryptionSegmentSize + 7, int64(len(data))} {
+			t.Run(fmt.Sprintf("rangeReader=%t,offset=%d", rangeReader, offset), func(t *testing.T) {
+				inner := &memoryBackupHandle{bs: mbs, dir: "ks/0", name: "backup", readOnly: true}
+				var bh BackupHandle = inner
+				rbh := &rangeMemoryBackupHandle{memoryBackupHandle: inner}
+				if rangeReader {
+					bh = rbh
+				}
+				ebh := &encryptedBackupHandle{BackupHandle: bh}
+
+				rc, err := ebh.ReadFileAt(t.Context(), "0", offset)
+				require.NoError(t, err)
+				got, err := io.ReadAll(rc)
+				require.NoError(t, err)
+				require.NoError(t, rc.Close())
+				assert.True(t, bytes.Equal(data[offset:], got))
+				if rangeReader && offset >= encryptionSegmentSize {
+					assert.Equal(t, 2, rbh.rangeReads)
+				}
+			})
+		}
+	}
+}
+
+func TestEncryptedBackupStorageTampering(t *testing.T) {
+	setupEncryptionKey(t)
+	data := randomData(2*encryptionSegmentSize + 5)
+
+	tests := []struct {
+		name   string
+		tamper func(files map[string][]byte)
+		err    string
+	}{
+		{
+			name: "flipped byte",
+			tamper: func(files map[string][]byte) {
+				files["0"][encryptionHeaderSize+encryptedSegmentSize+10] ^= 1
+			},
+			err: "backup file 0: cannot decrypt segment 1",
+		},
+		{
+			name: "truncated last segment",
+			tamper: func(files map[string][]byte) {
+				files["0"] = files["0"][:len(files["0"])-1]
+			},
+			err: "backup file 0: cannot decrypt segment 2",
+		},
+		{
+			name: "dropped last segment",
+			tamper: func(files map[string][]byte) {
+				files["0"] = files["0"][:encryptionHeaderSize+2*encryptedSegmentSize]
+			},
+			err: "backup file 0: cannot decrypt segment 1",
+		},
+		{
+			name: "truncated at segment boundary",
+			tamper: func(files map[string][]byte) {
+				files["0"] = files["0"][:encryptionHeaderSize]
+			},
+			err: "backup file 0 is truncated",
+		},
+		{
+			name: "swapped files",
+			tamper: func(files map[string][]byte) {
+				files["0"], files["1"] = files["1"], files["0"]
+			},
+			err: "backup file 0: cannot decrypt segment 0",
+		},
+		{
+			name: "not encrypted",
+			tamper: func(files map[string][]byte) {
+				files["0"] = data
+			},
+			err: "backup file 0: file is not encrypted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mbs := newMemoryBackupStorage()
+			bs, err := NewEncryptedBackupStorage(mbs, FileKeyProviderName)
+			require.NoError(t, err)
+			writeEncryptedBackup(t, bs, map[string][]byte{"0": data, "1": data})
+
+			tt.tamper(mbs.files("ks/0", "backup"))
+			_, err = readBackupFile(t, bs, "0")
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestEncryptedBackupStorageUnencryptedBackup(t *testing.T) {
+	setupEncryptionKey(t)
+	mbs := newMemoryBackupStorage()
+	data := randomData(100)
+
+	// A backup taken before encryption was enabled.
+	bh, err := mbs.StartBackup(t.Context(), "ks/0", "backup")
+	require.NoError(t, err)
+	for name, content := range map[string][]byte{"0": data, ManifestFileName: []byte("{}")} {
+		wc, err := bh.AddFile(t.Context(), name, int64(len(content)))
+		require.NoError(t, err)
+		_, err = wc.Write(content)
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+	}
+
+	bs, err := NewEncryptedBackupStorage(mbs, FileKeyProviderName)
+	require.NoError(t, err)
+	got, err := readBackupFile(t, bs, "0")
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestEncryptedBackupStorageWriteFile(t *testing.T) {
+	setupEncryptionKey(t)
+	mbs := newMemoryBackupStorage()
+	bs, err := NewEncryptedBackupStorage(mbs, FileKeyProviderName)
+	require.NoError(t, err)
+	writeEncryptedBackup(t, bs, map[string][]byte{"0": randomData(100)})
+
+	fw, ok := bs.(FileWriter)
+	require.True(t, ok)
+	// The VERIFICATION file is stored in the clear, and read back as is.
+	verification := []byte(`{"BackupName":"backup"}`)
+	require.NoError(t, fw.WriteFile(t.Context(), "ks/0", "backup", VerificationFileName, verification))
+	assert.Equal(t, verification, mbs.files("ks/0", "backup")[VerificationFileName])
+	got, err := readBackupFile(t, bs, VerificationFileName)
+	require.NoError(t, err)
+	assert.Equal(t, verification, got)
+
+	// Data files cannot be written into a completed backup.
+	require.Error(t, fw.WriteFile(t.Context(), "ks/0", "backup", "1", randomData(100)))
+}
+
+func TestEncryptedBackupStorageWrongKey(t *testing.T) {
+	setupEncryptionKey(t)
+	mbs := newMemoryBackupStorage()
+	bs, err := NewEncryptedBackupStorage(mbs, FileKeyProviderName)
+	require.NoError(t, err)
+	writeEncryptedBackup(t, bs, map[string][]byte{"0": randomData(100)})
+
+	// Rotate the key.
+	setupEncryptionKey(t)
+	_, err = readBackupFile(t, bs, "0")
+	require.ErrorContains(t, err, "backup data key was wrapped with key sha256:")
+}
+
+func TestNewEncryptedBackupStorage(t *testing.T) {
+	_, err := NewEncryptedBackupStorage(newMemoryBackupStorage(), "foobar")
+	require.ErrorContains(t, err, `unknown backup encryption key provider "foobar"`)
+
+	oldKeyFile := encryptionKeyFile
+	defer func() { encryptionKeyFile = oldKeyFile }()
+	encryptionKeyFile = ""
+	bs, err := NewEncryptedBackupStorage(newMemoryBackupStorage(), FileKeyProviderName)
+	require.NoError(t, err)
+	_, err = bs.StartBackup(t.Context(), "ks/0", "backup")
+	require.ErrorContains(t, err, "--backup-storage-encryption-key-file is required")
+}
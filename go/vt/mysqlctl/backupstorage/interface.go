@@ -95,6 +95,26 @@ type BackupHandle interface {
 	mysqlctlerrors.BackupErrorRecorder
 }
 
+// RangeReader is an optional interface a BackupHandle can implement to
+// start reading a file in the middle, without reading the data before it.
+// The builtin backup engine uses it to re-read a corrupt chunk of a file.
+type RangeReader interface {
+	// ReadFileAt starts reading a file from a backup at the given offset.
+	// Only works for read-only backups (created by ListBackups).
+	// The context is valid for the duration of the reads, until the
+	// ReadCloser is closed.
+	ReadFileAt(ctx context.Context, filename string, offset int64) (io.ReadCloser, error)
+}
+
+// FileWriter is an optional interface a BackupStorage can implement to write a
+// file into a backup that was already completed. It is only meant for small
+// metadata files that describe the backup, such as the record of its last
+// verification, and replaces the file if it exists.
+type FileWriter interface {
+	// WriteFile writes data to filename in the backup with the given name.
+	WriteFile(ctx context.Context, dir, name, filename string, data []byte) error
+}
+
 // BackupStorage is the interface to the storage system
 type BackupStorage interface {
 	// ListBackups returns all the backups in a directory.  The
@@ -133,6 +153,8 @@ type BackupStorage interface {
 var BackupStorageMap = make(map[string]BackupStorage)
 
 // GetBackupStorage returns the current BackupStorage implementation.
+// If a backup encryption key provider is configured, it is wrapped so that
+// the files of new backups are encrypted.
 // Should be called after flags have been initialized.
 // When all operations are done, call BackupStorage.Close() to free resources.
 func GetBackupStorage() (BackupStorage, error) {
@@ -140,5 +162,8 @@ func GetBackupStorage() (BackupStorage, error) {
 	if !ok {
 		return nil, errors.New("no registered implementation of BackupStorage")
 	}
+	if encryptionKeyProvider != "" {
+		return NewEncryptedBackupStorage(bs, encryptionKeyProvider)
+	}
 	return bs, nil
 }
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// KeyProvider wraps and unwraps the data keys that backups are encrypted with.
+// Every encrypted backup has its own data key, which is stored in the backup
+// MANIFEST wrapped by the key provider.
+type KeyProvider interface {
+	// WrapKey encrypts a data key. It returns the wrapped key, and a reference
+	// to the key it was wrapped with, which is passed back to UnwrapKey.
+	WrapKey(ctx context.Context, dataKey []byte) (wrappedKey []byte, keyRef string, err error)
+
+	// UnwrapKey decrypts a data key that was wrapped by WrapKey.
+	UnwrapKey(ctx context.Context, wrappedKey []byte, keyRef string) ([]byte, error)
+}
+
+// KeyProviderMap contains the registered implementations for KeyProvider.
+var KeyProviderMap = make(map[string]KeyProvider)
+
+// FileKeyProviderName is the name of the KeyProvider that wraps data keys
+// with a key read from a local file.
+const FileKeyProviderName = "file"
+
+func init() {
+	KeyProviderMap[FileKeyProviderName] = &fileKeyProvider{}
+}
+
+// fileKeyProvider wraps data keys with AES-256-GCM, using the key in the file
+// given by --backup-storage-encryption-key-file. The key file holds either 32
+// raw bytes, or their hex encoding.
+type fileKeyProvider struct {
+	mu     sync.Mutex
+	path   string
+	aead   cipher.AEAD
+	keyRef string
+}
+
+// load reads the key file, if it was not read already or the flag changed.
+func (kp *fileKeyProvider) load() (cipher.AEAD, string, error) {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+
+	if kp.aead != nil && kp.path == encryptionKeyFile {
+		return kp.aead, kp.keyRef, nil
+	}
+	if encryptionKeyFile == "" {
+		return nil, "", errors.New("--backup-storage-encryption-key-file is required by the file key provider")
+	}
+	data, err := os.ReadFile(encryptionKeyFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read backup encryption key file: %w", err)
+	}
+	key := data
+	if len(key) != encryptionKeySize {
+		key, err = hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != encryptionKeySize {
+			return nil, "", fmt.Errorf("backup encryption key file %s must contain a %d bytes key, raw or hex encoded", encryptionKeyFile, encryptionKeySize)
+		}
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, "", err
+	}
+	fingerprint := sha256.Sum256(key)
+
+	kp.path = encryptionKeyFile
+	kp.aead = aead
+	kp.keyRef = "sha256:" + hex.EncodeToString(fingerprint[:8])
+	return kp.aead, kp.keyRef, nil
+}
+
+// WrapKey is part of the KeyProvider interface.
+func (kp *fileKeyProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, string, error) {
+	aead, keyRef, err := kp.load()
+	if err != nil {
+		return nil, "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", err
+	}
+	return aead.Seal(nonce, nonce, dataKey, []byte(keyRef)), keyRef, nil
+}
+
+// UnwrapKey is part of the KeyProvider interface.
+func (kp *fileKeyProvider) UnwrapKey(ctx context.Context, wrappedKey []byte, keyRef string) ([]byte, error) {
+	aead, currentKeyRef, err := kp.load()
+	if err != nil {
+		return nil, err
+	}
+	if keyRef != currentKeyRef {
+		return nil, fmt.Errorf("backup data key was wrapped with key %s, but the backup encryption key file has key %s", keyRef, currentKeyRef)
+	}
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, errors.New("wrapped backup data key is too short")
+	}
+	nonce, ciphertext := wrappedKey[:aead.NonceSize()], wrappedKey[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, ciphertext, []byte(keyRef))
+	if err != nil {
+		return nil, fmt.Errorf("cannot unwrap backup data key: %w", err)
+	}
+	return dataKey, nil
+}
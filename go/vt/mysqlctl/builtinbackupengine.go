@@ -69,6 +69,10 @@ const (
 	// not retry.
 	maxRetriesPerFile   = 1
 	maxFileCloseRetries = 20 // At this point we should consider it permanent
+
+	// How many times we will re-read a chunk of a file from the backup storage
+	// when it doesn't match its checksum, before failing the file.
+	maxRetriesPerChunk = 3
 )
 
 var (
@@ -90,6 +94,11 @@ var (
 	// network, or something else.
 	builtinBackupStorageWriteBufferSize = 2 * 1024 * 1024 /* 2 MiB */
 
+	// The size of the chunks that get their own checksum in the MANIFEST, so that
+	// restores can detect and re-read a corrupt chunk instead of failing the
+	// whole file. Chunk checksums are disabled when set to 0.
+	builtinBackupChunkChecksumSize uint = 16 * 1024 * 1024 /* 16 MiB */
+
 	// The directory where incremental restore files, namely binlog files, are extracted to.
 	// In k8s environments, this should be set to a directory that is shared between the vttablet and mysqld pods.
 	// The path should exist.
@@ -159,6 +168,14 @@ type FileEntry struct {
 	// for writing files in a temporary directory
 	ParentPath string
 
+	// ChunkSize is the size of the chunks the data stored in the BackupStorage was
+	// split into to compute ChunkHashes. The last chunk may be shorter.
+	ChunkSize int64 `json:",omitempty"`
+
+	// ChunkHashes are the hashes of every chunk of the data stored in the
+	// BackupStorage. They are empty for backups taken without chunk checksums.
+	ChunkHashes []string `json:",omitempty"`
+
 	// RetryCount specifies how many times we retried restoring/backing up this FileEntry.
 	// If we fail to restore/backup this FileEntry, we will retry up to maxRetriesPerFile times.
 	// Every time the builtin backup engine retries this file, we increment this field by 1.
@@ -178,6 +195,7 @@ func registerBuiltinBackupEngineFlags(fs *pflag.FlagSet) {
 	utils.SetFlagDurationVar(fs, &builtinBackupProgress, "builtinbackup-progress", builtinBackupProgress, "how often to send progress updates when backing up large files.")
 	fs.UintVar(&builtinBackupFileReadBufferSize, "builtinbackup-file-read-buffer-size", builtinBackupFileReadBufferSize, "read files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
 	fs.UintVar(&builtinBackupFileWriteBufferSize, "builtinbackup-file-write-buffer-size", builtinBackupFileWriteBufferSize, "write files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
+	fs.UintVar(&builtinBackupChunkChecksumSize, "builtinbackup-chunk-checksum-size", builtinBackupChunkChecksumSize, "record a checksum in the backup MANIFEST for every chunk of this many bytes of the stored files, so that a restore can re-read a corrupt chunk instead of failing the whole file. Chunk checksums are disabled when set to 0.")
 	fs.StringVar(&builtinIncrementalRestorePath, "builtinbackup-incremental-restore-path", builtinIncrementalRestorePath, "the directory where incremental restore files, namely binlog files, are extracted to. In k8s environments, this should be set to a directory that is shared between the vttablet and mysqld pods. The path should exist. When empty, the default OS temp dir is assumed.")
 }
 
@@ -745,6 +763,7 @@ type backupPipe struct {
 	w *bufio.Writer
 
 	crc32  hash.Hash32
+	chunks *chunkHasher
 	nn     int64
 	done   chan struct{}
 	failed chan struct{}
@@ -788,6 +807,9 @@ func (bp *backupPipe) Read(p []byte) (int, error) {
 func (bp *backupPipe) Write(p []byte) (int, error) {
 	nn, err := bp.w.Write(p)
 	_, _ = bp.crc32.Write(p[:nn])
+	if bp.chunks != nil {
+		_, _ = bp.chunks.Write(p[:nn])
+	}
 	atomic.AddInt64(&bp.nn, int64(nn))
 	return nn, err
 }
@@ -910,6 +932,9 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 	timedDest := ioutil.NewMeteredWriteCloser(dest, destStats.TimedIncrementBytes)
 
 	bw := newBackupWriter(fe.Name, builtinBackupStorageWriteBufferSize, fi.Size(), timedDest)
+	if builtinBackupChunkChecksumSize > 0 {
+		bw.chunks = newChunkHasher(int64(builtinBackupChunkChecksumSize))
+	}
 
 	// We create the following inner function because:
 	// - we must `defer` the compressor's Close() function
@@ -975,8 +1000,11 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 		return errors.Join(finalErr, err)
 	}
 
-	// Save the hash.
+	// Save the hashes.
 	fe.Hash = bw.HashString()
+	if bw.chunks != nil {
+		fe.ChunkSize, fe.ChunkHashes = bw.chunks.Hashes()
+	}
 	return nil
 }
 
@@ -1045,6 +1073,7 @@ func (be *BuiltinBackupEngine) backupManifest(
 				MySQLVersion:       mysqlVersion,
 				UpgradeSafe:        params.UpgradeSafe,
 				IncrementalDetails: incrDetails,
+				Encryption:         backupstorage.GetEncryptionInfo(bh),
 			},
 
 			// Builtin-specific fields
@@ -1206,11 +1235,13 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 			}
 			oldFes := fes[fileNb]
 			newFEs[fileNb] = FileEntry{
-				Base:       oldFes.Base,
-				Name:       oldFes.Name,
-				ParentPath: oldFes.ParentPath,
-				Hash:       oldFes.Hash,
-				RetryCount: 1,
+				Base:        oldFes.Base,
+				Name:        oldFes.Name,
+				ParentPath:  oldFes.ParentPath,
+				Hash:        oldFes.Hash,
+				ChunkSize:   oldFes.ChunkSize,
+				ChunkHashes: oldFes.ChunkHashes,
+				RetryCount:  1,
 			}
 			bh.ResetErrorForFile(file)
 		}
@@ -1275,6 +1306,10 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 	if err != nil {
 		return vterrors.Wrap(err, "can't open source file for reading")
 	}
+	if len(fe.ChunkHashes) > 0 {
+		// Verify every chunk as it's read, re-reading the corrupt ones from the backup storage.
+		source = newChunkVerifyingReader(ctx, bh, name, fe, source, params.Logger)
+	}
 	params.Stats.Scope(stats.Operation("Source:Open")).TimedIncrement(time.Since(openSourceAt))
 
 	readStats := params.Stats.Scope(stats.Operation("Source:Read"))
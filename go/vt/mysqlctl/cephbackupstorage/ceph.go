@@ -19,6 +19,7 @@ limitations under the License.
 package cephbackupstorage
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -250,6 +251,18 @@ func (bs *CephBackupStorage) RemoveBackup(ctx context.Context, dir, name string)
 	return nil
 }
 
+// WriteFile implements backupstorage.FileWriter.
+func (bs *CephBackupStorage) WriteFile(ctx context.Context, dir, name, filename string, data []byte) error {
+	c, err := bs.client()
+	if err != nil {
+		return err
+	}
+
+	object := objName(dir, name, filename)
+	_, err = c.PutObjectWithContext(ctx, alterBucketName(dir), object, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/octet-stream"})
+	return err
+}
+
 // Close implements BackupStorage.
 func (bs *CephBackupStorage) Close() error {
 	bs.mu.Lock()
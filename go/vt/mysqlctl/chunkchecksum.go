@@ -0,0 +1,212 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+	"slices"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// chunkHasher computes the crc32 of every chunkSize bytes written to it.
+type chunkHasher struct {
+	chunkSize int64
+	crc32     hash.Hash32
+	// nn is the number of bytes written to the current chunk.
+	nn     int64
+	hashes []string
+}
+
+func newChunkHasher(chunkSize int64) *chunkHasher {
+	return &chunkHasher{
+		chunkSize: chunkSize,
+		crc32:     crc32.NewIEEE(),
+	}
+}
+
+func (ch *chunkHasher) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		size := min(int64(len(p)), ch.chunkSize-ch.nn)
+		_, _ = ch.crc32.Write(p[:size])
+		ch.nn += size
+		p = p[size:]
+		if ch.nn == ch.chunkSize {
+			ch.hashes = append(ch.hashes, hex.EncodeToString(ch.crc32.Sum(nil)))
+			ch.crc32.Reset()
+			ch.nn = 0
+		}
+	}
+	return n, nil
+}
+
+// Hashes returns the chunk size and the hashes of all the chunks written so far,
+// including the last partial chunk. It returns no hashes if nothing was written.
+func (ch *chunkHasher) Hashes() (int64, []string) {
+	hashes := ch.hashes
+	if ch.nn > 0 {
+		hashes = append(slices.Clip(hashes), hex.EncodeToString(ch.crc32.Sum(nil)))
+	}
+	if len(hashes) == 0 {
+		return 0, nil
+	}
+	return ch.chunkSize, hashes
+}
+
+// chunkVerifyingReader reads a file from the backup storage one chunk at a time,
+// and verifies each chunk against the hash recorded in the MANIFEST before
+// returning its data. A chunk that can't be read or doesn't match its hash is
+// read again from the backup storage, up to maxRetriesPerChunk times, so that a
+// single corrupt chunk doesn't fail the restore of the whole file.
+type chunkVerifyingReader struct {
+	ctx    context.Context
+	bh     backupstorage.BackupHandle
+	name   string
+	fe     *FileEntry
+	logger logutil.Logger
+
+	source io.ReadCloser
+	// chunk is the index of the next chunk to read from source.
+	chunk   int
+	buf     []byte
+	pending []byte
+	crc32   hash.Hash32
+}
+
+func newChunkVerifyingReader(ctx context.Context, bh backupstorage.BackupHandle, name string, fe *FileEntry, source io.ReadCloser, logger logutil.Logger) *chunkVerifyingReader {
+	return &chunkVerifyingReader{
+		ctx:    ctx,
+		bh:     bh,
+		name:   name,
+		fe:     fe,
+		logger: logger,
+		source: source,
+		buf:    make([]byte, fe.ChunkSize),
+		crc32:  crc32.NewIEEE(),
+	}
+}
+
+func (r *chunkVerifyingReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		if r.chunk >= len(r.fe.ChunkHashes) {
+			return 0, io.EOF
+		}
+		if err := r.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// readChunk reads and verifies the next chunk, retrying it if needed.
+func (r *chunkVerifyingReader) readChunk() error {
+	offset := int64(r.chunk) * r.fe.ChunkSize
+	var err error
+	for attempt := 0; attempt <= maxRetriesPerChunk; attempt++ {
+		if attempt > 0 {
+			if r.ctx.Err() != nil {
+				return r.ctx.Err()
+			}
+			r.logger.Warningf("Re-reading chunk %d at offset %d of %v (attempt %d/%d): %v", r.chunk, offset, r.fe.Name, attempt+1, maxRetriesPerChunk+1, err)
+			if err = r.reopen(offset); err != nil {
+				continue
+			}
+		}
+
+		var n int
+		if n, err = r.readFull(); err != nil {
+			continue
+		}
+		r.crc32.Reset()
+		_, _ = r.crc32.Write(r.buf[:n])
+		if hash := hex.EncodeToString(r.crc32.Sum(nil)); hash != r.fe.ChunkHashes[r.chunk] {
+			err = vterrors.Errorf(vtrpcpb.Code_INTERNAL, "hash mismatch, got %v expected %v", hash, r.fe.ChunkHashes[r.chunk])
+			continue
+		}
+
+		r.pending = r.buf[:n]
+		r.chunk++
+		return nil
+	}
+	return vterrors.Wrapf(err, "failed to read chunk %d at offset %d of %v", r.chunk, offset, r.fe.Name)
+}
+
+// readFull reads the next chunk into buf. Only the last chunk may be shorter than the chunk size.
+func (r *chunkVerifyingReader) readFull() (int, error) {
+	if r.source == nil {
+		return 0, errors.New("no source to read from")
+	}
+	n, err := io.ReadFull(r.source, r.buf)
+	if errors.Is(err, io.ErrUnexpectedEOF) && r.chunk == len(r.fe.ChunkHashes)-1 {
+		return n, nil
+	}
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// reopen closes the current source, and opens the file again at the given offset.
+func (r *chunkVerifyingReader) reopen(offset int64) error {
+	if r.source != nil {
+		if err := r.source.Close(); err != nil {
+			r.logger.Warningf("Failed to close source file %v: %v", r.fe.Name, err)
+		}
+		r.source = nil
+	}
+
+	if rr, ok := r.bh.(backupstorage.RangeReader); ok {
+		source, err := rr.ReadFileAt(r.ctx, r.name, offset)
+		if err != nil {
+			return err
+		}
+		r.source = source
+		return nil
+	}
+
+	// The backup storage can't start reading in the middle of a file, skip to the offset instead.
+	source, err := r.bh.ReadFile(r.ctx, r.name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(io.Discard, source, offset); err != nil {
+		source.Close()
+		return err
+	}
+	r.source = source
+	return nil
+}
+
+func (r *chunkVerifyingReader) Close() error {
+	if r.source == nil {
+		return nil
+	}
+	err := r.source.Close()
+	r.source = nil
+	return err
+}
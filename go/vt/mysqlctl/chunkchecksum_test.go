@@ -0,0 +1,192 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"hash/crc32"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+func crc32Hex(data []byte) string {
+	h := crc32.NewIEEE()
+	_, _ = h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// chunkedFileEntry returns a FileEntry with the chunk hashes of data.
+func chunkedFileEntry(t *testing.T, data []byte, chunkSize int64) *FileEntry {
+	ch := newChunkHasher(chunkSize)
+	// Write in odd sized pieces to cover writes spanning chunk boundaries.
+	for p := data; len(p) > 0; {
+		n := min(len(p), 7)
+		_, err := ch.Write(p[:n])
+		require.NoError(t, err)
+		p = p[n:]
+	}
+	fe := &FileEntry{Name: "file"}
+	fe.ChunkSize, fe.ChunkHashes = ch.Hashes()
+	return fe
+}
+
+func TestChunkHasher(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+
+	fe := chunkedFileEntry(t, data, 10)
+	assert.EqualValues(t, 10, fe.ChunkSize)
+	assert.Equal(t, []string{
+		crc32Hex(data[0:10]),
+		crc32Hex(data[10:20]),
+		crc32Hex(data[20:30]),
+		crc32Hex(data[30:]),
+	}, fe.ChunkHashes)
+
+	fe = chunkedFileEntry(t, data[:30], 10)
+	assert.Len(t, fe.ChunkHashes, 3)
+
+	fe = chunkedFileEntry(t, nil, 10)
+	assert.Zero(t, fe.ChunkSize)
+	assert.Nil(t, fe.ChunkHashes)
+}
+
+// corruptingBackupHandle serves data, flipping a byte at corruptOffset for the
+// first corruptReads reads of the file.
+type corruptingBackupHandle struct {
+	FakeBackupHandle
+	data          []byte
+	corruptOffset int
+	corruptReads  int
+
+	reads      int
+	rangeReads []int64
+}
+
+func (bh *corruptingBackupHandle) open(offset int64) io.ReadCloser {
+	data := bytes.Clone(bh.data)
+	if bh.reads < bh.corruptReads {
+		data[bh.corruptOffset] ^= 0xff
+	}
+	bh.reads++
+	return io.NopCloser(bytes.NewReader(data[offset:]))
+}
+
+func (bh *corruptingBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	return bh.open(0), nil
+}
+
+// rangeBackupHandle is a corruptingBackupHandle that implements backupstorage.RangeReader.
+type rangeBackupHandle struct {
+	*corruptingBackupHandle
+}
+
+func (bh rangeBackupHandle) ReadFileAt(ctx context.Context, filename string, offset int64) (io.ReadCloser, error) {
+	bh.rangeReads = append(bh.rangeReads, offset)
+	return bh.open(offset), nil
+}
+
+var _ backupstorage.RangeReader = rangeBackupHandle{}
+
+func TestChunkVerifyingReader(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+
+	tests := []struct {
+		name         string
+		corruptReads int
+		rangeReader  bool
+		err          string
+		reads        int
+		rangeReads   []int64
+	}{
+		{
+			name:  "no corruption",
+			reads: 1,
+		},
+		{
+			name:         "corrupt chunk re-read from its offset",
+			corruptReads: 1,
+			rangeReader:  true,
+			reads:        2,
+			rangeReads:   []int64{20},
+		},
+		{
+			name:         "corrupt chunk re-read from the start of the file",
+			corruptReads: 2,
+			reads:        3,
+		},
+		{
+			name:         "persistent corruption",
+			corruptReads: maxRetriesPerChunk + 1,
+			rangeReader:  true,
+			err:          "failed to read chunk 2 at offset 20 of file: hash mismatch",
+			reads:        maxRetriesPerChunk + 1,
+			rangeReads:   []int64{20, 20, 20},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fe := chunkedFileEntry(t, data, 10)
+			cbh := &corruptingBackupHandle{
+				data:          data,
+				corruptOffset: 25,
+				corruptReads:  tt.corruptReads,
+			}
+			var bh backupstorage.BackupHandle = cbh
+			if tt.rangeReader {
+				bh = rangeBackupHandle{cbh}
+			}
+
+			source, err := bh.ReadFile(t.Context(), "0")
+			require.NoError(t, err)
+			r := newChunkVerifyingReader(t.Context(), bh, "0", fe, source, logutil.NewMemoryLogger())
+			got, err := io.ReadAll(r)
+			require.NoError(t, r.Close())
+
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				// The verified chunks were returned before the failure.
+				assert.Equal(t, data[:20], got)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, data, got)
+			}
+			assert.Equal(t, tt.reads, cbh.reads)
+			assert.Equal(t, tt.rangeReads, cbh.rangeReads)
+		})
+	}
+}
+
+func TestChunkVerifyingReaderTruncated(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	fe := chunkedFileEntry(t, data, 10)
+
+	// The file is missing the last chunk altogether.
+	bh := &corruptingBackupHandle{data: data[:30], corruptOffset: 0}
+	source, err := bh.ReadFile(t.Context(), "0")
+	require.NoError(t, err)
+	r := newChunkVerifyingReader(t.Context(), bh, "0", fe, source, logutil.NewMemoryLogger())
+	_, err = io.ReadAll(r)
+	require.ErrorContains(t, err, "failed to read chunk 3 at offset 30 of file: unexpected EOF")
+}
@@ -21,6 +21,7 @@ This file contains common functions for cmd/mysqlctl and cmd/mysqlctld.
 package mysqlctl
 
 import (
+	"errors"
 	"fmt"
 
 	"vitess.io/vitess/go/mysql/collations"
@@ -51,6 +52,25 @@ func CreateMysqldAndMycnf(tabletUID uint32, mysqlSocket string, mysqlPort int, c
 	return NewMysqld(&dbconfigs.GlobalDBConfigs), mycnf, nil
 }
 
+// CreateScratchMysqldAndMycnf returns a Mysqld and a Mycnf object for a throwaway
+// MySQL installation, that hasn't been set up yet, next to the one of a running
+// tablet. The users of dbcfgs connect to it through its own socket file, and,
+// unlike CreateMysqldAndMycnf, dbconfigs.GlobalDBConfigs is left untouched.
+func CreateScratchMysqldAndMycnf(tabletUID uint32, mysqlPort int, dbcfgs *dbconfigs.DBConfigs, collationEnv *collations.Environment) (*Mysqld, *Mycnf, error) {
+	if socketFile != "" {
+		return nil, nil, errors.New("cannot run a scratch mysqld when mysqld is managed by mysqlctld (--mysqlctl-socket)")
+	}
+	if dbconfigs.GlobalDBConfigs.HasGlobalSettings() {
+		// NewMysqld would skip the flavor detection that the scratch mysqld needs.
+		return nil, nil, errors.New("cannot run a scratch mysqld when mysqld is unmanaged or remote (--db-host or --db-socket)")
+	}
+	mycnf := NewMycnf(tabletUID, mysqlPort)
+	if err := mycnf.RandomizeMysqlServerID(); err != nil {
+		return nil, nil, fmt.Errorf("couldn't generate random MySQL server_id: %v", err)
+	}
+	return NewMysqld(dbcfgs.CloneWithSocket(mycnf.SocketFile, collationEnv)), mycnf, nil
+}
+
 // OpenMysqldAndMycnf returns a Mysqld and a Mycnf object to use for working with a MySQL
 // installation that already exists. The Mycnf will be built based on the my.cnf file
 // of the MySQL instance.
@@ -47,6 +47,13 @@ const (
 
 var (
 	compressionLevel = 1
+	// zstdCompressionLevel is the native zstd compression level (1-22) used by the zstd engine.
+	// When 0, --compression-level is used instead.
+	zstdCompressionLevel = 0
+	// zstdWindowLog is the base 2 logarithm of the window size used by the zstd engine. Larger
+	// windows find matches further apart, at the expense of memory. When 0, the window size is
+	// derived from the compression level.
+	zstdWindowLog = 0
 	// CompressionEngineName specifies which compressor/decompressor to use
 	CompressionEngineName = "pargzip"
 	// ExternalCompressorCmd / ExternalDecompressorCmd specify the external commands compress/decompress the backups
@@ -75,6 +82,8 @@ func init() {
 
 func registerBackupCompressionFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&compressionLevel, "compression-level", compressionLevel, "what level to pass to the compressor.")
+	fs.IntVar(&zstdCompressionLevel, "compression-zstd-level", zstdCompressionLevel, "native zstd compression level (1-22) to use with the zstd compression engine. When 0, --compression-level is used instead.")
+	fs.IntVar(&zstdWindowLog, "compression-zstd-window-log", zstdWindowLog, "base 2 logarithm of the window size (10-29) to use with the zstd compression engine, like zstd's --long option. When 0, the window size is derived from the compression level.")
 	fs.StringVar(&CompressionEngineName, "compression-engine-name", CompressionEngineName, "compressor engine used for compression.")
 	fs.StringVar(&ExternalCompressorCmd, "external-compressor", ExternalCompressorCmd, "command with arguments to use when compressing a backup.")
 	fs.StringVar(&ExternalCompressorExt, "external-compressor-extension", ExternalCompressorExt, "extension to use when using an external compressor.")
@@ -261,7 +270,7 @@ func newBuiltinCompressor(engine string, writer io.Writer, logger logutil.Logger
 		}
 		compressor = lz4Writer
 	case ZstdCompressor:
-		zst, err := zstd.NewWriter(writer, zstd.WithEncoderLevel(zstd.EncoderLevel(compressionLevel)))
+		zst, err := zstd.NewWriter(writer, zstdEncoderOptions()...)
 		if err != nil {
 			return compressor, vterrors.Wrap(err, "cannot create zstd compressor")
 		}
@@ -275,6 +284,19 @@ func newBuiltinCompressor(engine string, writer io.Writer, logger logutil.Logger
 	return
 }
 
+// zstdEncoderOptions returns the options to create a zstd compressor with.
+func zstdEncoderOptions() []zstd.EOption {
+	level := zstd.EncoderLevel(compressionLevel)
+	if zstdCompressionLevel > 0 {
+		level = zstd.EncoderLevelFromZstd(zstdCompressionLevel)
+	}
+	opts := []zstd.EOption{zstd.WithEncoderLevel(level)}
+	if zstdWindowLog > 0 {
+		opts = append(opts, zstd.WithWindowSize(1<<zstdWindowLog))
+	}
+	return opts
+}
+
 // This struct wraps the underlying exec.Cmd and implements the io.WriteCloser interface.
 type externalCompressor struct {
 	cmd   *exec.Cmd
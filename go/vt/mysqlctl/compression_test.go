@@ -79,6 +79,48 @@ func TestBuiltinCompressors(t *testing.T) {
 	}
 }
 
+func TestZstdCompressorOptions(t *testing.T) {
+	data := bytes.Repeat([]byte("foo bar foobar "), 1000)
+	logger := logutil.NewMemoryLogger()
+
+	defer func(level, windowLog int) {
+		zstdCompressionLevel, zstdWindowLog = level, windowLog
+	}(zstdCompressionLevel, zstdWindowLog)
+
+	tests := []struct {
+		level, windowLog int
+		err              string
+	}{
+		{level: 0, windowLog: 0},
+		{level: 19, windowLog: 0},
+		{level: 3, windowLog: 27},
+		{level: 3, windowLog: 30, err: "window size must be at most"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("level=%d,windowLog=%d", tt.level, tt.windowLog), func(t *testing.T) {
+			zstdCompressionLevel, zstdWindowLog = tt.level, tt.windowLog
+
+			var compressed, decompressed bytes.Buffer
+			compressor, err := newBuiltinCompressor(ZstdCompressor, &compressed, logger)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			_, err = compressor.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, compressor.Close())
+
+			decompressor, err := newBuiltinDecompressor(ZstdCompressor, &compressed, logger)
+			require.NoError(t, err)
+			_, err = io.Copy(&decompressed, decompressor)
+			require.NoError(t, err)
+			decompressor.Close()
+			assert.Equal(t, data, decompressed.Bytes())
+		})
+	}
+}
+
 func TestUnSupportedBuiltinCompressors(t *testing.T) {
 	logger := logutil.NewMemoryLogger()
 
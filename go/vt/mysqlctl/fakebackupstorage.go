@@ -106,6 +106,8 @@ type FakeBackupStorage struct {
 	StartBackupReturn   FakeBackupStorageStartBackupReturn
 	WithParamsCalls     []backupstorage.Params
 	WithParamsReturn    backupstorage.BackupStorage
+	WriteFileCalls      []FakeBackupStorageWriteFileCall
+	WriteFileReturn     error
 }
 
 type FakeBackupStorageListBackupsCall struct {
@@ -130,6 +132,14 @@ type FakeBackupStorageStartBackupCall struct {
 	Name string
 }
 
+type FakeBackupStorageWriteFileCall struct {
+	Ctx      context.Context
+	Dir      string
+	Name     string
+	Filename string
+	Data     []byte
+}
+
 type FakeBackupStorageStartBackupReturn struct {
 	BackupHandle backupstorage.BackupHandle
 	Err          error
@@ -159,3 +169,8 @@ func (fbs *FakeBackupStorage) WithParams(params backupstorage.Params) backupstor
 	fbs.WithParamsCalls = append(fbs.WithParamsCalls, params)
 	return fbs.WithParamsReturn
 }
+
+func (fbs *FakeBackupStorage) WriteFile(ctx context.Context, dir, name, filename string, data []byte) error {
+	fbs.WriteFileCalls = append(fbs.WriteFileCalls, FakeBackupStorageWriteFileCall{ctx, dir, name, filename, data})
+	return fbs.WriteFileReturn
+}
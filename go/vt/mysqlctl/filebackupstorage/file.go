@@ -127,6 +127,11 @@ func (fbh *FileBackupHandle) AbortBackup(ctx context.Context) error {
 
 // ReadFile is part of the BackupHandle interface
 func (fbh *FileBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	return fbh.ReadFileAt(ctx, filename, 0)
+}
+
+// ReadFileAt is part of the backupstorage.RangeReader interface
+func (fbh *FileBackupHandle) ReadFileAt(ctx context.Context, filename string, offset int64) (io.ReadCloser, error) {
 	if !fbh.readOnly {
 		return nil, errors.New("ReadFile cannot be called on read-write backup")
 	}
@@ -138,10 +143,18 @@ func (fbh *FileBackupHandle) ReadFile(ctx context.Context, filename string) (io.
 	if err != nil {
 		return nil, err
 	}
+	if offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
 	stat := fbh.fbs.params.Stats.Scope(stats.Operation("File:Read"))
 	return ioutil.NewMeteredReadCloser(f, stat.TimedIncrementBytes), nil
 }
 
+var _ backupstorage.RangeReader = (*FileBackupHandle)(nil)
+
 // FileBackupStorage implements BackupStorage for local file system.
 type FileBackupStorage struct {
 	params backupstorage.Params
@@ -212,6 +225,20 @@ func (fbs *FileBackupStorage) RemoveBackup(ctx context.Context, dir, name string
 	return os.RemoveAll(p)
 }
 
+// WriteFile is part of the backupstorage.FileWriter interface
+func (fbs *FileBackupStorage) WriteFile(ctx context.Context, dir, name, filename string, data []byte) error {
+	p, err := fileutil.SafePathJoin(FileBackupStorageRoot, dir, name, filename)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so readers never see a partial file.
+	tmp := p + ".tmp"
+	if err := os2.WriteFile(tmp, data); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
 // Close implements BackupStorage.
 func (fbs *FileBackupStorage) Close() error {
 	return nil
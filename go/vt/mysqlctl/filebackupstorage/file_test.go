@@ -18,6 +18,8 @@ package filebackupstorage
 
 import (
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -138,3 +140,72 @@ func TestFileContents(t *testing.T) {
 	require.Truef(t, (err == nil || err == io.EOF) && n == len(contents1), "rc.Read returned wrong result: %v %#v", err, n)
 	require.NoError(t, rc.Close())
 }
+
+func TestReadFileAt(t *testing.T) {
+	fbs := setupFileBackupStorage(t)
+	ctx := t.Context()
+
+	dir := "keyspace/shard"
+	name := "cell-0001-2015-01-14-10-00-00"
+	filename := "file1"
+	contents := "contents of the first file"
+
+	bh, err := fbs.StartBackup(ctx, dir, name)
+	require.NoError(t, err)
+	wc, err := bh.AddFile(ctx, filename, 0)
+	require.NoError(t, err)
+	_, err = wc.Write([]byte(contents))
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+
+	_, err = bh.(backupstorage.RangeReader).ReadFileAt(ctx, filename, 0)
+	require.Error(t, err, "was able to ReadFileAt on read-write backup")
+	require.NoError(t, bh.EndBackup(ctx))
+
+	bhs, err := fbs.ListBackups(ctx, dir)
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+	rr, ok := bhs[0].(backupstorage.RangeReader)
+	require.True(t, ok, "FileBackupHandle should implement RangeReader")
+
+	rc, err := rr.ReadFileAt(ctx, filename, 9)
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.Equal(t, contents[9:], string(data))
+}
+
+func TestWriteFile(t *testing.T) {
+	fbs := setupFileBackupStorage(t)
+	ctx := t.Context()
+
+	dir := "keyspace/shard"
+	name := "cell-0001-2015-01-14-10-00-00"
+	fw, ok := fbs.(backupstorage.FileWriter)
+	require.True(t, ok, "FileBackupStorage should implement FileWriter")
+
+	// The backup has to exist.
+	require.Error(t, fw.WriteFile(ctx, dir, name, backupstorage.VerificationFileName, []byte("{}")))
+
+	bh, err := fbs.StartBackup(ctx, dir, name)
+	require.NoError(t, err)
+	require.NoError(t, bh.EndBackup(ctx))
+
+	// Writing again replaces the file.
+	require.NoError(t, fw.WriteFile(ctx, dir, name, backupstorage.VerificationFileName, []byte("first")))
+	require.NoError(t, fw.WriteFile(ctx, dir, name, backupstorage.VerificationFileName, []byte("second")))
+
+	bhs, err := fbs.ListBackups(ctx, dir)
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+	rc, err := bhs[0].ReadFile(ctx, backupstorage.VerificationFileName)
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.Equal(t, "second", string(data))
+
+	_, err = os.Stat(filepath.Join(FileBackupStorageRoot, dir, name, backupstorage.VerificationFileName+".tmp"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -107,11 +107,16 @@ func (bh *GCSBackupHandle) AbortBackup(ctx context.Context) error {
 
 // ReadFile implements BackupHandle.
 func (bh *GCSBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	return bh.ReadFileAt(ctx, filename, 0)
+}
+
+// ReadFileAt implements backupstorage.RangeReader.
+func (bh *GCSBackupHandle) ReadFileAt(ctx context.Context, filename string, offset int64) (io.ReadCloser, error) {
 	if !bh.readOnly {
 		return nil, errors.New("ReadFile cannot be called on read-write backup")
 	}
 	object := objName(bh.dir, bh.name, filename)
-	return bh.client.Bucket(bucket).Object(object).NewReader(ctx)
+	return bh.client.Bucket(bucket).Object(object).NewRangeReader(ctx, offset, -1)
 }
 
 // GCSBackupStorage implements BackupStorage for Google Cloud Storage.
@@ -223,6 +228,21 @@ func (bs *GCSBackupStorage) RemoveBackup(ctx context.Context, dir, name string)
 	return nil
 }
 
+// WriteFile implements backupstorage.FileWriter.
+func (bs *GCSBackupStorage) WriteFile(ctx context.Context, dir, name, filename string, data []byte) error {
+	c, err := bs.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := c.Bucket(bucket).Object(objName(dir, name, filename)).NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
 // Close implements BackupStorage.
 func (bs *GCSBackupStorage) Close() error {
 	bs.mu.Lock()
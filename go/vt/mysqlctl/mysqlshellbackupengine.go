@@ -123,6 +123,11 @@ const (
 func (be *MySQLShellBackupEngine) ExecuteBackup(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle) (result BackupResult, finalErr error) {
 	params.Logger.Infof("Starting ExecuteBackup in %s", params.TabletAlias)
 
+	// mysqlsh writes the backup to its location directly, not through the backup handle.
+	if backupstorage.GetEncryptionInfo(bh) != nil {
+		return BackupUnusable, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "%s backup engine does not support backup encryption", mysqlShellBackupEngineName)
+	}
+
 	location, err := be.backupLocation(bh.Directory(), bh.Name())
 	if err != nil {
 		return BackupUnusable, vterrors.Wrap(err, "cannot safely determine backup location")
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// BackupRetention is the result of applying a backup retention policy to the
+// backups of a shard. Both lists are sorted by backup name, like ListBackups.
+type BackupRetention struct {
+	// Kept are the backups kept by the policy.
+	Kept []backupstorage.BackupHandle
+	// Removed are the backups the policy does not keep. They are only removed
+	// from the backup storage when not in dry run mode.
+	Removed []backupstorage.BackupHandle
+}
+
+// ValidateBackupRetentionPolicy returns an error if the policy is missing, has
+// negative values, or has no rules set, which would only keep the most recent
+// full backup.
+func ValidateBackupRetentionPolicy(policy *topodatapb.BackupRetentionPolicy) error {
+	if policy == nil {
+		return vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "no backup retention policy")
+	}
+	if policy.KeepLast < 0 || policy.KeepDailyDays < 0 || policy.KeepWeeklyWeeks < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "backup retention policy values must not be negative: %v", policy)
+	}
+	if policy.KeepLast == 0 && policy.KeepDailyDays == 0 && policy.KeepWeeklyWeeks == 0 {
+		return vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "backup retention policy must set at least one of keep_last, keep_daily_days or keep_weekly_weeks")
+	}
+	return nil
+}
+
+// ApplyBackupRetention applies a retention policy to the backups of a shard,
+// as of now, and removes the backups the policy does not keep unless dryRun
+// is set. Backups whose MANIFEST cannot be read, such as backups in progress,
+// are always kept.
+func ApplyBackupRetention(ctx context.Context, logger logutil.Logger, bs backupstorage.BackupStorage, keyspace, shard string, policy *topodatapb.BackupRetentionPolicy, now time.Time, dryRun bool) (*BackupRetention, error) {
+	if err := ValidateBackupRetentionPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	backupDir := GetBackupDir(keyspace, shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+
+	// manifests has the same order as bhs, with nil for unreadable manifests.
+	manifests := make([]*BackupManifest, len(bhs))
+	for i, bh := range bhs {
+		bm, err := GetBackupManifest(ctx, bh)
+		if err != nil {
+			logger.Warningf("Keeping possibly incomplete backup %v in directory %v: can't read MANIFEST: %v", bh.Name(), backupDir, err)
+			continue
+		}
+		manifests[i] = bm
+	}
+	keep := selectBackupsToKeep(manifests, policy, now)
+
+	retention := &BackupRetention{}
+	for i, bh := range bhs {
+		if manifests[i] == nil || keep[manifests[i]] {
+			retention.Kept = append(retention.Kept, bh)
+			continue
+		}
+		retention.Removed = append(retention.Removed, bh)
+	}
+
+	if dryRun {
+		return retention, nil
+	}
+	for _, bh := range retention.Removed {
+		logger.Infof("Removing backup %v/%v as per the backup retention policy", backupDir, bh.Name())
+		if err := bs.RemoveBackup(ctx, backupDir, bh.Name()); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to remove backup %v/%v", backupDir, bh.Name())
+		}
+	}
+	return retention, nil
+}
+
+// selectBackupsToKeep returns the manifests of the backups a retention policy
+// keeps. nil manifests are ignored.
+func selectBackupsToKeep(manifests []*BackupManifest, policy *topodatapb.BackupRetentionPolicy, now time.Time) map[*BackupManifest]bool {
+	keep := make(map[*BackupManifest]bool)
+
+	type timedManifest struct {
+		manifest *BackupManifest
+		time     time.Time
+	}
+	var fulls, incrementals []timedManifest
+	for _, bm := range manifests {
+		if bm == nil {
+			continue
+		}
+		backupTime, err := ParseRFC3339(bm.BackupTime)
+		if err != nil {
+			// Without a time, the policy cannot tell how old the backup is.
+			keep[bm] = true
+			continue
+		}
+		if bm.Incremental {
+			incrementals = append(incrementals, timedManifest{bm, backupTime.UTC()})
+		} else {
+			fulls = append(fulls, timedManifest{bm, backupTime.UTC()})
+		}
+	}
+	// Most recent full backups first.
+	sort.SliceStable(fulls, func(i, j int) bool {
+		return fulls[i].time.After(fulls[j].time)
+	})
+
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	dailyCutoff := today.AddDate(0, 0, -int(policy.KeepDailyDays-1))
+	weeklyCutoff := startOfWeek(today).AddDate(0, 0, -7*int(policy.KeepWeeklyWeeks-1))
+	days := make(map[time.Time]bool)
+	weeks := make(map[time.Time]bool)
+
+	var oldestKeptFull time.Time
+	for i, full := range fulls {
+		kept := i == 0 || i < int(policy.KeepLast)
+		if policy.KeepDailyDays > 0 && !full.time.Before(dailyCutoff) {
+			day := time.Date(full.time.Year(), full.time.Month(), full.time.Day(), 0, 0, 0, 0, time.UTC)
+			if !days[day] {
+				days[day] = true
+				kept = true
+			}
+		}
+		if policy.KeepWeeklyWeeks > 0 && !full.time.Before(weeklyCutoff) {
+			week := startOfWeek(full.time)
+			if !weeks[week] {
+				weeks[week] = true
+				kept = true
+			}
+		}
+		if kept {
+			keep[full.manifest] = true
+			oldestKeptFull = full.time
+		}
+	}
+
+	// Incremental backups taken after the oldest kept full backup extend the
+	// point in time recovery window of the kept backups, and are kept along
+	// with every backup on their recovery path, even if the policy would not
+	// keep that full backup.
+	for _, incremental := range incrementals {
+		if oldestKeptFull.IsZero() || incremental.time.Before(oldestKeptFull) {
+			continue
+		}
+		keep[incremental.manifest] = true
+		if incremental.manifest.Position.IsZero() {
+			continue
+		}
+		path, err := FindPITRPath(incremental.manifest.Position.GTIDSet, manifests)
+		if err != nil {
+			continue
+		}
+		for _, bm := range path {
+			keep[bm] = true
+		}
+	}
+	return keep
+}
+
+// startOfWeek returns the start of the Monday of the week of t, in UTC.
+func startOfWeek(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
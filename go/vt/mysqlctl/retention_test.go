@@ -0,0 +1,258 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	retentionTestUUID1 = "16b1039f-22b6-11ed-b765-0a43f95f28a3"
+	retentionTestUUID2 = "0d4c2c3a-22b6-11ed-b765-0a43f95f28a3"
+)
+
+// retentionTestNow is a Wednesday.
+var retentionTestNow = time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)
+
+func retentionTestPosition(t *testing.T, gtids string) replication.Position {
+	pos, err := replication.DecodePosition("MySQL56/" + gtids)
+	require.NoError(t, err)
+	return pos
+}
+
+func fullBackupManifest(t *testing.T, name string, backupTime time.Time, gtids string) *BackupManifest {
+	return &BackupManifest{
+		BackupName:   name,
+		BackupMethod: "builtin",
+		BackupTime:   FormatRFC3339(backupTime),
+		Position:     retentionTestPosition(t, gtids),
+	}
+}
+
+func incrementalBackupManifest(t *testing.T, name string, backupTime time.Time, fromGTIDs, gtids string) *BackupManifest {
+	bm := fullBackupManifest(t, name, backupTime, gtids)
+	bm.Incremental = true
+	bm.FromPosition = retentionTestPosition(t, fromGTIDs)
+	return bm
+}
+
+func keptBackupNames(manifests []*BackupManifest, keep map[*BackupManifest]bool) []string {
+	var names []string
+	for _, bm := range manifests {
+		if keep[bm] {
+			names = append(names, bm.BackupName)
+		}
+	}
+	return names
+}
+
+func TestSelectBackupsToKeep(t *testing.T) {
+	hour := time.Hour
+	day := 24 * hour
+	uuid1 := retentionTestUUID1
+
+	// Two full backups a day for the last 20 days, oldest first.
+	var daily []*BackupManifest
+	for i := 19; i >= 0; i-- {
+		for _, h := range []time.Duration{10 * hour, 2 * hour} {
+			backupTime := retentionTestNow.Add(-time.Duration(i)*day - h)
+			daily = append(daily, fullBackupManifest(t, backupTime.Format(BackupTimestampFormat), backupTime, uuid1+":1-100"))
+		}
+	}
+
+	tests := []struct {
+		name      string
+		manifests []*BackupManifest
+		policy    *topodatapb.BackupRetentionPolicy
+		expected  []string
+	}{
+		{
+			name:      "keep last",
+			manifests: daily,
+			policy:    &topodatapb.BackupRetentionPolicy{KeepLast: 3},
+			expected:  []string{"2025-01-14.100000", "2025-01-15.020000", "2025-01-15.100000"},
+		},
+		{
+			name:      "keep daily",
+			manifests: daily,
+			policy:    &topodatapb.BackupRetentionPolicy{KeepDailyDays: 3},
+			expected:  []string{"2025-01-13.100000", "2025-01-14.100000", "2025-01-15.100000"},
+		},
+		{
+			// The weeks start on Mondays: January 13th, 6th, and December 30th.
+			name:      "keep weekly",
+			manifests: daily,
+			policy:    &topodatapb.BackupRetentionPolicy{KeepWeeklyWeeks: 3},
+			expected:  []string{"2025-01-05.100000", "2025-01-12.100000", "2025-01-15.100000"},
+		},
+		{
+			name:      "combined rules",
+			manifests: daily,
+			policy:    &topodatapb.BackupRetentionPolicy{KeepLast: 2, KeepDailyDays: 2, KeepWeeklyWeeks: 2},
+			expected:  []string{"2025-01-12.100000", "2025-01-14.100000", "2025-01-15.020000", "2025-01-15.100000"},
+		},
+		{
+			name: "most recent full backup is always kept",
+			manifests: []*BackupManifest{
+				fullBackupManifest(t, "old", retentionTestNow.Add(-100*day), uuid1+":1-10"),
+				fullBackupManifest(t, "latest", retentionTestNow.Add(-50*day), uuid1+":1-20"),
+			},
+			policy:   &topodatapb.BackupRetentionPolicy{KeepDailyDays: 7},
+			expected: []string{"latest"},
+		},
+		{
+			name: "incremental backups after the oldest kept full backup",
+			manifests: []*BackupManifest{
+				fullBackupManifest(t, "f1", retentionTestNow.Add(-4*day), uuid1+":1-10"),
+				incrementalBackupManifest(t, "i1", retentionTestNow.Add(-3*day), uuid1+":1-10", uuid1+":1-20"),
+				fullBackupManifest(t, "f2", retentionTestNow.Add(-2*day), uuid1+":1-25"),
+				incrementalBackupManifest(t, "i2", retentionTestNow.Add(-1*day), uuid1+":1-25", uuid1+":1-30"),
+				incrementalBackupManifest(t, "i3", retentionTestNow.Add(-1*hour), uuid1+":1-30", uuid1+":1-40"),
+			},
+			policy:   &topodatapb.BackupRetentionPolicy{KeepLast: 1},
+			expected: []string{"f2", "i2", "i3"},
+		},
+		{
+			// f2 has an errant GTID, so the recovery path of i2 starts at f1.
+			name: "full backup an incremental chain depends on",
+			manifests: []*BackupManifest{
+				fullBackupManifest(t, "f1", retentionTestNow.Add(-4*day), uuid1+":1-10"),
+				incrementalBackupManifest(t, "i1", retentionTestNow.Add(-3*day), uuid1+":1-10", uuid1+":1-20"),
+				fullBackupManifest(t, "f2", retentionTestNow.Add(-2*day), uuid1+":1-25,"+retentionTestUUID2+":1-3"),
+				incrementalBackupManifest(t, "i2", retentionTestNow.Add(-1*day), uuid1+":1-20", uuid1+":1-30"),
+			},
+			policy:   &topodatapb.BackupRetentionPolicy{KeepLast: 1},
+			expected: []string{"f1", "i1", "f2", "i2"},
+		},
+		{
+			name: "backups without a valid time are kept",
+			manifests: []*BackupManifest{
+				{BackupName: "no-time", BackupTime: "yesterday"},
+				fullBackupManifest(t, "f1", retentionTestNow.Add(-2*day), uuid1+":1-10"),
+				fullBackupManifest(t, "f2", retentionTestNow.Add(-1*day), uuid1+":1-20"),
+			},
+			policy:   &topodatapb.BackupRetentionPolicy{KeepLast: 1},
+			expected: []string{"no-time", "f2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep := selectBackupsToKeep(tt.manifests, tt.policy, retentionTestNow)
+			assert.Equal(t, tt.expected, keptBackupNames(tt.manifests, keep))
+		})
+	}
+}
+
+func TestValidateBackupRetentionPolicy(t *testing.T) {
+	assert.Error(t, ValidateBackupRetentionPolicy(nil))
+	assert.Error(t, ValidateBackupRetentionPolicy(&topodatapb.BackupRetentionPolicy{}))
+	assert.Error(t, ValidateBackupRetentionPolicy(&topodatapb.BackupRetentionPolicy{KeepLast: 2, KeepDailyDays: -1}))
+	assert.NoError(t, ValidateBackupRetentionPolicy(&topodatapb.BackupRetentionPolicy{KeepWeeklyWeeks: 4}))
+}
+
+func TestApplyBackupRetention(t *testing.T) {
+	ctx := context.Background()
+	manifests := map[string]*BackupManifest{
+		"2025-01-10.000000.zone1-0000000100": fullBackupManifest(t, "2025-01-10.000000.zone1-0000000100", retentionTestNow.Add(-5*24*time.Hour), retentionTestUUID1+":1-10"),
+		"2025-01-12.000000.zone1-0000000100": fullBackupManifest(t, "2025-01-12.000000.zone1-0000000100", retentionTestNow.Add(-3*24*time.Hour), retentionTestUUID1+":1-20"),
+		"2025-01-14.000000.zone1-0000000100": fullBackupManifest(t, "2025-01-14.000000.zone1-0000000100", retentionTestNow.Add(-1*24*time.Hour), retentionTestUUID1+":1-30"),
+	}
+	names := []string{
+		"2025-01-10.000000.zone1-0000000100",
+		"2025-01-12.000000.zone1-0000000100",
+		"2025-01-14.000000.zone1-0000000100",
+		// A backup in progress, without a MANIFEST yet.
+		"2025-01-15.000000.zone1-0000000100",
+	}
+	newStorage := func() *FakeBackupStorage {
+		bs := &FakeBackupStorage{}
+		for _, name := range names {
+			bs.ListBackupsReturn.BackupHandles = append(bs.ListBackupsReturn.BackupHandles, &FakeBackupHandle{
+				Dir:      "ks/-80",
+				NameV:    name,
+				ReadOnly: true,
+				ReadFileReturnF: func(ctx context.Context, filename string) (io.ReadCloser, error) {
+					bm, ok := manifests[name]
+					if !ok {
+						return nil, errors.New("MANIFEST not found")
+					}
+					data, err := json.Marshal(bm)
+					if err != nil {
+						return nil, err
+					}
+					return io.NopCloser(bytes.NewReader(data)), nil
+				},
+			})
+		}
+		return bs
+	}
+	handleNames := func(bhs []backupstorage.BackupHandle) (names []string) {
+		for _, bh := range bhs {
+			names = append(names, bh.Name())
+		}
+		return names
+	}
+	policy := &topodatapb.BackupRetentionPolicy{KeepLast: 2}
+
+	t.Run("dry run", func(t *testing.T) {
+		bs := newStorage()
+		retention, err := ApplyBackupRetention(ctx, logutil.NewMemoryLogger(), bs, "ks", "-80", policy, retentionTestNow, true)
+		require.NoError(t, err)
+		assert.Equal(t, names[1:], handleNames(retention.Kept))
+		assert.Equal(t, names[:1], handleNames(retention.Removed))
+		assert.Empty(t, bs.RemoveBackupCalls)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		bs := newStorage()
+		retention, err := ApplyBackupRetention(ctx, logutil.NewMemoryLogger(), bs, "ks", "-80", policy, retentionTestNow, false)
+		require.NoError(t, err)
+		assert.Equal(t, names[:1], handleNames(retention.Removed))
+		require.Len(t, bs.RemoveBackupCalls, 1)
+		assert.Equal(t, "ks/-80", bs.RemoveBackupCalls[0].Dir)
+		assert.Equal(t, names[0], bs.RemoveBackupCalls[0].Name)
+	})
+
+	t.Run("remove error", func(t *testing.T) {
+		bs := newStorage()
+		bs.RemoveBackupReturn = errors.New("permission denied")
+		_, err := ApplyBackupRetention(ctx, logutil.NewMemoryLogger(), bs, "ks", "-80", policy, retentionTestNow, false)
+		assert.ErrorContains(t, err, "permission denied")
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		bs := newStorage()
+		_, err := ApplyBackupRetention(ctx, logutil.NewMemoryLogger(), bs, "ks", "-80", &topodatapb.BackupRetentionPolicy{}, retentionTestNow, false)
+		assert.Error(t, err)
+		assert.Empty(t, bs.ListBackupsCalls)
+	})
+}
@@ -24,6 +24,7 @@ limitations under the License.
 package s3backupstorage
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"crypto/tls"
@@ -323,25 +324,37 @@ func (bh *S3BackupHandle) AbortBackup(ctx context.Context) error {
 
 // ReadFile is part of the backupstorage.BackupHandle interface.
 func (bh *S3BackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	return bh.ReadFileAt(ctx, filename, 0)
+}
+
+// ReadFileAt is part of the backupstorage.RangeReader interface.
+func (bh *S3BackupHandle) ReadFileAt(ctx context.Context, filename string, offset int64) (io.ReadCloser, error) {
 	if !bh.readOnly {
 		return nil, errors.New("ReadFile cannot be called on read-write backup")
 	}
 	object := objName(bh.dir, bh.name, filename)
-	sendStats := bh.bs.params.Stats.Scope(stats.Operation("AWS:Request:Send"))
-	out, err := (&timedS3Client{client: bh.s3Client, sendStats: sendStats}).GetObject(ctx, &s3.GetObjectInput{
+	input := &s3.GetObjectInput{
 		Bucket:               &bucket,
 		Key:                  &object,
 		SSECustomerAlgorithm: bh.bs.s3SSE.customerAlg,
 		SSECustomerKey:       bh.bs.s3SSE.customerKey,
 		SSECustomerKeyMD5:    bh.bs.s3SSE.customerMd5,
-	})
+	}
+	if offset > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+	}
+	sendStats := bh.bs.params.Stats.Scope(stats.Operation("AWS:Request:Send"))
+	out, err := (&timedS3Client{client: bh.s3Client, sendStats: sendStats}).GetObject(ctx, input)
 	if err != nil {
 		return nil, err
 	}
 	return out.Body, nil
 }
 
-var _ backupstorage.BackupHandle = (*S3BackupHandle)(nil)
+var (
+	_ backupstorage.BackupHandle = (*S3BackupHandle)(nil)
+	_ backupstorage.RangeReader  = (*S3BackupHandle)(nil)
+)
 
 type S3ServerSideEncryption struct {
 	awsAlg      types.ServerSideEncryption
@@ -529,6 +542,28 @@ func (bs *S3BackupStorage) RemoveBackup(ctx context.Context, dir, name string) e
 	return nil
 }
 
+// WriteFile is part of the backupstorage.FileWriter interface.
+func (bs *S3BackupStorage) WriteFile(ctx context.Context, dir, name, filename string, data []byte) error {
+	log.Info(fmt.Sprintf("WriteFile: [s3] dir: %v, name: %v, filename: %v, bucket: %v", dir, name, filename, bucket))
+	c, err := bs.client()
+	if err != nil {
+		return err
+	}
+
+	object := objName(dir, name, filename)
+	sendStats := bs.params.Stats.Scope(stats.Operation("AWS:Request:Send"))
+	_, err = (&timedS3Client{client: c, sendStats: sendStats}).PutObject(ctx, &s3.PutObjectInput{
+		Bucket:               &bucket,
+		Key:                  &object,
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: bs.s3SSE.awsAlg,
+		SSECustomerAlgorithm: bs.s3SSE.customerAlg,
+		SSECustomerKey:       bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    bs.s3SSE.customerMd5,
+	})
+	return err
+}
+
 // Close is part of the backupstorage.BackupStorage interface.
 func (bs *S3BackupStorage) Close() error {
 	bs.mu.Lock()
@@ -542,7 +577,10 @@ func (bs *S3BackupStorage) WithParams(params backupstorage.Params) backupstorage
 	return &S3BackupStorage{params: params, transport: bs.transport}
 }
 
-var _ backupstorage.BackupStorage = (*S3BackupStorage)(nil)
+var (
+	_ backupstorage.BackupStorage = (*S3BackupStorage)(nil)
+	_ backupstorage.FileWriter    = (*S3BackupStorage)(nil)
+)
 
 // getLogLevel converts the string loglevel to an aws.LogLevelType
 func getLogLevel() aws.ClientLogMode {
@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// verifyTablesQuery lists the tables VerifyBackup checks in the restored data.
+const verifyTablesQuery = "SELECT table_schema, table_name FROM information_schema.tables " +
+	"WHERE table_type = 'BASE TABLE' AND table_schema NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys') " +
+	"ORDER BY table_schema, table_name"
+
+// BackupVerification records the last successful verification of a backup.
+// It is stored as JSON in the backup, next to the MANIFEST.
+type BackupVerification struct {
+	// BackupName is the name of the verified backup.
+	BackupName string
+
+	// VerifiedTime is when the verification finished in UTC time (RFC 3339 format).
+	VerifiedTime string
+
+	// Position is the replication position of the restored data, which is
+	// the position in the backup MANIFEST.
+	Position replication.Position
+
+	// Hostname is the host the backup was restored on.
+	Hostname string
+
+	// TableChecksums are the CHECKSUM TABLE results of the restored tables,
+	// keyed by schema and table name, separated with a dot.
+	TableChecksums map[string]string
+}
+
+// VerifyBackup restores a full backup, checks the restored data, and records
+// the verification in the backup. The backup is the one named by
+// params.BackupName, or the most recent one. params.Cnf and params.Mysqld must
+// describe a throwaway mysqld, as all its data is replaced by the backup.
+//
+// The restored data is checked by comparing its replication position with
+// the one in the backup MANIFEST, and by running CHECK TABLE on all tables.
+// The CHECKSUM TABLE results of all tables are recorded with the verification.
+func VerifyBackup(ctx context.Context, params RestoreParams) (*BackupVerification, error) {
+	if params.IsIncrementalRecovery() || params.DryRun {
+		return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "only full backups can be verified, and not in dry run mode")
+	}
+
+	// Fail before restoring anything if the verification cannot be recorded.
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+	fw, ok := bs.(backupstorage.FileWriter)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "backup storage %v cannot record backup verifications", backupstorage.BackupStorageImplementation)
+	}
+
+	startTs := time.Now()
+	manifest, err := Restore(ctx, params)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to restore backup")
+	}
+	backupName := manifest.BackupName
+	if backupName == "" {
+		backupName = params.BackupName
+	}
+	if backupName == "" {
+		return nil, vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "restored backup has no name in its MANIFEST")
+	}
+
+	params.Logger.Infof("VerifyBackup: checking replication position of backup %v", backupName)
+	pos, err := params.Mysqld.PrimaryPosition(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to read the restored replication position")
+	}
+	if !pos.Equal(manifest.Position) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "restored replication position %v does not match position %v of backup %v", pos, manifest.Position, backupName)
+	}
+
+	params.Logger.Infof("VerifyBackup: checking tables of backup %v", backupName)
+	checksums, err := checkRestoredTables(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to get hostname")
+	}
+	verification := &BackupVerification{
+		BackupName:     backupName,
+		VerifiedTime:   FormatRFC3339(time.Now().UTC()),
+		Position:       pos,
+		Hostname:       hostname,
+		TableChecksums: checksums,
+	}
+	data, err := json.MarshalIndent(verification, "", "  ")
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot JSON encode backup verification")
+	}
+	if err := fw.WriteFile(ctx, GetBackupDir(params.Keyspace, params.Shard), backupName, backupstorage.VerificationFileName, data); err != nil {
+		return nil, vterrors.Wrapf(err, "failed to record verification of backup %v", backupName)
+	}
+
+	params.Logger.Infof("VerifyBackup: verified backup %v with %d tables in %v", backupName, len(checksums), time.Since(startTs).Round(time.Second))
+	return verification, nil
+}
+
+// checkRestoredTables runs CHECK TABLE on all the restored tables, and returns
+// their CHECKSUM TABLE results.
+func checkRestoredTables(ctx context.Context, params RestoreParams) (map[string]string, error) {
+	qr, err := params.Mysqld.FetchSuperQuery(ctx, verifyTablesQuery)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to list restored tables")
+	}
+
+	checksums := make(map[string]string, len(qr.Rows))
+	for _, row := range qr.Rows {
+		name := sqlescape.EscapeID(row[0].ToString()) + "." + sqlescape.EscapeID(row[1].ToString())
+		key := row[0].ToString() + "." + row[1].ToString()
+
+		check, err := params.Mysqld.FetchSuperQuery(ctx, "CHECK TABLE "+name)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to check table %v", key)
+		}
+		// CHECK TABLE returns rows of Table, Op, Msg_type and Msg_text, with
+		// a final status of OK for healthy tables.
+		for _, r := range check.Rows {
+			if len(r) < 4 {
+				continue
+			}
+			msgType, msgText := r[2].ToString(), r[3].ToString()
+			if strings.EqualFold(msgType, "error") || (strings.EqualFold(msgType, "status") && !strings.EqualFold(msgText, "OK")) {
+				return nil, vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "CHECK TABLE failed for table %v: %v", key, msgText)
+			}
+		}
+
+		checksum, err := params.Mysqld.FetchSuperQuery(ctx, "CHECKSUM TABLE "+name)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to checksum table %v", key)
+		}
+		if len(checksum.Rows) != 1 || len(checksum.Rows[0]) < 2 || checksum.Rows[0][1].IsNull() {
+			return nil, vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "CHECKSUM TABLE returned no checksum for table %v", key)
+		}
+		checksums[key] = checksum.Rows[0][1].ToString()
+	}
+	return checksums, nil
+}
+
+// GetBackupVerification returns the last successful verification of a backup.
+// It returns an error if the backup was never verified.
+func GetBackupVerification(ctx context.Context, bh backupstorage.BackupHandle) (*BackupVerification, error) {
+	rc, err := bh.ReadFile(ctx, backupstorage.VerificationFileName)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "can't read %v file", backupstorage.VerificationFileName)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "can't read %v file", backupstorage.VerificationFileName)
+	}
+	verification := &BackupVerification{}
+	if err := json.Unmarshal(data, verification); err != nil {
+		return nil, fmt.Errorf("can't decode %v file: %w", backupstorage.VerificationFileName, err)
+	}
+	return verification, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const testVerifyBackupName = "2025-01-01.000000.zone1-0000000100"
+
+// setupVerifyBackupEnv names the fake backup, and makes the fake mysqld
+// answer the queries VerifyBackup runs for a single table.
+func setupVerifyBackupEnv(t *testing.T, checkMsgType, checkMsgText string) *fakeBackupRestoreEnv {
+	env := createFakeBackupRestoreEnv(t)
+	env.backupStorage.ListBackupsReturn.BackupHandles[0].(*FakeBackupHandle).NameV = testVerifyBackupName
+	env.restoreParams.BackupName = testVerifyBackupName
+	env.mysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		verifyTablesQuery: sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("table_schema|table_name", "varchar|varchar"),
+			"vt_test|t1",
+		),
+		"CHECK TABLE `vt_test`.`t1`": sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("Table|Op|Msg_type|Msg_text", "varchar|varchar|varchar|varchar"),
+			"vt_test.t1|check|"+checkMsgType+"|"+checkMsgText,
+		),
+		"CHECKSUM TABLE `vt_test`.`t1`": sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("Table|Checksum", "varchar|int64"),
+			"vt_test.t1|1234567",
+		),
+	}
+	return env
+}
+
+func TestVerifyBackup(t *testing.T) {
+	env := setupVerifyBackupEnv(t, "status", "OK")
+
+	verification, err := VerifyBackup(env.ctx, env.restoreParams)
+	require.NoError(t, err, env.logger.Events)
+	assert.Equal(t, testVerifyBackupName, verification.BackupName)
+	assert.Equal(t, map[string]string{"vt_test.t1": "1234567"}, verification.TableChecksums)
+
+	require.Len(t, env.backupStorage.WriteFileCalls, 1)
+	call := env.backupStorage.WriteFileCalls[0]
+	assert.Equal(t, "test/-", call.Dir)
+	assert.Equal(t, testVerifyBackupName, call.Name)
+	assert.Equal(t, backupstorage.VerificationFileName, call.Filename)
+
+	recorded := &BackupVerification{}
+	require.NoError(t, json.Unmarshal(call.Data, recorded))
+	assert.Equal(t, verification, recorded)
+	_, err = ParseRFC3339(recorded.VerifiedTime)
+	assert.NoError(t, err)
+}
+
+func TestVerifyBackupFailures(t *testing.T) {
+	t.Run("unknown backup name", func(t *testing.T) {
+		env := setupVerifyBackupEnv(t, "status", "OK")
+		env.restoreParams.BackupName = "2025-01-01.000000.zone1-0000000101"
+
+		_, err := VerifyBackup(env.ctx, env.restoreParams)
+		require.Error(t, err)
+		assert.Empty(t, env.backupStorage.WriteFileCalls)
+	})
+
+	t.Run("position mismatch", func(t *testing.T) {
+		env := setupVerifyBackupEnv(t, "status", "OK")
+		pos, err := replication.DecodePosition("MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-5")
+		require.NoError(t, err)
+		env.mysqld.SetPrimaryPositionLocked(pos)
+
+		_, err = VerifyBackup(env.ctx, env.restoreParams)
+		require.Error(t, err)
+		assert.Equal(t, vtrpcpb.Code_DATA_LOSS, vterrors.Code(err))
+		assert.Empty(t, env.backupStorage.WriteFileCalls)
+	})
+
+	t.Run("check table error", func(t *testing.T) {
+		env := setupVerifyBackupEnv(t, "error", "Table is marked as crashed")
+
+		_, err := VerifyBackup(env.ctx, env.restoreParams)
+		require.ErrorContains(t, err, "Table is marked as crashed")
+		assert.Equal(t, vtrpcpb.Code_DATA_LOSS, vterrors.Code(err))
+		assert.Empty(t, env.backupStorage.WriteFileCalls)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		env := setupVerifyBackupEnv(t, "status", "OK")
+		env.restoreParams.DryRun = true
+
+		_, err := VerifyBackup(env.ctx, env.restoreParams)
+		require.Error(t, err)
+		assert.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err))
+	})
+}
+
+func TestGetBackupVerification(t *testing.T) {
+	data, err := json.Marshal(&BackupVerification{
+		BackupName:   testVerifyBackupName,
+		VerifiedTime: "2025-01-02T03:04:05Z",
+	})
+	require.NoError(t, err)
+
+	bh := &FakeBackupHandle{
+		ReadFileReturnF: func(ctx context.Context, filename string) (io.ReadCloser, error) {
+			if filename != backupstorage.VerificationFileName {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return io.NopCloser(bytes.NewReader(data)), nil
+		},
+	}
+	verification, err := GetBackupVerification(context.Background(), bh)
+	require.NoError(t, err)
+	assert.Equal(t, testVerifyBackupName, verification.BackupName)
+	assert.Equal(t, "2025-01-02T03:04:05Z", verification.VerifiedTime)
+
+	bh.ReadFileReturnF = func(ctx context.Context, filename string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader([]byte("not json"))), nil
+	}
+	_, err = GetBackupVerification(context.Background(), bh)
+	assert.Error(t, err)
+}
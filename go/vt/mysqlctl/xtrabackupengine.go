@@ -271,6 +271,7 @@ func (be *XtrabackupEngine) executeFullBackup(ctx context.Context, params Backup
 			// xtrabackup backups are always created such that they
 			// are safe to use for upgrades later on.
 			UpgradeSafe: true,
+			Encryption:  backupstorage.GetEncryptionInfo(bh),
 		},
 
 		// XtraBackup-specific fields
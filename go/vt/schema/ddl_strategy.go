@@ -24,34 +24,41 @@ import (
 	"time"
 
 	"github.com/google/shlex"
+
+	"vitess.io/vitess/go/timer"
 )
 
 var (
-	strategyParserRegexp        = regexp.MustCompile(`^([\S]+)\s+(.*)$`)
-	cutOverThresholdFlagRegexp  = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, cutOverThresholdFlag))
-	forceCutOverAfterFlagRegexp = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, forceCutOverAfterFlag))
-	retainArtifactsFlagRegexp   = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, retainArtifactsFlag))
+	strategyParserRegexp             = regexp.MustCompile(`^([\S]+)\s+(.*)$`)
+	cutOverThresholdFlagRegexp       = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, cutOverThresholdFlag))
+	forceCutOverAfterFlagRegexp      = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, forceCutOverAfterFlag))
+	cutOverWindowFlagRegexp          = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, cutOverWindowFlag))
+	retainArtifactsFlagRegexp        = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, retainArtifactsFlag))
+	atomicCutOverGroupSizeFlagRegexp = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, atomicCutOverGroupSizeFlag))
 )
 
 const (
-	declarativeFlag        = "declarative"
-	skipTopoFlag           = "skip-topo" // legacy. Kept for backwards compatibility, but unused
-	singletonFlag          = "singleton"
-	singletonContextFlag   = "singleton-context"
-	singletonTableFlag     = "singleton-table"
-	allowZeroInDateFlag    = "allow-zero-in-date"
-	postponeLaunchFlag     = "postpone-launch"
-	postponeCompletionFlag = "postpone-completion"
-	inOrderCompletionFlag  = "in-order-completion"
-	allowConcurrentFlag    = "allow-concurrent"
-	preferInstantDDL       = "prefer-instant-ddl"
-	fastRangeRotationFlag  = "fast-range-rotation"
-	cutOverThresholdFlag   = "cut-over-threshold"
-	forceCutOverAfterFlag  = "force-cut-over-after"
-	retainArtifactsFlag    = "retain-artifacts"
-	vreplicationTestSuite  = "vreplication-test-suite"
-	allowForeignKeysFlag   = "unsafe-allow-foreign-keys"
-	analyzeTableFlag       = "analyze-table"
+	declarativeFlag            = "declarative"
+	skipTopoFlag               = "skip-topo" // legacy. Kept for backwards compatibility, but unused
+	singletonFlag              = "singleton"
+	singletonContextFlag       = "singleton-context"
+	singletonTableFlag         = "singleton-table"
+	allowZeroInDateFlag        = "allow-zero-in-date"
+	postponeLaunchFlag         = "postpone-launch"
+	postponeCompletionFlag     = "postpone-completion"
+	inOrderCompletionFlag      = "in-order-completion"
+	allowConcurrentFlag        = "allow-concurrent"
+	atomicCutOverFlag          = "atomic-cut-over"
+	atomicCutOverGroupSizeFlag = "atomic-cut-over-group-size"
+	preferInstantDDL           = "prefer-instant-ddl"
+	fastRangeRotationFlag      = "fast-range-rotation"
+	cutOverThresholdFlag       = "cut-over-threshold"
+	forceCutOverAfterFlag      = "force-cut-over-after"
+	cutOverWindowFlag          = "cut-over-window"
+	retainArtifactsFlag        = "retain-artifacts"
+	vreplicationTestSuite      = "vreplication-test-suite"
+	allowForeignKeysFlag       = "unsafe-allow-foreign-keys"
+	analyzeTableFlag           = "analyze-table"
 )
 
 // DDLStrategy suggests how an ALTER TABLE should run (e.g. "direct", "online", "mysql")
@@ -119,12 +126,39 @@ func ParseDDLStrategy(strategyVariable string) (*DDLStrategySetting, error) {
 	if err != nil {
 		return nil, err
 	}
+	cutOverWindow, err := setting.CutOverWindow()
+	if err != nil {
+		return nil, err
+	}
 	switch setting.Strategy {
 	case DDLStrategyVitess, DDLStrategyOnline:
 	default:
 		if cutoverAfter != 0 {
 			return nil, fmt.Errorf("--force-cut-over-after is only valid in 'vitess' strategy. Found %v value in '%v' strategy", cutoverAfter, setting.Strategy)
 		}
+		if cutOverWindow != nil {
+			return nil, fmt.Errorf("--cut-over-window is only valid in 'vitess' strategy. Found %v value in '%v' strategy", cutOverWindow, setting.Strategy)
+		}
+		if setting.IsAtomicCutOver() {
+			return nil, fmt.Errorf("--atomic-cut-over is only valid in 'vitess' strategy. Found in '%v' strategy", setting.Strategy)
+		}
+	}
+	groupSize, err := setting.AtomicCutOverGroupSize()
+	if err != nil {
+		return nil, err
+	}
+	if groupSize != 0 && !setting.IsAtomicCutOver() {
+		return nil, fmt.Errorf("--atomic-cut-over-group-size is only valid with --atomic-cut-over")
+	}
+	if setting.IsAtomicCutOver() {
+		// Migrations in an atomic cut-over group wait for one another to be ready. In-order completion
+		// would have them wait for each other to complete, and an instant DDL completes by itself.
+		if setting.IsInOrderCompletion() {
+			return nil, fmt.Errorf("--atomic-cut-over cannot be combined with --in-order-completion")
+		}
+		if setting.IsPreferInstantDDL() {
+			return nil, fmt.Errorf("--atomic-cut-over cannot be combined with --prefer-instant-ddl")
+		}
 	}
 
 	switch setting.Strategy {
@@ -204,6 +238,11 @@ func (setting *DDLStrategySetting) IsAllowConcurrent() bool {
 	return setting.hasFlag(allowConcurrentFlag)
 }
 
+// IsAtomicCutOver checks if strategy options include --atomic-cut-over
+func (setting *DDLStrategySetting) IsAtomicCutOver() bool {
+	return setting.hasFlag(atomicCutOverFlag)
+}
+
 // IsPreferInstantDDL checks if strategy options include --prefer-instant-ddl
 func (setting *DDLStrategySetting) IsPreferInstantDDL() bool {
 	return setting.hasFlag(preferInstantDDL)
@@ -227,6 +266,15 @@ func isForceCutOverFlag(opt string) (string, bool) {
 	return submatch[1], true
 }
 
+// isCutOverWindowFlag returns true when given option denotes a `--cut-over-window=[...]` flag
+func isCutOverWindowFlag(opt string) (string, bool) {
+	submatch := cutOverWindowFlagRegexp.FindStringSubmatch(opt)
+	if len(submatch) == 0 {
+		return "", false
+	}
+	return submatch[1], true
+}
+
 // isRetainArtifactsFlag returns true when given option denotes a `--retain-artifacts=[...]` flag
 func isRetainArtifactsFlag(opt string) (string, bool) {
 	submatch := retainArtifactsFlagRegexp.FindStringSubmatch(opt)
@@ -236,6 +284,15 @@ func isRetainArtifactsFlag(opt string) (string, bool) {
 	return submatch[1], true
 }
 
+// isAtomicCutOverGroupSizeFlag returns true when given option denotes a `--atomic-cut-over-group-size=[...]` flag
+func isAtomicCutOverGroupSizeFlag(opt string) (string, bool) {
+	submatch := atomicCutOverGroupSizeFlagRegexp.FindStringSubmatch(opt)
+	if len(submatch) == 0 {
+		return "", false
+	}
+	return submatch[1], true
+}
+
 // CutOverThreshold returns a the duration threshold indicated by --cut-over-threshold
 func (setting *DDLStrategySetting) CutOverThreshold() (d time.Duration, err error) {
 	// We do some ugly manual parsing of --cut-over-threshold value
@@ -272,6 +329,57 @@ func (setting *DDLStrategySetting) ForceCutOverAfter() (d time.Duration, err err
 	return d, err
 }
 
+// CutOverWindow returns the daily window indicated by --cut-over-window, within which a migration
+// may cut-over, e.g. `--cut-over-window="02:00-04:00 UTC"`. A nil window means any time.
+func (setting *DDLStrategySetting) CutOverWindow() (w *timer.ScheduleWindow, err error) {
+	opts, _ := shlex.Split(setting.Options)
+	for _, opt := range opts {
+		if val, isCutOverWindow := isCutOverWindowFlag(opt); isCutOverWindow {
+			// value is possibly quoted
+			if s, err := strconv.Unquote(val); err == nil {
+				val = s
+			}
+			w, err = timer.ParseScheduleWindow(val)
+		}
+	}
+	return w, err
+}
+
+// AtomicCutOverGroupSize returns the number of migrations in the --atomic-cut-over group, as indicated
+// by --atomic-cut-over-group-size. It is 0 when the flag is not given.
+func (setting *DDLStrategySetting) AtomicCutOverGroupSize() (n int, err error) {
+	opts, _ := shlex.Split(setting.Options)
+	for _, opt := range opts {
+		if val, isGroupSize := isAtomicCutOverGroupSizeFlag(opt); isGroupSize {
+			// value is possibly quoted
+			if s, err := strconv.Unquote(val); err == nil {
+				val = s
+			}
+			n, err = strconv.Atoi(val)
+			if err == nil && n <= 0 {
+				err = fmt.Errorf("invalid --atomic-cut-over-group-size value: %v. Expected a positive number", val)
+			}
+		}
+	}
+	return n, err
+}
+
+// WithAtomicCutOverGroupSize returns a copy of the setting, with --atomic-cut-over-group-size
+// set to the given number of migrations unless the options already include it
+func (setting *DDLStrategySetting) WithAtomicCutOverGroupSize(n int) *DDLStrategySetting {
+	opts, _ := shlex.Split(setting.Options)
+	for _, opt := range opts {
+		if _, isGroupSize := isAtomicCutOverGroupSizeFlag(opt); isGroupSize {
+			return setting
+		}
+	}
+	options := fmt.Sprintf("--%s=%d", atomicCutOverGroupSizeFlag, n)
+	if setting.Options != "" {
+		options = setting.Options + " " + options
+	}
+	return NewDDLStrategySetting(setting.Strategy, options)
+}
+
 // RetainArtifactsDuration returns a the duration indicated by --retain-artifacts
 func (setting *DDLStrategySetting) RetainArtifactsDuration() (d time.Duration, err error) {
 	// We do some ugly manual parsing of --retain-artifacts
@@ -319,6 +427,12 @@ func (setting *DDLStrategySetting) RuntimeOptions() []string {
 		if _, ok := isRetainArtifactsFlag(opt); ok {
 			continue
 		}
+		if _, ok := isCutOverWindowFlag(opt); ok {
+			continue
+		}
+		if _, ok := isAtomicCutOverGroupSizeFlag(opt); ok {
+			continue
+		}
 		switch {
 		case isFlag(opt, declarativeFlag):
 		case isFlag(opt, skipTopoFlag): // deprecated flag, parsed for backwards compatibility
@@ -330,6 +444,7 @@ func (setting *DDLStrategySetting) RuntimeOptions() []string {
 		case isFlag(opt, postponeCompletionFlag):
 		case isFlag(opt, inOrderCompletionFlag):
 		case isFlag(opt, allowConcurrentFlag):
+		case isFlag(opt, atomicCutOverFlag):
 		case isFlag(opt, preferInstantDDL):
 		case isFlag(opt, fastRangeRotationFlag): // deprecated flag, parsed for backwards compatibility
 		case isFlag(opt, vreplicationTestSuite):
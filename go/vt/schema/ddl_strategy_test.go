@@ -191,12 +191,15 @@ func TestParseDDLStrategy(t *testing.T) {
 		isPostponeCompletion bool
 		isInOrderCompletion  bool
 		isAllowConcurrent    bool
+		isAtomicCutOver      bool
+		atomicCutOverGroup   int
 		fastOverRevertible   bool
 		fastRangeRotation    bool
 		allowForeignKeys     bool
 		analyzeTable         bool
 		cutOverThreshold     time.Duration
 		forceCutOverAfter    time.Duration
+		cutOverWindow        string
 		expireArtifacts      time.Duration
 		runtimeOptions       string
 		expectError          string
@@ -338,6 +341,78 @@ func TestParseDDLStrategy(t *testing.T) {
 			runtimeOptions:   "",
 			expectError:      "--force-cut-over-after is only valid in 'vitess' strategy",
 		},
+		{
+			strategyVariable: "vitess --cut-over-window=02:00-04:00",
+			strategy:         DDLStrategyVitess,
+			options:          "--cut-over-window=02:00-04:00",
+			runtimeOptions:   "",
+			cutOverWindow:    "02:00-04:00",
+		},
+		{
+			strategyVariable: `vitess --postpone-launch --cut-over-window="22:00-01:30 America/New_York"`,
+			strategy:         DDLStrategyVitess,
+			options:          `--postpone-launch --cut-over-window="22:00-01:30 America/New_York"`,
+			runtimeOptions:   "",
+			isPostponeLaunch: true,
+			cutOverWindow:    "22:00-01:30 America/New_York",
+		},
+		{
+			strategyVariable: "vitess --cut-over-window=02:00",
+			strategy:         DDLStrategyVitess,
+			runtimeOptions:   "",
+			expectError:      "invalid schedule window",
+		},
+		{
+			strategyVariable: "mysql --cut-over-window=02:00-04:00",
+			strategy:         DDLStrategyMySQL,
+			runtimeOptions:   "",
+			expectError:      "--cut-over-window is only valid in 'vitess' strategy",
+		},
+		{
+			strategyVariable: "vitess --atomic-cut-over",
+			strategy:         DDLStrategyVitess,
+			options:          "--atomic-cut-over",
+			runtimeOptions:   "",
+			isAtomicCutOver:  true,
+		},
+		{
+			strategyVariable: "mysql --atomic-cut-over",
+			strategy:         DDLStrategyMySQL,
+			runtimeOptions:   "",
+			expectError:      "--atomic-cut-over is only valid in 'vitess' strategy",
+		},
+		{
+			strategyVariable: "vitess --atomic-cut-over --in-order-completion",
+			strategy:         DDLStrategyVitess,
+			runtimeOptions:   "",
+			expectError:      "--atomic-cut-over cannot be combined with --in-order-completion",
+		},
+		{
+			strategyVariable: "vitess --atomic-cut-over --prefer-instant-ddl",
+			strategy:         DDLStrategyVitess,
+			runtimeOptions:   "",
+			expectError:      "--atomic-cut-over cannot be combined with --prefer-instant-ddl",
+		},
+		{
+			strategyVariable:   "vitess --atomic-cut-over --atomic-cut-over-group-size=3",
+			strategy:           DDLStrategyVitess,
+			options:            "--atomic-cut-over --atomic-cut-over-group-size=3",
+			runtimeOptions:     "",
+			isAtomicCutOver:    true,
+			atomicCutOverGroup: 3,
+		},
+		{
+			strategyVariable: "vitess --atomic-cut-over --atomic-cut-over-group-size=0",
+			strategy:         DDLStrategyVitess,
+			runtimeOptions:   "",
+			expectError:      "invalid --atomic-cut-over-group-size value",
+		},
+		{
+			strategyVariable: "vitess --atomic-cut-over-group-size=3",
+			strategy:         DDLStrategyVitess,
+			runtimeOptions:   "",
+			expectError:      "--atomic-cut-over-group-size is only valid with --atomic-cut-over",
+		},
 		{
 			strategyVariable: "vitess --retain-artifacts=4m",
 			strategy:         DDLStrategyVitess,
@@ -383,6 +458,10 @@ func TestParseDDLStrategy(t *testing.T) {
 			assert.Equal(t, ts.isPostponeCompletion, setting.IsPostponeCompletion())
 			assert.Equal(t, ts.isPostponeLaunch, setting.IsPostponeLaunch())
 			assert.Equal(t, ts.isAllowConcurrent, setting.IsAllowConcurrent())
+			assert.Equal(t, ts.isAtomicCutOver, setting.IsAtomicCutOver())
+			atomicCutOverGroup, err := setting.AtomicCutOverGroupSize()
+			assert.NoError(t, err)
+			assert.Equal(t, ts.atomicCutOverGroup, atomicCutOverGroup)
 			assert.Equal(t, ts.fastOverRevertible, setting.IsPreferInstantDDL())
 			assert.Equal(t, ts.allowForeignKeys, setting.IsAllowForeignKeysFlag())
 			assert.Equal(t, ts.analyzeTable, setting.IsAnalyzeTableFlag())
@@ -392,6 +471,9 @@ func TestParseDDLStrategy(t *testing.T) {
 			forceCutOverAfter, err := setting.ForceCutOverAfter()
 			assert.NoError(t, err)
 			assert.Equal(t, ts.forceCutOverAfter, forceCutOverAfter)
+			cutOverWindow, err := setting.CutOverWindow()
+			assert.NoError(t, err)
+			assert.Equal(t, ts.cutOverWindow, cutOverWindow.String())
 
 			runtimeOptions := strings.Join(setting.RuntimeOptions(), " ")
 			assert.Equal(t, ts.runtimeOptions, runtimeOptions)
@@ -414,3 +496,13 @@ func TestParseDDLStrategy(t *testing.T) {
 		assert.Error(t, err)
 	}
 }
+
+func TestWithAtomicCutOverGroupSize(t *testing.T) {
+	setting := NewDDLStrategySetting(DDLStrategyVitess, "--atomic-cut-over")
+	withSize := setting.WithAtomicCutOverGroupSize(3)
+	assert.Equal(t, "--atomic-cut-over --atomic-cut-over-group-size=3", withSize.Options)
+	assert.Equal(t, "--atomic-cut-over", setting.Options)
+
+	// an explicit group size is kept
+	assert.Equal(t, withSize, withSize.WithAtomicCutOverGroupSize(5))
+}
@@ -196,6 +196,20 @@ func (exec *TabletExecutor) isOnlineSchemaDDL(stmt sqlparser.Statement) (isOnlin
 	return false
 }
 
+// countOnlineDDLs returns the number of given queries that run as online DDL migrations
+func (exec *TabletExecutor) countOnlineDDLs(sqls []string) (count int, err error) {
+	for _, sql := range sqls {
+		stmt, err := exec.parser.Parse(sql)
+		if err != nil {
+			return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "failed to parse sql: %s, got error: %v", sql, err)
+		}
+		if exec.isOnlineSchemaDDL(stmt) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func validateThrottleParams(alterMigrationType sqlparser.AlterMigrationType, expireString string, ratioLiteral *sqlparser.Literal) (duration time.Duration, ratio float64, err error) {
 	switch alterMigrationType {
 	case sqlparser.UnthrottleMigrationType,
@@ -479,6 +493,15 @@ func (exec *TabletExecutor) Execute(ctx context.Context, sqls []string) *Execute
 
 		sqls = batchSQLs(sqls, int(exec.batchSize))
 	}
+	if exec.ddlStrategySetting != nil && exec.ddlStrategySetting.IsAtomicCutOver() {
+		// The migrations of a single ApplySchema form an --atomic-cut-over group. Each of them records the
+		// size of the group, so that the tablets do not cut-over any of them before all of them are submitted.
+		groupSize, err := exec.countOnlineDDLs(sqls)
+		if err != nil {
+			return errorExecResult(err)
+		}
+		exec.ddlStrategySetting = exec.ddlStrategySetting.WithAtomicCutOverGroupSize(groupSize)
+	}
 	for index, sql := range sqls {
 		// Attempt to renew lease:
 		if err := rl.Do(func() error { return topo.CheckKeyspaceLocked(ctx, exec.keyspace) }); err != nil {
@@ -271,6 +271,28 @@ func TestIsOnlineSchemaDDL(t *testing.T) {
 	}
 }
 
+func TestCountOnlineDDLs(t *testing.T) {
+	sqls := []string{
+		"ALTER TABLE t1 ADD COLUMN i INT",
+		"ALTER TABLE t2 ADD COLUMN i INT",
+		"INSERT INTO t3 VALUES (1)",
+		"ALTER TABLE t4 ADD COLUMN i INT",
+	}
+	e := &TabletExecutor{parser: sqlparser.NewTestParser()}
+	require.NoError(t, e.SetDDLStrategy("vitess --atomic-cut-over"))
+	count, err := e.countOnlineDDLs(sqls)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	require.NoError(t, e.SetDDLStrategy("direct"))
+	count, err = e.countOnlineDDLs(sqls)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	_, err = e.countOnlineDDLs([]string{"ALTER TABLE"})
+	assert.ErrorContains(t, err, "failed to parse sql")
+}
+
 func TestBatchSQLs(t *testing.T) {
 	sqls := []string{
 		"create table t1(id int primary key)",
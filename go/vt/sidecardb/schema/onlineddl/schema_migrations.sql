@@ -79,6 +79,7 @@ CREATE TABLE IF NOT EXISTS schema_migrations
     `force_cutover`                     tinyint unsigned NOT NULL DEFAULT '0',
     `cutover_threshold_seconds`         int unsigned     NOT NULL DEFAULT '0',
     `in_order_completion_pending_count` int unsigned     NOT NULL DEFAULT '0',
+    `cutover_window`                    varchar(128)     NOT NULL DEFAULT '',
     PRIMARY KEY (`id`),
     UNIQUE KEY `uuid_idx` (`migration_uuid`),
     KEY `keyspace_shard_idx` (`keyspace`(64), `shard`(64)),
@@ -1901,7 +1901,7 @@ func (node *WindowSpecification) Format(buf *TrackedBuffer) {
 		buf.astPrintf(node, "%v", node.Name)
 		hasContent = true
 	}
-	if node.PartitionClause != nil {
+	if len(node.PartitionClause) > 0 {
 		if hasContent {
 			buf.astPrintf(node, " partition by %n", node.PartitionClause)
 		} else {
@@ -2493,7 +2493,7 @@ func (node *WindowSpecification) FormatFast(buf *TrackedBuffer) {
 		node.Name.FormatFast(buf)
 		hasContent = true
 	}
-	if node.PartitionClause != nil {
+	if len(node.PartitionClause) > 0 {
 		if hasContent {
 			buf.WriteString(" partition by ")
 			buf.formatExprs(node.PartitionClause)
@@ -3977,6 +3977,10 @@ func (cached *QueryHints) CachedSize(alloc bool) int64 {
 	if cached.Timeout != nil {
 		size += hack.RuntimeAllocSize(int64(8))
 	}
+	// field ResultCacheTTL *int
+	if cached.ResultCacheTTL != nil {
+		size += hack.RuntimeAllocSize(int64(8))
+	}
 	return size
 }
 
@@ -62,6 +62,11 @@ const (
 	// DirectivePriority specifies the priority of a workload. It should be an integer between 0 and MaxPriorityValue,
 	// where 0 is the highest priority, and MaxPriorityValue is the lowest one.
 	DirectivePriority = "PRIORITY"
+	// DirectiveResultCacheTTL lets vtgate cache the result of a SELECT query for the given number of milliseconds.
+	// A value of zero disables the result cache for the query, even if it is enabled for the tables in the vschema.
+	// The cache is best-effort and local to each vtgate: the result can be stale after writes that don't go through
+	// the same vtgate, for up to --result-cache-max-ttl.
+	DirectiveResultCacheTTL = "RESULT_CACHE_TTL_MS"
 
 	// MaxPriorityValue specifies the maximum value allowed for the priority query directive. Valid priority values are
 	// between zero and MaxPriorityValue.
@@ -596,6 +601,7 @@ type QueryHints struct {
 	ForeignKeyChecks    *bool
 	Priority            string
 	Timeout             *int
+	ResultCacheTTL      *int
 }
 
 func BuildQueryHints(stmt Statement) (qh QueryHints, err error) {
@@ -617,6 +623,7 @@ func BuildQueryHints(stmt Statement) (qh QueryHints, err error) {
 	qh.Workload = getWorkload(directives)
 	qh.ForeignKeyChecks = getForeignKeyChecksState(comment)
 	qh.Timeout = getQueryTimeout(directives)
+	qh.ResultCacheTTL = getResultCacheTTL(stmt, directives)
 
 	return qh, nil
 }
@@ -689,3 +696,20 @@ func getQueryTimeout(directives *CommentDirectives) *int {
 	}
 	return &timeout
 }
+
+// getResultCacheTTL gets the result cache TTL from the provided Statement, using DirectiveResultCacheTTL
+func getResultCacheTTL(stmt Statement, directives *CommentDirectives) *int {
+	if _, isSelect := stmt.(SelectStatement); !isSelect {
+		return nil
+	}
+	ttlString, ok := directives.GetString(DirectiveResultCacheTTL, "")
+	if !ok || ttlString == "" {
+		return nil
+	}
+
+	ttl, err := strconv.Atoi(ttlString)
+	if err != nil || ttl < 0 {
+		return nil
+	}
+	return &ttl
+}
@@ -709,3 +709,41 @@ func TestQueryTimeout(t *testing.T) {
 		})
 	}
 }
+
+// TestResultCacheTTL tests the extraction of RESULT_CACHE_TTL_MS from the comments.
+func TestResultCacheTTL(t *testing.T) {
+	testCases := []struct {
+		query  string
+		expTTL int
+		noTTL  bool
+	}{{
+		query: "select * from a_table",
+		noTTL: true,
+	}, {
+		query:  "select /*vt+ RESULT_CACHE_TTL_MS=5000 */ * from another_table",
+		expTTL: 5000,
+	}, {
+		query:  "select /*vt+ RESULT_CACHE_TTL_MS=0 */ * from another_table union select * from a_table",
+		expTTL: 0,
+	}, {
+		query: "select /*vt+ RESULT_CACHE_TTL_MS=-1 */ * from another_table",
+		noTTL: true,
+	}, {
+		query: "update /*vt+ RESULT_CACHE_TTL_MS=5000 */ a_table set a = 1",
+		noTTL: true,
+	}}
+
+	parser := NewTestParser()
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tc.query)
+			assert.NoError(t, err)
+			qh, _ := BuildQueryHints(stmt)
+			if tc.noTTL {
+				assert.Nil(t, qh.ResultCacheTTL)
+			} else {
+				assert.Equal(t, tc.expTTL, *qh.ResultCacheTTL)
+			}
+		})
+	}
+}
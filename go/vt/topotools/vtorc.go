@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"context"
+	"errors"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
+)
+
+// SetVtorcRecoveryMaintenance sets the VTOrc recovery maintenance of a keyspace, of
+// one of its shards if shard is set, or of one of the tablets of the shard if
+// tabletAlias is also set. A nil maintenance clears it.
+//
+// The caller must hold the keyspace lock.
+func SetVtorcRecoveryMaintenance(ctx context.Context, ts *topo.Server, keyspace, shard, tabletAlias string, maintenance *vtorcdatapb.RecoveryMaintenance) error {
+	if shard == "" {
+		if tabletAlias != "" {
+			return errors.New("a shard is required to set the recovery maintenance of a tablet")
+		}
+		ki, err := ts.GetKeyspace(ctx, keyspace)
+		if err != nil {
+			return err
+		}
+		if ki.VtorcState == nil {
+			if maintenance == nil {
+				return nil
+			}
+			ki.VtorcState = &vtorcdatapb.Keyspace{}
+		}
+		ki.VtorcState.RecoveryMaintenance = maintenance
+		return ts.UpdateKeyspace(ctx, ki)
+	}
+
+	_, err := ts.UpdateShardFields(ctx, keyspace, shard, func(si *topo.ShardInfo) error {
+		if si.VtorcState == nil {
+			if maintenance == nil {
+				return topo.NewError(topo.NoUpdateNeeded, si.ShardName())
+			}
+			si.VtorcState = &vtorcdatapb.Shard{}
+		}
+		switch {
+		case tabletAlias == "":
+			si.VtorcState.RecoveryMaintenance = maintenance
+		case maintenance == nil:
+			delete(si.VtorcState.TabletRecoveryMaintenance, tabletAlias)
+		default:
+			if si.VtorcState.TabletRecoveryMaintenance == nil {
+				si.VtorcState.TabletRecoveryMaintenance = make(map[string]*vtorcdatapb.RecoveryMaintenance)
+			}
+			si.VtorcState.TabletRecoveryMaintenance[tabletAlias] = maintenance
+		}
+		return nil
+	})
+	return err
+}
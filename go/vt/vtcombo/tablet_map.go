@@ -1064,6 +1064,10 @@ func (itmc *internalTabletManagerClient) RestoreFromBackup(context.Context, *top
 	return nil, errors.New("not implemented in vtcombo")
 }
 
+func (itmc *internalTabletManagerClient) VerifyBackup(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.VerifyBackupRequest) (logutil.EventStream, error) {
+	return nil, errors.New("not implemented in vtcombo")
+}
+
 func (itmc *internalTabletManagerClient) CheckThrottler(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
 	return nil, errors.New("not implemented in vtcombo")
 }
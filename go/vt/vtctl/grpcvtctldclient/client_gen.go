@@ -47,6 +47,15 @@ func (client *gRPCVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldata
 	return client.c.AddCellsAlias(ctx, in, opts...)
 }
 
+// ApplyBackupRetention is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ApplyBackupRetention(ctx context.Context, in *vtctldatapb.ApplyBackupRetentionRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyBackupRetentionResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ApplyBackupRetention(ctx, in, opts...)
+}
+
 // ApplyKeyspaceRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ApplyKeyspaceRoutingRules(ctx context.Context, in *vtctldatapb.ApplyKeyspaceRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyKeyspaceRoutingRulesResponse, error) {
 	if client.c == nil {
@@ -632,6 +641,15 @@ func (client *gRPCVtctldClient) LookupVindexInternalize(ctx context.Context, in
 	return client.c.LookupVindexInternalize(ctx, in, opts...)
 }
 
+// LookupVindexVerify is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) LookupVindexVerify(ctx context.Context, in *vtctldatapb.LookupVindexVerifyRequest, opts ...grpc.CallOption) (*vtctldatapb.LookupVindexVerifyResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.LookupVindexVerify(ctx, in, opts...)
+}
+
 // MaterializeCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) MaterializeCreate(ctx context.Context, in *vtctldatapb.MaterializeCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.MaterializeCreateResponse, error) {
 	if client.c == nil {
@@ -857,6 +875,15 @@ func (client *gRPCVtctldClient) RunHealthCheck(ctx context.Context, in *vtctldat
 	return client.c.RunHealthCheck(ctx, in, opts...)
 }
 
+// SetKeyspaceBackupRetentionPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SetKeyspaceBackupRetentionPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SetKeyspaceBackupRetentionPolicy(ctx, in, opts...)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	if client.c == nil {
@@ -893,6 +920,15 @@ func (client *gRPCVtctldClient) SetVtorcEmergencyReparent(ctx context.Context, i
 	return client.c.SetVtorcEmergencyReparent(ctx, in, opts...)
 }
 
+// SetVtorcRecoveryMaintenance is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SetVtorcRecoveryMaintenance(ctx context.Context, in *vtctldatapb.SetVtorcRecoveryMaintenanceRequest, opts ...grpc.CallOption) (*vtctldatapb.SetVtorcRecoveryMaintenanceResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SetVtorcRecoveryMaintenance(ctx, in, opts...)
+}
+
 // SetWritable is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) SetWritable(ctx context.Context, in *vtctldatapb.SetWritableRequest, opts ...grpc.CallOption) (*vtctldatapb.SetWritableResponse, error) {
 	if client.c == nil {
@@ -1136,6 +1172,15 @@ func (client *gRPCVtctldClient) ValidateVersionShard(ctx context.Context, in *vt
 	return client.c.ValidateVersionShard(ctx, in, opts...)
 }
 
+// VerifyBackup is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) VerifyBackup(ctx context.Context, in *vtctldatapb.VerifyBackupRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_VerifyBackupClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.VerifyBackup(ctx, in, opts...)
+}
+
 // WorkflowAddTables is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) WorkflowAddTables(ctx context.Context, in *vtctldatapb.WorkflowAddTablesRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowAddTablesResponse, error) {
 	if client.c == nil {
@@ -151,6 +151,7 @@ func rowToSchemaMigration(row sqltypes.RowNamedValues) (sm *vtctldatapb.SchemaMi
 	sm.UserThrottleRatio = float32(row.AsFloat64("user_throttle_ratio", 0))
 	sm.SpecialPlan = row.AsString("special_plan", "")
 	sm.InOrderCompletionPendingCount = row.AsUint64("in_order_completion_pending_count", 0)
+	sm.CutoverWindow = row.AsString("cutover_window", "")
 
 	sm.LastThrottledAt, err = valueToVTTime(row.AsString("last_throttled_timestamp", ""))
 	if err != nil {
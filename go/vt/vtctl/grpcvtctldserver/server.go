@@ -174,6 +174,74 @@ func (s *VtctldServer) AddCellsAlias(ctx context.Context, req *vtctldatapb.AddCe
 	return &vtctldatapb.AddCellsAliasResponse{}, nil
 }
 
+// ApplyBackupRetention is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ApplyBackupRetention(ctx context.Context, req *vtctldatapb.ApplyBackupRetentionRequest) (resp *vtctldatapb.ApplyBackupRetentionResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyBackupRetention")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("dry_run", req.DryRun)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if ki.BackupRetentionPolicy == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "keyspace %v has no backup retention policy", req.Keyspace)
+		return nil, err
+	}
+
+	shards := []string{req.Shard}
+	if req.Shard == "" {
+		shards, err = s.ts.GetShardNames(ctx, req.Keyspace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !req.DryRun {
+		// Serialize concurrent runs, e.g. from several vtctlds, so they do
+		// not try to remove the same backups.
+		var unlock func(*error)
+		ctx, unlock, err = s.ts.LockName(ctx, "backup_retention_"+req.Keyspace, "ApplyBackupRetention")
+		if err != nil {
+			return nil, err
+		}
+		defer unlock(&err)
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	now := time.Now()
+	resp = &vtctldatapb.ApplyBackupRetentionResponse{}
+	for _, shard := range shards {
+		var retention *mysqlctl.BackupRetention
+		retention, err = mysqlctl.ApplyBackupRetention(ctx, logutil.NewConsoleLogger(), bs, req.Keyspace, shard, ki.BackupRetentionPolicy, now, req.DryRun)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to apply backup retention to %v/%v", req.Keyspace, shard)
+		}
+		for _, bh := range retention.Removed {
+			bi := mysqlctlproto.BackupHandleToProto(bh)
+			bi.Keyspace, bi.Shard = req.Keyspace, shard
+			resp.RemovedBackups = append(resp.RemovedBackups, bi)
+		}
+		for _, bh := range retention.Kept {
+			bi := mysqlctlproto.BackupHandleToProto(bh)
+			bi.Keyspace, bi.Shard = req.Keyspace, shard
+			resp.KeptBackups = append(resp.KeptBackups, bi)
+		}
+	}
+
+	return resp, nil
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplyRoutingRules(ctx context.Context, req *vtctldatapb.ApplyRoutingRulesRequest) (resp *vtctldatapb.ApplyRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyRoutingRules")
@@ -1565,9 +1633,16 @@ func (s *VtctldServer) GetBackups(ctx context.Context, req *vtctldatapb.GetBacku
 		bi.Shard = req.Shard
 
 		if req.Detailed {
-			if i >= backupsToSkipDetails { //nolint:staticcheck
+			if i >= backupsToSkipDetails {
 				// (TODO:@ajm188) Update backupengine/backupstorage implementations
 				// to get Status info for backups.
+
+				// Backups without a readable verification were never verified.
+				if verification, err := mysqlctl.GetBackupVerification(ctx, bh); err == nil {
+					if verifiedTime, err := mysqlctl.ParseRFC3339(verification.VerifiedTime); err == nil {
+						bi.VerifiedTime = protoutil.TimeToProto(verifiedTime)
+					}
+				}
 			}
 		}
 
@@ -3113,6 +3188,23 @@ func (s *VtctldServer) LookupVindexInternalize(ctx context.Context, req *vtctlda
 	return resp, err
 }
 
+// LookupVindexVerify is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) LookupVindexVerify(ctx context.Context, req *vtctldatapb.LookupVindexVerifyRequest) (resp *vtctldatapb.LookupVindexVerifyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.LookupVindexVerify")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("name", req.Name)
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table_keyspace", req.TableKeyspace)
+	span.Annotate("uuid", req.Uuid)
+	span.Annotate("repair", req.Repair)
+
+	resp, err = s.ws.LookupVindexVerify(ctx, req)
+	return resp, err
+}
+
 // MaterializeCreate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) MaterializeCreate(ctx context.Context, req *vtctldatapb.MaterializeCreateRequest) (resp *vtctldatapb.MaterializeCreateResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.MaterializeCreate")
@@ -3871,6 +3963,47 @@ func (s *VtctldServer) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunH
 	return &vtctldatapb.RunHealthCheckResponse{}, nil
 }
 
+// SetKeyspaceBackupRetentionPolicy is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetKeyspaceBackupRetentionPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest) (resp *vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceBackupRetentionPolicy")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("backup_retention_policy", req.BackupRetentionPolicy.String())
+
+	if req.BackupRetentionPolicy != nil {
+		if err = mysqlctl.ValidateBackupRetentionPolicy(req.BackupRetentionPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "SetKeyspaceBackupRetentionPolicy")
+	if lockErr != nil {
+		err = lockErr
+		return nil, err
+	}
+
+	defer unlock(&err)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	ki.BackupRetentionPolicy = req.BackupRetentionPolicy
+
+	err = s.ts.UpdateKeyspace(ctx, ki)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+		Keyspace: ki.Keyspace,
+	}, nil
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceDurabilityPolicyRequest) (resp *vtctldatapb.SetKeyspaceDurabilityPolicyResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceDurabilityPolicy")
@@ -4058,6 +4191,66 @@ func (s *VtctldServer) SetVtorcEmergencyReparent(ctx context.Context, req *vtctl
 	return &vtctldatapb.SetVtorcEmergencyReparentResponse{}, nil
 }
 
+// SetVtorcRecoveryMaintenance is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetVtorcRecoveryMaintenance(ctx context.Context, req *vtctldatapb.SetVtorcRecoveryMaintenanceRequest) (resp *vtctldatapb.SetVtorcRecoveryMaintenanceResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetVtorcRecoveryMaintenance")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+	span.Annotate("clear", req.Clear)
+
+	keyspace, shard := req.Keyspace, req.Shard
+	var tabletAlias string
+	if req.TabletAlias != nil {
+		tablet, err := s.ts.GetTablet(ctx, req.TabletAlias)
+		if err != nil {
+			return nil, err
+		}
+		keyspace, shard = tablet.Keyspace, tablet.Shard
+		tabletAlias = topoproto.TabletAliasString(req.TabletAlias)
+	}
+	if keyspace == "" {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "keyspace or tablet alias is required")
+		return nil, err
+	}
+
+	var maintenance *vtorcdatapb.RecoveryMaintenance
+	if !req.Clear {
+		duration, ok, err := protoutil.DurationFromProto(req.Duration)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || duration <= 0 {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a positive duration is required to set a recovery maintenance")
+			return nil, err
+		}
+		maintenance = &vtorcdatapb.RecoveryMaintenance{
+			Reason:     req.Reason,
+			ExpireTime: protoutil.TimeToProto(time.Now().Add(duration)),
+		}
+	}
+
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, keyspace, "SetVtorcRecoveryMaintenance")
+	if lockErr != nil {
+		err = lockErr
+		return nil, err
+	}
+
+	defer unlock(&err)
+
+	if err = topotools.SetVtorcRecoveryMaintenance(ctx, s.ts, keyspace, shard, tabletAlias, maintenance); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SetVtorcRecoveryMaintenanceResponse{
+		RecoveryMaintenance: maintenance,
+	}, nil
+}
+
 // SetWritable is part of the vtctldservicepb.VtctldServer interface.
 func (s *VtctldServer) SetWritable(ctx context.Context, req *vtctldatapb.SetWritableRequest) (resp *vtctldatapb.SetWritableResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetWritable")
@@ -5530,6 +5723,59 @@ func (s *VtctldServer) ValidateVSchema(ctx context.Context, req *vtctldatapb.Val
 	return resp, err
 }
 
+// VerifyBackup is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) VerifyBackup(req *vtctldatapb.VerifyBackupRequest, stream vtctlservicepb.Vtctld_VerifyBackupServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.VerifyBackup")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+	span.Annotate("backup_name", req.BackupName)
+	span.Annotate("concurrency", req.Concurrency)
+
+	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
+	if err != nil {
+		return err
+	}
+
+	span.Annotate("keyspace", ti.Keyspace)
+	span.Annotate("shard", ti.Shard)
+
+	r := &tabletmanagerdatapb.VerifyBackupRequest{
+		BackupName:  req.BackupName,
+		Concurrency: req.Concurrency,
+	}
+	logStream, err := s.tmc.VerifyBackup(ctx, ti.Tablet, r)
+	if err != nil {
+		return err
+	}
+
+	logger := logutil.NewConsoleLogger()
+
+	for {
+		var event *logutilpb.Event
+		event, err = logStream.Recv()
+		switch err {
+		case nil:
+			logutil.LogEvent(logger, event)
+			resp := &vtctldatapb.VerifyBackupResponse{
+				TabletAlias: req.TabletAlias,
+				Keyspace:    ti.Keyspace,
+				Shard:       ti.Shard,
+				Event:       event,
+			}
+			if err = stream.Send(resp); err != nil {
+				logger.Errorf("failed to send stream response %+v: %v", resp, err)
+			}
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
 // VDiffCreate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRequest) (resp *vtctldatapb.VDiffCreateResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.VDiffCreate")
@@ -69,6 +69,7 @@ import (
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
 )
 
 func init() {
@@ -239,6 +240,106 @@ func TestAddCellsAlias(t *testing.T) {
 	}
 }
 
+func TestApplyBackupRetention(t *testing.T) {
+	ctx := t.Context()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddKeyspaces(ctx, t, ts,
+		&vtctldatapb.Keyspace{
+			Name: "testkeyspace",
+			Keyspace: &topodatapb.Keyspace{
+				BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepLast: 2},
+			},
+		},
+		&vtctldatapb.Keyspace{
+			Name:     "nopolicy",
+			Keyspace: &topodatapb.Keyspace{},
+		},
+	)
+	testutil.AddShards(ctx, t, ts,
+		&vtctldatapb.Shard{Keyspace: "testkeyspace", Name: "-80"},
+		&vtctldatapb.Shard{Keyspace: "testkeyspace", Name: "80-"},
+	)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	manifest := func(name, backupTime, gtids string) []byte {
+		return fmt.Appendf(nil, `{"BackupName":%q,"BackupMethod":"builtin","BackupTime":%q,"Position":"MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:%s"}`, name, backupTime, gtids)
+	}
+	setup := func() {
+		testutil.BackupStorage.Backups = map[string][]string{
+			"testkeyspace/-80": {"backup1", "backup2", "backup3"},
+			"testkeyspace/80-": {"backup1", "backup2"},
+		}
+		testutil.BackupStorage.Manifests = map[string][]byte{
+			"testkeyspace/-80/backup1": manifest("backup1", "2025-01-01T00:00:00Z", "1-10"),
+			"testkeyspace/-80/backup2": manifest("backup2", "2025-01-02T00:00:00Z", "1-20"),
+			"testkeyspace/-80/backup3": manifest("backup3", "2025-01-03T00:00:00Z", "1-30"),
+			"testkeyspace/80-/backup1": manifest("backup1", "2025-01-01T00:00:00Z", "1-10"),
+			"testkeyspace/80-/backup2": manifest("backup2", "2025-01-02T00:00:00Z", "1-20"),
+		}
+	}
+	defer func() { testutil.BackupStorage.Manifests = nil }()
+
+	backupNames := func(backups []*mysqlctlpb.BackupInfo) (names []string) {
+		for _, bi := range backups {
+			names = append(names, bi.Keyspace+"/"+bi.Shard+"/"+bi.Name)
+		}
+		return names
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		setup()
+		resp, err := vtctld.ApplyBackupRetention(ctx, &vtctldatapb.ApplyBackupRetentionRequest{
+			Keyspace: "testkeyspace",
+			DryRun:   true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"testkeyspace/-80/backup1"}, backupNames(resp.RemovedBackups))
+		assert.Equal(t, []string{"testkeyspace/-80/backup2", "testkeyspace/-80/backup3", "testkeyspace/80-/backup1", "testkeyspace/80-/backup2"}, backupNames(resp.KeptBackups))
+		assert.Len(t, testutil.BackupStorage.Backups["testkeyspace/-80"], 3, "dry run must not remove backups")
+	})
+
+	t.Run("single shard", func(t *testing.T) {
+		setup()
+		resp, err := vtctld.ApplyBackupRetention(ctx, &vtctldatapb.ApplyBackupRetentionRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "80-",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.RemovedBackups)
+		assert.Equal(t, []string{"testkeyspace/80-/backup1", "testkeyspace/80-/backup2"}, backupNames(resp.KeptBackups))
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		setup()
+		resp, err := vtctld.ApplyBackupRetention(ctx, &vtctldatapb.ApplyBackupRetentionRequest{
+			Keyspace: "testkeyspace",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"testkeyspace/-80/backup1"}, backupNames(resp.RemovedBackups))
+		assert.Equal(t, []string{"backup2", "backup3"}, testutil.BackupStorage.Backups["testkeyspace/-80"])
+		assert.Equal(t, []string{"backup1", "backup2"}, testutil.BackupStorage.Backups["testkeyspace/80-"])
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		setup()
+		_, err := vtctld.ApplyBackupRetention(ctx, &vtctldatapb.ApplyBackupRetentionRequest{
+			Keyspace: "nopolicy",
+		})
+		require.Error(t, err)
+		assert.Equal(t, vtrpc.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	})
+
+	t.Run("keyspace not found", func(t *testing.T) {
+		setup()
+		_, err := vtctld.ApplyBackupRetention(ctx, &vtctldatapb.ApplyBackupRetentionRequest{
+			Keyspace: "notfound",
+		})
+		assert.Error(t, err)
+	})
+}
+
 func TestApplyRoutingRules(t *testing.T) {
 	t.Parallel()
 
@@ -5852,6 +5953,31 @@ func TestGetBackups(t *testing.T) {
 		assert.Less(t, len(limited.Backups), len(unlimited.Backups), "expected limited backups to be less than unlimited")
 		utils.MustMatch(t, limited.Backups[0], unlimited.Backups[len(unlimited.Backups)-1], "expected limiting to keep N most recent")
 	})
+
+	t.Run("detailed verification", func(t *testing.T) {
+		testutil.BackupStorage.Verifications = map[string][]byte{
+			"testkeyspace/-/backup2": []byte(`{"BackupName":"backup2","VerifiedTime":"2025-01-02T03:04:05Z"}`),
+		}
+		defer func() { testutil.BackupStorage.Verifications = nil }()
+
+		resp, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Detailed: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Backups, 2)
+		assert.Nil(t, resp.Backups[0].VerifiedTime, "backup1 was never verified")
+		utils.MustMatch(t, protoutil.TimeToProto(time.Date(2025, time.January, 2, 3, 4, 5, 0, time.UTC)), resp.Backups[1].VerifiedTime)
+
+		// Without details, verifications are not read.
+		resp, err = vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		})
+		require.NoError(t, err)
+		assert.Nil(t, resp.Backups[1].VerifiedTime)
+	})
 }
 
 func TestGetKeyspace(t *testing.T) {
@@ -11367,6 +11493,103 @@ func TestRunHealthCheck(t *testing.T) {
 	}
 }
 
+func TestSetKeyspaceBackupRetentionPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		keyspaces   []*vtctldatapb.Keyspace
+		req         *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest
+		expected    *vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse
+		expectedErr string
+	}{
+		{
+			name: "ok",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace:              "ks1",
+				BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepLast: 3, KeepDailyDays: 7},
+			},
+			expected: &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{
+					BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepLast: 3, KeepDailyDays: 7},
+				},
+			},
+		},
+		{
+			name: "clear",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name: "ks1",
+					Keyspace: &topodatapb.Keyspace{
+						BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepLast: 3},
+					},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace: "ks1",
+			},
+			expected: &vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{},
+			},
+		},
+		{
+			name: "keyspace not found",
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace:              "ks1",
+				BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepLast: 3},
+			},
+			expectedErr: "node doesn't exist: keyspaces/ks1",
+		},
+		{
+			name: "invalid policy",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest{
+				Keyspace:              "ks1",
+				BackupRetentionPolicy: &topodatapb.BackupRetentionPolicy{KeepLast: -1},
+			},
+			expectedErr: "backup retention policy values must not be negative: keep_last:-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddKeyspaces(ctx, t, ts, tt.keyspaces...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.SetKeyspaceBackupRetentionPolicy(ctx, tt.req)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+
+			ki, err := ts.GetKeyspace(ctx, tt.req.Keyspace)
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected.Keyspace, ki.Keyspace)
+		})
+	}
+}
+
 func TestSetKeyspaceDurabilityPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -11791,6 +12014,143 @@ func TestSetShardTabletControl(t *testing.T) {
 	}
 }
 
+func TestSetVtorcRecoveryMaintenance(t *testing.T) {
+	t.Parallel()
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		},
+		Keyspace: "testkeyspace",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_REPLICA,
+	}
+
+	tests := []struct {
+		name                    string
+		req                     *vtctldatapb.SetVtorcRecoveryMaintenanceRequest
+		keyspaceMaintenance     bool
+		shardMaintenance        bool
+		tabletMaintenance       bool
+		previousShardVtorcState *vtorcdatapb.Shard
+		shouldErr               bool
+	}{
+		{
+			name: "keyspace",
+			req: &vtctldatapb.SetVtorcRecoveryMaintenanceRequest{
+				Keyspace: "testkeyspace",
+				Reason:   "upgrade",
+				Duration: protoutil.DurationToProto(time.Hour),
+			},
+			keyspaceMaintenance: true,
+		},
+		{
+			name: "shard",
+			req: &vtctldatapb.SetVtorcRecoveryMaintenanceRequest{
+				Keyspace: "testkeyspace",
+				Shard:    "-80",
+				Reason:   "upgrade",
+				Duration: protoutil.DurationToProto(time.Hour),
+			},
+			shardMaintenance: true,
+		},
+		{
+			name: "tablet",
+			req: &vtctldatapb.SetVtorcRecoveryMaintenanceRequest{
+				TabletAlias: tablet.Alias,
+				Reason:      "upgrade",
+				Duration:    protoutil.DurationToProto(time.Hour),
+			},
+			tabletMaintenance: true,
+		},
+		{
+			name: "clear tablet",
+			req: &vtctldatapb.SetVtorcRecoveryMaintenanceRequest{
+				TabletAlias: tablet.Alias,
+				Clear:       true,
+			},
+			previousShardVtorcState: &vtorcdatapb.Shard{
+				RecoveryMaintenance: &vtorcdatapb.RecoveryMaintenance{Reason: "upgrade"},
+				TabletRecoveryMaintenance: map[string]*vtorcdatapb.RecoveryMaintenance{
+					"zone1-0000000100": {Reason: "upgrade"},
+				},
+			},
+			shardMaintenance: true,
+		},
+		{
+			name: "missing duration",
+			req: &vtctldatapb.SetVtorcRecoveryMaintenanceRequest{
+				Keyspace: "testkeyspace",
+			},
+			shouldErr: true,
+		},
+		{
+			name: "missing keyspace",
+			req: &vtctldatapb.SetVtorcRecoveryMaintenanceRequest{
+				Shard:    "-80",
+				Duration: protoutil.DurationToProto(time.Hour),
+			},
+			shouldErr: true,
+		},
+		{
+			name: "no such tablet",
+			req: &vtctldatapb.SetVtorcRecoveryMaintenanceRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  200,
+				},
+				Duration: protoutil.DurationToProto(time.Hour),
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			defer ts.Close()
+
+			testutil.AddTablets(ctx, t, ts, nil, tablet)
+			if tt.previousShardVtorcState != nil {
+				_, err := ts.UpdateShardFields(ctx, tablet.Keyspace, tablet.Shard, func(si *topo.ShardInfo) error {
+					si.VtorcState = tt.previousShardVtorcState
+					return nil
+				})
+				require.NoError(t, err)
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+
+			resp, err := vtctld.SetVtorcRecoveryMaintenance(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.req.Clear {
+				assert.Nil(t, resp.RecoveryMaintenance)
+			} else {
+				assert.Equal(t, "upgrade", resp.RecoveryMaintenance.Reason)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), protoutil.TimeFromProto(resp.RecoveryMaintenance.ExpireTime), time.Minute)
+			}
+
+			ki, err := ts.GetKeyspace(ctx, tablet.Keyspace)
+			require.NoError(t, err)
+			assert.Equal(t, tt.keyspaceMaintenance, ki.VtorcState.GetRecoveryMaintenance() != nil)
+			si, err := ts.GetShard(ctx, tablet.Keyspace, tablet.Shard)
+			require.NoError(t, err)
+			assert.Equal(t, tt.shardMaintenance, si.VtorcState.GetRecoveryMaintenance() != nil)
+			assert.Equal(t, tt.tabletMaintenance, si.VtorcState.GetTabletRecoveryMaintenance()["zone1-0000000100"] != nil)
+		})
+	}
+}
+
 func TestSetWritable(t *testing.T) {
 	t.Parallel()
 
@@ -14994,6 +15354,108 @@ func TestValidateShard(t *testing.T) {
 	}
 }
 
+func TestVerifyBackup(t *testing.T) {
+	ctx := t.Context()
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		},
+		Keyspace: "ks",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_REPLICA,
+	}
+
+	tests := []struct {
+		name      string
+		tmc       *testutil.TabletManagerClient
+		req       *vtctldatapb.VerifyBackupRequest
+		responses int
+		wantErr   string
+	}{
+		{
+			name: "ok",
+			tmc: &testutil.TabletManagerClient{
+				VerifyBackupResults: map[string]struct {
+					Events []*logutilpb.Event
+					Error  error
+				}{
+					"zone1-0000000100": {
+						Events: []*logutilpb.Event{{}, {}, {}},
+					},
+				},
+			},
+			req: &vtctldatapb.VerifyBackupRequest{
+				TabletAlias: tablet.Alias,
+				BackupName:  "2025-01-01.000000.zone1-0000000100",
+			},
+			responses: 3,
+		},
+		{
+			name: "verification failure",
+			tmc: &testutil.TabletManagerClient{
+				VerifyBackupResults: map[string]struct {
+					Events []*logutilpb.Event
+					Error  error
+				}{
+					"zone1-0000000100": {
+						Events: []*logutilpb.Event{{}},
+						Error:  errors.New("CHECK TABLE failed"),
+					},
+				},
+			},
+			req: &vtctldatapb.VerifyBackupRequest{
+				TabletAlias: tablet.Alias,
+			},
+			responses: 1,
+			wantErr:   "CHECK TABLE failed",
+		},
+		{
+			name: "no such tablet",
+			tmc:  &testutil.TabletManagerClient{},
+			req: &vtctldatapb.VerifyBackupRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone404",
+					Uid:  404,
+				},
+			},
+			wantErr: "node doesn't exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, nil, tablet)
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			client := localvtctldclient.New(vtctld)
+			stream, err := client.VerifyBackup(ctx, tt.req)
+			require.NoError(t, err)
+
+			var responses []*vtctldatapb.VerifyBackupResponse
+			for {
+				var resp *vtctldatapb.VerifyBackupResponse
+				resp, err = stream.Recv()
+				if err != nil {
+					break
+				}
+				assert.Equal(t, "ks", resp.Keyspace)
+				assert.Equal(t, "-", resp.Shard)
+				responses = append(responses, resp)
+			}
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.ErrorIs(t, err, io.EOF)
+			}
+			assert.Len(t, responses, tt.responses)
+		})
+	}
+}
+
 func TestMain(m *testing.M) {
 	_flag.ParseFlagsForTest()
 	os.Exit(m.Run())
@@ -17,8 +17,10 @@ limitations under the License.
 package testutil
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"sort"
 
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
@@ -30,6 +32,12 @@ type backupStorage struct {
 	// Backups is a mapping of directory to list of backup names stored in that
 	// directory.
 	Backups map[string][]string
+	// Manifests is a mapping of "directory/name" to the contents of the
+	// MANIFEST file of that backup.
+	Manifests map[string][]byte
+	// Verifications is a mapping of "directory/name" to the contents of the
+	// VERIFICATION file of that backup.
+	Verifications map[string][]byte
 	// ListBackupsError is returned from ListBackups when it is non-nil.
 	ListBackupsError error
 }
@@ -45,7 +53,7 @@ func (bs *backupStorage) ListBackups(ctx context.Context, dir string) ([]backups
 	for k, v := range bs.Backups {
 		if k == dir {
 			for _, name := range v {
-				handles = append(handles, &backupHandle{directory: k, name: name})
+				handles = append(handles, &backupHandle{bs: bs, directory: k, name: name})
 			}
 		}
 	}
@@ -85,12 +93,31 @@ func (bs *backupStorage) Close() error { return nil }
 type backupHandle struct {
 	backupstorage.BackupHandle
 
+	bs        *backupStorage
 	directory string
 	name      string
 }
 
 func (bh *backupHandle) Directory() string { return bh.directory }
 func (bh *backupHandle) Name() string      { return bh.name }
+func (bh *backupHandle) Error() error      { return nil }
+
+// ReadFile is part of the backupstorage.BackupHandle interface. Only the
+// MANIFEST and VERIFICATION files are supported.
+func (bh *backupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	var files map[string][]byte
+	switch filename {
+	case backupstorage.ManifestFileName:
+		files = bh.bs.Manifests
+	case backupstorage.VerificationFileName:
+		files = bh.bs.Verifications
+	}
+	data, ok := files[bh.directory+"/"+bh.name]
+	if !ok {
+		return nil, fmt.Errorf("no file %s for backup %s/%s in testutil.BackupStorage", filename, bh.directory, bh.name)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
 
 // handlesByName implements the sort interface for backup handles by Name().
 type handlesByName []backupstorage.BackupHandle
@@ -374,6 +374,11 @@ type TabletManagerClient struct {
 	UndoDemotePrimaryDelays map[string]time.Duration
 	// keyed by tablet alias
 	UndoDemotePrimaryResults map[string]error
+	// keyed by tablet alias. The stream ends with Error after sending Events.
+	VerifyBackupResults map[string]struct {
+		Events []*logutilpb.Event
+		Error  error
+	}
 	// tablet alias => duration
 	VReplicationExecDelays map[string]time.Duration
 	// tablet alias => query string => result
@@ -1564,6 +1569,32 @@ func (fake *TabletManagerClient) UndoDemotePrimary(ctx context.Context, tablet *
 	return assert.AnError
 }
 
+// VerifyBackup is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) VerifyBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.VerifyBackupRequest) (logutil.EventStream, error) {
+	key := topoproto.TabletAliasString(tablet.Alias)
+	testdata, ok := fake.VerifyBackupResults[key]
+	if !ok {
+		return nil, fmt.Errorf("no VerifyBackup fake result set for %s", key)
+	}
+
+	// The channel is unbuffered, so that all events are received before the
+	// stream is closed.
+	stream := &backupRestoreStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *logutilpb.Event),
+	}
+	go func() {
+		for _, event := range testdata.Events {
+			if err := stream.Send(event); err != nil {
+				return
+			}
+		}
+		stream.CloseWithError(testdata.Error)
+	}()
+
+	return stream, nil
+}
+
 // VReplicationExec is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) VReplicationExec(ctx context.Context, tablet *topodatapb.Tablet, query string) (*querypb.QueryResult, error) {
 	if fake.VReplicationExecResults == nil {
@@ -39,6 +39,11 @@ func (client *localVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldat
 	return client.s.AddCellsAlias(ctx, in)
 }
 
+// ApplyBackupRetention is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ApplyBackupRetention(ctx context.Context, in *vtctldatapb.ApplyBackupRetentionRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyBackupRetentionResponse, error) {
+	return client.s.ApplyBackupRetention(ctx, in)
+}
+
 // ApplyKeyspaceRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ApplyKeyspaceRoutingRules(ctx context.Context, in *vtctldatapb.ApplyKeyspaceRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyKeyspaceRoutingRulesResponse, error) {
 	return client.s.ApplyKeyspaceRoutingRules(ctx, in)
@@ -456,6 +461,11 @@ func (client *localVtctldClient) LookupVindexInternalize(ctx context.Context, in
 	return client.s.LookupVindexInternalize(ctx, in)
 }
 
+// LookupVindexVerify is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) LookupVindexVerify(ctx context.Context, in *vtctldatapb.LookupVindexVerifyRequest, opts ...grpc.CallOption) (*vtctldatapb.LookupVindexVerifyResponse, error) {
+	return client.s.LookupVindexVerify(ctx, in)
+}
+
 // MaterializeCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) MaterializeCreate(ctx context.Context, in *vtctldatapb.MaterializeCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.MaterializeCreateResponse, error) {
 	return client.s.MaterializeCreate(ctx, in)
@@ -627,6 +637,11 @@ func (client *localVtctldClient) RunHealthCheck(ctx context.Context, in *vtctlda
 	return client.s.RunHealthCheck(ctx, in)
 }
 
+// SetKeyspaceBackupRetentionPolicy is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SetKeyspaceBackupRetentionPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceBackupRetentionPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceBackupRetentionPolicyResponse, error) {
+	return client.s.SetKeyspaceBackupRetentionPolicy(ctx, in)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	return client.s.SetKeyspaceDurabilityPolicy(ctx, in)
@@ -647,6 +662,11 @@ func (client *localVtctldClient) SetVtorcEmergencyReparent(ctx context.Context,
 	return client.s.SetVtorcEmergencyReparent(ctx, in)
 }
 
+// SetVtorcRecoveryMaintenance is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SetVtorcRecoveryMaintenance(ctx context.Context, in *vtctldatapb.SetVtorcRecoveryMaintenanceRequest, opts ...grpc.CallOption) (*vtctldatapb.SetVtorcRecoveryMaintenanceResponse, error) {
+	return client.s.SetVtorcRecoveryMaintenance(ctx, in)
+}
+
 // SetWritable is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) SetWritable(ctx context.Context, in *vtctldatapb.SetWritableRequest, opts ...grpc.CallOption) (*vtctldatapb.SetWritableResponse, error) {
 	return client.s.SetWritable(ctx, in)
@@ -782,6 +802,57 @@ func (client *localVtctldClient) ValidateVersionShard(ctx context.Context, in *v
 	return client.s.ValidateVersionShard(ctx, in)
 }
 
+type verifyBackupStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.VerifyBackupResponse
+}
+
+func (stream *verifyBackupStreamAdapter) Recv() (*vtctldatapb.VerifyBackupResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case err := <-stream.ErrCh:
+		return nil, err
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *verifyBackupStreamAdapter) Send(msg *vtctldatapb.VerifyBackupResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// VerifyBackup is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) VerifyBackup(ctx context.Context, in *vtctldatapb.VerifyBackupRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_VerifyBackupClient, error) {
+	stream := &verifyBackupStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.VerifyBackupResponse, 1),
+	}
+	go func() {
+		err := client.s.VerifyBackup(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
 // WorkflowAddTables is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) WorkflowAddTables(ctx context.Context, in *vtctldatapb.WorkflowAddTablesRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowAddTablesResponse, error) {
 	return client.s.WorkflowAddTables(ctx, in)
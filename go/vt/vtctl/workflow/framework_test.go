@@ -281,6 +281,7 @@ type testTMClient struct {
 	applySchemaRequests                map[uint32][]*applySchemaRequestResponse
 	primaryPositions                   map[uint32]string
 	vdiffRequests                      map[uint32]*vdiffRequestResponse
+	receivedVDiffRequests              map[uint32]*tabletmanagerdatapb.VDiffRequest // The last one, by tablet.
 	refreshStateErrors                 map[uint32]error
 
 	// Stack of ReadVReplicationWorkflowsResponse to return, in order, for each shard
@@ -310,6 +311,7 @@ func newTestTMClient(env *testEnv) *testTMClient {
 		readVReplicationWorkflowsResponses: make(map[string][]*tabletmanagerdatapb.ReadVReplicationWorkflowsResponse),
 		primaryPositions:                   make(map[uint32]string),
 		vdiffRequests:                      make(map[uint32]*vdiffRequestResponse),
+		receivedVDiffRequests:              make(map[uint32]*tabletmanagerdatapb.VDiffRequest),
 		refreshStateErrors:                 make(map[uint32]error),
 		env:                                env,
 	}
@@ -541,6 +543,10 @@ func (tmc *testTMClient) ExecuteFetchAsApp(ctx context.Context, tablet *topodata
 	return tmc.VReplicationExec(ctx, tablet, string(req.Query))
 }
 
+func (tmc *testTMClient) CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
+	return &tabletmanagerdatapb.CheckThrottlerResponse{ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_OK}, nil
+}
+
 func (tmc *testTMClient) expectApplySchemaRequest(tabletID uint32, req *applySchemaRequestResponse) {
 	tmc.mu.Lock()
 	defer tmc.mu.Unlock()
@@ -665,6 +671,7 @@ func (tmc *testTMClient) VDiff(ctx context.Context, tablet *topodatapb.Tablet, r
 	tmc.mu.Lock()
 	defer tmc.mu.Unlock()
 
+	tmc.receivedVDiffRequests[tablet.Alias.Uid] = req
 	if vrr, ok := tmc.vdiffRequests[tablet.Alias.Uid]; ok {
 		if !proto.Equal(vrr.req, req) {
 			return nil, fmt.Errorf("unexpected VDiff request on tablet %s; got %+v, want %+v",
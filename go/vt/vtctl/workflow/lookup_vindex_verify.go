@@ -0,0 +1,978 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/schematools"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	defaultLookupVindexVerifyBatchSize     = 1000
+	defaultLookupVindexVerifyMaxSampleRows = 1000
+
+	// lookupVindexVerifyResponseSampleRows is the number of rows of each
+	// kind of difference that are returned as samples.
+	lookupVindexVerifyResponseSampleRows = 10
+
+	// lookupVindexVerifyMaxFetchRows bounds the number of rows that can be
+	// fetched for the from values of a batch. It's larger than the batch
+	// size because non-unique vindexes can map a value to many rows.
+	lookupVindexVerifyMaxFetchRows = 1_000_000
+
+	// lookupVindexVerifyThrottleWait is how long we wait before checking
+	// the throttler again after being throttled.
+	lookupVindexVerifyThrottleWait = 5 * time.Second
+)
+
+// lookupVindexVerifyTypes are the lookup vindex types that can be verified,
+// which are the ones that store the keyspace id in their to column, and
+// whether they are unique.
+var lookupVindexVerifyTypes = map[string]bool{
+	"lookup":                   false,
+	"lookup_unique":            true,
+	"consistent_lookup":        false,
+	"consistent_lookup_unique": true,
+}
+
+type lookupVindexDiffKind int
+
+const (
+	lookupVindexDiffMissing lookupVindexDiffKind = iota
+	lookupVindexDiffOrphaned
+	lookupVindexDiffMismatched
+)
+
+// lookupVindexRow is a row of the owner table or of the lookup table,
+// reduced to the from values of the lookup vindex and the keyspace id
+// that they map to.
+type lookupVindexRow struct {
+	// key identifies the from values. For the rows that are read from the
+	// tables, it uses their weight strings so that values are matched the
+	// way MySQL matches them.
+	key  string
+	from []sqltypes.Value
+	ksid []byte
+	// shard is the shard of the lookup table holding the row. It's only
+	// set for lookup table rows.
+	shard *lookupVindexVerifyShard
+}
+
+// lookupVindexDiff is a difference between the owner table and the
+// lookup table.
+type lookupVindexDiff struct {
+	kind lookupVindexDiffKind
+	key  string
+	from []sqltypes.Value
+	// lookupTo is the to value of the lookup table row. It's nil for
+	// missing rows.
+	lookupTo []byte
+	// ownerTo is the keyspace id of the owner table row. It's nil for
+	// orphaned rows.
+	ownerTo []byte
+	// shard is the shard of the lookup table holding the row. It's nil
+	// for missing rows.
+	shard *lookupVindexVerifyShard
+}
+
+type lookupVindexVerifyShard struct {
+	si     *topo.ShardInfo
+	tablet *topodatapb.Tablet
+}
+
+// lookupVindexVerifier compares the owner table of a lookup vindex with its
+// lookup table using a VDiff of the workflow that backfilled the vindex, which
+// projects the owner table onto the lookup table. The VDiff is run without
+// syncing the streams of the workflow, as the lookup table is maintained by
+// vtgate once the vindex is externalized. The lookup table can then be
+// repaired using the rows of the VDiff report.
+type lookupVindexVerifier struct {
+	lv *lookupVindex
+
+	name          string
+	tableKeyspace string
+	repair        bool
+	batchSize     int64
+	maxSampleRows int64
+
+	unique bool
+
+	ownerTable string
+	ownerCols  []string
+	// ownerFromTypes are the types of the owner columns, which are the types
+	// of the from values of the rows that the VDiff reads from the owner table.
+	ownerFromTypes []querypb.Type
+	// ownerVindex is the primary vindex of the owner table, which maps
+	// ownerVindexCol to the keyspace id of the owner rows.
+	ownerVindex    vindexes.SingleColumn
+	ownerVindexCol string
+	ownerShards    []*lookupVindexVerifyShard
+
+	lookupTable     string
+	fromCols        []string
+	toCol           string
+	lookupFromTypes []querypb.Type
+	// lookupVindex is the primary vindex of the lookup table, which maps
+	// the from column at lookupVindexColIdx to the keyspace id of the
+	// lookup rows. It's nil if the lookup table is in an unsharded keyspace.
+	lookupVindex       vindexes.SingleColumn
+	lookupVindexColIdx int
+	lookupShards       []*lookupVindexVerifyShard
+
+	resp *vtctldatapb.LookupVindexVerifyResponse
+}
+
+// newLookupVindexVerifier validates the lookup vindex and gathers
+// everything needed to verify it.
+func newLookupVindexVerifier(ctx context.Context, lv *lookupVindex, req *vtctldatapb.LookupVindexVerifyRequest) (*lookupVindexVerifier, error) {
+	v := &lookupVindexVerifier{
+		lv:            lv,
+		name:          req.Name,
+		repair:        req.Repair,
+		batchSize:     req.BatchSize,
+		maxSampleRows: req.MaxSampleRows,
+		resp:          &vtctldatapb.LookupVindexVerifyResponse{},
+	}
+	if v.batchSize <= 0 {
+		v.batchSize = defaultLookupVindexVerifyBatchSize
+	}
+	if v.maxSampleRows <= 0 {
+		v.maxSampleRows = defaultLookupVindexVerifyMaxSampleRows
+	}
+
+	vschema, err := lv.ts.GetVSchema(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if !vschema.Sharded {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "keyspace %s is not sharded", req.Keyspace)
+	}
+	vindex, ok := vschema.Vindexes[req.Name]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "vindex %s not found in the %s keyspace", req.Name, req.Keyspace)
+	}
+	unique, ok := lookupVindexVerifyTypes[vindex.Type]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vindex %s has type %s, only lookup vindexes that store the keyspace id can be verified",
+			req.Name, vindex.Type)
+	}
+	v.unique = unique
+	if vindex.Owner == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vindex %s has no owner", req.Name)
+	}
+	v.ownerTable = vindex.Owner
+
+	tableKeyspace, lookupTable, err := lv.parser.ParseTable(vindex.Params["table"])
+	if err != nil || lookupTable == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vindex %s has an invalid table param: %q", req.Name, vindex.Params["table"])
+	}
+	if tableKeyspace == "" {
+		tableKeyspace = req.Keyspace
+	}
+	if req.TableKeyspace != "" && req.TableKeyspace != tableKeyspace {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vindex %s uses a lookup table in the %s keyspace, not in the %s keyspace",
+			req.Name, tableKeyspace, req.TableKeyspace)
+	}
+	v.tableKeyspace = tableKeyspace
+	v.lookupTable = lookupTable
+	for _, col := range strings.Split(vindex.Params["from"], ",") {
+		if col = strings.TrimSpace(col); col != "" {
+			v.fromCols = append(v.fromCols, col)
+		}
+	}
+	v.toCol = strings.TrimSpace(vindex.Params["to"])
+	if len(v.fromCols) == 0 || v.toCol == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vindex %s must have from and to params", req.Name)
+	}
+
+	ownerTable, ok := vschema.Tables[v.ownerTable]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "owner table %s not found in the %s keyspace", v.ownerTable, req.Keyspace)
+	}
+	for _, cv := range ownerTable.ColumnVindexes {
+		if cv.Name == req.Name {
+			v.ownerCols = columnVindexColumns(cv)
+			break
+		}
+	}
+	if len(v.ownerCols) != len(v.fromCols) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "owner table %s must have %d columns for vindex %s, found %d",
+			v.ownerTable, len(v.fromCols), req.Name, len(v.ownerCols))
+	}
+	v.ownerVindex, v.ownerVindexCol, err = primaryVindex(vschema.Keyspace, v.ownerTable, ownerTable)
+	if err != nil {
+		return nil, err
+	}
+
+	lookupVSchema, err := lv.ts.GetVSchema(ctx, tableKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	if lookupVSchema.Sharded {
+		table, ok := lookupVSchema.Tables[v.lookupTable]
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "lookup table %s not found in the %s keyspace", v.lookupTable, tableKeyspace)
+		}
+		var col string
+		v.lookupVindex, col, err = primaryVindex(lookupVSchema.Keyspace, v.lookupTable, table)
+		if err != nil {
+			return nil, err
+		}
+		v.lookupVindexColIdx = -1
+		for i, fromCol := range v.fromCols {
+			if strings.EqualFold(fromCol, col) {
+				v.lookupVindexColIdx = i
+				break
+			}
+		}
+		if v.lookupVindexColIdx == -1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary vindex column %s of lookup table %s is not a from column of vindex %s",
+				col, v.lookupTable, req.Name)
+		}
+	}
+
+	if v.ownerShards, err = lv.getPrimaries(ctx, req.Keyspace); err != nil {
+		return nil, err
+	}
+	if v.lookupShards, err = lv.getPrimaries(ctx, tableKeyspace); err != nil {
+		return nil, err
+	}
+	if v.lookupVindex == nil && len(v.lookupShards) != 1 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unsharded keyspace %s has %d shards", tableKeyspace, len(v.lookupShards))
+	}
+	if v.ownerFromTypes, err = lv.getColumnTypes(ctx, v.ownerShards[0], v.ownerTable, v.ownerCols); err != nil {
+		return nil, err
+	}
+	if v.lookupFromTypes, err = lv.getColumnTypes(ctx, v.lookupShards[0], v.lookupTable, v.fromCols); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func columnVindexColumns(cv *vschemapb.ColumnVindex) []string {
+	if len(cv.Columns) != 0 {
+		return cv.Columns
+	}
+	if cv.Column != "" {
+		return []string{cv.Column}
+	}
+	return nil
+}
+
+// primaryVindex returns the primary vindex of the table, and its column,
+// which must be a single column functional vindex.
+func primaryVindex(ks *vschemapb.Keyspace, tableName string, table *vschemapb.Table) (vindexes.SingleColumn, string, error) {
+	if len(table.ColumnVindexes) == 0 {
+		return nil, "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s has no primary vindex", tableName)
+	}
+	cv := table.ColumnVindexes[0]
+	cols := columnVindexColumns(cv)
+	vindex, ok := ks.Vindexes[cv.Name]
+	if !ok || len(cols) != 1 {
+		return nil, "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary vindex %s of table %s must be a single column vindex", cv.Name, tableName)
+	}
+	vdx, err := vindexes.CreateVindex(vindex.Type, cv.Name, vindex.Params)
+	if err != nil {
+		return nil, "", err
+	}
+	single, ok := vdx.(vindexes.SingleColumn)
+	if !ok || vdx.NeedsVCursor() {
+		return nil, "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary vindex %s of table %s must be a single column functional vindex", cv.Name, tableName)
+	}
+	return single, cols[0], nil
+}
+
+// getPrimaries returns the serving shards of the keyspace along with their
+// primary tablets.
+func (lv *lookupVindex) getPrimaries(ctx context.Context, keyspace string) ([]*lookupVindexVerifyShard, error) {
+	shards, err := lv.ts.GetServingShards(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	primaries := make([]*lookupVindexVerifyShard, 0, len(shards))
+	for _, si := range shards {
+		if si.PrimaryAlias == nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", keyspace, si.ShardName())
+		}
+		ti, err := lv.ts.GetTablet(ctx, si.PrimaryAlias)
+		if err != nil {
+			return nil, err
+		}
+		primaries = append(primaries, &lookupVindexVerifyShard{si: si, tablet: ti.Tablet})
+	}
+	return primaries, nil
+}
+
+// getColumnTypes returns the types of the columns of the table.
+func (lv *lookupVindex) getColumnTypes(ctx context.Context, shard *lookupVindexVerifyShard, table string, cols []string) ([]querypb.Type, error) {
+	schema, err := schematools.GetSchema(ctx, lv.ts, lv.tmc, shard.tablet.Alias, &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{table}})
+	if err != nil {
+		return nil, err
+	}
+	if len(schema.TableDefinitions) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found on tablet %s", table, topoproto.TabletAliasString(shard.tablet.Alias))
+	}
+	types := make([]querypb.Type, len(cols))
+	for i, col := range cols {
+		idx := slices.IndexFunc(schema.TableDefinitions[0].Fields, func(field *querypb.Field) bool {
+			return strings.EqualFold(field.Name, col)
+		})
+		if idx == -1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "column %s not found in table %s", col, table)
+		}
+		types[i] = schema.TableDefinitions[0].Fields[idx].Type
+	}
+	return types, nil
+}
+
+// startVDiff starts a VDiff of the lookup table in the workflow that
+// backfilled the vindex, which is named after it.
+func (v *lookupVindexVerifier) startVDiff(ctx context.Context, s *Server) (*vtctldatapb.LookupVindexVerifyResponse, error) {
+	resp, err := s.vdiffCreate(ctx, &vtctldatapb.VDiffCreateRequest{
+		Workflow:              v.name,
+		TargetKeyspace:        v.tableKeyspace,
+		Tables:                []string{v.lookupTable},
+		AutoRetry:             true,
+		MaxExtraRowsToCompare: v.maxSampleRows,
+		MaxReportSampleRows:   v.maxSampleRows,
+	}, true)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to start a VDiff of the %s.%s workflow that backfilled vindex %s",
+			v.tableKeyspace, v.name, v.name)
+	}
+	return &vtctldatapb.LookupVindexVerifyResponse{
+		Uuid:  resp.UUID,
+		State: string(vdiff.PendingState),
+	}, nil
+}
+
+// showVDiff reports the progress and the differences found by the VDiff, and
+// repairs the lookup table once the VDiff has completed, if requested.
+func (v *lookupVindexVerifier) showVDiff(ctx context.Context, s *Server, uuid string) (*vtctldatapb.LookupVindexVerifyResponse, error) {
+	showResp, err := s.VDiffShow(ctx, &vtctldatapb.VDiffShowRequest{
+		Workflow:       v.name,
+		TargetKeyspace: v.tableKeyspace,
+		Arg:            uuid,
+	})
+	if err != nil {
+		return nil, err
+	}
+	summary, err := BuildSummary(v.tableKeyspace, v.name, uuid, showResp, true)
+	if err != nil {
+		return nil, err
+	}
+	v.resp = &vtctldatapb.LookupVindexVerifyResponse{
+		Uuid:         uuid,
+		State:        string(summary.State),
+		RowsCompared: summary.RowsCompared,
+	}
+	if summary.Progress != nil {
+		v.resp.ProgressPercentage = summary.Progress.Percentage
+		v.resp.Eta = summary.Progress.ETA
+	}
+
+	reports := summary.Reports[v.lookupTable]
+	shards := slices.Sorted(maps.Keys(reports))
+	var keys []*lookupVindexRow
+	for _, shard := range shards {
+		shardKeys, err := v.recordReport(reports[shard])
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, shardKeys...)
+	}
+
+	if !v.repair {
+		return v.resp, nil
+	}
+	if summary.State != vdiff.CompletedState {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "VDiff %s is %s, the lookup table can only be repaired once it has completed",
+			uuid, summary.State)
+	}
+	for len(keys) > 0 {
+		batch := keys[:min(int64(len(keys)), v.batchSize)]
+		keys = keys[len(batch):]
+		if err := v.repairRows(ctx, batch); err != nil {
+			return nil, err
+		}
+	}
+	return v.resp, nil
+}
+
+// recordReport adds the differences found on a shard to the response, and
+// returns the from values of the rows that differ. The VDiff reads the from
+// values of the owner table as the from columns of the lookup table, and
+// mismatched rows have the same from values on both sides.
+func (v *lookupVindexVerifier) recordReport(report vdiff.DiffReport) ([]*lookupVindexRow, error) {
+	v.resp.MissingRows += report.ExtraRowsSource
+	v.resp.OrphanedRows += report.ExtraRowsTarget
+	v.resp.MismatchedRows += report.MismatchedRows
+
+	var keys []*lookupVindexRow
+	for _, rd := range report.ExtraRowsSourceDiffs {
+		row, err := v.parseReportRow(rd, v.ownerFromTypes)
+		if err != nil {
+			return nil, err
+		}
+		v.resp.MissingRowSamples = appendLookupVindexSample(v.resp.MissingRowSamples, row.from, "", row.to)
+		keys = append(keys, row.lookupVindexRow)
+	}
+	for _, rd := range report.ExtraRowsTargetDiffs {
+		row, err := v.parseReportRow(rd, v.lookupFromTypes)
+		if err != nil {
+			return nil, err
+		}
+		v.resp.OrphanedRowSamples = appendLookupVindexSample(v.resp.OrphanedRowSamples, row.from, row.to, "")
+		keys = append(keys, row.lookupVindexRow)
+	}
+	for _, dm := range report.MismatchedRowsDiffs {
+		if dm.Source == nil || dm.Target == nil {
+			continue
+		}
+		source, err := v.parseReportRow(dm.Source, v.ownerFromTypes)
+		if err != nil {
+			return nil, err
+		}
+		target, err := v.parseReportRow(dm.Target, v.lookupFromTypes)
+		if err != nil {
+			return nil, err
+		}
+		v.resp.MismatchedRowSamples = appendLookupVindexSample(v.resp.MismatchedRowSamples, target.from, target.to, source.to)
+		keys = append(keys, target.lookupVindexRow)
+	}
+	return keys, nil
+}
+
+func appendLookupVindexSample(samples []*vtctldatapb.LookupVindexVerifyResponse_RowDiff, from []string, lookupTo, ownerTo string) []*vtctldatapb.LookupVindexVerifyResponse_RowDiff {
+	if len(samples) >= lookupVindexVerifyResponseSampleRows {
+		return samples
+	}
+	return append(samples, &vtctldatapb.LookupVindexVerifyResponse_RowDiff{
+		From:     from,
+		LookupTo: lookupTo,
+		OwnerTo:  ownerTo,
+	})
+}
+
+// lookupVindexReportRow is a row of a VDiff report, with the values of the
+// from columns and of the to column as they are reported.
+type lookupVindexReportRow struct {
+	*lookupVindexRow
+	from []string
+	to   string
+}
+
+// parseReportRow parses the from values of a row of a VDiff report, given
+// the types of the columns that the row was read from. The columns of the
+// rows read from the owner table are the select expressions of the workflow
+// filter, like "c1 as c1", and binary values are reported in hex, like
+// "0x6a".
+func (v *lookupVindexVerifier) parseReportRow(rd *vdiff.RowDiff, fromTypes []querypb.Type) (*lookupVindexReportRow, error) {
+	row := &lookupVindexReportRow{
+		lookupVindexRow: &lookupVindexRow{from: make([]sqltypes.Value, len(v.fromCols))},
+		from:            make([]string, len(v.fromCols)),
+	}
+	found := make([]bool, len(v.fromCols))
+	for expr, val := range rd.Row {
+		col, err := v.reportColumn(expr)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(col, v.toCol) {
+			row.to = val
+			continue
+		}
+		i := slices.IndexFunc(v.fromCols, func(fromCol string) bool {
+			return strings.EqualFold(fromCol, col)
+		})
+		if i == -1 {
+			continue
+		}
+		raw := []byte(val)
+		if sqltypes.IsBinary(fromTypes[i]) && strings.HasPrefix(val, "0x") {
+			if raw, err = hex.DecodeString(val[2:]); err != nil {
+				return nil, vterrors.Wrapf(err, "invalid value %q of column %s in the VDiff report", val, col)
+			}
+		}
+		row.from[i] = val
+		row.lookupVindexRow.from[i] = sqltypes.MakeTrusted(fromTypes[i], raw)
+		found[i] = true
+	}
+	if i := slices.Index(found, false); i != -1 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "column %s is missing from a row of the VDiff report", v.fromCols[i])
+	}
+	row.key = strings.Join(row.from, "\x00")
+	return row, nil
+}
+
+// reportColumn returns the name of the column of the lookup table that a
+// column of a VDiff report row is for.
+func (v *lookupVindexVerifier) reportColumn(expr string) (string, error) {
+	stmt, err := v.lv.parser.Parse("select " + expr + " from dual")
+	if err != nil {
+		return "", vterrors.Wrapf(err, "invalid column %q in the VDiff report", expr)
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if ok && len(sel.SelectExprs.Exprs) == 1 {
+		if ae, ok := sel.SelectExprs.Exprs[0].(*sqlparser.AliasedExpr); ok {
+			if !ae.As.IsEmpty() {
+				return ae.As.String(), nil
+			}
+			if col, ok := ae.Expr.(*sqlparser.ColName); ok {
+				return col.Name.String(), nil
+			}
+		}
+	}
+	return "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "invalid column %q in the VDiff report", expr)
+}
+
+// diffOwnerRows returns the owner rows that are missing from, or
+// mismatched in, the lookup rows.
+func (v *lookupVindexVerifier) diffOwnerRows(ownerRows, lookupRows []*lookupVindexRow) []*lookupVindexDiff {
+	byKey := groupLookupVindexRows(lookupRows)
+	var diffs []*lookupVindexDiff
+	for _, owner := range ownerRows {
+		rows := byKey[owner.key]
+		if containsKeyspaceID(rows, owner.ksid) {
+			continue
+		}
+		if v.unique && len(rows) > 0 {
+			diffs = append(diffs, &lookupVindexDiff{
+				kind:     lookupVindexDiffMismatched,
+				key:      owner.key,
+				from:     rows[0].from,
+				lookupTo: rows[0].ksid,
+				ownerTo:  owner.ksid,
+				shard:    rows[0].shard,
+			})
+			continue
+		}
+		diffs = append(diffs, &lookupVindexDiff{
+			kind:    lookupVindexDiffMissing,
+			key:     owner.key,
+			from:    owner.from,
+			ownerTo: owner.ksid,
+		})
+	}
+	return diffs
+}
+
+// diffLookupRows returns the lookup rows that are orphaned. The rows of
+// unique vindexes that have owner rows are mismatched, which is reported
+// when diffing the owner rows.
+func (v *lookupVindexVerifier) diffLookupRows(lookupRows, ownerRows []*lookupVindexRow) []*lookupVindexDiff {
+	byKey := groupLookupVindexRows(ownerRows)
+	var diffs []*lookupVindexDiff
+	for _, lookup := range lookupRows {
+		rows := byKey[lookup.key]
+		if containsKeyspaceID(rows, lookup.ksid) || (v.unique && len(rows) > 0) {
+			continue
+		}
+		diffs = append(diffs, &lookupVindexDiff{
+			kind:     lookupVindexDiffOrphaned,
+			key:      lookup.key,
+			from:     lookup.from,
+			lookupTo: lookup.ksid,
+			shard:    lookup.shard,
+		})
+	}
+	return diffs
+}
+
+func groupLookupVindexRows(rows []*lookupVindexRow) map[string][]*lookupVindexRow {
+	byKey := make(map[string][]*lookupVindexRow, len(rows))
+	for _, row := range rows {
+		byKey[row.key] = append(byKey[row.key], row)
+	}
+	return byKey
+}
+
+func containsKeyspaceID(rows []*lookupVindexRow, ksid []byte) bool {
+	for _, row := range rows {
+		if bytes.Equal(row.ksid, ksid) {
+			return true
+		}
+	}
+	return false
+}
+
+// repairRows repairs the lookup table for the given from values. The rows
+// of the owner table and of the lookup table that have them are read again,
+// and only the differences that exist now are repaired, so that we don't
+// undo changes made since the VDiff compared them.
+func (v *lookupVindexVerifier) repairRows(ctx context.Context, keys []*lookupVindexRow) error {
+	ownerRows, err := v.fetchOwnerRows(ctx, keys)
+	if err != nil {
+		return err
+	}
+	lookupRows, err := v.fetchLookupRows(ctx, keys)
+	if err != nil {
+		return err
+	}
+	diffs := append(v.diffOwnerRows(ownerRows, lookupRows), v.diffLookupRows(lookupRows, ownerRows)...)
+
+	inserts := make(map[*lookupVindexVerifyShard][]*lookupVindexDiff)
+	deletes := make(map[*lookupVindexVerifyShard][]*lookupVindexDiff)
+	var updates []*lookupVindexDiff
+	for _, d := range diffs {
+		switch d.kind {
+		case lookupVindexDiffMissing:
+			shard, err := v.lookupShard(ctx, d.from)
+			if err != nil {
+				return err
+			}
+			inserts[shard] = append(inserts[shard], d)
+		case lookupVindexDiffOrphaned:
+			deletes[d.shard] = append(deletes[d.shard], d)
+		case lookupVindexDiffMismatched:
+			updates = append(updates, d)
+		}
+	}
+
+	// Iterate over the shards, rather than the maps, for a stable order.
+	for _, shard := range v.lookupShards {
+		if len(inserts[shard]) > 0 {
+			if err := v.repairOnShard(ctx, shard, v.insertQuery(inserts[shard])); err != nil {
+				return err
+			}
+		}
+		if len(deletes[shard]) > 0 {
+			if err := v.repairOnShard(ctx, shard, v.deleteQuery(deletes[shard])); err != nil {
+				return err
+			}
+		}
+	}
+	for _, d := range updates {
+		if err := v.repairOnShard(ctx, d.shard, v.updateQuery(d)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *lookupVindexVerifier) repairOnShard(ctx context.Context, shard *lookupVindexVerifyShard, query string) error {
+	if err := v.waitForThrottler(ctx, shard); err != nil {
+		return err
+	}
+	qr, err := v.execute(ctx, shard, query, 0)
+	if err != nil {
+		return err
+	}
+	v.resp.RepairedRows += int64(qr.RowsAffected)
+	return nil
+}
+
+// lookupShard returns the shard of the lookup table that a row with the
+// given from values belongs to.
+func (v *lookupVindexVerifier) lookupShard(ctx context.Context, from []sqltypes.Value) (*lookupVindexVerifyShard, error) {
+	if v.lookupVindex == nil {
+		return v.lookupShards[0], nil
+	}
+	dests, err := v.lookupVindex.Map(ctx, nil, []sqltypes.Value{from[v.lookupVindexColIdx]})
+	if err != nil {
+		return nil, err
+	}
+	if ksid, ok := dests[0].(key.DestinationKeyspaceID); ok {
+		for _, shard := range v.lookupShards {
+			if key.KeyRangeContains(shard.si.KeyRange, ksid) {
+				return shard, nil
+			}
+		}
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no shard of the lookup table %s found for %v", v.lookupTable, from)
+}
+
+// fetchLookupRows reads the rows of the lookup table, from all of its
+// shards, that have the from values of the given rows.
+func (v *lookupVindexVerifier) fetchLookupRows(ctx context.Context, keys []*lookupVindexRow) ([]*lookupVindexRow, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	query := v.fetchQuery(v.lookupTable, v.fromCols, v.toCol, keys)
+	var rows []*lookupVindexRow
+	for _, shard := range v.lookupShards {
+		qr, err := v.execute(ctx, shard, query, lookupVindexVerifyMaxFetchRows)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, v.lookupRows(shard, qr)...)
+	}
+	return rows, nil
+}
+
+// fetchOwnerRows reads the rows of the owner table, from all of its shards,
+// that have the from values of the given rows.
+func (v *lookupVindexVerifier) fetchOwnerRows(ctx context.Context, keys []*lookupVindexRow) ([]*lookupVindexRow, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	query := v.fetchQuery(v.ownerTable, v.ownerCols, v.ownerVindexCol, keys)
+	var rows []*lookupVindexRow
+	for _, shard := range v.ownerShards {
+		qr, err := v.execute(ctx, shard, query, lookupVindexVerifyMaxFetchRows)
+		if err != nil {
+			return nil, err
+		}
+		ownerRows, err := v.ownerRows(ctx, qr)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, ownerRows...)
+	}
+	return rows, nil
+}
+
+// ownerRows converts the result of a fetch query on the owner table to rows.
+// The keyspace ids are computed using the primary vindex of the owner table.
+func (v *lookupVindexVerifier) ownerRows(ctx context.Context, qr *sqltypes.Result) ([]*lookupVindexRow, error) {
+	rows, vindexValues := v.parseRows(qr)
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	dests, err := v.ownerVindex.Map(ctx, nil, vindexValues)
+	if err != nil {
+		return nil, err
+	}
+	ownerRows := rows[:0]
+	for i, row := range rows {
+		ksid, ok := dests[i].(key.DestinationKeyspaceID)
+		if !ok {
+			continue
+		}
+		row.ksid = ksid
+		ownerRows = append(ownerRows, row)
+	}
+	return ownerRows, nil
+}
+
+// lookupRows converts the result of a fetch query on the lookup table to rows.
+func (v *lookupVindexVerifier) lookupRows(shard *lookupVindexVerifyShard, qr *sqltypes.Result) []*lookupVindexRow {
+	rows, toValues := v.parseRows(qr)
+	for i, row := range rows {
+		row.ksid = toValues[i].Raw()
+		row.shard = shard
+	}
+	return rows
+}
+
+// parseRows parses the from values, their weight strings and the last
+// column of the result, skipping the rows that have NULL from values as
+// those have no lookup rows.
+func (v *lookupVindexVerifier) parseRows(qr *sqltypes.Result) ([]*lookupVindexRow, []sqltypes.Value) {
+	numFrom := len(v.fromCols)
+	var rows []*lookupVindexRow
+	var last []sqltypes.Value
+	for _, r := range qr.Rows {
+		from := r[:numFrom]
+		weights := r[numFrom : 2*numFrom]
+		hasNull := false
+		var key strings.Builder
+		for i, val := range from {
+			if val.IsNull() {
+				hasNull = true
+				break
+			}
+			// The weight string is NULL for types that don't have one.
+			weight := weights[i].RawStr()
+			if weights[i].IsNull() {
+				weight = val.ToString()
+			}
+			fmt.Fprintf(&key, "%d:%s", len(weight), weight)
+		}
+		if hasNull {
+			continue
+		}
+		rows = append(rows, &lookupVindexRow{key: key.String(), from: from})
+		last = append(last, r[2*numFrom])
+	}
+	return rows, last
+}
+
+// fetchQuery returns the query that reads the rows of the table that have
+// the from values of the given rows.
+func (v *lookupVindexVerifier) fetchQuery(table string, fromCols []string, lastCol string, keys []*lookupVindexRow) string {
+	var buf strings.Builder
+	buf.WriteString("select ")
+	v.writeSelectExprs(&buf, fromCols, lastCol)
+	buf.WriteString(" from ")
+	buf.WriteString(sqlescape.EscapeID(table))
+	buf.WriteString(" where ")
+	writeTuple(&buf, sqlescape.EscapeIDs(fromCols))
+	buf.WriteString(" in (")
+	seen := make(map[string]bool, len(keys))
+	for _, row := range keys {
+		if seen[row.key] {
+			continue
+		}
+		if len(seen) > 0 {
+			buf.WriteString(", ")
+		}
+		seen[row.key] = true
+		writeValueTuple(&buf, row.from)
+	}
+	buf.WriteString(")")
+	return buf.String()
+}
+
+func (v *lookupVindexVerifier) writeSelectExprs(buf *strings.Builder, fromCols []string, lastCol string) {
+	for _, col := range fromCols {
+		buf.WriteString(sqlescape.EscapeID(col))
+		buf.WriteString(", ")
+	}
+	for _, col := range fromCols {
+		fmt.Fprintf(buf, "weight_string(%s), ", sqlescape.EscapeID(col))
+	}
+	buf.WriteString(sqlescape.EscapeID(lastCol))
+}
+
+func (v *lookupVindexVerifier) insertQuery(diffs []*lookupVindexDiff) string {
+	var buf strings.Builder
+	buf.WriteString("insert ignore into ")
+	buf.WriteString(sqlescape.EscapeID(v.lookupTable))
+	buf.WriteString(" ")
+	writeTuple(&buf, sqlescape.EscapeIDs(append(append([]string{}, v.fromCols...), v.toCol)))
+	buf.WriteString(" values ")
+	for i, d := range diffs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeValueTuple(&buf, append(append([]sqltypes.Value{}, d.from...), sqltypes.MakeTrusted(sqltypes.VarBinary, d.ownerTo)))
+	}
+	return buf.String()
+}
+
+func (v *lookupVindexVerifier) deleteQuery(diffs []*lookupVindexDiff) string {
+	var buf strings.Builder
+	buf.WriteString("delete from ")
+	buf.WriteString(sqlescape.EscapeID(v.lookupTable))
+	buf.WriteString(" where ")
+	writeTuple(&buf, sqlescape.EscapeIDs(append(append([]string{}, v.fromCols...), v.toCol)))
+	buf.WriteString(" in (")
+	for i, d := range diffs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeValueTuple(&buf, append(append([]sqltypes.Value{}, d.from...), sqltypes.MakeTrusted(sqltypes.VarBinary, d.lookupTo)))
+	}
+	buf.WriteString(")")
+	return buf.String()
+}
+
+func (v *lookupVindexVerifier) updateQuery(d *lookupVindexDiff) string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "update %s set %s = ", sqlescape.EscapeID(v.lookupTable), sqlescape.EscapeID(v.toCol))
+	sqltypes.MakeTrusted(sqltypes.VarBinary, d.ownerTo).EncodeSQLStringBuilder(&buf)
+	buf.WriteString(" where ")
+	for i, col := range v.fromCols {
+		fmt.Fprintf(&buf, "%s = ", sqlescape.EscapeID(col))
+		d.from[i].EncodeSQLStringBuilder(&buf)
+		buf.WriteString(" and ")
+	}
+	fmt.Fprintf(&buf, "%s = ", sqlescape.EscapeID(v.toCol))
+	sqltypes.MakeTrusted(sqltypes.VarBinary, d.lookupTo).EncodeSQLStringBuilder(&buf)
+	return buf.String()
+}
+
+// writeTuple writes the expressions, in parentheses if there's more
+// than one.
+func writeTuple(buf *strings.Builder, exprs []string) {
+	if len(exprs) == 1 {
+		buf.WriteString(exprs[0])
+		return
+	}
+	buf.WriteString("(")
+	buf.WriteString(strings.Join(exprs, ", "))
+	buf.WriteString(")")
+}
+
+// writeValueTuple writes the values, in parentheses if there's more
+// than one.
+func writeValueTuple(buf *strings.Builder, values []sqltypes.Value) {
+	if len(values) > 1 {
+		buf.WriteString("(")
+	}
+	for i, val := range values {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		val.EncodeSQLStringBuilder(buf)
+	}
+	if len(values) > 1 {
+		buf.WriteString(")")
+	}
+}
+
+func (v *lookupVindexVerifier) execute(ctx context.Context, shard *lookupVindexVerifyShard, query string, maxRows int64) (*sqltypes.Result, error) {
+	qr, err := v.lv.tmc.ExecuteFetchAsApp(ctx, shard.tablet, false, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+		Query:   []byte(query),
+		MaxRows: uint64(maxRows),
+	})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to execute %q on tablet %s", query, topoproto.TabletAliasString(shard.tablet.Alias))
+	}
+	return sqltypes.Proto3ToResult(qr), nil
+}
+
+// waitForThrottler waits until the throttler of the shard's primary lets
+// us read or write the next batch. Failing to check the throttler is not
+// an error, as the throttler may not be enabled.
+func (v *lookupVindexVerifier) waitForThrottler(ctx context.Context, shard *lookupVindexVerifyShard) error {
+	for {
+		resp, err := v.lv.tmc.CheckThrottler(ctx, shard.tablet, &tabletmanagerdatapb.CheckThrottlerRequest{
+			AppName: throttlerapp.LookupVindexVerifyName.String(),
+		})
+		if err != nil {
+			v.lv.logger.Warningf("Failed to check the throttler on tablet %s: %v", topoproto.TabletAliasString(shard.tablet.Alias), err)
+			return nil
+		}
+		switch resp.ResponseCode {
+		case tabletmanagerdatapb.CheckThrottlerResponseCode_THRESHOLD_EXCEEDED, tabletmanagerdatapb.CheckThrottlerResponseCode_APP_DENIED:
+		default:
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lookupVindexVerifyThrottleWait):
+		}
+	}
+}
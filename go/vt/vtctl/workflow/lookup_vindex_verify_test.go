@@ -0,0 +1,407 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func setupLookupVindexVerifyEnv(t *testing.T, vindexType string) *testEnv {
+	ctx := t.Context()
+	env := newTestEnv(t, ctx, "cell", &testKeyspace{
+		KeyspaceName: "ks",
+		ShardNames:   []string{"0"},
+	}, &testKeyspace{
+		KeyspaceName: "lookupks",
+		ShardNames:   []string{"0"},
+	})
+	t.Cleanup(env.close)
+
+	err := env.ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+		Name: "ks",
+		Keyspace: &vschemapb.Keyspace{
+			Sharded: true,
+			Vindexes: map[string]*vschemapb.Vindex{
+				"hash": {
+					Type: "hash",
+				},
+				"c1_lookup": {
+					Type: vindexType,
+					Params: map[string]string{
+						"table": "lookupks.c1_lookup",
+						"from":  "c1",
+						"to":    "keyspace_id",
+					},
+					Owner: "t1",
+				},
+			},
+			Tables: map[string]*vschemapb.Table{
+				"t1": {
+					ColumnVindexes: []*vschemapb.ColumnVindex{{
+						Name:   "hash",
+						Column: "id",
+					}, {
+						Name:   "c1_lookup",
+						Column: "c1",
+					}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	err = env.ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+		Name:     "lookupks",
+		Keyspace: &vschemapb.Keyspace{},
+	})
+	require.NoError(t, err)
+
+	env.tmc.schema["ks.t1"] = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:              "t1",
+			PrimaryKeyColumns: []string{"id"},
+			Fields:            sqltypes.MakeTestFields("id|c1", "int64|varchar"),
+		}},
+	}
+	env.tmc.schema["lookupks.c1_lookup"] = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:              "c1_lookup",
+			PrimaryKeyColumns: []string{"c1"},
+			Fields:            sqltypes.MakeTestFields("c1|keyspace_id", "varchar|varbinary"),
+		}},
+	}
+	return env
+}
+
+// verifyTestKeyspaceID returns the keyspace id of an owner row with the
+// given id, and its SQL encoding.
+func verifyTestKeyspaceID(t *testing.T, id int64) ([]byte, string) {
+	vdx, err := vindexes.CreateVindex("hash", "hash", nil)
+	require.NoError(t, err)
+	dests, err := vdx.(vindexes.SingleColumn).Map(t.Context(), nil, []sqltypes.Value{sqltypes.NewInt64(id)})
+	require.NoError(t, err)
+	ksid := []byte(dests[0].(key.DestinationKeyspaceID))
+	var buf strings.Builder
+	sqltypes.MakeTrusted(sqltypes.VarBinary, ksid).EncodeSQLStringBuilder(&buf)
+	return ksid, buf.String()
+}
+
+func verifyTestResult(fields string, types string, rows ...[]sqltypes.Value) *sqltypes.Result {
+	return &sqltypes.Result{
+		Fields: sqltypes.MakeTestFields(fields, types),
+		Rows:   rows,
+	}
+}
+
+// expectVerifyTestVDiffShow expects a VDiff show request for the lookup
+// vindex workflow, answered with the given state and report of the lookup
+// table on the only shard of the lookup keyspace.
+func expectVerifyTestVDiffShow(t *testing.T, env *testEnv, uuid string, state vdiff.VDiffState, rowsCompared int64, report *vdiff.DiffReport) {
+	reportJSON, err := json.Marshal(report)
+	require.NoError(t, err)
+	completedAt := sqltypes.NULL
+	if state == vdiff.CompletedState {
+		completedAt = sqltypes.NewVarChar("2025-01-01 00:01:00")
+	}
+	result := verifyTestResult(
+		"vdiff_state|last_error|table_name|uuid|table_state|table_rows|started_at|rows_compared|completed_at|has_mismatch|report",
+		"varchar|varchar|varchar|varchar|varchar|int64|varchar|int64|varchar|int64|varchar",
+		[]sqltypes.Value{
+			sqltypes.NewVarChar(string(state)), sqltypes.NewVarChar(""), sqltypes.NewVarChar("c1_lookup"), sqltypes.NewVarChar(uuid),
+			sqltypes.NewVarChar(string(state)), sqltypes.NewInt64(4), sqltypes.NewVarChar("2025-01-01 00:00:00"), sqltypes.NewInt64(rowsCompared),
+			completedAt, sqltypes.NewInt64(1), sqltypes.NewVarChar(string(reportJSON)),
+		},
+	)
+	env.tmc.expectVDiffRequest(env.tablets["lookupks"][200], &vdiffRequestResponse{
+		req: &tabletmanagerdatapb.VDiffRequest{
+			Keyspace:  "lookupks",
+			Workflow:  "c1_lookup",
+			Action:    string(vdiff.ShowAction),
+			ActionArg: uuid,
+		},
+		res: &tabletmanagerdatapb.VDiffResponse{Output: sqltypes.ResultToProto3(result)},
+	})
+}
+
+func TestLookupVindexVerify(t *testing.T) {
+	env := setupLookupVindexVerifyEnv(t, "consistent_lookup_unique")
+
+	// Without a UUID, a VDiff of the lookup table is started in the workflow
+	// that backfilled the vindex, without syncing its streams.
+	resp, err := env.ws.LookupVindexVerify(t.Context(), &vtctldatapb.LookupVindexVerifyRequest{
+		Keyspace: "ks",
+		Name:     "c1_lookup",
+	})
+	require.NoError(t, err)
+	require.NoError(t, uuid.Validate(resp.Uuid))
+	require.Equal(t, string(vdiff.PendingState), resp.State)
+
+	req := env.tmc.receivedVDiffRequests[200]
+	require.NotNil(t, req)
+	require.Equal(t, string(vdiff.CreateAction), req.Action)
+	require.Equal(t, "lookupks", req.Keyspace)
+	require.Equal(t, "c1_lookup", req.Workflow)
+	require.Equal(t, resp.Uuid, req.VdiffUuid)
+	require.Equal(t, "c1_lookup", req.Options.CoreOptions.Tables)
+	require.True(t, req.Options.CoreOptions.NoStreamSync)
+	require.True(t, req.Options.CoreOptions.AutoRetry)
+	require.EqualValues(t, defaultLookupVindexVerifyMaxSampleRows, req.Options.CoreOptions.MaxExtraRowsToCompare)
+	require.EqualValues(t, defaultLookupVindexVerifyMaxSampleRows, req.Options.ReportOptions.MaxSampleRows)
+	require.Nil(t, env.tmc.receivedVDiffRequests[100])
+}
+
+func TestLookupVindexVerifyRepair(t *testing.T) {
+	env := setupLookupVindexVerifyEnv(t, "consistent_lookup_unique")
+	uuid := uuid.New().String()
+
+	ksid2, sqlKsid2 := verifyTestKeyspaceID(t, 2)
+	ksid3, sqlKsid3 := verifyTestKeyspaceID(t, 3)
+	ksid4, sqlKsid4 := verifyTestKeyspaceID(t, 4)
+	ksid5, _ := verifyTestKeyspaceID(t, 5)
+	hexKsid := func(ksid []byte) string {
+		return "0x" + hex.EncodeToString(ksid)
+	}
+
+	// The VDiff found that c and e are missing, d is orphaned and b is
+	// mismatched. The owner rows are reported with the select expressions of
+	// the workflow filter, and the lookup rows with the column names.
+	expectVerifyTestVDiffShow(t, env, uuid, vdiff.CompletedState, 5, &vdiff.DiffReport{
+		TableName:       "c1_lookup",
+		ProcessedRows:   5,
+		MatchingRows:    1,
+		MismatchedRows:  1,
+		ExtraRowsSource: 2,
+		ExtraRowsTarget: 1,
+		ExtraRowsSourceDiffs: []*vdiff.RowDiff{
+			{Row: map[string]string{"c1 as c1": "c", "keyspace_id() as keyspace_id": hexKsid(ksid3)}},
+			{Row: map[string]string{"c1 as c1": "e", "keyspace_id() as keyspace_id": hexKsid(ksid5)}},
+		},
+		ExtraRowsTargetDiffs: []*vdiff.RowDiff{
+			{Row: map[string]string{"c1": "d", "keyspace_id": hexKsid(ksid4)}},
+		},
+		MismatchedRowsDiffs: []*vdiff.DiffMismatch{{
+			Source: &vdiff.RowDiff{Row: map[string]string{"c1 as c1": "b", "keyspace_id() as keyspace_id": hexKsid(ksid2)}},
+			Target: &vdiff.RowDiff{Row: map[string]string{"c1": "b", "keyspace_id": hexKsid(ksid3)}},
+		}},
+	})
+
+	ownerRow := func(id int64, c1, weight string) []sqltypes.Value {
+		return []sqltypes.Value{sqltypes.NewVarChar(c1), sqltypes.NewVarBinary(weight), sqltypes.NewInt64(id)}
+	}
+	lookupRow := func(c1, weight string, ksid []byte) []sqltypes.Value {
+		return []sqltypes.Value{sqltypes.NewVarChar(c1), sqltypes.NewVarBinary(weight), sqltypes.MakeTrusted(sqltypes.VarBinary, ksid)}
+	}
+
+	// The rows are read again before being repaired. The owner row of e was
+	// deleted since the VDiff compared it, so it's left alone.
+	env.tmc.expectVRQuery(100, "select `c1`, weight_string(`c1`), `id` from `t1` where `c1` in ('c', 'e', 'd', 'b')",
+		verifyTestResult("c1|weight_string(c1)|id", "varchar|varbinary|int64",
+			ownerRow(2, "b", "B"), ownerRow(3, "c", "C")))
+	env.tmc.expectVRQuery(200, "select `c1`, weight_string(`c1`), `keyspace_id` from `c1_lookup` where `c1` in ('c', 'e', 'd', 'b')",
+		verifyTestResult("c1|weight_string(c1)|keyspace_id", "varchar|varbinary|varbinary",
+			lookupRow("b", "B", ksid3), lookupRow("d", "D", ksid4)))
+	env.tmc.expectVRQuery(200, "insert ignore into `c1_lookup` (`c1`, `keyspace_id`) values ('c', "+sqlKsid3+")",
+		&sqltypes.Result{RowsAffected: 1})
+	env.tmc.expectVRQuery(200, "delete from `c1_lookup` where (`c1`, `keyspace_id`) in (('d', "+sqlKsid4+"))",
+		&sqltypes.Result{RowsAffected: 1})
+	env.tmc.expectVRQuery(200, "update `c1_lookup` set `keyspace_id` = "+sqlKsid2+" where `c1` = 'b' and `keyspace_id` = "+sqlKsid3,
+		&sqltypes.Result{RowsAffected: 1})
+
+	resp, err := env.ws.LookupVindexVerify(t.Context(), &vtctldatapb.LookupVindexVerifyRequest{
+		Keyspace:  "ks",
+		Name:      "c1_lookup",
+		Uuid:      uuid,
+		Repair:    true,
+		BatchSize: 10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, env.tmc.vrQueries[100])
+	require.Empty(t, env.tmc.vrQueries[200])
+
+	want := &vtctldatapb.LookupVindexVerifyResponse{
+		Uuid:           uuid,
+		State:          string(vdiff.CompletedState),
+		RowsCompared:   5,
+		MissingRows:    2,
+		OrphanedRows:   1,
+		MismatchedRows: 1,
+		RepairedRows:   3,
+		MissingRowSamples: []*vtctldatapb.LookupVindexVerifyResponse_RowDiff{{
+			From:    []string{"c"},
+			OwnerTo: hexKsid(ksid3),
+		}, {
+			From:    []string{"e"},
+			OwnerTo: hexKsid(ksid5),
+		}},
+		OrphanedRowSamples: []*vtctldatapb.LookupVindexVerifyResponse_RowDiff{{
+			From:     []string{"d"},
+			LookupTo: hexKsid(ksid4),
+		}},
+		MismatchedRowSamples: []*vtctldatapb.LookupVindexVerifyResponse_RowDiff{{
+			From:     []string{"b"},
+			LookupTo: hexKsid(ksid3),
+			OwnerTo:  hexKsid(ksid2),
+		}},
+	}
+	require.EqualValues(t, want, resp)
+}
+
+func TestLookupVindexVerifyBatches(t *testing.T) {
+	env := setupLookupVindexVerifyEnv(t, "consistent_lookup")
+	uuid := uuid.New().String()
+
+	ksid1, _ := verifyTestKeyspaceID(t, 1)
+	ksid2, sqlKsid2 := verifyTestKeyspaceID(t, 2)
+	ksid3, sqlKsid3 := verifyTestKeyspaceID(t, 3)
+
+	// The vindex is not unique, so the owner rows (1, a) and (2, a) both need
+	// a lookup row, and the VDiff found that the second one is missing, as is
+	// the one of (3, b).
+	expectVerifyTestVDiffShow(t, env, uuid, vdiff.CompletedState, 3, &vdiff.DiffReport{
+		TableName:       "c1_lookup",
+		ProcessedRows:   3,
+		MatchingRows:    1,
+		ExtraRowsSource: 2,
+		ExtraRowsSourceDiffs: []*vdiff.RowDiff{
+			{Row: map[string]string{"c1 as c1": "a", "keyspace_id() as keyspace_id": "0x" + hex.EncodeToString(ksid2)}},
+			{Row: map[string]string{"c1 as c1": "b", "keyspace_id() as keyspace_id": "0x" + hex.EncodeToString(ksid3)}},
+		},
+	})
+
+	ownerRow := func(id int64, c1, weight string) []sqltypes.Value {
+		return []sqltypes.Value{sqltypes.NewVarChar(c1), sqltypes.NewVarBinary(weight), sqltypes.NewInt64(id)}
+	}
+	lookupRow := func(c1, weight string, ksid []byte) []sqltypes.Value {
+		return []sqltypes.Value{sqltypes.NewVarChar(c1), sqltypes.NewVarBinary(weight), sqltypes.MakeTrusted(sqltypes.VarBinary, ksid)}
+	}
+
+	// The rows are repaired one batch of from values at a time.
+	env.tmc.expectVRQuery(100, "select `c1`, weight_string(`c1`), `id` from `t1` where `c1` in ('a')",
+		verifyTestResult("c1|weight_string(c1)|id", "varchar|varbinary|int64", ownerRow(1, "a", "A"), ownerRow(2, "a", "A")))
+	env.tmc.expectVRQuery(200, "select `c1`, weight_string(`c1`), `keyspace_id` from `c1_lookup` where `c1` in ('a')",
+		verifyTestResult("c1|weight_string(c1)|keyspace_id", "varchar|varbinary|varbinary", lookupRow("a", "A", ksid1)))
+	env.tmc.expectVRQuery(200, "insert ignore into `c1_lookup` (`c1`, `keyspace_id`) values ('a', "+sqlKsid2+")",
+		&sqltypes.Result{RowsAffected: 1})
+	env.tmc.expectVRQuery(100, "select `c1`, weight_string(`c1`), `id` from `t1` where `c1` in ('b')",
+		verifyTestResult("c1|weight_string(c1)|id", "varchar|varbinary|int64", ownerRow(3, "b", "B")))
+	env.tmc.expectVRQuery(200, "select `c1`, weight_string(`c1`), `keyspace_id` from `c1_lookup` where `c1` in ('b')",
+		verifyTestResult("c1|weight_string(c1)|keyspace_id", "varchar|varbinary|varbinary"))
+	env.tmc.expectVRQuery(200, "insert ignore into `c1_lookup` (`c1`, `keyspace_id`) values ('b', "+sqlKsid3+")",
+		&sqltypes.Result{RowsAffected: 1})
+
+	resp, err := env.ws.LookupVindexVerify(t.Context(), &vtctldatapb.LookupVindexVerifyRequest{
+		Keyspace:  "ks",
+		Name:      "c1_lookup",
+		Uuid:      uuid,
+		Repair:    true,
+		BatchSize: 1,
+	})
+	require.NoError(t, err)
+	require.Empty(t, env.tmc.vrQueries[100])
+	require.Empty(t, env.tmc.vrQueries[200])
+	require.EqualValues(t, 2, resp.MissingRows)
+	require.EqualValues(t, 2, resp.RepairedRows)
+}
+
+func TestLookupVindexVerifyProgress(t *testing.T) {
+	env := setupLookupVindexVerifyEnv(t, "consistent_lookup_unique")
+	uuid := uuid.New().String()
+
+	report := &vdiff.DiffReport{TableName: "c1_lookup", ProcessedRows: 2, MatchingRows: 2}
+	expectVerifyTestVDiffShow(t, env, uuid, vdiff.StartedState, 2, report)
+	resp, err := env.ws.LookupVindexVerify(t.Context(), &vtctldatapb.LookupVindexVerifyRequest{
+		Keyspace: "ks",
+		Name:     "c1_lookup",
+		Uuid:     uuid,
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(vdiff.StartedState), resp.State)
+	require.EqualValues(t, 2, resp.RowsCompared)
+	require.Equal(t, 50.0, resp.ProgressPercentage)
+
+	// The lookup table is only repaired once the VDiff has completed.
+	expectVerifyTestVDiffShow(t, env, uuid, vdiff.StartedState, 2, report)
+	_, err = env.ws.LookupVindexVerify(t.Context(), &vtctldatapb.LookupVindexVerifyRequest{
+		Keyspace: "ks",
+		Name:     "c1_lookup",
+		Uuid:     uuid,
+		Repair:   true,
+	})
+	require.ErrorContains(t, err, "VDiff "+uuid+" is started, the lookup table can only be repaired once it has completed")
+}
+
+func TestLookupVindexVerifyFailures(t *testing.T) {
+	testcases := []struct {
+		name       string
+		vindexType string
+		req        *vtctldatapb.LookupVindexVerifyRequest
+		wantErr    string
+	}{
+		{
+			name:       "unknown vindex",
+			vindexType: "consistent_lookup_unique",
+			req:        &vtctldatapb.LookupVindexVerifyRequest{Keyspace: "ks", Name: "unknown"},
+			wantErr:    "vindex unknown not found in the ks keyspace",
+		},
+		{
+			name:       "unsupported vindex type",
+			vindexType: "lookup_hash_unique",
+			req:        &vtctldatapb.LookupVindexVerifyRequest{Keyspace: "ks", Name: "c1_lookup"},
+			wantErr:    "vindex c1_lookup has type lookup_hash_unique, only lookup vindexes that store the keyspace id can be verified",
+		},
+		{
+			name:       "wrong table keyspace",
+			vindexType: "consistent_lookup_unique",
+			req:        &vtctldatapb.LookupVindexVerifyRequest{Keyspace: "ks", Name: "c1_lookup", TableKeyspace: "ks"},
+			wantErr:    "vindex c1_lookup uses a lookup table in the lookupks keyspace, not in the ks keyspace",
+		},
+		{
+			name:       "repair without a VDiff",
+			vindexType: "consistent_lookup_unique",
+			req:        &vtctldatapb.LookupVindexVerifyRequest{Keyspace: "ks", Name: "c1_lookup", Repair: true},
+			wantErr:    "the UUID of a completed VDiff is required to repair the lookup table",
+		},
+		{
+			name:       "unsharded keyspace",
+			vindexType: "consistent_lookup_unique",
+			req:        &vtctldatapb.LookupVindexVerifyRequest{Keyspace: "lookupks", Name: "c1_lookup"},
+			wantErr:    "keyspace lookupks is not sharded",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := setupLookupVindexVerifyEnv(t, tc.vindexType)
+			_, err := env.ws.LookupVindexVerify(t.Context(), tc.req)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
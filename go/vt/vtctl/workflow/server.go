@@ -813,6 +813,34 @@ func (s *Server) LookupVindexInternalize(ctx context.Context, req *vtctldatapb.L
 	return resp, s.ts.RebuildSrvVSchema(ctx, nil)
 }
 
+// LookupVindexVerify compares the owner table of a lookup vindex with its
+// lookup table using a VDiff of the workflow that backfilled the vindex.
+// Without a UUID, the VDiff is started. With the UUID of the VDiff, its
+// progress and the rows that are missing from, orphaned in, or mismatched in
+// the lookup table are reported, and the lookup table is repaired if requested.
+func (s *Server) LookupVindexVerify(ctx context.Context, req *vtctldatapb.LookupVindexVerifyRequest) (*vtctldatapb.LookupVindexVerifyResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.LookupVindexVerify")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("name", req.Name)
+	span.Annotate("table_keyspace", req.TableKeyspace)
+	span.Annotate("uuid", req.Uuid)
+	span.Annotate("repair", req.Repair)
+
+	v, err := newLookupVindexVerifier(ctx, newLookupVindex(s), req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Uuid == "" {
+		if req.Repair {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the UUID of a completed VDiff is required to repair the lookup table")
+		}
+		return v.startVDiff(ctx, s)
+	}
+	return v.showVDiff(ctx, s, req.Uuid)
+}
+
 // Materialize performs the steps needed to materialize a list of
 // tables based on the materialization specs.
 func (s *Server) Materialize(ctx context.Context, ms *vtctldatapb.MaterializeSettings) error {
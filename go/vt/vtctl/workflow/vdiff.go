@@ -358,6 +358,13 @@ func BuildProgressReport(rowsCompared int64, rowsToCompare int64, startedAt stri
 // It passes on the request to the target primary tablets that are
 // participating in the given workflow and VDiff.
 func (s *Server) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRequest) (*vtctldatapb.VDiffCreateResponse, error) {
+	return s.vdiffCreate(ctx, req, false)
+}
+
+// vdiffCreate creates a VDiff for the workflow. With noStreamSync, the workflow
+// doesn't need to be running and the data is compared without syncing its
+// streams, as it no longer maintains the target.
+func (s *Server) vdiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRequest, noStreamSync bool) (*vtctldatapb.VDiffCreateResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "workflow.Server.VDiffCreate")
 	defer span.Finish()
 
@@ -427,6 +434,7 @@ func (s *Server) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRe
 			UpdateTableStats:      req.UpdateTableStats,
 			MaxDiffSeconds:        req.MaxDiffDuration.Seconds,
 			AutoStart:             &autoStart,
+			NoStreamSync:          noStreamSync,
 		},
 		ReportOptions: &tabletmanagerdatapb.VDiffReportOptions{
 			OnlyPks:                 req.OnlyPKs,
@@ -453,14 +461,16 @@ func (s *Server) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRe
 			req.TargetKeyspace, req.Workflow)
 	}
 
-	workflowStatus, err := s.getWorkflowStatus(ctx, req.TargetKeyspace, req.Workflow)
-	if err != nil {
-		return nil, err
-	}
-	if workflowStatus != binlogdatapb.VReplicationWorkflowState_Running {
-		s.Logger().Infof("Workflow %s.%s is not running, cannot start VDiff in state %s", req.TargetKeyspace, req.Workflow, workflowStatus)
-		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION,
-			"not all streams are running in workflow %s.%s", req.TargetKeyspace, req.Workflow)
+	if !noStreamSync {
+		workflowStatus, err := s.getWorkflowStatus(ctx, req.TargetKeyspace, req.Workflow)
+		if err != nil {
+			return nil, err
+		}
+		if workflowStatus != binlogdatapb.VReplicationWorkflowState_Running {
+			s.Logger().Infof("Workflow %s.%s is not running, cannot start VDiff in state %s", req.TargetKeyspace, req.Workflow, workflowStatus)
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION,
+				"not all streams are running in workflow %s.%s", req.TargetKeyspace, req.Workflow)
+		}
 	}
 
 	err = ts.ForAllTargets(func(target *MigrationTarget) error {
@@ -98,6 +98,7 @@ const (
 	ModePreferCell
 	ModeRandom
 	ModeSession
+	ModeLeastLoaded
 )
 
 func ParseMode(ms string) Mode {
@@ -110,6 +111,8 @@ func ParseMode(ms string) Mode {
 		return ModeRandom
 	case "session":
 		return ModeSession
+	case "least-loaded":
+		return ModeLeastLoaded
 	default:
 		return ModeInvalid
 	}
@@ -125,13 +128,15 @@ func (m Mode) String() string {
 		return "random"
 	case ModeSession:
 		return "session"
+	case ModeLeastLoaded:
+		return "least-loaded"
 	default:
 		return "invalid"
 	}
 }
 
 func GetAvailableModeNames() []string {
-	return []string{ModeCell.String(), ModePreferCell.String(), ModeRandom.String(), ModeSession.String()}
+	return []string{ModeCell.String(), ModePreferCell.String(), ModeRandom.String(), ModeSession.String(), ModeLeastLoaded.String()}
 }
 
 type TabletBalancer interface {
@@ -149,6 +154,7 @@ type TabletBalancer interface {
 //   - See the RFC here: https://github.com/vitessio/vitess/issues/12241
 //   - "random": Random balancer that uniformly distributes load without cell affinity
 //   - "session": Session balancer that pins a session to the same tablet for the duration of the session. If the tablet goes away, the session is automatically and transparently migrated to another tablet of the same type.
+//   - "least-loaded": Picks between two random tablets the one with the fewest in-flight queries and lowest latency
 //
 // Note: "cell" mode is handled by the gateway and does not create a balancer instance.
 // operates as a round robin inside of the vtgate's cell
@@ -161,6 +167,8 @@ func NewTabletBalancer(mode Mode, localCell string, vtGateCells []string) (Table
 		return newRandomBalancer(localCell, vtGateCells), nil
 	case ModeSession:
 		return newSessionBalancer(localCell), nil
+	case ModeLeastLoaded:
+		return newLeastLoadedBalancer(localCell, vtGateCells), nil
 	case ModeCell:
 		return nil, errors.New("cell mode should be handled by the gateway, not the balancer factory")
 	default:
@@ -0,0 +1,301 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+/*
+
+The leastLoadedBalancer routes queries away from tablets that are busy or slow.
+
+For every tablet it keeps track of the number of queries currently in flight and
+an exponentially weighted moving average (EWMA) of the observed response latency.
+Both are fed back by the gateway through the LoadTracker interface.
+
+A tablet's cost is its latency EWMA multiplied by the number of in-flight queries
+plus one, so that a tablet that is fast but already saturated and a tablet that is
+idle but slow are both penalized. To pick a tablet we use "power of two choices":
+two distinct tablets are sampled at random and the one with the lower cost wins.
+This avoids the herding behaviour of always picking the global minimum, while still
+shifting the bulk of the traffic away from a degraded replica.
+
+A query that fails because of the tablet is accounted as taking at least
+leastLoadedErrorLatency. Otherwise a broken tablet that fails every query right away
+would look like the fastest one, and attract more and more of the traffic.
+
+The latency EWMA of a tablet decays towards zero while the tablet does not receive
+any traffic. That way a tablet which was slow in the past, and therefore stopped
+being picked, is eventually probed again and can win its traffic back once it has
+recovered.
+
+As with the random balancer, the tablets can optionally be filtered to those in the
+given vtGateCells.
+
+*/
+
+const (
+	// leastLoadedDecayWindow is the time constant used for the latency EWMA. A latency
+	// observed this long ago weighs 1/e as much as one observed right now.
+	leastLoadedDecayWindow = 10 * time.Second
+
+	// leastLoadedPruneInterval is how often we forget about tablets that have not been
+	// seen in any Pick call. Tablets come and go, we don't want to track them forever.
+	leastLoadedPruneInterval = 5 * time.Minute
+
+	// leastLoadedErrorLatency is the minimum latency accounted for a query that failed
+	// because of the tablet.
+	leastLoadedErrorLatency = time.Second
+)
+
+// LoadTracker is implemented by balancers that need to be told about the queries
+// sent to the tablets they picked. The gateway calls QueryStarted right before a
+// query is sent to a tablet, and QueryFinished once it has returned.
+type LoadTracker interface {
+	QueryStarted(tablet *discovery.TabletHealth)
+	QueryFinished(tablet *discovery.TabletHealth, latency time.Duration, err error)
+}
+
+// tabletLoad is the load information tracked for a single tablet.
+type tabletLoad struct {
+	// Alias of the tablet, only used for the debug output.
+	Alias string
+
+	// InFlight is the number of queries currently being executed on the tablet.
+	InFlight int
+
+	// Latency is the EWMA of the response latency of the tablet.
+	Latency time.Duration
+
+	// Queries is the total number of queries that finished on the tablet.
+	Queries int64
+
+	// Errors is the total number of queries that finished with an error.
+	Errors int64
+
+	// lastUpdate is the last time Latency was updated.
+	lastUpdate time.Time
+
+	// lastSeen is the last time the tablet was part of a Pick call.
+	lastSeen time.Time
+}
+
+// cost returns the cost of sending one more query to the tablet.
+func (l *tabletLoad) cost(now time.Time) float64 {
+	return l.decayedLatency(now) * float64(l.InFlight+1)
+}
+
+// decayedLatency returns the latency EWMA in nanoseconds, decayed for the time
+// elapsed since it was last updated.
+func (l *tabletLoad) decayedLatency(now time.Time) float64 {
+	if l.lastUpdate.IsZero() {
+		return 0
+	}
+	elapsed := now.Sub(l.lastUpdate)
+	if elapsed <= 0 {
+		return float64(l.Latency)
+	}
+	return float64(l.Latency) * math.Exp(-float64(elapsed)/float64(leastLoadedDecayWindow))
+}
+
+// observe folds a new latency sample into the EWMA.
+func (l *tabletLoad) observe(now time.Time, latency time.Duration) {
+	if l.lastUpdate.IsZero() {
+		l.Latency = latency
+		l.lastUpdate = now
+		return
+	}
+	elapsed := max(now.Sub(l.lastUpdate), 0)
+	// The weight of the previous average depends on how long ago it was computed,
+	// so that a burst of queries does not wipe out the history instantly, and a
+	// single query after a long pause is not dominated by stale data.
+	w := math.Exp(-float64(elapsed) / float64(leastLoadedDecayWindow))
+	l.Latency = time.Duration(float64(l.Latency)*w + float64(latency)*(1-w))
+	l.lastUpdate = now
+}
+
+func newLeastLoadedBalancer(localCell string, vtGateCells []string) TabletBalancer {
+	cellsMap := make(map[string]struct{}, len(vtGateCells))
+	for _, cell := range vtGateCells {
+		cellsMap[cell] = struct{}{}
+	}
+
+	return &leastLoadedBalancer{
+		localCell:      localCell,
+		vtGateCells:    vtGateCells,
+		vtGateCellsMap: cellsMap,
+		loads:          map[string]*tabletLoad{},
+		now:            time.Now,
+	}
+}
+
+type leastLoadedBalancer struct {
+	// The local cell for the vtgate (used for debugging/logging only)
+	localCell string
+
+	// Optional list of cells to filter tablets to. If empty, all tablets are considered.
+	vtGateCells []string
+
+	// Map of vtGateCells for O(1) lookup performance. Initialized from vtGateCells.
+	vtGateCellsMap map[string]struct{}
+
+	// mu protects loads and lastPrune
+	mu sync.Mutex
+
+	// loads holds the tracked load of each tablet, keyed by tablet alias.
+	loads map[string]*tabletLoad
+
+	// lastPrune is the last time loads was pruned of tablets that went away.
+	lastPrune time.Time
+
+	// now returns the current time, it's overridden in tests.
+	now func() time.Time
+}
+
+// Pick samples two tablets at random and returns the one with the lowest cost.
+// If vtGateCells is configured, only tablets in those cells are considered.
+func (b *leastLoadedBalancer) Pick(target *querypb.Target, tablets []*discovery.TabletHealth, _ ...PickOption) *discovery.TabletHealth {
+	if len(b.vtGateCells) > 0 {
+		filtered := make([]*discovery.TabletHealth, 0, len(tablets))
+		for _, tablet := range tablets {
+			if _, ok := b.vtGateCellsMap[tablet.Tablet.Alias.Cell]; ok {
+				filtered = append(filtered, tablet)
+			}
+		}
+		tablets = filtered
+	}
+
+	switch len(tablets) {
+	case 0:
+		return nil
+	case 1:
+		return tablets[0]
+	}
+
+	i := rand.IntN(len(tablets))
+	j := rand.IntN(len(tablets) - 1)
+	if j >= i {
+		j++
+	}
+	a, c := tablets[i], tablets[j]
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	la, lc := b.getLoadLocked(a), b.getLoadLocked(c)
+	la.lastSeen, lc.lastSeen = now, now
+	b.maybePruneLocked(now)
+
+	if lc.cost(now) < la.cost(now) {
+		return c
+	}
+	return a
+}
+
+// QueryStarted is part of the LoadTracker interface.
+func (b *leastLoadedBalancer) QueryStarted(tablet *discovery.TabletHealth) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.getLoadLocked(tablet).InFlight++
+}
+
+// QueryFinished is part of the LoadTracker interface.
+func (b *leastLoadedBalancer) QueryFinished(tablet *discovery.TabletHealth, latency time.Duration, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	load := b.getLoadLocked(tablet)
+	if load.InFlight > 0 {
+		load.InFlight--
+	}
+	load.Queries++
+	if err != nil {
+		load.Errors++
+		if isTabletError(err) {
+			latency = max(latency, leastLoadedErrorLatency)
+		}
+	}
+	load.observe(b.now(), latency)
+}
+
+// isTabletError returns true if the error is attributed to the tablet, rather than to the
+// query itself (e.g. a syntax error or a duplicate key) or to the client cancelling it.
+func isTabletError(err error) bool {
+	switch vterrors.Code(err) {
+	case vtrpcpb.Code_OK, vtrpcpb.Code_INVALID_ARGUMENT, vtrpcpb.Code_ALREADY_EXISTS, vtrpcpb.Code_NOT_FOUND, vtrpcpb.Code_CANCELED:
+		return false
+	}
+	return true
+}
+
+// getLoadLocked returns the load of the given tablet, creating it if needed.
+// Must be called with mu held.
+func (b *leastLoadedBalancer) getLoadLocked(tablet *discovery.TabletHealth) *tabletLoad {
+	alias := tabletAlias(tablet)
+	load, ok := b.loads[alias]
+	if !ok {
+		load = &tabletLoad{Alias: alias, lastSeen: b.now()}
+		b.loads[alias] = load
+	}
+	return load
+}
+
+// maybePruneLocked removes the tablets that have not been picked from for a while
+// and have no queries in flight. Must be called with mu held.
+func (b *leastLoadedBalancer) maybePruneLocked(now time.Time) {
+	if now.Sub(b.lastPrune) < leastLoadedPruneInterval {
+		return
+	}
+	b.lastPrune = now
+	for alias, load := range b.loads {
+		if load.InFlight == 0 && now.Sub(load.lastSeen) >= leastLoadedPruneInterval {
+			delete(b.loads, alias)
+		}
+	}
+}
+
+func (b *leastLoadedBalancer) DebugHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintf(w, "Balancer Mode: least-loaded\r\n")
+	fmt.Fprintf(w, "Local Cell: %v\r\n", b.localCell)
+	if len(b.vtGateCells) > 0 {
+		fmt.Fprintf(w, "Filtered to Cells: %v\r\n", b.vtGateCells)
+	} else {
+		fmt.Fprintf(w, "Cells: all (no filter)\r\n")
+	}
+	fmt.Fprintf(w, "Strategy: Power of two choices over in-flight queries and latency EWMA (decay window %v)\r\n", leastLoadedDecayWindow)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	loads, _ := json.MarshalIndent(b.loads, "", "  ")
+	fmt.Fprintf(w, "Tablets: %v\r\n", string(loads))
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancer
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// newTestLeastLoadedBalancer returns a least-loaded balancer with a fake clock.
+func newTestLeastLoadedBalancer(vtGateCells []string) (*leastLoadedBalancer, *time.Time) {
+	b := newLeastLoadedBalancer("cell1", vtGateCells).(*leastLoadedBalancer)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestLeastLoadedBalancerFactory(t *testing.T) {
+	assert.Equal(t, ModeLeastLoaded, ParseMode("least-loaded"))
+	assert.Equal(t, "least-loaded", ModeLeastLoaded.String())
+	assert.Contains(t, GetAvailableModeNames(), "least-loaded")
+
+	b, err := NewTabletBalancer(ModeLeastLoaded, "cell1", nil)
+	require.NoError(t, err)
+
+	_, ok := b.(*leastLoadedBalancer)
+	assert.True(t, ok, "factory should create a leastLoadedBalancer")
+	_, ok = b.(LoadTracker)
+	assert.True(t, ok, "leastLoadedBalancer should implement LoadTracker")
+}
+
+func TestLeastLoadedBalancerPickEmptyAndSingle(t *testing.T) {
+	target := &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA}
+	b, _ := newTestLeastLoadedBalancer(nil)
+
+	assert.Nil(t, b.Pick(target, nil))
+
+	tablets := []*discovery.TabletHealth{createTestTablet("cell1")}
+	assert.Equal(t, tablets[0], b.Pick(target, tablets))
+}
+
+func TestLeastLoadedBalancerUniformWithoutFeedback(t *testing.T) {
+	tablets := []*discovery.TabletHealth{
+		createTestTablet("cell1"),
+		createTestTablet("cell1"),
+		createTestTablet("cell2"),
+	}
+	target := &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA}
+	b, _ := newTestLeastLoadedBalancer(nil)
+
+	const numPicks = 30000
+	pickCounts := make(map[uint32]int)
+	for range numPicks {
+		pickCounts[b.Pick(target, tablets).Tablet.Alias.Uid]++
+	}
+
+	for _, tablet := range tablets {
+		assert.InEpsilon(t, numPicks/len(tablets), pickCounts[tablet.Tablet.Alias.Uid], 0.05)
+	}
+}
+
+func TestLeastLoadedBalancerAvoidsSlowTablet(t *testing.T) {
+	tablets := []*discovery.TabletHealth{
+		createTestTablet("cell1"),
+		createTestTablet("cell1"),
+		createTestTablet("cell2"),
+	}
+	slow := tablets[2]
+	target := &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA}
+	b, now := newTestLeastLoadedBalancer(nil)
+
+	b.QueryFinished(tablets[0], time.Millisecond, nil)
+	b.QueryFinished(tablets[1], time.Millisecond, nil)
+	b.QueryFinished(slow, 100*time.Millisecond, nil)
+
+	// With two choices out of three tablets, the slow tablet can only win when
+	// both samples are the slow tablet, which can't happen.
+	for range 1000 {
+		assert.NotEqual(t, slow, b.Pick(target, tablets))
+	}
+
+	// Once the latency has decayed long enough, the slow tablet gets probed again.
+	*now = now.Add(time.Minute)
+	b.QueryFinished(tablets[0], time.Millisecond, nil)
+	b.QueryFinished(tablets[1], time.Millisecond, nil)
+	*now = now.Add(time.Minute)
+	picked := false
+	for range 1000 {
+		if b.Pick(target, tablets) == slow {
+			picked = true
+			break
+		}
+	}
+	assert.True(t, picked, "slow tablet should eventually be picked again")
+}
+
+func TestLeastLoadedBalancerAvoidsBusyTablet(t *testing.T) {
+	tablets := []*discovery.TabletHealth{
+		createTestTablet("cell1"),
+		createTestTablet("cell1"),
+	}
+	target := &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA}
+	b, _ := newTestLeastLoadedBalancer(nil)
+
+	// Same latency, but tablets[0] has a bunch of queries in flight.
+	b.QueryFinished(tablets[0], 10*time.Millisecond, nil)
+	b.QueryFinished(tablets[1], 10*time.Millisecond, nil)
+	for range 5 {
+		b.QueryStarted(tablets[0])
+	}
+
+	for range 100 {
+		assert.Equal(t, tablets[1], b.Pick(target, tablets))
+	}
+
+	// Once the queries finish the load evens out.
+	for range 5 {
+		b.QueryFinished(tablets[0], 10*time.Millisecond, nil)
+	}
+	load := b.loads[tabletAlias(tablets[0])]
+	assert.Equal(t, 0, load.InFlight)
+	assert.EqualValues(t, 6, load.Queries)
+}
+
+func TestLeastLoadedBalancerCellFiltering(t *testing.T) {
+	tablets := []*discovery.TabletHealth{
+		createTestTablet("cell1"),
+		createTestTablet("cell2"),
+		createTestTablet("cell3"),
+	}
+	target := &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA}
+	b, _ := newTestLeastLoadedBalancer([]string{"cell1", "cell2"})
+
+	for range 1000 {
+		th := b.Pick(target, tablets)
+		require.NotNil(t, th)
+		assert.NotEqual(t, "cell3", th.Tablet.Alias.Cell)
+	}
+
+	assert.Nil(t, b.Pick(target, tablets[2:]))
+}
+
+func TestLeastLoadedBalancerEWMA(t *testing.T) {
+	tablet := createTestTablet("cell1")
+	b, now := newTestLeastLoadedBalancer(nil)
+
+	// An error caused by the query itself does not penalize the tablet.
+	b.QueryFinished(tablet, 100*time.Millisecond, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "syntax error"))
+	load := b.loads[tabletAlias(tablet)]
+	assert.Equal(t, 100*time.Millisecond, load.Latency)
+	assert.EqualValues(t, 1, load.Errors)
+
+	// A sample one decay window later weighs 1-1/e.
+	*now = now.Add(leastLoadedDecayWindow)
+	b.QueryFinished(tablet, 0, nil)
+	assert.InDelta(t, float64(100*time.Millisecond)/2.718281828, float64(load.Latency), float64(time.Millisecond))
+
+	// The in-flight counter never goes negative.
+	assert.Equal(t, 0, load.InFlight)
+}
+
+func TestLeastLoadedBalancerAvoidsFailingTablet(t *testing.T) {
+	tablets := []*discovery.TabletHealth{
+		createTestTablet("cell1"),
+		createTestTablet("cell1"),
+		createTestTablet("cell2"),
+	}
+	failing := tablets[2]
+	target := &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA}
+	b, _ := newTestLeastLoadedBalancer(nil)
+
+	b.QueryFinished(tablets[0], 10*time.Millisecond, nil)
+	b.QueryFinished(tablets[1], 10*time.Millisecond, nil)
+	// The failing tablet answers much faster than the healthy ones.
+	b.QueryFinished(failing, time.Microsecond, errors.New("oops"))
+	b.QueryFinished(failing, time.Microsecond, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "unavailable"))
+
+	load := b.loads[tabletAlias(failing)]
+	assert.Equal(t, leastLoadedErrorLatency, load.Latency)
+	assert.EqualValues(t, 2, load.Errors)
+	for range 1000 {
+		assert.NotEqual(t, failing, b.Pick(target, tablets))
+	}
+}
+
+func TestLeastLoadedBalancerPrune(t *testing.T) {
+	tablets := []*discovery.TabletHealth{
+		createTestTablet("cell1"),
+		createTestTablet("cell1"),
+	}
+	gone := createTestTablet("cell1")
+	target := &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA}
+	b, now := newTestLeastLoadedBalancer(nil)
+
+	b.QueryFinished(gone, time.Millisecond, nil)
+	b.Pick(target, tablets)
+	require.Len(t, b.loads, 3)
+
+	*now = now.Add(leastLoadedPruneInterval)
+	b.Pick(target, tablets)
+	assert.Len(t, b.loads, 2)
+	assert.NotContains(t, b.loads, tabletAlias(gone))
+}
+
+func TestLeastLoadedBalancerDebugHandler(t *testing.T) {
+	tablet := createTestTablet("cell1")
+	b, _ := newTestLeastLoadedBalancer([]string{"cell1"})
+	b.QueryStarted(tablet)
+
+	w := httptest.NewRecorder()
+	b.DebugHandler(w, httptest.NewRequest("GET", "/debug/balancer", nil))
+
+	body := w.Body.String()
+	assert.Contains(t, body, "Balancer Mode: least-loaded")
+	assert.Contains(t, body, "Filtered to Cells: [cell1]")
+	assert.Contains(t, body, tabletAlias(tablet))
+	assert.Contains(t, body, `"InFlight": 1`)
+}
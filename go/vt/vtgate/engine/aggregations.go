@@ -18,13 +18,19 @@ package engine
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/collations/charset"
+	"vitess.io/vitess/go/mysql/collations/colldata"
+	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/sqltypes"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
@@ -47,6 +53,20 @@ type AggregateParams struct {
 	WCol   int
 	Type   evalengine.Type
 
+	// HashDistinct is used only for distinct opcodes. It is set when the input
+	// is not sorted by the distinct column, so the values already seen in the
+	// group have to be remembered instead of only comparing consecutive values.
+	HashDistinct bool
+
+	// GroupConcat is used only for the group_concat opcode. It is set when the
+	// GROUP_CONCAT is computed from the individual values of the group instead
+	// of concatenating the values already concatenated by the shards.
+	GroupConcat *GroupConcatParams
+
+	// GroupingKeys is used only for the grouping opcode. It holds the
+	// index in the grouping keys of each argument of the GROUPING() call.
+	GroupingKeys []int
+
 	Alias    string
 	Func     sqlparser.AggrFunc
 	Original *sqlparser.AliasedExpr
@@ -83,6 +103,36 @@ func NewAggregateParam(
 	return out
 }
 
+// GroupConcatParams specify how to compute a GROUP_CONCAT from the individual values of a group.
+type GroupConcatParams struct {
+	// Cols are the input columns holding the arguments of the GROUP_CONCAT
+	Cols []int
+	// OrderBy is used to sort the values of a group before concatenating them
+	OrderBy evalengine.Comparison
+}
+
+func (gc *GroupConcatParams) String(distinct bool) string {
+	var buf strings.Builder
+	if distinct {
+		buf.WriteString("DISTINCT ")
+	}
+	for i, col := range gc.Cols {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(strconv.Itoa(col))
+	}
+	for i, order := range gc.OrderBy {
+		if i == 0 {
+			buf.WriteString(" ORDER BY ")
+		} else {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(order.String())
+	}
+	return buf.String()
+}
+
 func (ap *AggregateParams) WAssigned() bool {
 	return ap.WCol >= 0
 }
@@ -98,6 +148,12 @@ func (ap *AggregateParams) String() string {
 	if sqltypes.IsText(ap.Type.Type()) && ap.CollationEnv.IsSupported(ap.Type.Collation()) {
 		keyCol += " COLLATE " + ap.CollationEnv.LookupName(ap.Type.Collation())
 	}
+	if ap.HashDistinct {
+		keyCol += " USING HASH"
+	}
+	if ap.GroupConcat != nil {
+		keyCol = ap.GroupConcat.String(sqlparser.IsDistinct(ap.Func))
+	}
 	dispOrigOp := ""
 	if ap.OrigOpcode != opcode.AggregateUnassigned && ap.OrigOpcode != ap.Opcode {
 		dispOrigOp = "_" + ap.OrigOpcode.String()
@@ -134,9 +190,23 @@ type aggregatorDistinct struct {
 	coll         collations.ID
 	collationEnv *collations.Environment
 	values       *evalengine.EnumSetValues
+
+	// seen remembers all the values of the group when the input is not sorted by the distinct column
+	seen   *probeTable
+	memory *aggregationMemory
 }
 
 func (a *aggregatorDistinct) shouldReturn(row []sqltypes.Value) (bool, error) {
+	if a.seen != nil {
+		unseen, err := a.seen.exists(row)
+		if unseen == nil {
+			return true, err
+		}
+		if err := a.memory.reserve(seenValueSize); err != nil {
+			return true, err
+		}
+		return false, nil
+	}
 	if a.column >= 0 {
 		last := a.last
 		next := row[a.column]
@@ -158,6 +228,9 @@ func (a *aggregatorDistinct) shouldReturn(row []sqltypes.Value) (bool, error) {
 
 func (a *aggregatorDistinct) reset() {
 	a.last = sqltypes.NULL
+	if a.seen != nil {
+		clear(a.seen.seenRows)
+	}
 }
 
 type aggregatorCount struct {
@@ -303,16 +376,37 @@ func eval(env *evalengine.ExpressionEnv, eexpr evalengine.Expr, coll collations.
 
 func (*aggregatorConstant) reset() {}
 
+// aggregatorGrouping produces the value of GROUPING() for a row that
+// is not a super-aggregate row. The rollup rows get their value from
+// the OrderedAggregate producing them.
+type aggregatorGrouping struct{}
+
+func (*aggregatorGrouping) add([]sqltypes.Value) error {
+	return nil
+}
+
+func (*aggregatorGrouping) finish(*evalengine.ExpressionEnv, collations.ID) (sqltypes.Value, error) {
+	return sqltypes.NewInt64(0), nil
+}
+
+func (*aggregatorGrouping) reset() {}
+
 type aggregatorGroupConcat struct {
 	from      int
 	type_     sqltypes.Type
 	separator []byte
+	limit     *groupConcatLimit
+	charset   charset.Charset
 
-	concat []byte
-	n      int
+	concat    []byte
+	n         int
+	truncated bool
 }
 
 func (a *aggregatorGroupConcat) add(row []sqltypes.Value) error {
+	if a.truncated {
+		return nil
+	}
 	if row[a.from].IsNull() {
 		return nil
 	}
@@ -321,6 +415,7 @@ func (a *aggregatorGroupConcat) add(row []sqltypes.Value) error {
 	}
 	a.concat = append(a.concat, row[a.from].Raw()...)
 	a.n++
+	a.concat, a.truncated = a.limit.truncate(a.charset, a.concat, a.n)
 	return nil
 }
 
@@ -334,6 +429,152 @@ func (a *aggregatorGroupConcat) finish(*evalengine.ExpressionEnv, collations.ID)
 func (a *aggregatorGroupConcat) reset() {
 	a.n = 0
 	a.concat = nil // not safe to reuse this byte slice as it's returned as MakeTrusted
+	a.truncated = false
+}
+
+// aggregatorGroupConcatRows computes a GROUP_CONCAT from the individual rows of the group,
+// which is needed when the values have to be deduplicated or sorted, or when there are
+// multiple arguments to concatenate for each row.
+type aggregatorGroupConcatRows struct {
+	cols      []int
+	orderBy   evalengine.Comparison
+	distinct  *probeTable
+	type_     sqltypes.Type
+	separator []byte
+	limit     *groupConcatLimit
+	charset   charset.Charset
+	memory    *aggregationMemory
+
+	rows []sqltypes.Row
+	// size is the length of the result with the rows kept so far
+	size int
+}
+
+func (a *aggregatorGroupConcatRows) add(row []sqltypes.Value) error {
+	for _, col := range a.cols {
+		// like MySQL, we skip the rows where any of the arguments is NULL
+		if row[col].IsNull() {
+			return nil
+		}
+	}
+	if len(a.orderBy) == 0 && a.limit != nil && a.limit.maxLen > 0 && a.size > a.limit.maxLen {
+		// the rows are concatenated in the order they are added, so the result
+		// is already cut before this row and there is no need to keep it
+		return nil
+	}
+	size := rowSize(row)
+	if a.distinct != nil {
+		unseen, err := a.distinct.exists(row)
+		if unseen == nil {
+			return err
+		}
+		size += seenValueSize
+	}
+	if err := a.memory.reserve(size); err != nil {
+		return err
+	}
+	if len(a.rows) > 0 {
+		a.size += len(a.separator)
+	}
+	for _, col := range a.cols {
+		a.size += len(row[col].Raw())
+	}
+	a.rows = append(a.rows, row)
+	return nil
+}
+
+func (a *aggregatorGroupConcatRows) finish(*evalengine.ExpressionEnv, collations.ID) (_ sqltypes.Value, err error) {
+	if len(a.rows) == 0 {
+		return sqltypes.NULL, nil
+	}
+	if len(a.orderBy) > 0 {
+		defer evalengine.PanicHandler(&err)
+		slices.SortStableFunc(a.rows, a.orderBy.Compare)
+	}
+
+	var concat []byte
+	for i, row := range a.rows {
+		if i > 0 {
+			concat = append(concat, a.separator...)
+		}
+		for _, col := range a.cols {
+			concat = append(concat, row[col].Raw()...)
+		}
+		var truncated bool
+		if concat, truncated = a.limit.truncate(a.charset, concat, i+1); truncated {
+			break
+		}
+	}
+	return sqltypes.MakeTrusted(a.type_, concat), nil
+}
+
+func (a *aggregatorGroupConcatRows) reset() {
+	a.rows = nil
+	a.size = 0
+	if a.distinct != nil {
+		clear(a.distinct.seenRows)
+	}
+}
+
+// groupConcatLimit cuts the results of GROUP_CONCAT down to group_concat_max_len bytes,
+// and records a warning for every value it cuts, like MySQL does.
+type groupConcatLimit struct {
+	// maxLen is the value of group_concat_max_len, or 0 when the session doesn't set it.
+	// The shards have already applied their own limit to the values they produced, so
+	// vtgate only cuts the values it concatenates when the session asks for a limit.
+	maxLen  int
+	session SessionActions
+}
+
+// truncate cuts the result of a GROUP_CONCAT down to the limit, without splitting a multibyte
+// character. It returns true if the value had to be cut. row is the number of the row of the
+// group at which the value is cut, which is reported in the warning.
+func (l *groupConcatLimit) truncate(cs charset.Charset, concat []byte, row int) ([]byte, bool) {
+	if l == nil || l.maxLen <= 0 || len(concat) <= l.maxLen {
+		return concat, false
+	}
+	if l.session != nil {
+		l.session.RecordWarning(&querypb.QueryWarning{
+			Code:    uint32(sqlerror.ERCutValueGroupConcat),
+			Message: fmt.Sprintf("Row %d was cut by GROUP_CONCAT()", row),
+		})
+	}
+	return truncateGroupConcat(cs, concat, l.maxLen), true
+}
+
+// truncateGroupConcat cuts concat down to maxLen bytes, without splitting a multibyte character
+func truncateGroupConcat(cs charset.Charset, concat []byte, maxLen int) []byte {
+	if cs == nil || cs.MaxWidth() == 1 {
+		return concat[:maxLen]
+	}
+	size := 0
+	for size < maxLen {
+		_, width := cs.DecodeRune(concat[size:])
+		if width < 1 {
+			// invalid bytes are cut one by one
+			width = 1
+		}
+		if size+width > maxLen {
+			break
+		}
+		size += width
+	}
+	return concat[:size]
+}
+
+// newGroupConcatLimit returns the limit set by group_concat_max_len for the session,
+// which is the maximum length in bytes of the values produced by GROUP_CONCAT
+func newGroupConcatLimit(vcursor VCursor) *groupConcatLimit {
+	limit := &groupConcatLimit{session: vcursor.Session()}
+	vcursor.Session().GetSystemVariables(func(k string, v string) {
+		if k != "group_concat_max_len" {
+			return
+		}
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			limit.maxLen = int(n)
+		}
+	})
+	return limit
 }
 
 type aggregatorGtid struct {
@@ -363,11 +604,14 @@ type aggregationState struct {
 	env         *evalengine.ExpressionEnv
 	aggregators []aggregator
 	coll        collations.ID
+	memory      *aggregationMemory
 }
 
 func (a *aggregationState) add(row []sqltypes.Value) error {
 	for _, st := range a.aggregators {
 		if err := st.add(row); err != nil {
+			// the query fails, so the group won't be finished or reset
+			a.memory.release()
 			return err
 		}
 	}
@@ -383,6 +627,8 @@ func (a *aggregationState) finish() ([]sqltypes.Value, error) {
 		}
 		row = append(row, v)
 	}
+	// the rows and values kept for the group are not needed once it is finished
+	a.memory.release()
 	return row, nil
 }
 
@@ -390,6 +636,103 @@ func (a *aggregationState) reset() {
 	for _, st := range a.aggregators {
 		st.reset()
 	}
+	a.memory.release()
+}
+
+// seenValueSize is an estimate of the memory used by each value remembered by a probeTable
+const seenValueSize = 48
+
+// aggregationMemory charges the rows and values that the aggregators of a group keep in memory,
+// for GROUP_CONCAT and for hashed DISTINCT aggregations, to the memory budget of the query
+// and to its maximum number of in-memory rows. Unlike the rows of a MemorySort or a HashJoin,
+// they can't be spilled to disk, so going over the limits fails the query.
+type aggregationMemory struct {
+	vcursor  VCursor
+	budget   *MemoryBudget
+	rows     int
+	reserved int64
+}
+
+func newAggregationMemory(vcursor VCursor) *aggregationMemory {
+	if vcursor == nil {
+		return nil
+	}
+	return &aggregationMemory{
+		vcursor: vcursor,
+		budget:  vcursor.MemoryBudget(),
+	}
+}
+
+// reserve charges one more row or value of size bytes to the query
+func (m *aggregationMemory) reserve(size int64) error {
+	if m == nil {
+		return nil
+	}
+	m.rows++
+	if m.vcursor.ExceedsMaxMemoryRows(m.rows) {
+		return vterrors.Errorf(vtrpcpb.Code_ABORTED, "in-memory row count exceeded allowed limit of %d", m.vcursor.MaxMemoryRows())
+	}
+	if m.budget == nil {
+		return nil
+	}
+	m.reserved += size
+	if !m.budget.grow(size) {
+		return vterrors.Errorf(vtrpcpb.Code_ABORTED, "aggregation exceeded the memory budget of %d bytes", m.budget.limit)
+	}
+	return nil
+}
+
+// release gives back everything reserved for the current group
+func (m *aggregationMemory) release() {
+	if m == nil {
+		return
+	}
+	if m.budget != nil {
+		m.budget.shrink(m.reserved)
+	}
+	m.rows = 0
+	m.reserved = 0
+}
+
+// distinctCheckCol returns how to compare the values of the distinct column when they are hashed
+func (ap *AggregateParams) distinctCheckCol(fields []*querypb.Field, col int, collation collations.ID) CheckCol {
+	typ := ap.Type
+	if col != ap.KeyCol || !typ.Valid() {
+		typ = fieldType(fields[col], collation)
+	}
+	return CheckCol{Col: col, Type: typ, CollationEnv: ap.CollationEnv}
+}
+
+// resultType returns the type of the GROUP_CONCAT, which is binary if any of its arguments is binary
+func (gc *GroupConcatParams) resultType(fields []*querypb.Field) sqltypes.Type {
+	for _, col := range gc.Cols {
+		if sqltypes.IsBinary(fields[col].Type) {
+			return sqltypes.Blob
+		}
+	}
+	return sqltypes.Text
+}
+
+// comparison returns the ORDER BY of the GROUP_CONCAT, using the types of the
+// input fields for the expressions the planner could not type
+func (gc *GroupConcatParams) comparison(fields []*querypb.Field, collation collations.ID) evalengine.Comparison {
+	cmp := slices.Clone(gc.OrderBy)
+	for i := range cmp {
+		if !cmp[i].Type.Valid() {
+			cmp[i].Type = fieldType(fields[cmp[i].Col], collation)
+		}
+	}
+	return cmp
+}
+
+// fieldType returns the type of the given field, using the connection collation for text
+// fields that don't specify their collation
+func fieldType(field *querypb.Field, collation collations.ID) evalengine.Type {
+	typ := evalengine.NewTypeFromField(field)
+	if sqltypes.IsText(typ.Type()) && typ.Collation() == collations.Unknown {
+		return evalengine.NewTypeEx(typ.Type(), collation, typ.Nullable(), typ.Size(), typ.Scale(), typ.Values())
+	}
+	return typ
 }
 
 func isComparable(typ sqltypes.Type) bool {
@@ -411,7 +754,7 @@ func isComparable(typ sqltypes.Type) bool {
 	return false
 }
 
-func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams, env *evalengine.ExpressionEnv, collation collations.ID) (*aggregationState, []*querypb.Field, error) {
+func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams, env *evalengine.ExpressionEnv, collation collations.ID, groupConcatLimit *groupConcatLimit, memory *aggregationMemory) (*aggregationState, []*querypb.Field, error) {
 	fields = slice.Map(fields, func(from *querypb.Field) *querypb.Field { return from.CloneVT() })
 
 	aggregators := make([]aggregator, len(fields))
@@ -424,12 +767,16 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams, env
 
 		var ag aggregator
 		distinct := -1
+		var seen *probeTable
 
 		if aggr.Opcode.IsDistinct() {
 			distinct = aggr.KeyCol
 			if aggr.WAssigned() && !isComparable(sourceType) {
 				distinct = aggr.WCol
 			}
+			if aggr.HashDistinct {
+				seen = newProbeTable([]CheckCol{aggr.distinctCheckCol(fields, distinct, collation)}, aggr.CollationEnv)
+			}
 		}
 
 		if aggr.Opcode == opcode.AggregateMin || aggr.Opcode == opcode.AggregateMax {
@@ -450,6 +797,8 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams, env
 					coll:         aggr.Type.Collation(),
 					collationEnv: aggr.CollationEnv,
 					values:       aggr.Type.Values(),
+					seen:         seen,
+					memory:       memory,
 				},
 			}
 
@@ -470,6 +819,8 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams, env
 					coll:         aggr.Type.Collation(),
 					collationEnv: aggr.CollationEnv,
 					values:       aggr.Type.Values(),
+					seen:         seen,
+					memory:       memory,
 				},
 			}
 
@@ -498,15 +849,49 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams, env
 		case opcode.AggregateGroupConcat:
 			gcFunc := aggr.Func.(*sqlparser.GroupConcatExpr)
 			separator := []byte(gcFunc.Separator)
-			ag = &aggregatorGroupConcat{
-				from:      aggr.Col,
+			if aggr.GroupConcat != nil {
+				targetType = aggr.GroupConcat.resultType(fields)
+			}
+			var cs charset.Charset
+			if !sqltypes.IsBinary(targetType) {
+				if coll := colldata.Lookup(collation); coll != nil {
+					cs = coll.Charset()
+				}
+			}
+			if aggr.GroupConcat == nil {
+				ag = &aggregatorGroupConcat{
+					from:      aggr.Col,
+					type_:     targetType,
+					separator: separator,
+					limit:     groupConcatLimit,
+					charset:   cs,
+				}
+				break
+			}
+			gc := &aggregatorGroupConcatRows{
+				cols:      aggr.GroupConcat.Cols,
+				orderBy:   aggr.GroupConcat.comparison(fields, collation),
 				type_:     targetType,
 				separator: separator,
+				limit:     groupConcatLimit,
+				charset:   cs,
+				memory:    memory,
 			}
+			if gcFunc.Distinct {
+				checkCols := make([]CheckCol, 0, len(gc.cols))
+				for _, col := range gc.cols {
+					checkCols = append(checkCols, CheckCol{Col: col, Type: fieldType(fields[col], collation), CollationEnv: aggr.CollationEnv})
+				}
+				gc.distinct = newProbeTable(checkCols, aggr.CollationEnv)
+			}
+			ag = gc
 
 		case opcode.AggregateConstant:
 			ag = &aggregatorConstant{expr: aggr.EExpr}
 
+		case opcode.AggregateGrouping:
+			ag = &aggregatorGrouping{}
+
 		default:
 			panic("BUG: unexpected Aggregation opcode")
 		}
@@ -524,5 +909,5 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams, env
 		}
 	}
 
-	return &aggregationState{aggregators: aggregators, env: env, coll: collation}, fields, nil
+	return &aggregationState{aggregators: aggregators, env: env, coll: collation, memory: memory}, fields, nil
 }
@@ -0,0 +1,294 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*CorrelatedSubquery)(nil)
+
+// CorrelatedSubquery executes a subquery for the rows of the outer query,
+// sending the columns of the outer row that the subquery depends on as bind variables.
+// The subquery is executed once for every distinct set of these values in a batch of outer rows.
+// The result of the subquery is either used to filter the outer rows, or added to them as a new column.
+type CorrelatedSubquery struct {
+	Opcode opcode.PulloutOpcode
+
+	// SubqueryResult and HasValues are the bind variables holding the result
+	// of the subquery when evaluating the Predicate
+	SubqueryResult string
+	HasValues      string
+
+	// Vars defines the bind variables that need to be built
+	// from the outer row before executing the subquery
+	Vars map[string]int
+
+	// Predicate is evaluated against every outer row, and the rows it isn't true for are discarded.
+	// When the subquery is used as a value, the Predicate computes that value if it is set,
+	// like for IN subqueries where the value is the result of the comparison.
+	Predicate    evalengine.Expr
+	ASTPredicate sqlparser.Expr
+
+	// ResultColumn is the offset at which the value of the subquery is inserted in the outer rows,
+	// or -1 when the subquery is used as a filter.
+	ResultColumn int
+
+	Outer    Primitive
+	Subquery Primitive
+}
+
+// Inputs returns the input primitives for this subquery
+func (cs *CorrelatedSubquery) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{cs.Outer, cs.Subquery}, []map[string]any{{
+		inputName: "Outer",
+	}, {
+		inputName: "SubQuery",
+	}}
+}
+
+// TryExecute satisfies the Primitive interface.
+func (cs *CorrelatedSubquery) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	outer, err := vcursor.ExecutePrimitive(ctx, cs.Outer, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	result := &sqltypes.Result{}
+	if outer.Fields != nil {
+		result.Fields, err = cs.fields(ctx, vcursor, bindVars, outer.Fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result.Rows, err = cs.evalRows(ctx, vcursor, bindVars, outer.Rows)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TryStreamExecute performs a streaming exec.
+func (cs *CorrelatedSubquery) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	var mu sync.Mutex
+	return vcursor.StreamExecutePrimitive(ctx, cs.Outer, bindVars, wantfields, func(outer *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var err error
+		result := &sqltypes.Result{}
+		if outer.Fields != nil {
+			result.Fields, err = cs.fields(ctx, vcursor, bindVars, outer.Fields)
+			if err != nil {
+				return err
+			}
+		}
+		result.Rows, err = cs.evalRows(ctx, vcursor, bindVars, outer.Rows)
+		if err != nil {
+			return err
+		}
+		return callback(result)
+	})
+}
+
+// GetFields fetches the field info.
+func (cs *CorrelatedSubquery) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	outer, err := cs.Outer.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := cs.fields(ctx, vcursor, bindVars, outer.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: fields}, nil
+}
+
+// NeedsTransaction implements the Primitive interface
+func (cs *CorrelatedSubquery) NeedsTransaction() bool {
+	return cs.Outer.NeedsTransaction() || cs.Subquery.NeedsTransaction()
+}
+
+// evalRows executes the subquery for the given outer rows, and returns the resulting rows.
+// The rows are processed as one batch: the subquery is executed once for every distinct set of values
+// it needs from the outer rows, and the results are kept in memory until the whole batch has been evaluated.
+func (cs *CorrelatedSubquery) evalRows(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, rows []sqltypes.Row) ([]sqltypes.Row, error) {
+	vars := slices.Sorted(maps.Keys(cs.Vars))
+	results := make(map[string]*sqltypes.Result)
+	var resultRows int
+	var key []byte
+
+	var out []sqltypes.Row
+	for _, row := range rows {
+		key = cs.correlationKey(key[:0], vars, row)
+		result, ok := results[string(key)]
+		if !ok {
+			joinVars := make(map[string]*querypb.BindVariable, len(vars))
+			for _, name := range vars {
+				joinVars[name] = sqltypes.ValueBindVariable(row[cs.Vars[name]])
+			}
+			var err error
+			result, err = vcursor.ExecutePrimitive(ctx, cs.Subquery, combineVars(bindVars, joinVars), false)
+			if err != nil {
+				return nil, err
+			}
+			// every distinct execution is kept, even when it returned no rows
+			resultRows += max(len(result.Rows), 1)
+			if vcursor.ExceedsMaxMemoryRows(resultRows) {
+				return nil, fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+			}
+			results[string(key)] = result
+		}
+
+		if cs.Predicate == nil {
+			value, err := cs.resultValue(result)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, cs.insertResult(row, value))
+			continue
+		}
+
+		predicateVars := combineVars(bindVars, nil)
+		if err := setPulloutVars(cs.Opcode, cs.SubqueryResult, cs.HasValues, result, predicateVars); err != nil {
+			return nil, err
+		}
+		env := evalengine.NewExpressionEnv(ctx, predicateVars, vcursor)
+		env.Row = row
+		evalResult, err := env.Evaluate(cs.Predicate)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case cs.ResultColumn >= 0:
+			// the predicate computes the value of the subquery
+			out = append(out, cs.insertResult(row, evalResult.Value(vcursor.ConnCollation())))
+		case evalResult.ToBoolean():
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+// correlationKey appends to buf the values of the outer row that the subquery depends on,
+// in a form that only compares equal for identical values
+func (cs *CorrelatedSubquery) correlationKey(buf []byte, vars []string, row sqltypes.Row) []byte {
+	for _, name := range vars {
+		value := row[cs.Vars[name]]
+		buf = binary.AppendUvarint(buf, uint64(value.Type()))
+		if value.IsNull() {
+			continue
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(value.Raw())))
+		buf = append(buf, value.Raw()...)
+	}
+	return buf
+}
+
+// resultValue returns the value of a subquery used as a value
+func (cs *CorrelatedSubquery) resultValue(result *sqltypes.Result) (sqltypes.Value, error) {
+	if cs.Opcode == opcode.PulloutExists {
+		if len(result.Rows) == 0 {
+			return sqltypes.NewInt64(0), nil
+		}
+		return sqltypes.NewInt64(1), nil
+	}
+	switch len(result.Rows) {
+	case 0:
+		return sqltypes.NULL, nil
+	case 1:
+		return result.Rows[0][0], nil
+	default:
+		return sqltypes.Value{}, errSqRow
+	}
+}
+
+func (cs *CorrelatedSubquery) insertResult(row sqltypes.Row, value sqltypes.Value) sqltypes.Row {
+	if cs.ResultColumn < 0 {
+		return row
+	}
+	return slices.Insert(slices.Clone(row), cs.ResultColumn, value)
+}
+
+func (cs *CorrelatedSubquery) fields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, outer []*querypb.Field) ([]*querypb.Field, error) {
+	if cs.ResultColumn < 0 {
+		return outer, nil
+	}
+	field := &querypb.Field{
+		Name: cs.SubqueryResult,
+		Type: sqltypes.Int64,
+	}
+	if cs.Predicate == nil && cs.Opcode != opcode.PulloutExists {
+		joinVars := make(map[string]*querypb.BindVariable, len(cs.Vars))
+		for k := range cs.Vars {
+			joinVars[k] = sqltypes.NullBindVariable
+		}
+		result, err := cs.Subquery.GetFields(ctx, vcursor, combineVars(bindVars, joinVars))
+		if err != nil {
+			return nil, err
+		}
+		field = &querypb.Field{
+			Name: cs.SubqueryResult,
+			Type: sqltypes.Null,
+		}
+		if len(result.Fields) > 0 {
+			field = result.Fields[0].CloneVT()
+			field.Name = cs.SubqueryResult
+		}
+	}
+	return slices.Insert(slices.Clone(outer), cs.ResultColumn, field), nil
+}
+
+func (cs *CorrelatedSubquery) description() PrimitiveDescription {
+	other := map[string]any{}
+	if len(cs.Vars) > 0 {
+		other["JoinVars"] = orderedStringIntMap(cs.Vars)
+	}
+	var pulloutVars []string
+	if cs.HasValues != "" {
+		pulloutVars = append(pulloutVars, cs.HasValues)
+	}
+	if cs.SubqueryResult != "" {
+		pulloutVars = append(pulloutVars, cs.SubqueryResult)
+	}
+	if len(pulloutVars) > 0 {
+		other["PulloutVars"] = pulloutVars
+	}
+	if cs.Predicate != nil {
+		other["Predicate"] = sqlparser.String(cs.ASTPredicate)
+	}
+	if cs.ResultColumn >= 0 {
+		// zero values are left out of plan descriptions, so the offset is shown as a string
+		other["ResultColumn"] = strconv.Itoa(cs.ResultColumn)
+	}
+	return PrimitiveDescription{
+		OperatorType: "CorrelatedSubquery",
+		Variant:      cs.Opcode.String(),
+		Other:        other,
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func newCorrelatedSubqueryOuter() *fakePrimitive {
+	return &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields(
+					"id|col",
+					"int64|varchar",
+				),
+				"1|a",
+				"2|b",
+				"3|c",
+			),
+		},
+	}
+}
+
+func newCorrelatedSubqueryInner() *fakePrimitive {
+	fields := sqltypes.MakeTestFields("id", "int64")
+	return &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "1", "4"),
+			sqltypes.MakeTestResult(fields),
+			sqltypes.MakeTestResult(fields, "5"),
+		},
+	}
+}
+
+func TestCorrelatedSubqueryFilter(t *testing.T) {
+	ast, err := sqlparser.NewTestParser().ParseExpr("id in ::__sq1 and :__sq_has_values")
+	require.NoError(t, err)
+	predicate, err := evalengine.Translate(ast, &evalengine.Config{
+		Collation:     collations.MySQL8().DefaultConnectionCharset(),
+		ResolveColumn: evalengine.FieldResolver(sqltypes.MakeTestFields("id|col", "int64|varchar")).Column,
+		Environment:   vtenv.NewTestEnv(),
+	})
+	require.NoError(t, err)
+
+	outer := newCorrelatedSubqueryOuter()
+	inner := newCorrelatedSubqueryInner()
+	cs := &CorrelatedSubquery{
+		Opcode:         opcode.PulloutIn,
+		SubqueryResult: "__sq1",
+		HasValues:      "__sq_has_values",
+		Vars:           map[string]int{"col": 1},
+		Predicate:      predicate,
+		ASTPredicate:   ast,
+		ResultColumn:   -1,
+		Outer:          outer,
+		Subquery:       inner,
+	}
+
+	r, err := cs.TryExecute(t.Context(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	inner.ExpectLog(t, []string{
+		fmt.Sprintf(`Execute col: %v false`, &querypb.BindVariable{Type: querypb.Type_VARCHAR, Value: []byte("a")}),
+		fmt.Sprintf(`Execute col: %v false`, &querypb.BindVariable{Type: querypb.Type_VARCHAR, Value: []byte("b")}),
+		fmt.Sprintf(`Execute col: %v false`, &querypb.BindVariable{Type: querypb.Type_VARCHAR, Value: []byte("c")}),
+	})
+	utils.MustMatch(t, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"id|col",
+			"int64|varchar",
+		),
+		"1|a",
+	), r)
+}
+
+func TestCorrelatedSubqueryValue(t *testing.T) {
+	fields := sqltypes.MakeTestFields("id", "int64")
+	outer := newCorrelatedSubqueryOuter()
+	inner := &fakePrimitive{
+		results: []*sqltypes.Result{
+			// the first result is used for the fields of the subquery
+			sqltypes.MakeTestResult(fields),
+			sqltypes.MakeTestResult(fields, "4"),
+			sqltypes.MakeTestResult(fields),
+			sqltypes.MakeTestResult(fields, "5"),
+		},
+	}
+	cs := &CorrelatedSubquery{
+		Opcode:         opcode.PulloutValue,
+		SubqueryResult: "__sq1",
+		Vars:           map[string]int{"col": 1},
+		ResultColumn:   1,
+		Outer:          outer,
+		Subquery:       inner,
+	}
+
+	r, err := cs.TryExecute(t.Context(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"id|__sq1|col",
+			"int64|int64|varchar",
+		),
+		"1|4|a",
+		"2|null|b",
+		"3|5|c",
+	), r)
+
+	// a scalar subquery returning more than one row is an error
+	outer.rewind()
+	inner.rewind()
+	inner.results[1] = sqltypes.MakeTestResult(fields, "4", "5")
+	_, err = cs.TryExecute(t.Context(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.EqualError(t, err, "subquery returned more than one row")
+}
+
+func TestCorrelatedSubqueryExistsStreamExecute(t *testing.T) {
+	outer := newCorrelatedSubqueryOuter()
+	inner := newCorrelatedSubqueryInner()
+	cs := &CorrelatedSubquery{
+		Opcode:         opcode.PulloutExists,
+		SubqueryResult: "__sq1",
+		Vars:           map[string]int{"col": 1},
+		ResultColumn:   2,
+		Outer:          outer,
+		Subquery:       inner,
+	}
+
+	r, err := wrapStreamExecute(cs, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	expectResult(t, r, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"id|col|__sq1",
+			"int64|varchar|int64",
+		),
+		"1|a|1",
+		"2|b|0",
+		"3|c|1",
+	))
+}
+
+func TestCorrelatedSubqueryInValue(t *testing.T) {
+	ast, err := sqlparser.NewTestParser().ParseExpr(":__sq_has_values and id in ::__sq1")
+	require.NoError(t, err)
+	predicate, err := evalengine.Translate(ast, &evalengine.Config{
+		Collation:     collations.MySQL8().DefaultConnectionCharset(),
+		ResolveColumn: evalengine.FieldResolver(sqltypes.MakeTestFields("id|col", "int64|varchar")).Column,
+		Environment:   vtenv.NewTestEnv(),
+	})
+	require.NoError(t, err)
+
+	fields := sqltypes.MakeTestFields("id", "int64")
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields(
+					"id|col",
+					"int64|varchar",
+				),
+				"1|a",
+				"2|a",
+				"3|b",
+				"4|null",
+				"5|a",
+			),
+		},
+	}
+	inner := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "1", "5"),
+			sqltypes.MakeTestResult(fields),
+			sqltypes.MakeTestResult(fields, "4"),
+		},
+	}
+	cs := &CorrelatedSubquery{
+		Opcode:         opcode.PulloutIn,
+		SubqueryResult: "__sq1",
+		HasValues:      "__sq_has_values",
+		Vars:           map[string]int{"col": 1},
+		Predicate:      predicate,
+		ASTPredicate:   ast,
+		ResultColumn:   2,
+		Outer:          outer,
+		Subquery:       inner,
+	}
+
+	r, err := cs.TryExecute(t.Context(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	// the subquery is only executed once for every distinct value it needs from the outer rows
+	inner.ExpectLog(t, []string{
+		fmt.Sprintf(`Execute col: %v false`, &querypb.BindVariable{Type: querypb.Type_VARCHAR, Value: []byte("a")}),
+		fmt.Sprintf(`Execute col: %v false`, &querypb.BindVariable{Type: querypb.Type_VARCHAR, Value: []byte("b")}),
+		fmt.Sprintf(`Execute col: %v false`, sqltypes.NullBindVariable),
+	})
+	utils.MustMatch(t, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"id|col|__sq1",
+			"int64|varchar|int64",
+		),
+		"1|a|1",
+		"2|a|0",
+		"3|b|0",
+		"4|null|1",
+		"5|a|1",
+	), r)
+}
+
+func TestCorrelatedSubqueryMaxMemoryRows(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	testMaxMemoryRows = 3
+	defer func() {
+		testMaxMemoryRows = saveMax
+	}()
+
+	cs := &CorrelatedSubquery{
+		Opcode:         opcode.PulloutExists,
+		SubqueryResult: "__sq1",
+		Vars:           map[string]int{"col": 1},
+		ResultColumn:   2,
+		Outer:          newCorrelatedSubqueryOuter(),
+		Subquery:       newCorrelatedSubqueryInner(),
+	}
+	_, err := cs.TryExecute(t.Context(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.EqualError(t, err, "in-memory row count exceeded allowed limit of 3")
+}
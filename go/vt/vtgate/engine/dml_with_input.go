@@ -38,6 +38,10 @@ type DMLWithInput struct {
 	DMLs       []Primitive
 	OutputCols [][]int
 	BVList     []map[string]int
+
+	// MovesRows is set when the DMLs delete the input rows and insert them again in other shards.
+	// The rows affected are then the rows inserted, as they are the same rows that were deleted.
+	MovesRows bool
 }
 
 func (dml *DMLWithInput) Inputs() ([]Primitive, []map[string]any) {
@@ -69,9 +73,12 @@ func (dml *DMLWithInput) TryExecute(ctx context.Context, vcursor VCursor, bindVa
 			return nil, err
 		}
 
-		if res == nil {
+		switch {
+		case res == nil:
 			res = qr
-		} else {
+		case dml.MovesRows:
+			res.RowsAffected = qr.RowsAffected
+		default:
 			res.RowsAffected += qr.RowsAffected
 		}
 	}
@@ -168,6 +175,9 @@ func (dml *DMLWithInput) description() PrimitiveDescription {
 	if len(bvList) > 0 {
 		other["BindVars"] = bvList
 	}
+	if dml.MovesRows {
+		other["MovesRows"] = true
+	}
 	return PrimitiveDescription{
 		OperatorType: "DMLWithInput",
 		Other:        other,
@@ -272,3 +272,59 @@ func TestUpdateWithInputNonLiteral(t *testing.T) {
 	})
 	assert.EqualValues(t, 3, qr.RowsAffected)
 }
+
+func TestUpdateWithInputMovesRows(t *testing.T) {
+	input := &fakePrimitive{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|new_id", "int64|int64"), "1|100", "2|200"),
+	}}
+
+	rdParams := &RoutingParameters{
+		Opcode: Scatter,
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+	}
+	dml := &DMLWithInput{
+		Input: input,
+		DMLs: []Primitive{&Delete{
+			DML: &DML{
+				RoutingParameters: rdParams,
+				Query:             "dummy_delete",
+			},
+		}, &Update{
+			DML: &DML{
+				RoutingParameters: rdParams,
+				Query:             "dummy_insert",
+			},
+		}},
+		OutputCols: [][]int{{0}, {0}},
+		BVList: []map[string]int{
+			nil,
+			{"bv1": 1},
+		},
+		MovesRows: true,
+	}
+
+	vc := newTestVCursor("-20", "20-")
+	vc.results = []*sqltypes.Result{
+		{RowsAffected: 2}, {RowsAffected: 1}, {RowsAffected: 1},
+	}
+	qr, err := dml.TryExecute(t.Context(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`InDMLExecution set to true`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.-20: dummy_delete {dml_vals: type:TUPLE values:{type:INT64 value:"1"} values:{type:INT64 value:"2"}} ` +
+			`ks.20-: dummy_delete {dml_vals: type:TUPLE values:{type:INT64 value:"1"} values:{type:INT64 value:"2"}} true false`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.-20: dummy_insert {bv1: type:INT64 value:"100" dml_vals: type:TUPLE values:{type:INT64 value:"1"}} ` +
+			`ks.20-: dummy_insert {bv1: type:INT64 value:"100" dml_vals: type:TUPLE values:{type:INT64 value:"1"}} true false`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.-20: dummy_insert {bv1: type:INT64 value:"200" dml_vals: type:TUPLE values:{type:INT64 value:"2"}} ` +
+			`ks.20-: dummy_insert {bv1: type:INT64 value:"200" dml_vals: type:TUPLE values:{type:INT64 value:"2"}} true false`,
+		`InDMLExecution set to false`,
+	})
+	// the deleted rows are the same rows that are inserted again, so they are only counted once
+	assert.EqualValues(t, 2, qr.RowsAffected)
+}
@@ -59,11 +59,12 @@ var (
 
 // noopVCursor is used to build other vcursors.
 type noopVCursor struct {
-	inTx bool
+	inTx         bool
+	memoryBudget *MemoryBudget
 }
 
 func (t *noopVCursor) GetExecutionMetrics() *Metrics {
-	panic("implement me")
+	return nil
 }
 
 func (t *noopVCursor) SetExecutedPrimitive(Primitive) {}
@@ -205,7 +206,6 @@ func (t *noopVCursor) HasSystemVariables() bool {
 }
 
 func (t *noopVCursor) GetSystemVariables(func(k string, v string)) {
-	panic("implement me")
 }
 
 func (t *noopVCursor) GetWarnings() []*querypb.QueryWarning {
@@ -380,6 +380,10 @@ func (t *noopVCursor) ExceedsMaxMemoryRows(numRows int) bool {
 	return !testIgnoreMaxMemoryRows && numRows > testMaxMemoryRows
 }
 
+func (t *noopVCursor) MemoryBudget() *MemoryBudget {
+	return t.memoryBudget
+}
+
 func (t *noopVCursor) GetKeyspace() string {
 	return "test_ks"
 }
@@ -538,8 +542,10 @@ func (f *loggingVCursor) HasSystemVariables() bool {
 	return len(f.systemVariables) > 0
 }
 
-func (f *loggingVCursor) GetSystemVariables(func(k string, v string)) {
-	panic("implement me")
+func (f *loggingVCursor) GetSystemVariables(visitor func(k string, v string)) {
+	for k, v := range f.systemVariables {
+		visitor(k, v)
+	}
 }
 
 func (f *loggingVCursor) SetFoundRows(u uint64) {
@@ -18,7 +18,9 @@ package engine
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -133,6 +135,10 @@ func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 
 // TryStreamExecute implements the Primitive interface
 func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	if budget := vcursor.MemoryBudget(); budget != nil {
+		return hj.streamExecuteWithSpill(ctx, vcursor, bindVars, wantfields, budget, callback)
+	}
+
 	// build the probe table from the LHS result
 	pt := newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values)
 	var lfields []*querypb.Field
@@ -155,10 +161,15 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 		return err
 	}
 
+	return hj.streamProbe(ctx, vcursor, bindVars, wantfields, pt, lfields, &mu, callback)
+}
+
+// streamProbe streams the RHS and sends the rows that match the probe table to the callback
+func (hj *HashJoin) streamProbe(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, pt *hashJoinProbeTable, lfields []*querypb.Field, mu *sync.Mutex, callback func(*sqltypes.Result) error) error {
 	var sendFields atomic.Bool
 	sendFields.Store(wantfields)
 
-	err = vcursor.StreamExecutePrimitive(ctx, hj.Right, bindVars, sendFields.Load(), func(result *sqltypes.Result) error {
+	err := vcursor.StreamExecutePrimitive(ctx, hj.Right, bindVars, sendFields.Load(), func(result *sqltypes.Result) error {
 		mu.Lock()
 		defer mu.Unlock()
 		// compare the results coming from the RHS with the probe-table
@@ -201,6 +212,77 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 	return nil
 }
 
+// streamExecuteWithSpill works like TryStreamExecute as long as the LHS fits in the memory budget of the query.
+// When it does not, it turns into a grace hash join: the rows of both sides are written to partitions on disk
+// using the hash of their join column, and each pair of partitions is then joined in memory.
+func (hj *HashJoin) streamExecuteWithSpill(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, budget *MemoryBudget, callback func(*sqltypes.Result) error) error {
+	spill := &hashJoinSpill{
+		budget: budget,
+		pt:     newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values),
+	}
+	defer func() {
+		vcursor.GetExecutionMetrics().addSpilledBytes("HashJoin", spill.spilled())
+		spill.close()
+	}()
+
+	var lfields []*querypb.Field
+	var mu sync.Mutex
+	err := vcursor.StreamExecutePrimitive(ctx, hj.Left, bindVars, wantfields, func(result *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(lfields) == 0 && len(result.Fields) != 0 {
+			lfields = result.Fields
+		}
+		for _, current := range result.Rows {
+			if err := spill.addLeftRow(current); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if spill.left == nil {
+		// the LHS fit in memory, so we can do a normal hash join
+		return hj.streamProbe(ctx, vcursor, bindVars, wantfields, spill.pt, lfields, &mu, callback)
+	}
+
+	var rfields []*querypb.Field
+	err = vcursor.StreamExecutePrimitive(ctx, hj.Right, bindVars, wantfields, func(result *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(rfields) == 0 && len(result.Fields) != 0 {
+			rfields = result.Fields
+		}
+		for _, current := range result.Rows {
+			if err := spill.addRightRow(current); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if wantfields {
+		if len(rfields) == 0 {
+			rres, err := hj.Right.GetFields(ctx, vcursor, bindVars)
+			if err != nil {
+				return err
+			}
+			rfields = rres.Fields
+		}
+		if err := callback(&sqltypes.Result{Fields: joinFields(lfields, rfields, hj.Cols)}); err != nil {
+			return err
+		}
+	}
+
+	return spill.join(hj.Opcode == LeftJoin, callback)
+}
+
 // GetFields implements the Primitive interface
 func (hj *HashJoin) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
 	joinVars := make(map[string]*querypb.BindVariable)
@@ -311,3 +393,175 @@ func (pt *hashJoinProbeTable) notFetched() (rows []sqltypes.Row) {
 	}
 	return
 }
+
+// hashJoinSpillPartitions is the number of partitions that the rows of a hash join are split into
+// when it goes over the memory budget of the query
+const hashJoinSpillPartitions = 16
+
+// hashJoinSpill builds the probe table of a hash join in memory until the query goes over its memory budget.
+// From then on, the rows of both sides are written to partitions on disk instead. Rows are assigned to a
+// partition using the hash of their join column, so rows that can match always end up in the same partition.
+type hashJoinSpill struct {
+	budget *MemoryBudget
+	pt     *hashJoinProbeTable
+
+	// size is the memory reserved for the rows in the probe table
+	size int64
+
+	// left and right are the partitions of each side. They are nil until the join is partitioned
+	left, right []*spillFile
+}
+
+func (hs *hashJoinSpill) addLeftRow(row sqltypes.Row) error {
+	if hs.left != nil {
+		return hs.write(hs.left, row, hs.pt.lhsKey)
+	}
+	if err := hs.pt.addLeftRow(row); err != nil {
+		return err
+	}
+	size := rowSize(row)
+	hs.size += size
+	if hs.budget.grow(size) {
+		return nil
+	}
+	return hs.partition()
+}
+
+func (hs *hashJoinSpill) addRightRow(row sqltypes.Row) error {
+	if row[hs.pt.rhsKey].IsNull() {
+		// a NULL can't match anything on the LHS, so we don't need to keep the row around
+		return nil
+	}
+	return hs.write(hs.right, row, hs.pt.rhsKey)
+}
+
+// partition creates the partitions on disk, and moves the rows of the probe table to them
+func (hs *hashJoinSpill) partition() error {
+	for range hashJoinSpillPartitions {
+		left, err := hs.budget.newSpillFile()
+		if err != nil {
+			return err
+		}
+		hs.left = append(hs.left, left)
+		right, err := hs.budget.newSpillFile()
+		if err != nil {
+			return err
+		}
+		hs.right = append(hs.right, right)
+	}
+
+	for hash, e := range hs.pt.innerMap {
+		for ; e != nil; e = e.next {
+			if err := hs.left[partitionFor(hash)].write(e.row); err != nil {
+				return err
+			}
+		}
+	}
+	hs.resetProbeTable()
+	return nil
+}
+
+func (hs *hashJoinSpill) write(partitions []*spillFile, row sqltypes.Row, key int) error {
+	hash, err := hs.pt.hash(row[key])
+	if err != nil {
+		return err
+	}
+	return partitions[partitionFor(hash)].write(row)
+}
+
+// join reads back the partitions one at a time and joins them in memory
+func (hs *hashJoinSpill) join(leftJoin bool, callback func(*sqltypes.Result) error) error {
+	for i := range hashJoinSpillPartitions {
+		left, right := hs.left[i], hs.right[i]
+		if err := left.rewind(); err != nil {
+			return err
+		}
+		for {
+			row, err := left.read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if err := hs.pt.addLeftRow(row); err != nil {
+				return err
+			}
+			// a single partition can be bigger than the budget, but we have no other option than to keep it in memory
+			size := rowSize(row)
+			hs.size += size
+			hs.budget.grow(size)
+		}
+
+		if err := right.rewind(); err != nil {
+			return err
+		}
+		result := &sqltypes.Result{}
+		var size int64
+		for {
+			row, err := right.read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			matches, err := hs.pt.get(row)
+			if err != nil {
+				return err
+			}
+			for _, match := range matches {
+				result.Rows = append(result.Rows, match)
+				size += rowSize(match)
+			}
+			if size >= spillBatchSize {
+				if err := callback(result); err != nil {
+					return err
+				}
+				result = &sqltypes.Result{}
+				size = 0
+			}
+		}
+		if leftJoin {
+			result.Rows = append(result.Rows, hs.pt.notFetched()...)
+		}
+		if len(result.Rows) != 0 {
+			if err := callback(result); err != nil {
+				return err
+			}
+		}
+		hs.resetProbeTable()
+	}
+	return nil
+}
+
+func (hs *hashJoinSpill) resetProbeTable() {
+	hs.pt.innerMap = map[vthash.Hash]*probeTableEntry{}
+	hs.budget.shrink(hs.size)
+	hs.size = 0
+}
+
+// spilled returns the number of bytes written to disk
+func (hs *hashJoinSpill) spilled() (size int64) {
+	for _, f := range hs.left {
+		size += f.size
+	}
+	for _, f := range hs.right {
+		size += f.size
+	}
+	return size
+}
+
+func (hs *hashJoinSpill) close() {
+	hs.budget.shrink(hs.size)
+	for _, f := range hs.left {
+		f.close()
+	}
+	for _, f := range hs.right {
+		f.close()
+	}
+}
+
+func partitionFor(hash vthash.Hash) int {
+	return int(binary.LittleEndian.Uint64(hash[:8]) % hashJoinSpillPartitions)
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package engine
 
 import (
+	"os"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/mysql/collations"
@@ -157,6 +159,19 @@ func TestHashJoinVariations(t *testing.T) {
 			require.NoError(t, err)
 			expectResultAnyOrder(t, r, expected)
 		})
+		t.Run("Spilling "+tc.name, func(t *testing.T) {
+			jn.Left = first()
+			jn.Right = last()
+			dir := t.TempDir()
+			budget := NewMemoryBudget(1, dir)
+			r, err := wrapStreamExecute(jn, &noopVCursor{memoryBudget: budget}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, r, expected)
+			assert.Zero(t, budget.Used())
+			files, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Empty(t, files)
+		})
 	}
 }
 
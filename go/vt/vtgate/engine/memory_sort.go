@@ -19,8 +19,10 @@ package engine
 import (
 	"context"
 	"fmt"
+	"io"
 	"math"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -78,6 +80,10 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 		return callback(qr.Truncate(ms.TruncateColumnCount))
 	}
 
+	if budget := vcursor.MemoryBudget(); budget != nil {
+		return ms.streamExecuteWithSpill(ctx, vcursor, bindVars, wantfields, count, budget, cb)
+	}
+
 	sorter := &evalengine.Sorter{
 		Compare: ms.OrderBy,
 		Limit:   count,
@@ -106,6 +112,185 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 	return cb(&sqltypes.Result{Rows: sorter.Sorted()})
 }
 
+// streamExecuteWithSpill sorts the input like TryStreamExecute does, but when the rows held in memory
+// go over the memory budget of the query, they are sorted and written to disk as a sorted run.
+// At the end, all the runs are merged together with the rows that are still in memory.
+func (ms *MemorySort) streamExecuteWithSpill(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, count int, budget *MemoryBudget, callback func(*sqltypes.Result) error) error {
+	sorter := &spillSorter{
+		budget:  budget,
+		compare: ms.OrderBy,
+		limit:   count,
+	}
+	defer func() {
+		vcursor.GetExecutionMetrics().addSpilledBytes("Sort", sorter.spilled)
+		sorter.close()
+	}()
+
+	var mu sync.Mutex
+	err := vcursor.StreamExecutePrimitive(ctx, ms.Input, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(qr.Fields) != 0 {
+			if err := callback(&sqltypes.Result{Fields: qr.Fields}); err != nil {
+				return err
+			}
+		}
+		for _, row := range qr.Rows {
+			if err := sorter.push(row); err != nil {
+				return err
+			}
+		}
+		if vcursor.ExceedsMaxMemoryRows(len(sorter.rows)) {
+			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return sorter.sorted(callback)
+}
+
+// spillSorter is used to sort more rows than fit in the memory budget of the query, using an external merge sort
+type spillSorter struct {
+	budget  *MemoryBudget
+	compare evalengine.Comparison
+	limit   int
+
+	// rows are the rows held in memory, and size is the memory reserved for them
+	rows []sqltypes.Row
+	size int64
+
+	// runs are the files with sorted rows that have been spilled to disk
+	runs    []*spillFile
+	spilled int64
+}
+
+func (s *spillSorter) push(row sqltypes.Row) error {
+	s.rows = append(s.rows, row)
+	size := rowSize(row)
+	s.size += size
+	if s.budget.grow(size) {
+		return nil
+	}
+	return s.spill()
+}
+
+// spill is called when the query is over its memory budget. Rows past the limit can never be part
+// of the result, so they are thrown away first. If that is not enough to free up at least half of the
+// memory held by the sorter, the sorted rows are moved to a new run on disk.
+func (s *spillSorter) spill() error {
+	s.compare.Sort(s.rows)
+	if len(s.rows) > s.limit {
+		s.rows = slices.Clone(s.rows[:s.limit])
+		var size int64
+		for _, row := range s.rows {
+			size += rowSize(row)
+		}
+		s.budget.shrink(s.size - size)
+		if size <= s.size/2 {
+			s.size = size
+			return nil
+		}
+		s.size = size
+	}
+
+	run, err := s.budget.newSpillFile()
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, run)
+	for _, row := range s.rows {
+		if err := run.write(row); err != nil {
+			return err
+		}
+	}
+	s.spilled += run.size
+	s.budget.shrink(s.size)
+	s.rows = nil
+	s.size = 0
+	return nil
+}
+
+// sorted sends all the rows in order to the callback
+func (s *spillSorter) sorted(callback func(*sqltypes.Result) error) error {
+	s.compare.Sort(s.rows)
+	if len(s.rows) > s.limit {
+		s.rows = s.rows[:s.limit]
+	}
+	if len(s.runs) == 0 {
+		return callback(&sqltypes.Result{Rows: s.rows})
+	}
+
+	// the rows still in memory are merged as the last source
+	merge := &evalengine.Merger{
+		Compare: s.compare,
+	}
+	next := func(source int) (sqltypes.Row, error) {
+		if source < len(s.runs) {
+			return s.runs[source].read()
+		}
+		if len(s.rows) == 0 {
+			return nil, io.EOF
+		}
+		row := s.rows[0]
+		s.rows = s.rows[1:]
+		return row, nil
+	}
+	for _, run := range s.runs {
+		if err := run.rewind(); err != nil {
+			return err
+		}
+	}
+	for source := 0; source <= len(s.runs); source++ {
+		row, err := next(source)
+		switch {
+		case err == io.EOF:
+		case err != nil:
+			return err
+		default:
+			merge.Push(row, source)
+		}
+	}
+	merge.Init()
+
+	result := &sqltypes.Result{}
+	var size int64
+	for n := 0; n < s.limit && merge.Len() != 0; n++ {
+		row, source := merge.Peek()
+		result.Rows = append(result.Rows, row)
+		size += rowSize(row)
+		if size >= spillBatchSize {
+			if err := callback(result); err != nil {
+				return err
+			}
+			result = &sqltypes.Result{}
+			size = 0
+		}
+
+		row, err := next(source)
+		switch {
+		case err == io.EOF:
+			merge.Pop()
+		case err != nil:
+			return err
+		default:
+			merge.ReplaceMin(row, source)
+		}
+	}
+	if len(result.Rows) == 0 {
+		return nil
+	}
+	return callback(result)
+}
+
+func (s *spillSorter) close() {
+	s.budget.shrink(s.size)
+	for _, run := range s.runs {
+		run.close()
+	}
+}
+
 // GetFields satisfies the Primitive interface.
 func (ms *MemorySort) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
 	return ms.Input.GetFields(ctx, vcursor, bindVars)
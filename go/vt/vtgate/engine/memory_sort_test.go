@@ -17,6 +17,7 @@ limitations under the License.
 package engine
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -403,6 +404,59 @@ func TestMemorySortStreamExecute(t *testing.T) {
 	utils.MustMatch(t, wantResults, results)
 }
 
+func TestMemorySortStreamExecuteSpill(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2",
+		"varbinary|decimal",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"a|1",
+			"g|2",
+			"a|1",
+			"c|4",
+			"c|3",
+			"e|null",
+		)},
+	}
+
+	ms := &MemorySort{
+		OrderBy: []evalengine.OrderByParams{{
+			WeightStringCol: -1,
+			Col:             1,
+		}},
+		Input: fp,
+	}
+
+	for _, limit := range []int64{0, 2, 3, 10} {
+		// a budget this small makes the sort spill every row to disk
+		dir := t.TempDir()
+		budget := NewMemoryBudget(1, dir)
+		bv := map[string]*querypb.BindVariable{}
+		ms.UpperLimit = nil
+		if limit > 0 {
+			ms.UpperLimit = evalengine.NewBindVar("__upper_limit", evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID))
+			bv["__upper_limit"] = sqltypes.Int64BindVariable(limit)
+		}
+
+		fp.rewind()
+		result, err := wrapStreamExecute(ms, &noopVCursor{memoryBudget: budget}, bv, true)
+		require.NoError(t, err)
+
+		wantRows := []string{"e|null", "a|1", "a|1", "g|2", "c|3", "c|4"}
+		if limit > 0 && int(limit) < len(wantRows) {
+			wantRows = wantRows[:limit]
+		}
+		expectResult(t, result, sqltypes.MakeTestResult(fields, wantRows...))
+
+		assert.Zero(t, budget.Used())
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	}
+}
+
 func TestMemorySortGetFields(t *testing.T) {
 	result := sqltypes.MakeTestResult(
 		sqltypes.MakeTestFields(
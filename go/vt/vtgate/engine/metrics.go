@@ -23,10 +23,19 @@ import (
 
 type Metrics struct {
 	optimizedQueryExec *stats.CountersWithSingleLabel
+	spilledBytes       *stats.CountersWithSingleLabel
 }
 
 func InitMetrics(exporter *servenv.Exporter) *Metrics {
 	return &Metrics{
 		optimizedQueryExec: exporter.NewCountersWithSingleLabel("OptimizedQueryExecutions", "Counts optimized queries executed at VTGate by plan type.", "Plan"),
+		spilledBytes:       exporter.NewCountersWithSingleLabel("SpilledBytes", "Counts bytes written to disk by VTGate primitives that went over the memory budget of the query.", "Operator"),
 	}
 }
+
+func (m *Metrics) addSpilledBytes(operator string, n int64) {
+	if m == nil || n == 0 {
+		return
+	}
+	m.spilledBytes.Add(operator, n)
+}
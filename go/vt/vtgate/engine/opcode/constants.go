@@ -79,6 +79,7 @@ const (
 	AggregateAvg
 	AggregateUDF      // This is an opcode used to represent UDFs
 	AggregateConstant // This is an opcode used to represent constants that are not grouped
+	AggregateGrouping // This is an opcode used to represent GROUPING() in queries WITH ROLLUP
 	_NumOfOpCodes     // This line must be last of the opcodes!
 )
 
@@ -99,6 +100,7 @@ var SupportedAggregates = map[string]AggregateOpcode{
 	"any_value":      AggregateAnyValue,
 	"group_concat":   AggregateGroupConcat,
 	"constant_aggr":  AggregateGroupConcat,
+	"grouping":       AggregateGrouping,
 }
 
 var AggregateName = map[AggregateOpcode]string{
@@ -114,6 +116,7 @@ var AggregateName = map[AggregateOpcode]string{
 	AggregateAnyValue:      "any_value",
 	AggregateAvg:           "avg",
 	AggregateConstant:      "constant_aggr",
+	AggregateGrouping:      "grouping",
 }
 
 func (code AggregateOpcode) String() string {
@@ -153,7 +156,7 @@ func (code AggregateOpcode) SQLType(typ querypb.Type) querypb.Type {
 			return sqltypes.Decimal
 		}
 		return sqltypes.Float64
-	case AggregateCount, AggregateCountStar, AggregateCountDistinct:
+	case AggregateCount, AggregateCountStar, AggregateCountDistinct, AggregateGrouping:
 		return sqltypes.Int64
 	case AggregateGtid:
 		return sqltypes.VarChar
@@ -166,7 +169,7 @@ func (code AggregateOpcode) SQLType(typ querypb.Type) querypb.Type {
 
 func (code AggregateOpcode) Nullable() bool {
 	switch code {
-	case AggregateCount, AggregateCountStar:
+	case AggregateCount, AggregateCountStar, AggregateGrouping:
 		return false
 	default:
 		return true
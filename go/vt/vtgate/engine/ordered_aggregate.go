@@ -25,6 +25,7 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
 
@@ -49,6 +50,11 @@ type OrderedAggregate struct {
 	// from the result received. If 0, no truncation happens.
 	TruncateColumnCount int
 
+	// WithRollup is set for GROUP BY ... WITH ROLLUP queries. The input then
+	// holds the rows grouped by all the GroupByKeys, and the super-aggregate
+	// rows are computed here while the groups are being emitted.
+	WithRollup bool
+
 	// Input is the primitive that will feed into this Primitive.
 	Input Primitive
 }
@@ -129,41 +135,54 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 	if err != nil {
 		return nil, err
 	}
-	if len(oa.Aggregates) == 0 {
+	if len(oa.Aggregates) == 0 && !oa.WithRollup {
 		return oa.executeGroupBy(result)
 	}
 
-	agg, fields, err := newAggregation(result.Fields, oa.Aggregates, env, vcursor.ConnCollation())
+	agg, fields, err := newAggregation(result.Fields, oa.Aggregates, env, vcursor.ConnCollation(), newGroupConcatLimit(vcursor), newAggregationMemory(vcursor))
+	if err != nil {
+		return nil, err
+	}
+	rollup, err := oa.newRollup(vcursor, result.Fields, env, vcursor.ConnCollation(), newGroupConcatLimit(vcursor))
 	if err != nil {
 		return nil, err
 	}
 
 	out := &sqltypes.Result{
-		Fields: fields,
+		Fields: oa.rollupFields(fields),
 		Rows:   make([][]sqltypes.Value, 0, len(result.Rows)),
 	}
 
 	var currentKey []sqltypes.Value
 	for _, row := range result.Rows {
-		var nextGroup bool
+		var changedKey int
 
-		currentKey, nextGroup, err = oa.nextGroupBy(currentKey, row)
+		currentKey, changedKey, err = oa.nextGroupByKey(currentKey, row)
 		if err != nil {
 			return nil, err
 		}
 
-		if nextGroup {
+		if changedKey >= 0 {
 			values, err := agg.finish()
 			if err != nil {
 				return nil, err
 			}
 			out.Rows = append(out.Rows, values)
 			agg.reset()
+
+			rows, err := oa.finishRollup(rollup, changedKey+1)
+			if err != nil {
+				return nil, err
+			}
+			out.Rows = append(out.Rows, rows...)
 		}
 
 		if err := agg.add(row); err != nil {
 			return nil, err
 		}
+		if err := rollup.add(row); err != nil {
+			return nil, err
+		}
 	}
 
 	if currentKey != nil {
@@ -172,6 +191,12 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 			return nil, err
 		}
 		out.Rows = append(out.Rows, values)
+
+		rows, err := oa.finishRollup(rollup, 0)
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, rows...)
 	}
 
 	return out, nil
@@ -230,7 +255,7 @@ func (oa *OrderedAggregate) executeStreamGroupBy(ctx context.Context, vcursor VC
 
 // TryStreamExecute is a Primitive function.
 func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool, callback func(*sqltypes.Result) error) error {
-	if len(oa.Aggregates) == 0 {
+	if len(oa.Aggregates) == 0 && !oa.WithRollup {
 		return oa.executeStreamGroupBy(ctx, vcursor, bindVars, callback)
 	}
 	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
@@ -240,6 +265,7 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 	}
 
 	var agg *aggregationState
+	var rollup rollupState
 	var fields []*querypb.Field
 	var currentKey []sqltypes.Value
 
@@ -247,31 +273,39 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 		var err error
 
 		if agg == nil && len(qr.Fields) != 0 {
-			agg, fields, err = newAggregation(qr.Fields, oa.Aggregates, env, vcursor.ConnCollation())
+			agg, fields, err = newAggregation(qr.Fields, oa.Aggregates, env, vcursor.ConnCollation(), newGroupConcatLimit(vcursor), newAggregationMemory(vcursor))
 			if err != nil {
 				return err
 			}
-			if err = cb(&sqltypes.Result{Fields: fields}); err != nil {
+			rollup, err = oa.newRollup(vcursor, qr.Fields, env, vcursor.ConnCollation(), newGroupConcatLimit(vcursor))
+			if err != nil {
+				return err
+			}
+			if err = cb(&sqltypes.Result{Fields: oa.rollupFields(fields)}); err != nil {
 				return err
 			}
 		}
 
 		// This code is similar to the one in Execute.
 		for _, row := range qr.Rows {
-			var nextGroup bool
+			var changedKey int
 
-			currentKey, nextGroup, err = oa.nextGroupBy(currentKey, row)
+			currentKey, changedKey, err = oa.nextGroupByKey(currentKey, row)
 			if err != nil {
 				return err
 			}
 
-			if nextGroup {
+			if changedKey >= 0 {
 				// this is a new grouping. let's yield the old one, and start a new
 				values, err := agg.finish()
 				if err != nil {
 					return err
 				}
-				if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{values}}); err != nil {
+				rows, err := oa.finishRollup(rollup, changedKey+1)
+				if err != nil {
+					return err
+				}
+				if err := cb(&sqltypes.Result{Rows: append([][]sqltypes.Value{values}, rows...)}); err != nil {
 					return err
 				}
 
@@ -281,6 +315,9 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 			if err := agg.add(row); err != nil {
 				return err
 			}
+			if err := rollup.add(row); err != nil {
+				return err
+			}
 		}
 		return nil
 	}
@@ -296,7 +333,11 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 		if err != nil {
 			return err
 		}
-		if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{values}}); err != nil {
+		rows, err := oa.finishRollup(rollup, 0)
+		if err != nil {
+			return err
+		}
+		if err := cb(&sqltypes.Result{Rows: append([][]sqltypes.Value{values}, rows...)}); err != nil {
 			return err
 		}
 	}
@@ -310,12 +351,12 @@ func (oa *OrderedAggregate) GetFields(ctx context.Context, vcursor VCursor, bind
 		return nil, err
 	}
 	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
-	_, fields, err := newAggregation(qr.Fields, oa.Aggregates, env, vcursor.ConnCollation())
+	_, fields, err := newAggregation(qr.Fields, oa.Aggregates, env, vcursor.ConnCollation(), newGroupConcatLimit(vcursor), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	qr = &sqltypes.Result{Fields: fields}
+	qr = &sqltypes.Result{Fields: oa.rollupFields(fields)}
 	return qr.Truncate(oa.TruncateColumnCount), nil
 }
 
@@ -330,34 +371,121 @@ func (oa *OrderedAggregate) NeedsTransaction() bool {
 }
 
 func (oa *OrderedAggregate) nextGroupBy(currentKey, nextRow []sqltypes.Value) (nextKey []sqltypes.Value, nextGroup bool, err error) {
+	nextKey, changedKey, err := oa.nextGroupByKey(currentKey, nextRow)
+	return nextKey, changedKey >= 0, err
+}
+
+// nextGroupByKey works like nextGroupBy, but instead of only telling if a new group starts,
+// it returns the index of the first grouping key that differs, or -1 if the group is the same.
+func (oa *OrderedAggregate) nextGroupByKey(currentKey, nextRow []sqltypes.Value) (nextKey []sqltypes.Value, changedKey int, err error) {
 	if currentKey == nil {
-		return nextRow, false, nil
+		return nextRow, -1, nil
 	}
 
-	for _, gb := range oa.GroupByKeys {
+	for idx, gb := range oa.GroupByKeys {
 		v1 := currentKey[gb.KeyCol]
 		v2 := nextRow[gb.KeyCol]
 		if v1.TinyWeightCmp(v2) != 0 {
-			return nextRow, true, nil
+			return nextRow, idx, nil
 		}
 
 		cmp, err := evalengine.NullsafeCompare(v1, v2, gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
 		if err != nil {
 			_, isCollationErr := err.(evalengine.UnsupportedCollationError)
 			if !isCollationErr || gb.WeightStringCol == -1 {
-				return nil, false, err
+				return nil, -1, err
 			}
 			gb.KeyCol = gb.WeightStringCol
 			cmp, err = evalengine.NullsafeCompare(currentKey[gb.WeightStringCol], nextRow[gb.WeightStringCol], gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
 			if err != nil {
-				return nil, false, err
+				return nil, -1, err
 			}
 		}
 		if cmp != 0 {
-			return nextRow, true, nil
+			return nextRow, idx, nil
 		}
 	}
-	return currentKey, false, nil
+	return currentKey, -1, nil
+}
+
+// rollupState holds one aggregation per super-aggregate level of a WITH ROLLUP query.
+// The state at index i aggregates the rows that share the values of the first i grouping keys,
+// so the state at index 0 computes the grand total.
+type rollupState []*aggregationState
+
+func (r rollupState) add(row []sqltypes.Value) error {
+	for _, level := range r {
+		if err := level.add(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (oa *OrderedAggregate) newRollup(vcursor VCursor, fields []*querypb.Field, env *evalengine.ExpressionEnv, collation collations.ID, groupConcatLimit *groupConcatLimit) (rollupState, error) {
+	if !oa.WithRollup {
+		return nil, nil
+	}
+	r := make(rollupState, len(oa.GroupByKeys))
+	for level := range r {
+		agg, _, err := newAggregation(fields, oa.Aggregates, env, collation, groupConcatLimit, newAggregationMemory(vcursor))
+		if err != nil {
+			return nil, err
+		}
+		r[level] = agg
+	}
+	return r, nil
+}
+
+// finishRollup returns the super-aggregate rows of all the levels from the
+// most detailed one down to the given one, and resets their aggregations.
+func (oa *OrderedAggregate) finishRollup(r rollupState, downTo int) ([]sqltypes.Row, error) {
+	var rows []sqltypes.Row
+	for level := len(r) - 1; level >= downTo; level-- {
+		values, err := r[level].finish()
+		if err != nil {
+			return nil, err
+		}
+		r[level].reset()
+
+		// the grouping keys that are rolled up at this level are NULL in the super-aggregate row
+		for _, gb := range oa.GroupByKeys[level:] {
+			values[gb.KeyCol] = sqltypes.NULL
+			if gb.WeightStringCol >= 0 {
+				values[gb.WeightStringCol] = sqltypes.NULL
+			}
+		}
+		for _, aggr := range oa.Aggregates {
+			if aggr.Opcode != opcode.AggregateGrouping {
+				continue
+			}
+			// GROUPING(a, b, ...) returns a bitmask with one bit per argument,
+			// the last argument being the lowest bit
+			var bits int64
+			for _, key := range aggr.GroupingKeys {
+				bits <<= 1
+				if key >= level {
+					bits |= 1
+				}
+			}
+			values[aggr.Col] = sqltypes.NewInt64(bits)
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// rollupFields marks the grouping key fields as nullable, since the super-aggregate rows have NULL in them
+func (oa *OrderedAggregate) rollupFields(fields []*querypb.Field) []*querypb.Field {
+	if !oa.WithRollup {
+		return fields
+	}
+	for _, gb := range oa.GroupByKeys {
+		if gb.KeyCol < len(fields) {
+			fields[gb.KeyCol].Flags &^= uint32(querypb.MySqlFlag_NOT_NULL_FLAG)
+		}
+	}
+	return fields
 }
 
 func aggregateParamsToString(in any) string {
@@ -378,6 +506,9 @@ func (oa *OrderedAggregate) description() PrimitiveDescription {
 	if oa.TruncateColumnCount > 0 {
 		other["ResultColumns"] = oa.TruncateColumnCount
 	}
+	if oa.WithRollup {
+		other["WithRollup"] = true
+	}
 	return PrimitiveDescription{
 		OperatorType: "Aggregate",
 		Variant:      "Ordered",
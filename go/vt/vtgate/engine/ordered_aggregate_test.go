@@ -32,6 +32,8 @@ import (
 	"vitess.io/vitess/go/test/utils"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
 	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
 )
 
@@ -1189,3 +1191,369 @@ func TestGroupConcat(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderedAggregateWithRollup(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|b|grouping(a, b)|count(*)",
+		"varbinary|int64|int64|int64",
+	)
+	newInput := func() *fakePrimitive {
+		return &fakePrimitive{
+			results: []*sqltypes.Result{sqltypes.MakeTestResult(
+				fields,
+				"x|1|0|1",
+				"x|1|0|2",
+				"x|2|0|3",
+				"y|1|0|4",
+			)},
+		}
+	}
+
+	count := NewAggregateParam(AggregateSum, 3, nil, "", collations.MySQL8())
+	count.OrigOpcode = AggregateCountStar
+	grouping := NewAggregateParam(AggregateGrouping, 2, nil, "", collations.MySQL8())
+	grouping.GroupingKeys = []int{0, 1}
+
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{grouping, count},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}, {KeyCol: 1, WeightStringCol: -1}},
+		WithRollup:  true,
+		Input:       newInput(),
+	}
+
+	wantResult := sqltypes.MakeTestResult(
+		fields,
+		"x|1|0|3",
+		"x|2|0|3",
+		"x|null|1|6",
+		"y|1|0|4",
+		"y|null|1|4",
+		"null|null|3|10",
+	)
+
+	result, err := oa.TryExecute(t.Context(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, wantResult, result)
+
+	oa.Input = newInput()
+	var results []*sqltypes.Result
+	err = oa.TryStreamExecute(t.Context(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		results = append(results, qr)
+		return nil
+	})
+	require.NoError(t, err)
+
+	wantResults := sqltypes.MakeTestStreamingResults(
+		fields,
+		"x|1|0|3",
+		"---",
+		"x|2|0|3",
+		"x|null|1|6",
+		"---",
+		"y|1|0|4",
+		"y|null|1|4",
+		"null|null|3|10",
+	)
+	utils.MustMatch(t, wantResults, results)
+}
+
+func TestOrderedAggregateWithRollupNoAggregates(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|b",
+		"varbinary|varbinary",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"x|1",
+			"x|2",
+			"y|1",
+		)},
+	}
+
+	oa := &OrderedAggregate{
+		GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}, {KeyCol: 1, WeightStringCol: -1}},
+		WithRollup:  true,
+		Input:       fp,
+	}
+
+	result, err := oa.TryExecute(t.Context(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		fields,
+		"x|1",
+		"x|2",
+		"x|null",
+		"y|1",
+		"y|null",
+		"null|null",
+	)
+	utils.MustMatch(t, wantResult, result)
+}
+
+func TestOrderedAggregateWithRollupEmptyInput(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|count(*)",
+		"varbinary|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(fields)},
+	}
+
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{NewAggregateParam(AggregateSum, 1, nil, "", collations.MySQL8())},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}},
+		WithRollup:  true,
+		Input:       fp,
+	}
+
+	result, err := oa.TryExecute(t.Context(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Rows)
+}
+
+// TestMultiDistinctHashed tests distinct aggregations on columns the input is not sorted by.
+func TestMultiDistinctHashed(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3",
+		"int64|int64|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"10|1|3",
+			"10|1|1",
+			"10|2|3",
+			"10|2|null",
+			"10|3|1",
+			"20|null|null",
+			"30|1|2",
+			"30|2|1",
+			"30|3|2",
+		)},
+	}
+
+	hashed := NewAggregateParam(AggregateSumDistinct, 2, nil, "sum(distinct c3)", collations.MySQL8())
+	hashed.HashDistinct = true
+	oa := &OrderedAggregate{
+		Aggregates: []*AggregateParams{
+			NewAggregateParam(AggregateCountDistinct, 1, nil, "count(distinct c2)", collations.MySQL8()),
+			hashed,
+		},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+		Input:       fp,
+	}
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"c1|count(distinct c2)|sum(distinct c3)",
+			"int64|int64|decimal",
+		),
+		`10|3|4`,
+		`20|0|null`,
+		`30|3|3`,
+	)
+
+	qr, err := oa.TryExecute(t.Context(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, want, qr)
+
+	fp.rewind()
+	results := &sqltypes.Result{}
+	err = oa.TryStreamExecute(t.Context(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		if qr.Fields != nil {
+			results.Fields = qr.Fields
+		}
+		results.Rows = append(results.Rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, want, results)
+}
+
+// TestGroupConcatFromRows tests group_concat computed on the engine from the individual values of each group.
+func TestGroupConcatFromRows(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3|c4",
+		"int64|varchar|varchar|int64",
+	)
+	input := sqltypes.MakeTestResult(fields,
+		"10|b|x|2", "10|a|y|3", "10|b|x|1", "10|c|null|4",
+		"20|a|x|1",
+		"30|null|x|1",
+		"40|b|y|1", "40|b|x|2", "40|a|x|3")
+	outFields := sqltypes.MakeTestFields(
+		"c1|group_concat",
+		"int64|text",
+	)
+
+	tcases := []struct {
+		name     string
+		distinct bool
+		cols     []int
+		orderBy  evalengine.Comparison
+		maxLen   string
+		expected *sqltypes.Result
+	}{{
+		name:     "multiple columns",
+		cols:     []int{1, 2},
+		expected: sqltypes.MakeTestResult(outFields, `10|bx-ay-bx`, `20|ax`, `30|null`, `40|by-bx-ax`),
+	}, {
+		name:     "distinct",
+		distinct: true,
+		cols:     []int{1},
+		expected: sqltypes.MakeTestResult(outFields, `10|b-a-c`, `20|a`, `30|null`, `40|b-a`),
+	}, {
+		name:     "order by",
+		cols:     []int{1},
+		orderBy:  evalengine.Comparison{{Col: 3, WeightStringCol: -1, Desc: true, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)}},
+		expected: sqltypes.MakeTestResult(outFields, `10|c-a-b-b`, `20|a`, `30|null`, `40|a-b-b`),
+	}, {
+		name:     "distinct with order by",
+		distinct: true,
+		cols:     []int{1, 2},
+		orderBy:  evalengine.Comparison{{Col: 1, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.VarChar, collations.MySQL8().DefaultConnectionCharset())}},
+		expected: sqltypes.MakeTestResult(outFields, `10|ay-bx`, `20|ax`, `30|null`, `40|ax-by-bx`),
+	}, {
+		name:     "truncated to group_concat_max_len",
+		cols:     []int{1, 2},
+		maxLen:   "7",
+		expected: sqltypes.MakeTestResult(outFields, `10|bx-ay-b`, `20|ax`, `30|null`, `40|by-bx-a`),
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			fp := &fakePrimitive{results: []*sqltypes.Result{input}}
+			agp := NewAggregateParam(AggregateGroupConcat, 1, nil, "group_concat", collations.MySQL8())
+			agp.Func = &sqlparser.GroupConcatExpr{Distinct: tcase.distinct, Separator: "-"}
+			agp.GroupConcat = &GroupConcatParams{Cols: tcase.cols, OrderBy: tcase.orderBy}
+			oa := &OrderedAggregate{
+				Aggregates:          []*AggregateParams{agp},
+				GroupByKeys:         []*GroupByParams{{KeyCol: 0}},
+				TruncateColumnCount: 2,
+				Input:               fp,
+			}
+			vc := &loggingVCursor{}
+			if tcase.maxLen != "" {
+				vc.systemVariables = map[string]string{"group_concat_max_len": tcase.maxLen}
+			}
+
+			qr, err := oa.TryExecute(t.Context(), vc, nil, false)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.expected.Rows, qr.Rows)
+			assert.Equal(t, sqltypes.Text, qr.Fields[1].Type)
+
+			fp.rewind()
+			results := &sqltypes.Result{}
+			err = oa.TryStreamExecute(t.Context(), vc, nil, true, func(qr *sqltypes.Result) error {
+				results.Rows = append(results.Rows, qr.Rows...)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tcase.expected.Rows, results.Rows)
+		})
+	}
+}
+
+// TestAggregationMemoryLimits tests that the rows kept for group_concat and the values remembered
+// by hashed distinct aggregations are charged to the memory budget and to the in-memory row limit.
+func TestAggregationMemoryLimits(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2",
+		"int64|varchar",
+	)
+	input := sqltypes.MakeTestResult(fields, "10|a", "10|b", "10|c", "10|d", "20|e")
+
+	groupConcat := func() *AggregateParams {
+		agp := NewAggregateParam(AggregateGroupConcat, 1, nil, "group_concat", collations.MySQL8())
+		agp.Func = &sqlparser.GroupConcatExpr{Separator: ","}
+		agp.GroupConcat = &GroupConcatParams{Cols: []int{1}}
+		return agp
+	}
+	countDistinct := func() *AggregateParams {
+		agp := NewAggregateParam(AggregateCountDistinct, 1, nil, "count(distinct c2)", collations.MySQL8())
+		agp.HashDistinct = true
+		return agp
+	}
+	// rowSize of the rows of the input, which all have the same size
+	size := rowSize(input.Rows[0])
+
+	tcases := []struct {
+		name      string
+		aggr      *AggregateParams
+		budget    int64
+		maxRows   int
+		maxLen    string
+		expectErr string
+	}{{
+		name:      "group_concat over the memory budget",
+		aggr:      groupConcat(),
+		budget:    3 * size,
+		expectErr: fmt.Sprintf("aggregation exceeded the memory budget of %d bytes", 3*size),
+	}, {
+		name:   "group_concat within the memory budget",
+		aggr:   groupConcat(),
+		budget: 4 * size,
+	}, {
+		name:      "group_concat over the row limit",
+		aggr:      groupConcat(),
+		maxRows:   3,
+		expectErr: "in-memory row count exceeded allowed limit of 3",
+	}, {
+		name:    "group_concat stops keeping rows at group_concat_max_len",
+		aggr:    groupConcat(),
+		budget:  2 * size,
+		maxRows: 2,
+		maxLen:  "1",
+	}, {
+		name:      "hashed distinct over the memory budget",
+		aggr:      countDistinct(),
+		budget:    3 * seenValueSize,
+		expectErr: fmt.Sprintf("aggregation exceeded the memory budget of %d bytes", 3*seenValueSize),
+	}, {
+		name:   "hashed distinct within the memory budget",
+		aggr:   countDistinct(),
+		budget: 4 * seenValueSize,
+	}, {
+		name:      "hashed distinct over the row limit",
+		aggr:      countDistinct(),
+		maxRows:   3,
+		expectErr: "in-memory row count exceeded allowed limit of 3",
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			if tcase.maxRows > 0 {
+				saveMax := testMaxMemoryRows
+				testMaxMemoryRows = tcase.maxRows
+				defer func() {
+					testMaxMemoryRows = saveMax
+				}()
+			}
+			oa := &OrderedAggregate{
+				Aggregates:  []*AggregateParams{tcase.aggr},
+				GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+				Input:       &fakePrimitive{results: []*sqltypes.Result{input}},
+			}
+			vc := &loggingVCursor{}
+			if tcase.budget > 0 {
+				vc.memoryBudget = NewMemoryBudget(tcase.budget, "")
+			}
+			if tcase.maxLen != "" {
+				vc.systemVariables = map[string]string{"group_concat_max_len": tcase.maxLen}
+			}
+
+			_, err := oa.TryExecute(t.Context(), vc, nil, false)
+			if tcase.expectErr != "" {
+				require.EqualError(t, err, tcase.expectErr)
+				assert.Equal(t, vtrpcpb.Code_ABORTED, vterrors.Code(err))
+			} else {
+				require.NoError(t, err)
+			}
+			if vc.memoryBudget != nil {
+				assert.Zero(t, vc.memoryBudget.Used())
+			}
+		})
+	}
+}
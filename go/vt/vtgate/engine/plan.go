@@ -51,6 +51,8 @@ type (
 		ParamsCount  uint16                  // ParamsCount is the total number of bind parameters (?) in the query.
 		Optimized    atomic.Bool             // Prepared queries need to be optimized before the first execution
 
+		ResultCacheTTL time.Duration // ResultCacheTTL is how long the results of this query may be kept in the result cache.
+
 		ExecCount    uint64 // ExecCount is how many times this plan has been executed.
 		ExecTime     uint64 // ExecTime is the total accumulated execution time in nanoseconds.
 		ShardQueries uint64 // ShardQueries is the total count of shard-level queries performed.
@@ -355,6 +357,8 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		RowsReturned uint64                `json:",omitempty"`
 		Errors       uint64                `json:",omitempty"`
 		TablesUsed   []string              `json:",omitempty"`
+
+		ResultCacheTTL time.Duration `json:",omitempty"`
 	}{
 		Type:         p.Type.String(),
 		QueryType:    p.QueryType.String(),
@@ -367,6 +371,8 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		RowsReturned: atomic.LoadUint64(&p.RowsReturned),
 		Errors:       atomic.LoadUint64(&p.Errors),
 		TablesUsed:   p.TablesUsed,
+
+		ResultCacheTTL: p.ResultCacheTTL,
 	}
 
 	b := new(bytes.Buffer)
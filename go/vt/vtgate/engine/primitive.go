@@ -58,6 +58,10 @@ type (
 		// if the max memory rows override directive is set to true
 		ExceedsMaxMemoryRows(numRows int) bool
 
+		// MemoryBudget returns the budget for rows buffered in memory by this query,
+		// or nil if buffered rows should never be spilled to disk
+		MemoryBudget() *MemoryBudget
+
 		Execute(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
 		AutocommitApproval() bool
 
@@ -97,6 +101,7 @@ type (
 		ExecuteLock(ctx context.Context, rs *srvtopo.ResolvedShard, query *querypb.BoundQuery, lockFuncType sqlparser.LockingFuncType) (*sqltypes.Result, error)
 
 		InTransactionAndIsDML() bool
+		InTransaction() bool
 
 		LookupRowLockShardSession() vtgatepb.CommitOrder
 
@@ -18,9 +18,11 @@ package engine
 
 import (
 	"context"
+	"strconv"
 
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/vterrors"
 )
 
@@ -37,17 +39,48 @@ type RecurseCTE struct {
 
 var _ Primitive = (*RecurseCTE)(nil)
 
+// defaultCTEMaxRecursionDepth is the default value of cte_max_recursion_depth in MySQL
+const defaultCTEMaxRecursionDepth = 1000
+
 func (r *RecurseCTE) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	res, err := vcursor.ExecutePrimitive(ctx, r.Seed, bindVars, wantfields)
 	if err != nil {
 		return nil, err
 	}
 
+	// the rows of the CTE can't be spilled to disk, the memory budget is a hard limit for them
+	budget := vcursor.MemoryBudget()
+	var reserved int64
+	defer func() {
+		if budget != nil {
+			budget.shrink(reserved)
+		}
+	}()
+	reserve := func(rows []sqltypes.Row) error {
+		if budget == nil {
+			return nil
+		}
+		for _, row := range rows {
+			size := rowSize(row)
+			reserved += size
+			if !budget.grow(size) {
+				return vterrors.Errorf(vtrpcpb.Code_ABORTED, "recursive CTE exceeded the memory budget of %d bytes", budget.limit)
+			}
+		}
+		return nil
+	}
+	if err := reserve(res.Rows); err != nil {
+		return nil, err
+	}
+
 	// recurseRows contains the rows used in the next recursion
 	recurseRows := res.Rows
 	joinVars := make(map[string]*querypb.BindVariable)
-	loops := 0
-	for len(recurseRows) > 0 {
+	maxDepth := cteMaxRecursionDepth(vcursor)
+	for depth := 0; len(recurseRows) > 0; depth++ {
+		if depth >= maxDepth {
+			return nil, vterrors.VT09030(maxDepth)
+		}
 		// copy over the results from the previous recursion
 		theseRows := recurseRows
 		recurseRows = nil
@@ -65,9 +98,11 @@ func (r *RecurseCTE) TryExecute(ctx context.Context, vcursor VCursor, bindVars m
 			}
 			recurseRows = append(recurseRows, rresult.Rows...)
 			res.Rows = append(res.Rows, rresult.Rows...)
-			loops++
-			if loops > 1000 { // TODO: This should be controlled with a system variable setting
-				return nil, vterrors.VT09030("")
+			if vcursor.ExceedsMaxMemoryRows(len(res.Rows)) {
+				return nil, vterrors.Errorf(vtrpcpb.Code_ABORTED, "in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+			}
+			if err := reserve(rresult.Rows); err != nil {
+				return nil, err
 			}
 		}
 	}
@@ -82,19 +117,23 @@ func (r *RecurseCTE) TryStreamExecute(ctx context.Context, vcursor VCursor, bind
 		}
 		return callback(res)
 	}
+	maxDepth := cteMaxRecursionDepth(vcursor)
 	return vcursor.StreamExecutePrimitive(ctx, r.Seed, bindVars, wantfields, func(result *sqltypes.Result) error {
 		err := callback(result)
 		if err != nil {
 			return err
 		}
-		return r.recurse(ctx, vcursor, bindVars, result, callback)
+		return r.recurse(ctx, vcursor, bindVars, result, 0, maxDepth, callback)
 	})
 }
 
-func (r *RecurseCTE) recurse(ctx context.Context, vcursor VCursor, bindvars map[string]*querypb.BindVariable, result *sqltypes.Result, callback func(*sqltypes.Result) error) error {
+func (r *RecurseCTE) recurse(ctx context.Context, vcursor VCursor, bindvars map[string]*querypb.BindVariable, result *sqltypes.Result, depth, maxDepth int, callback func(*sqltypes.Result) error) error {
 	if len(result.Rows) == 0 {
 		return nil
 	}
+	if depth >= maxDepth {
+		return vterrors.VT09030(maxDepth)
+	}
 	joinVars := make(map[string]*querypb.BindVariable)
 	for _, row := range result.Rows {
 		for k, col := range r.Vars {
@@ -106,7 +145,7 @@ func (r *RecurseCTE) recurse(ctx context.Context, vcursor VCursor, bindvars map[
 			if err != nil {
 				return err
 			}
-			return r.recurse(ctx, vcursor, bindvars, result, callback)
+			return r.recurse(ctx, vcursor, bindvars, result, depth+1, maxDepth, callback)
 		})
 		if err != nil {
 			return err
@@ -115,6 +154,21 @@ func (r *RecurseCTE) recurse(ctx context.Context, vcursor VCursor, bindvars map[
 	return nil
 }
 
+// cteMaxRecursionDepth returns the value of cte_max_recursion_depth for the session,
+// which is the maximum number of iterations of a recursive CTE
+func cteMaxRecursionDepth(vcursor VCursor) int {
+	maxDepth := defaultCTEMaxRecursionDepth
+	vcursor.Session().GetSystemVariables(func(k string, v string) {
+		if k != "cte_max_recursion_depth" {
+			return
+		}
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n >= 0 {
+			maxDepth = int(n)
+		}
+	})
+	return maxDepth
+}
+
 func (r *RecurseCTE) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
 	return r.Seed.GetFields(ctx, vcursor, bindVars)
 }
@@ -126,3 +126,100 @@ func TestRecurseDualQuery(t *testing.T) {
 	})
 	expectResult(t, r, wantRes)
 }
+
+func TestRecurseExecuteMaxMemoryRows(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	saveIgnore := testIgnoreMaxMemoryRows
+	testMaxMemoryRows = 3
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testIgnoreMaxMemoryRows = saveIgnore
+	}()
+
+	testCases := []struct {
+		ignoreMaxMemoryRows bool
+		err                 string
+	}{
+		{true, ""},
+		{false, "in-memory row count exceeded allowed limit of 3"},
+	}
+	for _, test := range testCases {
+		fields := sqltypes.MakeTestFields("col1", "int64")
+		cte := &RecurseCTE{
+			Seed: &fakePrimitive{
+				results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "1")},
+			},
+			Term: &fakePrimitive{
+				results: []*sqltypes.Result{
+					sqltypes.MakeTestResult(fields, "2"),
+					sqltypes.MakeTestResult(fields, "3"),
+					sqltypes.MakeTestResult(fields, "4"),
+					sqltypes.MakeTestResult(fields),
+				},
+			},
+			Vars: map[string]int{"col1": 0},
+		}
+
+		testIgnoreMaxMemoryRows = test.ignoreMaxMemoryRows
+		_, err := cte.TryExecute(t.Context(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+		if test.ignoreMaxMemoryRows {
+			require.NoError(t, err)
+		} else {
+			require.EqualError(t, err, test.err)
+		}
+	}
+}
+
+func TestRecurseMaxRecursionDepth(t *testing.T) {
+	fields := sqltypes.MakeTestFields("col1", "int64")
+	newCTE := func() *RecurseCTE {
+		return &RecurseCTE{
+			Seed: &fakePrimitive{
+				results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "1")},
+			},
+			Term: &fakePrimitive{
+				results: []*sqltypes.Result{
+					sqltypes.MakeTestResult(fields, "2"),
+					sqltypes.MakeTestResult(fields, "3"),
+					sqltypes.MakeTestResult(fields, "4"),
+					sqltypes.MakeTestResult(fields),
+				},
+			},
+			Vars: map[string]int{"col1": 0},
+		}
+	}
+
+	vc := &loggingVCursor{systemVariables: map[string]string{"cte_max_recursion_depth": "2"}}
+	_, err := newCTE().TryExecute(t.Context(), vc, map[string]*querypb.BindVariable{}, true)
+	require.EqualError(t, err, "VT09030: Recursive query aborted after 2 iterations. Try increasing @@cte_max_recursion_depth to a larger value.")
+
+	err = newCTE().TryStreamExecute(t.Context(), vc, map[string]*querypb.BindVariable{}, true, func(*sqltypes.Result) error { return nil })
+	require.EqualError(t, err, "VT09030: Recursive query aborted after 2 iterations. Try increasing @@cte_max_recursion_depth to a larger value.")
+
+	vc.systemVariables["cte_max_recursion_depth"] = "4"
+	res, err := newCTE().TryExecute(t.Context(), vc, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	require.Len(t, res.Rows, 4)
+}
+
+func TestRecurseExecuteMemoryBudget(t *testing.T) {
+	fields := sqltypes.MakeTestFields("col1", "varchar")
+	cte := &RecurseCTE{
+		Seed: &fakePrimitive{
+			results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "a")},
+		},
+		Term: &fakePrimitive{
+			results: []*sqltypes.Result{
+				sqltypes.MakeTestResult(fields, "b"),
+				sqltypes.MakeTestResult(fields, "c"),
+				sqltypes.MakeTestResult(fields),
+			},
+		},
+		Vars: map[string]int{"col1": 0},
+	}
+
+	budget := NewMemoryBudget(2*rowSize(sqltypes.Row{sqltypes.NewVarChar("a")}), "")
+	_, err := cte.TryExecute(t.Context(), &noopVCursor{memoryBudget: budget}, map[string]*querypb.BindVariable{}, true)
+	require.EqualError(t, err, fmt.Sprintf("recursive CTE exceeded the memory budget of %d bytes", budget.limit))
+	require.Zero(t, budget.Used())
+}
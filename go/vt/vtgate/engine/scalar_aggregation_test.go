@@ -19,14 +19,17 @@ package engine
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
 	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
 )
@@ -420,3 +423,68 @@ func TestScalarGroupConcat(t *testing.T) {
 		})
 	}
 }
+
+// TestScalarGroupConcatMaxLen tests that group_concat is truncated to group_concat_max_len
+// without splitting multibyte characters.
+func TestScalarGroupConcatMaxLen(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"group_concat(c2)",
+		"text",
+	)
+	fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "añ", "b", "ñc")}}
+	oa := &ScalarAggregate{
+		Aggregates: []*AggregateParams{{
+			Opcode: AggregateGroupConcat,
+			Col:    0,
+			Func:   &sqlparser.GroupConcatExpr{Separator: ","},
+		}},
+		Input: fp,
+	}
+	vc := &loggingVCursor{systemVariables: map[string]string{"group_concat_max_len": "7"}}
+
+	wantWarnings := []*querypb.QueryWarning{{Code: uint32(sqlerror.ERCutValueGroupConcat), Message: "Row 3 was cut by GROUP_CONCAT()"}}
+
+	qr, err := oa.TryExecute(t.Context(), vc, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.MakeTestResult(fields, "añ,b,"), qr)
+	vc.ExpectWarnings(t, wantWarnings)
+
+	fp.rewind()
+	vc.Rewind()
+	results := &sqltypes.Result{}
+	err = oa.TryStreamExecute(t.Context(), vc, nil, true, func(qr *sqltypes.Result) error {
+		if qr.Fields != nil {
+			results.Fields = qr.Fields
+		}
+		results.Rows = append(results.Rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.MakeTestResult(fields, "añ,b,"), results)
+	vc.ExpectWarnings(t, wantWarnings)
+}
+
+// TestScalarGroupConcatWithoutMaxLen tests that group_concat is not truncated
+// when the session doesn't set group_concat_max_len.
+func TestScalarGroupConcatWithoutMaxLen(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"group_concat(c2)",
+		"text",
+	)
+	long := strings.Repeat("a", 1000)
+	fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, long, long)}}
+	oa := &ScalarAggregate{
+		Aggregates: []*AggregateParams{{
+			Opcode: AggregateGroupConcat,
+			Col:    0,
+			Func:   &sqlparser.GroupConcatExpr{Separator: ","},
+		}},
+		Input: fp,
+	}
+	vc := &loggingVCursor{}
+
+	qr, err := oa.TryExecute(t.Context(), vc, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.MakeTestResult(fields, long+","+long), qr)
+	vc.ExpectWarnings(t, nil)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// spillBatchSize is the number of bytes of rows that are sent in each result
+// when reading rows back from disk
+const spillBatchSize = 32 * 1024
+
+// MemoryBudget keeps track of the memory used by the primitives of a query that buffer rows in VTGate.
+// Once the rows held by these primitives go over the budget, they start moving rows to files on disk.
+// A MemoryBudget is shared by all the primitives of a single query, and is safe for concurrent use.
+type MemoryBudget struct {
+	limit int64
+	dir   string
+	used  atomic.Int64
+}
+
+// NewMemoryBudget creates a MemoryBudget of limit bytes. Spill files are created in dir,
+// or in the default directory for temporary files if dir is empty.
+func NewMemoryBudget(limit int64, dir string) *MemoryBudget {
+	return &MemoryBudget{
+		limit: limit,
+		dir:   dir,
+	}
+}
+
+// Used returns the number of bytes currently reserved by the primitives of the query
+func (mb *MemoryBudget) Used() int64 {
+	return mb.used.Load()
+}
+
+// grow reserves n more bytes, and returns false if the query is now over its budget
+func (mb *MemoryBudget) grow(n int64) bool {
+	return mb.used.Add(n) <= mb.limit
+}
+
+// shrink gives back n bytes that were reserved using grow
+func (mb *MemoryBudget) shrink(n int64) {
+	mb.used.Add(-n)
+}
+
+// rowSize is an estimate of the memory used by a row
+func rowSize(row sqltypes.Row) int64 {
+	size := int64(24) // slice header
+	for i := range row {
+		size += row[i].CachedSize(true)
+	}
+	return size
+}
+
+// spillFile stores rows on disk. Rows are first written to it, and after calling rewind, they can be read back
+// in the same order. Each value is stored as its type, followed by the length of the raw value and the raw value.
+type spillFile struct {
+	file *os.File
+	w    *bufio.Writer
+	r    *bufio.Reader
+	size int64
+	buf  []byte
+}
+
+func (mb *MemoryBudget) newSpillFile() (*spillFile, error) {
+	file, err := os.CreateTemp(mb.dir, "vtgate-spill-*")
+	if err != nil {
+		return nil, err
+	}
+	return &spillFile{
+		file: file,
+		w:    bufio.NewWriter(file),
+	}, nil
+}
+
+func (sf *spillFile) write(row sqltypes.Row) error {
+	sf.buf = binary.AppendUvarint(sf.buf[:0], uint64(len(row)))
+	for _, val := range row {
+		sf.buf = binary.AppendUvarint(sf.buf, uint64(val.Type()))
+		sf.buf = binary.AppendUvarint(sf.buf, uint64(len(val.Raw())))
+		sf.buf = append(sf.buf, val.Raw()...)
+	}
+	n, err := sf.w.Write(sf.buf)
+	sf.size += int64(n)
+	return err
+}
+
+// rewind flushes the rows written so far, and prepares the file to be read from the beginning
+func (sf *spillFile) rewind() error {
+	if err := sf.w.Flush(); err != nil {
+		return err
+	}
+	if _, err := sf.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	sf.r = bufio.NewReader(sf.file)
+	return nil
+}
+
+// read returns the next row in the file, or io.EOF when all the rows have been read
+func (sf *spillFile) read() (sqltypes.Row, error) {
+	cols, err := binary.ReadUvarint(sf.r)
+	if err != nil {
+		return nil, err
+	}
+	row := make(sqltypes.Row, cols)
+	for i := range row {
+		typ, err := binary.ReadUvarint(sf.r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		length, err := binary.ReadUvarint(sf.r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		raw := make([]byte, length)
+		if _, err := io.ReadFull(sf.r, raw); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		row[i] = sqltypes.MakeTrusted(querypb.Type(typ), raw)
+	}
+	return row, nil
+}
+
+// close closes and removes the file
+func (sf *spillFile) close() {
+	_ = sf.file.Close()
+	_ = os.Remove(sf.file.Name())
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestSpillFile(t *testing.T) {
+	dir := t.TempDir()
+	budget := NewMemoryBudget(100, dir)
+
+	rows := []sqltypes.Row{
+		{sqltypes.NewInt64(1), sqltypes.NewVarChar("foo"), sqltypes.NULL},
+		{sqltypes.NewInt64(-2), sqltypes.NewVarChar(""), sqltypes.NewVarBinary("\x00bar")},
+		{},
+		{sqltypes.NewDecimal("3.14"), sqltypes.NewFloat64(2.5), sqltypes.NewDatetime("2025-01-02 03:04:05")},
+	}
+
+	sf, err := budget.newSpillFile()
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NoError(t, sf.write(row))
+	}
+	require.NoError(t, sf.rewind())
+	assert.NotZero(t, sf.size)
+
+	for _, want := range rows {
+		got, err := sf.read()
+		require.NoError(t, err)
+		require.Len(t, got, len(want))
+		for i := range want {
+			assert.Equal(t, want[i].Type(), got[i].Type())
+			assert.Equal(t, want[i].Raw(), got[i].Raw())
+		}
+	}
+	_, err = sf.read()
+	assert.ErrorIs(t, err, io.EOF)
+
+	sf.close()
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestMemoryBudget(t *testing.T) {
+	budget := NewMemoryBudget(100, "")
+	assert.True(t, budget.grow(60))
+	assert.False(t, budget.grow(60))
+	budget.shrink(60)
+	assert.True(t, budget.grow(10))
+	assert.EqualValues(t, 70, budget.Used())
+}
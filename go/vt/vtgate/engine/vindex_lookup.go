@@ -156,10 +156,22 @@ func (vr *VindexLookup) lookup(ctx context.Context, vcursor VCursor, ids []sqlty
 		vcursor.Session().SetCommitOrder(co)
 		defer vcursor.Session().SetCommitOrder(vtgatepb.CommitOrder_NORMAL)
 	}
-	if ids[0].IsIntegral() || vr.Vindex.AllowBatch() {
-		return vr.executeBatch(ctx, vcursor, ids)
+	return vr.lookupCache(vcursor).Lookup(vcursor.ConnCollation(), ids, func(ids []sqltypes.Value) ([]*sqltypes.Result, error) {
+		if ids[0].IsIntegral() || vr.Vindex.AllowBatch() {
+			return vr.executeBatch(ctx, vcursor, ids)
+		}
+		return vr.executeNonBatch(ctx, vcursor, ids)
+	})
+}
+
+// lookupCache returns the cache of the vindex, if it has one and the
+// lookup is not done inside of a transaction.
+func (vr *VindexLookup) lookupCache(vcursor VCursor) *vindexes.LookupCache {
+	lc, ok := vr.Vindex.(vindexes.LookupCaching)
+	if !ok || vcursor.InTransaction() {
+		return nil
 	}
-	return vr.executeNonBatch(ctx, vcursor, ids)
+	return lc.LookupCache()
 }
 
 func (vr *VindexLookup) executeNonBatch(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]*sqltypes.Result, error) {
@@ -133,3 +133,61 @@ func TestVindexLookupTruncate(t *testing.T) {
 	})
 	expectResult(t, result, wantRes)
 }
+
+func TestVindexLookupCache(t *testing.T) {
+	cachedVindex, err := vindexes.CreateVindex("lookup_unique", "", map[string]string{
+		"table":      "lkp",
+		"from":       "from",
+		"to":         "toc",
+		"cache_size": "10",
+	})
+	require.NoError(t, err)
+	planableVindex := cachedVindex.(vindexes.LookupPlanable)
+	_, args := planableVindex.Query()
+
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields("id|keyspace_id", "int64|varbinary"),
+				"1|\x10"),
+		},
+	}
+	route := NewRoute(ByDestination, ks, "dummy_select", "dummy_select_field")
+	vdxLookup := &VindexLookup{
+		Opcode:    EqualUnique,
+		Keyspace:  ks,
+		Vindex:    planableVindex,
+		Arguments: args,
+		Values:    []evalengine.Expr{evalengine.NewLiteralInt(1)},
+		Lookup:    fp,
+		SendTo:    route,
+	}
+	wantLookup := fmt.Sprintf(`Execute from: %v false`, &querypb.BindVariable{Type: querypb.Type_TUPLE, Values: []*querypb.Value{{Type: querypb.Type_INT64, Value: []byte("1")}}})
+	wantRoute := []string{
+		fmt.Sprintf(`ResolveDestinations ks [%v] Destinations:DestinationKeyspaceID(10)`, sqltypes.Int64BindVariable(1)),
+		`ExecuteMultiShard ks.-20: dummy_select {} false false`,
+	}
+
+	vc := &loggingVCursor{results: []*sqltypes.Result{defaultSelectResult}}
+	_, err = vdxLookup.TryExecute(t.Context(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	fp.ExpectLog(t, []string{wantLookup})
+
+	// The second execution is routed using the cached lookup result.
+	fp.rewind()
+	vc.Rewind()
+	result, err := vdxLookup.TryExecute(t.Context(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	fp.ExpectLog(t, nil)
+	vc.ExpectLog(t, wantRoute)
+	expectResult(t, result, defaultSelectResult)
+	require.EqualValues(t, 1, cachedVindex.(vindexes.LookupCaching).LookupCache().Hits())
+
+	// Inside of a transaction the cache is bypassed.
+	fp.rewind()
+	vc.Rewind()
+	vc.inTx = true
+	_, err = vdxLookup.TryExecute(t.Context(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	fp.ExpectLog(t, []string{wantLookup})
+}
@@ -0,0 +1,548 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*Window)(nil)
+
+// Window is a primitive that evaluates window functions in vtgate. It expects the underlying
+// primitive to feed rows sorted by the PartitionBy keys and then by the OrderBy keys, which
+// is what a scatter route with a merge sort does. Each window function reads its argument
+// from its column of the input rows, and its result replaces that column in the output.
+type Window struct {
+	// PartitionBy specifies the keys of the partitions of the window.
+	PartitionBy []*GroupByParams
+
+	// OrderBy specifies the keys used to find the peers of a row within its partition.
+	OrderBy []*GroupByParams
+
+	// Functions specifies the window functions to evaluate.
+	Functions []*WindowFunctionParams
+
+	// TruncateColumnCount specifies the number of columns to return
+	// in the final result. Rest of the columns are truncated
+	// from the result received. If 0, no truncation happens.
+	TruncateColumnCount int
+
+	// Input is the primitive that will feed into this Primitive.
+	Input Primitive
+}
+
+// WindowFunctionParams specify the parameters for each window function.
+type WindowFunctionParams struct {
+	// Opcode is the window function, or WindowUnassigned if this is an aggregate
+	// function used as a window function, in which case AggrOpcode is set.
+	Opcode     opcode.WindowOpcode
+	AggrOpcode opcode.AggregateOpcode
+
+	// Col is the column that holds the argument of the function, and receives its result.
+	Col int
+
+	// N is the number of buckets of NTILE(), the position of NTH_VALUE(),
+	// or the offset of LAG() and LEAD().
+	N int64
+
+	// Default is the default value of LAG() and LEAD(). NULL is used when it is nil.
+	Default evalengine.Expr
+
+	// Frame is used by FIRST_VALUE(), LAST_VALUE(), NTH_VALUE() and the aggregate functions.
+	Frame evalengine.WindowFrame
+
+	Type         evalengine.Type
+	Alias        string
+	CollationEnv *collations.Environment
+}
+
+func (wf *WindowFunctionParams) isAggregate() bool {
+	return wf.Opcode == opcode.WindowUnassigned
+}
+
+func (wf *WindowFunctionParams) usesFrame() bool {
+	switch wf.Opcode {
+	case opcode.WindowUnassigned, opcode.WindowFirstValue, opcode.WindowLastValue, opcode.WindowNthValue:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns a string. Used for plan descriptions
+func (wf *WindowFunctionParams) String() string {
+	name := wf.Opcode.String()
+	if wf.isAggregate() {
+		name = wf.AggrOpcode.String()
+	}
+
+	var args []string
+	switch wf.Opcode {
+	case opcode.WindowRowNumber, opcode.WindowRank, opcode.WindowDenseRank, opcode.WindowPercentRank, opcode.WindowCumeDist:
+	case opcode.WindowNtile:
+		args = append(args, strconv.FormatInt(wf.N, 10))
+	case opcode.WindowLag, opcode.WindowLead:
+		args = append(args, strconv.Itoa(wf.Col), strconv.FormatInt(wf.N, 10))
+		if wf.Default != nil {
+			args = append(args, sqlparser.String(wf.Default))
+		}
+	case opcode.WindowNthValue:
+		args = append(args, strconv.Itoa(wf.Col), strconv.FormatInt(wf.N, 10))
+	default:
+		if wf.AggrOpcode != opcode.AggregateCountStar {
+			args = append(args, strconv.Itoa(wf.Col))
+		}
+	}
+
+	out := fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+	if wf.usesFrame() {
+		out += " " + wf.Frame.String()
+	}
+	if wf.Alias != "" {
+		out += " AS " + wf.Alias
+	}
+	return out
+}
+
+func (wf *WindowFunctionParams) typ(inputType querypb.Type) querypb.Type {
+	if wf.isAggregate() {
+		return wf.AggrOpcode.SQLType(inputType)
+	}
+	return wf.Opcode.SQLType(inputType)
+}
+
+// TryExecute is a Primitive function.
+func (w *Window) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	result, err := vcursor.ExecutePrimitive(
+		ctx,
+		w.Input,
+		bindVars,
+		true, /*wantFields - we need the input fields types to correctly calculate the output types*/
+	)
+	if err != nil {
+		return nil, err
+	}
+	if vcursor.ExceedsMaxMemoryRows(len(result.Rows)) {
+		return nil, fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+	}
+
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	out := &sqltypes.Result{
+		Fields: w.fields(result.Fields),
+		Rows:   make([][]sqltypes.Value, 0, len(result.Rows)),
+	}
+
+	start := 0
+	for i := 1; i <= len(result.Rows); i++ {
+		if i < len(result.Rows) {
+			same, err := sameWindowKeys(w.PartitionBy, result.Rows[start], result.Rows[i])
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+		}
+		rows, err := w.evaluatePartition(env, vcursor.ConnCollation(), result.Fields, result.Rows[start:i])
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, rows...)
+		start = i
+	}
+	return out.Truncate(w.TruncateColumnCount), nil
+}
+
+// TryStreamExecute is a Primitive function.
+func (w *Window) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool, callback func(*sqltypes.Result) error) error {
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+
+	var fields []*querypb.Field
+	var partition [][]sqltypes.Value
+
+	flush := func() error {
+		if len(partition) == 0 {
+			return nil
+		}
+		rows, err := w.evaluatePartition(env, vcursor.ConnCollation(), fields, partition)
+		if err != nil {
+			return err
+		}
+		partition = nil
+		qr := &sqltypes.Result{Rows: rows}
+		return callback(qr.Truncate(w.TruncateColumnCount))
+	}
+
+	visitor := func(qr *sqltypes.Result) error {
+		if fields == nil && len(qr.Fields) > 0 {
+			fields = qr.Fields
+			qr := &sqltypes.Result{Fields: w.fields(fields)}
+			if err := callback(qr.Truncate(w.TruncateColumnCount)); err != nil {
+				return err
+			}
+		}
+		for _, row := range qr.Rows {
+			if len(partition) > 0 {
+				same, err := sameWindowKeys(w.PartitionBy, partition[0], row)
+				if err != nil {
+					return err
+				}
+				if !same {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+			}
+			partition = append(partition, row)
+			if vcursor.ExceedsMaxMemoryRows(len(partition)) {
+				return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+			}
+		}
+		return nil
+	}
+
+	/* we need the input fields types to correctly calculate the output types */
+	if err := vcursor.StreamExecutePrimitive(ctx, w.Input, bindVars, true, visitor); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// GetFields is a Primitive function.
+func (w *Window) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := w.Input.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	qr = &sqltypes.Result{Fields: w.fields(qr.Fields)}
+	return qr.Truncate(w.TruncateColumnCount), nil
+}
+
+// Inputs returns the Primitive input for this window
+func (w *Window) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{w.Input}, nil
+}
+
+// NeedsTransaction implements the Primitive interface
+func (w *Window) NeedsTransaction() bool {
+	return w.Input.NeedsTransaction()
+}
+
+func (w *Window) fields(fields []*querypb.Field) []*querypb.Field {
+	fields = slice.Map(fields, func(from *querypb.Field) *querypb.Field { return from.CloneVT() })
+	for _, wf := range w.Functions {
+		if wf.Col >= len(fields) {
+			continue
+		}
+		fields[wf.Col].Type = wf.typ(fields[wf.Col].Type)
+		if wf.Alias != "" {
+			fields[wf.Col].Name = wf.Alias
+		}
+	}
+	return fields
+}
+
+// windowPeers holds, for every row of a partition, the positions of the first and last
+// of its peers, and the position of its peer group within the partition.
+type windowPeers struct {
+	start, end, group []int
+}
+
+func (w *Window) findPeers(rows [][]sqltypes.Value) (*windowPeers, error) {
+	peers := &windowPeers{
+		start: make([]int, len(rows)),
+		end:   make([]int, len(rows)),
+		group: make([]int, len(rows)),
+	}
+	start, group := 0, 0
+	for i := 1; i <= len(rows); i++ {
+		if i < len(rows) {
+			same, err := sameWindowKeys(w.OrderBy, rows[start], rows[i])
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+		}
+		for j := start; j < i; j++ {
+			peers.start[j], peers.end[j], peers.group[j] = start, i-1, group
+		}
+		start = i
+		group++
+	}
+	return peers, nil
+}
+
+// evaluatePartition evaluates the window functions on the rows of a partition,
+// and returns the rows with the results of the functions.
+func (w *Window) evaluatePartition(env *evalengine.ExpressionEnv, coll collations.ID, fields []*querypb.Field, rows [][]sqltypes.Value) ([][]sqltypes.Value, error) {
+	peers, err := w.findPeers(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// The arguments of all the functions must be read before any result is written.
+	results := make([][]sqltypes.Value, len(w.Functions))
+	for i, wf := range w.Functions {
+		var sourceType querypb.Type
+		if wf.Col < len(fields) {
+			sourceType = fields[wf.Col].Type
+		}
+		results[i], err = wf.evaluate(env, coll, sourceType, rows, peers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([][]sqltypes.Value, len(rows))
+	for r, row := range rows {
+		row = slices.Clone(row)
+		for i, wf := range w.Functions {
+			row[wf.Col] = results[i][r]
+		}
+		out[r] = row
+	}
+	return out, nil
+}
+
+func (wf *WindowFunctionParams) evaluate(env *evalengine.ExpressionEnv, coll collations.ID, sourceType querypb.Type, rows [][]sqltypes.Value, peers *windowPeers) ([]sqltypes.Value, error) {
+	size := len(rows)
+	out := make([]sqltypes.Value, size)
+
+	switch wf.Opcode {
+	case opcode.WindowRowNumber:
+		for i := range rows {
+			out[i] = sqltypes.NewInt64(int64(i + 1))
+		}
+	case opcode.WindowRank:
+		for i := range rows {
+			out[i] = sqltypes.NewInt64(int64(peers.start[i] + 1))
+		}
+	case opcode.WindowDenseRank:
+		for i := range rows {
+			out[i] = sqltypes.NewInt64(int64(peers.group[i] + 1))
+		}
+	case opcode.WindowPercentRank:
+		for i := range rows {
+			var rank float64
+			if size > 1 {
+				rank = float64(peers.start[i]) / float64(size-1)
+			}
+			out[i] = sqltypes.NewFloat64(rank)
+		}
+	case opcode.WindowCumeDist:
+		for i := range rows {
+			out[i] = sqltypes.NewFloat64(float64(peers.end[i]+1) / float64(size))
+		}
+	case opcode.WindowNtile:
+		for i := range rows {
+			out[i] = sqltypes.NewInt64(ntile(i, size, wf.N))
+		}
+	case opcode.WindowLag, opcode.WindowLead:
+		def := sqltypes.NULL
+		if wf.Default != nil {
+			var err error
+			def, err = eval(env, wf.Default, coll)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for i := range rows {
+			j := int64(i) + wf.N
+			if wf.Opcode == opcode.WindowLag {
+				j = int64(i) - wf.N
+			}
+			if j >= 0 && j < int64(size) {
+				out[i] = rows[j][wf.Col]
+			} else {
+				out[i] = def
+			}
+		}
+	case opcode.WindowFirstValue, opcode.WindowLastValue, opcode.WindowNthValue:
+		for i := range rows {
+			start, end := wf.Frame.Bounds(i, size, peers.start[i], peers.end[i])
+			pos := start
+			switch wf.Opcode {
+			case opcode.WindowLastValue:
+				pos = end
+			case opcode.WindowNthValue:
+				pos = start + int(min(wf.N-1, int64(size)))
+			}
+			if start > end || pos > end {
+				out[i] = sqltypes.NULL
+				continue
+			}
+			out[i] = rows[pos][wf.Col]
+		}
+	case opcode.WindowUnassigned:
+		return wf.evaluateAggregate(sourceType, rows, peers)
+	default:
+		return nil, fmt.Errorf("BUG: unexpected window function opcode %s", wf.Opcode.String())
+	}
+	return out, nil
+}
+
+// ntile returns the bucket of the row at the given position of a partition of the given size,
+// when the partition is divided in n buckets. Like in MySQL, the first buckets get one more row
+// when the rows cannot be evenly distributed.
+func ntile(row, size int, n int64) int64 {
+	perBucket := int64(size) / n
+	extra := int64(size) % n
+	pos := int64(row)
+	if pos < extra*(perBucket+1) {
+		return pos/(perBucket+1) + 1
+	}
+	return (pos-extra*(perBucket+1))/perBucket + extra + 1
+}
+
+func (wf *WindowFunctionParams) evaluateAggregate(sourceType querypb.Type, rows [][]sqltypes.Value, peers *windowPeers) ([]sqltypes.Value, error) {
+	var agg evalengine.Sum
+	switch wf.AggrOpcode {
+	case opcode.AggregateCount, opcode.AggregateCountStar:
+		agg = &windowCount{star: wf.AggrOpcode == opcode.AggregateCountStar}
+	case opcode.AggregateSum:
+		agg = evalengine.NewAggregationSum(sourceType)
+	case opcode.AggregateAvg:
+		agg = evalengine.NewAggregationAvg(sourceType)
+	case opcode.AggregateMin, opcode.AggregateMax:
+		agg = &windowMinMax{
+			minmax: evalengine.NewAggregationMinMax(sourceType, wf.CollationEnv, wf.Type.Collation(), wf.Type.Values()),
+			max:    wf.AggrOpcode == opcode.AggregateMax,
+		}
+	default:
+		return nil, fmt.Errorf("BUG: unexpected window aggregate opcode %s", wf.AggrOpcode.String())
+	}
+
+	size := len(rows)
+	out := make([]sqltypes.Value, size)
+	// next is the position of the next row to add when the frame always starts at the
+	// beginning of the partition, so that the aggregation does not have to start over.
+	next := 0
+	for i := range rows {
+		start, end := wf.Frame.Bounds(i, size, peers.start[i], peers.end[i])
+		if !wf.Frame.StartsAtPartition() {
+			agg.Reset()
+			next = start
+		}
+		for ; next <= end; next++ {
+			if err := agg.Add(rows[next][wf.Col]); err != nil {
+				return nil, err
+			}
+		}
+		out[i] = agg.Result()
+	}
+	return out, nil
+}
+
+// windowCount implements COUNT() and COUNT(*) over a window frame.
+type windowCount struct {
+	n    int64
+	star bool
+}
+
+func (c *windowCount) Add(value sqltypes.Value) error {
+	if c.star || !value.IsNull() {
+		c.n++
+	}
+	return nil
+}
+
+func (c *windowCount) Result() sqltypes.Value {
+	return sqltypes.NewInt64(c.n)
+}
+
+func (c *windowCount) Reset() {
+	c.n = 0
+}
+
+// windowMinMax implements MIN() and MAX() over a window frame.
+type windowMinMax struct {
+	minmax evalengine.MinMax
+	max    bool
+}
+
+func (m *windowMinMax) Add(value sqltypes.Value) error {
+	if m.max {
+		return m.minmax.Max(value)
+	}
+	return m.minmax.Min(value)
+}
+
+func (m *windowMinMax) Result() sqltypes.Value {
+	return m.minmax.Result()
+}
+
+func (m *windowMinMax) Reset() {
+	m.minmax.Reset()
+}
+
+// sameWindowKeys returns true if both rows have the same values for all the given keys.
+func sameWindowKeys(keys []*GroupByParams, row1, row2 []sqltypes.Value) (bool, error) {
+	for _, key := range keys {
+		cmp, err := evalengine.NullsafeCompare(row1[key.KeyCol], row2[key.KeyCol], key.CollationEnv, key.Type.Collation(), key.Type.Values())
+		if err != nil {
+			_, isCollationErr := err.(evalengine.UnsupportedCollationError)
+			if !isCollationErr || key.WeightStringCol == -1 {
+				return false, err
+			}
+			cmp, err = evalengine.NullsafeCompare(row1[key.WeightStringCol], row2[key.WeightStringCol], key.CollationEnv, key.Type.Collation(), key.Type.Values())
+			if err != nil {
+				return false, err
+			}
+		}
+		if cmp != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func windowFunctionParamsToString(in any) string {
+	return in.(*WindowFunctionParams).String()
+}
+
+func (w *Window) description() PrimitiveDescription {
+	other := map[string]any{
+		"Functions": GenericJoin(w.Functions, windowFunctionParamsToString),
+	}
+	if len(w.PartitionBy) > 0 {
+		other["PartitionBy"] = GenericJoin(w.PartitionBy, groupByParamsToString)
+	}
+	if len(w.OrderBy) > 0 {
+		other["OrderBy"] = GenericJoin(w.OrderBy, groupByParamsToString)
+	}
+	if w.TruncateColumnCount > 0 {
+		other["ResultColumns"] = w.TruncateColumnCount
+	}
+	return PrimitiveDescription{
+		OperatorType: "Window",
+		Other:        other,
+	}
+}
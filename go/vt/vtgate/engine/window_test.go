@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func newTestWindow(input Primitive) *Window {
+	collationEnv := collations.MySQL8()
+	rowsAround := evalengine.WindowFrame{
+		Unit:  evalengine.WindowFrameRows,
+		Start: evalengine.WindowFrameBound{Type: evalengine.WindowFramePreceding, Offset: 1},
+		End:   evalengine.WindowFrameBound{Type: evalengine.WindowFrameFollowing, Offset: 1},
+	}
+	return &Window{
+		PartitionBy: []*GroupByParams{{
+			KeyCol:          0,
+			WeightStringCol: -1,
+			Type:            evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID),
+			CollationEnv:    collationEnv,
+		}},
+		OrderBy: []*GroupByParams{{
+			KeyCol:          1,
+			WeightStringCol: -1,
+			Type:            evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+			CollationEnv:    collationEnv,
+		}},
+		Functions: []*WindowFunctionParams{
+			{Opcode: WindowRowNumber, Col: 2, Alias: "rn"},
+			{Opcode: WindowRank, Col: 3, Alias: "rnk"},
+			{Opcode: WindowDenseRank, Col: 4, Alias: "drnk"},
+			{AggrOpcode: AggregateSum, Col: 5, Frame: evalengine.DefaultWindowFrame(), Alias: "s"},
+			{Opcode: WindowLag, Col: 6, N: 1, Default: evalengine.NewLiteralInt(0), Alias: "lg"},
+			{AggrOpcode: AggregateCountStar, Col: 7, Frame: rowsAround, Alias: "cnt"},
+			{Opcode: WindowCumeDist, Col: 8, Alias: "cd"},
+			{Opcode: WindowNtile, Col: 9, N: 2, Alias: "nt"},
+			{Opcode: WindowLastValue, Col: 10, Frame: evalengine.DefaultWindowFrame(), Alias: "lv"},
+		},
+		Input: input,
+	}
+}
+
+func newTestWindowInput() *fakePrimitive {
+	return &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"grp|val|a|b|c|val|val|d|e|f|val",
+				"varchar|int64|int64|int64|int64|int64|int64|int64|int64|int64|int64",
+			),
+			"a|1|null|null|null|1|1|null|null|null|1",
+			"a|2|null|null|null|2|2|null|null|null|2",
+			"a|2|null|null|null|2|2|null|null|null|2",
+			"a|4|null|null|null|4|4|null|null|null|4",
+			"b|3|null|null|null|3|3|null|null|null|3",
+		)},
+	}
+}
+
+var wantWindowResult = sqltypes.MakeTestResult(
+	sqltypes.MakeTestFields(
+		"grp|val|rn|rnk|drnk|s|lg|cnt|cd|nt|lv",
+		"varchar|int64|int64|int64|int64|decimal|int64|int64|float64|int64|int64",
+	),
+	"a|1|1|1|1|1|0|2|0.25|1|1",
+	"a|2|2|2|2|5|1|3|0.75|1|2",
+	"a|2|3|2|2|5|2|3|0.75|2|2",
+	"a|4|4|4|3|9|2|2|1|2|4",
+	"b|3|1|1|1|3|0|1|1|1|3",
+)
+
+func TestWindowExecute(t *testing.T) {
+	w := newTestWindow(newTestWindowInput())
+
+	result, err := w.TryExecute(t.Context(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, wantWindowResult, result)
+}
+
+func TestWindowStreamExecute(t *testing.T) {
+	w := newTestWindow(newTestWindowInput())
+
+	result := &sqltypes.Result{}
+	err := w.TryStreamExecute(t.Context(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		if qr.Fields != nil {
+			result.Fields = qr.Fields
+		}
+		result.Rows = append(result.Rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, wantWindowResult, result)
+}
+
+func TestWindowMaxMemoryRows(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	testMaxMemoryRows = 3
+	defer func() {
+		testMaxMemoryRows = saveMax
+	}()
+
+	w := newTestWindow(newTestWindowInput())
+	_, err := w.TryExecute(t.Context(), &noopVCursor{}, nil, true)
+	assert.EqualError(t, err, "in-memory row count exceeded allowed limit of 3")
+
+	// When streaming, only the rows of a single partition are held in memory.
+	w = newTestWindow(newTestWindowInput())
+	err = w.TryStreamExecute(t.Context(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		return nil
+	})
+	assert.EqualError(t, err, "in-memory row count exceeded allowed limit of 3")
+}
+
+func TestWindowGetFields(t *testing.T) {
+	w := newTestWindow(newTestWindowInput())
+
+	result, err := w.GetFields(t.Context(), &noopVCursor{}, nil)
+	require.NoError(t, err)
+	utils.MustMatch(t, &sqltypes.Result{Fields: wantWindowResult.Fields}, result)
+}
+
+func TestWindowFunctionParamsString(t *testing.T) {
+	w := newTestWindow(nil)
+	var got []string
+	for _, wf := range w.Functions {
+		got = append(got, wf.String())
+	}
+	assert.Equal(t, []string{
+		"row_number() AS rn",
+		"rank() AS rnk",
+		"dense_rank() AS drnk",
+		"sum(5) range between unbounded preceding and current row AS s",
+		"lag(6, 1, 0) AS lg",
+		"count_star() rows between 1 preceding and 1 following AS cnt",
+		"cume_dist() AS cd",
+		"ntile(2) AS nt",
+		"last_value(10) range between unbounded preceding and current row AS lv",
+	}, got)
+}
+
+func TestNtile(t *testing.T) {
+	var got []int64
+	for row := range 7 {
+		got = append(got, ntile(row, 7, 3))
+	}
+	assert.Equal(t, []int64{1, 1, 1, 2, 2, 3, 3}, got)
+
+	got = nil
+	for row := range 2 {
+		got = append(got, ntile(row, 2, 5))
+	}
+	assert.Equal(t, []int64{1, 2}, got)
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// WindowFrameUnit is the unit of a window frame: ROWS or RANGE.
+type WindowFrameUnit int8
+
+const (
+	// WindowFrameRows frames are defined by row positions relative to the current row.
+	WindowFrameRows WindowFrameUnit = iota
+	// WindowFrameRange frames are defined by the peers of the current row, i.e. the rows
+	// with the same ORDER BY values.
+	WindowFrameRange
+)
+
+// WindowFrameBoundType is the type of one of the bounds of a window frame.
+type WindowFrameBoundType int8
+
+const (
+	WindowFrameUnboundedPreceding WindowFrameBoundType = iota
+	WindowFramePreceding
+	WindowFrameCurrentRow
+	WindowFrameFollowing
+	WindowFrameUnboundedFollowing
+)
+
+// WindowFrameBound is the start or end of a window frame. Offset is only used by
+// WindowFramePreceding and WindowFrameFollowing bounds.
+type WindowFrameBound struct {
+	Type   WindowFrameBoundType
+	Offset int64
+}
+
+// WindowFrame describes the rows of a partition that a framing window function, such as
+// SUM() or FIRST_VALUE(), operates on for every row. The zero value is not valid; use
+// DefaultWindowFrame or TranslateWindowFrame.
+type WindowFrame struct {
+	Unit  WindowFrameUnit
+	Start WindowFrameBound
+	End   WindowFrameBound
+}
+
+// DefaultWindowFrame returns the frame MySQL uses when a window does not specify one:
+// RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW. Without an ORDER BY all the rows of
+// the partition are peers, so this frame covers the whole partition.
+func DefaultWindowFrame() WindowFrame {
+	return WindowFrame{
+		Unit:  WindowFrameRange,
+		Start: WindowFrameBound{Type: WindowFrameUnboundedPreceding},
+		End:   WindowFrameBound{Type: WindowFrameCurrentRow},
+	}
+}
+
+// TranslateWindowFrame translates the frame clause of a window specification. A nil
+// clause returns the default frame. Only ROWS frames support offsets, which must be
+// non-negative integer literals.
+func TranslateWindowFrame(frame *sqlparser.FrameClause) (WindowFrame, error) {
+	if frame == nil {
+		return DefaultWindowFrame(), nil
+	}
+	out := WindowFrame{Unit: WindowFrameRows}
+	if frame.Unit == sqlparser.FrameRangeType {
+		out.Unit = WindowFrameRange
+	}
+
+	var err error
+	out.Start, err = translateWindowFrameBound(out.Unit, frame.Start)
+	if err != nil {
+		return WindowFrame{}, err
+	}
+	if frame.End == nil {
+		out.End = WindowFrameBound{Type: WindowFrameCurrentRow}
+	} else {
+		out.End, err = translateWindowFrameBound(out.Unit, frame.End)
+		if err != nil {
+			return WindowFrame{}, err
+		}
+	}
+	if out.Start.Type == WindowFrameUnboundedFollowing || out.End.Type == WindowFrameUnboundedPreceding || out.Start.Type > out.End.Type {
+		return WindowFrame{}, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid window frame: %s", sqlparser.String(frame))
+	}
+	return out, nil
+}
+
+func translateWindowFrameBound(unit WindowFrameUnit, point *sqlparser.FramePoint) (WindowFrameBound, error) {
+	switch point.Type {
+	case sqlparser.UnboundedPrecedingType:
+		return WindowFrameBound{Type: WindowFrameUnboundedPreceding}, nil
+	case sqlparser.CurrentRowType:
+		return WindowFrameBound{Type: WindowFrameCurrentRow}, nil
+	case sqlparser.UnboundedFollowingType:
+		return WindowFrameBound{Type: WindowFrameUnboundedFollowing}, nil
+	}
+
+	bound := WindowFrameBound{Type: WindowFramePreceding}
+	if point.Type == sqlparser.ExprFollowingType {
+		bound.Type = WindowFrameFollowing
+	}
+	if unit == WindowFrameRange {
+		return WindowFrameBound{}, vterrors.VT12001(fmt.Sprintf("RANGE window frame with an offset in a cross-shard query: %s", strings.TrimSpace(sqlparser.String(point))))
+	}
+	lit, ok := point.Expr.(*sqlparser.Literal)
+	if !ok || lit.Type != sqlparser.IntVal || point.Unit != sqlparser.IntervalNone {
+		return WindowFrameBound{}, vterrors.VT12001(fmt.Sprintf("window frame offset that is not an integer literal in a cross-shard query: %s", strings.TrimSpace(sqlparser.String(point))))
+	}
+	offset, err := strconv.ParseInt(lit.Val, 10, 64)
+	if err != nil || offset < 0 {
+		return WindowFrameBound{}, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid window frame offset: %s", strings.TrimSpace(sqlparser.String(point)))
+	}
+	bound.Offset = offset
+	return bound, nil
+}
+
+// Bounds returns the first and last positions of the frame for the row at the given position
+// of a partition of the given size. peersStart and peersEnd are the first and last positions of
+// the peers of the row. The frame is empty when start is greater than end.
+func (f WindowFrame) Bounds(row, size, peersStart, peersEnd int) (start, end int) {
+	start = f.Start.position(f.Unit, row, size, peersStart)
+	end = f.End.position(f.Unit, row, size, peersEnd)
+	return max(start, 0), min(end, size-1)
+}
+
+// StartsAtPartition returns true if the frame always starts at the first row of the partition,
+// which lets framing functions accumulate their result from one row to the next.
+func (f WindowFrame) StartsAtPartition() bool {
+	return f.Start.Type == WindowFrameUnboundedPreceding
+}
+
+func (b WindowFrameBound) position(unit WindowFrameUnit, row, size, peer int) int {
+	switch b.Type {
+	case WindowFrameUnboundedPreceding:
+		return 0
+	case WindowFramePreceding:
+		return row - int(min(b.Offset, int64(size)))
+	case WindowFrameFollowing:
+		return row + int(min(b.Offset, int64(size)))
+	case WindowFrameUnboundedFollowing:
+		return size - 1
+	default:
+		if unit == WindowFrameRange {
+			return peer
+		}
+		return row
+	}
+}
+
+func (f WindowFrame) String() string {
+	unit := sqlparser.FrameRowsStr
+	if f.Unit == WindowFrameRange {
+		unit = sqlparser.FrameRangeStr
+	}
+	return fmt.Sprintf("%s between %s and %s", unit, f.Start.String(), f.End.String())
+}
+
+func (b WindowFrameBound) String() string {
+	switch b.Type {
+	case WindowFrameUnboundedPreceding:
+		return sqlparser.UnboundedPrecedingStr
+	case WindowFramePreceding:
+		return fmt.Sprintf("%d %s", b.Offset, sqlparser.ExprPrecedingStr)
+	case WindowFrameFollowing:
+		return fmt.Sprintf("%d %s", b.Offset, sqlparser.ExprFollowingStr)
+	case WindowFrameUnboundedFollowing:
+		return sqlparser.UnboundedFollowingStr
+	default:
+		return sqlparser.CurrentRowStr
+	}
+}
+
+// aggregationAvg implements an AVG() aggregation on top of the SUM() aggregation for the same
+// type. Like in MySQL, the average of integer and decimal values is a DECIMAL with 4 more digits
+// of precision, and the average of any other value is a FLOAT64.
+type aggregationAvg struct {
+	sum   Sum
+	count int64
+}
+
+func (a *aggregationAvg) Add(value sqltypes.Value) error {
+	if value.IsNull() {
+		return nil
+	}
+	if err := a.sum.Add(value); err != nil {
+		return err
+	}
+	a.count++
+	return nil
+}
+
+func (a *aggregationAvg) Result() sqltypes.Value {
+	sum := a.sum.Result()
+	if sum.IsNull() || a.count == 0 {
+		return sqltypes.NULL
+	}
+	e, err := valueToEval(sum, collationNumeric, nil)
+	if err != nil {
+		return sqltypes.NULL
+	}
+	avg, err := divideNumericWithError(e, newEvalInt64(a.count), true)
+	if err != nil || avg == nil {
+		return sqltypes.NULL
+	}
+	return evalToSQLValue(avg)
+}
+
+func (a *aggregationAvg) Reset() {
+	a.sum.Reset()
+	a.count = 0
+}
+
+// NewAggregationAvg returns an AVG() aggregation for values of the given type.
+func NewAggregationAvg(type_ sqltypes.Type) Sum {
+	return &aggregationAvg{sum: NewAggregationSum(type_)}
+}
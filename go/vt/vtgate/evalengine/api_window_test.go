@@ -0,0 +1,158 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestTranslateWindowFrame(t *testing.T) {
+	tcases := []struct {
+		window string
+		frame  string
+		err    string
+	}{{
+		window: "order by a",
+		frame:  "range between unbounded preceding and current row",
+	}, {
+		window: "order by a rows unbounded preceding",
+		frame:  "rows between unbounded preceding and current row",
+	}, {
+		window: "order by a rows between 2 preceding and 1 following",
+		frame:  "rows between 2 preceding and 1 following",
+	}, {
+		window: "order by a range between current row and unbounded following",
+		frame:  "range between current row and unbounded following",
+	}, {
+		window: "order by a range between 1 preceding and current row",
+		err:    "VT12001: unsupported: RANGE window frame with an offset in a cross-shard query",
+	}, {
+		window: "order by a rows between ? preceding and current row",
+		err:    "VT12001: unsupported: window frame offset that is not an integer literal in a cross-shard query",
+	}, {
+		window: "order by a rows between current row and 1 preceding",
+		err:    "invalid window frame",
+	}}
+
+	parser := sqlparser.NewTestParser()
+	for _, tc := range tcases {
+		t.Run(tc.window, func(t *testing.T) {
+			stmt, err := parser.Parse("select sum(a) over (" + tc.window + ") from t")
+			require.NoError(t, err)
+			wf := stmt.(*sqlparser.Select).SelectExprs.Exprs[0].(*sqlparser.AliasedExpr).Expr.(sqlparser.WindowFunc)
+
+			frame, err := TranslateWindowFrame(wf.GetOverClause().WindowSpec.FrameClause)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.frame, frame.String())
+		})
+	}
+}
+
+func TestWindowFrameBounds(t *testing.T) {
+	// A partition of 5 rows where rows 1 to 3 are peers.
+	peers := [][2]int{{0, 0}, {1, 3}, {1, 3}, {1, 3}, {4, 4}}
+	tcases := []struct {
+		frame WindowFrame
+		want  [][2]int
+	}{{
+		frame: DefaultWindowFrame(),
+		want:  [][2]int{{0, 0}, {0, 3}, {0, 3}, {0, 3}, {0, 4}},
+	}, {
+		frame: WindowFrame{
+			Unit:  WindowFrameRows,
+			Start: WindowFrameBound{Type: WindowFrameUnboundedPreceding},
+			End:   WindowFrameBound{Type: WindowFrameCurrentRow},
+		},
+		want: [][2]int{{0, 0}, {0, 1}, {0, 2}, {0, 3}, {0, 4}},
+	}, {
+		frame: WindowFrame{
+			Unit:  WindowFrameRows,
+			Start: WindowFrameBound{Type: WindowFramePreceding, Offset: 1},
+			End:   WindowFrameBound{Type: WindowFrameFollowing, Offset: 1},
+		},
+		want: [][2]int{{0, 1}, {0, 2}, {1, 3}, {2, 4}, {3, 4}},
+	}, {
+		frame: WindowFrame{
+			Unit:  WindowFrameRange,
+			Start: WindowFrameBound{Type: WindowFrameCurrentRow},
+			End:   WindowFrameBound{Type: WindowFrameUnboundedFollowing},
+		},
+		want: [][2]int{{0, 4}, {1, 4}, {1, 4}, {1, 4}, {4, 4}},
+	}, {
+		frame: WindowFrame{
+			Unit:  WindowFrameRows,
+			Start: WindowFrameBound{Type: WindowFrameFollowing, Offset: 2},
+			End:   WindowFrameBound{Type: WindowFrameFollowing, Offset: 3},
+		},
+		want: [][2]int{{2, 3}, {3, 4}, {4, 4}, {5, 4}, {6, 4}},
+	}}
+
+	for _, tc := range tcases {
+		t.Run(tc.frame.String(), func(t *testing.T) {
+			for row, want := range tc.want {
+				start, end := tc.frame.Bounds(row, len(peers), peers[row][0], peers[row][1])
+				assert.Equal(t, want, [2]int{start, end}, "row %d", row)
+			}
+		})
+	}
+}
+
+func TestAggregationAvg(t *testing.T) {
+	tcases := []struct {
+		type_  sqltypes.Type
+		values []sqltypes.Value
+		want   sqltypes.Value
+	}{{
+		type_: sqltypes.Int64,
+		want:  sqltypes.NULL,
+	}, {
+		type_:  sqltypes.Int64,
+		values: []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NULL, sqltypes.NewInt64(2)},
+		want:   sqltypes.NewDecimal("1.5000"),
+	}, {
+		type_:  sqltypes.Decimal,
+		values: []sqltypes.Value{sqltypes.NewDecimal("1.25"), sqltypes.NewDecimal("2")},
+		want:   sqltypes.NewDecimal("1.625000"),
+	}, {
+		type_:  sqltypes.Float64,
+		values: []sqltypes.Value{sqltypes.NewFloat64(1), sqltypes.NewFloat64(2)},
+		want:   sqltypes.NewFloat64(1.5),
+	}}
+
+	for _, tc := range tcases {
+		t.Run(tc.type_.String(), func(t *testing.T) {
+			avg := NewAggregationAvg(tc.type_)
+			for _, v := range tc.values {
+				require.NoError(t, avg.Add(v))
+			}
+			assert.Equal(t, tc.want, avg.Result())
+
+			avg.Reset()
+			assert.Equal(t, sqltypes.NULL, avg.Result())
+		})
+	}
+}
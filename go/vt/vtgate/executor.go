@@ -142,6 +142,9 @@ type (
 		plans *PlanCache
 		epoch atomic.Uint32
 
+		resultCache *ResultCache
+		txEndHooks  transactionEndHooks
+
 		vm            *VSchemaManager
 		schemaTracker SchemaInfo
 
@@ -165,6 +168,7 @@ const (
 	pathQueryPlans   = "/debug/query_plans"
 	pathScatterStats = "/debug/scatter_stats"
 	pathVSchema      = "/debug/vschema"
+	pathResultCache  = "/debug/result_cache"
 )
 
 type (
@@ -207,6 +211,9 @@ func NewExecutor(
 		warmingReadsSemaphore: newWarmingReadsSemaphore(warmingReadsConcurrency),
 		ddlConfig:             ddlConfig,
 	}
+	if resultCacheMemory > 0 {
+		e.resultCache = NewResultCache(resultCacheMemory)
+	}
 	// setting the vcursor config.
 	e.initVConfig(warnOnShardedOnly, pv)
 	e.metrics = &Metrics{
@@ -242,9 +249,46 @@ func NewExecutor(
 		stats.NewCounterFunc("QueryPlanCacheMisses", "Query plan cache misses", func() int64 {
 			return e.plans.Metrics.Misses()
 		})
+		if e.resultCache != nil {
+			stats.NewGaugeFunc("ResultCacheLength", "Result cache length", func() int64 {
+				return int64(e.resultCache.results.Len())
+			})
+			stats.NewGaugeFunc("ResultCacheSize", "Result cache size", func() int64 {
+				return int64(e.resultCache.results.UsedCapacity())
+			})
+			stats.NewGaugeFunc("ResultCacheCapacity", "Result cache capacity", func() int64 {
+				return int64(e.resultCache.results.MaxCapacity())
+			})
+			stats.NewCounterFunc("ResultCacheEvictions", "Result cache evictions", func() int64 {
+				return e.resultCache.results.Metrics.Evicted()
+			})
+			stats.NewCounterFunc("ResultCacheHits", "Result cache hits", func() int64 {
+				return e.resultCache.hits.Load()
+			})
+			stats.NewCounterFunc("ResultCacheMisses", "Result cache misses", func() int64 {
+				return e.resultCache.misses.Load()
+			})
+			stats.NewCounterFunc("ResultCacheInvalidations", "Result cache invalidations caused by writes", func() int64 {
+				return e.resultCache.invalidations.Load()
+			})
+		}
+		lookupCacheLabels := []string{"Keyspace", "Vindex"}
+		stats.NewGaugesFuncWithMultiLabels("VindexLookupCacheLength", "Number of values in the cache of each lookup vindex", lookupCacheLabels, func() map[string]int64 {
+			return e.lookupCacheStats(func(lc *vindexes.LookupCache) int64 { return int64(lc.Len()) })
+		})
+		stats.NewCountersFuncWithMultiLabels("VindexLookupCacheHits", "Values served from the cache of each lookup vindex", lookupCacheLabels, func() map[string]int64 {
+			return e.lookupCacheStats((*vindexes.LookupCache).Hits)
+		})
+		stats.NewCountersFuncWithMultiLabels("VindexLookupCacheMisses", "Values looked up past the cache of each lookup vindex", lookupCacheLabels, func() map[string]int64 {
+			return e.lookupCacheStats((*vindexes.LookupCache).Misses)
+		})
+		stats.NewCountersFuncWithMultiLabels("VindexLookupCacheInvalidations", "Values invalidated in the cache of each lookup vindex", lookupCacheLabels, func() map[string]int64 {
+			return e.lookupCacheStats((*vindexes.LookupCache).Invalidations)
+		})
 		servenv.HTTPHandle(pathQueryPlans, e)
 		servenv.HTTPHandle(pathScatterStats, e)
 		servenv.HTTPHandle(pathVSchema, e)
+		servenv.HTTPHandle(pathResultCache, e)
 	})
 	return e
 }
@@ -762,6 +806,8 @@ func (e *Executor) handleKill(ctx context.Context, mysqlCtx vtgateservice.MySQLC
 // CloseSession releases the current connection, which rollbacks open transactions and closes reserved connections.
 // It is called then the MySQL servers closes the connection to its client.
 func (e *Executor) CloseSession(ctx context.Context, safeSession *econtext.SafeSession) error {
+	defer e.resultCache.closeSession(safeSession)
+	defer e.txEndHooks.run(safeSession)
 	return e.txConn.ReleaseAll(ctx, safeSession)
 }
 
@@ -1103,6 +1149,26 @@ func (e *Executor) VSchema() *vindexes.VSchema {
 	return e.vschema
 }
 
+// lookupCacheStats returns the value of f for the cache of each lookup
+// vindex in the current vschema that has one, by keyspace and vindex.
+func (e *Executor) lookupCacheStats(f func(*vindexes.LookupCache) int64) map[string]int64 {
+	vschema := e.VSchema()
+	if vschema == nil {
+		return nil
+	}
+	out := make(map[string]int64)
+	for ksName, ks := range vschema.Keyspaces {
+		for vindexName, vindex := range ks.Vindexes {
+			lc, ok := vindex.(vindexes.LookupCaching)
+			if !ok || lc.LookupCache() == nil {
+				continue
+			}
+			out[ksName+"."+vindexName] = f(lc.LookupCache())
+		}
+	}
+	return out
+}
+
 // SaveVSchema updates the vschema and stats
 func (e *Executor) SaveVSchema(vschema *vindexes.VSchema, stats *VSchemaStats) {
 	e.mu.Lock()
@@ -1112,6 +1178,7 @@ func (e *Executor) SaveVSchema(vschema *vindexes.VSchema, stats *VSchemaStats) {
 	}
 	e.vschemaStats = stats
 	e.ClearPlans()
+	e.resultCache.Clear()
 
 	if vschemaCounters != nil {
 		vschemaCounters.Add("Reload", 1)
@@ -1381,6 +1448,7 @@ func (e *Executor) buildStatement(
 	plan.ParamsCount = paramsCount
 	plan.Warnings = vcursor.GetAndEmptyWarnings()
 	plan.QueryHints = qh
+	plan.ResultCacheTTL = resultCacheTTL(vcursor.GetVSchema(), stmt, plan, resultCacheMaxTTL)
 
 	err = e.checkThatPlanIsValid(stmt, plan)
 	return plan, err
@@ -1409,6 +1477,8 @@ func (e *Executor) ServeHTTP(response http.ResponseWriter, request *http.Request
 		returnAsJSON(response, e.VSchema())
 	case pathScatterStats:
 		e.WriteScatterStats(response)
+	case pathResultCache:
+		returnAsJSON(response, e.resultCache.debugEntries())
 	default:
 		response.WriteHeader(http.StatusNotFound)
 	}
@@ -1566,6 +1636,9 @@ func (e *Executor) initVConfig(warnOnShardedOnly bool, pv plancontext.PlannerVer
 		QueryTimeout:  queryTimeout,
 		MaxMemoryRows: maxMemoryRows,
 
+		SpillMemoryBudget: spillMemoryBudget,
+		SpillDir:          spillDir,
+
 		SetVarEnabled:         setVarEnabled,
 		DeniedSystemVariables: buildDeniedSystemVariables(deniedSystemVariables),
 		EnableViews:           enableViews,
@@ -1850,6 +1923,7 @@ func (e *Executor) Close() {
 	}
 	topo.Close()
 	e.plans.Close()
+	e.resultCache.Close()
 }
 
 func (e *Executor) Environment() *vtenv.Environment {
@@ -1868,6 +1942,11 @@ func (e *Executor) AddWarningCount(name string, count int64) {
 	warnings.Add(name, count)
 }
 
+// OnTransactionEnd runs fn once the open transaction of safeSession is committed or rolled back.
+func (e *Executor) OnTransactionEnd(safeSession *econtext.SafeSession, fn func()) {
+	e.txEndHooks.add(safeSession, fn)
+}
+
 type (
 	errorTransformer interface {
 		TransformError(err error) error
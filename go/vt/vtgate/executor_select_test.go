@@ -3289,7 +3289,7 @@ func TestOnlyOptimizedPlan(t *testing.T) {
 func TestPrepareWithUnsupportedQuery(t *testing.T) {
 	executor, _, _, _, ctx := createExecutorEnvWithConfig(t, createExecutorConfigWithNormalizer())
 
-	sql := "select a, b, c, row_number() over (partition by x) from user where c1 = ? and c2 = ?"
+	sql := "select a, b, c, row_number() over (partition by x) from user where c1 = ? and c2 = ? group by a, b, c"
 	session := econtext.NewAutocommitSession(&vtgatepb.Session{})
 	fields, paramsCount, err := executorPrepare(ctx, executor, session.Session, sql)
 	require.NoError(t, err)
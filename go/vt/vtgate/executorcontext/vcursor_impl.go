@@ -90,6 +90,11 @@ type (
 		WarnShardedOnly    bool
 		PlannerVersion     plancontext.PlannerVersion
 
+		// SpillMemoryBudget is the number of bytes that a query may buffer in memory
+		// before its rows are spilled to disk. Zero disables spilling.
+		SpillMemoryBudget int64
+		SpillDir          string
+
 		PreventCrossKeyspaceReads bool
 
 		// DeniedSystemVariables is the set of system variable names (lowercased)
@@ -128,6 +133,7 @@ type (
 		ReadTransaction(ctx context.Context, transactionID string) (*querypb.TransactionMetadata, error)
 		UnresolvedTransactions(ctx context.Context, targets []*querypb.Target) ([]*querypb.TransactionMetadata, error)
 		AddWarningCount(name string, value int64)
+		OnTransactionEnd(safeSession *SafeSession, fn func())
 	}
 
 	// VSchemaOperator is an interface to Vschema Operations
@@ -179,6 +185,7 @@ type (
 		// A nil value represents that no foreign_key_checks value was provided.
 		fkChecksState       *bool
 		ignoreMaxMemoryRows bool
+		memoryBudget        *engine.MemoryBudget
 		vschema             *vindexes.VSchema
 		vm                  VSchemaOperator
 		semTable            *semantics.SemTable
@@ -242,6 +249,11 @@ func NewVCursorImpl(
 		}
 	}
 
+	var memoryBudget *engine.MemoryBudget
+	if cfg.SpillMemoryBudget > 0 {
+		memoryBudget = engine.NewMemoryBudget(cfg.SpillMemoryBudget, cfg.SpillDir)
+	}
+
 	return &VCursorImpl{
 		config:         cfg,
 		SafeSession:    safeSession,
@@ -252,6 +264,7 @@ func NewVCursorImpl(
 		executor:       executor,
 		logStats:       logStats,
 		metrics:        metrics,
+		memoryBudget:   memoryBudget,
 
 		resolver:   resolver,
 		vschema:    vschema,
@@ -301,6 +314,7 @@ func (vc *VCursorImpl) CloneForMirroring(ctx context.Context) engine.VCursor {
 		metrics:        vc.metrics,
 
 		ignoreMaxMemoryRows: vc.ignoreMaxMemoryRows,
+		memoryBudget:        vc.memoryBudget,
 		vschema:             vc.vschema,
 		vm:                  vc.vm,
 		semTable:            vc.semTable,
@@ -328,6 +342,7 @@ func (vc *VCursorImpl) CloneForReplicaWarming(ctx context.Context) engine.VCurso
 		metrics:        vc.metrics,
 
 		ignoreMaxMemoryRows: vc.ignoreMaxMemoryRows,
+		memoryBudget:        vc.memoryBudget,
 		vschema:             vc.vschema,
 		vm:                  vc.vm,
 		semTable:            vc.semTable,
@@ -366,6 +381,7 @@ func (vc *VCursorImpl) cloneWithAutocommitSession() *VCursorImpl {
 		executor:       vc.executor,
 		logStats:       vc.logStats,
 		metrics:        vc.metrics,
+		memoryBudget:   vc.memoryBudget,
 
 		resolver:   vc.resolver,
 		vschema:    vc.vschema,
@@ -440,6 +456,12 @@ func (vc *VCursorImpl) ExceedsMaxMemoryRows(numRows int) bool {
 	return !vc.ignoreMaxMemoryRows && numRows > vc.config.MaxMemoryRows
 }
 
+// MemoryBudget returns the budget for rows buffered in memory by this query,
+// or nil if spilling to disk is disabled.
+func (vc *VCursorImpl) MemoryBudget() *engine.MemoryBudget {
+	return vc.memoryBudget
+}
+
 // SetIgnoreMaxMemoryRows sets the ignoreMaxMemoryRows value.
 func (vc *VCursorImpl) SetIgnoreMaxMemoryRows(ignoreMaxMemoryRows bool) {
 	vc.ignoreMaxMemoryRows = ignoreMaxMemoryRows
@@ -1405,6 +1427,11 @@ func (vc *VCursorImpl) InTransaction() bool {
 	return vc.SafeSession.InTransaction()
 }
 
+// OnTransactionEnd implements the vindexes.TransactionEndNotifier interface.
+func (vc *VCursorImpl) OnTransactionEnd(fn func()) {
+	vc.executor.OnTransactionEnd(vc.SafeSession, fn)
+}
+
 func (vc *VCursorImpl) Commit(ctx context.Context) error {
 	return vc.executor.Commit(ctx, vc.SafeSession)
 }
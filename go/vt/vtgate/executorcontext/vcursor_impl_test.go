@@ -427,6 +427,11 @@ func (f fakeExecutor) AddWarningCount(name string, value int64) {
 	panic("implement me")
 }
 
+func (f fakeExecutor) OnTransactionEnd(safeSession *SafeSession, fn func()) {
+	// TODO implement me
+	panic("implement me")
+}
+
 var _ iExecute = (*fakeExecutor)(nil)
 
 type fakeObserver struct{}
@@ -86,6 +86,10 @@ func (e *Executor) newExecute(
 		stmt               sqlparser.Statement
 		cancel             context.CancelFunc
 	)
+	defer func() {
+		e.resultCache.afterExecute(plan, safeSession)
+		e.txEndHooks.afterExecute(safeSession)
+	}()
 
 	for try := range MaxBufferingRetries {
 		if try > 0 && !vs.GetCreated().After(lastVSchemaCreated) { // We need to wait for a vschema update
@@ -402,8 +406,17 @@ func (e *Executor) executePlan(
 	logStats *logstats.LogStats,
 	execStart time.Time,
 ) (*sqltypes.Result, error) {
+	cached, lookup := e.resultCache.lookup(ctx, plan, vcursor, safeSession, bindVars)
+	if cached != nil {
+		e.setLogStats(logStats, plan, vcursor, execStart, nil, cached)
+		return cached, nil
+	}
+
 	// 4: Execute!
 	qr, err := vcursor.ExecutePrimitive(ctx, plan.Instructions, bindVars, true)
+	if err == nil && len(safeSession.GetWarnings()) == 0 {
+		e.resultCache.store(lookup, qr)
+	}
 
 	// 5: Log and add statistics
 	e.setLogStats(logStats, plan, vcursor, execStart, err, qr)
@@ -18,12 +18,14 @@ package planbuilder
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/sysvars"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -136,6 +138,7 @@ func transformDMLWithInput(ctx *plancontext.PlanningContext, op *operators.DMLWi
 		Input:      input,
 		OutputCols: op.Offsets,
 		BVList:     op.BvList,
+		MovesRows:  op.MovesRows,
 	}, nil
 }
 
@@ -288,6 +291,38 @@ func transformSubQuery(ctx *plancontext.PlanningContext, op *operators.SubQuery)
 		}, nil
 	}
 
+	if op.IsArgument {
+		cs := &engine.CorrelatedSubquery{
+			Opcode:         op.FilterType,
+			SubqueryResult: op.ArgName,
+			Vars:           op.Vars,
+			ResultColumn:   op.ResultColumn,
+			Outer:          outer,
+			Subquery:       inner,
+		}
+		if len(op.FilterPredicates) > 0 {
+			// the value of an IN subquery is computed by evaluating the comparison it is used in
+			cs.HasValues = op.HasValuesName
+			cs.Predicate = op.PredicateWithOffsets
+			cs.ASTPredicate = ctx.SemTable.AndExpressions(op.FilterPredicates...)
+		}
+		return cs, nil
+	}
+
+	if len(op.FilterPredicates) > 0 {
+		return &engine.CorrelatedSubquery{
+			Opcode:         op.FilterType,
+			SubqueryResult: op.SubqueryValueName,
+			HasValues:      op.HasValuesName,
+			Vars:           op.Vars,
+			Predicate:      op.PredicateWithOffsets,
+			ASTPredicate:   ctx.SemTable.AndExpressions(op.FilterPredicates...),
+			ResultColumn:   -1,
+			Outer:          outer,
+			Subquery:       inner,
+		}, nil
+	}
+
 	return &engine.SemiJoin{
 		Left:  outer,
 		Right: inner,
@@ -326,9 +361,6 @@ func transformFkVerify(ctx *plancontext.PlanningContext, fkv *operators.FkVerify
 }
 
 func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggregator) (engine.Primitive, error) {
-	if op.WithRollup {
-		return nil, vterrors.VT12001("GROUP BY WITH ROLLUP not supported for sharded queries")
-	}
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
 		return nil, err
@@ -344,6 +376,20 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		case opcode.AggregateUDF:
 			message := fmt.Sprintf("Aggregate UDF '%s' must be pushed down to MySQL", sqlparser.String(aggr.Original.Expr))
 			return nil, vterrors.VT12001(message)
+		case opcode.AggregateGrouping:
+			groupingKeys, err := groupingFuncKeys(ctx, op, aggr.Original.Expr)
+			if err != nil {
+				return nil, err
+			}
+			aggrParam := engine.NewAggregateParam(aggr.OpCode, aggr.ColOffset, nil, aggr.Alias, ctx.VSchema.Environment().CollationEnv())
+			aggrParam.GroupingKeys = groupingKeys
+			aggregates = append(aggregates, aggrParam)
+			continue
+		case opcode.AggregateCountDistinct, opcode.AggregateSumDistinct:
+			if op.WithRollup {
+				// the distinct values are only sorted within the most detailed groups
+				return nil, vterrors.VT12001("DISTINCT aggregation in a GROUP BY WITH ROLLUP query that cannot be pushed down to MySQL")
+			}
 		case opcode.AggregateConstant:
 			// For AnyValue aggregations (literals, parameters), translate to evalengine
 			// This allows evaluation even when no input rows are present (empty result sets)
@@ -369,6 +415,10 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		aggrParam.OrigOpcode = aggr.OriginalOpCode
 		aggrParam.WCol = aggr.WSOffset
 		aggrParam.Type = aggr.GetTypeCollation(ctx)
+		aggrParam.HashDistinct = aggr.HashDistinct
+		if len(aggr.ArgOffsets) > 0 {
+			aggrParam.GroupConcat = groupConcatParams(ctx, aggr)
+		}
 		aggregates = append(aggregates, aggrParam)
 	}
 
@@ -395,10 +445,55 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		Aggregates:          aggregates,
 		GroupByKeys:         groupByKeys,
 		TruncateColumnCount: op.ResultColumns,
+		WithRollup:          op.WithRollup,
 		Input:               src,
 	}, nil
 }
 
+// groupConcatParams returns how the engine computes a GROUP_CONCAT from the individual values of each group
+func groupConcatParams(ctx *plancontext.PlanningContext, aggr operators.Aggr) *engine.GroupConcatParams {
+	gc := aggr.Func.(*sqlparser.GroupConcatExpr)
+	params := &engine.GroupConcatParams{Cols: aggr.ArgOffsets}
+	orderExprs := aggr.GroupConcatOrderExprs()
+	for idx, order := range gc.OrderBy {
+		typ, _ := ctx.TypeForExpr(orderExprs[idx])
+		params.OrderBy = append(params.OrderBy, evalengine.OrderByParams{
+			Col:             aggr.OrderOffsets[idx],
+			WeightStringCol: aggr.OrderWSOffsets[idx],
+			Desc:            order.Direction == sqlparser.DescOrder,
+			Type:            typ,
+			CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+		})
+	}
+	return params
+}
+
+// groupingFuncKeys returns the index in the grouping of the aggregator for each argument of a GROUPING() call
+func groupingFuncKeys(ctx *plancontext.PlanningContext, op *operators.Aggregator, expr sqlparser.Expr) ([]int, error) {
+	fnc, ok := expr.(*sqlparser.FuncExpr)
+	if !ok {
+		return nil, vterrors.VT13001(fmt.Sprintf("expected GROUPING() function, got: %s", sqlparser.String(expr)))
+	}
+	if !op.WithRollup {
+		if op.Original {
+			return nil, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.InvalidGroupFuncUse, "Invalid use of group function")
+		}
+		// this aggregator only produces the rows that the rollup will be computed from
+		return nil, nil
+	}
+	keys := make([]int, 0, len(fnc.Exprs))
+	for argIdx, arg := range fnc.Exprs {
+		idx := slices.IndexFunc(op.Grouping, func(by operators.GroupBy) bool {
+			return ctx.SemTable.EqualsExprWithDeps(by.Inner, arg)
+		})
+		if idx < 0 {
+			return nil, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongFieldWithGroup, "Argument #%d of GROUPING function is not in GROUP BY", argIdx+1)
+		}
+		keys = append(keys, idx)
+	}
+	return keys, nil
+}
+
 func transformDistinct(ctx *plancontext.PlanningContext, op *operators.Distinct) (engine.Primitive, error) {
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
@@ -357,6 +357,10 @@ func (qb *queryBuilder) sortTables() {
 		if !isSel {
 			return true, nil
 		}
+		if slices.ContainsFunc(sel.From, func(tbl sqlparser.TableExpr) bool { return lateralDerivedTable(tbl) != nil }) {
+			// a LATERAL derived table has to come after the tables it uses
+			return true, nil
+		}
 		ts := &tableSorter{
 			sel: sel,
 			tbl: qb.ctx.SemTable,
@@ -626,6 +630,9 @@ func buildApplyJoin(op *ApplyJoin, qb *queryBuilder) {
 
 	qbR := &queryBuilder{ctx: qb.ctx}
 	buildQuery(op.RHS, qbR)
+	if op.Lateral && qbR.stmt != nil {
+		qbR.markLateral()
+	}
 
 	switch {
 	// if we have a recursive cte, we might be missing a statement from one of the sides
@@ -638,6 +645,19 @@ func buildApplyJoin(op *ApplyJoin, qb *queryBuilder) {
 	}
 }
 
+// markLateral marks the derived tables of the query as LATERAL, so they can use the tables they are joined with
+func (qb *queryBuilder) markLateral() {
+	for _, tbl := range qb.stmt.(FromStatement).GetFrom() {
+		aliased, ok := tbl.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			continue
+		}
+		if dt, ok := aliased.Expr.(*sqlparser.DerivedTable); ok {
+			dt.Lateral = true
+		}
+	}
+}
+
 func buildUnion(op *Union, qb *queryBuilder) {
 	// the first input is built first
 	buildQuery(op.Sources[0], qb)
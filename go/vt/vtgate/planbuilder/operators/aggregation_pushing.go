@@ -28,9 +28,6 @@ import (
 )
 
 func errDistinctAggrWithMultiExpr(f sqlparser.AggrFunc) {
-	if f == nil {
-		panic(vterrors.VT12001("distinct aggregation function with multiple expressions"))
-	}
 	panic(vterrors.VT12001(fmt.Sprintf("distinct aggregation function with multiple expressions '%s'", sqlparser.String(f))))
 }
 
@@ -39,8 +36,9 @@ func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 		return aggregator, NoRewrite
 	}
 
-	// this rewrite is always valid, and we should do it whenever possible
-	if route, ok := aggregator.Source.(*Route); ok && (route.IsSingleShard() || overlappingUniqueVindex(ctx, aggregator.Grouping)) {
+	// this rewrite is always valid, and we should do it whenever possible.
+	// the super-aggregate rows of a rollup span all groups, so they can only be produced by a single shard
+	if route, ok := aggregator.Source.(*Route); ok && (route.IsSingleShard() || !aggregator.WithRollup && overlappingUniqueVindex(ctx, aggregator.Grouping)) {
 		return Swap(aggregator, route, "push down aggregation under route - remove original")
 	}
 
@@ -55,6 +53,15 @@ func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 		return splitAvgAggregations(ctx, aggregator)
 	}
 
+	if slices.ContainsFunc(aggregator.Aggregations, Aggr.groupConcatNeedsRows) {
+		// the values of such a GROUP_CONCAT can't be deduplicated or sorted in parts,
+		// so it is computed on the vtgate from the individual values of each group
+		if _, distinctExprs := checkIfWeCanPush(ctx, aggregator); len(distinctExprs) > 0 {
+			aggregator.setDistinctExpr(ctx, distinctExprs[0])
+		}
+		return aggregator, NoRewrite
+	}
+
 	switch src := aggregator.Source.(type) {
 	case *Route:
 		// if we have a single sharded route, we can push it down
@@ -95,6 +102,13 @@ func pushAggregationThroughSubquery(
 	rootAggr *Aggregator,
 	src *SubQueryContainer,
 ) (Operator, *ApplyResult) {
+	for _, aggr := range rootAggr.Aggregations {
+		if slices.ContainsFunc(aggr.SubQueryExpression, (*SubQuery).evaluatedPerRow) {
+			// the value of a correlated subquery executed for every row of the outer query
+			// is only known in vtgate, so the aggregation can't be pushed to the outer query
+			return nil, nil
+		}
+	}
 	pushedAggr := rootAggr.SplitAggregatorBelowOperators(ctx, []Operator{src.Outer})
 	for _, subQuery := range src.Inner {
 		lhsCols := subQuery.OuterExpressionsNeeded(ctx, src.Outer)
@@ -171,9 +185,11 @@ func pushAggregationThroughRoute(
 func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator, aggrBelowRoute *Aggregator) {
 	canPushDistinctAggr, distinctExprs := checkIfWeCanPush(ctx, aggregator)
 
-	distinctAggrGroupByAdded := false
-
 	for i, aggr := range aggregator.Aggregations {
+		if aggr.OpCode == opcode.AggregateGrouping {
+			// GROUPING() is evaluated by the aggregator above the route
+			aggrBelowRoute.Columns[aggr.ColOffset] = aeWrap(aggr.getPushColumn())
+		}
 		if !aggr.Distinct || canPushDistinctAggr {
 			aggrBelowRoute.Aggregations = append(aggrBelowRoute.Aggregations, aggr)
 			aggregateTheAggregate(aggregator, i)
@@ -181,38 +197,40 @@ func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator,
 			continue
 		}
 
-		if len(distinctExprs) != 1 {
+		args := aggr.Func.GetArgs()
+		if len(args) != 1 {
 			errDistinctAggrWithMultiExpr(aggr.Func)
 		}
 
 		// We handle a distinct aggregation by turning it into a group by and
 		// doing the aggregating on the vtgate level instead
-		aeDistinctExpr := aeWrap(distinctExprs[0])
-		aggrBelowRoute.Columns[aggr.ColOffset] = aeDistinctExpr
+		aggrBelowRoute.Columns[aggr.ColOffset] = aeWrap(args[0])
 
-		// We handle a distinct aggregation by turning it into a group by and
-		// doing the aggregating on the vtgate level instead
 		// Adding to group by can be done only once even though there are multiple distinct aggregation with same expression.
-		if !distinctAggrGroupByAdded {
-			groupBy := NewGroupBy(distinctExprs[0])
+		sameExpr := func(gb GroupBy) bool {
+			return ctx.SemTable.EqualsExpr(gb.Inner, args[0])
+		}
+		if !slices.ContainsFunc(aggrBelowRoute.Grouping, sameExpr) {
+			groupBy := NewGroupBy(args[0])
 			groupBy.ColOffset = aggr.ColOffset
 			aggrBelowRoute.Grouping = append(aggrBelowRoute.Grouping, groupBy)
-			distinctAggrGroupByAdded = true
 		}
 	}
 
 	if !canPushDistinctAggr {
-		aggregator.DistinctExpr = distinctExprs[0]
+		aggregator.setDistinctExpr(ctx, distinctExprs[0])
 	}
 }
 
+// checkIfWeCanPush returns true if all the distinct aggregations can be pushed down,
+// and the arguments of the first distinct aggregation
 func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator) (bool, []sqlparser.Expr) {
 	canPush := true
 	var distinctExprs []sqlparser.Expr
-	var differentExpr *sqlparser.AliasedExpr
 
 	for _, aggr := range aggregator.Aggregations {
-		if !aggr.Distinct {
+		if !aggr.Distinct || aggr.OpCode == opcode.AggregateGroupConcat {
+			// a GROUP_CONCAT with DISTINCT deduplicates its own values
 			continue
 		}
 
@@ -230,21 +248,28 @@ func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 		if len(distinctExprs) == 0 {
 			distinctExprs = args
 		}
-		for idx, expr := range distinctExprs {
-			if !ctx.SemTable.EqualsExpr(expr, args[idx]) {
-				differentExpr = aggr.Original
-				break
-			}
-		}
-	}
-
-	if !canPush && differentExpr != nil {
-		panic(vterrors.VT12001("only one DISTINCT aggregation is allowed in a SELECT: " + sqlparser.String(differentExpr)))
 	}
 
 	return canPush, distinctExprs
 }
 
+// setDistinctExpr makes the aggregator sort its input by the given expression, so the
+// distinct aggregations on it can skip duplicates by comparing consecutive values.
+// The distinct aggregations on other expressions remember the values seen in each group instead.
+func (a *Aggregator) setDistinctExpr(ctx *plancontext.PlanningContext, expr sqlparser.Expr) {
+	a.DistinctExpr = expr
+	for i, aggr := range a.Aggregations {
+		if !aggr.Distinct || aggr.OpCode == opcode.AggregateGroupConcat {
+			continue
+		}
+		args := aggr.Func.GetArgs()
+		if len(args) != 1 {
+			errDistinctAggrWithMultiExpr(aggr.Func)
+		}
+		a.Aggregations[i].HashDistinct = !ctx.SemTable.EqualsExpr(args[0], expr)
+	}
+}
+
 func pushAggregationThroughFilter(
 	ctx *plancontext.PlanningContext,
 	aggregator *Aggregator,
@@ -546,10 +571,7 @@ func splitAggrColumnsToLeftAndRight(
 	// Distinctable aggregation cannot be pushed down in the join.
 	// We keep node of the distinct aggregation expression to be used later for ordering.
 	if !canPushDistinctAggr {
-		if len(distinctExprs) != 1 {
-			errDistinctAggrWithMultiExpr(nil)
-		}
-		aggregator.DistinctExpr = distinctExprs[0]
+		aggregator.setDistinctExpr(ctx, distinctExprs[0])
 		return nil, errAbortAggrPushing
 	}
 
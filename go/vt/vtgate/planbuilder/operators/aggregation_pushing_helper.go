@@ -133,10 +133,6 @@ func (ab *aggBuilder) handleAggr(ctx *plancontext.PlanningContext, aggr Aggr) er
 	case opcode.AggregateMax, opcode.AggregateMin, opcode.AggregateAnyValue, opcode.AggregateConstant:
 		return ab.handlePushThroughAggregation(ctx, aggr)
 	case opcode.AggregateGroupConcat:
-		f := aggr.Func.(*sqlparser.GroupConcatExpr)
-		if f.Distinct || len(f.OrderBy) > 0 {
-			panic(vterrors.VT12001("cannot evaluate group concat with distinct or order by"))
-		}
 		// this needs special handling, currently aborting the push of function
 		// and later will try pushing the column instead.
 		// TODO: this should be handled better by pushing the function down.
@@ -146,6 +142,10 @@ func (ab *aggBuilder) handleAggr(ctx *plancontext.PlanningContext, aggr Aggr) er
 	case opcode.AggregateGtid:
 		// this is only used for SHOW GTID queries that will never contain joins
 		panic(vterrors.VT13001("cannot do join with vgtid"))
+	case opcode.AggregateGrouping:
+		// GROUPING() is evaluated by the aggregator above the join, so we only need a placeholder
+		ab.proj.addUnexploredExpr(aggr.Original, aggr.getPushColumn())
+		return nil
 	case opcode.AggregateSumDistinct, opcode.AggregateCountDistinct:
 		// we are not going to see values multiple times, so we don't need to multiply with the count(*) from the other side
 		return ab.handlePushThroughAggregation(ctx, aggr)
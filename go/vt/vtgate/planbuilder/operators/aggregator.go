@@ -19,6 +19,7 @@ package operators
 import (
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
 	"vitess.io/vitess/go/slice"
@@ -41,9 +42,10 @@ type (
 		Grouping     []GroupBy
 		Aggregations []Aggr
 
-		// We support a single distinct aggregation per aggregator. It is stored here.
+		// The input is sorted by the expression of the first distinct aggregation, stored here.
 		// When planning the ordering that the OrderedAggregate will require,
-		// this needs to be the last ORDER BY expression
+		// this needs to be the last ORDER BY expression.
+		// The distinct aggregations on other expressions are marked with HashDistinct.
 		DistinctExpr sqlparser.Expr
 
 		// Pushed will be set to true once this aggregation has been pushed deeper in the tree
@@ -80,8 +82,12 @@ func (a *Aggregator) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser
 }
 
 // createNonGroupingAggr creates the appropriate aggregation for a non-grouping, non-aggregation column
-// If the expression is constant, it returns AggregateConstant, otherwise AggregateAnyValue
+// If the expression is constant, it returns AggregateConstant, GROUPING() calls return AggregateGrouping,
+// otherwise AggregateAnyValue
 func createNonGroupingAggr(expr *sqlparser.AliasedExpr) Aggr {
+	if isGroupingFunc(expr.Expr) {
+		return NewAggr(opcode.AggregateGrouping, nil, expr, expr.ColumnName())
+	}
 	if sqlparser.IsConstant(expr.Expr) {
 		return NewAggr(opcode.AggregateConstant, nil, expr, expr.ColumnName())
 	} else {
@@ -89,6 +95,13 @@ func createNonGroupingAggr(expr *sqlparser.AliasedExpr) Aggr {
 	}
 }
 
+// isGroupingFunc returns true if the expression is a call to GROUPING(), which is used
+// to tell the super-aggregate rows of a WITH ROLLUP query apart from the other rows
+func isGroupingFunc(expr sqlparser.SQLNode) bool {
+	fnc, ok := expr.(*sqlparser.FuncExpr)
+	return ok && fnc.Qualifier.IsEmpty() && fnc.Name.EqualString("grouping")
+}
+
 func (a *Aggregator) addColumnWithoutPushing(ctx *plancontext.PlanningContext, expr *sqlparser.AliasedExpr, addToGroupBy bool) int {
 	offset := len(a.Columns)
 	a.Columns = append(a.Columns, expr)
@@ -185,15 +198,20 @@ func (a *Aggregator) AddColumn(ctx *plancontext.PlanningContext, reuse bool, gro
 		}
 	}
 
+	pushed := ae
 	if !groupBy {
 		aggr := createNonGroupingAggr(ae)
 		aggr.ColOffset = len(a.Columns)
 		a.Aggregations = append(a.Aggregations, aggr)
+		if aggr.OpCode == opcode.AggregateGrouping {
+			// GROUPING() can only be evaluated here, so we only need a placeholder from the input
+			pushed = aeWrap(aggr.getPushColumn())
+		}
 	}
 
 	offset = len(a.Columns)
 	a.Columns = append(a.Columns, ae)
-	incomingOffset := a.Source.AddColumn(ctx, false, groupBy, ae)
+	incomingOffset := a.Source.AddColumn(ctx, false, groupBy, pushed)
 
 	if offset != incomingOffset {
 		panic(errFailedToPlan(ae))
@@ -431,10 +449,11 @@ func (aggr Aggr) getPushColumn() sqlparser.Expr {
 		return aggr.Original.Expr
 	case opcode.AggregateCountStar:
 		return sqlparser.NewIntLiteral("1")
+	case opcode.AggregateGrouping:
+		// the rows coming from the input are never super-aggregate rows, so GROUPING() is always 0 for them
+		return sqlparser.NewIntLiteral("0")
 	case opcode.AggregateGroupConcat:
-		if len(aggr.Func.GetArgs()) > 1 {
-			panic(vterrors.VT12001("group_concat with more than 1 column"))
-		}
+		// the other arguments are added as separate columns when planning the offsets
 		return aggr.Func.GetArg()
 	default:
 		if len(aggr.Func.GetArgs()) > 1 {
@@ -444,12 +463,31 @@ func (aggr Aggr) getPushColumn() sqlparser.Expr {
 	}
 }
 
+// GroupConcatOrderExprs returns the expressions the values of a GROUP_CONCAT are sorted by.
+// Like in MySQL, a number refers to one of the arguments of the GROUP_CONCAT.
+func (aggr Aggr) GroupConcatOrderExprs() []sqlparser.Expr {
+	gc := aggr.Func.(*sqlparser.GroupConcatExpr)
+	exprs := make([]sqlparser.Expr, 0, len(gc.OrderBy))
+	for _, order := range gc.OrderBy {
+		expr := order.Expr
+		if lit, ok := expr.(*sqlparser.Literal); ok && lit.Type == sqlparser.IntVal {
+			if num, err := strconv.Atoi(lit.Val); err == nil && num >= 1 && num <= len(gc.Exprs) {
+				expr = gc.Exprs[num-1]
+			}
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs
+}
+
 func (aggr Aggr) getPushColumnExprs() []sqlparser.Expr {
 	switch aggr.OpCode {
 	case opcode.AggregateAnyValue, opcode.AggregateConstant:
 		return []sqlparser.Expr{aggr.Original.Expr}
 	case opcode.AggregateCountStar:
 		return []sqlparser.Expr{sqlparser.NewIntLiteral("1")}
+	case opcode.AggregateGrouping:
+		return []sqlparser.Expr{aggr.getPushColumn()}
 	default:
 		if aggr.Func == nil {
 			return nil
@@ -540,6 +578,34 @@ func (a *Aggregator) pushRemainingGroupingColumnsAndWeightStrings(ctx *planconte
 
 		a.Aggregations[idx].WSOffset = offset
 	}
+	for idx, aggr := range a.Aggregations {
+		if aggr.OpCode == opcode.AggregateGroupConcat {
+			a.pushGroupConcatColumns(ctx, &a.Aggregations[idx])
+		}
+	}
+}
+
+// pushGroupConcatColumns pushes the columns needed to compute a GROUP_CONCAT from the individual
+// values of each group, when it has multiple arguments or the values have to be deduplicated or sorted
+func (a *Aggregator) pushGroupConcatColumns(ctx *plancontext.PlanningContext, aggr *Aggr) {
+	gc := aggr.Func.(*sqlparser.GroupConcatExpr)
+	if len(gc.Exprs) == 1 && !aggr.groupConcatNeedsRows() {
+		return
+	}
+
+	aggr.ArgOffsets = []int{aggr.ColOffset}
+	for _, arg := range gc.Exprs[1:] {
+		aggr.ArgOffsets = append(aggr.ArgOffsets, a.internalAddColumn(ctx, aeWrap(arg), false))
+	}
+	for _, expr := range aggr.GroupConcatOrderExprs() {
+		offset := a.internalAddColumn(ctx, aeWrap(expr), false)
+		wsOffset := -1
+		if ctx.NeedsWeightString(expr) {
+			wsOffset = a.internalAddWSColumn(ctx, offset, aeWrap(weightStringFor(expr)))
+		}
+		aggr.OrderOffsets = append(aggr.OrderOffsets, offset)
+		aggr.OrderWSOffsets = append(aggr.OrderWSOffsets, wsOffset)
+	}
 }
 
 func (a *Aggregator) internalAddWSColumn(ctx *plancontext.PlanningContext, inOffset int, aliasedExpr *sqlparser.AliasedExpr) int {
@@ -589,6 +655,8 @@ func (a *Aggregator) SplitAggregatorBelowOperators(ctx *plancontext.PlanningCont
 	newOp.Pushed = false
 	newOp.Original = false
 	newOp.DT = nil
+	// the super-aggregate rows are only produced by the aggregator above
+	newOp.WithRollup = false
 
 	// We need to make sure that the columns are cloned so that the original operator is not affected
 	// by the changes we make to the new operator
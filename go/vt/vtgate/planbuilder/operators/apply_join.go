@@ -50,6 +50,14 @@ type (
 		// these are needed by other operators further down the right hand side of the join
 		ExtraLHSVars []BindVarExpr
 
+		// Lateral is set when the RHS is a LATERAL derived table that uses columns from the LHS.
+		Lateral bool
+		// LateralPredicates are the predicates inside the LATERAL derived table that use columns from the LHS.
+		// They need the LHS columns as arguments, but are not used as join predicates when the join is merged into a route.
+		LateralPredicates []applyJoinColumn
+		// mustMergeLateral is set when the LATERAL derived table uses the LHS in ways we can only plan by merging the join into a route
+		mustMergeLateral bool
+
 		// After offset planning
 
 		// Columns stores the column indexes of the columns coming from the left and right side
@@ -114,6 +122,7 @@ func (aj *ApplyJoin) Clone(inputs []Operator) Operator {
 	kopy.JoinPredicates = aj.JoinPredicates.clone()
 	kopy.Vars = maps.Clone(aj.Vars)
 	kopy.ExtraLHSVars = slices.Clone(aj.ExtraLHSVars)
+	kopy.LateralPredicates = slices.Clone(aj.LateralPredicates)
 	return &kopy
 }
 
@@ -278,13 +287,9 @@ func (aj *ApplyJoin) AddWSColumn(ctx *plancontext.PlanningContext, offset int, u
 		aj.JoinColumns.addRight(wsExpr)
 	}
 
-	if out >= 0 {
-		aj.addOffset(out)
-	} else {
-		col := aj.getJoinColumnFor(ctx, aeWrap(wsExpr), wsExpr, !ctx.ContainsAggr(wsExpr))
-		aj.JoinColumns.add(col)
-		aj.planOffsetFor(ctx, col)
-	}
+	// the weight_string has been added to JoinColumns above, so we only need to record its offset,
+	// or the two would get out of sync
+	aj.addOffset(out)
 
 	return len(aj.Columns) - 1
 }
@@ -294,12 +299,15 @@ func (aj *ApplyJoin) planOffsets(ctx *plancontext.PlanningContext) Operator {
 		// we've already done offset planning
 		return nil
 	}
+	if aj.mustMergeLateral {
+		panic(vterrors.VT12001("cross-shard LATERAL derived table using columns from the left side of the join outside of its WHERE clause"))
+	}
 	for _, col := range aj.JoinColumns.columns {
 		// Read the type description for applyJoinColumn to understand the following code
 		aj.planOffsetFor(ctx, col)
 	}
 
-	for _, col := range aj.JoinPredicates.columns {
+	for _, col := range append(slices.Clone(aj.JoinPredicates.columns), aj.LateralPredicates...) {
 		for _, lhsExpr := range col.LHSExprs {
 			if _, found := aj.Vars[lhsExpr.Name]; found {
 				continue
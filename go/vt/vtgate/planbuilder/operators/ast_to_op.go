@@ -29,6 +29,7 @@ import (
 const (
 	foreignKeyConstraintValues = "fkc_vals"
 	foreignKeyUpdateExpr       = "fkc_upd"
+	movedRowValue              = "mv_val"
 )
 
 // translateQueryToOp creates an operator tree that represents the input SELECT or UNION query
@@ -254,6 +255,9 @@ func getOperatorFromTableExpr(ctx *plancontext.PlanningContext, tableExpr sqlpar
 
 func getOperatorFromJoinTableExpr(ctx *plancontext.PlanningContext, tableExpr *sqlparser.JoinTableExpr) Operator {
 	lhs := getOperatorFromTableExpr(ctx, tableExpr.LeftExpr, false)
+	if lateral := lateralDerivedTable(tableExpr.RightExpr); lateral != nil {
+		return createLateralJoin(ctx, tableExpr, lhs, lateral)
+	}
 	rhs := getOperatorFromTableExpr(ctx, tableExpr.RightExpr, false)
 
 	switch tableExpr.Join {
@@ -411,6 +415,14 @@ func idForRecursiveTable(ctx *plancontext.PlanningContext, def *semantics.CTE) s
 func crossJoin(ctx *plancontext.PlanningContext, exprs sqlparser.TableExprs) Operator {
 	var output Operator
 	for _, tableExpr := range exprs {
+		if lateral := lateralDerivedTable(tableExpr); lateral != nil && output != nil {
+			output = newLateralJoin(ctx, output, lateral, sqlparser.NormalJoinType)
+			continue
+		}
+		if _, isAliased := tableExpr.(*sqlparser.AliasedTableExpr); !isAliased && output != nil && usesTablesOf(ctx, tableExpr, TableID(output)) {
+			// only a LATERAL derived table inside of a join can use the tables of the earlier items of the FROM clause
+			panic(vterrors.VT12001("LATERAL derived table inside a join using the tables of an earlier item of the FROM clause"))
+		}
 		op := getOperatorFromTableExpr(ctx, tableExpr, len(exprs) == 1)
 		if output == nil {
 			output = op
@@ -421,6 +433,18 @@ func crossJoin(ctx *plancontext.PlanningContext, exprs sqlparser.TableExprs) Ope
 	return output
 }
 
+// lateralDerivedTable returns the table expression if it is a LATERAL derived table, and nil otherwise
+func lateralDerivedTable(tableExpr sqlparser.TableExpr) *sqlparser.AliasedTableExpr {
+	aliased, ok := tableExpr.(*sqlparser.AliasedTableExpr)
+	if !ok {
+		return nil
+	}
+	if dt, ok := aliased.Expr.(*sqlparser.DerivedTable); !ok || !dt.Lateral {
+		return nil
+	}
+	return aliased
+}
+
 func createQueryTableForDML(
 	ctx *plancontext.PlanningContext,
 	tableExpr sqlparser.TableExpr,
@@ -17,8 +17,10 @@ limitations under the License.
 package operators
 
 import (
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
 )
 
 func tryMergeRecurse(ctx *plancontext.PlanningContext, in *RecurseCTE) (Operator, *ApplyResult) {
@@ -74,9 +76,96 @@ func tryMergeCTESharded(ctx *plancontext.PlanningContext, seed, term *Route, in
 		}
 	}
 
+	if recursionStaysInShard(ctx, seed, term, in) {
+		return mergeCTE(ctx, seed, term, tblA, in, nil)
+	}
+
 	return nil
 }
 
+// recursionStaysInShard returns true when every row produced by the recursive part of the CTE is guaranteed to
+// live on the same shard as the row that produced it. This is the case when the term is routed using a unique
+// vindex on a column coming from the CTE, that column is the same vindex column in the seed, and the term
+// passes the vindex column of its own table through at the same position.
+// When this holds, each shard can evaluate the whole recursion on its own, and the CTE can be sent down as is.
+func recursionStaysInShard(ctx *plancontext.PlanningContext, seed, term *Route, in *RecurseCTE) bool {
+	tblB := term.Routing.(*ShardedRouting)
+	if tblB.RouteOpCode != engine.EqualUnique {
+		return false
+	}
+	vdx := tblB.SelectedVindex()
+	vExprs := tblB.VindexExpressions()
+	if vdx == nil || len(vExprs) != 1 {
+		return false
+	}
+	arg, ok := vExprs[0].(*sqlparser.Argument)
+	if !ok {
+		return false
+	}
+
+	union, ok := in.Def.Query.(*sqlparser.Union)
+	if !ok {
+		return false
+	}
+	termSel, ok := union.Right.(*sqlparser.Select)
+	if !ok {
+		return false
+	}
+
+	for _, pred := range in.Predicates {
+		for _, bvExpr := range pred.LeftExprs {
+			if bvExpr.Name != arg.Name {
+				continue
+			}
+			offset := cteColumnOffset(in.Def, bvExpr.Expr.Name)
+			if offset < 0 {
+				return false
+			}
+			seedExpr := cteColumnExpr(union.Left.GetColumns(), offset)
+			termExpr := cteColumnExpr(termSel.GetColumns(), offset)
+			if seedExpr == nil || termExpr == nil {
+				return false
+			}
+			return findColumnVindex(ctx, seed, seedExpr) == vdx && findColumnVindex(ctx, term, termExpr) == vdx
+		}
+	}
+	return false
+}
+
+// cteColumnOffset returns the position of the named column in the CTE, or -1 if it can't be found
+func cteColumnOffset(def *semantics.CTE, name sqlparser.IdentifierCI) int {
+	if len(def.Columns) > 0 {
+		for i, col := range def.Columns {
+			if col.Equal(name) {
+				return i
+			}
+		}
+		return -1
+	}
+	for i, selExpr := range def.Query.GetColumns() {
+		ae, ok := selExpr.(*sqlparser.AliasedExpr)
+		if !ok {
+			return -1
+		}
+		if name.EqualString(ae.ColumnName()) {
+			return i
+		}
+	}
+	return -1
+}
+
+func cteColumnExpr(selExprs []sqlparser.SelectExpr, offset int) *sqlparser.ColName {
+	if offset >= len(selExprs) {
+		return nil
+	}
+	ae, ok := selExprs[offset].(*sqlparser.AliasedExpr)
+	if !ok {
+		return nil
+	}
+	col, _ := ae.Expr.(*sqlparser.ColName)
+	return col
+}
+
 func mergeCTE(ctx *plancontext.PlanningContext, seed, term *Route, r Routing, in *RecurseCTE, conditions []engine.Condition) *Route {
 	in.Def.Merged = true
 	hz := in.Horizon
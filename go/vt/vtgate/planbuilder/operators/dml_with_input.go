@@ -36,6 +36,10 @@ type DMLWithInput struct {
 	updList []updList
 	BvList  []map[string]int
 
+	// MovesRows is set when the DMLs delete the input rows and insert them again,
+	// to move them to the shards of their new primary vindex values.
+	MovesRows bool
+
 	noColumns
 	noPredicates
 }
@@ -96,13 +96,20 @@ func (h *Horizon) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.
 	}
 
 	newExpr := semantics.RewriteDerivedTableExpression(expr, tableInfo)
-	if ctx.ContainsAggr(newExpr) {
+	if ctx.ContainsAggr(newExpr) || ctx.ContainsWindowFunc(newExpr) || h.hasRollup() {
 		return newFilter(h, expr)
 	}
 	h.Source = h.Source.AddPredicate(ctx, newExpr)
 	return h
 }
 
+// hasRollup returns true if the query is a GROUP BY ... WITH ROLLUP. Predicates on top of such a query
+// can't be pushed into it, since they also have to filter the super-aggregate rows.
+func (h *Horizon) hasRollup() bool {
+	sel, isSel := h.Query.(*sqlparser.Select)
+	return isSel && sel.GroupBy != nil && sel.GroupBy.WithRollup
+}
+
 func (h *Horizon) AddColumn(ctx *plancontext.PlanningContext, reuse bool, _ bool, expr *sqlparser.AliasedExpr) int {
 	if !reuse {
 		panic(errNoNewColumns)
@@ -106,7 +106,7 @@ func expandSelectHorizon(ctx *plancontext.PlanningContext, horizon *Horizon, sel
 		// SQL execution order: Projection → Aggregation → HAVING → Window → Distinct → Order → Limit
 		// We wrap the current operator (which is either a Projection or Aggregation)
 		// with the Window operator to handle these calculations.
-		op = newWindow(op, qp)
+		op = createWindow(ctx, op, qp, horizon)
 		extracted = append(extracted, "Window")
 	}
 
@@ -17,9 +17,13 @@ limitations under the License.
 package operators
 
 import (
+	"fmt"
+	"slices"
+
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
 )
@@ -32,6 +36,15 @@ type Join struct {
 	// NormalJoinType, StraightJoinType and LeftJoinType.
 	JoinType sqlparser.JoinType
 
+	// Lateral is set when the RHS is a LATERAL derived table that uses columns from the LHS.
+	Lateral bool
+	// LateralPredicates are the predicates of the LATERAL derived table that use columns from the LHS.
+	// They have been broken up into LHS and RHS parts, and the RHS part has been pushed into the derived table.
+	LateralPredicates []applyJoinColumn
+	// mustMergeLateral is set when the LATERAL derived table uses columns from the LHS outside of
+	// the predicates we could break up, which means that both sides have to end up in the same route
+	mustMergeLateral bool
+
 	noColumns
 }
 
@@ -42,6 +55,7 @@ func (j *Join) Clone(inputs []Operator) Operator {
 	clone := *j
 	clone.LHS = inputs[0]
 	clone.RHS = inputs[1]
+	clone.LateralPredicates = slices.Clone(j.LateralPredicates)
 	return &clone
 }
 
@@ -50,7 +64,7 @@ func (j *Join) GetOrdering(*plancontext.PlanningContext) []OrderBy {
 }
 
 func (j *Join) tryCompact(ctx *plancontext.PlanningContext) Operator {
-	if !j.JoinType.IsCommutative() {
+	if !j.JoinType.IsCommutative() || j.Lateral {
 		// if we can't move tables around, we can't merge these inputs
 		return nil
 	}
@@ -98,21 +112,162 @@ func createLeftOuterJoin(ctx *plancontext.PlanningContext, join *sqlparser.JoinT
 		JoinType:       join.Join,
 	}
 
+	return addOuterJoinPredicate(ctx, joinOp, join.Condition.On)
+}
+
+func addOuterJoinPredicate(ctx *plancontext.PlanningContext, joinOp *Join, predicate sqlparser.Expr) Operator {
 	// mark the RHS as outer tables so we know which columns are nullable
-	ctx.OuterTables = ctx.OuterTables.Merge(TableID(rhs))
+	ctx.OuterTables = ctx.OuterTables.Merge(TableID(joinOp.RHS))
 
 	// for outer joins we have to be careful with the predicates we use
-	var op Operator
-	subq, _, _ := getSubQuery(join.Condition.On)
-	if subq != nil {
-		panic(vterrors.VT12001("subquery in outer join predicate"))
-	}
-	predicate := join.Condition.On
 	sqlparser.RemoveKeyspaceInCol(predicate)
-	joinOp.Predicate = predicate
-	op = joinOp
+	if subq, _, _ := getSubQuery(predicate); subq == nil {
+		joinOp.Predicate = predicate
+		return joinOp
+	}
+
+	sqc := &SubQueryBuilder{}
+	joinID := TableID(joinOp)
+	rhsID := TableID(joinOp.RHS)
+	var predicates []sqlparser.Expr
+	for _, pred := range sqlparser.SplitAndExpression(nil, predicate) {
+		if subq, _, _ := getSubQuery(pred); subq == nil {
+			predicates = append(predicates, pred)
+			continue
+		}
+		if ctx.SemTable.RecursiveDeps(pred).KeepOnly(joinID).IsSolvedBy(rhsID) {
+			// a predicate that only uses the RHS filters the rows of the RHS before they are joined,
+			// so it can be planned like any other filter, correlated subqueries included
+			joinOp.RHS = addJoinPredicates(ctx, pred, joinOp.RHS)
+			continue
+		}
+		predicates = append(predicates, pullOutOuterJoinSubqueries(ctx, sqc, pred, joinID))
+	}
+	joinOp.Predicate = sqlparser.AndExpressions(predicates...)
+	return sqc.getRootOperator(joinOp, nil)
+}
+
+// pullOutOuterJoinSubqueries replaces the subqueries of an outer join predicate with the arguments
+// holding their results, so that they can be executed before the join.
+// This is only possible for subqueries that don't depend on the tables of the join.
+func pullOutOuterJoinSubqueries(ctx *plancontext.PlanningContext, sqc *SubQueryBuilder, pred sqlparser.Expr, joinID semantics.TableSet) sqlparser.Expr {
+	newPred, subqs := sqc.pullOutValueSubqueries(ctx, pred, joinID, false)
+	for _, sq := range subqs {
+		if sq.correlated {
+			panic(vterrors.VT12001("correlated subquery in outer join predicate that uses the tables of the outer side of the join"))
+		}
+		sq.outerJoinPredicate = true
+	}
+	newPred = rewriteColNameToArgument(ctx, newPred, subqs, subqs...)
+
+	// For IN and NOT IN, we have to check if the subquery returned any rows, like we do for filters
+	return sqlparser.Rewrite(newPred, nil, func(cursor *sqlparser.Cursor) bool {
+		compExpr, isCompExpr := cursor.Node().(*sqlparser.ComparisonExpr)
+		if !isCompExpr {
+			return true
+		}
+		listArg, isListArg := compExpr.Right.(sqlparser.ListArg)
+		if !isListArg {
+			return true
+		}
+		for _, sq := range subqs {
+			if sq.ArgName != string(listArg) {
+				continue
+			}
+			if sq.HasValuesName == "" {
+				sq.HasValuesName = ctx.ReservedVars.ReserveHasValuesSubQuery()
+			}
+			hasValues := sqlparser.NewArgument(sq.HasValuesName)
+			if sq.FilterType == opcode.PulloutIn {
+				cursor.Replace(sqlparser.AndExpressions(hasValues, compExpr))
+			} else {
+				cursor.Replace(&sqlparser.OrExpr{Left: sqlparser.NewNotExpr(hasValues), Right: compExpr})
+			}
+		}
+		return true
+	}).(sqlparser.Expr)
+}
 
-	return op
+func createLateralJoin(ctx *plancontext.PlanningContext, join *sqlparser.JoinTableExpr, lhs Operator, tableExpr *sqlparser.AliasedTableExpr) Operator {
+	joinOp := newLateralJoin(ctx, lhs, tableExpr, join.Join)
+	switch join.Join {
+	case sqlparser.NormalJoinType, sqlparser.StraightJoinType:
+		return addJoinPredicates(ctx, join.Condition.On, joinOp)
+	case sqlparser.LeftJoinType:
+		return addOuterJoinPredicate(ctx, joinOp, join.Condition.On)
+	case sqlparser.RightJoinType:
+		if joinOp.Lateral {
+			panic(vterrors.VT12001("RIGHT JOIN with a LATERAL derived table that uses the tables on its left"))
+		}
+		return createLeftOuterJoin(ctx, join, lhs, joinOp.RHS)
+	default:
+		panic(vterrors.VT13001("unsupported: %s", join.Join.ToString()))
+	}
+}
+
+// newLateralJoin creates a join with a LATERAL derived table on the RHS.
+// The predicates of the derived table that use columns from the LHS are broken up the same way as join predicates,
+// so that the derived table can be evaluated once per row of the LHS when the two sides can't be merged into a single route.
+func newLateralJoin(ctx *plancontext.PlanningContext, lhs Operator, tableExpr *sqlparser.AliasedTableExpr, joinType sqlparser.JoinType) *Join {
+	dt, ok := tableExpr.Expr.(*sqlparser.DerivedTable)
+	if !ok {
+		panic(vterrors.VT13001(fmt.Sprintf("expected derived table, got %T", tableExpr.Expr)))
+	}
+	lhsID := TableID(lhs)
+	if !usesTablesOf(ctx, dt.Select, lhsID) {
+		rhs := getOperatorFromAliasedTableExpr(ctx, tableExpr, false)
+		return &Join{binaryOperator: newBinaryOp(lhs, rhs), JoinType: joinType}
+	}
+
+	var lateralPredicates []applyJoinColumn
+	if sel, isSel := dt.Select.(*sqlparser.Select); isSel && sel.Where != nil {
+		var remaining []sqlparser.Expr
+		for _, pred := range sqlparser.SplitAndExpression(nil, sel.Where.Expr) {
+			if subq, _, _ := getSubQuery(pred); subq != nil || !ctx.SemTable.RecursiveDeps(pred).IsOverlapping(lhsID) {
+				remaining = append(remaining, pred)
+				continue
+			}
+			sqlparser.RemoveKeyspaceInCol(pred)
+			lateralPredicates = append(lateralPredicates, breakExpressionInLHSandRHS(ctx, pred, lhsID))
+		}
+		// the predicates using the LHS are added to the operator of the derived table as join predicates below
+		if len(remaining) == 0 {
+			sel.Where = nil
+		} else {
+			sel.Where.Expr = sqlparser.AndExpressions(remaining...)
+		}
+	}
+
+	rhs := getOperatorFromAliasedTableExpr(ctx, tableExpr, false)
+	horizon, ok := rhs.(*Horizon)
+	if !ok && len(lateralPredicates) > 0 {
+		panic(vterrors.VT13001(fmt.Sprintf("expected horizon for LATERAL derived table, got %T", rhs)))
+	}
+	for i, col := range lateralPredicates {
+		jp := ctx.PredTracker.NewJoinPredicate(col.RHSExpr)
+		lateralPredicates[i].JoinPredicateID = &jp.ID
+		horizon.Source = horizon.Source.AddPredicate(ctx, jp)
+	}
+
+	return &Join{
+		binaryOperator:    newBinaryOp(lhs, rhs),
+		JoinType:          joinType,
+		Lateral:           true,
+		LateralPredicates: lateralPredicates,
+		mustMergeLateral:  usesTablesOf(ctx, dt.Select, lhsID),
+	}
+}
+
+// usesTablesOf returns true if any column in the node depends on the given tables
+func usesTablesOf(ctx *plancontext.PlanningContext, node sqlparser.SQLNode, tables semantics.TableSet) (found bool) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		col, ok := node.(*sqlparser.ColName)
+		if ok && ctx.SemTable.RecursiveDeps(col).IsOverlapping(tables) {
+			found = true
+		}
+		return !found, nil
+	}, node)
+	return
 }
 
 func createInnerJoin(ctx *plancontext.PlanningContext, tableExpr *sqlparser.JoinTableExpr, lhs, rhs Operator) Operator {
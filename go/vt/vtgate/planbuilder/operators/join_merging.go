@@ -18,7 +18,9 @@ package operators
 
 import (
 	"fmt"
+	"slices"
 
+	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -35,6 +37,7 @@ func (jm *joinMerger) mergeJoinInputs(ctx *plancontext.PlanningContext, lhs, rhs
 		return nil
 	}
 
+	predicates := jm.routingPredicates()
 	switch {
 	// We clone the right hand side and try and push all the join predicates that are solved entirely by that side.
 	// If a dual is on the left side, and it is a left join (all right joins are changed to left joins), then we can only merge if the right side is a single sharded routing.
@@ -42,7 +45,7 @@ func (jm *joinMerger) mergeJoinInputs(ctx *plancontext.PlanningContext, lhs, rhs
 		newRouting := rhsRoute.Routing.Clone()
 
 		rhsID := TableID(rhsRoute)
-		for _, predicate := range jm.predicates {
+		for _, predicate := range predicates {
 			if ctx.SemTable.DirectDeps(predicate).IsSolvedBy(rhsID) {
 				newRouting = UpdateRoutingLogic(ctx, predicate, newRouting)
 			}
@@ -61,6 +64,10 @@ func (jm *joinMerger) mergeJoinInputs(ctx *plancontext.PlanningContext, lhs, rhs
 	// As both are reference route. We need to merge the alternates as well.
 	case a == anyShard && b == anyShard && sameKeyspace:
 		newrouting := mergeAnyShardRoutings(ctx, routingA.(*AnyShardRouting), routingB.(*AnyShardRouting), jm.predicates, jm.joinType)
+		if jm.lateral {
+			// the alternates are planned as regular joins, which can't be used for a LATERAL derived table
+			newrouting.Alternates = nil
+		}
 		return jm.merge(ctx, lhsRoute, rhsRoute, newrouting)
 
 	// an unsharded/reference route can be merged with anything going to that keyspace
@@ -85,7 +92,7 @@ func (jm *joinMerger) mergeJoinInputs(ctx *plancontext.PlanningContext, lhs, rhs
 
 	// sharded routing is complex, so we handle it in a separate method
 	case a == sharded && b == sharded:
-		result := tryMergeShardedRouting(ctx, lhsRoute, rhsRoute, jm, jm.predicates)
+		result := tryMergeShardedRouting(ctx, lhsRoute, rhsRoute, jm, predicates)
 		if result == nil {
 			debugNoRewrite("apply join merge blocked: sharded routing merge failed (different keyspaces or incompatible vindex predicates)")
 		}
@@ -145,6 +152,12 @@ type (
 		// joinType is permitted to store only 3 of the possible values
 		// NormalJoinType, StraightJoinType and LeftJoinType.
 		joinType sqlparser.JoinType
+
+		// lateral is set when the RHS is a LATERAL derived table. The lateralPredicates are the
+		// predicates inside the derived table that use the LHS. They are used to decide if the
+		// inputs can be merged, but they stay in the derived table instead of becoming join predicates
+		lateral           bool
+		lateralPredicates []sqlparser.Expr
 	}
 
 	routingType int
@@ -235,6 +248,25 @@ func newJoinMerge(predicates []sqlparser.Expr, joinType sqlparser.JoinType) *joi
 	}
 }
 
+func newLateralJoinMerge(predicates []sqlparser.Expr, lateralPredicates []applyJoinColumn, joinType sqlparser.JoinType) *joinMerger {
+	return &joinMerger{
+		predicates: predicates,
+		joinType:   joinType,
+		lateral:    true,
+		lateralPredicates: slice.Map(lateralPredicates, func(col applyJoinColumn) sqlparser.Expr {
+			return col.Original
+		}),
+	}
+}
+
+// routingPredicates returns all the predicates that can be used to decide whether the inputs can be merged
+func (jm *joinMerger) routingPredicates() []sqlparser.Expr {
+	if len(jm.lateralPredicates) == 0 {
+		return jm.predicates
+	}
+	return append(slices.Clone(jm.predicates), jm.lateralPredicates...)
+}
+
 func (jm *joinMerger) mergeShardedRouting(ctx *plancontext.PlanningContext, r1, r2 *ShardedRouting, op1, op2 *Route, conditions ...engine.Condition) *Route {
 	return jm.merge(ctx, op1, op2, mergeShardedRouting(r1, r2), conditions...)
 }
@@ -263,6 +295,7 @@ func getKeyspaceName(routing Routing) string {
 
 func (jm *joinMerger) merge(ctx *plancontext.PlanningContext, op1, op2 *Route, r Routing, conditions ...engine.Condition) *Route {
 	aj := NewApplyJoin(ctx, op1.Source, op2.Source, ctx.SemTable.AndExpressions(jm.predicates...), jm.joinType, false)
+	aj.Lateral = jm.lateral
 	for _, column := range aj.JoinPredicates.columns {
 		if column.JoinPredicateID != nil {
 			ctx.PredTracker.Set(*column.JoinPredicateID, column.Original)
@@ -190,6 +190,10 @@ func tryTruncateColumnsAt(op Operator, truncateAt int) bool {
 	case *Limit:
 		return tryTruncateColumnsAt(op.Source, truncateAt)
 	case *SubQuery:
+		if op.evaluatedPerRow() {
+			// the value or the filter of the subquery is computed from the outer columns, so they can't be dropped early
+			return false
+		}
 		for _, offset := range op.Vars {
 			if offset >= truncateAt {
 				return false
@@ -372,6 +372,12 @@ func (p *Projection) addColumn(
 		return p.addProjExpr(pe)
 	}
 
+	if sq, ok := p.Source.(*SubQuery); ok && sq.usesResult(expr) && !sq.isResultColumn(expr) {
+		// the value of a correlated subquery is only added to the rows of the outer query in vtgate,
+		// so expressions using it are evaluated here
+		return p.addProjExpr(pe)
+	}
+
 	var inputOffset int
 	if nothingNeedsFetching(ctx, expr) {
 		// if we don't need to fetch anything, we could just evaluate it in the projection
@@ -150,6 +150,11 @@ func pushProjectionToOuter(ctx *plancontext.PlanningContext, p *Projection, sq *
 			return p, NoRewrite
 		}
 
+		if sq.usesResult(pe.EvalExpr) {
+			// the value of the subquery is computed in vtgate, after the outer query has been executed
+			return p, NoRewrite
+		}
+
 		se, ok := pe.Info.(SubQueryExpression)
 		if ok {
 			pe.EvalExpr = rewriteColNameToArgument(ctx, pe.EvalExpr, se, sq)
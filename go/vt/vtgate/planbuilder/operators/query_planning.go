@@ -19,6 +19,7 @@ package operators
 import (
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 
 	"vitess.io/vitess/go/slice"
@@ -136,6 +137,9 @@ func tryMergeApplyJoin(in *ApplyJoin, ctx *plancontext.PlanningContext) (_ Opera
 	})
 
 	jm := newJoinMerge(preds, in.JoinType)
+	if in.Lateral {
+		jm = newLateralJoinMerge(preds, in.LateralPredicates, in.JoinType)
+	}
 	r := jm.mergeJoinInputs(ctx, in.LHS, in.RHS)
 	if r == nil {
 		// Specific failure reason already logged by mergeJoinInputs
@@ -152,7 +156,7 @@ func tryMergeApplyJoin(in *ApplyJoin, ctx *plancontext.PlanningContext) (_ Opera
 	//  - Rewrite join predicates already pushed down &&
 	//  - Save original join predicates if we have to bail out of the rewrite
 	original := map[predicates.ID]sqlparser.Expr{}
-	for _, col := range aj.JoinPredicates.columns {
+	for _, col := range append(slices.Clone(aj.JoinPredicates.columns), in.LateralPredicates...) {
 		if col.JoinPredicateID != nil {
 			// if we have pushed down a join predicate, we need to restore it to its original shape, without the argument from the LHS
 			id := *col.JoinPredicateID
@@ -590,6 +594,11 @@ func setUpperLimit(in *Limit) (Operator, *ApplyResult) {
 		case *Join, *ApplyJoin, *SubQueryContainer, *SubQuery:
 			// we can't push limits down on either side
 			return SkipChildren
+		case *Window:
+			if op.InVTGate {
+				// the window functions need all the rows of their partitions
+				return SkipChildren
+			}
 		case *Aggregator:
 			if len(op.Grouping) > 0 {
 				// we can't push limits down if we have a group by
@@ -655,6 +664,12 @@ func tryPushOrdering(ctx *plancontext.PlanningContext, in *Ordering) (Operator,
 	case *Projection:
 		return pushOrderingUnderProjection(ctx, in, src)
 	case *Aggregator:
+		if src.WithRollup {
+			// the super-aggregate rows are produced after the groups they summarize,
+			// and the grouping order decides what gets rolled up, so we have to sort the output
+			debugNoRewrite("ordering push blocked: aggregation is WITH ROLLUP")
+			return in, NoRewrite
+		}
 		if !src.QP.AlignGroupByAndOrderBy(ctx) && !overlaps(ctx, in.Order, src.Grouping) {
 			debugNoRewrite("ordering push blocked: GROUP BY and ORDER BY cannot be aligned and don't overlap")
 			return in, NoRewrite
@@ -837,6 +852,10 @@ func tryPushFilter(ctx *plancontext.PlanningContext, in *Filter) (Operator, *App
 				debugNoRewrite("filter push blocked: predicate depends on inner subquery tables")
 				return in, NoRewrite
 			}
+			if src.usesResult(pred) {
+				debugNoRewrite("filter push blocked: predicate uses the value of a correlated subquery")
+				return in, NoRewrite
+			}
 		}
 		src.Outer, in.Source = in, src.Outer
 		return src, Rewrote("push filter to outer query in subquery container")
@@ -931,6 +950,10 @@ func tryPushDistinct(in *Distinct) (Operator, *ApplyResult) {
 		in.Source = src.Source
 		return in, Rewrote("remove ordering under distinct")
 	case *Window:
+		if src.InVTGate {
+			debugNoRewrite("distinct push blocked: window functions are evaluated in vtgate")
+			return in, NoRewrite
+		}
 		if isDistinct(src.Source) {
 			debugNoRewrite("distinct push blocked: window source already has distinct")
 			return in, NoRewrite
@@ -19,6 +19,7 @@ package operators
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"slices"
 	"sort"
 	"strings"
@@ -86,6 +87,16 @@ type (
 		SubQueryExpression []*SubQuery // Subqueries associated with this aggregation
 
 		PushedDown bool // Whether the aggregation has been pushed down to the next layer
+
+		// HashDistinct is set for a distinct aggregation whose values are not sorted when they reach
+		// the aggregator, so the values seen in each group have to be remembered to skip the duplicates
+		HashDistinct bool
+
+		// Offsets of the arguments and of the ORDER BY expressions of a GROUP_CONCAT
+		// that is computed from the individual values of each group
+		ArgOffsets     []int
+		OrderOffsets   []int
+		OrderWSOffsets []int
 	}
 )
 
@@ -93,6 +104,13 @@ func (aggr Aggr) NeedsWeightString(ctx *plancontext.PlanningContext) bool {
 	return aggr.OpCode.NeedsComparableValues() && ctx.NeedsWeightString(aggr.Func.GetArg())
 }
 
+// groupConcatNeedsRows returns true for a GROUP_CONCAT that can't be computed by concatenating
+// the values already concatenated by the shards, since they have to be deduplicated or sorted together
+func (aggr Aggr) groupConcatNeedsRows() bool {
+	gc, ok := aggr.Func.(*sqlparser.GroupConcatExpr)
+	return ok && (gc.Distinct || len(gc.OrderBy) > 0)
+}
+
 func (aggr Aggr) GetTypeCollation(ctx *plancontext.PlanningContext) evalengine.Type {
 	if aggr.Func == nil {
 		return evalengine.NewUnknownType()
@@ -427,7 +445,7 @@ func (qp *QueryProjection) AggregationExpressions(ctx *plancontext.PlanningConte
 				panic(err)
 			}
 
-			if ctx.ContainsWindowFunc(selectExpr.Col) {
+			if ctx.ContainsWindowFunc(selectExpr.Col) || qp.needsRollupEvaluation(ctx, getExpr) {
 				sqlparser.CopyOnRewrite(aliasedExpr.Expr, qp.extractAggr(ctx, aliasedExpr, addAggr, makeComplex), nil, nil)
 				continue
 			}
@@ -481,6 +499,22 @@ func (qp *QueryProjection) extractAggr(
 			makeComplex()
 			return true
 		}
+		if isGroupingFunc(ex) {
+			ae := aeWrap(ex)
+			if ex == aliasedExpr.Expr {
+				ae = aliasedExpr
+			}
+			addAggr(createNonGroupingAggr(ae))
+			return false
+		}
+		if qp.needsRollupEvaluation(ctx, ex) {
+			makeComplex()
+			return true
+		}
+		if qp.WithRollup && sqlparser.IsConstant(ex) {
+			// constants can be evaluated as part of the expression on top of the aggregation
+			return false
+		}
 		if !qp.isExprInGroupByExprs(ctx, ex) {
 			aggr := createNonGroupingAggr(aeWrap(ex))
 			addAggr(aggr)
@@ -489,6 +523,28 @@ func (qp *QueryProjection) extractAggr(
 	}
 }
 
+// needsRollupEvaluation returns true if the expression has to be evaluated on top of the aggregation
+// of a WITH ROLLUP query, because it uses GROUPING() or grouping columns that are NULL in the
+// super-aggregate rows.
+func (qp *QueryProjection) needsRollupEvaluation(ctx *plancontext.PlanningContext, expr sqlparser.Expr) bool {
+	if !qp.WithRollup || qp.isExprInGroupByExprs(ctx, expr) {
+		return false
+	}
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		e, isExpr := node.(sqlparser.Expr)
+		if !isExpr {
+			return true, nil
+		}
+		if isGroupingFunc(e) || qp.isExprInGroupByExprs(ctx, e) {
+			found = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, expr)
+	return found
+}
+
 func (qp *QueryProjection) addOrderByToSelect(ctx *plancontext.PlanningContext) {
 orderBy:
 	// We need to return all columns that are being used for ordering
@@ -58,9 +58,38 @@ func optimizeJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyR
 	if newOp := op.tryCompact(ctx); newOp != nil {
 		return newOp, Rewrote("merged query graphs")
 	}
+	if op.Lateral {
+		return mergeOrLateralJoin(ctx, op)
+	}
 	return mergeOrJoin(ctx, op.LHS, op.RHS, sqlparser.SplitAndExpression(nil, op.Predicate), op.JoinType)
 }
 
+// mergeOrLateralJoin plans a join with a LATERAL derived table on the RHS.
+// Since the RHS uses columns from the LHS, we can't switch sides or use a hash join.
+func mergeOrLateralJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyResult) {
+	joinPredicates := sqlparser.SplitAndExpression(nil, op.Predicate)
+	jm := newLateralJoinMerge(joinPredicates, op.LateralPredicates, op.JoinType)
+	if newPlan := jm.mergeJoinInputs(ctx, op.LHS, op.RHS); newPlan != nil {
+		// the predicates pushed into the derived table can now use the LHS columns directly
+		for _, col := range op.LateralPredicates {
+			ctx.PredTracker.Set(*col.JoinPredicateID, col.Original)
+		}
+		newPlan.Routing = newPlan.Routing.resetRoutingLogic(ctx)
+		return newPlan, Rewrote("merge LATERAL join into single route")
+	}
+
+	checkCrossKeyspaceOp(ctx, op.LHS, op.RHS, "JOIN")
+
+	join := NewApplyJoin(ctx, Clone(op.LHS), Clone(op.RHS), nil, op.JoinType, false)
+	join.Lateral = true
+	join.LateralPredicates = op.LateralPredicates
+	join.mustMergeLateral = op.mustMergeLateral
+	for _, pred := range joinPredicates {
+		join.AddJoinPredicate(ctx, pred, true)
+	}
+	return join, Rewrote("logical join to lateral applyJoin")
+}
+
 func optimizeQueryGraph(ctx *plancontext.PlanningContext, op *QueryGraph) (result Operator, changed *ApplyResult) {
 	switch ctx.PlannerVersion {
 	case querypb.ExecuteOptions_Gen4Left2Right:
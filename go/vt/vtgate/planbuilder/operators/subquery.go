@@ -18,13 +18,17 @@ package operators
 
 import (
 	"fmt"
+	"io"
 	"maps"
 	"slices"
+	"strings"
 
 	"vitess.io/vitess/go/slice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
 )
@@ -52,6 +56,20 @@ type SubQuery struct {
 	// correlated stores whether this subquery is correlated or not.
 	// We use this information to fail the planning if we are unable to merge the subquery with a route.
 	correlated bool
+	// unboundCorrelation is set when the subquery uses columns of the outer query outside
+	// of the predicates that can be sent to it as join variables.
+	unboundCorrelation bool
+	// outerJoinPredicate is set when the subquery has been pulled out of the predicate of an outer join.
+	// The join predicate uses the arguments holding its result, so it can't be merged into a route.
+	outerJoinPredicate bool
+	// FilterPredicates are evaluated in vtgate against every row of the outer query, for correlated
+	// subqueries used as filters that have to be executed for every outer row.
+	// For correlated IN subqueries used as a value, they hold the comparison producing that value.
+	FilterPredicates     []sqlparser.Expr
+	PredicateWithOffsets evalengine.Expr
+	// ResultColumn is the offset at which the value of a correlated subquery used as an argument
+	// is inserted in the rows of the outer query, or -1 when it's not needed.
+	ResultColumn int
 
 	// IsArgument is set to true if the subquery puts the
 	IsArgument bool
@@ -69,6 +87,25 @@ func (sq *SubQuery) planOffsets(ctx *plancontext.PlanningContext) Operator {
 			sq.Vars[lhsExpr.Name] = offset
 		}
 	}
+	if len(sq.FilterPredicates) == 0 {
+		return nil
+	}
+
+	cfg := &evalengine.Config{
+		ResolveType: ctx.TypeForExpr,
+		Collation:   ctx.SemTable.Collation,
+		Environment: ctx.VSchema.Environment(),
+	}
+	predicate := sqlparser.AndExpressions(sq.FilterPredicates...)
+	rewritten := useOffsets(ctx, predicate, sq)
+	eexpr, err := evalengine.Translate(rewritten, cfg)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), evalengine.ErrTranslateExprNotSupported) {
+			panic(vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "%s: %s", evalengine.ErrTranslateExprNotSupported, sqlparser.String(predicate)))
+		}
+		panic(err)
+	}
+	sq.PredicateWithOffsets = eexpr
 	return nil
 }
 
@@ -126,6 +163,7 @@ func (sq *SubQuery) Clone(inputs []Operator) Operator {
 	klone.JoinColumns = slices.Clone(sq.JoinColumns)
 	klone.Vars = maps.Clone(sq.Vars)
 	klone.Predicates = slices.Clone(sq.Predicates)
+	klone.FilterPredicates = slices.Clone(sq.FilterPredicates)
 	return &klone
 }
 
@@ -177,26 +215,117 @@ func (sq *SubQuery) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparse
 }
 
 func (sq *SubQuery) AddColumn(ctx *plancontext.PlanningContext, reuseExisting bool, addToGroupBy bool, ae *sqlparser.AliasedExpr) int {
+	if sq.isResultColumn(ae.Expr) {
+		if sq.ResultColumn < 0 {
+			sq.ResultColumn = len(sq.Outer.GetColumns(ctx))
+		}
+		return sq.ResultColumn
+	}
+	if sq.usesResult(ae.Expr) {
+		panic(vterrors.VT12001("expression using the value of a correlated subquery: " + sqlparser.String(ae.Expr)))
+	}
 	ae = sqlparser.Clone(ae)
 	// we need to rewrite the column name to an argument if it's the same as the subquery column name
 	ae.Expr = rewriteColNameToArgument(ctx, ae.Expr, []*SubQuery{sq}, sq)
-	return sq.Outer.AddColumn(ctx, reuseExisting, addToGroupBy, ae)
+	return sq.fromOuterOffset(sq.Outer.AddColumn(ctx, reuseExisting, addToGroupBy, ae))
 }
 
 func (sq *SubQuery) AddWSColumn(ctx *plancontext.PlanningContext, offset int, underRoute bool) int {
-	return sq.Outer.AddWSColumn(ctx, offset, underRoute)
+	if sq.ResultColumn < 0 {
+		return sq.Outer.AddWSColumn(ctx, offset, underRoute)
+	}
+	if offset == sq.ResultColumn {
+		panic(vterrors.VT12001("weight_string of the value of a correlated subquery"))
+	}
+	if offset > sq.ResultColumn {
+		offset--
+	}
+	return sq.fromOuterOffset(sq.Outer.AddWSColumn(ctx, offset, underRoute))
 }
 
 func (sq *SubQuery) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
-	return sq.Outer.FindCol(ctx, expr, underRoute)
+	if sq.isResultColumn(expr) {
+		// the value of the subquery is always available, it only needs a place in the output
+		return sq.AddColumn(ctx, true, false, aeWrap(expr))
+	}
+	return sq.fromOuterOffset(sq.Outer.FindCol(ctx, expr, underRoute))
 }
 
 func (sq *SubQuery) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
-	return sq.Outer.GetColumns(ctx)
+	columns := sq.Outer.GetColumns(ctx)
+	if sq.ResultColumn < 0 {
+		return columns
+	}
+	return slices.Insert(slices.Clone(columns), sq.ResultColumn, aeWrap(sqlparser.NewColName(sq.ArgName)))
 }
 
 func (sq *SubQuery) GetSelectExprs(ctx *plancontext.PlanningContext) []sqlparser.SelectExpr {
-	return sq.Outer.GetSelectExprs(ctx)
+	exprs := sq.Outer.GetSelectExprs(ctx)
+	if sq.ResultColumn < 0 {
+		return exprs
+	}
+	return slices.Insert(slices.Clone(exprs), sq.ResultColumn, sqlparser.SelectExpr(aeWrap(sqlparser.NewColName(sq.ArgName))))
+}
+
+// evaluatedPerRow returns true if the subquery is executed for every row of the outer query
+func (sq *SubQuery) evaluatedPerRow() bool {
+	return len(sq.Predicates) > 0 && (sq.IsArgument || len(sq.FilterPredicates) > 0)
+}
+
+// isResultColumn returns true if the expression is the value of this subquery,
+// when it is computed in vtgate for every row of the outer query
+func (sq *SubQuery) isResultColumn(expr sqlparser.Expr) bool {
+	if !sq.IsArgument || !sq.evaluatedPerRow() {
+		return false
+	}
+	if sq.FilterType.NeedsListArg() {
+		// the value of an IN subquery is the result of the comparison it is used in
+		cmp, ok := expr.(*sqlparser.ComparisonExpr)
+		return ok && (cmp.Operator == sqlparser.InOp || cmp.Operator == sqlparser.NotInOp) && sq.isArgument(cmp.Right)
+	}
+	if exists, ok := expr.(*sqlparser.ExistsExpr); ok {
+		return sq.isArgument(exists.Subquery)
+	}
+	return sq.isArgument(expr)
+}
+
+// isArgument returns true if the expression stands for this subquery in the outer query
+func (sq *SubQuery) isArgument(expr sqlparser.Expr) bool {
+	switch expr := expr.(type) {
+	case *sqlparser.ColName:
+		return expr.Qualifier.IsEmpty() && expr.Name.String() == sq.ArgName
+	case *sqlparser.Argument:
+		return expr.Name == sq.ArgName || (sq.FilterType == opcode.PulloutExists && sq.HasValuesName != "" && expr.Name == sq.HasValuesName)
+	case sqlparser.ListArg:
+		return string(expr) == sq.ArgName
+	}
+	// the output columns of the query can still refer to the subquery itself
+	return sqlparser.Equals.Expr(expr, sq.originalSubquery)
+}
+
+// usesResult returns true if the expression uses the value of this subquery,
+// when it is computed in vtgate for every row of the outer query
+func (sq *SubQuery) usesResult(expr sqlparser.Expr) bool {
+	if !sq.IsArgument || !sq.evaluatedPerRow() {
+		return false
+	}
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if e, ok := node.(sqlparser.Expr); ok && sq.isResultColumn(e) {
+			found = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, expr)
+	return found
+}
+
+// fromOuterOffset maps an offset of the outer query to an offset of the output of this operator
+func (sq *SubQuery) fromOuterOffset(offset int) int {
+	if sq.ResultColumn >= 0 && offset >= sq.ResultColumn {
+		return offset + 1
+	}
+	return offset
 }
 
 // GetMergePredicates returns the predicates that we can use to try to merge this subquery with the outer query.
@@ -213,23 +342,100 @@ func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer Operator) Ope
 	if !sq.TopLevel && sq.correlated {
 		panic(subqueryNotAtTopErr)
 	}
-	if sq.correlated && sq.FilterType != opcode.PulloutExists {
+	if sq.correlated && sq.FilterType != opcode.PulloutExists && (len(sq.Predicates) == 0 || sq.unboundCorrelation) {
+		// we can only execute correlated subqueries when the values they need
+		// from the outer query can be sent to them as bind variables
 		panic(correlatedSubqueryErr)
 	}
+	if !sq.outerProvidesCorrelation(ctx, outer) {
+		panic(correlatedDerivedTableErr)
+	}
 	if sq.IsArgument {
-		if len(sq.GetMergePredicates()) > 0 {
-			// this means that we have a correlated subquery on our hands
-			panic(correlatedSubqueryErr)
+		if len(sq.Predicates) == 0 {
+			sq.SubqueryValueName = sq.ArgName
+			return outer
+		}
+		// a correlated subquery is executed for every row of the outer query,
+		// and its value is added to the outer row
+		if sq.FilterType.NeedsListArg() {
+			sq.settleInValue(ctx)
+		}
+		if sq.FilterType == opcode.PulloutExists {
+			sq.addLimit()
 		}
-		sq.SubqueryValueName = sq.ArgName
 		return outer
 	}
 	return sq.settleFilter(ctx, outer)
 }
 
+// settleInValue prepares a correlated IN or NOT IN subquery used as a value. The comparison using it
+// is evaluated in vtgate for every row of the outer query, and its result is the value added to the outer row.
+func (sq *SubQuery) settleInValue(ctx *plancontext.PlanningContext) {
+	var comparison *sqlparser.ComparisonExpr
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		cmp, ok := node.(*sqlparser.ComparisonExpr)
+		if ok && sqlparser.Equals.Expr(cmp.Right, sq.originalSubquery) {
+			comparison = cmp
+			return false, io.EOF
+		}
+		return true, nil
+	}, sq.Original)
+	if comparison == nil {
+		panic(vterrors.VT13001("could not find the comparison using the subquery: " + sqlparser.String(sq.Original)))
+	}
+
+	sq.HasValuesName = ctx.ReservedVars.ReserveHasValuesSubQuery()
+	hasValues := sqlparser.NewArgument(sq.HasValuesName)
+	cmp := &sqlparser.ComparisonExpr{
+		Operator: comparison.Operator,
+		Left:     comparison.Left,
+		Right:    sqlparser.NewListArg(sq.ArgName),
+	}
+	// like for filters, we have to check if we got any rows back from the subquery
+	if sq.FilterType == opcode.PulloutIn {
+		sq.FilterPredicates = []sqlparser.Expr{sqlparser.AndExpressions(hasValues, cmp)}
+	} else {
+		sq.FilterPredicates = []sqlparser.Expr{&sqlparser.OrExpr{Left: sqlparser.NewNotExpr(hasValues), Right: cmp}}
+	}
+}
+
+// outerProvidesCorrelation returns true if the outer operator can produce all the columns
+// that the predicates of a correlated subquery need. This is not the case when the subquery
+// is inside a derived table that doesn't project the columns it depends on.
+func (sq *SubQuery) outerProvidesCorrelation(ctx *plancontext.PlanningContext, outer Operator) bool {
+	available := visibleTableID(outer).Merge(TableID(sq.Subquery))
+	for _, pred := range sq.Predicates {
+		if !ctx.SemTable.RecursiveDeps(pred).IsSolvedBy(available) {
+			return false
+		}
+	}
+	return true
+}
+
+// visibleTableID works like TableID, but does not return the tables inside of derived tables
+func visibleTableID(op Operator) (result semantics.TableSet) {
+	switch op := op.(type) {
+	case *Projection:
+		if op.isDerived() {
+			return op.introducesTableID()
+		}
+	case *Horizon:
+		if op.IsDerived() {
+			return op.introducesTableID()
+		}
+	case tableIDIntroducer:
+		result = op.introducesTableID()
+	}
+	for _, input := range op.Inputs() {
+		result = result.Merge(visibleTableID(input))
+	}
+	return result
+}
+
 var (
-	correlatedSubqueryErr = vterrors.VT12001("correlated subquery is only supported for EXISTS")
-	subqueryNotAtTopErr   = vterrors.VT12001("unmergable subquery can not be inside complex expression")
+	correlatedSubqueryErr     = vterrors.VT12001("correlated subquery that cannot be evaluated for each row of the outer query")
+	correlatedDerivedTableErr = vterrors.VT12001("correlated subquery inside a derived table that does not project the columns it uses")
+	subqueryNotAtTopErr       = vterrors.VT12001("unmergable subquery can not be inside complex expression")
 )
 
 func (sq *SubQuery) addLimit() {
@@ -238,10 +444,7 @@ func (sq *SubQuery) addLimit() {
 }
 
 func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operator) Operator {
-	if len(sq.Predicates) > 0 {
-		if sq.FilterType != opcode.PulloutExists {
-			panic(correlatedSubqueryErr)
-		}
+	if len(sq.Predicates) > 0 && sq.FilterType == opcode.PulloutExists {
 		sq.addLimit()
 		return outer
 	}
@@ -308,6 +511,12 @@ func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operato
 		predicates = append(predicates, rhsPred)
 		sq.SubqueryValueName = sq.ArgName
 	}
+	if len(sq.Predicates) > 0 {
+		// correlated subqueries that are not EXISTS are executed for every row of the outer query,
+		// and the predicates using their results are evaluated in vtgate
+		sq.FilterPredicates = predicates
+		return outer
+	}
 	return newFilter(outer, predicates...)
 }
 
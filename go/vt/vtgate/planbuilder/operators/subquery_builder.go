@@ -17,6 +17,8 @@ limitations under the License.
 package operators
 
 import (
+	"io"
+
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -190,6 +192,9 @@ func createSubquery(
 	original = cloneASTAndSemState(ctx, original)
 	originalSq := cloneASTAndSemState(ctx, subq)
 	subqID := findTablesContained(ctx, subq.Select)
+	// the tables of a subquery nested in the WHERE clause of another subquery are part
+	// of the total scope of the enclosing one, but are not outer tables for it
+	outerID = outerID.Remove(subqID)
 	totalID := subqID.Merge(outerID)
 	sqc := &SubQueryBuilder{totalID: totalID, subqID: subqID, outerID: outerID}
 
@@ -197,21 +202,24 @@ func createSubquery(
 
 	subqDependencies := ctx.SemTable.RecursiveDeps(subq)
 	correlated := subqDependencies.KeepOnly(outerID).NotEmpty()
+	unboundCorrelation := correlated && sqc.hasUnboundCorrelation(ctx, subq.Select, predicates)
 
 	opInner := translateQueryToOp(ctx, subq.Select)
 
 	opInner = sqc.getRootOperator(opInner, nil)
 	return &SubQuery{
-		FilterType:       filterType,
-		Subquery:         opInner,
-		Predicates:       predicates,
-		Original:         original,
-		ArgName:          argName,
-		originalSubquery: originalSq,
-		IsArgument:       isArg,
-		TopLevel:         topLevel,
-		JoinColumns:      joinCols,
-		correlated:       correlated,
+		FilterType:         filterType,
+		Subquery:           opInner,
+		Predicates:         predicates,
+		Original:           original,
+		ArgName:            argName,
+		originalSubquery:   originalSq,
+		IsArgument:         isArg,
+		TopLevel:           topLevel,
+		JoinColumns:        joinCols,
+		correlated:         correlated,
+		unboundCorrelation: unboundCorrelation,
+		ResultColumn:       -1,
 	}
 }
 
@@ -232,6 +240,9 @@ func createSubqueryFromPath(
 	original = cloneASTAndSemState(ctx, original)
 	originalSq := sqlparser.GetNodeFromPath(original, path).(*sqlparser.Subquery)
 	subqID := findTablesContained(ctx, originalSq.Select)
+	// the tables of a subquery nested in the WHERE clause of another subquery are part
+	// of the total scope of the enclosing one, but are not outer tables for it
+	outerID = outerID.Remove(subqID)
 	totalID := subqID.Merge(outerID)
 	sqc := &SubQueryBuilder{totalID: totalID, subqID: subqID, outerID: outerID}
 
@@ -239,22 +250,84 @@ func createSubqueryFromPath(
 
 	subqDependencies := ctx.SemTable.RecursiveDeps(subq)
 	correlated := subqDependencies.KeepOnly(outerID).NotEmpty()
+	unboundCorrelation := correlated && sqc.hasUnboundCorrelation(ctx, subq.Select, predicates)
 
 	opInner := translateQueryToOp(ctx, subq.Select)
 
 	opInner = sqc.getRootOperator(opInner, nil)
 	return &SubQuery{
-		FilterType:       filterType,
-		Subquery:         opInner,
-		Predicates:       predicates,
-		Original:         original,
-		ArgName:          argName,
-		originalSubquery: originalSq,
-		IsArgument:       isArg,
-		TopLevel:         topLevel,
-		JoinColumns:      joinCols,
-		correlated:       correlated,
+		FilterType:         filterType,
+		Subquery:           opInner,
+		Predicates:         predicates,
+		Original:           original,
+		ArgName:            argName,
+		originalSubquery:   originalSq,
+		IsArgument:         isArg,
+		TopLevel:           topLevel,
+		JoinColumns:        joinCols,
+		correlated:         correlated,
+		unboundCorrelation: unboundCorrelation,
+		ResultColumn:       -1,
+	}
+}
+
+// hasUnboundCorrelation returns true if the subquery uses columns of the outer query that were not
+// replaced by arguments when extracting the predicates connecting it to the outer query, or if
+// these predicates aggregate columns of the outer query.
+// Must be called after inspectStatement, on a processor SQB.
+func (sqb *SubQueryBuilder) hasUnboundCorrelation(ctx *plancontext.PlanningContext, stmt sqlparser.TableStatement, predicates []sqlparser.Expr) bool {
+	for _, inner := range sqb.Inner {
+		if ctx.SemTable.RecursiveDeps(inner.Original).IsOverlapping(sqb.outerID) {
+			return true
+		}
+	}
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		col, ok := node.(*sqlparser.ColName)
+		if ok && ctx.SemTable.RecursiveDeps(col).IsOverlapping(sqb.outerID) {
+			found = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, stmt)
+	if found {
+		return true
+	}
+	for _, predicate := range predicates {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			switch node := node.(type) {
+			case sqlparser.AggrFunc:
+				found = ctx.SemTable.RecursiveDeps(node).IsOverlapping(sqb.outerID)
+			case *sqlparser.ColName:
+				// a reference to an alias of the outer query can't be fetched as a column
+				found = node.Qualifier.IsEmpty() &&
+					ctx.SemTable.RecursiveDeps(node).IsOverlapping(sqb.outerID) &&
+					isSelectAlias(ctx.Statement, node.Name)
+			}
+			if found {
+				return false, io.EOF
+			}
+			return true, nil
+		}, predicate)
+		if found {
+			return true
+		}
 	}
+	return false
+}
+
+// isSelectAlias returns true if any SELECT in the statement has a column with the given alias
+func isSelectAlias(stmt sqlparser.Statement, name sqlparser.IdentifierCI) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		ae, ok := node.(*sqlparser.AliasedExpr)
+		if ok && ae.As.Equal(name) {
+			found = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, stmt)
+	return found
 }
 
 // inspectWhere processes a WHERE or HAVING clause to extract subqueries and identify join predicates.
@@ -387,6 +460,11 @@ func (sqb *SubQueryBuilder) pullOutValueSubqueries(
 	replaceWithArg := func(cursor *sqlparser.Cursor, sq *sqlparser.Subquery, filterType opcode.PulloutOpcode) {
 		argName := ctx.ReservedVars.ReserveSubQuery()
 		sqInner := createSubquery(ctx, original, sq, outerID, original, argName, filterType, true)
+		if sqInner.correlated && usesSubqueryArguments(sq.Select, allSubqs) {
+			// subqueries nested in this one have already been pulled out next to it,
+			// so the values they produce can't be sent to it for every outer row
+			sqInner.unboundCorrelation = true
+		}
 		allSubqs = append(allSubqs, sqInner)
 		sqb.Inner = append(sqb.Inner, sqInner)
 		sqb.replaceSubqueryNode(cursor, argName, filterType, isDML)
@@ -412,6 +490,36 @@ func (sqb *SubQueryBuilder) pullOutValueSubqueries(
 	return expr, allSubqs
 }
 
+// usesSubqueryArguments returns true if the statement references the argument of any of the given subqueries
+func usesSubqueryArguments(stmt sqlparser.TableStatement, subqs []*SubQuery) bool {
+	if len(subqs) == 0 {
+		return false
+	}
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		var name string
+		switch node := node.(type) {
+		case *sqlparser.ColName:
+			if !node.Qualifier.IsEmpty() {
+				return true, nil
+			}
+			name = node.Name.String()
+		case *sqlparser.Argument:
+			name = node.Name
+		default:
+			return true, nil
+		}
+		for _, sq := range subqs {
+			if name == sq.ArgName {
+				found = true
+				return false, io.EOF
+			}
+		}
+		return true, nil
+	}, stmt)
+	return found
+}
+
 // replaceSubqueryNode replaces the current cursor node with the appropriate
 // argument placeholder for the given bind var name and opcode.
 func (sqb *SubQueryBuilder) replaceSubqueryNode(cursor *sqlparser.Cursor, argName string, filterType opcode.PulloutOpcode, isDML bool) {
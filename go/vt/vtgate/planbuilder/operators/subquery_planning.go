@@ -569,6 +569,9 @@ func mergingIsBlocked(subQuery *SubQuery, updOp *Update) bool {
 }
 
 func pushOrMerge(ctx *plancontext.PlanningContext, outer Operator, inner *SubQuery) (Operator, *ApplyResult) {
+	if inner.outerJoinPredicate {
+		return outer, NoRewrite
+	}
 	switch o := outer.(type) {
 	case *Route:
 		return tryMergeSubQuery(ctx, inner, o)
@@ -99,6 +99,12 @@ func createOperatorFromUpdate(ctx *plancontext.PlanningContext, updStmt *sqlpars
 	parentFks := ctx.SemTable.GetParentForeignKeysForTargets()
 	childFks := ctx.SemTable.GetChildForeignKeysForTargets()
 
+	// Changing the primary vindex columns of the rows means that they have to be moved
+	// to the shards that their new values map to.
+	if primaryVindexUpdated(ctx, updStmt) {
+		return createMoveRowsUpdateOp(ctx, updStmt)
+	}
+
 	// We check if dml with input plan is required. DML with input planning is generally
 	// slower, because it does a selection and then creates an update statement wherein we have to
 	// list all the primary key values.
@@ -204,6 +210,151 @@ func createUpdateWithInputOp(ctx *plancontext.PlanningContext, upd *sqlparser.Up
 	return op
 }
 
+// primaryVindexUpdated returns true if the update has a single sharded target table,
+// and changes the value of the columns of its primary vindex.
+func primaryVindexUpdated(ctx *plancontext.PlanningContext, updStmt *sqlparser.Update) bool {
+	if ctx.SemTable.DMLTargets.NumberOfTables() != 1 {
+		return false
+	}
+	ti, err := ctx.SemTable.TableInfoFor(ctx.SemTable.DMLTargets)
+	if err != nil {
+		panic(vterrors.VT13001(err.Error()))
+	}
+	vTbl := ti.GetVindexTable()
+	if vTbl == nil || !vTbl.Keyspace.Sharded || vTbl.Type != vindexes.TypeTable || len(vTbl.ColumnVindexes) == 0 {
+		return false
+	}
+	for _, ue := range updStmt.Exprs {
+		if slices.ContainsFunc(vTbl.ColumnVindexes[0].Columns, ue.Name.Name.Equal) {
+			return true
+		}
+	}
+	return false
+}
+
+// createMoveRowsUpdateOp plans an update that changes the primary vindex columns of the rows.
+// The rows, with the new values of the updated columns, are selected first. They are then deleted by primary key,
+// which also removes their entries from the owned lookup vindexes, and inserted again in the shards of their new keyspace ids.
+// This all happens in a single transaction.
+func createMoveRowsUpdateOp(ctx *plancontext.PlanningContext, upd *sqlparser.Update) Operator {
+	target := ctx.SemTable.DMLTargets
+	ti, err := ctx.SemTable.TableInfoFor(target)
+	if err != nil {
+		panic(vterrors.VT13001(err.Error()))
+	}
+	vTbl := ti.GetVindexTable()
+	tblName, err := ti.Name()
+	if err != nil {
+		panic(err)
+	}
+	errIfMoveRowsNotSupported(ctx, upd, vTbl)
+
+	updClone := ctx.SemTable.Clone(upd).(*sqlparser.Update)
+	selectStmt := &sqlparser.Select{
+		From:    updClone.TableExprs,
+		Where:   updClone.Where,
+		OrderBy: updClone.OrderBy,
+		Limit:   updClone.Limit,
+		Lock:    sqlparser.ForUpdateLock,
+	}
+
+	delOp := createDeleteOpWithTarget(ctx, target, upd.Ignore)
+	for _, col := range delOp.cols {
+		selectStmt.AddSelectExpr(aeWrap(col))
+	}
+
+	// every column of the row is sent to the insert as a bind variable,
+	// using the value of the update expression for the updated columns
+	ins := &sqlparser.Insert{
+		Action: sqlparser.InsertAct,
+		Table:  sqlparser.NewAliasedTableExpr(vTbl.GetTableName(), ""),
+	}
+	row := make(sqlparser.ValTuple, 0, len(vTbl.Columns))
+	var insList updList
+	for _, column := range vTbl.Columns {
+		var value sqlparser.Expr
+		for _, ue := range upd.Exprs {
+			if ue.Name.Name.Equal(column.Name) {
+				value = ue.Expr
+			}
+		}
+		colName := sqlparser.NewColNameWithQualifier(column.Name.String(), tblName)
+		if value == nil {
+			ctx.SemTable.Recursive[colName] = target
+			value = colName
+		}
+		selectStmt.AddSelectExpr(aeWrap(value))
+
+		bvName := ctx.ReservedVars.ReserveVariable(movedRowValue)
+		ins.Columns = append(ins.Columns, column.Name)
+		row = append(row, sqlparser.NewArgument(bvName))
+		insList = append(insList, updColumn{
+			updCol: colName,
+			jc: applyJoinColumn{
+				Original: value,
+				LHSExprs: []BindVarExpr{{Name: bvName, Expr: value}},
+			},
+		})
+	}
+	ins.Rows = sqlparser.Values{row}
+	insOp := createInsertOperator(ctx, ins, vTbl, &ShardedRouting{keyspace: vTbl.Keyspace, RouteOpCode: engine.Scatter})
+
+	var op Operator = &DMLWithInput{
+		DML:       []Operator{delOp.op, insOp},
+		Source:    createOperatorFromSelect(ctx, selectStmt),
+		cols:      [][]*sqlparser.ColName{delOp.cols, delOp.cols},
+		updList:   []updList{nil, insList},
+		MovesRows: true,
+	}
+
+	if upd.Comments != nil {
+		op = newLockAndComment(op, upd.Comments, sqlparser.NoLock)
+	}
+	return op
+}
+
+// errIfMoveRowsNotSupported fails the planning of an update changing the primary vindex columns,
+// when the rows can't be moved to their new shards by deleting and inserting them again.
+func errIfMoveRowsNotSupported(ctx *plancontext.PlanningContext, upd *sqlparser.Update, vTbl *vindexes.BaseTable) {
+	unsupported := func(reason string) error {
+		return vterrors.VT12001(fmt.Sprintf("you cannot UPDATE primary vindex columns %s; invalid update on vindex: %v", reason, vTbl.ColumnVindexes[0].Name))
+	}
+	switch {
+	case !vTbl.ColumnListAuthoritative:
+		panic(unsupported("of a table without an authoritative column list"))
+	case len(vTbl.PrimaryKey) == 0:
+		panic(vterrors.VT09015())
+	case bool(upd.Ignore):
+		panic(unsupported("with UPDATE IGNORE"))
+	case len(ctx.SemTable.GetParentForeignKeysForTargets()) > 0 || len(ctx.SemTable.GetChildForeignKeysForTargets()) > 0:
+		panic(unsupported("of a table with foreign keys"))
+	}
+
+	// MySQL evaluates the assignments from left to right, so an expression using a column
+	// updated before it sees the new value, while the selected value is the old one
+	var updated []*sqlparser.ColName
+	for _, ue := range upd.Exprs {
+		if slices.ContainsFunc(updated, func(col *sqlparser.ColName) bool { return col.Name.Equal(ue.Name.Name) }) {
+			panic(vterrors.VT03015(ue.Name.Name))
+		}
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			switch node := node.(type) {
+			case *sqlparser.Subquery:
+				panic(unsupported("using a subquery"))
+			case *sqlparser.ColName:
+				for _, col := range updated {
+					if col.Name.Equal(node.Name) {
+						panic(vterrors.VT12001(
+							fmt.Sprintf("'%s' column referenced in update expression '%s' is itself updated", sqlparser.String(col), sqlparser.String(ue.Expr))))
+					}
+				}
+			}
+			return true, nil
+		}, ue.Expr)
+		updated = append(updated, ue.Name)
+	}
+}
+
 func prepareUpdateExpressionList(ctx *plancontext.PlanningContext, upd *sqlparser.Update) map[semantics.TableSet]updList {
 	// Any update expression requiring column value from any other table is rewritten to take it as bindvar column.
 	// E.g. UPDATE t1 join t2 on t1.col = t2.col SET t1.col = t2.col + 1 where t2.col = 10;
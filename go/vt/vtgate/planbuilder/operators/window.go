@@ -17,13 +17,60 @@ limitations under the License.
 package operators
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
+// Window represents the evaluation of the window functions of a query.
+// When all the rows of every window partition come from a single shard, the window
+// functions are pushed down to MySQL with the rest of the query, and this operator
+// only passes its source through. Otherwise, they are evaluated in vtgate over the
+// rows of the source, sorted by the PARTITION BY and ORDER BY of the window.
 type Window struct {
 	unaryOperator
 	QP *QueryProjection
+
+	// InVTGate is set when the window functions are evaluated in vtgate.
+	// The fields below are only used in that case.
+	InVTGate bool
+
+	// Functions are the window functions. The source column at the offset of a function
+	// holds the argument of the function, and is replaced by its result.
+	Functions []WindowFunc
+
+	// Spec is the window specification shared by all the window functions, and PartitionBy
+	// and OrderBy are its PARTITION BY and ORDER BY expressions.
+	Spec        *sqlparser.WindowSpecification
+	PartitionBy []GroupBy
+	OrderBy     []GroupBy
+
+	DT *DerivedTable
+
+	// ResultColumns signals how many columns will be produced by this operator
+	// This is used to truncate the columns added to evaluate the window functions
+	ResultColumns int
+
+	// Truncate is set to true if the columns produced by this operator should be truncated if we added any additional columns
+	Truncate bool
+
+	offsetPlanned bool
+}
+
+// WindowFunc is a window function evaluated in vtgate
+type WindowFunc struct {
+	Original  *sqlparser.AliasedExpr
+	Func      sqlparser.WindowFunc
+	ColOffset int
 }
 
 func newWindow(source Operator, qp *QueryProjection) *Window {
@@ -33,39 +80,493 @@ func newWindow(source Operator, qp *QueryProjection) *Window {
 	}
 }
 
+// createWindow adds a Window on top of the projection of a SELECT horizon. The window functions are
+// pushed down when the source of the horizon guarantees that all the rows of a partition come from the
+// same shard. Otherwise, they are evaluated in vtgate, over the rows of the source sorted by the
+// partitions of the window.
+func createWindow(ctx *plancontext.PlanningContext, op Operator, qp *QueryProjection, horizon *Horizon) Operator {
+	if route, ok := horizon.src().(*Route); ok && (route.IsSingleShard() || canPushDownWindow(qp, route)) {
+		return newWindow(op, qp)
+	}
+
+	proj := findWindowProjection(op)
+	cols, ok := proj.Columns.(AliasedProjections)
+	if !ok {
+		panic(vterrors.VT09015())
+	}
+
+	w := &Window{
+		unaryOperator: newUnaryOp(op),
+		QP:            qp,
+		InVTGate:      true,
+		DT:            proj.DT,
+		Truncate:      horizon.Truncate,
+	}
+	// The projection below the window only produces the arguments of the window functions,
+	// so it cannot be used to rename the columns of the derived table anymore.
+	proj.DT = nil
+
+	for idx, pe := range cols {
+		wf, isWindowFunc := pe.ColExpr.(sqlparser.WindowFunc)
+		if !isWindowFunc || wf.GetOverClause() == nil {
+			if ctx.ContainsWindowFunc(pe.ColExpr) {
+				panic(vterrors.VT12001(fmt.Sprintf("window function inside an expression in a cross-shard query: %s", sqlparser.String(pe.ColExpr))))
+			}
+			continue
+		}
+		if pe.Info != nil {
+			panic(vterrors.VT12001(fmt.Sprintf("subquery in a window function in a cross-shard query: %s", sqlparser.String(pe.ColExpr))))
+		}
+		w.checkWindowFunc(ctx, wf)
+		cols[idx] = newProjExpr(aeWrap(windowFuncArgument(wf)))
+		w.Functions = append(w.Functions, WindowFunc{
+			Original:  pe.Original,
+			Func:      wf,
+			ColOffset: idx,
+		})
+	}
+
+	// window functions that are only used in the ORDER BY are added later, but they must share the same window
+	for _, order := range qp.OrderExprs {
+		if wf, ok := order.SimplifiedExpr.(sqlparser.WindowFunc); ok && wf.GetOverClause() != nil {
+			w.checkWindowFunc(ctx, wf)
+		}
+	}
+
+	if w.Spec == nil {
+		panic(vterrors.VT13001("window functions not found"))
+	}
+
+	var order []OrderBy
+	for _, by := range w.PartitionBy {
+		order = append(order, OrderBy{
+			Inner:          &sqlparser.Order{Expr: by.Inner, Direction: sqlparser.AscOrder},
+			SimplifiedExpr: by.Inner,
+		})
+	}
+	for _, by := range w.Spec.OrderClause {
+		order = append(order, OrderBy{
+			Inner:          by,
+			SimplifiedExpr: by.Expr,
+		})
+	}
+	if len(order) > 0 {
+		w.Source = newOrdering(op, order)
+	}
+	return w
+}
+
+// findWindowProjection returns the projection of the horizon under the window, and any HAVING filter on top of it.
+func findWindowProjection(op Operator) *Projection {
+	for {
+		switch src := op.(type) {
+		case *Projection:
+			return src
+		case *Filter:
+			op = src.Source
+		default:
+			panic(vterrors.VT12001("window functions with aggregation in a cross-shard query"))
+		}
+	}
+}
+
+// windowFuncArgument returns the expression the source has to produce for a window function evaluated in vtgate.
+// Functions without an argument get a NULL placeholder column.
+func windowFuncArgument(wf sqlparser.WindowFunc) sqlparser.Expr {
+	switch wf := wf.(type) {
+	case *sqlparser.FirstOrLastValueExpr:
+		return wf.Expr
+	case *sqlparser.NTHValueExpr:
+		return wf.Expr
+	case *sqlparser.LagLeadExpr:
+		return wf.Expr
+	case *sqlparser.Count:
+		return wf.Args[0]
+	case *sqlparser.Sum:
+		return wf.Arg
+	case *sqlparser.Avg:
+		return wf.Arg
+	case *sqlparser.Min:
+		return wf.Arg
+	case *sqlparser.Max:
+		return wf.Arg
+	}
+	return &sqlparser.NullVal{}
+}
+
+// checkWindowFunc fails if the window function cannot be evaluated in vtgate, or if it does not use the same
+// window as the other window functions. The first window function sets the window.
+func (w *Window) checkWindowFunc(ctx *plancontext.PlanningContext, wf sqlparser.WindowFunc) {
+	unsupported := func(what string) {
+		panic(vterrors.VT12001(fmt.Sprintf("%s in a cross-shard query: %s", what, sqlparser.String(wf))))
+	}
+	ignoreNulls := func(clause *sqlparser.NullTreatmentClause) {
+		if clause != nil && clause.Type == sqlparser.IgnoreNullsType {
+			unsupported("IGNORE NULLS")
+		}
+	}
+
+	switch wf := wf.(type) {
+	case *sqlparser.ArgumentLessWindowExpr, *sqlparser.CountStar, *sqlparser.Min, *sqlparser.Max:
+	case *sqlparser.NtileExpr:
+		if n, ok := WindowFuncLiteral(wf.N); !ok || n == 0 {
+			unsupported("NTILE with a number of buckets that is not a positive integer literal")
+		}
+	case *sqlparser.FirstOrLastValueExpr:
+		ignoreNulls(wf.NullTreatmentClause)
+	case *sqlparser.NTHValueExpr:
+		if n, ok := WindowFuncLiteral(wf.N); !ok || n == 0 {
+			unsupported("NTH_VALUE with a position that is not a positive integer literal")
+		}
+		if wf.FromFirstLastClause != nil && wf.FromFirstLastClause.Type == sqlparser.FromLastType {
+			unsupported("FROM LAST")
+		}
+		ignoreNulls(wf.NullTreatmentClause)
+	case *sqlparser.LagLeadExpr:
+		if _, ok := WindowFuncLiteral(wf.N); wf.N != nil && !ok {
+			unsupported("LAG or LEAD with an offset that is not an integer literal")
+		}
+		if wf.Default != nil && !sqlparser.IsConstant(wf.Default) {
+			unsupported("LAG or LEAD with a default value that is not a constant")
+		}
+		ignoreNulls(wf.NullTreatmentClause)
+	case *sqlparser.Count:
+		if wf.Distinct || len(wf.Args) != 1 {
+			unsupported("COUNT with DISTINCT or several arguments")
+		}
+	case *sqlparser.Sum:
+		if wf.Distinct {
+			unsupported("SUM with DISTINCT")
+		}
+	case *sqlparser.Avg:
+		if wf.Distinct {
+			unsupported("AVG with DISTINCT")
+		}
+	default:
+		unsupported("window function " + strings.ToUpper(wf.WindowFuncName()))
+	}
+
+	over := wf.GetOverClause()
+	if over.WindowName.NotEmpty() || over.WindowSpec == nil || over.WindowSpec.Name.NotEmpty() {
+		unsupported("named window")
+	}
+	if _, err := evalengine.TranslateWindowFrame(over.WindowSpec.FrameClause); err != nil {
+		panic(err)
+	}
+
+	if w.Spec == nil {
+		w.Spec = over.WindowSpec
+		for _, expr := range over.WindowSpec.PartitionClause {
+			w.PartitionBy = append(w.PartitionBy, NewGroupBy(expr))
+		}
+		for _, order := range over.WindowSpec.OrderClause {
+			w.OrderBy = append(w.OrderBy, NewGroupBy(order.Expr))
+		}
+		return
+	}
+
+	sameWindow := slices.EqualFunc(w.Spec.PartitionClause, over.WindowSpec.PartitionClause, ctx.SemTable.EqualsExprWithDeps) &&
+		slices.EqualFunc(w.Spec.OrderClause, over.WindowSpec.OrderClause, func(a, b *sqlparser.Order) bool {
+			return a.Direction == b.Direction && ctx.SemTable.EqualsExprWithDeps(a.Expr, b.Expr)
+		})
+	if !sameWindow {
+		unsupported("window functions with different PARTITION BY or ORDER BY")
+	}
+}
+
+// WindowFuncLiteral returns the value of a non-negative integer literal argument of a window function,
+// such as the offset of LAG() or the number of buckets of NTILE().
+func WindowFuncLiteral(expr sqlparser.Expr) (int64, bool) {
+	lit, ok := expr.(*sqlparser.Literal)
+	if !ok || lit.Type != sqlparser.IntVal {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(lit.Val, 10, 64)
+	return n, err == nil && n >= 0
+}
+
 func (w *Window) Clone(inputs []Operator) Operator {
-	return newWindow(inputs[0], w.QP)
+	kopy := *w
+	kopy.Source = inputs[0]
+	kopy.Functions = slices.Clone(w.Functions)
+	kopy.PartitionBy = slices.Clone(w.PartitionBy)
+	kopy.OrderBy = slices.Clone(w.OrderBy)
+	return &kopy
 }
 
 func (w *Window) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	if w.InVTGate {
+		// filtering the rows before the window functions are evaluated would change their results
+		return newFilter(w, expr)
+	}
 	w.Source = w.Source.AddPredicate(ctx, expr)
 	return w
 }
 
-func (w *Window) AddColumn(ctx *plancontext.PlanningContext, reuseExisting bool, addToGroupBy bool, expr *sqlparser.AliasedExpr) int {
-	return w.Source.AddColumn(ctx, reuseExisting, addToGroupBy, expr)
+func (w *Window) AddColumn(ctx *plancontext.PlanningContext, reuseExisting bool, addToGroupBy bool, expr *sqlparser.AliasedExpr) (offset int) {
+	if !w.InVTGate {
+		return w.Source.AddColumn(ctx, reuseExisting, addToGroupBy, expr)
+	}
+	defer func() {
+		w.checkOffset(offset)
+	}()
+
+	ae := &sqlparser.AliasedExpr{
+		Expr: w.DT.RewriteExpression(ctx, expr.Expr),
+		As:   expr.As,
+	}
+	if reuseExisting {
+		if offset := w.FindCol(ctx, ae.Expr, false); offset >= 0 {
+			return offset
+		}
+	}
+
+	wf, isWindowFunc := ae.Expr.(sqlparser.WindowFunc)
+	if !isWindowFunc || wf.GetOverClause() == nil {
+		// the column can not be shared with a window function argument, since the argument is replaced by the result
+		return w.Source.AddColumn(ctx, false, addToGroupBy, ae)
+	}
+
+	w.checkWindowFunc(ctx, wf)
+	offset = w.Source.AddColumn(ctx, false, false, aeWrap(windowFuncArgument(wf)))
+	w.Functions = append(w.Functions, WindowFunc{
+		Original:  ae,
+		Func:      wf,
+		ColOffset: offset,
+	})
+	return offset
 }
 
 func (w *Window) AddWSColumn(ctx *plancontext.PlanningContext, offset int, underRoute bool) int {
+	if w.InVTGate && w.isFunctionOffset(offset) {
+		panic(vterrors.VT12001("comparing the results of window functions evaluated in vtgate that need a weight_string"))
+	}
 	return w.Source.AddWSColumn(ctx, offset, underRoute)
 }
 
 func (w *Window) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
-	return w.Source.FindCol(ctx, expr, underRoute)
+	if !w.InVTGate {
+		return w.Source.FindCol(ctx, expr, underRoute)
+	}
+
+	expr = w.DT.RewriteExpression(ctx, expr)
+	for _, wf := range w.Functions {
+		if ctx.SemTable.EqualsExprWithDeps(wf.Original.Expr, expr) {
+			w.checkOffset(wf.ColOffset)
+			return wf.ColOffset
+		}
+	}
+	offset := w.Source.FindCol(ctx, expr, underRoute)
+	if w.isFunctionOffset(offset) {
+		return -1
+	}
+	w.checkOffset(offset)
+	return offset
+}
+
+func (w *Window) checkOffset(offset int) {
+	// if the offset is greater than the number of columns we expect to produce, we need to update the number of columns
+	// this is to make sure that the column is not truncated in the final result
+	if w.ResultColumns > 0 && w.ResultColumns <= offset {
+		w.ResultColumns = offset + 1
+	}
+}
+
+func (w *Window) isFunctionOffset(offset int) bool {
+	return slices.ContainsFunc(w.Functions, func(wf WindowFunc) bool {
+		return wf.ColOffset == offset
+	})
 }
 
 func (w *Window) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
-	return w.Source.GetColumns(ctx)
+	columns := w.Source.GetColumns(ctx)
+	if !w.InVTGate {
+		return columns
+	}
+	columns = slices.Clone(columns)
+	for _, wf := range w.Functions {
+		columns[wf.ColOffset] = wf.Original
+	}
+	return truncate(w, columns)
 }
 
 func (w *Window) GetSelectExprs(ctx *plancontext.PlanningContext) []sqlparser.SelectExpr {
-	return w.Source.GetSelectExprs(ctx)
+	if !w.InVTGate {
+		return w.Source.GetSelectExprs(ctx)
+	}
+	return transformColumnsToSelectExprs(ctx, w)
 }
 
 func (w *Window) ShortDescription() string {
-	return "Window"
+	if !w.InVTGate {
+		return "Window"
+	}
+	return strings.Join(slice.Map(w.Functions, func(wf WindowFunc) string {
+		return sqlparser.String(wf.Original)
+	}), ", ")
+}
+
+func (w *Window) setTruncateColumnCount(offset int) {
+	w.ResultColumns = offset
+}
+
+func (w *Window) getTruncateColumnCount() int {
+	return w.ResultColumns
 }
 
 func (w *Window) GetOrdering(ctx *plancontext.PlanningContext) []OrderBy {
 	return w.Source.GetOrdering(ctx)
 }
+
+func (w *Window) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	if !w.InVTGate || w.offsetPlanned {
+		return nil
+	}
+	w.offsetPlanned = true
+
+	if w.Truncate && w.ResultColumns == 0 {
+		// the columns added for the keys of the window are not part of the output
+		w.ResultColumns = len(w.Source.GetColumns(ctx))
+	}
+
+	planKeys := func(keys []GroupBy) {
+		for i, key := range keys {
+			offset := w.Source.AddColumn(ctx, true, false, aeWrap(key.Inner))
+			keys[i].ColOffset = offset
+			if ctx.NeedsWeightString(key.Inner) {
+				keys[i].WSOffset = w.Source.AddColumn(ctx, true, false, aeWrap(weightStringFor(key.Inner)))
+			}
+		}
+	}
+	planKeys(w.PartitionBy)
+	planKeys(w.OrderBy)
+	return nil
+}
+
+func (w *Window) introducesTableID() semantics.TableSet {
+	return w.DT.introducesTableID()
+}
+
+type windowTableInfo struct {
+	vTable *vindexes.BaseTable
+	alias  sqlparser.IdentifierCS
+}
+
+// canPushDownWindow checks if all window functions partition by a unique vindex.
+// Returns false if PARTITION BY is missing or covers non-vindex columns.
+// Examples:
+//
+//	OK: SELECT ... FROM user PARTITION BY id (id is primary vindex, same-shard partitions)
+//	NO: SELECT ... FROM user PARTITION BY region (region scattered across shards)
+func canPushDownWindow(qp *QueryProjection, route *Route) bool {
+	// Collect tables with their aliases
+	var tables []windowTableInfo
+	_ = Visit(route, func(o Operator) error {
+		if t, ok := o.(*Table); ok && t.VTable != nil {
+			alias := t.QTable.Alias.As
+			if alias.IsEmpty() {
+				alias = sqlparser.NewIdentifierCS(t.QTable.Table.Name.String())
+			}
+			tables = append(tables, windowTableInfo{vTable: t.VTable, alias: alias})
+		}
+		return nil
+	})
+
+	// Collect window functions from SELECT and ORDER BY expressions
+	var windowFuncs []sqlparser.WindowFunc
+	collect := func(node sqlparser.SQLNode) {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if wf, ok := node.(sqlparser.WindowFunc); ok && wf.GetOverClause() != nil {
+				windowFuncs = append(windowFuncs, wf)
+			}
+			return true, nil
+		}, node)
+	}
+	for _, expr := range qp.SelectExprs {
+		collect(expr.Col)
+	}
+	for _, order := range qp.OrderExprs {
+		collect(order.Inner)
+	}
+
+	// Validate each window function partitions by unique vindex
+	for _, wf := range windowFuncs {
+		if !isPartitionedByUniqueVindex(wf, tables) {
+			return false
+		}
+	}
+
+	return true
+}
+func isPartitionedByUniqueVindex(wf sqlparser.WindowFunc, tables []windowTableInfo) bool {
+	overClause := wf.GetOverClause()
+	if overClause == nil || overClause.WindowSpec == nil || len(overClause.WindowSpec.PartitionClause) == 0 {
+		return false
+	}
+
+	partitionBy := overClause.WindowSpec.PartitionClause
+
+	for _, table := range tables {
+		if len(table.vTable.ColumnVindexes) == 0 {
+			continue
+		}
+
+		// Pre-build column lookup map for column validation
+		var columnSet map[string]bool
+		if table.vTable.ColumnListAuthoritative {
+			columnSet = make(map[string]bool, len(table.vTable.Columns))
+			for _, col := range table.vTable.Columns {
+				columnSet[col.Name.Lowered()] = true
+			}
+		}
+
+		// Build set of partition columns matching this table - O(p) where p = partition columns
+		coveredCols := make(map[string]bool)
+		for _, pExpr := range partitionBy {
+			colName, ok := pExpr.(*sqlparser.ColName)
+			if !ok {
+				continue
+			}
+
+			// Skip if qualified to different table
+			if !colName.Qualifier.IsEmpty() && colName.Qualifier.Name.String() != table.alias.String() {
+				continue
+			}
+
+			// Validate column exists in schema if authoritative - O(1) lookup instead of O(c)
+			if columnSet != nil {
+				if !columnSet[colName.Name.Lowered()] {
+					if !colName.Qualifier.IsEmpty() || len(tables) == 1 {
+						return false
+					}
+					continue
+				}
+			}
+
+			coveredCols[colName.Name.Lowered()] = true
+		}
+
+		checkVindex := func(vindex *vindexes.ColumnVindex) bool {
+			for _, vCol := range vindex.Columns {
+				if !coveredCols[vCol.Lowered()] {
+					return false
+				}
+			}
+			return true
+		}
+
+		// Check primary vindex (determines shard routing)
+		primaryVindex := table.vTable.ColumnVindexes[0]
+		if checkVindex(primaryVindex) {
+			return true
+		}
+
+		// Check unique vindexes (each partition has ≤1 row)
+		for _, vindex := range table.vTable.ColumnVindexes[1:] {
+			if vindex.IsUnique() && checkVindex(vindex) {
+				return true
+			}
+		}
+	}
+	return false
+}
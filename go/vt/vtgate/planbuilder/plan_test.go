@@ -83,6 +83,7 @@ func (s *planTestSuite) TestPlan() {
 
 	s.addPKs(vschema, "user", []string{"user", "music"})
 	s.addPKsProvided(vschema, "user", []string{"user_extra"}, []string{"id", "user_id"})
+	s.addPKsProvided(vschema, "user", []string{"user_profile"}, []string{"user_id"})
 	s.addPKsProvided(vschema, "ordering", []string{"order"}, []string{"oid", "region_id"})
 	s.addPKsProvided(vschema, "ordering", []string{"order_event"}, []string{"oid", "ename"})
 	s.addPKsProvided(vschema, "main", []string{"source_of_ref"}, []string{"id"})
@@ -313,6 +314,7 @@ func (s *planTestSuite) TestOne() {
 	s.addPKs(vschema, "user", []string{"user", "music"})
 	s.addPKs(vschema, "main", []string{"unsharded"})
 	s.addPKsProvided(vschema, "user", []string{"user_extra"}, []string{"id", "user_id"})
+	s.addPKsProvided(vschema, "user", []string{"user_profile"}, []string{"user_id"})
 	s.addPKsProvided(vschema, "ordering", []string{"order"}, []string{"oid", "region_id"})
 	s.addPKsProvided(vschema, "ordering", []string{"order_event"}, []string{"oid", "ename"})
 	s.addPKsProvided(vschema, "main", []string{"source_of_ref"}, []string{"id"})
@@ -84,6 +84,11 @@ func (r *rewriter) rewriteHavingClause(node *sqlparser.Select) {
 	if node.Having == nil {
 		return
 	}
+	if node.GroupBy != nil && node.GroupBy.WithRollup {
+		// the HAVING clause also filters the super-aggregate rows,
+		// so none of the predicates can be evaluated before the rollup
+		return
+	}
 
 	// for each expression in the having clause, we check if it contains aggregation.
 	// if it does, we keep the expression in the having clause ; and if it does not
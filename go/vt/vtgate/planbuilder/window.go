@@ -18,11 +18,11 @@ package planbuilder
 
 import (
 	"vitess.io/vitess/go/vt/sqlparser"
-	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
-	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
 func transformWindow(ctx *plancontext.PlanningContext, op *operators.Window) (engine.Primitive, error) {
@@ -31,158 +31,87 @@ func transformWindow(ctx *plancontext.PlanningContext, op *operators.Window) (en
 		return nil, err
 	}
 
-	// Multi-source primitives (Join, HashJoin, ValuesJoin, SemiJoin, Concatenate, Sequential)
-	// cannot guarantee partitions stay on single shard
-	switch prim.(type) {
-	case *engine.Join, *engine.HashJoin, *engine.ValuesJoin, *engine.SemiJoin, *engine.Concatenate, *engine.Sequential:
-		return nil, vterrors.VT12001("window functions are only supported for single-shard queries")
+	if !op.InVTGate {
+		// All the rows of a partition come from the same shard, so the window
+		// functions have been pushed down with the rest of the query
+		return prim, nil
 	}
 
-	// Multi-shard routes OK only if partitioned by unique vindex (guarantees same-shard partition rows)
-	// E.g., PARTITION BY id (primary vindex) OK; PARTITION BY region NOT OK
-	if route, ok := prim.(*engine.Route); ok && !isSingleShardPrimitive(route) {
-		if routeOp, ok := op.Source.(*operators.Route); ok {
-			if canPushDownWindow(op, routeOp) {
-				// Partition is based on unique vindex - safe to execute on multi-shard route
-				return prim, nil
-			}
+	window := &engine.Window{
+		PartitionBy:         windowKeys(ctx, op.PartitionBy),
+		OrderBy:             windowKeys(ctx, op.OrderBy),
+		TruncateColumnCount: op.ResultColumns,
+		Input:               prim,
+	}
+	for _, wf := range op.Functions {
+		params, err := windowFunctionParams(ctx, wf)
+		if err != nil {
+			return nil, err
 		}
-		return nil, vterrors.VT12001("window functions are only supported for single-shard queries")
+		window.Functions = append(window.Functions, params)
 	}
-
-	return prim, nil
+	return window, nil
 }
 
-func isSingleShardPrimitive(route *engine.Route) bool {
-	if route == nil || route.RoutingParameters == nil {
-		return false
+func windowKeys(ctx *plancontext.PlanningContext, keys []operators.GroupBy) []*engine.GroupByParams {
+	var out []*engine.GroupByParams
+	for _, key := range keys {
+		typ, _ := ctx.TypeForExpr(key.Inner)
+		out = append(out, &engine.GroupByParams{
+			KeyCol:          key.ColOffset,
+			WeightStringCol: key.WSOffset,
+			Expr:            key.Inner,
+			Type:            typ,
+			CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+		})
 	}
-	return route.Opcode.IsSingleShard()
-}
-
-type windowTableInfo struct {
-	vTable *vindexes.BaseTable
-	alias  sqlparser.IdentifierCS
+	return out
 }
 
-// canPushDownWindow checks if a window function partitions by a unique vindex.
-// Returns false if PARTITION BY is missing or covers non-vindex columns.
-// Examples:
-//
-//	OK: SELECT ... FROM user WHERE id=1 PARTITION BY id (single shard)
-//	OK: SELECT ... FROM user PARTITION BY id (id is primary vindex, same-shard partitions)
-//	NO: SELECT ... FROM user PARTITION BY region (region scattered across shards)
-func canPushDownWindow(op *operators.Window, route *operators.Route) bool {
-	// Collect tables with their aliases
-	var tables []windowTableInfo
-	_ = operators.Visit(route, func(o operators.Operator) error {
-		if t, ok := o.(*operators.Table); ok && t.VTable != nil {
-			alias := t.QTable.Alias.As
-			if alias.IsEmpty() {
-				alias = sqlparser.NewIdentifierCS(t.QTable.Table.Name.String())
-			}
-			tables = append(tables, windowTableInfo{vTable: t.VTable, alias: alias})
-		}
-		return nil
-	})
-
-	// Collect window functions from SELECT expressions
-	var windowFuncs []sqlparser.WindowFunc
-	for _, expr := range op.QP.SelectExprs {
-		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
-			if wf, ok := node.(sqlparser.WindowFunc); ok {
-				windowFuncs = append(windowFuncs, wf)
-			}
-			return true, nil
-		}, expr.Col)
+func windowFunctionParams(ctx *plancontext.PlanningContext, wf operators.WindowFunc) (*engine.WindowFunctionParams, error) {
+	frame, err := evalengine.TranslateWindowFrame(wf.Func.GetOverClause().WindowSpec.FrameClause)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(windowFuncs) == 0 {
-		return true
+	params := &engine.WindowFunctionParams{
+		Col:          wf.ColOffset,
+		Frame:        frame,
+		Alias:        wf.Original.ColumnName(),
+		CollationEnv: ctx.VSchema.Environment().CollationEnv(),
 	}
-
-	// Validate each window function partitions by unique vindex
-	for _, wf := range windowFuncs {
-		if !isPartitionedByUniqueVindex(wf, tables) {
-			return false
-		}
+	if arg := wf.Func.GetArg(); arg != nil {
+		params.Type, _ = ctx.TypeForExpr(arg)
 	}
 
-	return true
-}
-
-// isPartitionedByUniqueVindex checks if a window function's PARTITION BY covers:
-//  1. Primary vindex columns (ensures same-shard partitions), or
-//  2. Unique vindex columns (each partition has ≤1 row, trivially single-shard)
-func isPartitionedByUniqueVindex(wf sqlparser.WindowFunc, tables []windowTableInfo) bool {
-	overClause := wf.GetOverClause()
-	if overClause == nil || overClause.WindowSpec == nil || len(overClause.WindowSpec.PartitionClause) == 0 {
-		return false
+	if _, isCountStar := wf.Func.(*sqlparser.CountStar); isCountStar {
+		params.AggrOpcode = opcode.AggregateCountStar
+	} else if code, ok := opcode.SupportedWindowFunctions[wf.Func.WindowFuncName()]; ok {
+		params.Opcode = code
+	} else {
+		params.AggrOpcode = opcode.SupportedAggregates[wf.Func.WindowFuncName()]
 	}
 
-	partitionBy := overClause.WindowSpec.PartitionClause
-
-	for _, table := range tables {
-		if len(table.vTable.ColumnVindexes) == 0 {
-			continue
+	switch f := wf.Func.(type) {
+	case *sqlparser.NtileExpr:
+		params.N, _ = operators.WindowFuncLiteral(f.N)
+	case *sqlparser.NTHValueExpr:
+		params.N, _ = operators.WindowFuncLiteral(f.N)
+	case *sqlparser.LagLeadExpr:
+		params.N = 1
+		if f.N != nil {
+			params.N, _ = operators.WindowFuncLiteral(f.N)
 		}
-
-		// Pre-build column lookup map for column validation
-		var columnSet map[string]bool
-		if table.vTable.ColumnListAuthoritative {
-			columnSet = make(map[string]bool, len(table.vTable.Columns))
-			for _, col := range table.vTable.Columns {
-				columnSet[col.Name.Lowered()] = true
-			}
-		}
-
-		// Build set of partition columns matching this table - O(p) where p = partition columns
-		coveredCols := make(map[string]bool)
-		for _, pExpr := range partitionBy {
-			colName, ok := pExpr.(*sqlparser.ColName)
-			if !ok {
-				continue
-			}
-
-			// Skip if qualified to different table
-			if !colName.Qualifier.IsEmpty() && colName.Qualifier.Name.String() != table.alias.String() {
-				continue
-			}
-
-			// Validate column exists in schema if authoritative - O(1) lookup instead of O(c)
-			if columnSet != nil {
-				if !columnSet[colName.Name.Lowered()] {
-					if !colName.Qualifier.IsEmpty() || len(tables) == 1 {
-						return false
-					}
-					continue
-				}
+		if f.Default != nil {
+			cfg := &evalengine.Config{
+				Collation:   ctx.VSchema.ConnCollation(),
+				Environment: ctx.VSchema.Environment(),
 			}
-
-			coveredCols[colName.Name.Lowered()] = true
-		}
-
-		checkVindex := func(vindex *vindexes.ColumnVindex) bool {
-			for _, vCol := range vindex.Columns {
-				if !coveredCols[vCol.Lowered()] {
-					return false
-				}
-			}
-			return true
-		}
-
-		// Check primary vindex (determines shard routing)
-		primaryVindex := table.vTable.ColumnVindexes[0]
-		if checkVindex(primaryVindex) {
-			return true
-		}
-
-		// Check unique vindexes (each partition has ≤1 row)
-		for _, vindex := range table.vTable.ColumnVindexes[1:] {
-			if vindex.IsUnique() && checkVindex(vindex) {
-				return true
+			params.Default, err = evalengine.Translate(f.Default, cfg)
+			if err != nil {
+				return nil, err
 			}
 		}
 	}
-	return false
+	return params, nil
 }
@@ -26,6 +26,7 @@ import (
 	"vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 )
@@ -172,13 +173,13 @@ func TestWindowFunctions(t *testing.T) {
 				_, err = transformToPrimitive(ctx, op)
 				require.NoError(t, err, "Should transform to primitive successfully")
 			} else {
-				// For scatter, Window operator should be present initially
-				assert.NotNil(t, windowOp, "Window operator should be present for scatter query")
+				// For scatter, the window functions should be evaluated in vtgate
+				require.NotNil(t, windowOp, "Window operator should be present for scatter query")
+				assert.True(t, windowOp.InVTGate, "Window functions should be evaluated in vtgate")
 
-				// But transformation should fail
-				_, err = transformToPrimitive(ctx, op)
-				require.Error(t, err)
-				require.Contains(t, err.Error(), "window functions are only supported for single-shard queries")
+				prim, err := transformToPrimitive(ctx, op)
+				require.NoError(t, err)
+				assert.IsType(t, &engine.Window{}, prim)
 			}
 		})
 	}
@@ -0,0 +1,439 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"encoding/binary"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/cache/theine"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vthash"
+)
+
+type (
+	ResultCacheKey = theine.HashKey256
+
+	// ResultCache keeps the results of SELECT queries that opted into caching, either through the
+	// RESULT_CACHE_TTL_MS query comment directive or through the result_cache_ttl_ms setting of
+	// the tables in the vschema. It is a best-effort cache, local to this VTGate.
+	//
+	// Cached results are invalidated per table: every table has a generation that is bumped when
+	// the table is written to through this VTGate, and a cached result is only served while the
+	// generations of all the tables it read from are unchanged. Writes done inside a transaction
+	// bump the generations again once the transaction is over. A new vschema, which is also what
+	// the schema tracker produces when the schema of a table changes, drops all cached results.
+	// Writes that don't go through this VTGate, i.e. writes through other VTGates, by vreplication
+	// or directly on the tablets, are not seen: results can be stale until they expire, which is
+	// why their TTL is bounded by --result-cache-max-ttl.
+	//
+	// Results are cached per caller, so that a user is never served a result read with the
+	// table ACLs of another user.
+	ResultCache struct {
+		results *theine.Store[ResultCacheKey, *cachedResult]
+		epoch   atomic.Uint32
+
+		mu          sync.Mutex
+		generation  uint64
+		generations map[string]uint64
+		// pendingWrites holds the tables that were written to by open transactions, by session UUID
+		pendingWrites map[string][]string
+		pendingCount  atomic.Int64
+
+		hits          atomic.Int64
+		misses        atomic.Int64
+		invalidations atomic.Int64
+	}
+
+	cachedResult struct {
+		query       string
+		result      *sqltypes.Result
+		tables      []string
+		generations []uint64
+		expires     time.Time
+	}
+
+	// resultCacheLookup holds what is needed to store the result of a query after executing it
+	resultCacheLookup struct {
+		key         ResultCacheKey
+		query       string
+		ttl         time.Duration
+		tables      []string
+		generations []uint64
+	}
+)
+
+// NewResultCache creates a ResultCache that holds at most maxMemory bytes of results.
+func NewResultCache(maxMemory int64) *ResultCache {
+	return &ResultCache{
+		// queries opted into caching explicitly, so we don't wait for them to be seen twice
+		results:       theine.NewStore[ResultCacheKey, *cachedResult](maxMemory, false),
+		generations:   make(map[string]uint64),
+		pendingWrites: make(map[string][]string),
+	}
+}
+
+func (cr *cachedResult) CachedSize(alloc bool) int64 {
+	size := int64(len(cr.query)) + cr.result.CachedSize(true)
+	for _, table := range cr.tables {
+		size += int64(len(table)) + 16
+	}
+	size += int64(len(cr.generations)) * 8
+	if alloc {
+		size += int64(112)
+	}
+	return size
+}
+
+// lookup returns the cached result for the query if there is one, and otherwise a resultCacheLookup
+// to use to cache the result once the query has run. Both are nil if the result of the query cannot be
+// cached in this session.
+func (rc *ResultCache) lookup(
+	ctx context.Context,
+	plan *engine.Plan,
+	vcursor *econtext.VCursorImpl,
+	safeSession *econtext.SafeSession,
+	bindVars map[string]*querypb.BindVariable,
+) (*sqltypes.Result, *resultCacheLookup) {
+	if rc == nil || plan.ResultCacheTTL <= 0 || plan.QueryType != sqlparser.StmtSelect {
+		return nil, nil
+	}
+	// transactions must see their own writes, and reserved connections can have temporary
+	// tables or locks that are specific to the session.
+	if safeSession.InTransaction() || safeSession.InReservedConn() {
+		return nil, nil
+	}
+
+	key := resultCacheKey(ctx, plan, vcursor, safeSession, bindVars)
+	if entry, ok := rc.results.Get(key, rc.epoch.Load()); ok {
+		if time.Now().Before(entry.expires) && rc.isCurrent(entry.tables, entry.generations) {
+			rc.hits.Add(1)
+			return entry.result.Copy(), nil
+		}
+		rc.results.Delete(key)
+	}
+	rc.misses.Add(1)
+
+	return nil, &resultCacheLookup{
+		key:         key,
+		query:       plan.Original,
+		ttl:         plan.ResultCacheTTL,
+		tables:      plan.TablesUsed,
+		generations: rc.currentGenerations(plan.TablesUsed),
+	}
+}
+
+// store caches the result of a query that was looked up earlier. The result is not cached if one
+// of the tables it read from was written to since the lookup.
+func (rc *ResultCache) store(lookup *resultCacheLookup, qr *sqltypes.Result) {
+	if lookup == nil || !rc.isCurrent(lookup.tables, lookup.generations) {
+		return
+	}
+	rc.results.Set(lookup.key, &cachedResult{
+		query:       lookup.query,
+		result:      qr.Copy(),
+		tables:      lookup.tables,
+		generations: lookup.generations,
+		expires:     time.Now().Add(lookup.ttl),
+	}, 0, rc.epoch.Load())
+}
+
+// afterExecute invalidates the results cached for the tables that were written to by the plan.
+// When the session is inside a transaction, the tables are invalidated again once the
+// transaction is over, so that results read while the transaction was open are not kept.
+func (rc *ResultCache) afterExecute(plan *engine.Plan, safeSession *econtext.SafeSession) {
+	if rc == nil {
+		return
+	}
+	var written []string
+	if plan != nil && !plan.QueryType.IsReadStatement() {
+		written = plan.TablesUsed
+	}
+	if len(written) == 0 && rc.pendingCount.Load() == 0 {
+		return
+	}
+
+	sessionUUID := safeSession.GetSessionUUID()
+	inTransaction := safeSession.InTransaction()
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.invalidateLocked(written)
+	pending, hasPending := rc.pendingWrites[sessionUUID]
+	switch {
+	case inTransaction && len(written) > 0:
+		if !hasPending {
+			rc.pendingCount.Add(1)
+		}
+		rc.pendingWrites[sessionUUID] = append(pending, written...)
+	case !inTransaction && hasPending:
+		rc.invalidateLocked(pending)
+		delete(rc.pendingWrites, sessionUUID)
+		rc.pendingCount.Add(-1)
+	}
+}
+
+// closeSession invalidates the tables written to by the open transaction of a session that is closed
+func (rc *ResultCache) closeSession(safeSession *econtext.SafeSession) {
+	if rc == nil || rc.pendingCount.Load() == 0 {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	sessionUUID := safeSession.GetSessionUUID()
+	if pending, ok := rc.pendingWrites[sessionUUID]; ok {
+		rc.invalidateLocked(pending)
+		delete(rc.pendingWrites, sessionUUID)
+		rc.pendingCount.Add(-1)
+	}
+}
+
+func (rc *ResultCache) invalidateLocked(tables []string) {
+	if len(tables) == 0 {
+		return
+	}
+	rc.generation++
+	for _, table := range tables {
+		rc.generations[table] = rc.generation
+	}
+	rc.invalidations.Add(1)
+}
+
+// Clear drops all the cached results
+func (rc *ResultCache) Clear() {
+	if rc == nil {
+		return
+	}
+	rc.epoch.Add(1)
+}
+
+func (rc *ResultCache) currentGenerations(tables []string) []uint64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	generations := make([]uint64, len(tables))
+	for i, table := range tables {
+		generations[i] = rc.generations[table]
+	}
+	return generations
+}
+
+func (rc *ResultCache) isCurrent(tables []string, generations []uint64) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for i, table := range tables {
+		if rc.generations[table] != generations[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (rc *ResultCache) Close() {
+	if rc == nil {
+		return
+	}
+	rc.results.Close()
+}
+
+// ResultCacheEntry describes a cached result on the debug page
+type ResultCacheEntry struct {
+	Query     string
+	Tables    []string `json:",omitempty"`
+	Rows      int
+	Size      int64
+	ExpiresIn time.Duration
+}
+
+func (rc *ResultCache) debugEntries() []ResultCacheEntry {
+	entries := []ResultCacheEntry{}
+	if rc == nil {
+		return entries
+	}
+	now := time.Now()
+	rc.results.Range(rc.epoch.Load(), func(_ ResultCacheKey, entry *cachedResult) bool {
+		if now.Before(entry.expires) && rc.isCurrent(entry.tables, entry.generations) {
+			entries = append(entries, ResultCacheEntry{
+				Query:     entry.query,
+				Tables:    entry.tables,
+				Rows:      len(entry.result.Rows),
+				Size:      entry.CachedSize(true),
+				ExpiresIn: entry.expires.Sub(now),
+			})
+		}
+		return true
+	})
+	slices.SortFunc(entries, func(a, b ResultCacheEntry) int {
+		return strings.Compare(a.Query, b.Query)
+	})
+	return entries
+}
+
+// resultCacheKey identifies the result of a query: the normalized query with its bind variables,
+// the caller running it, and everything in the session that can change what the query returns.
+func resultCacheKey(
+	ctx context.Context,
+	plan *engine.Plan,
+	vcursor *econtext.VCursorImpl,
+	safeSession *econtext.SafeSession,
+	bindVars map[string]*querypb.BindVariable,
+) ResultCacheKey {
+	hasher := vthash.New256()
+	var buf []byte
+	writeString := func(s string) {
+		buf = binary.AppendUvarint(buf[:0], uint64(len(s)))
+		buf = append(buf, s...)
+		_, _ = hasher.Write(buf)
+	}
+
+	_, _ = hasher.WriteUint16(uint16(vcursor.ConnCollation()))
+	_, _ = hasher.WriteUint16(uint16(vcursor.TabletType()))
+	_, _ = hasher.WriteUint16(uint16(safeSession.GetOptions().GetIncludedFields()))
+	writeString(safeSession.TargetString)
+	writeString(plan.Original)
+
+	// table ACLs are checked with the caller IDs, so callers can't share results
+	ef := callerid.EffectiveCallerIDFromContext(ctx)
+	writeString(ef.GetPrincipal())
+	writeString(ef.GetComponent())
+	writeString(ef.GetSubcomponent())
+	_, _ = hasher.WriteUint16(uint16(len(ef.GetGroups())))
+	for _, group := range ef.GetGroups() {
+		writeString(group)
+	}
+	im := callerid.ImmediateCallerIDFromContext(ctx)
+	writeString(im.GetUsername())
+	_, _ = hasher.WriteUint16(uint16(len(im.GetGroups())))
+	for _, group := range im.GetGroups() {
+		writeString(group)
+	}
+
+	var sysvars []string
+	safeSession.GetSystemVariables(func(k string, v string) {
+		sysvars = append(sysvars, k+"="+v)
+	})
+	slices.Sort(sysvars)
+	for _, sysvar := range sysvars {
+		writeString(sysvar)
+	}
+
+	names := make([]string, 0, len(bindVars))
+	for name := range bindVars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		bv := bindVars[name]
+		writeString(name)
+		_, _ = hasher.WriteUint16(uint16(bv.Type))
+		writeString(string(bv.Value))
+		for _, val := range bv.Values {
+			_, _ = hasher.WriteUint16(uint16(val.Type))
+			writeString(string(val.Value))
+		}
+	}
+
+	var key ResultCacheKey
+	hasher.Sum(key[:0])
+	return key
+}
+
+// resultCacheTTL returns how long the results of a query may be cached. A query comment directive
+// takes precedence over the vschema; otherwise, every table read by the query must have a TTL in
+// the vschema, and the smallest one is used. Queries that read from tables this VTGate doesn't know
+// about, or that can return a different result each time they run, are never cached.
+// The TTL is never longer than maxTTL, unless maxTTL is 0.
+func resultCacheTTL(vschema *vindexes.VSchema, stmt sqlparser.Statement, plan *engine.Plan, maxTTL time.Duration) time.Duration {
+	ttl := queryResultCacheTTL(vschema, stmt, plan)
+	if maxTTL > 0 && ttl > maxTTL {
+		return maxTTL
+	}
+	return ttl
+}
+
+func queryResultCacheTTL(vschema *vindexes.VSchema, stmt sqlparser.Statement, plan *engine.Plan) time.Duration {
+	if plan.QueryType != sqlparser.StmtSelect || !resultIsCacheable(stmt) {
+		return 0
+	}
+	if ttl := plan.QueryHints.ResultCacheTTL; ttl != nil {
+		return time.Duration(*ttl) * time.Millisecond
+	}
+	if vschema == nil || len(plan.TablesUsed) == 0 {
+		return 0
+	}
+	var ttl time.Duration
+	for _, name := range plan.TablesUsed {
+		ks, tableName, _ := strings.Cut(name, ".")
+		table, err := vschema.FindTable(ks, tableName)
+		if err != nil || table == nil || table.ResultCacheTTL <= 0 {
+			return 0
+		}
+		if ttl == 0 || table.ResultCacheTTL < ttl {
+			ttl = table.ResultCacheTTL
+		}
+	}
+	return ttl
+}
+
+// resultIsCacheable returns false for statements whose result can change without any write to the
+// tables they read from, that depend on the state of the session, or that have side effects.
+func resultIsCacheable(stmt sqlparser.Statement) bool {
+	cacheable := true
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Select:
+			if node.Lock != sqlparser.NoLock || node.Into != nil || (node.Cache != nil && !*node.Cache) {
+				cacheable = false
+			}
+		case *sqlparser.Union:
+			if node.Lock != sqlparser.NoLock || node.Into != nil {
+				cacheable = false
+			}
+		case *sqlparser.CurTimeFuncExpr, *sqlparser.LockingFunc, *sqlparser.Variable:
+			cacheable = false
+		case *sqlparser.Argument:
+			// the functions that read the state of the session are rewritten to these arguments
+			switch {
+			case node.Name == sqlparser.LastInsertIDName, node.Name == sqlparser.FoundRowsName,
+				node.Name == sqlparser.RowCountName, node.Name == sqlparser.DBVarName,
+				strings.HasPrefix(node.Name, sqlparser.UserDefinedVariableName):
+				cacheable = false
+			}
+		case *sqlparser.FuncExpr:
+			switch node.Name.Lowered() {
+			case "rand", "uuid", "uuid_short", "random_bytes", "sleep", "connection_id",
+				"current_user", "session_user", "system_user", "user", "current_role", "unix_timestamp",
+				"utc_date", "utc_time", "utc_timestamp", "curdate", "curtime", "current_date",
+				"current_time", "localtime", "localtimestamp", "sysdate", "last_insert_id",
+				"found_rows", "row_count", "database", "schema", "benchmark":
+				cacheable = false
+			}
+		}
+		return cacheable, nil
+	}, stmt)
+	return cacheable
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestResultCache(t *testing.T) {
+	executor, sbc1, sbc2, _, ctx := createExecutorEnv(t)
+	executor.resultCache = NewResultCache(1024 * 1024)
+	rc := executor.resultCache
+
+	shardQueries := func() int64 {
+		return sbc1.ExecCount.Load() + sbc2.ExecCount.Load()
+	}
+	exec := func(session *econtext.SafeSession, sql string) *sqltypes.Result {
+		t.Helper()
+		qr, err := executorExecSession(ctx, executor, session, sql, nil)
+		require.NoError(t, err)
+		return qr
+	}
+
+	session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@primary", Autocommit: true, SessionUUID: "a"})
+	query := "select /*vt+ RESULT_CACHE_TTL_MS=60000 */ id from `user` where id > 1"
+
+	want := exec(session, query)
+	before := shardQueries()
+	got := exec(session, query)
+	assert.Equal(t, before, shardQueries(), "the second execution should be served from the cache")
+	assert.Equal(t, want, got)
+	assert.EqualValues(t, 1, rc.hits.Load())
+	assert.Len(t, rc.debugEntries(), 1)
+
+	// different bind variables are a different result
+	exec(session, "select /*vt+ RESULT_CACHE_TTL_MS=60000 */ id from `user` where id > 2")
+	assert.Greater(t, shardQueries(), before)
+
+	// queries without the directive are not cached
+	before = shardQueries()
+	exec(session, "select id from `user` where id > 1")
+	exec(session, "select id from `user` where id > 1")
+	assert.Equal(t, before+4, shardQueries())
+
+	// a write through this vtgate invalidates the results that read from the table
+	exec(session, "delete from `user` where id = 1")
+	before = shardQueries()
+	exec(session, query)
+	assert.Greater(t, shardQueries(), before)
+	before = shardQueries()
+	exec(session, query)
+	assert.Equal(t, before, shardQueries())
+
+	// transactions don't use the cache
+	txSession := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@primary", Autocommit: true, SessionUUID: "b"})
+	exec(txSession, "begin")
+	before = shardQueries()
+	exec(txSession, query)
+	assert.Greater(t, shardQueries(), before)
+
+	// writes are invalidated again when the transaction is over
+	exec(txSession, "delete from `user` where id = 1")
+	exec(session, query)
+	before = shardQueries()
+	exec(session, query)
+	assert.Equal(t, before, shardQueries(), "results read while the transaction is open are cached")
+	exec(txSession, "commit")
+	exec(session, query)
+	assert.Greater(t, shardQueries(), before, "the commit should invalidate the cached result")
+
+	// a new vschema drops everything
+	exec(session, query)
+	executor.SaveVSchema(nil, executor.vschemaStats)
+	assert.Empty(t, rc.debugEntries())
+
+	// session variables are part of the key
+	exec(session, "set sql_mode = ''")
+	before = shardQueries()
+	exec(session, query)
+	assert.Greater(t, shardQueries(), before)
+}
+
+func TestResultCacheTTL(t *testing.T) {
+	executor, sbc1, _, _, ctx := createExecutorEnv(t)
+	executor.resultCache = NewResultCache(1024 * 1024)
+
+	session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@primary", Autocommit: true})
+	query := "select /*vt+ RESULT_CACHE_TTL_MS=1 */ id from `user` where id = 1"
+	_, err := executorExecSession(ctx, executor, session, query, nil)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	before := sbc1.ExecCount.Load()
+	_, err = executorExecSession(ctx, executor, session, query, nil)
+	require.NoError(t, err)
+	assert.Equal(t, before+1, sbc1.ExecCount.Load(), "the cached result should have expired")
+}
+
+func TestResultCacheTTLFromVSchema(t *testing.T) {
+	srvVSchema := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {ResultCacheTtlMs: 1000},
+					"t2": {ResultCacheTtlMs: 500},
+					"t3": {},
+				},
+			},
+		},
+	}
+	parser := sqlparser.NewTestParser()
+	vschema := vindexes.BuildVSchema(srvVSchema, parser)
+	require.NoError(t, vschema.Keyspaces["ks"].Error)
+
+	ms := func(n int) *int { return &n }
+	tcases := []struct {
+		query  string
+		tables []string
+		hint   *int
+		maxTTL time.Duration
+		want   time.Duration
+	}{{
+		query:  "select * from t1",
+		tables: []string{"ks.t1"},
+		want:   time.Second,
+	}, {
+		query:  "select * from t1 join t2",
+		tables: []string{"ks.t1", "ks.t2"},
+		want:   500 * time.Millisecond,
+	}, {
+		query:  "select * from t1 join t3",
+		tables: []string{"ks.t1", "ks.t3"},
+	}, {
+		query:  "select * from t3",
+		tables: []string{"ks.t3"},
+		hint:   ms(10),
+		want:   10 * time.Millisecond,
+	}, {
+		query:  "select * from t1",
+		tables: []string{"ks.t1"},
+		hint:   ms(0),
+	}, {
+		query:  "select now() from t1",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select rand() from t1",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select * from t1 for update",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select sql_no_cache * from t1",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select * from t1 where id in (select id from t2 lock in share mode)",
+		tables: []string{"ks.t1", "ks.t2"},
+	}, {
+		query:  "select last_insert_id(), found_rows(), row_count() from t1",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select :__lastInsertId from t1",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select database() from t1",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select * from t1 where id = @id",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select * from t1 where id = :__vtudvid",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select @@sql_mode from t1",
+		tables: []string{"ks.t1"},
+	}, {
+		query:  "select * from t1",
+		tables: []string{"ks.t1"},
+		maxTTL: 100 * time.Millisecond,
+		want:   100 * time.Millisecond,
+	}, {
+		query:  "select * from t3",
+		tables: []string{"ks.t3"},
+		hint:   ms(10),
+		maxTTL: 100 * time.Millisecond,
+		want:   10 * time.Millisecond,
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tc.query)
+			require.NoError(t, err)
+			plan := &engine.Plan{
+				QueryType:  sqlparser.StmtSelect,
+				TablesUsed: tc.tables,
+				QueryHints: sqlparser.QueryHints{ResultCacheTTL: tc.hint},
+			}
+			assert.Equal(t, tc.want, resultCacheTTL(vschema, stmt, plan, tc.maxTTL))
+		})
+	}
+}
+
+func TestResultCacheKey(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+	session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@primary"})
+	vcursor, err := econtext.NewVCursorImpl(session, sqlparser.MarginComments{}, executor, nil, executor.vm, executor.VSchema(), executor.resolver.resolver, nil, nullResultsObserver{}, executor.vConfig, nil)
+	require.NoError(t, err)
+
+	plan := &engine.Plan{Original: "select id from `user` where id = :id"}
+	key := func(bv map[string]*querypb.BindVariable) ResultCacheKey {
+		return resultCacheKey(t.Context(), plan, vcursor, session, bv)
+	}
+
+	assert.Equal(t,
+		key(map[string]*querypb.BindVariable{"id": sqltypes.Int64BindVariable(1)}),
+		key(map[string]*querypb.BindVariable{"id": sqltypes.Int64BindVariable(1)}))
+	assert.NotEqual(t,
+		key(map[string]*querypb.BindVariable{"id": sqltypes.Int64BindVariable(1)}),
+		key(map[string]*querypb.BindVariable{"id": sqltypes.Int64BindVariable(2)}))
+	assert.NotEqual(t,
+		key(map[string]*querypb.BindVariable{"id": sqltypes.Int64BindVariable(1)}),
+		key(map[string]*querypb.BindVariable{"id": sqltypes.StringBindVariable("1")}))
+	assert.NotEqual(t,
+		key(map[string]*querypb.BindVariable{"a": sqltypes.StringBindVariable("bc")}),
+		key(map[string]*querypb.BindVariable{"ab": sqltypes.StringBindVariable("c")}))
+
+	// results are not shared between callers
+	bv := map[string]*querypb.BindVariable{"id": sqltypes.Int64BindVariable(1)}
+	userCtx := func(ctx context.Context, user string) context.Context {
+		return callerid.NewContext(ctx, callerid.NewEffectiveCallerID(user, "", ""), callerid.NewImmediateCallerID(user))
+	}
+	assert.Equal(t,
+		resultCacheKey(userCtx(t.Context(), "a"), plan, vcursor, session, bv),
+		resultCacheKey(userCtx(t.Context(), "a"), plan, vcursor, session, bv))
+	assert.NotEqual(t,
+		resultCacheKey(userCtx(t.Context(), "a"), plan, vcursor, session, bv),
+		resultCacheKey(userCtx(t.Context(), "b"), plan, vcursor, session, bv))
+	assert.NotEqual(t, key(bv), resultCacheKey(userCtx(t.Context(), "a"), plan, vcursor, session, bv))
+}
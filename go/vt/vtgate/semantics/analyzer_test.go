@@ -528,6 +528,18 @@ func TestScoping(t *testing.T) {
 			query:        "select 1 from u1, u2 left join u3 on u1.a = u2.a",
 			errorMessage: "column 'u1.a' not found",
 		},
+		{
+			query:        "select 1 from u1 join (select u1.a from u2) as t",
+			errorMessage: "column 'u1.a' not found",
+		},
+		{
+			query:        "select 1 from u1, (select u1.a from u2) as t",
+			errorMessage: "column 'u1.a' not found",
+		},
+		{
+			query:        "select 1 from u1 join lateral (select u3.a from u2) as t join u3",
+			errorMessage: "column 'u3.a' not found",
+		},
 	}
 	for _, query := range queries {
 		t.Run(query.query, func(t *testing.T) {
@@ -585,6 +597,42 @@ func TestScopeForSubqueries(t *testing.T) {
 	}
 }
 
+func TestScopeForLateralDerivedTables(t *testing.T) {
+	tcases := []struct {
+		sql  string
+		deps TableSet
+	}{
+		{
+			sql:  `select 1 from x join lateral (select x.col from y) as t`,
+			deps: TS0,
+		}, {
+			sql:  `select 1 from x, lateral (select x.col from y) as t`,
+			deps: TS0,
+		}, {
+			sql:  `select 1 from x, z join lateral (select x.col from y) as t`,
+			deps: TS0,
+		}, {
+			sql:  `select 1 from x join lateral (select col from y) as t`,
+			deps: TS1,
+		},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.sql, func(t *testing.T) {
+			stmt, semTable := parseAndAnalyze(t, tc.sql, "d")
+			sel, _ := stmt.(*sqlparser.Select)
+
+			// the lateral derived table is the last table of the FROM clause
+			var tbl sqlparser.TableExpr = sel.From[len(sel.From)-1]
+			if join, ok := tbl.(*sqlparser.JoinTableExpr); ok {
+				tbl = join.RightExpr
+			}
+			dt := tbl.(*sqlparser.AliasedTableExpr).Expr.(*sqlparser.DerivedTable)
+			exp := extract(dt.Select.(*sqlparser.Select), 0)
+			assert.Equal(t, tc.deps, semTable.RecursiveDeps(exp))
+		})
+	}
+}
+
 func TestSubqueryOrderByBinding(t *testing.T) {
 	queries := []struct {
 		query    string
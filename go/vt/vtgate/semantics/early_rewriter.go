@@ -134,7 +134,7 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 	}
 	scope := r.scoper.currentScope()
 	cte := scope.findCTE(tbl.Name.String())
-	if cte == nil {
+	if cte == nil || r.tables.isBaseTable(node) {
 		return nil
 	}
 	if node.As.IsEmpty() {
@@ -920,6 +920,12 @@ func TestCTEToDerivedTableRewrite(t *testing.T) {
 	}, {
 		sql:    "with x(id) as (select 1) select * from x",
 		expSQL: "select id from (select 1 from dual) as x(id)",
+	}, {
+		sql:    "with t1 as (select a from t1) select * from t1",
+		expSQL: "select a from (select a from t1) as t1",
+	}, {
+		sql:    "with x as (select a from t2), t2 as (select 1 as a) select * from x",
+		expSQL: "select a from (select a from t2) as x",
 	}}
 	for _, tcase := range tcases {
 		t.Run(tcase.sql, func(t *testing.T) {
@@ -19,6 +19,7 @@ package semantics
 import (
 	"maps"
 	"reflect"
+	"slices"
 
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -49,6 +50,7 @@ type (
 		isUnion      bool
 		joinUsing    map[string]TableSet
 		stmtScope    bool
+		fromItem     bool // set for the scope of one of the table expressions in the FROM clause of a SELECT
 		ctes         map[string]*sqlparser.CommonTableExpr
 		windows      map[string]*sqlparser.WindowDefinition
 		inGroupBy    bool
@@ -73,7 +75,7 @@ func (s *scoper) down(cursor *sqlparser.Cursor) error {
 	case *sqlparser.Update, *sqlparser.Delete, *sqlparser.Insert:
 		s.pushDMLScope(node)
 	case *sqlparser.Select:
-		s.pushSelectScope(node)
+		s.pushSelectScope(node, cursor.Parent())
 	case *sqlparser.Union:
 		s.pushUnionScope(node)
 	case sqlparser.TableExpr:
@@ -183,12 +185,17 @@ func (s *scoper) enterJoinScope(cursor *sqlparser.Cursor) {
 			nScope.ctes = currScope.ctes
 		}
 		nScope.stmt = cursor.Parent().(*sqlparser.Select)
+		nScope.fromItem = true
 		s.push(nScope)
 	}
 }
 
-func (s *scoper) pushSelectScope(node *sqlparser.Select) {
-	currScope := newScope(s.currentScope())
+func (s *scoper) pushSelectScope(node *sqlparser.Select, parent sqlparser.SQLNode) {
+	parentScope := s.currentScope()
+	if dt, ok := parent.(*sqlparser.DerivedTable); ok {
+		parentScope = s.derivedTableParentScope(dt)
+	}
+	currScope := newScope(parentScope)
 	currScope.stmtScope = true
 	s.push(currScope)
 
@@ -205,6 +212,26 @@ func (s *scoper) pushSelectScope(node *sqlparser.Select) {
 	s.wScope[node] = newScope(nil)
 }
 
+// derivedTableParentScope returns the scope that the query of a derived table in the FROM clause is nested in.
+// A derived table can't see the other tables of the FROM clause, unless it is a LATERAL derived table,
+// which can use the columns of the tables that come before it.
+func (s *scoper) derivedTableParentScope(dt *sqlparser.DerivedTable) *scope {
+	current := s.currentScope()
+	if !current.fromItem {
+		return current
+	}
+	if !dt.Lateral {
+		nScope := newScope(current.parent)
+		nScope.ctes = current.ctes
+		return nScope
+	}
+	// the tables of the earlier items of the FROM clause have already been added to the scope of the SELECT,
+	// while the current scope has the tables joined before the derived table in this item
+	nScope := newScope(current)
+	nScope.tables = slices.Clone(s.rScope[current.stmt.(*sqlparser.Select)].tables)
+	return nScope
+}
+
 func (s *scoper) pushDMLScope(node sqlparser.SQLNode) {
 	currScope := newScope(s.currentScope())
 	currScope.stmtScope = true
@@ -361,29 +388,10 @@ func (s *scope) addCTE(cte *sqlparser.CommonTableExpr) error {
 	if exists {
 		return vterrors.VT03013(name)
 	}
-	if err := checkForInvalidAliasUse(cte, name); err != nil {
-		return err
-	}
 	s.ctes[name] = cte
 	return nil
 }
 
-func checkForInvalidAliasUse(cte *sqlparser.CommonTableExpr, name string) (err error) {
-	// TODO I'm sure there is a better. way, but we need to do this to stop infinite loops from occurring
-	down := func(node sqlparser.SQLNode, parent sqlparser.SQLNode) bool {
-		tbl, ok := node.(sqlparser.TableName)
-		if !ok || tbl.Qualifier.NotEmpty() {
-			return err == nil
-		}
-		if tbl.Name.String() == name {
-			err = vterrors.VT12001("do not support CTE that use the CTE alias inside the CTE query")
-		}
-		return err == nil
-	}
-	_ = sqlparser.CopyOnRewrite(cte.Subquery, down, nil, nil)
-	return err
-}
-
 func (s *scope) addTable(info TableInfo) error {
 	name, err := info.Name()
 	if err != nil {
@@ -47,6 +47,10 @@ type (
 		// cte is a map of CTE definitions that are used in the query
 		cte map[string]*CTE
 
+		// baseTables are table references inside non-recursive CTE definitions that use the name of the CTE itself,
+		// or of a CTE defined later in the same WITH clause. These are reading from real tables, not from the CTE.
+		baseTables map[*sqlparser.AliasedTableExpr]any
+
 		// lastInsertIdWithArgument is used to signal to later stages that we
 		// need to do special handling of the engine primitive
 		lastInsertIdWithArgument bool
@@ -55,10 +59,11 @@ type (
 
 func newEarlyTableCollector(si SchemaInformation, currentDb string) *earlyTableCollector {
 	return &earlyTableCollector{
-		si:        si,
-		currentDb: currentDb,
-		done:      map[*sqlparser.AliasedTableExpr]TableInfo{},
-		cte:       map[string]*CTE{},
+		si:         si,
+		currentDb:  currentDb,
+		done:       map[*sqlparser.AliasedTableExpr]TableInfo{},
+		cte:        map[string]*CTE{},
+		baseTables: map[*sqlparser.AliasedTableExpr]any{},
 	}
 }
 
@@ -73,6 +78,11 @@ func (etc *earlyTableCollector) down(cursor *sqlparser.Cursor) bool {
 				Recursive: node.Recursive,
 			}
 		}
+		if !node.Recursive {
+			for i, cte := range node.CTEs {
+				etc.markBaseTables(cte, node.CTEs[i:])
+			}
+		}
 	case *sqlparser.FuncExpr:
 		if node.Name.EqualString("last_insert_id") && len(node.Exprs) == 1 {
 			etc.lastInsertIdWithArgument = true
@@ -82,6 +92,34 @@ func (etc *earlyTableCollector) down(cursor *sqlparser.Cursor) bool {
 	return true
 }
 
+// markBaseTables finds the table references inside a non-recursive CTE definition that can't be pointing to a CTE.
+// A non-recursive CTE can only use the CTEs defined before it, so using its own name or the name of a
+// CTE defined later means that the query is reading from a real table with that name.
+func (etc *earlyTableCollector) markBaseTables(cte *sqlparser.CommonTableExpr, notVisible []*sqlparser.CommonTableExpr) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		ate, ok := node.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return true, nil
+		}
+		tbl, ok := ate.Expr.(sqlparser.TableName)
+		if !ok || tbl.Qualifier.NotEmpty() {
+			return true, nil
+		}
+		for _, other := range notVisible {
+			if other.ID.String() == tbl.Name.String() {
+				etc.baseTables[ate] = nil
+				break
+			}
+		}
+		return true, nil
+	}, cte.Subquery)
+}
+
+func (etc *earlyTableCollector) isBaseTable(node *sqlparser.AliasedTableExpr) bool {
+	_, found := etc.baseTables[node]
+	return found
+}
+
 func (etc *earlyTableCollector) up(cursor *sqlparser.Cursor) bool {
 	ate, ok := cursor.Node().(*sqlparser.AliasedTableExpr)
 	if !ok {
@@ -111,7 +149,7 @@ func (etc *earlyTableCollector) newTableCollector(scoper *scoper, org originable
 func (etc *earlyTableCollector) handleTableName(tbl sqlparser.TableName, aet *sqlparser.AliasedTableExpr) {
 	if tbl.Qualifier.IsEmpty() {
 		_, isCTE := etc.cte[tbl.Name.String()]
-		if isCTE {
+		if isCTE && !etc.isBaseTable(aet) {
 			// no need to handle these tables here, we wait for the late phase instead
 			return
 		}
@@ -351,7 +389,7 @@ func (etc *earlyTableCollector) getCTE(t sqlparser.TableName) *CTE {
 func (etc *earlyTableCollector) getTableInfo(node *sqlparser.AliasedTableExpr, t sqlparser.TableName, sc *scoper) (TableInfo, error) {
 	var tbl *vindexes.BaseTable
 	var vindex vindexes.Vindex
-	if cteDef := etc.getCTE(t); cteDef != nil {
+	if cteDef := etc.getCTE(t); cteDef != nil && !etc.isBaseTable(node) {
 		cte, err := etc.buildRecursiveCTE(node, t, sc, cteDef)
 		if err != nil {
 			return nil, err
@@ -75,7 +75,7 @@ func registerTabletGatewayFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&retryCount, "retry-count", 2, "retry count")
 	fs.BoolVar(&balancerEnabled, "enable-balancer", false, "(DEPRECATED: use --vtgate-balancer-mode instead) Enable the tablet balancer to evenly spread query load for a given tablet type")
 	fs.StringVar(&balancerModeFlag, "vtgate-balancer-mode", "", fmt.Sprintf("Tablet balancer mode (options: %s). Defaults to 'cell' which shuffles tablets in the local cell.", strings.Join(balancer.GetAvailableModeNames(), ", ")))
-	fs.StringSliceVar(&balancerVtgateCells, "balancer-vtgate-cells", []string{}, "Comma-separated list of cells that contain vttablets. For 'prefer-cell' mode, this is required. For 'random' mode, this is optional and filters tablets to those cells.")
+	fs.StringSliceVar(&balancerVtgateCells, "balancer-vtgate-cells", []string{}, "Comma-separated list of cells that contain vttablets. For 'prefer-cell' mode, this is required. For 'random' and 'least-loaded' modes, this is optional and filters tablets to those cells.")
 	fs.StringSliceVar(&balancerKeyspaces, "balancer-keyspaces", []string{}, "Comma-separated list of keyspaces for which to use the balancer (optional). If empty, applies to all keyspaces.")
 }
 
@@ -213,7 +213,7 @@ func (gw *TabletGateway) setupBalancer() {
 		os.Exit(1)
 	}
 
-	// Create the balancer for prefer-cell or random modes
+	// Create the balancer for prefer-cell, random, session or least-loaded modes
 	var err error
 	gw.balancer, err = balancer.NewTabletBalancer(gw.balancerMode, gw.localCell, balancerVtgateCells)
 	if err != nil {
@@ -426,9 +426,18 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 
 		gw.updateDefaultConnCollation(tabletLastUsed)
 
+		// Let balancers that route on load know about the query we're about to send.
+		tracker, _ := gw.balancer.(balancer.LoadTracker)
+		if tracker != nil {
+			tracker.QueryStarted(th)
+		}
+
 		startTime := time.Now()
 		var canRetry bool
 		canRetry, err = inner(ctx, target, th.Conn)
+		if tracker != nil {
+			tracker.QueryFinished(th, time.Since(startTime), err)
+		}
 		gw.updateStats(target, startTime, err)
 		if canRetry {
 			invalidTablets[topoproto.TabletAliasString(tabletLastUsed.Alias)] = true
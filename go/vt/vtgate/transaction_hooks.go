@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"sync"
+	"sync/atomic"
+
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+)
+
+// transactionEndHooks holds the functions to run once the open transaction of a
+// session is over, by session UUID. Sessions are not kept between queries, so
+// the hooks are run by the executor after every query that leaves the session
+// outside of a transaction, and when the session is closed.
+type transactionEndHooks struct {
+	mu    sync.Mutex
+	hooks map[string][]func()
+	count atomic.Int64
+}
+
+// add registers fn to run when the transaction of safeSession is over.
+func (h *transactionEndHooks) add(safeSession *econtext.SafeSession, fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.hooks == nil {
+		h.hooks = make(map[string][]func())
+	}
+	sessionUUID := safeSession.GetSessionUUID()
+	hooks, ok := h.hooks[sessionUUID]
+	if !ok {
+		h.count.Add(1)
+	}
+	h.hooks[sessionUUID] = append(hooks, fn)
+}
+
+// afterExecute runs the hooks of safeSession if it's not in a transaction anymore.
+func (h *transactionEndHooks) afterExecute(safeSession *econtext.SafeSession) {
+	if h.count.Load() == 0 || safeSession.InTransaction() {
+		return
+	}
+	h.run(safeSession)
+}
+
+// run runs and removes the hooks of safeSession.
+func (h *transactionEndHooks) run(safeSession *econtext.SafeSession) {
+	if h.count.Load() == 0 {
+		return
+	}
+	h.mu.Lock()
+	sessionUUID := safeSession.GetSessionUUID()
+	hooks, ok := h.hooks[sessionUUID]
+	if ok {
+		delete(h.hooks, sessionUUID)
+		h.count.Add(-1)
+	}
+	h.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	econtext "vitess.io/vitess/go/vt/vtgate/executorcontext"
+)
+
+func TestTransactionEndHooks(t *testing.T) {
+	executor, _, _, _, ctx := createExecutorEnv(t)
+
+	exec := func(session *econtext.SafeSession, sql string) {
+		t.Helper()
+		_, err := executorExecSession(ctx, executor, session, sql, nil)
+		require.NoError(t, err)
+	}
+
+	var committed, other, closed int
+	session := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@primary", Autocommit: true, SessionUUID: "a"})
+	otherSession := econtext.NewSafeSession(&vtgatepb.Session{TargetString: "@primary", Autocommit: true, SessionUUID: "b"})
+
+	exec(session, "begin")
+	exec(otherSession, "begin")
+	executor.OnTransactionEnd(session, func() { committed++ })
+	executor.OnTransactionEnd(otherSession, func() { other++ })
+
+	// the hooks don't run while the transaction is open
+	exec(session, "select id from `user` where id = 1")
+	assert.Zero(t, committed)
+
+	// and run once when it's over, without running the hooks of other sessions
+	exec(session, "commit")
+	assert.Equal(t, 1, committed)
+	assert.Zero(t, other)
+	exec(session, "select id from `user` where id = 1")
+	assert.Equal(t, 1, committed)
+
+	exec(otherSession, "rollback")
+	assert.Equal(t, 1, other)
+
+	// closing a session ends its transaction
+	exec(session, "begin")
+	executor.OnTransactionEnd(session, func() { closed++ })
+	require.NoError(t, executor.CloseSession(ctx, session))
+	assert.Equal(t, 1, closed)
+	assert.Zero(t, executor.txEndHooks.count.Load())
+}
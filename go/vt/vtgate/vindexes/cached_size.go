@@ -177,6 +177,21 @@ func (cached *Keyspace) CachedSize(alloc bool) int64 {
 	return size
 }
 
+func (cached *LookupCache) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(64)
+	}
+	// field entries vitess.io/vitess/go/vt/vtgate/vindexes.lookupCacheEntries
+	if cc, ok := cached.entries.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
+
 func (cached *LookupCost) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -196,7 +211,7 @@ func (cached *LookupHash) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(224)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -218,7 +233,7 @@ func (cached *LookupHashUnique) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(224)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -240,7 +255,7 @@ func (cached *LookupNonUnique) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(224)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -262,7 +277,7 @@ func (cached *LookupUnicodeLooseMD5Hash) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(224)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -284,7 +299,7 @@ func (cached *LookupUnicodeLooseMD5HashUnique) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(224)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -306,7 +321,7 @@ func (cached *LookupUnique) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(192)
+		size += int64(224)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -430,6 +445,39 @@ func (cached *NumericStaticMap) CachedSize(alloc bool) int64 {
 	return size
 }
 
+func (cached *RangePartition) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(96)
+	}
+	// field name string
+	size += hack.RuntimeAllocSize(int64(len(cached.name)))
+	// field typ string
+	size += hack.RuntimeAllocSize(int64(len(cached.typ)))
+	// field coll vitess.io/vitess/go/mysql/collations/colldata.Collation
+	if cc, ok := cached.coll.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field partitions []vitess.io/vitess/go/vt/vtgate/vindexes.rangePartition
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.partitions)) * int64(80))
+		for _, elem := range cached.partitions {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field unknownParams []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.unknownParams)) * int64(16))
+		for _, elem := range cached.unknownParams {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	return size
+}
+
 func (cached *RegionExperimental) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -600,7 +648,7 @@ func (cached *clCommon) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(288)
+		size += int64(320)
 	}
 	// field name string
 	size += hack.RuntimeAllocSize(int64(len(cached.name)))
@@ -634,7 +682,7 @@ func (cached *lookupInternal) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(144)
+		size += int64(176)
 	}
 	// field Table string
 	size += hack.RuntimeAllocSize(int64(len(cached.Table)))
@@ -649,6 +697,8 @@ func (cached *lookupInternal) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.To)))
 	// field ReadLock string
 	size += hack.RuntimeAllocSize(int64(len(cached.ReadLock)))
+	// field CacheTTL string
+	size += hack.RuntimeAllocSize(int64(len(cached.CacheTTL)))
 	// field sel string
 	size += hack.RuntimeAllocSize(int64(len(cached.sel)))
 	// field selTxDml string
@@ -657,6 +707,8 @@ func (cached *lookupInternal) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.ver)))
 	// field del string
 	size += hack.RuntimeAllocSize(int64(len(cached.del)))
+	// field cache *vitess.io/vitess/go/vt/vtgate/vindexes.LookupCache
+	size += cached.cache.CachedSize(true)
 	return size
 }
 
@@ -672,3 +724,22 @@ func (cached *prefixCFC) CachedSize(alloc bool) int64 {
 	size += cached.cfcCommon.CachedSize(true)
 	return size
 }
+
+func (cached *rangePartition) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field from []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.from)))
+	}
+	// field to []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.to)))
+	}
+	return size
+}
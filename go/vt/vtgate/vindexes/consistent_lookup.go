@@ -45,11 +45,13 @@ var (
 	_ Lookup          = (*ConsistentLookupUnique)(nil)
 	_ WantOwnerInfo   = (*ConsistentLookupUnique)(nil)
 	_ LookupPlanable  = (*ConsistentLookupUnique)(nil)
+	_ LookupCaching   = (*ConsistentLookupUnique)(nil)
 	_ ParamValidating = (*ConsistentLookupUnique)(nil)
 	_ SingleColumn    = (*ConsistentLookup)(nil)
 	_ Lookup          = (*ConsistentLookup)(nil)
 	_ WantOwnerInfo   = (*ConsistentLookup)(nil)
 	_ LookupPlanable  = (*ConsistentLookup)(nil)
+	_ LookupCaching   = (*ConsistentLookup)(nil)
 	_ ParamValidating = (*ConsistentLookup)(nil)
 
 	consistentLookupParams = append(
@@ -168,6 +170,11 @@ func (lu *ConsistentLookup) AutoCommitEnabled() bool {
 	return lu.lkp.Autocommit
 }
 
+// LookupCache implements the LookupCaching interface.
+func (lu *ConsistentLookup) LookupCache() *LookupCache {
+	return lu.lkp.cache
+}
+
 // UnknownParams implements the ParamValidating interface.
 func (lu *ConsistentLookup) UnknownParams() []string {
 	return lu.unknownParams
@@ -273,6 +280,11 @@ func (lu *ConsistentLookupUnique) AutoCommitEnabled() bool {
 	return lu.lkp.Autocommit
 }
 
+// LookupCache implements the LookupCaching interface.
+func (lu *ConsistentLookupUnique) LookupCache() *LookupCache {
+	return lu.lkp.cache
+}
+
 // ====================================================================
 
 // clCommon defines a vindex that uses a lookup table.
@@ -397,6 +409,8 @@ func (lu *clCommon) handleDup(ctx context.Context, vcursor VCursor, values []sql
 	}
 	bindVars[lu.lkp.To] = sqltypes.BytesBindVariable(ksid)
 
+	defer lu.lkp.invalidate(vcursor, [][]sqltypes.Value{values})()
+
 	// Lock the lookup row using pre priority.
 	qr, err := vcursor.Execute(ctx, "VindexCreate", lu.lockLookupQuery, bindVars, false /* rollbackOnError */, vtgatepb.CommitOrder_PRE)
 	if err != nil {
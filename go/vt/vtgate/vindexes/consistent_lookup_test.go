@@ -515,6 +515,10 @@ func (vc *loggingVCursor) InTransactionAndIsDML() bool {
 	return false
 }
 
+func (vc *loggingVCursor) InTransaction() bool {
+	return false
+}
+
 func (vc *loggingVCursor) ConnCollation() collations.ID {
 	return vc.Environment().CollationEnv().DefaultConnectionCharset()
 }
@@ -93,6 +93,7 @@ var availableVindexes = []string{
 	"unicode_loose_xxhash",
 	"reverse_bits",
 	"region_json",
+	"range_partition",
 	"null",
 }
 
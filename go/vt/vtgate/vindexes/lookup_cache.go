@@ -0,0 +1,227 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/cache"
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vthash"
+)
+
+// LookupCache is a bounded LRU cache of the rows returned by the lookup
+// query of a lookup vindex, keyed by the looked up value. Text values are
+// keyed by their hash in the collation of the connection, so that values
+// that are equal for the lookup query share their entry. Entries expire
+// after a TTL, which bounds how stale the cache can get when the lookup
+// table is written to from outside of this vtgate. Writes done through
+// the vindex itself invalidate the entries they touch, and do so again
+// once their transaction is over.
+type LookupCache struct {
+	ttl time.Duration
+
+	// mu serializes invalidations with stores, so that a lookup that raced
+	// with an invalidation cannot store what it read before the write.
+	mu         sync.Mutex
+	generation uint64
+	entries    lookupCacheEntries
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+}
+
+// lookupCacheEntries is implemented by cache.LRUCache. It's an interface
+// so that the cache, which is shared and bounded on its own, is not
+// accounted in the size of the plans that use the vindex.
+type lookupCacheEntries interface {
+	Get(key string) (*lookupCacheEntry, bool)
+	Set(key string, entry *lookupCacheEntry) bool
+	Delete(key string)
+	Len() int
+}
+
+type lookupCacheEntry struct {
+	rows    [][]sqltypes.Value
+	expires time.Time
+}
+
+// LookupCaching is implemented by the lookup vindexes that have been
+// configured with a cache for their lookup results.
+type LookupCaching interface {
+	// LookupCache returns the cache of the vindex, or nil if
+	// it doesn't have one.
+	LookupCache() *LookupCache
+}
+
+// TransactionEndNotifier is implemented by the VCursors that can run a
+// function once the open transaction of their session is over.
+type TransactionEndNotifier interface {
+	// OnTransactionEnd runs fn once the transaction is committed
+	// or rolled back.
+	OnTransactionEnd(fn func())
+}
+
+// NewLookupCache creates a LookupCache that holds up to size values
+// for up to ttl each.
+func NewLookupCache(size int64, ttl time.Duration) *LookupCache {
+	return &LookupCache{
+		ttl:     ttl,
+		entries: cache.NewLRUCache[*lookupCacheEntry](size),
+	}
+}
+
+// Lookup returns the lookup results for ids, serving the ones it can from
+// the cache and calling lookup for the others. The results of lookup are
+// stored in the cache. Lookup calls lookup directly if lc is nil.
+func (lc *LookupCache) Lookup(coll collations.ID, ids []sqltypes.Value, lookup func(ids []sqltypes.Value) ([]*sqltypes.Result, error)) ([]*sqltypes.Result, error) {
+	if lc == nil {
+		return lookup(ids)
+	}
+
+	results := make([]*sqltypes.Result, len(ids))
+	var missing []sqltypes.Value
+	var missingIdx []int
+	for i, id := range ids {
+		if rows, ok := lc.get(coll, id); ok {
+			results[i] = &sqltypes.Result{Rows: rows}
+			continue
+		}
+		missing = append(missing, id)
+		missingIdx = append(missingIdx, i)
+	}
+	lc.hits.Add(int64(len(ids) - len(missing)))
+	lc.misses.Add(int64(len(missing)))
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	lc.mu.Lock()
+	generation := lc.generation
+	lc.mu.Unlock()
+
+	fetched, err := lookup(missing)
+	if err != nil {
+		return nil, err
+	}
+	lc.store(coll, generation, missing, fetched)
+	for i, idx := range missingIdx {
+		results[idx] = fetched[i]
+	}
+	return results, nil
+}
+
+func (lc *LookupCache) get(coll collations.ID, id sqltypes.Value) ([][]sqltypes.Value, bool) {
+	key, ok := lookupCacheKey(coll, id)
+	if !ok {
+		return nil, false
+	}
+	entry, ok := lc.entries.Get(key)
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		lc.entries.Delete(key)
+		return nil, false
+	}
+	return entry.rows, true
+}
+
+func (lc *LookupCache) store(coll collations.ID, generation uint64, ids []sqltypes.Value, results []*sqltypes.Result) {
+	expires := time.Now().Add(lc.ttl)
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if generation != lc.generation {
+		return
+	}
+	for i, id := range ids {
+		key, ok := lookupCacheKey(coll, id)
+		if !ok || i >= len(results) {
+			continue
+		}
+		lc.entries.Set(key, &lookupCacheEntry{rows: results[i].Rows, expires: expires})
+	}
+}
+
+// Invalidate removes ids from the cache. Lookups that are in flight while
+// Invalidate is called won't store their results.
+func (lc *LookupCache) Invalidate(coll collations.ID, ids ...sqltypes.Value) {
+	if lc == nil {
+		return
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.generation++
+	for _, id := range ids {
+		if key, ok := lookupCacheKey(coll, id); ok {
+			lc.entries.Delete(key)
+		}
+	}
+	lc.invalidations.Add(int64(len(ids)))
+}
+
+// lookupCacheKey returns the key of id in the cache. Text values are hashed
+// with coll and numbers are hashed as decimals, so that the values that the
+// lookup query considers equal share their key. NULLs, and the values that
+// cannot be hashed, are not cached.
+func lookupCacheKey(coll collations.ID, id sqltypes.Value) (string, bool) {
+	if id.IsNull() {
+		return "", false
+	}
+	coerceTo := id.Type()
+	switch {
+	case id.IsText():
+		coerceTo = sqltypes.VarChar
+	case id.IsBinary():
+		coerceTo = sqltypes.VarBinary
+	case id.IsIntegral() || id.IsFloat() || id.IsDecimal():
+		coerceTo = sqltypes.Decimal
+	}
+	hasher := vthash.New()
+	if err := evalengine.NullsafeHashcode128(&hasher, id, coll, coerceTo, 0, nil); err != nil {
+		return "", false
+	}
+	hash := hasher.Sum128()
+	return string(hash[:]), true
+}
+
+// Len returns the number of values in the cache.
+func (lc *LookupCache) Len() int {
+	return lc.entries.Len()
+}
+
+// Hits returns the number of values that were served from the cache.
+func (lc *LookupCache) Hits() int64 {
+	return lc.hits.Load()
+}
+
+// Misses returns the number of values that had to be looked up.
+func (lc *LookupCache) Misses() int64 {
+	return lc.misses.Load()
+}
+
+// Invalidations returns the number of values that were invalidated
+// by writes to the lookup table.
+func (lc *LookupCache) Invalidations() int64 {
+	return lc.invalidations.Load()
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+)
+
+var testCollation = collations.MySQL8().DefaultConnectionCharset()
+
+// fakeLookup returns one row per id, holding the id, and records the ids
+// it was called with.
+type fakeLookup struct {
+	calls [][]sqltypes.Value
+	err   error
+}
+
+func (fl *fakeLookup) lookup(ids []sqltypes.Value) ([]*sqltypes.Result, error) {
+	fl.calls = append(fl.calls, ids)
+	if fl.err != nil {
+		return nil, fl.err
+	}
+	results := make([]*sqltypes.Result, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, &sqltypes.Result{Rows: [][]sqltypes.Value{{id}}})
+	}
+	return results, nil
+}
+
+func TestLookupCache(t *testing.T) {
+	lc := NewLookupCache(2, time.Hour)
+	fl := &fakeLookup{}
+
+	ids := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2)}
+	results, err := lc.Lookup(testCollation, ids, fl.lookup)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, ids, fl.calls[0])
+
+	// The second lookup is served from the cache and keeps the order of ids.
+	results, err = lc.Lookup(testCollation, []sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewInt64(1)}, fl.lookup)
+	require.NoError(t, err)
+	assert.Len(t, fl.calls, 1)
+	assert.Equal(t, sqltypes.NewInt64(2), results[0].Rows[0][0])
+	assert.Equal(t, sqltypes.NewInt64(1), results[1].Rows[0][0])
+	assert.EqualValues(t, 2, lc.Hits())
+	assert.EqualValues(t, 2, lc.Misses())
+
+	// The cache is bounded: adding a third value evicts the least recently used one.
+	_, err = lc.Lookup(testCollation, []sqltypes.Value{sqltypes.NewInt64(3)}, fl.lookup)
+	require.NoError(t, err)
+	assert.Equal(t, 2, lc.Len())
+	_, err = lc.Lookup(testCollation, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2)}, fl.lookup)
+	require.NoError(t, err)
+	assert.Equal(t, []sqltypes.Value{sqltypes.NewInt64(2)}, fl.calls[2])
+
+	// Invalidated values are looked up again.
+	lc.Invalidate(testCollation, sqltypes.NewInt64(1))
+	_, err = lc.Lookup(testCollation, []sqltypes.Value{sqltypes.NewInt64(1)}, fl.lookup)
+	require.NoError(t, err)
+	assert.Equal(t, []sqltypes.Value{sqltypes.NewInt64(1)}, fl.calls[3])
+	assert.EqualValues(t, 1, lc.Invalidations())
+
+	// Errors are returned and not cached.
+	fl.err = errors.New("lookup failed")
+	_, err = lc.Lookup(testCollation, []sqltypes.Value{sqltypes.NewInt64(4)}, fl.lookup)
+	require.EqualError(t, err, "lookup failed")
+}
+
+func TestLookupCacheExpiry(t *testing.T) {
+	lc := NewLookupCache(10, time.Millisecond)
+	fl := &fakeLookup{}
+
+	ids := []sqltypes.Value{sqltypes.NewInt64(1)}
+	_, err := lc.Lookup(testCollation, ids, fl.lookup)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = lc.Lookup(testCollation, ids, fl.lookup)
+	require.NoError(t, err)
+	assert.Len(t, fl.calls, 2)
+}
+
+func TestLookupCacheInvalidateDuringLookup(t *testing.T) {
+	lc := NewLookupCache(10, time.Hour)
+	fl := &fakeLookup{}
+
+	// A lookup that races with a write must not cache what it read before it.
+	_, err := lc.Lookup(testCollation, []sqltypes.Value{sqltypes.NewInt64(1)}, func(ids []sqltypes.Value) ([]*sqltypes.Result, error) {
+		lc.Invalidate(testCollation, sqltypes.NewInt64(1))
+		return fl.lookup(ids)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, lc.Len())
+}
+
+func TestLookupCacheCollation(t *testing.T) {
+	lc := NewLookupCache(10, time.Hour)
+	fl := &fakeLookup{}
+
+	// Values that are equal in the collation of the connection share their entry.
+	_, err := lc.Lookup(testCollation, []sqltypes.Value{sqltypes.NewVarChar("abc")}, fl.lookup)
+	require.NoError(t, err)
+	_, err = lc.Lookup(testCollation, []sqltypes.Value{sqltypes.NewVarChar("ABC"), sqltypes.NewVarChar("Abc")}, fl.lookup)
+	require.NoError(t, err)
+	assert.Len(t, fl.calls, 1)
+
+	lc.Invalidate(testCollation, sqltypes.NewVarChar("Abc"))
+	assert.Equal(t, 0, lc.Len())
+
+	// Numbers share their entry whatever their type.
+	_, err = lc.Lookup(testCollation, []sqltypes.Value{sqltypes.NewInt64(1)}, fl.lookup)
+	require.NoError(t, err)
+	_, err = lc.Lookup(testCollation, []sqltypes.Value{sqltypes.NewUint64(1), sqltypes.NewDecimal("1.0")}, fl.lookup)
+	require.NoError(t, err)
+	assert.Len(t, fl.calls, 2)
+
+	// Binary strings are compared byte by byte.
+	_, err = lc.Lookup(testCollation, []sqltypes.Value{sqltypes.NewVarBinary("abc"), sqltypes.NewVarBinary("ABC")}, fl.lookup)
+	require.NoError(t, err)
+	assert.Equal(t, []sqltypes.Value{sqltypes.NewVarBinary("abc"), sqltypes.NewVarBinary("ABC")}, fl.calls[2])
+}
+
+func TestLookupCacheNil(t *testing.T) {
+	var lc *LookupCache
+	fl := &fakeLookup{}
+
+	ids := []sqltypes.Value{sqltypes.NewInt64(1)}
+	for range 2 {
+		_, err := lc.Lookup(testCollation, ids, fl.lookup)
+		require.NoError(t, err)
+	}
+	assert.Len(t, fl.calls, 2)
+	lc.Invalidate(testCollation, sqltypes.NewInt64(1))
+}
+
+func TestLookupCacheNullValues(t *testing.T) {
+	lc := NewLookupCache(10, time.Hour)
+	fl := &fakeLookup{}
+
+	ids := []sqltypes.Value{sqltypes.NULL}
+	for range 2 {
+		_, err := lc.Lookup(testCollation, ids, fl.lookup)
+		require.NoError(t, err)
+	}
+	assert.Len(t, fl.calls, 2)
+	assert.Equal(t, 0, lc.Len())
+}
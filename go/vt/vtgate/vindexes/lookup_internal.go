@@ -23,6 +23,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -46,6 +47,10 @@ const (
 	lookupInternalParamIgnoreNulls = "ignore_nulls"
 	lookupInternalParamBatchLookup = "batch_lookup"
 	lookupInternalParamReadLock    = "read_lock"
+	lookupInternalParamCacheSize   = "cache_size"
+	lookupInternalParamCacheTTL    = "cache_ttl"
+
+	defaultLookupCacheTTL = 10 * time.Second
 )
 
 var (
@@ -71,6 +76,8 @@ var (
 		lookupInternalParamIgnoreNulls,
 		lookupInternalParamBatchLookup,
 		lookupInternalParamReadLock,
+		lookupInternalParamCacheSize,
+		lookupInternalParamCacheTTL,
 	}
 )
 
@@ -85,7 +92,12 @@ type lookupInternal struct {
 	IgnoreNulls             bool     `json:"ignore_nulls,omitempty"`
 	BatchLookup             bool     `json:"batch_lookup,omitempty"`
 	ReadLock                string   `json:"read_lock,omitempty"`
+	CacheSize               int64    `json:"cache_size,omitempty"`
+	CacheTTL                string   `json:"cache_ttl,omitempty"`
 	sel, selTxDml, ver, del string   // sel: map query, ver: verify query, del: delete query
+
+	// cache holds the results of the lookup query, if CacheSize is set.
+	cache *LookupCache
 }
 
 func (lkp *lookupInternal) Init(lookupQueryParams map[string]string, autocommit, upsert, multiShardAutocommit bool) error {
@@ -112,6 +124,9 @@ func (lkp *lookupInternal) Init(lookupQueryParams map[string]string, autocommit,
 		}
 		lkp.ReadLock = readLock
 	}
+	if err := lkp.initCache(lookupQueryParams); err != nil {
+		return err
+	}
 
 	lkp.Autocommit = autocommit
 	lkp.Upsert = upsert
@@ -138,11 +153,50 @@ func (lkp *lookupInternal) Init(lookupQueryParams map[string]string, autocommit,
 	return nil
 }
 
+func (lkp *lookupInternal) initCache(lookupQueryParams map[string]string) error {
+	if size, ok := lookupQueryParams[lookupInternalParamCacheSize]; ok {
+		var err error
+		lkp.CacheSize, err = strconv.ParseInt(size, 10, 64)
+		if err != nil || lkp.CacheSize < 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid %s value: %s", lookupInternalParamCacheSize, size)
+		}
+	}
+	ttl := defaultLookupCacheTTL
+	if val, ok := lookupQueryParams[lookupInternalParamCacheTTL]; ok {
+		var err error
+		ttl, err = time.ParseDuration(val)
+		if err != nil || ttl <= 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid %s value: %s", lookupInternalParamCacheTTL, val)
+		}
+		lkp.CacheTTL = val
+	}
+	if lkp.CacheSize > 0 {
+		lkp.cache = NewLookupCache(lkp.CacheSize, ttl)
+	}
+	return nil
+}
+
+// cacheFor returns the cache to use for a lookup in the session of vcursor.
+// Lookups done inside a transaction bypass the cache, so that they see the
+// writes of the transaction and take the locks they are expected to take.
+func (lkp *lookupInternal) cacheFor(vcursor VCursor) *LookupCache {
+	if lkp.cache == nil || vcursor.InTransaction() {
+		return nil
+	}
+	return lkp.cache
+}
+
 // Lookup performs a lookup for the ids.
 func (lkp *lookupInternal) Lookup(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, co vtgatepb.CommitOrder) ([]*sqltypes.Result, error) {
 	if vcursor == nil {
 		return nil, vterrors.VT13001("cannot perform lookup: no vcursor provided")
 	}
+	return lkp.cacheFor(vcursor).Lookup(vcursor.ConnCollation(), ids, func(ids []sqltypes.Value) ([]*sqltypes.Result, error) {
+		return lkp.lookup(ctx, vcursor, ids, co)
+	})
+}
+
+func (lkp *lookupInternal) lookup(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, co vtgatepb.CommitOrder) ([]*sqltypes.Result, error) {
 	results := make([]*sqltypes.Result, 0, len(ids))
 	if lkp.Autocommit {
 		co = vtgatepb.CommitOrder_AUTOCOMMIT
@@ -306,6 +360,7 @@ nextRow:
 		return vterrors.VT03030(lkp.FromColumns, len(trimmedRowsCols[0]))
 	}
 	sort.Sort(&sorter{rowsColValues: trimmedRowsCols, toValues: trimmedToValues})
+	defer lkp.invalidate(vcursor, trimmedRowsCols)()
 
 	insStmt := "insert"
 	if lkp.MultiShardAutocommit {
@@ -381,6 +436,7 @@ func (lkp *lookupInternal) Delete(ctx context.Context, vcursor VCursor, rowsColV
 	if len(rowsColValues[0]) != len(lkp.FromColumns) {
 		return vterrors.VT03030(lkp.FromColumns, len(rowsColValues[0]))
 	}
+	defer lkp.invalidate(vcursor, rowsColValues)()
 	for _, column := range rowsColValues {
 		bindVars := make(map[string]*querypb.BindVariable, len(rowsColValues))
 		for colIdx, columnValue := range column {
@@ -403,6 +459,32 @@ func (lkp *lookupInternal) Update(ctx context.Context, vcursor VCursor, oldValue
 	return lkp.Create(ctx, vcursor, [][]sqltypes.Value{newValues}, []sqltypes.Value{toValue}, false /* ignoreMode */)
 }
 
+// invalidate removes the rows that are about to be written from the cache,
+// and returns the function to call once they have been written. Lookups
+// from other sessions can cache the rows again until the write is committed,
+// so they are invalidated again after the write, and once more when the
+// transaction of vcursor is over. Only the first column is looked up, so
+// it's the only one cached.
+func (lkp *lookupInternal) invalidate(vcursor VCursor, rowsColValues [][]sqltypes.Value) func() {
+	if lkp.cache == nil {
+		return func() {}
+	}
+	ids := make([]sqltypes.Value, 0, len(rowsColValues))
+	for _, row := range rowsColValues {
+		ids = append(ids, row[0])
+	}
+	coll := vcursor.ConnCollation()
+	lkp.cache.Invalidate(coll, ids...)
+	return func() {
+		lkp.cache.Invalidate(coll, ids...)
+		if notifier, ok := vcursor.(TransactionEndNotifier); ok && vcursor.InTransaction() {
+			notifier.OnTransactionEnd(func() {
+				lkp.cache.Invalidate(coll, ids...)
+			})
+		}
+	}
+}
+
 func (lkp *lookupInternal) initDelStmt() string {
 	var delBuffer strings.Builder
 	fmt.Fprintf(&delBuffer, "delete from %s where ", lkp.Table)
@@ -52,6 +52,9 @@ type vcursor struct {
 	autocommits int
 	pre, post   int
 	keys        []sqltypes.Value
+
+	inTransaction bool
+	txEndHooks    []func()
 }
 
 func (vc *vcursor) LookupRowLockShardSession() vtgatepb.CommitOrder {
@@ -62,6 +65,14 @@ func (vc *vcursor) InTransactionAndIsDML() bool {
 	return false
 }
 
+func (vc *vcursor) InTransaction() bool {
+	return vc.inTransaction
+}
+
+func (vc *vcursor) OnTransactionEnd(fn func()) {
+	vc.txEndHooks = append(vc.txEndHooks, fn)
+}
+
 func (vc *vcursor) Execute(ctx context.Context, method string, query string, bindvars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error) {
 	switch co {
 	case vtgatepb.CommitOrder_PRE:
@@ -287,6 +298,24 @@ func testLookupCreateVindexInternalCases(t *testing.T, testCaseF func(string, ma
 			vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid read_lock value: unknown"),
 			nil,
 		),
+		testCaseF(
+			"cache_size and cache_ttl",
+			map[string]string{"cache_size": "1000", "cache_ttl": "1m"},
+			nil,
+			nil,
+		),
+		testCaseF(
+			"cache_size reject not a number",
+			map[string]string{"cache_size": "hello"},
+			vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid cache_size value: hello"),
+			nil,
+		),
+		testCaseF(
+			"cache_ttl reject not a duration",
+			map[string]string{"cache_ttl": "hello"},
+			vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid cache_ttl value: hello"),
+			nil,
+		),
 		testCaseF(
 			"ignore_nulls reject not bool",
 			map[string]string{"ignore_nulls": "hello"},
@@ -411,6 +440,75 @@ func TestLookupNonUniqueMap(t *testing.T) {
 	require.EqualError(t, err, "lookup.Map: execute failed")
 }
 
+func TestLookupNonUniqueMapCache(t *testing.T) {
+	lnu, err := CreateVindex("lookup", "lookup", map[string]string{
+		"table":      "t",
+		"from":       "fromc",
+		"to":         "toc",
+		"cache_size": "10",
+	})
+	require.NoError(t, err)
+	lc := lnu.(LookupCaching).LookupCache()
+	require.NotNil(t, lc)
+	vc := &vcursor{numRows: 2}
+
+	want := []key.ShardDestination{
+		key.DestinationKeyspaceIDs([][]byte{
+			[]byte("1"),
+			[]byte("2"),
+		}),
+		key.DestinationKeyspaceIDs([][]byte{
+			[]byte("1"),
+			[]byte("2"),
+		}),
+	}
+	for range 2 {
+		got, err := lnu.(SingleColumn).Map(t.Context(), vc, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2)})
+		require.NoError(t, err)
+		utils.MustMatch(t, want, got)
+	}
+	assert.Len(t, vc.queries, 1)
+	assert.EqualValues(t, 2, lc.Hits())
+	assert.EqualValues(t, 2, lc.Misses())
+
+	// Only the values that are not cached are looked up.
+	_, err = lnu.(SingleColumn).Map(t.Context(), vc, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(3)})
+	require.NoError(t, err)
+	require.Len(t, vc.queries, 2)
+	vars, err := sqltypes.BuildBindVariable([]any{sqltypes.NewInt64(3)})
+	require.NoError(t, err)
+	utils.MustMatch(t, vars, vc.queries[1].BindVariables["fromc"])
+
+	// Writes to the lookup table invalidate the values they touch.
+	err = lnu.(Lookup).Delete(t.Context(), vc, [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, []byte("1"))
+	require.NoError(t, err)
+	err = lnu.(Lookup).Create(t.Context(), vc, [][]sqltypes.Value{{sqltypes.NewInt64(2)}}, [][]byte{[]byte("1")}, false /* ignoreMode */)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, lc.Invalidations(), "values are invalidated before and after they are written")
+	assert.Equal(t, 1, lc.Len())
+	assert.Empty(t, vc.txEndHooks)
+
+	// Lookups inside of a transaction bypass the cache.
+	vc.queries = nil
+	vc.inTransaction = true
+	_, err = lnu.(SingleColumn).Map(t.Context(), vc, []sqltypes.Value{sqltypes.NewInt64(3)})
+	require.NoError(t, err)
+	assert.Len(t, vc.queries, 1)
+
+	// Writes done inside of a transaction are invalidated again once it's over,
+	// since other sessions can cache the rows until the write is committed.
+	err = lnu.(Lookup).Delete(t.Context(), vc, [][]sqltypes.Value{{sqltypes.NewInt64(3)}}, []byte("1"))
+	require.NoError(t, err)
+	require.Len(t, vc.txEndHooks, 1)
+	assert.Equal(t, 0, lc.Len())
+	other := &vcursor{numRows: 1}
+	_, err = lnu.(SingleColumn).Map(t.Context(), other, []sqltypes.Value{sqltypes.NewInt64(3)})
+	require.NoError(t, err)
+	assert.Equal(t, 1, lc.Len())
+	vc.txEndHooks[0]()
+	assert.Equal(t, 0, lc.Len())
+}
+
 func TestLookupNonUniqueMapAutocommit(t *testing.T) {
 	vindex, err := CreateVindex("lookup", "lookup", map[string]string{
 		"table":      "t",
@@ -0,0 +1,442 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/collations/colldata"
+	"vitess.io/vitess/go/mysql/datetime"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	rangePartitionParamPartitions = "partitions"
+	rangePartitionParamType       = "type"
+	rangePartitionParamCollation  = "collation"
+
+	rangePartitionTypeInt      = "int"
+	rangePartitionTypeUint     = "uint"
+	rangePartitionTypeDatetime = "datetime"
+	rangePartitionTypeString   = "string"
+
+	rangePartitionDefaultCollation = "utf8mb4_0900_ai_ci"
+)
+
+var (
+	_ SingleColumn    = (*RangePartition)(nil)
+	_ Sequential      = (*RangePartition)(nil)
+	_ ParamValidating = (*RangePartition)(nil)
+
+	rangePartitionParams = []string{
+		rangePartitionParamPartitions,
+		rangePartitionParamType,
+		rangePartitionParamCollation,
+	}
+)
+
+// RangePartition maps ordered ranges of values to keyspace id ranges, which
+// are configured in the vschema as a JSON list in the "partitions" param:
+//
+//	[{"to": "10000000", "key_range": "-40"},
+//	 {"from": "10000000", "to": "20000000", "key_range": "40-80"},
+//	 {"from": "20000000", "key_range": "80-"}]
+//
+// "from" is inclusive and "to" is exclusive. The first partition may omit
+// "from" and the last one may omit "to" to be unbounded. Values that don't
+// fall into any partition don't map to any keyspace id.
+//
+// The "type" param tells how values are ordered: "int" (default), "uint",
+// "datetime" (for DATE, DATETIME and TIMESTAMP columns) or "string", which
+// compares values with the "collation" param (utf8mb4_0900_ai_ci by default).
+//
+// Within a partition, values are spread linearly over the keyspace ids of its
+// key range, so that splitting the shard that holds a partition splits the
+// values of the partition proportionally, and splitting a partition into two
+// partitions whose key ranges are the halves of the original key range, at the
+// matching value, keeps all the rows in place.
+type RangePartition struct {
+	name          string
+	typ           string
+	coll          colldata.Collation
+	partitions    []rangePartition
+	unknownParams []string
+}
+
+// rangePartition is a value range of a RangePartition vindex, with its
+// boundaries encoded by RangePartition.encode.
+type rangePartition struct {
+	from, to []byte
+	// firstPos and lastPos are the positions of the first and last value of
+	// the partition.
+	firstPos, lastPos uint64
+	// firstKsid and lastKsid are the first and last keyspace id of the key
+	// range of the partition.
+	firstKsid, lastKsid uint64
+}
+
+type rangePartitionJSON struct {
+	From     any    `json:"from"`
+	To       any    `json:"to"`
+	KeyRange string `json:"key_range"`
+}
+
+// newRangePartition creates a RangePartition vindex.
+func newRangePartition(name string, m map[string]string) (Vindex, error) {
+	rp := &RangePartition{
+		name:          name,
+		typ:           rangePartitionTypeInt,
+		unknownParams: FindUnknownParams(m, rangePartitionParams),
+	}
+	if typ, ok := m[rangePartitionParamType]; ok {
+		switch typ {
+		case rangePartitionTypeInt, rangePartitionTypeUint, rangePartitionTypeDatetime, rangePartitionTypeString:
+			rp.typ = typ
+		default:
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range_partition: invalid type %q, must be one of int, uint, datetime or string", typ)
+		}
+	}
+	if collName, ok := m[rangePartitionParamCollation]; ok || rp.typ == rangePartitionTypeString {
+		if rp.typ != rangePartitionTypeString {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range_partition: collation is only valid for the string type")
+		}
+		if !ok {
+			collName = rangePartitionDefaultCollation
+		}
+		rp.coll = colldata.Lookup(collations.MySQL8().LookupByName(collName))
+		if rp.coll == nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range_partition: unknown collation %q", collName)
+		}
+	}
+
+	partitionsJSON, ok := m[rangePartitionParamPartitions]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range_partition: missing partitions param")
+	}
+	dec := json.NewDecoder(strings.NewReader(partitionsJSON))
+	dec.UseNumber()
+	var partitions []rangePartitionJSON
+	if err := dec.Decode(&partitions); err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range_partition: invalid partitions: %v", err)
+	}
+	if len(partitions) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range_partition: no partitions")
+	}
+	for i, p := range partitions {
+		part, err := rp.buildPartition(p, i == 0, i == len(partitions)-1)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range_partition: partition %d: %v", i, err)
+		}
+		if i > 0 {
+			prev := rp.partitions[i-1]
+			if rp.compare(prev.to, part.from) > 0 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range_partition: partition %d overlaps with partition %d, partitions must be in order and must not overlap", i, i-1)
+			}
+			if prev.lastKsid >= part.firstKsid {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range_partition: key range of partition %d overlaps with partition %d, key ranges must be in the same order as the partitions and must not overlap", i, i-1)
+			}
+		}
+		rp.partitions = append(rp.partitions, part)
+	}
+	return rp, nil
+}
+
+func (rp *RangePartition) buildPartition(p rangePartitionJSON, first, last bool) (part rangePartition, err error) {
+	boundary := func(name string, v any, allowUnbounded bool) ([]byte, error) {
+		var str string
+		switch v := v.(type) {
+		case nil:
+			if !allowUnbounded {
+				return nil, fmt.Errorf("missing %s", name)
+			}
+			return nil, nil
+		case string:
+			str = v
+		case json.Number:
+			str = v.String()
+		default:
+			return nil, fmt.Errorf("invalid %s: %v", name, v)
+		}
+		b, err := rp.encode(sqltypes.NewVarChar(str))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", name, str, err)
+		}
+		return b, nil
+	}
+	if part.from, err = boundary("from", p.From, first); err != nil {
+		return part, err
+	}
+	if part.to, err = boundary("to", p.To, last); err != nil {
+		return part, err
+	}
+	if part.from != nil && part.to != nil && rp.compare(part.from, part.to) >= 0 {
+		return part, fmt.Errorf("from must be smaller than to")
+	}
+
+	part.lastPos = math.MaxUint64
+	if part.from != nil {
+		part.firstPos = rp.position(part.from)
+	}
+	if part.to != nil {
+		toPos := rp.position(part.to)
+		part.lastPos = part.firstPos
+		if toPos > part.firstPos {
+			part.lastPos = toPos - 1
+		}
+	}
+
+	keyRanges, err := key.ParseShardingSpec(p.KeyRange)
+	if err != nil || len(keyRanges) != 1 {
+		return part, fmt.Errorf("invalid key_range %q", p.KeyRange)
+	}
+	if len(keyRanges[0].Start) > 8 || len(keyRanges[0].End) > 8 {
+		return part, fmt.Errorf("key_range %q is longer than 8 bytes", p.KeyRange)
+	}
+	part.firstKsid = bytesToUint64(keyRanges[0].Start)
+	part.lastKsid = math.MaxUint64
+	if len(keyRanges[0].End) > 0 {
+		part.lastKsid = bytesToUint64(keyRanges[0].End) - 1
+	}
+	return part, nil
+}
+
+// String returns the name of the vindex.
+func (rp *RangePartition) String() string {
+	return rp.name
+}
+
+// Cost returns the cost of this vindex as 1.
+func (*RangePartition) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (*RangePartition) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (*RangePartition) NeedsVCursor() bool {
+	return false
+}
+
+// Map can map ids to key.ShardDestination objects.
+func (rp *RangePartition) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.ShardDestination, error) {
+	out := make([]key.ShardDestination, 0, len(ids))
+	for _, id := range ids {
+		ksid, ok, err := rp.keyspaceID(id)
+		if err != nil || !ok {
+			out = append(out, key.DestinationNone{})
+			continue
+		}
+		out = append(out, key.DestinationKeyspaceID(ksid))
+	}
+	return out, nil
+}
+
+// Verify returns true if ids maps to ksids.
+func (rp *RangePartition) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	out := make([]bool, 0, len(ids))
+	for i, id := range ids {
+		ksid, ok, err := rp.keyspaceID(id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ok && bytes.Equal(ksid, ksids[i]))
+	}
+	return out, nil
+}
+
+// RangeMap maps the values between startId and endId, both inclusive, to the
+// key range that holds them.
+func (rp *RangePartition) RangeMap(ctx context.Context, vcursor VCursor, startId sqltypes.Value, endId sqltypes.Value) ([]key.ShardDestination, error) {
+	start, err := rp.encode(startId)
+	if err != nil {
+		return nil, err
+	}
+	end, err := rp.encode(endId)
+	if err != nil {
+		return nil, err
+	}
+
+	var first, last uint64
+	switch i, in := rp.find(start); {
+	case i < 0:
+		first = rp.partitions[0].firstKsid
+	case in:
+		first = rp.partitions[i].keyspaceID(rp.position(start))
+	case i+1 < len(rp.partitions):
+		first = rp.partitions[i+1].firstKsid
+	default:
+		return []key.ShardDestination{key.DestinationNone{}}, nil
+	}
+	switch i, in := rp.find(end); {
+	case i < 0:
+		return []key.ShardDestination{key.DestinationNone{}}, nil
+	case in:
+		last = rp.partitions[i].keyspaceID(rp.position(end))
+	default:
+		last = rp.partitions[i].lastKsid
+	}
+	if first > last {
+		return []key.ShardDestination{key.DestinationNone{}}, nil
+	}
+
+	keyRange := key.NewKeyRange(nil, nil)
+	if first != 0 {
+		keyRange.Start = uint64ToBytes(first)
+	}
+	if last != math.MaxUint64 {
+		keyRange.End = uint64ToBytes(last + 1)
+	}
+	return []key.ShardDestination{&key.DestinationKeyRange{KeyRange: keyRange}}, nil
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (rp *RangePartition) UnknownParams() []string {
+	return rp.unknownParams
+}
+
+// keyspaceID returns the keyspace id of id, or false if id doesn't fall into
+// any partition.
+func (rp *RangePartition) keyspaceID(id sqltypes.Value) ([]byte, bool, error) {
+	v, err := rp.encode(id)
+	if err != nil {
+		return nil, false, err
+	}
+	i, in := rp.find(v)
+	if !in {
+		return nil, false, nil
+	}
+	return uint64ToBytes(rp.partitions[i].keyspaceID(rp.position(v))), true, nil
+}
+
+// find returns the index of the last partition that starts at or before the
+// encoded value v, and whether v falls into it.
+func (rp *RangePartition) find(v []byte) (int, bool) {
+	i := sort.Search(len(rp.partitions), func(i int) bool {
+		from := rp.partitions[i].from
+		return from != nil && rp.compare(from, v) > 0
+	}) - 1
+	if i < 0 {
+		return i, false
+	}
+	to := rp.partitions[i].to
+	return i, to == nil || rp.compare(v, to) < 0
+}
+
+// encode returns the representation of id that is used to compare it with
+// the boundaries of the partitions. Numbers and datetimes are encoded as big
+// endian uint64 that sort in the same order as the values, strings are kept
+// as is and compared with the collation of the vindex.
+func (rp *RangePartition) encode(id sqltypes.Value) ([]byte, error) {
+	if id.IsNull() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range_partition: NULL values are not mapped")
+	}
+	switch rp.typ {
+	case rangePartitionTypeInt:
+		v, err := id.ToCastInt64()
+		if err != nil {
+			return nil, err
+		}
+		return uint64ToBytes(uint64(v) ^ (1 << 63)), nil
+	case rangePartitionTypeUint:
+		v, err := id.ToCastUint64()
+		if err != nil {
+			return nil, err
+		}
+		return uint64ToBytes(v), nil
+	case rangePartitionTypeDatetime:
+		dt, _, ok := datetime.ParseDateTime(id.ToString(), -1)
+		if !ok {
+			d, ok := datetime.ParseDate(id.ToString())
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range_partition: cannot parse datetime from %q", id.ToString())
+			}
+			dt = datetime.DateTime{Date: d}
+		}
+		return uint64ToBytes(uint64(dt.ToSeconds())*1e6 + uint64(dt.Time.Nanosecond()/1e3)), nil
+	default:
+		return id.Raw(), nil
+	}
+}
+
+// compare compares two encoded values.
+func (rp *RangePartition) compare(a, b []byte) int {
+	if rp.coll != nil {
+		return rp.coll.Collate(a, b, false)
+	}
+	return bytes.Compare(a, b)
+}
+
+// position maps an encoded value to a uint64 that never sorts before the
+// position of smaller values, which is used to spread the values of a
+// partition over its key range.
+func (rp *RangePartition) position(v []byte) uint64 {
+	if rp.coll != nil {
+		v = rp.coll.WeightString(make([]byte, 0, 8), v, 0)
+	}
+	return bytesToUint64(v)
+}
+
+// keyspaceID interpolates the position of a value of the partition into its
+// key range.
+func (part *rangePartition) keyspaceID(pos uint64) uint64 {
+	offset := min(pos-part.firstPos, part.lastPos-part.firstPos)
+	width := part.lastPos - part.firstPos  // number of positions minus one
+	span := part.lastKsid - part.firstKsid // number of keyspace ids minus one
+
+	// firstKsid + offset * (span+1) / (width+1), in 128 bits.
+	hi, lo := bits.Mul64(offset, span)
+	lo, carry := bits.Add64(lo, offset, 0)
+	hi += carry
+	if width == math.MaxUint64 {
+		return part.firstKsid + hi
+	}
+	q, _ := bits.Div64(hi, lo, width+1)
+	return part.firstKsid + q
+}
+
+// bytesToUint64 returns the first 8 bytes of b as a big endian uint64, padded
+// with zeroes.
+func bytesToUint64(b []byte) uint64 {
+	var buf [8]byte
+	copy(buf[:], b)
+	return binary.BigEndian.Uint64(buf[:])
+}
+
+func uint64ToBytes(v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return buf[:]
+}
+
+func init() {
+	Register("range_partition", newRangePartition)
+}
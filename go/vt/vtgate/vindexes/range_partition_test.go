@@ -0,0 +1,333 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+)
+
+const rangePartitionTestPartitions = `[
+	{"to": 100, "key_range": "-40"},
+	{"from": 100, "to": 200, "key_range": "40-80"},
+	{"from": 300, "key_range": "c0-"}
+]`
+
+func createRangePartition(t *testing.T, params map[string]string) *RangePartition {
+	t.Helper()
+	vindex, err := CreateVindex("range_partition", "range_partition", params)
+	require.NoError(t, err)
+	return vindex.(*RangePartition)
+}
+
+func rangePartitionCreateVindexTestCase(
+	testName string,
+	vindexParams map[string]string,
+	expectErr error,
+	expectUnknownParams []string,
+) createVindexTestCase {
+	return createVindexTestCase{
+		testName: testName,
+
+		vindexType:   "range_partition",
+		vindexName:   "range_partition",
+		vindexParams: vindexParams,
+
+		expectCost:          1,
+		expectErr:           expectErr,
+		expectIsUnique:      true,
+		expectNeedsVCursor:  false,
+		expectString:        "range_partition",
+		expectUnknownParams: expectUnknownParams,
+	}
+}
+
+func TestRangePartitionCreateVindex(t *testing.T) {
+	cases := []createVindexTestCase{
+		rangePartitionCreateVindexTestCase(
+			"no params",
+			nil,
+			errors.New("range_partition: missing partitions param"),
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"partitions",
+			map[string]string{"partitions": rangePartitionTestPartitions},
+			nil,
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"unknown params",
+			map[string]string{"partitions": rangePartitionTestPartitions, "hello": "world"},
+			nil,
+			[]string{"hello"},
+		),
+		rangePartitionCreateVindexTestCase(
+			"string partitions with collation",
+			map[string]string{"type": "string", "collation": "latin1_swedish_ci", "partitions": `[{"to": "m", "key_range": "-80"}, {"from": "m", "key_range": "80-"}]`},
+			nil,
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"invalid type",
+			map[string]string{"type": "float", "partitions": rangePartitionTestPartitions},
+			errors.New(`range_partition: invalid type "float", must be one of int, uint, datetime or string`),
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"collation for int",
+			map[string]string{"collation": "binary", "partitions": rangePartitionTestPartitions},
+			errors.New("range_partition: collation is only valid for the string type"),
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"unknown collation",
+			map[string]string{"type": "string", "collation": "nope", "partitions": rangePartitionTestPartitions},
+			errors.New(`range_partition: unknown collation "nope"`),
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"invalid json",
+			map[string]string{"partitions": `{`},
+			errors.New("range_partition: invalid partitions: unexpected EOF"),
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"no partitions",
+			map[string]string{"partitions": `[]`},
+			errors.New("range_partition: no partitions"),
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"invalid boundary",
+			map[string]string{"partitions": `[{"from": "abc", "key_range": "-"}]`},
+			errors.New(`range_partition: partition 0: invalid from "abc": cannot parse int64 from "abc"`),
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"unbounded partition in the middle",
+			map[string]string{"partitions": `[{"to": 10, "key_range": "-40"}, {"from": 10, "key_range": "40-80"}, {"from": 20, "key_range": "80-"}]`},
+			errors.New("range_partition: partition 1: missing to"),
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"empty partition",
+			map[string]string{"partitions": `[{"from": 10, "to": 10, "key_range": "-"}]`},
+			errors.New("range_partition: partition 0: from must be smaller than to"),
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"overlapping partitions",
+			map[string]string{"partitions": `[{"from": 0, "to": 20, "key_range": "-40"}, {"from": 10, "to": 30, "key_range": "40-80"}]`},
+			errors.New("range_partition: partition 1 overlaps with partition 0, partitions must be in order and must not overlap"),
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"overlapping key ranges",
+			map[string]string{"partitions": `[{"from": 0, "to": 10, "key_range": "-80"}, {"from": 10, "to": 20, "key_range": "40-c0"}]`},
+			errors.New("range_partition: key range of partition 1 overlaps with partition 0, key ranges must be in the same order as the partitions and must not overlap"),
+			nil,
+		),
+		rangePartitionCreateVindexTestCase(
+			"invalid key range",
+			map[string]string{"partitions": `[{"from": 0, "to": 10, "key_range": "40-80-c0"}]`},
+			errors.New(`range_partition: partition 0: invalid key_range "40-80-c0"`),
+			nil,
+		),
+	}
+
+	testCreateVindexes(t, cases)
+}
+
+func TestRangePartitionMap(t *testing.T) {
+	rp := createRangePartition(t, map[string]string{"partitions": rangePartitionTestPartitions})
+	got, err := rp.Map(t.Context(), nil, []sqltypes.Value{
+		sqltypes.NewInt64(100),
+		sqltypes.NewInt64(150),
+		sqltypes.NewVarChar("150"),
+		sqltypes.NewInt64(250),
+		sqltypes.NewInt64(300),
+		sqltypes.NewInt64(-5),
+		sqltypes.NewFloat64(1.1),
+		sqltypes.NULL,
+	})
+	require.NoError(t, err)
+	want := []key.ShardDestination{
+		key.DestinationKeyspaceID([]byte("\x40\x00\x00\x00\x00\x00\x00\x00")),
+		key.DestinationKeyspaceID([]byte("\x60\x00\x00\x00\x00\x00\x00\x00")),
+		key.DestinationKeyspaceID([]byte("\x60\x00\x00\x00\x00\x00\x00\x00")),
+		key.DestinationNone{},
+		key.DestinationKeyspaceID([]byte("\xc0\x00\x00\x00\x00\x00\x00\x00")),
+		got[5],
+		key.DestinationNone{},
+		key.DestinationNone{},
+	}
+	assert.Equal(t, want, got)
+	assert.True(t, key.KeyRangeContains(key.NewKeyRange(nil, []byte{0x40}), got[5].(key.DestinationKeyspaceID)))
+
+	// values are in the same order as their keyspace ids
+	var prev []byte
+	for i := int64(-1000); i < 1000; i++ {
+		got, err := rp.Map(t.Context(), nil, []sqltypes.Value{sqltypes.NewInt64(i)})
+		require.NoError(t, err)
+		if ksid, ok := got[0].(key.DestinationKeyspaceID); ok {
+			assert.GreaterOrEqual(t, key.Compare(ksid, prev), 0, "keyspace id of %d", i)
+			prev = ksid
+		}
+	}
+}
+
+func TestRangePartitionSplit(t *testing.T) {
+	// Splitting a partition at the value that matches the split of its key
+	// range doesn't move any row.
+	whole := createRangePartition(t, map[string]string{"partitions": `[{"from": 0, "to": 1000, "key_range": "-80"}]`})
+	split := createRangePartition(t, map[string]string{"partitions": `[{"from": 0, "to": 500, "key_range": "-40"}, {"from": 500, "to": 1000, "key_range": "40-80"}]`})
+	for i := range int64(1000) {
+		ids := []sqltypes.Value{sqltypes.NewInt64(i)}
+		want, err := whole.Map(t.Context(), nil, ids)
+		require.NoError(t, err)
+		got, err := split.Map(t.Context(), nil, ids)
+		require.NoError(t, err)
+		require.Equal(t, want, got, "keyspace id of %d", i)
+	}
+}
+
+func TestRangePartitionVerify(t *testing.T) {
+	rp := createRangePartition(t, map[string]string{"partitions": rangePartitionTestPartitions})
+	got, err := rp.Verify(t.Context(), nil,
+		[]sqltypes.Value{sqltypes.NewInt64(150), sqltypes.NewInt64(150), sqltypes.NewInt64(250)},
+		[][]byte{[]byte("\x60\x00\x00\x00\x00\x00\x00\x00"), []byte("\x40\x00\x00\x00\x00\x00\x00\x00"), []byte("\x40\x00\x00\x00\x00\x00\x00\x00")})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false}, got)
+
+	_, err = rp.Verify(t.Context(), nil, []sqltypes.Value{sqltypes.NewVarBinary("aa")}, [][]byte{nil})
+	require.EqualError(t, err, "cannot parse int64 from \"aa\"")
+}
+
+func TestRangePartitionRangeMap(t *testing.T) {
+	rp := createRangePartition(t, map[string]string{"partitions": rangePartitionTestPartitions})
+	ksid := func(v int64) []byte {
+		got, err := rp.Map(t.Context(), nil, []sqltypes.Value{sqltypes.NewInt64(v)})
+		require.NoError(t, err)
+		return got[0].(key.DestinationKeyspaceID)
+	}
+	next := func(ksid []byte) []byte {
+		return uint64ToBytes(bytesToUint64(ksid) + 1)
+	}
+	keyRange := func(start, end []byte) []key.ShardDestination {
+		return []key.ShardDestination{&key.DestinationKeyRange{KeyRange: key.NewKeyRange(start, end)}}
+	}
+
+	tcases := []struct {
+		start, end int64
+		want       []key.ShardDestination
+	}{{
+		start: 120,
+		end:   150,
+		want:  keyRange(ksid(120), next(ksid(150))),
+	}, {
+		// the end falls between partitions
+		start: 110,
+		end:   250,
+		want:  keyRange(ksid(110), []byte("\x80\x00\x00\x00\x00\x00\x00\x00")),
+	}, {
+		// the start falls between partitions
+		start: 250,
+		end:   300,
+		want:  keyRange([]byte("\xc0\x00\x00\x00\x00\x00\x00\x00"), next(ksid(300))),
+	}, {
+		start: 50,
+		end:   1000,
+		want:  keyRange(ksid(50), next(ksid(1000))),
+	}, {
+		start: 300,
+		end:   1<<63 - 1,
+		want:  keyRange([]byte("\xc0\x00\x00\x00\x00\x00\x00\x00"), nil),
+	}, {
+		start: -1 << 63,
+		end:   100,
+		want:  keyRange(nil, next(ksid(100))),
+	}, {
+		// no partition in between
+		start: 220,
+		end:   260,
+		want:  []key.ShardDestination{key.DestinationNone{}},
+	}}
+	for _, tc := range tcases {
+		got, err := rp.RangeMap(t.Context(), nil, sqltypes.NewInt64(tc.start), sqltypes.NewInt64(tc.end))
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got, "RangeMap(%d, %d)", tc.start, tc.end)
+	}
+
+	_, err := rp.RangeMap(t.Context(), nil, sqltypes.NULL, sqltypes.NewInt64(1))
+	require.EqualError(t, err, "range_partition: NULL values are not mapped")
+}
+
+func TestRangePartitionString(t *testing.T) {
+	rp := createRangePartition(t, map[string]string{
+		"type":       "string",
+		"partitions": `[{"to": "h", "key_range": "-80"}, {"from": "h", "key_range": "80-"}]`,
+	})
+	got, err := rp.Map(t.Context(), nil, []sqltypes.Value{
+		sqltypes.NewVarChar("apple"),
+		sqltypes.NewVarChar("Hello"),
+		sqltypes.NewVarChar("zebra"),
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	lower, upper := key.NewKeyRange(nil, []byte{0x80}), key.NewKeyRange([]byte{0x80}, nil)
+	assert.True(t, key.KeyRangeContains(lower, got[0].(key.DestinationKeyspaceID)))
+	assert.True(t, key.KeyRangeContains(upper, got[1].(key.DestinationKeyspaceID)), "the default collation is case insensitive")
+	assert.True(t, key.KeyRangeContains(upper, got[2].(key.DestinationKeyspaceID)))
+	assert.Negative(t, key.Compare(got[1].(key.DestinationKeyspaceID), got[2].(key.DestinationKeyspaceID)))
+}
+
+func TestRangePartitionDatetime(t *testing.T) {
+	rp := createRangePartition(t, map[string]string{
+		"type": "datetime",
+		"partitions": `[
+			{"from": "2024-01-01", "to": "2025-01-01", "key_range": "-80"},
+			{"from": "2025-01-01", "to": "2026-01-01 00:00:00", "key_range": "80-"}
+		]`,
+	})
+	got, err := rp.Map(t.Context(), nil, []sqltypes.Value{
+		sqltypes.MakeTrusted(sqltypes.Datetime, []byte("2024-01-01 00:00:00")),
+		sqltypes.MakeTrusted(sqltypes.Datetime, []byte("2024-07-02 00:00:00")),
+		sqltypes.MakeTrusted(sqltypes.Date, []byte("2025-01-01")),
+		sqltypes.NewVarChar("2025-12-31 23:59:59.999999"),
+		sqltypes.MakeTrusted(sqltypes.Date, []byte("2023-12-31")),
+		sqltypes.NewVarChar("not a date"),
+	})
+	require.NoError(t, err)
+	want := []key.ShardDestination{
+		key.DestinationKeyspaceID([]byte("\x00\x00\x00\x00\x00\x00\x00\x00")),
+		// 2024 is a leap year, so July 2nd is right in the middle.
+		key.DestinationKeyspaceID([]byte("\x40\x00\x00\x00\x00\x00\x00\x00")),
+		key.DestinationKeyspaceID([]byte("\x80\x00\x00\x00\x00\x00\x00\x00")),
+		got[3],
+		key.DestinationNone{},
+		key.DestinationNone{},
+	}
+	assert.Equal(t, want, got)
+	assert.True(t, key.KeyRangeContains(key.NewKeyRange([]byte{0xff}, nil), got[3].(key.DestinationKeyspaceID)))
+}
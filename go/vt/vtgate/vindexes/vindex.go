@@ -43,6 +43,7 @@ type (
 		Execute(ctx context.Context, method string, query string, bindvars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
 		ExecuteKeyspaceID(ctx context.Context, keyspace string, ksid []byte, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError, autocommit bool) (*sqltypes.Result, error)
 		InTransactionAndIsDML() bool
+		InTransaction() bool
 		LookupRowLockShardSession() vtgatepb.CommitOrder
 		ConnCollation() collations.ID
 		Environment() *vtenv.Environment
@@ -173,6 +173,10 @@ type BaseTable struct {
 	// MySQL error message: ERROR 3756 (HY000): The primary key cannot be a functional index
 	PrimaryKey sqlparser.Columns  `json:"primary_key,omitempty"`
 	UniqueKeys [][]sqlparser.Expr `json:"unique_keys,omitempty"`
+
+	// ResultCacheTTL is how long VTGate may cache the results of SELECT queries on this table.
+	// Zero means the results are only cached when requested through a query comment directive.
+	ResultCacheTTL time.Duration `json:"result_cache_ttl,omitempty"`
 }
 
 // GetTableName gets the sqlparser.TableName for the vindex Table.
@@ -709,6 +713,15 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 			Name:                    sqlparser.NewIdentifierCS(tname),
 			Keyspace:                keyspace,
 			ColumnListAuthoritative: table.ColumnListAuthoritative,
+			ResultCacheTTL:          time.Duration(table.ResultCacheTtlMs) * time.Millisecond,
+		}
+		if table.ResultCacheTtlMs < 0 {
+			return vterrors.Errorf(
+				vtrpcpb.Code_INVALID_ARGUMENT,
+				"invalid result_cache_ttl_ms %d for table: %s",
+				table.ResultCacheTtlMs,
+				tname,
+			)
 		}
 		switch table.Type {
 		case "":
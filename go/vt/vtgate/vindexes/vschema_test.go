@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1618,6 +1619,27 @@ func TestBuildVSchemaVindexNotFoundFail(t *testing.T) {
 	assert.EqualErrorf(t, err, want, "BuildVSchema: %v, want %v", err, want)
 }
 
+func TestBuildVSchemaResultCacheTTL(t *testing.T) {
+	srv := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {ResultCacheTtlMs: 1500},
+				},
+			},
+			"bad": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {ResultCacheTtlMs: -1},
+				},
+			},
+		},
+	}
+	got := BuildVSchema(&srv, sqlparser.NewTestParser())
+	require.NoError(t, got.Keyspaces["unsharded"].Error)
+	assert.Equal(t, 1500*time.Millisecond, got.Keyspaces["unsharded"].Tables["t1"].ResultCacheTTL)
+	assert.EqualError(t, got.Keyspaces["bad"].Error, "invalid result_cache_ttl_ms -1 for table: t1")
+}
+
 func TestBuildVSchemaNoColumnVindexFail(t *testing.T) {
 	bad := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -78,11 +78,19 @@ var (
 	// plan cache related flag
 	queryPlanCacheMemory int64 = 32 * 1024 * 1024 // 32mb
 
+	// result cache related flags
+	resultCacheMemory int64
+	resultCacheMaxTTL = time.Minute
+
 	maxMemoryRows   = 300000
 	warnMemoryRows  = 30000
 	maxPayloadSize  int
 	warnPayloadSize int
 
+	// spill related flags
+	spillMemoryBudget int64
+	spillDir          string
+
 	noScatter                 bool
 	preventCrossKeyspaceReads bool
 	enableShardRouting        bool
@@ -190,7 +198,11 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&truncateErrorLen, "truncate-error-len", truncateErrorLen, "truncate errors sent to client if they are longer than this value (0 means do not truncate)")
 	utils.SetFlagIntVar(fs, &streamBufferSize, "stream-buffer-size", streamBufferSize, "the number of bytes sent from vtgate for each stream call. It's recommended to keep this value in sync with vttablet's query-server-config-stream-buffer-size.")
 	utils.SetFlagInt64Var(fs, &queryPlanCacheMemory, "gate-query-cache-memory", queryPlanCacheMemory, "gate server query cache size in bytes, maximum amount of memory to be cached. vtgate analyzes every incoming query and generate a query plan, these plans are being cached in a lru cache. This config controls the capacity of the lru cache.")
+	utils.SetFlagInt64Var(fs, &resultCacheMemory, "result-cache-memory", resultCacheMemory, "Maximum number of bytes of query results that vtgate caches for the SELECT queries that enable the result cache, through the RESULT_CACHE_TTL_MS comment directive or the result_cache_ttl_ms vschema table setting. 0 disables the result cache. The cache is best-effort and local to each vtgate: only writes through the same vtgate invalidate cached results, so results can be stale after writes through other vtgates, vreplication or directly on the tablets, for up to --result-cache-max-ttl.")
+	utils.SetFlagDurationVar(fs, &resultCacheMaxTTL, "result-cache-max-ttl", resultCacheMaxTTL, "Maximum time a result stays in the result cache, whatever the TTL the query or its tables ask for. Only the writes done through this vtgate invalidate cached results, so this bounds how stale a result can be after writes through other vtgates, vreplication or directly on the tablets. 0 means no maximum, which lets such stale results be served until their own TTL expires.")
 	utils.SetFlagIntVar(fs, &maxMemoryRows, "max-memory-rows", maxMemoryRows, "Maximum number of rows that will be held in memory for intermediate results as well as the final result.")
+	utils.SetFlagInt64Var(fs, &spillMemoryBudget, "spill-memory-budget", spillMemoryBudget, "Maximum number of bytes of rows that a streaming query may buffer in memory for its sorts and hash joins, before spilling them to disk. Queries that are not streamed, and aggregations, never spill. Recursive CTEs evaluated by vtgate fail when their rows go over it. 0 disables spilling.")
+	utils.SetFlagStringVar(fs, &spillDir, "spill-dir", spillDir, "Directory where vtgate creates the files for rows spilled to disk. Defaults to the system temporary directory.")
 	utils.SetFlagIntVar(fs, &warnMemoryRows, "warn-memory-rows", warnMemoryRows, "Warning threshold for in-memory results. A row count higher than this amount will cause the VtGateWarnings.ResultsExceeded counter to be incremented.")
 	utils.SetFlagStringVar(fs, &defaultDDLStrategy, "ddl-strategy", defaultDDLStrategy, "Set default strategy for DDL statements. Override with @@ddl_strategy session variable")
 	utils.SetFlagStringVar(fs, &dbDDLPlugin, "dbddl-plugin", dbDDLPlugin, "controls how to handle CREATE/DROP DATABASE. use it if you are using your own database provisioning service")
@@ -42,6 +42,24 @@ const (
 	UnseenInstanceForgetHours             = 240 // Number of hours after which an unseen instance is forgotten
 )
 
+const (
+	// ErrantGTIDRecoveryInjectEmptyTransactions is the errant GTID recovery action that injects
+	// empty transactions on the primary for errant GTIDs that are confirmed to be no-ops.
+	ErrantGTIDRecoveryInjectEmptyTransactions = "inject-empty-transactions"
+	// ErrantGTIDRecoveryRestoreFromBackup is the errant GTID recovery action that rebuilds the
+	// replica from the most recent backup of its shard.
+	ErrantGTIDRecoveryRestoreFromBackup = "restore-from-backup"
+)
+
+// DefaultErrantGTIDNoOpStatements are the regular expressions of the maintenance statements
+// that do not change any data, and are allowed in errant transactions that are recovered by
+// injecting empty transactions.
+var DefaultErrantGTIDNoOpStatements = []string{
+	`^ANALYZE\s+((NO_WRITE_TO_BINLOG|LOCAL)\s+)?TABLES?\s`,
+	`^OPTIMIZE\s+((NO_WRITE_TO_BINLOG|LOCAL)\s+)?TABLES?\s`,
+	`^FLUSH\s`,
+}
+
 var (
 	cell = viperutil.Configure(
 		"cell",
@@ -223,6 +241,78 @@ var (
 		},
 	)
 
+	errantGTIDRecoveryActions = viperutil.Configure(
+		"errant-gtid-recovery-actions",
+		viperutil.Options[[]string]{
+			FlagName: "errant-gtid-recovery-actions",
+			Default:  nil,
+			Dynamic:  true,
+		},
+	)
+
+	errantGTIDNoOpStatements = viperutil.Configure(
+		"errant-gtid-noop-statements",
+		viperutil.Options[[]string]{
+			FlagName: "errant-gtid-noop-statements",
+			Default:  DefaultErrantGTIDNoOpStatements,
+			Dynamic:  true,
+		},
+	)
+
+	errantGTIDRestoreTimeout = viperutil.Configure(
+		"errant-gtid-restore-timeout",
+		viperutil.Options[time.Duration]{
+			FlagName: "errant-gtid-restore-timeout",
+			Default:  24 * time.Hour,
+			Dynamic:  true,
+		},
+	)
+
+	recoveryWebhookURL = viperutil.Configure(
+		"recovery-webhook-url",
+		viperutil.Options[string]{
+			FlagName: "recovery-webhook-url",
+			Default:  "",
+			Dynamic:  true,
+		},
+	)
+
+	recoveryHookCommand = viperutil.Configure(
+		"recovery-hook-command",
+		viperutil.Options[string]{
+			FlagName: "recovery-hook-command",
+			Default:  "",
+			Dynamic:  false,
+		},
+	)
+
+	recoveryHooksTimeout = viperutil.Configure(
+		"recovery-hooks-timeout",
+		viperutil.Options[time.Duration]{
+			FlagName: "recovery-hooks-timeout",
+			Default:  10 * time.Second,
+			Dynamic:  true,
+		},
+	)
+
+	recoveryHooksRetries = viperutil.Configure(
+		"recovery-hooks-retries",
+		viperutil.Options[int]{
+			FlagName: "recovery-hooks-retries",
+			Default:  2,
+			Dynamic:  true,
+		},
+	)
+
+	recoveryHooksRetryDelay = viperutil.Configure(
+		"recovery-hooks-retry-delay",
+		viperutil.Options[time.Duration]{
+			FlagName: "recovery-hooks-retry-delay",
+			Default:  1 * time.Second,
+			Dynamic:  true,
+		},
+	)
+
 	enablePrimaryDiskStalledRecovery = viperutil.Configure(
 		"enable-primary-disk-stalled-recovery",
 		viperutil.Options[bool]{
@@ -259,6 +349,14 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.Bool("allow-emergency-reparent", ersEnabled.Default(), "Whether VTOrc should be allowed to run emergency reparent operation when it detects a dead primary")
 	fs.Bool("allow-recovery", allowRecovery.Default(), "Whether VTOrc should be allowed to run recovery actions")
 	fs.Bool("change-tablets-with-errant-gtid-to-drained", convertTabletsWithErrantGTIDs.Default(), "Whether VTOrc should be changing the type of tablets with errant GTIDs to DRAINED")
+	fs.StringSlice("errant-gtid-recovery-actions", errantGTIDRecoveryActions.Default(), "Ordered list of the recovery actions VTOrc tries on replicas with errant GTIDs, before changing them to DRAINED if --change-tablets-with-errant-gtid-to-drained is set. Valid actions are 'inject-empty-transactions', which injects empty transactions on the primary for the errant GTIDs when they are confirmed to be no-ops, and 'restore-from-backup', which rebuilds the replica from the most recent backup of its shard")
+	fs.StringSlice("errant-gtid-noop-statements", errantGTIDNoOpStatements.Default(), "Case-insensitive regular expressions of the statements that errant transactions may contain to be confirmed as no-ops by the 'inject-empty-transactions' errant GTID recovery action")
+	fs.Duration("errant-gtid-restore-timeout", errantGTIDRestoreTimeout.Default(), "Timeout of the 'restore-from-backup' errant GTID recovery action. The restore runs in the background, while the tablet is DRAINED")
+	fs.String("recovery-webhook-url", recoveryWebhookURL.Default(), "URL VTOrc posts a JSON description of each recovery to, before and after running it")
+	fs.String("recovery-hook-command", recoveryHookCommand.Default(), "Local command VTOrc runs before and after each recovery, with a JSON description of the recovery on its standard input")
+	fs.Duration("recovery-hooks-timeout", recoveryHooksTimeout.Default(), "Timeout of each attempt to run the recovery webhook or hook command")
+	fs.Int("recovery-hooks-retries", recoveryHooksRetries.Default(), "Number of times a failed recovery webhook or hook command is retried")
+	fs.Duration("recovery-hooks-retry-delay", recoveryHooksRetryDelay.Default(), "Delay between the retries of a failed recovery webhook or hook command")
 	fs.Bool("enable-primary-disk-stalled-recovery", enablePrimaryDiskStalledRecovery.Default(), "Whether VTOrc should detect a stalled disk on the primary and failover")
 
 	viperutil.BindFlags(fs,
@@ -282,6 +380,14 @@ func registerFlags(fs *pflag.FlagSet) {
 		ersEnabled,
 		allowRecovery,
 		convertTabletsWithErrantGTIDs,
+		errantGTIDRecoveryActions,
+		errantGTIDNoOpStatements,
+		errantGTIDRestoreTimeout,
+		recoveryWebhookURL,
+		recoveryHookCommand,
+		recoveryHooksTimeout,
+		recoveryHooksRetries,
+		recoveryHooksRetryDelay,
 		enablePrimaryDiskStalledRecovery,
 	)
 }
@@ -426,6 +532,81 @@ func SetConvertTabletWithErrantGTIDs(val bool) {
 	convertTabletsWithErrantGTIDs.Set(val)
 }
 
+// GetErrantGTIDRecoveryActions returns the recovery actions VTOrc tries, in order, on replicas with errant GTIDs.
+func GetErrantGTIDRecoveryActions() []string {
+	return errantGTIDRecoveryActions.Get()
+}
+
+// SetErrantGTIDRecoveryActions sets the value for the errantGTIDRecoveryActions variable. This should only be used from tests.
+func SetErrantGTIDRecoveryActions(val []string) {
+	errantGTIDRecoveryActions.Set(val)
+}
+
+// GetErrantGTIDNoOpStatements returns the regular expressions of the statements errant transactions may contain to be no-ops.
+func GetErrantGTIDNoOpStatements() []string {
+	return errantGTIDNoOpStatements.Get()
+}
+
+// SetErrantGTIDNoOpStatements sets the value for the errantGTIDNoOpStatements variable. This should only be used from tests.
+func SetErrantGTIDNoOpStatements(val []string) {
+	errantGTIDNoOpStatements.Set(val)
+}
+
+// GetErrantGTIDRestoreTimeout returns the timeout of the restore-from-backup errant GTID recovery action.
+func GetErrantGTIDRestoreTimeout() time.Duration {
+	return errantGTIDRestoreTimeout.Get()
+}
+
+// SetErrantGTIDRestoreTimeout sets the value for the errantGTIDRestoreTimeout variable. This should only be used from tests.
+func SetErrantGTIDRestoreTimeout(val time.Duration) {
+	errantGTIDRestoreTimeout.Set(val)
+}
+
+// GetRecoveryWebhookURL is a getter function.
+func GetRecoveryWebhookURL() string {
+	return recoveryWebhookURL.Get()
+}
+
+// SetRecoveryWebhookURL sets the value for the recoveryWebhookURL variable. This should only be used from tests.
+func SetRecoveryWebhookURL(val string) {
+	recoveryWebhookURL.Set(val)
+}
+
+// GetRecoveryHookCommand is a getter function.
+func GetRecoveryHookCommand() string {
+	return recoveryHookCommand.Get()
+}
+
+// SetRecoveryHookCommand sets the value for the recoveryHookCommand variable. This should only be used from tests.
+func SetRecoveryHookCommand(val string) {
+	recoveryHookCommand.Set(val)
+}
+
+// GetRecoveryHooksTimeout is a getter function.
+func GetRecoveryHooksTimeout() time.Duration {
+	return recoveryHooksTimeout.Get()
+}
+
+// GetRecoveryHooksRetries is a getter function.
+func GetRecoveryHooksRetries() int {
+	return recoveryHooksRetries.Get()
+}
+
+// SetRecoveryHooksRetries sets the value for the recoveryHooksRetries variable. This should only be used from tests.
+func SetRecoveryHooksRetries(val int) {
+	recoveryHooksRetries.Set(val)
+}
+
+// GetRecoveryHooksRetryDelay is a getter function.
+func GetRecoveryHooksRetryDelay() time.Duration {
+	return recoveryHooksRetryDelay.Get()
+}
+
+// SetRecoveryHooksRetryDelay sets the value for the recoveryHooksRetryDelay variable. This should only be used from tests.
+func SetRecoveryHooksRetryDelay(val time.Duration) {
+	recoveryHooksRetryDelay.Set(val)
+}
+
 // GetStalledDiskPrimaryRecovery reports whether VTOrc is allowed to check for and recovery stalled disk problems.
 func GetStalledDiskPrimaryRecovery() bool {
 	return enablePrimaryDiskStalledRecovery.Get()
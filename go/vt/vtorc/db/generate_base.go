@@ -33,6 +33,7 @@ var TableNames = []string{
 	"primary_health",
 	"vitess_keyspace",
 	"vitess_shard",
+	"recovery_maintenance",
 }
 
 // vtorcBackend is a list of SQL statements required to build the vtorc backend
@@ -323,6 +324,18 @@ CREATE TABLE vitess_shard (
 	PRIMARY KEY (keyspace, shard)
 )`,
 	`
+DROP TABLE IF EXISTS recovery_maintenance
+`,
+	`
+CREATE TABLE recovery_maintenance (
+	keyspace varchar(128) NOT NULL,
+	shard varchar(128) NOT NULL,
+	alias varchar(256) NOT NULL,
+	reason text NOT NULL,
+	expire_timestamp timestamp NOT NULL,
+	PRIMARY KEY (keyspace, shard, alias)
+)`,
+	`
 CREATE INDEX source_host_port_idx_database_instance_database_instance on database_instance (source_host, source_port)
 	`,
 	`
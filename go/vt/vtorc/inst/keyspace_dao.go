@@ -84,7 +84,10 @@ func SaveKeyspace(keyspace *topo.KeyspaceInfo) error {
 		keyspace.GetDurabilityPolicy(),
 		disableEmergencyReparent,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return saveKeyspaceRecoveryMaintenance(keyspace.KeyspaceName(), keyspace.VtorcState)
 }
 
 // GetDurabilityPolicy gets the durability policy for the given keyspace.
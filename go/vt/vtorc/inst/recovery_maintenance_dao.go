@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inst
+
+import (
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/external/golib/sqlutils"
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
+	"vitess.io/vitess/go/vt/vtorc/db"
+)
+
+// RecoveryMaintenance silences the recoveries of a keyspace, a shard or a tablet until it expires.
+// Shard is empty for keyspace maintenances, and TabletAlias is empty for keyspace and shard maintenances.
+type RecoveryMaintenance struct {
+	Keyspace        string
+	Shard           string
+	TabletAlias     string
+	Reason          string
+	ExpireTimestamp time.Time
+}
+
+// ReadRecoveryMaintenance returns the active recovery maintenance that silences the recoveries of the given
+// tablet, or of its keyspace or shard. It returns nil if there is none. If several maintenances are active,
+// the one that expires last is returned.
+func ReadRecoveryMaintenance(keyspace, shard, tabletAlias string) (*RecoveryMaintenance, error) {
+	query := `SELECT
+			keyspace,
+			shard,
+			alias,
+			reason,
+			expire_timestamp
+		FROM
+			recovery_maintenance
+		WHERE
+			keyspace = ?
+			AND shard IN ('', ?)
+			AND alias IN ('', ?)`
+	maintenances, err := readRecoveryMaintenances(query, sqlutils.Args(keyspace, shard, tabletAlias))
+	if err != nil {
+		return nil, err
+	}
+	var maintenance *RecoveryMaintenance
+	for _, m := range maintenances {
+		if maintenance == nil || m.ExpireTimestamp.After(maintenance.ExpireTimestamp) {
+			maintenance = m
+		}
+	}
+	return maintenance, nil
+}
+
+// ReadRecoveryMaintenances returns the active recovery maintenances, optionally filtered by keyspace and shard.
+// The maintenances of the keyspace are returned along with the ones of the shard.
+func ReadRecoveryMaintenances(keyspace, shard string) ([]*RecoveryMaintenance, error) {
+	query := `SELECT
+			keyspace,
+			shard,
+			alias,
+			reason,
+			expire_timestamp
+		FROM
+			recovery_maintenance
+		WHERE
+			(? = '' OR keyspace = ?)
+			AND (shard = '' OR ? = '' OR shard = ?)
+		ORDER BY
+			keyspace, shard, alias`
+	return readRecoveryMaintenances(query, sqlutils.Args(keyspace, keyspace, shard, shard))
+}
+
+// readRecoveryMaintenances runs the given query and returns the recovery maintenances that have not expired.
+func readRecoveryMaintenances(query string, args []any) ([]*RecoveryMaintenance, error) {
+	var maintenances []*RecoveryMaintenance
+	now := time.Now()
+	err := db.QueryVTOrc(query, args, func(row sqlutils.RowMap) error {
+		maintenance := &RecoveryMaintenance{
+			Keyspace:        row.GetString("keyspace"),
+			Shard:           row.GetString("shard"),
+			TabletAlias:     row.GetString("alias"),
+			Reason:          row.GetString("reason"),
+			ExpireTimestamp: row.GetTime("expire_timestamp"),
+		}
+		if maintenance.ExpireTimestamp.After(now) {
+			maintenances = append(maintenances, maintenance)
+		}
+		return nil
+	})
+	return maintenances, err
+}
+
+// saveKeyspaceRecoveryMaintenance replaces the recovery maintenance of the given keyspace.
+func saveKeyspaceRecoveryMaintenance(keyspace string, vtorcState *vtorcdatapb.Keyspace) error {
+	if err := deleteRecoveryMaintenances(keyspace, ""); err != nil {
+		return err
+	}
+	return insertRecoveryMaintenance(keyspace, "", "", vtorcState.GetRecoveryMaintenance())
+}
+
+// saveShardRecoveryMaintenances replaces the recovery maintenances of the given shard and of its tablets.
+func saveShardRecoveryMaintenances(keyspace, shard string, vtorcState *vtorcdatapb.Shard) error {
+	if err := deleteRecoveryMaintenances(keyspace, shard); err != nil {
+		return err
+	}
+	if err := insertRecoveryMaintenance(keyspace, shard, "", vtorcState.GetRecoveryMaintenance()); err != nil {
+		return err
+	}
+	for tabletAlias, maintenance := range vtorcState.GetTabletRecoveryMaintenance() {
+		if err := insertRecoveryMaintenance(keyspace, shard, tabletAlias, maintenance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertRecoveryMaintenance inserts the given recovery maintenance, if any.
+func insertRecoveryMaintenance(keyspace, shard, tabletAlias string, maintenance *vtorcdatapb.RecoveryMaintenance) error {
+	if maintenance == nil {
+		return nil
+	}
+	_, err := db.ExecVTOrc(`
+		replace into recovery_maintenance (
+			keyspace, shard, alias, reason, expire_timestamp
+		) values (
+			?, ?, ?, ?, ?
+		)`,
+		keyspace,
+		shard,
+		tabletAlias,
+		maintenance.Reason,
+		protoutil.TimeFromProto(maintenance.ExpireTime).UTC(),
+	)
+	return err
+}
+
+// deleteRecoveryMaintenances deletes the recovery maintenances of the given keyspace, if shard is empty,
+// or of the given shard and of its tablets.
+func deleteRecoveryMaintenances(keyspace, shard string) error {
+	_, err := db.ExecVTOrc(`DELETE FROM
+			recovery_maintenance
+		WHERE
+			keyspace = ?
+			AND shard = ?`,
+		keyspace,
+		shard,
+	)
+	return err
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inst
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtorc/db"
+)
+
+func TestRecoveryMaintenance(t *testing.T) {
+	// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
+	defer func() {
+		db.ClearVTOrcDatabase()
+	}()
+
+	now := time.Now()
+	maintenance := func(reason string, expireTime time.Time) *vtorcdatapb.RecoveryMaintenance {
+		return &vtorcdatapb.RecoveryMaintenance{
+			Reason:     reason,
+			ExpireTime: protoutil.TimeToProto(expireTime),
+		}
+	}
+
+	keyspaceInfo := &topo.KeyspaceInfo{
+		Keyspace: &topodatapb.Keyspace{
+			VtorcState: &vtorcdatapb.Keyspace{
+				RecoveryMaintenance: maintenance("keyspace upgrade", now.Add(-time.Minute)),
+			},
+		},
+	}
+	keyspaceInfo.SetKeyspaceName("ks")
+	require.NoError(t, SaveKeyspace(keyspaceInfo))
+
+	shardInfo := topo.NewShardInfo("ks", "-80", &topodatapb.Shard{
+		VtorcState: &vtorcdatapb.Shard{
+			RecoveryMaintenance: maintenance("shard upgrade", now.Add(time.Hour)),
+			TabletRecoveryMaintenance: map[string]*vtorcdatapb.RecoveryMaintenance{
+				"zone1-0000000101": maintenance("disk replacement", now.Add(2*time.Hour)),
+			},
+		},
+	}, nil)
+	require.NoError(t, SaveShard(shardInfo))
+	require.NoError(t, SaveShard(topo.NewShardInfo("ks", "80-", &topodatapb.Shard{}, nil)))
+
+	// The keyspace maintenance has expired, so only the shard and tablet maintenances are active.
+	got, err := ReadRecoveryMaintenance("ks", "-80", "zone1-0000000100")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "shard upgrade", got.Reason)
+	assert.WithinDuration(t, now.Add(time.Hour), got.ExpireTimestamp, time.Second)
+
+	got, err = ReadRecoveryMaintenance("ks", "-80", "zone1-0000000101")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "disk replacement", got.Reason)
+	assert.Equal(t, "zone1-0000000101", got.TabletAlias)
+
+	got, err = ReadRecoveryMaintenance("ks", "80-", "zone1-0000000200")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	maintenances, err := ReadRecoveryMaintenances("ks", "")
+	require.NoError(t, err)
+	require.Len(t, maintenances, 2)
+	assert.Equal(t, "", maintenances[0].TabletAlias)
+	assert.Equal(t, "zone1-0000000101", maintenances[1].TabletAlias)
+
+	// A keyspace maintenance silences the recoveries of all the shards.
+	keyspaceInfo.VtorcState.RecoveryMaintenance = maintenance("keyspace upgrade", now.Add(time.Hour))
+	require.NoError(t, SaveKeyspace(keyspaceInfo))
+	got, err = ReadRecoveryMaintenance("ks", "80-", "zone1-0000000200")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "keyspace upgrade", got.Reason)
+
+	// Saving the shard without maintenances clears them.
+	require.NoError(t, SaveShard(topo.NewShardInfo("ks", "-80", &topodatapb.Shard{}, nil)))
+	maintenances, err = ReadRecoveryMaintenances("ks", "-80")
+	require.NoError(t, err)
+	require.Len(t, maintenances, 1)
+	assert.Equal(t, "", maintenances[0].Shard)
+
+	// Keyspace and shard names are matched exactly, '_' and '%' are not wildcards.
+	otherKeyspaceInfo := &topo.KeyspaceInfo{
+		Keyspace: &topodatapb.Keyspace{
+			VtorcState: &vtorcdatapb.Keyspace{
+				RecoveryMaintenance: maintenance("other keyspace upgrade", now.Add(time.Hour)),
+			},
+		},
+	}
+	otherKeyspaceInfo.SetKeyspaceName("ksx1")
+	require.NoError(t, SaveKeyspace(otherKeyspaceInfo))
+	maintenances, err = ReadRecoveryMaintenances("ks_1", "")
+	require.NoError(t, err)
+	assert.Empty(t, maintenances)
+	maintenances, err = ReadRecoveryMaintenances("ks%", "")
+	require.NoError(t, err)
+	assert.Empty(t, maintenances)
+	maintenances, err = ReadRecoveryMaintenances("ksx1", "")
+	require.NoError(t, err)
+	require.Len(t, maintenances, 1)
+	assert.Equal(t, "other keyspace upgrade", maintenances[0].Reason)
+	otherKeyspaceInfo.VtorcState = nil
+	require.NoError(t, SaveKeyspace(otherKeyspaceInfo))
+
+	// Deleting the keyspace maintenance leaves no maintenance.
+	keyspaceInfo.VtorcState = nil
+	require.NoError(t, SaveKeyspace(keyspaceInfo))
+	maintenances, err = ReadRecoveryMaintenances("", "")
+	require.NoError(t, err)
+	assert.Empty(t, maintenances)
+}
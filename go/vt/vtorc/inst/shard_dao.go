@@ -146,7 +146,10 @@ func SaveShard(shard *topo.ShardInfo) error {
 		getShardPrimaryTermStartTime(shard),
 		disableEmergencyReparent,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return saveShardRecoveryMaintenances(shard.Keyspace(), shard.ShardName(), shard.VtorcState)
 }
 
 // getShardPrimaryAliasString gets the shard primary alias to be stored as a string in the database.
@@ -175,5 +178,8 @@ func DeleteShard(keyspace, shard string) error {
 		keyspace,
 		shard,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return deleteRecoveryMaintenances(keyspace, shard)
 }
@@ -0,0 +1,314 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+const (
+	// binlogEventsPageSize is the number of events read from a binary log per query.
+	binlogEventsPageSize = 10000
+	// maxErrantGTIDsToInject is the maximum number of errant GTIDs VTOrc injects empty
+	// transactions for. Replicas with more errant GTIDs are not recovered this way.
+	maxErrantGTIDsToInject = 1000
+)
+
+var (
+	// binlogGTIDNextRegexp matches the GTID in the info of a Gtid binary log event.
+	binlogGTIDNextRegexp = regexp.MustCompile(`GTID_NEXT\s*=\s*'([^']+)'`)
+	// binlogQueryUseRegexp matches the default database prefixed to the info of a Query binary log event.
+	binlogQueryUseRegexp = regexp.MustCompile("^use\\s+`(?:[^`]|``)*`;\\s*")
+	// binlogQueryXidRegexp matches the xid suffixed to the info of a Query binary log event for DDLs.
+	binlogQueryXidRegexp = regexp.MustCompile(`\s*/\*\s*xid=\d+\s*\*/$`)
+)
+
+// errantTransaction is a transaction with an errant GTID, read from the binary logs of a replica.
+type errantTransaction struct {
+	gtid replication.Mysql56GTID
+	// reason explains why the transaction is not a no-op. It is empty for no-ops.
+	reason string
+}
+
+// errantTransactionParser finds the transactions with errant GTIDs in the SHOW BINLOG EVENTS
+// output of a binary log, and checks whether they are no-ops.
+type errantTransactionParser struct {
+	errant  replication.Mysql56GTIDSet
+	noOps   []*regexp.Regexp
+	found   []*errantTransaction
+	current *errantTransaction
+}
+
+// parseEvent parses the next event of the binary log.
+func (p *errantTransactionParser) parseEvent(eventType, info string) error {
+	switch eventType {
+	case "Gtid":
+		p.current = nil
+		m := binlogGTIDNextRegexp.FindStringSubmatch(info)
+		if m == nil {
+			return fmt.Errorf("cannot parse GTID from binary log event %q", info)
+		}
+		gtid, err := replication.ParseMysql56GTID(m[1])
+		if err != nil {
+			return err
+		}
+		if p.errant.ContainsGTID(gtid) {
+			p.current = &errantTransaction{gtid: gtid.(replication.Mysql56GTID)}
+			p.found = append(p.found, p.current)
+		}
+	case "Anonymous_Gtid", "Xid":
+		p.current = nil
+	case "Format_desc", "Previous_gtids", "Rotate", "Stop", "Heartbeat":
+		// These events are not part of any transaction.
+	case "Query":
+		if p.current == nil {
+			return nil
+		}
+		statement := binlogQueryUseRegexp.ReplaceAllString(info, "")
+		statement = strings.TrimSpace(binlogQueryXidRegexp.ReplaceAllString(statement, ""))
+		switch {
+		case strings.EqualFold(statement, "BEGIN"):
+		case strings.EqualFold(statement, "COMMIT"):
+			p.current = nil
+		case !slices.ContainsFunc(p.noOps, func(re *regexp.Regexp) bool { return re.MatchString(statement) }):
+			if p.current.reason == "" {
+				p.current.reason = fmt.Sprintf("statement %q is not a known no-op", statement)
+			}
+		}
+	default:
+		if p.current != nil && p.current.reason == "" {
+			p.current.reason = eventType + " event"
+		}
+	}
+	return nil
+}
+
+// foundAll returns whether all the errant transactions were found.
+func (p *errantTransactionParser) foundAll() bool {
+	var found replication.GTIDSet = replication.Mysql56GTIDSet{}
+	for _, trx := range p.found {
+		found = found.AddGTID(trx.gtid)
+	}
+	return found.Contains(p.errant)
+}
+
+// readErrantTransactions reads the transactions with the given errant GTIDs from the binary
+// logs of the given tablet, starting with the most recent binary log.
+func readErrantTransactions(ctx context.Context, tablet *topodatapb.Tablet, errant replication.Mysql56GTIDSet, noOps []*regexp.Regexp) ([]*errantTransaction, error) {
+	logs, err := executeFetchAsDba(ctx, tablet, "SHOW BINARY LOGS")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list binary logs: %w", err)
+	}
+
+	parser := &errantTransactionParser{errant: errant, noOps: noOps}
+	for i := len(logs.Rows) - 1; i >= 0; i-- {
+		binlog := logs.Rows[i][0].ToString()
+		parser.current = nil
+		pos := uint64(4)
+		for {
+			query := fmt.Sprintf("SHOW BINLOG EVENTS IN %s FROM %d LIMIT %d", sqltypes.EncodeStringSQL(binlog), pos, binlogEventsPageSize)
+			events, err := executeFetchAsDba(ctx, tablet, query)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read binary log %s: %w", binlog, err)
+			}
+			for _, row := range events.Named().Rows {
+				if err := parser.parseEvent(row.AsString("Event_type", ""), row.AsString("Info", "")); err != nil {
+					return nil, fmt.Errorf("failed to parse binary log %s: %w", binlog, err)
+				}
+				pos = row.AsUint64("End_log_pos", 0)
+			}
+			if len(events.Rows) < binlogEventsPageSize || pos == 0 {
+				break
+			}
+		}
+		// Transactions do not span binary logs, so the ones found so far are complete.
+		if parser.foundAll() {
+			return parser.found, nil
+		}
+	}
+	return nil, fmt.Errorf("errant GTIDs %v are not all in the binary logs of %v, they may have been purged", errant, topoproto.TabletAliasString(tablet.Alias))
+}
+
+// confirmErrantGTIDsAreNoOps returns the errant GTIDs of the given tablet if all the errant
+// transactions are confirmed to be no-ops, as per --errant-gtid-noop-statements.
+func confirmErrantGTIDsAreNoOps(ctx context.Context, tablet *topodatapb.Tablet, errantGTIDs string) ([]replication.Mysql56GTID, error) {
+	errant, err := replication.ParseMysql56GTIDSet(errantGTIDs)
+	if err != nil {
+		return nil, err
+	}
+	if errant.Empty() {
+		return nil, errors.New("no errant GTIDs")
+	}
+	if count := errant.Count(); count > maxErrantGTIDsToInject {
+		return nil, fmt.Errorf("%d errant GTIDs is more than the %d VTOrc injects empty transactions for", count, maxErrantGTIDsToInject)
+	}
+
+	var noOps []*regexp.Regexp
+	for _, expr := range config.GetErrantGTIDNoOpStatements() {
+		re, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --errant-gtid-noop-statements expression %q: %w", expr, err)
+		}
+		noOps = append(noOps, re)
+	}
+
+	transactions, err := readErrantTransactions(ctx, tablet, errant, noOps)
+	if err != nil {
+		return nil, err
+	}
+	gtids := make([]replication.Mysql56GTID, 0, len(transactions))
+	for _, trx := range transactions {
+		if trx.reason != "" {
+			return nil, fmt.Errorf("errant transaction %v is not a no-op: %s", trx.gtid, trx.reason)
+		}
+		gtids = append(gtids, trx.gtid)
+	}
+	// The binary logs are read most recent first, so sort the GTIDs back.
+	slices.SortFunc(gtids, func(a, b replication.Mysql56GTID) int {
+		if c := bytes.Compare(a.Server[:], b.Server[:]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+	return gtids, nil
+}
+
+// injectEmptyTransactions commits an empty transaction for each of the given GTIDs on the primary.
+func injectEmptyTransactions(ctx context.Context, primary *topodatapb.Tablet, gtids []replication.Mysql56GTID) error {
+	var sql strings.Builder
+	for _, gtid := range gtids {
+		fmt.Fprintf(&sql, "SET GTID_NEXT=%s; BEGIN; COMMIT; ", sqltypes.EncodeStringSQL(gtid.String()))
+	}
+	sql.WriteString("SET GTID_NEXT='AUTOMATIC'")
+
+	tmcCtx, tmcCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer tmcCancel()
+	_, err := tmc.ExecuteMultiFetchAsDba(tmcCtx, primary, false, &tabletmanagerdatapb.ExecuteMultiFetchAsDbaRequest{
+		Sql: []byte(sql.String()),
+	})
+	return err
+}
+
+// restoresFromBackup tracks the restores VTOrc runs in the background, by tablet alias. Each
+// channel is closed once its restore is over.
+var restoresFromBackup = struct {
+	mu      sync.Mutex
+	running map[string]chan struct{}
+}{running: make(map[string]chan struct{})}
+
+// restoringFromBackup returns a channel that is closed once the restore of the given tablet is
+// over, or nil if the tablet is not being restored.
+func restoringFromBackup(tabletAlias *topodatapb.TabletAlias) chan struct{} {
+	restoresFromBackup.mu.Lock()
+	defer restoresFromBackup.mu.Unlock()
+	return restoresFromBackup.running[topoproto.TabletAliasString(tabletAlias)]
+}
+
+// startRestoreFromBackup changes the type of the given tablet to DRAINED, and then restores it
+// from the most recent backup of its shard in the background. A restore takes a long time, and
+// so must not hold the shard lock of the recovery. Once restored, the tablet is changed back to
+// its type. If the restore fails or times out, the tablet stays DRAINED.
+func startRestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, semiSync bool, logger *log.PrefixedLogger) error {
+	tabletAlias := topoproto.TabletAliasString(tablet.Alias)
+	done := make(chan struct{})
+	restoresFromBackup.mu.Lock()
+	if _, ok := restoresFromBackup.running[tabletAlias]; ok {
+		restoresFromBackup.mu.Unlock()
+		return fmt.Errorf("tablet %v is already being restored from backup", tabletAlias)
+	}
+	restoresFromBackup.running[tabletAlias] = done
+	restoresFromBackup.mu.Unlock()
+	restoreDone := func() {
+		restoresFromBackup.mu.Lock()
+		delete(restoresFromBackup.running, tabletAlias)
+		restoresFromBackup.mu.Unlock()
+		close(done)
+	}
+
+	if err := changeTabletType(ctx, tablet, topodatapb.TabletType_DRAINED, false); err != nil {
+		restoreDone()
+		return fmt.Errorf("failed to change tablet type to DRAINED before restoring from backup: %w", err)
+	}
+	go func() {
+		defer restoreDone()
+		// The restore outlives the recovery, and so doesn't use its context.
+		restoreCtx, cancel := context.WithTimeout(context.Background(), config.GetErrantGTIDRestoreTimeout())
+		defer cancel()
+		err := restoreFromBackup(restoreCtx, tablet, logger)
+		if err == nil {
+			err = changeTabletType(restoreCtx, tablet, tablet.Type, semiSync)
+		}
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to restore tablet %v from backup: %v", tabletAlias, err))
+			_ = inst.AuditOperation("errant-gtid-restore-from-backup", tablet.Alias, fmt.Sprintf("failed, tablet stays DRAINED: %v", err))
+			return
+		}
+		_ = inst.AuditOperation("errant-gtid-restore-from-backup", tablet.Alias, "restored from backup, changed tablet type back to "+topoproto.TabletTypeLString(tablet.Type))
+	}()
+	return nil
+}
+
+// restoreFromBackup restores the given tablet from the most recent backup of its shard.
+func restoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, logger *log.PrefixedLogger) error {
+	stream, err := tmc.RestoreFromBackup(ctx, tablet, &tabletmanagerdatapb.RestoreFromBackupRequest{})
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		logger.Info(event.Value)
+	}
+}
+
+// executeFetchAsDba runs the given query on the given tablet as the DBA user.
+func executeFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, query string) (*sqltypes.Result, error) {
+	tmcCtx, tmcCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer tmcCancel()
+	qr, err := tmc.ExecuteFetchAsDba(tmcCtx, tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+		Query:   []byte(query),
+		MaxRows: binlogEventsPageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sqltypes.Proto3ToResult(qr), nil
+}
@@ -0,0 +1,514 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+	tmcmock "vitess.io/vitess/go/vt/vttablet/tmclient/mock"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	errantTestPrimaryUUID = "16b1039f-22b6-11ed-b765-0a43f95f28a3"
+	errantTestReplicaUUID = "0d4c2c3a-22b6-11ed-b765-0a43f95f28a3"
+)
+
+// binlogEvent is an event in the SHOW BINLOG EVENTS output.
+type binlogEvent struct {
+	eventType string
+	info      string
+}
+
+func gtidEvent(gtid string) binlogEvent {
+	return binlogEvent{"Gtid", fmt.Sprintf("SET @@SESSION.GTID_NEXT= '%s'", gtid)}
+}
+
+func queryEvent(query string) binlogEvent {
+	return binlogEvent{"Query", query}
+}
+
+// binlogEventsResult returns the SHOW BINLOG EVENTS result of the given events.
+func binlogEventsResult(binlog string, events ...binlogEvent) *querypb.QueryResult {
+	var rows []string
+	for i, event := range events {
+		rows = append(rows, fmt.Sprintf("%s|%d|%s|1|%d|%s", binlog, 4+i*100, event.eventType, 4+(i+1)*100, event.info))
+	}
+	return sqltypes.ResultToProto3(sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("Log_name|Pos|Event_type|Server_id|End_log_pos|Info", "varchar|int64|varchar|int64|int64|varchar"),
+		rows...,
+	))
+}
+
+// binlogQueries returns the results of the queries reading the given binary logs.
+func binlogQueries(binlogs map[string][]binlogEvent, names ...string) map[string]*querypb.QueryResult {
+	var logs []string
+	queries := make(map[string]*querypb.QueryResult)
+	for _, name := range names {
+		logs = append(logs, name+"|1000")
+		queries[fmt.Sprintf("SHOW BINLOG EVENTS IN '%s' FROM 4 LIMIT %d", name, binlogEventsPageSize)] = binlogEventsResult(name, binlogs[name]...)
+	}
+	queries["SHOW BINARY LOGS"] = sqltypes.ResultToProto3(sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("Log_name|File_size", "varchar|int64"),
+		logs...,
+	))
+	return queries
+}
+
+// expectBinlogQueries makes the mock tablet manager client answer the given queries.
+func expectBinlogQueries(mockTMC *tmcmock.MockTabletManagerClient, queries map[string]*querypb.QueryResult) {
+	mockTMC.EXPECT().
+		ExecuteFetchAsDba(gomock.Any(), gomock.Any(), false, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *topodatapb.Tablet, _ bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+			qr, ok := queries[string(req.Query)]
+			if !ok {
+				return nil, fmt.Errorf("unexpected query %q", req.Query)
+			}
+			return qr, nil
+		}).
+		AnyTimes()
+}
+
+// errantTestBinlogs are the binary logs of a replica with the errant GTIDs
+// errantTestReplicaUUID:3-4. The transaction of errantTestReplicaUUID:4 is
+// a no-op in noOpBinlog, and writes rows in rowsBinlog.
+var errantTestBinlogs = map[string][]binlogEvent{
+	"binlog.000001": {
+		{"Format_desc", "Server ver: 8.0.40, Binlog ver: 4"},
+		{"Previous_gtids", ""},
+		gtidEvent(errantTestReplicaUUID + ":3"),
+		queryEvent("use `vt_ks`; ANALYZE TABLE `t1` /* xid=12 */"),
+		gtidEvent(errantTestPrimaryUUID + ":10"),
+		queryEvent("BEGIN"),
+		{"Table_map", "table_id: 90 (vt_ks.t1)"},
+		{"Write_rows", "table_id: 90 flags: STMT_END_F"},
+		{"Xid", "COMMIT /* xid=13 */"},
+		{"Rotate", "binlog.000002;pos=4"},
+	},
+	"noOpBinlog": {
+		{"Format_desc", "Server ver: 8.0.40, Binlog ver: 4"},
+		{"Previous_gtids", errantTestPrimaryUUID + ":1-10," + errantTestReplicaUUID + ":1-3"},
+		gtidEvent(errantTestReplicaUUID + ":4"),
+		queryEvent("FLUSH PRIVILEGES"),
+	},
+	"rowsBinlog": {
+		{"Format_desc", "Server ver: 8.0.40, Binlog ver: 4"},
+		{"Previous_gtids", errantTestPrimaryUUID + ":1-10," + errantTestReplicaUUID + ":1-3"},
+		gtidEvent(errantTestReplicaUUID + ":4"),
+		queryEvent("BEGIN"),
+		{"Table_map", "table_id: 90 (vt_ks.t1)"},
+		{"Delete_rows", "table_id: 90 flags: STMT_END_F"},
+		{"Xid", "COMMIT /* xid=20 */"},
+	},
+}
+
+func TestErrantTransactionParser(t *testing.T) {
+	errant, err := replication.ParseMysql56GTIDSet(errantTestReplicaUUID + ":3-4")
+	require.NoError(t, err)
+	noOps := []*regexp.Regexp{regexp.MustCompile(`(?i)^ANALYZE\s+TABLE\s`), regexp.MustCompile(`(?i)^FLUSH\s`)}
+
+	tests := []struct {
+		name      string
+		events    []binlogEvent
+		wantFound []string
+		wantNoOp  []bool
+		wantAll   bool
+		wantErr   string
+	}{
+		{
+			name:      "no-ops",
+			events:    append(errantTestBinlogs["binlog.000001"], errantTestBinlogs["noOpBinlog"]...),
+			wantFound: []string{errantTestReplicaUUID + ":3", errantTestReplicaUUID + ":4"},
+			wantNoOp:  []bool{true, true},
+			wantAll:   true,
+		},
+		{
+			name:      "row events",
+			events:    errantTestBinlogs["rowsBinlog"],
+			wantFound: []string{errantTestReplicaUUID + ":4"},
+			wantNoOp:  []bool{false},
+		},
+		{
+			name: "statement that is not a no-op",
+			events: []binlogEvent{
+				gtidEvent(errantTestReplicaUUID + ":3"),
+				queryEvent("use `vt_ks`; DROP TABLE `t1` /* generated by server */"),
+			},
+			wantFound: []string{errantTestReplicaUUID + ":3"},
+			wantNoOp:  []bool{false},
+		},
+		{
+			name: "empty transaction",
+			events: []binlogEvent{
+				gtidEvent(errantTestReplicaUUID + ":3"),
+				queryEvent("BEGIN"),
+				queryEvent("COMMIT"),
+				{"Write_rows", "table_id: 90 flags: STMT_END_F"},
+			},
+			wantFound: []string{errantTestReplicaUUID + ":3"},
+			wantNoOp:  []bool{true},
+		},
+		{
+			name:    "unparseable GTID",
+			events:  []binlogEvent{{"Gtid", "SET @@SESSION.GTID_NEXT= AUTOMATIC"}},
+			wantErr: "cannot parse GTID",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &errantTransactionParser{errant: errant, noOps: noOps}
+			for _, event := range tt.events {
+				err = parser.parseEvent(event.eventType, event.info)
+				if err != nil {
+					break
+				}
+			}
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var found []string
+			var noOp []bool
+			for _, trx := range parser.found {
+				found = append(found, trx.gtid.String())
+				noOp = append(noOp, trx.reason == "")
+			}
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.wantNoOp, noOp)
+			assert.Equal(t, tt.wantAll, parser.foundAll())
+		})
+	}
+}
+
+func TestConfirmErrantGTIDsAreNoOps(t *testing.T) {
+	tablet := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101}}
+	binlogs := map[string][]binlogEvent{
+		"binlog.000001": errantTestBinlogs["binlog.000001"],
+		"binlog.000002": errantTestBinlogs["noOpBinlog"],
+		"binlog.000003": errantTestBinlogs["rowsBinlog"],
+	}
+
+	tests := []struct {
+		name        string
+		binlogs     []string
+		errantGTIDs string
+		want        []string
+		wantErr     string
+	}{
+		{
+			name:        "no-ops",
+			binlogs:     []string{"binlog.000001", "binlog.000002"},
+			errantGTIDs: errantTestReplicaUUID + ":3-4",
+			want:        []string{errantTestReplicaUUID + ":3", errantTestReplicaUUID + ":4"},
+		},
+		{
+			name:        "not a no-op",
+			binlogs:     []string{"binlog.000001", "binlog.000003"},
+			errantGTIDs: errantTestReplicaUUID + ":3-4",
+			wantErr:     "errant transaction " + errantTestReplicaUUID + ":4 is not a no-op: Table_map event",
+		},
+		{
+			name:        "purged binary logs",
+			binlogs:     []string{"binlog.000002"},
+			errantGTIDs: errantTestReplicaUUID + ":3-4",
+			wantErr:     "may have been purged",
+		},
+		{
+			name:        "too many errant GTIDs",
+			errantGTIDs: errantTestReplicaUUID + ":1-2000",
+			wantErr:     "more than the 1000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldTMC := tmc
+			defer func() { tmc = oldTMC }()
+			mockTMC := tmcmock.NewMockTabletManagerClient(gomock.NewController(t))
+			expectBinlogQueries(mockTMC, binlogQueries(binlogs, tt.binlogs...))
+			tmc = mockTMC
+
+			gtids, err := confirmErrantGTIDsAreNoOps(t.Context(), tablet, tt.errantGTIDs)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var got []string
+			for _, gtid := range gtids {
+				got = append(got, gtid.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// fakeEventStream is a logutil.EventStream of the given events. If hangCtx is set, the
+// stream then hangs until the context is done.
+type fakeEventStream struct {
+	events  []*logutilpb.Event
+	err     error
+	hangCtx context.Context
+}
+
+func (s *fakeEventStream) Recv() (*logutilpb.Event, error) {
+	if len(s.events) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		if s.hangCtx != nil {
+			<-s.hangCtx.Done()
+			return nil, s.hangCtx.Err()
+		}
+		return nil, io.EOF
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
+
+func TestRecoverErrantGTIDDetected(t *testing.T) {
+	const (
+		keyspace = "ks"
+		shard    = "0"
+	)
+	errantGTIDs := errantTestReplicaUUID + ":3-4"
+
+	tests := []struct {
+		name           string
+		actions        []string
+		drain          bool
+		replicaBinlogs []string
+		restoring      bool
+		restoreErr     error
+		restoreHangs   bool
+		wantInjected   string
+		wantRestore    bool
+		wantRestored   bool
+		wantDrained    bool
+		wantAttempted  bool
+		wantErr        string
+		wantAudits     []string
+	}{
+		{
+			name:           "inject empty transactions",
+			actions:        []string{config.ErrantGTIDRecoveryInjectEmptyTransactions, config.ErrantGTIDRecoveryRestoreFromBackup},
+			drain:          true,
+			replicaBinlogs: []string{"binlog.000001", "noOpBinlog"},
+			wantInjected: fmt.Sprintf("SET GTID_NEXT='%s:3'; BEGIN; COMMIT; SET GTID_NEXT='%s:4'; BEGIN; COMMIT; SET GTID_NEXT='AUTOMATIC'",
+				errantTestReplicaUUID, errantTestReplicaUUID),
+			wantAttempted: true,
+			wantAudits:    []string{"errant-gtid-detected", "errant-gtid-inject-empty-transactions", "errant-gtid-inject-empty-transactions"},
+		},
+		{
+			name:           "restore from backup when the errant transactions are not no-ops",
+			actions:        []string{config.ErrantGTIDRecoveryInjectEmptyTransactions, config.ErrantGTIDRecoveryRestoreFromBackup},
+			drain:          true,
+			replicaBinlogs: []string{"binlog.000001", "rowsBinlog"},
+			wantDrained:    true,
+			wantRestore:    true,
+			wantRestored:   true,
+			wantAttempted:  true,
+			wantAudits:     []string{"errant-gtid-detected", "errant-gtid-inject-empty-transactions", "errant-gtid-restore-from-backup", "errant-gtid-restore-from-backup", "errant-gtid-restore-from-backup"},
+		},
+		{
+			name:          "tablet stays drained when the restore fails",
+			actions:       []string{config.ErrantGTIDRecoveryRestoreFromBackup},
+			restoreErr:    errors.New("no backup"),
+			wantDrained:   true,
+			wantRestore:   true,
+			wantAttempted: true,
+			wantAudits:    []string{"errant-gtid-detected", "errant-gtid-restore-from-backup", "errant-gtid-restore-from-backup", "errant-gtid-restore-from-backup"},
+		},
+		{
+			name:          "tablet stays drained when the restore times out",
+			actions:       []string{config.ErrantGTIDRecoveryRestoreFromBackup},
+			restoreHangs:  true,
+			wantDrained:   true,
+			wantRestore:   true,
+			wantAttempted: true,
+			wantAudits:    []string{"errant-gtid-detected", "errant-gtid-restore-from-backup", "errant-gtid-restore-from-backup", "errant-gtid-restore-from-backup"},
+		},
+		{
+			name:      "tablet already being restored",
+			actions:   []string{config.ErrantGTIDRecoveryRestoreFromBackup},
+			restoring: true,
+		},
+		{
+			name:           "no action recovers the tablet",
+			actions:        []string{config.ErrantGTIDRecoveryInjectEmptyTransactions},
+			replicaBinlogs: []string{"rowsBinlog"},
+			wantAttempted:  true,
+			wantErr:        "no errant GTID recovery action recovered tablet zone1-0000000101",
+			wantAudits:     []string{"errant-gtid-detected", "errant-gtid-inject-empty-transactions"},
+		},
+	}
+
+	orcDB, err := db.OpenVTOrc()
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.ClearVTOrcDatabase()
+			defer db.ClearVTOrcDatabase()
+
+			primaryTablet := &topodatapb.Tablet{
+				Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				Hostname: "primary",
+				Keyspace: keyspace,
+				Shard:    shard,
+				Type:     topodatapb.TabletType_PRIMARY,
+			}
+			replicaTablet := &topodatapb.Tablet{
+				Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+				Hostname: "replica",
+				Keyspace: keyspace,
+				Shard:    shard,
+				Type:     topodatapb.TabletType_REPLICA,
+			}
+			require.NoError(t, inst.SaveTablet(primaryTablet))
+			require.NoError(t, inst.SaveTablet(replicaTablet))
+			keyspaceInfo := &topo.KeyspaceInfo{
+				Keyspace: &topodatapb.Keyspace{DurabilityPolicy: policy.DurabilityNone},
+			}
+			keyspaceInfo.SetKeyspaceName(keyspace)
+			require.NoError(t, inst.SaveKeyspace(keyspaceInfo))
+
+			oldActions := config.GetErrantGTIDRecoveryActions()
+			oldDrain := config.ConvertTabletWithErrantGTIDs()
+			oldAuditToBackend := config.GetAuditToBackend()
+			oldRestoreTimeout := config.GetErrantGTIDRestoreTimeout()
+			oldTS, oldTMC := ts, tmc
+			defer func() {
+				config.SetErrantGTIDRecoveryActions(oldActions)
+				config.SetConvertTabletWithErrantGTIDs(oldDrain)
+				config.SetAuditToBackend(oldAuditToBackend)
+				config.SetErrantGTIDRestoreTimeout(oldRestoreTimeout)
+				ts, tmc = oldTS, oldTMC
+			}()
+			config.SetErrantGTIDRecoveryActions(tt.actions)
+			config.SetConvertTabletWithErrantGTIDs(tt.drain)
+			config.SetAuditToBackend(true)
+			config.SetErrantGTIDRestoreTimeout(100 * time.Millisecond)
+			if tt.restoring {
+				restoresFromBackup.mu.Lock()
+				restoresFromBackup.running["zone1-0000000101"] = make(chan struct{})
+				restoresFromBackup.mu.Unlock()
+				defer func() {
+					restoresFromBackup.mu.Lock()
+					delete(restoresFromBackup.running, "zone1-0000000101")
+					restoresFromBackup.mu.Unlock()
+				}()
+			}
+
+			ctx := t.Context()
+			ts = memorytopo.NewServer(ctx, "zone1")
+			mockTMC := tmcmock.NewMockTabletManagerClient(gomock.NewController(t))
+			expectBinlogQueries(mockTMC, binlogQueries(errantTestBinlogs, tt.replicaBinlogs...))
+			var injected string
+			if tt.wantInjected != "" {
+				mockTMC.EXPECT().
+					ExecuteMultiFetchAsDba(gomock.Any(), primaryTablet, false, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *topodatapb.Tablet, _ bool, req *tabletmanagerdatapb.ExecuteMultiFetchAsDbaRequest) ([]*querypb.QueryResult, error) {
+						injected = string(req.Sql)
+						return nil, nil
+					})
+			}
+			if tt.wantDrained {
+				mockTMC.EXPECT().
+					ChangeType(gomock.Any(), replicaTablet, topodatapb.TabletType_DRAINED, false).
+					Return(nil)
+			}
+			if tt.wantRestore {
+				mockTMC.EXPECT().
+					RestoreFromBackup(gomock.Any(), replicaTablet, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, _ *topodatapb.Tablet, _ *tabletmanagerdatapb.RestoreFromBackupRequest) (logutil.EventStream, error) {
+						stream := &fakeEventStream{events: []*logutilpb.Event{{Value: "restoring"}}, err: tt.restoreErr}
+						if tt.restoreHangs {
+							stream.hangCtx = ctx
+						}
+						return stream, nil
+					})
+			}
+			if tt.wantRestored {
+				mockTMC.EXPECT().
+					ChangeType(gomock.Any(), replicaTablet, topodatapb.TabletType_REPLICA, false).
+					Return(nil)
+			}
+			tmc = mockTMC
+
+			analysisEntry := &inst.DetectionAnalysis{
+				Analysis:              inst.ErrantGTIDDetected,
+				AnalyzedInstanceAlias: replicaTablet.Alias,
+				AnalyzedKeyspace:      keyspace,
+				AnalyzedShard:         shard,
+				ErrantGTID:            errantGTIDs,
+			}
+			attempted, topologyRecovery, err := recoverErrantGTIDDetected(ctx, analysisEntry, log.NewPrefixedLogger("test-errant-gtid"))
+			require.Equal(t, tt.wantAttempted, attempted)
+			require.NotNil(t, topologyRecovery)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantInjected, injected)
+			if !tt.restoring {
+				// The recovery does not wait for the restore, which runs in the background.
+				if done := restoringFromBackup(replicaTablet.Alias); done != nil {
+					<-done
+				}
+			}
+
+			var audits []string
+			rows, err := orcDB.Query("select audit_type from audit where alias = 'zone1-0000000101' order by audit_id")
+			require.NoError(t, err)
+			defer rows.Close()
+			for rows.Next() {
+				var auditType string
+				require.NoError(t, rows.Scan(&auditType))
+				audits = append(audits, auditType)
+			}
+			assert.Equal(t, tt.wantAudits, audits)
+		})
+	}
+}
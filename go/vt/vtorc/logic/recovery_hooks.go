@@ -0,0 +1,211 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+const (
+	recoveryHookPhasePre  = "pre-recovery"
+	recoveryHookPhasePost = "post-recovery"
+
+	recoveryHookOutcomeSuccess      = "success"
+	recoveryHookOutcomeFailure      = "failure"
+	recoveryHookOutcomeNotAttempted = "not-attempted"
+
+	recoveryHookWebhook = "webhook"
+	recoveryHookCommand = "command"
+)
+
+// recoveryHooksFailureCounter counts the recovery hooks that failed after all their retries.
+var recoveryHooksFailureCounter = stats.NewCountersWithMultiLabels("RecoveryHookFailures", "Count of the recovery hooks that failed after all their retries", []string{"Hook", "Phase"})
+
+// recoveryHookEvent is the JSON description of a recovery that is sent to the recovery hooks.
+type recoveryHookEvent struct {
+	Phase        string    `json:"phase"`
+	AnalysisCode string    `json:"analysis_code"`
+	Recovery     string    `json:"recovery"`
+	Keyspace     string    `json:"keyspace"`
+	Shard        string    `json:"shard"`
+	TabletAlias  string    `json:"tablet_alias"`
+	OldPrimary   string    `json:"old_primary,omitempty"`
+	NewPrimary   string    `json:"new_primary,omitempty"`
+	Outcome      string    `json:"outcome,omitempty"`
+	Errors       []string  `json:"errors,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// recoveryHooksEnabled returns whether any recovery hook is configured.
+func recoveryHooksEnabled() bool {
+	return config.GetRecoveryWebhookURL() != "" || config.GetRecoveryHookCommand() != ""
+}
+
+// newPreRecoveryHookEvent returns the event describing the given recovery before it runs.
+func newPreRecoveryHookEvent(analysisEntry *inst.DetectionAnalysis, recoveryName string) *recoveryHookEvent {
+	event := &recoveryHookEvent{
+		Phase:        recoveryHookPhasePre,
+		AnalysisCode: string(analysisEntry.Analysis),
+		Recovery:     recoveryName,
+		Keyspace:     analysisEntry.AnalyzedKeyspace,
+		Shard:        analysisEntry.AnalyzedShard,
+		TabletAlias:  topoproto.TabletAliasString(analysisEntry.AnalyzedInstanceAlias),
+		Timestamp:    time.Now(),
+	}
+	if primary, err := shardPrimary(analysisEntry.AnalyzedKeyspace, analysisEntry.AnalyzedShard); err == nil {
+		event.OldPrimary = topoproto.TabletAliasString(primary.Alias)
+	}
+	return event
+}
+
+// newPostRecoveryHookEvent returns the event describing the outcome of the recovery described by the given pre-recovery event.
+func newPostRecoveryHookEvent(preEvent *recoveryHookEvent, recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error) *recoveryHookEvent {
+	event := *preEvent
+	event.Phase = recoveryHookPhasePost
+	event.Timestamp = time.Now()
+	switch {
+	case !recoveryAttempted:
+		event.Outcome = recoveryHookOutcomeNotAttempted
+	case err != nil:
+		event.Outcome = recoveryHookOutcomeFailure
+	default:
+		event.Outcome = recoveryHookOutcomeSuccess
+	}
+	if topologyRecovery != nil {
+		if topologyRecovery.SuccessorAlias != nil {
+			event.NewPrimary = topoproto.TabletAliasString(topologyRecovery.SuccessorAlias)
+		}
+		if len(topologyRecovery.AllErrors) > 0 {
+			event.Errors = topologyRecovery.AllErrors
+		}
+	}
+	if event.Errors == nil && err != nil {
+		event.Errors = []string{err.Error()}
+	}
+	return &event
+}
+
+// startRecoveryHooks runs the pre-recovery hooks for the given event in the background, so that they never
+// delay the recovery nor hold the shard lock. It returns the function to call with the outcome of the recovery,
+// which runs the post-recovery hooks in the background as well, once the pre-recovery hooks are done.
+func startRecoveryHooks(preEvent *recoveryHookEvent, logger *log.PrefixedLogger) (postRecovery func(recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error)) {
+	// The hooks outlive the recovery, and so don't use its context. Each attempt is bounded by
+	// --recovery-hooks-timeout.
+	preHooksDone := make(chan struct{})
+	go func() {
+		defer close(preHooksDone)
+		runRecoveryHooks(context.Background(), preEvent, logger)
+	}()
+	return func(recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error) {
+		postEvent := newPostRecoveryHookEvent(preEvent, recoveryAttempted, topologyRecovery, err)
+		go func() {
+			<-preHooksDone
+			runRecoveryHooks(context.Background(), postEvent, logger)
+		}()
+	}
+}
+
+// runRecoveryHooks runs the configured recovery webhook and hook command for the given event.
+// Failures are logged and counted, but never fail the recovery.
+func runRecoveryHooks(ctx context.Context, event *recoveryHookEvent, logger *log.PrefixedLogger) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to marshal %s hook event: %v", event.Phase, err))
+		return
+	}
+	if url := config.GetRecoveryWebhookURL(); url != "" {
+		runRecoveryHookWithRetries(ctx, recoveryHookWebhook, event.Phase, logger, func(ctx context.Context) error {
+			return postRecoveryWebhook(ctx, url, payload)
+		})
+	}
+	if command := config.GetRecoveryHookCommand(); command != "" {
+		runRecoveryHookWithRetries(ctx, recoveryHookCommand, event.Phase, logger, func(ctx context.Context) error {
+			return runRecoveryHookCommand(ctx, command, payload)
+		})
+	}
+}
+
+// runRecoveryHookWithRetries runs the given hook until it succeeds, retrying it --recovery-hooks-retries times.
+// Each attempt is bounded by --recovery-hooks-timeout.
+func runRecoveryHookWithRetries(ctx context.Context, hook string, phase string, logger *log.PrefixedLogger, run func(context.Context) error) {
+	retries := max(config.GetRecoveryHooksRetries(), 0)
+	var err error
+attempts:
+	for attempt := 0; attempt <= retries; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, config.GetRecoveryHooksTimeout())
+		err = run(attemptCtx)
+		cancel()
+		if err == nil {
+			return
+		}
+		logger.Warn(fmt.Sprintf("Recovery %s for %s failed (attempt %d of %d): %v", hook, phase, attempt+1, retries+1, err))
+		if attempt == retries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break attempts
+		case <-time.After(config.GetRecoveryHooksRetryDelay()):
+		}
+	}
+	logger.Error(fmt.Sprintf("Recovery %s for %s failed: %v", hook, phase, err))
+	recoveryHooksFailureCounter.Add([]string{hook, phase}, 1)
+}
+
+// postRecoveryWebhook posts the given payload to the recovery webhook.
+func postRecoveryWebhook(ctx context.Context, url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// runRecoveryHookCommand runs the recovery hook command with the given payload on its standard input.
+func runRecoveryHookCommand(ctx context.Context, command string, payload []byte) error {
+	cmd := exec.CommandContext(ctx, command)
+	cmd.Stdin = bytes.NewReader(payload)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
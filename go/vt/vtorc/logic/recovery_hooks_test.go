@@ -0,0 +1,230 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestRecoveryHookEvents(t *testing.T) {
+	db.ClearVTOrcDatabase()
+	defer db.ClearVTOrcDatabase()
+
+	primary := &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Hostname: "primary",
+		Keyspace: "ks",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}
+	require.NoError(t, inst.SaveTablet(primary))
+
+	analysisEntry := &inst.DetectionAnalysis{
+		Analysis:              inst.DeadPrimary,
+		AnalyzedInstanceAlias: primary.Alias,
+		AnalyzedKeyspace:      "ks",
+		AnalyzedShard:         "0",
+	}
+	preEvent := newPreRecoveryHookEvent(analysisEntry, "RecoverDeadPrimary")
+	assert.Equal(t, recoveryHookPhasePre, preEvent.Phase)
+	assert.EqualValues(t, inst.DeadPrimary, preEvent.AnalysisCode)
+	assert.Equal(t, "RecoverDeadPrimary", preEvent.Recovery)
+	assert.Equal(t, "zone1-0000000100", preEvent.TabletAlias)
+	assert.Equal(t, "zone1-0000000100", preEvent.OldPrimary)
+	assert.Empty(t, preEvent.Outcome)
+
+	topologyRecovery := NewTopologyRecovery(*analysisEntry)
+	topologyRecovery.SuccessorAlias = &topodatapb.TabletAlias{Cell: "zone1", Uid: 101}
+
+	tests := []struct {
+		name              string
+		recoveryAttempted bool
+		topologyRecovery  *TopologyRecovery
+		err               error
+		wantOutcome       string
+		wantNewPrimary    string
+		wantErrors        []string
+	}{
+		{
+			name:              "success",
+			recoveryAttempted: true,
+			topologyRecovery:  topologyRecovery,
+			wantOutcome:       recoveryHookOutcomeSuccess,
+			wantNewPrimary:    "zone1-0000000101",
+		},
+		{
+			name:              "failure",
+			recoveryAttempted: true,
+			err:               errors.New("ERS failed"),
+			wantOutcome:       recoveryHookOutcomeFailure,
+			wantErrors:        []string{"ERS failed"},
+		},
+		{
+			name:        "not attempted",
+			err:         errors.New("recovery already in progress"),
+			wantOutcome: recoveryHookOutcomeNotAttempted,
+			wantErrors:  []string{"recovery already in progress"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postEvent := newPostRecoveryHookEvent(preEvent, tt.recoveryAttempted, tt.topologyRecovery, tt.err)
+			assert.Equal(t, recoveryHookPhasePost, postEvent.Phase)
+			assert.Equal(t, "zone1-0000000100", postEvent.OldPrimary)
+			assert.Equal(t, tt.wantNewPrimary, postEvent.NewPrimary)
+			assert.Equal(t, tt.wantOutcome, postEvent.Outcome)
+			assert.Equal(t, tt.wantErrors, postEvent.Errors)
+		})
+	}
+}
+
+func TestRunRecoveryHooks(t *testing.T) {
+	oldURL := config.GetRecoveryWebhookURL()
+	oldCommand := config.GetRecoveryHookCommand()
+	oldRetries := config.GetRecoveryHooksRetries()
+	oldRetryDelay := config.GetRecoveryHooksRetryDelay()
+	defer func() {
+		config.SetRecoveryWebhookURL(oldURL)
+		config.SetRecoveryHookCommand(oldCommand)
+		config.SetRecoveryHooksRetries(oldRetries)
+		config.SetRecoveryHooksRetryDelay(oldRetryDelay)
+	}()
+	config.SetRecoveryHooksRetryDelay(time.Millisecond)
+
+	event := &recoveryHookEvent{
+		Phase:        recoveryHookPhasePost,
+		AnalysisCode: string(inst.DeadPrimary),
+		Recovery:     "RecoverDeadPrimary",
+		Keyspace:     "ks",
+		Shard:        "0",
+		TabletAlias:  "zone1-0000000100",
+		OldPrimary:   "zone1-0000000100",
+		NewPrimary:   "zone1-0000000101",
+		Outcome:      recoveryHookOutcomeSuccess,
+	}
+
+	t.Run("webhook", func(t *testing.T) {
+		var requests int
+		var received recoveryHookEvent
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(body, &received))
+		}))
+		defer server.Close()
+
+		config.SetRecoveryWebhookURL(server.URL)
+		config.SetRecoveryHookCommand("")
+		config.SetRecoveryHooksRetries(1)
+		runRecoveryHooks(t.Context(), event, log.NewPrefixedLogger("test-recovery-hooks"))
+		assert.Equal(t, 2, requests)
+		assert.Equal(t, *event, received)
+
+		// The webhook is not retried more than --recovery-hooks-retries times.
+		requests = 0
+		config.SetRecoveryHooksRetries(0)
+		failures := recoveryHooksFailureCounter.Counts()["webhook.post-recovery"]
+		runRecoveryHooks(t.Context(), event, log.NewPrefixedLogger("test-recovery-hooks"))
+		assert.Equal(t, 1, requests)
+		assert.Equal(t, failures+1, recoveryHooksFailureCounter.Counts()["webhook.post-recovery"])
+	})
+
+	t.Run("command", func(t *testing.T) {
+		dir := t.TempDir()
+		output := filepath.Join(dir, "event.json")
+		command := filepath.Join(dir, "hook.sh")
+		require.NoError(t, os.WriteFile(command, []byte("#!/bin/sh\ncat > "+output+"\n"), 0o755))
+
+		config.SetRecoveryWebhookURL("")
+		config.SetRecoveryHookCommand(command)
+		runRecoveryHooks(t.Context(), event, log.NewPrefixedLogger("test-recovery-hooks"))
+
+		body, err := os.ReadFile(output)
+		require.NoError(t, err)
+		var received recoveryHookEvent
+		require.NoError(t, json.Unmarshal(body, &received))
+		assert.Equal(t, *event, received)
+	})
+}
+
+func TestStartRecoveryHooks(t *testing.T) {
+	oldURL := config.GetRecoveryWebhookURL()
+	oldCommand := config.GetRecoveryHookCommand()
+	defer func() {
+		config.SetRecoveryWebhookURL(oldURL)
+		config.SetRecoveryHookCommand(oldCommand)
+	}()
+
+	release := make(chan struct{})
+	phases := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var received recoveryHookEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received.Phase == recoveryHookPhasePre {
+			// A slow pre-recovery hook.
+			<-release
+		}
+		phases <- received.Phase
+	}))
+	defer server.Close()
+	config.SetRecoveryWebhookURL(server.URL)
+	config.SetRecoveryHookCommand("")
+
+	preEvent := &recoveryHookEvent{
+		Phase:        recoveryHookPhasePre,
+		AnalysisCode: string(inst.DeadPrimary),
+		Keyspace:     "ks",
+		Shard:        "0",
+	}
+	// Neither the pre-recovery nor the post-recovery hooks block the recovery.
+	postRecovery := startRecoveryHooks(preEvent, log.NewPrefixedLogger("test-recovery-hooks"))
+	postRecovery(true, nil, nil)
+	select {
+	case phase := <-phases:
+		assert.Fail(t, "hook should still be blocked", phase)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The post-recovery hooks run once the pre-recovery hooks are done.
+	close(release)
+	assert.Equal(t, recoveryHookPhasePre, <-phases)
+	assert.Equal(t, recoveryHookPhasePost, <-phases)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+// This file holds the routines to silence the recoveries of a keyspace,
+// shard or tablet during a planned maintenance.
+//
+// Recovery maintenances are stored in the VTOrc state of the keyspace and
+// shard records in the topology server, so that all the VTOrc instances
+// watching the keyspace respect them. Each VTOrc instance copies them into
+// its backend database when it refreshes the keyspace and shard records,
+// and the recoveries of the silenced keyspace, shard or tablet are skipped
+// until the maintenance expires.
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/topotools"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
+)
+
+// SetRecoveryMaintenance silences the recoveries of the given keyspace, shard or tablet for the given
+// duration, and returns the maintenance. If tabletAlias is set, the keyspace and shard of the tablet are used.
+func SetRecoveryMaintenance(ctx context.Context, keyspace, shard string, tabletAlias *topodatapb.TabletAlias, reason string, duration time.Duration) (*vtorcdatapb.RecoveryMaintenance, error) {
+	if duration <= 0 {
+		return nil, errors.New("a positive duration is required to set a recovery maintenance")
+	}
+	maintenance := &vtorcdatapb.RecoveryMaintenance{
+		Reason:     reason,
+		ExpireTime: protoutil.TimeToProto(time.Now().Add(duration)),
+	}
+	if err := updateRecoveryMaintenance(ctx, keyspace, shard, tabletAlias, maintenance); err != nil {
+		return nil, err
+	}
+	return maintenance, nil
+}
+
+// ClearRecoveryMaintenance ends the recovery maintenance of the given keyspace, shard or tablet.
+func ClearRecoveryMaintenance(ctx context.Context, keyspace, shard string, tabletAlias *topodatapb.TabletAlias) error {
+	return updateRecoveryMaintenance(ctx, keyspace, shard, tabletAlias, nil)
+}
+
+// updateRecoveryMaintenance updates the recovery maintenance in the topology server, and refreshes the keyspace
+// and shard records so that it is respected right away.
+func updateRecoveryMaintenance(ctx context.Context, keyspace, shard string, tabletAlias *topodatapb.TabletAlias, maintenance *vtorcdatapb.RecoveryMaintenance) (err error) {
+	var tabletAliasString string
+	if tabletAlias != nil {
+		tablet, err := inst.ReadTablet(tabletAlias)
+		if err != nil {
+			return err
+		}
+		keyspace, shard = tablet.Keyspace, tablet.Shard
+		tabletAliasString = topoproto.TabletAliasString(tabletAlias)
+	}
+	if keyspace == "" {
+		return errors.New("keyspace or tablet alias is required")
+	}
+
+	lockCtx, unlock, err := ts.LockKeyspace(ctx, keyspace, "SetVtorcRecoveryMaintenance")
+	if err != nil {
+		return err
+	}
+	err = topotools.SetVtorcRecoveryMaintenance(lockCtx, ts, keyspace, shard, tabletAliasString, maintenance)
+	unlock(&err)
+	if err != nil {
+		return err
+	}
+
+	if shard == "" {
+		return refreshKeyspace(keyspace)
+	}
+	return refreshShard(keyspace, shard)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestRecoveryMaintenance(t *testing.T) {
+	db.ClearVTOrcDatabase()
+	defer db.ClearVTOrcDatabase()
+
+	oldTs := ts
+	defer func() {
+		ts = oldTs
+	}()
+	ctx := t.Context()
+	ts = memorytopo.NewServer(ctx, "zone1")
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "-80"))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "80-"))
+	require.NoError(t, RefreshKeyspaceAndShard("ks", "-80"))
+	require.NoError(t, refreshShard("ks", "80-"))
+
+	replica := &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+		Hostname: "localhost",
+		Keyspace: "ks",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_REPLICA,
+	}
+	require.NoError(t, inst.SaveTablet(replica))
+
+	analysisEntry := &inst.DetectionAnalysis{
+		Analysis:              inst.ReplicationStopped,
+		AnalyzedInstanceAlias: replica.Alias,
+		AnalyzedKeyspace:      "ks",
+		AnalyzedShard:         "-80",
+	}
+	skippedRecoveries := func() int64 {
+		return recoveriesSkippedCounter.Counts()["FixReplica.ks.-80.RecoveryMaintenance"]
+	}
+
+	// Silence the recoveries of the tablet.
+	maintenance, err := SetRecoveryMaintenance(ctx, "", "", replica.Alias, "disk replacement", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "disk replacement", maintenance.Reason)
+
+	si, err := ts.GetShard(ctx, "ks", "-80")
+	require.NoError(t, err)
+	assert.NotNil(t, si.VtorcState.GetTabletRecoveryMaintenance()["zone1-0000000101"])
+
+	got, err := inst.ReadRecoveryMaintenance("ks", "-80", "zone1-0000000101")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "disk replacement", got.Reason)
+
+	skipped := skippedRecoveries()
+	require.NoError(t, executeCheckAndRecoverFunction(analysisEntry))
+	assert.Equal(t, skipped+1, skippedRecoveries())
+
+	// The other shard is not silenced.
+	got, err = inst.ReadRecoveryMaintenance("ks", "80-", "zone1-0000000201")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// Clear the maintenance of the tablet, and silence the recoveries of the keyspace.
+	require.NoError(t, ClearRecoveryMaintenance(ctx, "", "", replica.Alias))
+	got, err = inst.ReadRecoveryMaintenance("ks", "-80", "zone1-0000000101")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = SetRecoveryMaintenance(ctx, "ks", "", nil, "upgrade", time.Hour)
+	require.NoError(t, err)
+	got, err = inst.ReadRecoveryMaintenance("ks", "80-", "zone1-0000000201")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "upgrade", got.Reason)
+
+	_, err = SetRecoveryMaintenance(ctx, "ks", "", nil, "upgrade", 0)
+	assert.ErrorContains(t, err, "a positive duration is required")
+	assert.ErrorContains(t, ClearRecoveryMaintenance(ctx, "", "-80", nil), "keyspace or tablet alias is required")
+}
@@ -78,6 +78,7 @@ const (
 	RecoverySkipERSDisabled
 	RecoverySkipStaleAnalysis
 	RecoverySkipPrimaryRecovery
+	RecoverySkipMaintenance
 )
 
 // String represents a RecoverySkip as a string.
@@ -93,6 +94,8 @@ func (rsc RecoverySkipCode) String() string {
 		return "StaleAnalysis"
 	case RecoverySkipPrimaryRecovery:
 		return "PrimaryRecovery"
+	case RecoverySkipMaintenance:
+		return "RecoveryMaintenance"
 	default:
 		return "None"
 	}
@@ -679,7 +682,7 @@ func getCheckAndRecoverFunctionCode(analysisEntry *inst.DetectionAnalysis) (reco
 		}
 		recoveryFunc = recoverPrimaryTabletDeletedFunc
 	case inst.ErrantGTIDDetected:
-		if !config.ConvertTabletWithErrantGTIDs() {
+		if !config.ConvertTabletWithErrantGTIDs() && len(config.GetErrantGTIDRecoveryActions()) == 0 {
 			log.Info(fmt.Sprintf("VTOrc not configured to do anything on detecting errant GTIDs, skipping recovering %v", analysisCode))
 			recoverySkipCode = RecoverySkipNoRecoveryAction
 		}
@@ -927,6 +930,18 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.DetectionAnalysis) (err
 		return err
 	}
 
+	// Check for recoveries being silenced by a maintenance of the keyspace, shard or tablet
+	if maintenance, err := inst.ReadRecoveryMaintenance(analysisEntry.AnalyzedKeyspace, analysisEntry.AnalyzedShard, analyzedInstanceAliasString); err != nil {
+		// Unexpected. Shouldn't get this
+		logger.Error(fmt.Sprintf("Unable to determine if recovery is silenced by a maintenance, still attempting to recover: %v", err))
+	} else if maintenance != nil {
+		logger.Info(fmt.Sprintf("CheckAndRecover: Tablet: %+v: NOT Recovering host (maintenance until %v: %s)",
+			analyzedInstanceAliasString, maintenance.ExpireTimestamp, maintenance.Reason))
+		recoveriesSkippedCounter.Add(append(recoveryLabels, RecoverySkipMaintenance.String()), 1)
+
+		return nil
+	}
+
 	// Prioritise primary recovery.
 	// If we are performing some other action, first ensure that it is not because of primary issues.
 	// This step is only meant to improve the time taken to detect and fix shard-wide recoveries, it does not impact correctness.
@@ -1021,7 +1036,15 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.DetectionAnalysis) (err
 			logger.Info(fmt.Sprintf("Analysis: %v, %v %+v", analysisEntry.Analysis, recoveryName, analyzedInstanceAliasString))
 		}
 	}
+	var postRecoveryHooks func(recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error)
+	if recoveryHooksEnabled() {
+		// The hooks run in the background, a slow hook must neither delay the recovery nor keep the shard locked.
+		postRecoveryHooks = startRecoveryHooks(newPreRecoveryHookEvent(analysisEntry, recoveryName), logger)
+	}
 	recoveryAttempted, topologyRecovery, err := getCheckAndRecoverFunction(checkAndRecoverFunctionCode)(ctx, analysisEntry, logger)
+	if postRecoveryHooks != nil {
+		postRecoveryHooks(recoveryAttempted, topologyRecovery, err)
+	}
 	if !recoveryAttempted {
 		logger.Error(fmt.Sprintf("Recovery not attempted: %+v", err))
 		return err
@@ -1485,7 +1508,10 @@ func changeTabletTypeInTopo(ctx context.Context, tablet *topodatapb.Tablet, tabl
 	return topotools.ChangeType(ctx, ts, tablet.Alias, tabletType, nil)
 }
 
-// recoverErrantGTIDDetected changes the tablet type of a replica tablet that has errant GTIDs.
+// recoverErrantGTIDDetected recovers a replica tablet that has errant GTIDs. It tries the
+// --errant-gtid-recovery-actions in order, and changes the tablet type to DRAINED if none of
+// them recovers the tablet and --change-tablets-with-errant-gtid-to-drained is set. A restore
+// from backup runs in the background, after the recovery releases the shard lock.
 func recoverErrantGTIDDetected(ctx context.Context, analysisEntry *inst.DetectionAnalysis, logger *log.PrefixedLogger) (recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error) {
 	topologyRecovery, err = AttemptRecoveryRegistration(analysisEntry)
 	if topologyRecovery == nil {
@@ -1518,12 +1544,63 @@ func recoverErrantGTIDDetected(ctx context.Context, analysisEntry *inst.Detectio
 		return false, topologyRecovery, err
 	}
 
+	if restoringFromBackup(analyzedTablet.Alias) != nil {
+		logger.Info(fmt.Sprintf("Tablet %v is being restored from backup, will not issue another recoverErrantGTIDDetected", topoproto.TabletAliasString(analyzedTablet.Alias)))
+		return false, topologyRecovery, nil
+	}
+
 	durabilityPolicy, err := inst.GetDurabilityPolicy(analyzedTablet.Keyspace)
 	if err != nil {
 		logger.Info(fmt.Sprintf("Could not read the durability policy for %v/%v", analyzedTablet.Keyspace, analyzedTablet.Shard))
 		return false, topologyRecovery, err
 	}
+	semiSync := policy.IsReplicaSemiSync(durabilityPolicy, primaryTablet, analyzedTablet)
+
+	auditErrantGTIDRecovery(topologyRecovery, "errant-gtid-detected", analyzedTablet.Alias, fmt.Sprintf("errant GTIDs %v detected, primary is %v", analysisEntry.ErrantGTID, topoproto.TabletAliasString(primaryTablet.Alias)))
+	for _, action := range config.GetErrantGTIDRecoveryActions() {
+		var actionErr error
+		recovered := fmt.Sprintf("recovered errant GTIDs %v", analysisEntry.ErrantGTID)
+		switch action {
+		case config.ErrantGTIDRecoveryInjectEmptyTransactions:
+			actionErr = recoverErrantGTIDsWithEmptyTransactions(ctx, analyzedTablet, primaryTablet, analysisEntry.ErrantGTID, topologyRecovery)
+		case config.ErrantGTIDRecoveryRestoreFromBackup:
+			auditErrantGTIDRecovery(topologyRecovery, "errant-gtid-restore-from-backup", analyzedTablet.Alias, "changing tablet type to DRAINED and restoring from the most recent backup")
+			actionErr = startRestoreFromBackup(ctx, analyzedTablet, semiSync, logger)
+			recovered = "restoring from backup in the background"
+		default:
+			actionErr = fmt.Errorf("unknown errant GTID recovery action %q", action)
+		}
+		if actionErr != nil {
+			_ = topologyRecovery.AddError(actionErr)
+			auditErrantGTIDRecovery(topologyRecovery, "errant-gtid-"+action, analyzedTablet.Alias, fmt.Sprintf("failed: %v", actionErr))
+			continue
+		}
+		auditErrantGTIDRecovery(topologyRecovery, "errant-gtid-"+action, analyzedTablet.Alias, recovered)
+		return true, topologyRecovery, nil
+	}
+
+	if !config.ConvertTabletWithErrantGTIDs() {
+		return true, topologyRecovery, fmt.Errorf("no errant GTID recovery action recovered tablet %v", topoproto.TabletAliasString(analyzedTablet.Alias))
+	}
 
-	err = changeTabletType(ctx, analyzedTablet, topodatapb.TabletType_DRAINED, policy.IsReplicaSemiSync(durabilityPolicy, primaryTablet, analyzedTablet))
+	auditErrantGTIDRecovery(topologyRecovery, "errant-gtid-drain", analyzedTablet.Alias, "changing tablet type to DRAINED")
+	err = changeTabletType(ctx, analyzedTablet, topodatapb.TabletType_DRAINED, semiSync)
 	return true, topologyRecovery, err
 }
+
+// recoverErrantGTIDsWithEmptyTransactions injects empty transactions on the primary for the
+// errant GTIDs of the given replica, if the errant transactions are confirmed to be no-ops.
+func recoverErrantGTIDsWithEmptyTransactions(ctx context.Context, replica *topodatapb.Tablet, primary *topodatapb.Tablet, errantGTIDs string, topologyRecovery *TopologyRecovery) error {
+	gtids, err := confirmErrantGTIDsAreNoOps(ctx, replica, errantGTIDs)
+	if err != nil {
+		return err
+	}
+	auditErrantGTIDRecovery(topologyRecovery, "errant-gtid-inject-empty-transactions", replica.Alias, fmt.Sprintf("errant transactions %v are no-ops, injecting empty transactions on primary %v", errantGTIDs, topoproto.TabletAliasString(primary.Alias)))
+	return injectEmptyTransactions(ctx, primary, gtids)
+}
+
+// auditErrantGTIDRecovery writes an audit entry and a topology recovery step for a step of an errant GTID recovery.
+func auditErrantGTIDRecovery(topologyRecovery *TopologyRecovery, auditType string, tabletAlias *topodatapb.TabletAlias, message string) {
+	_ = inst.AuditOperation(auditType, tabletAlias, message)
+	_ = AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("%s: %s: %s", auditType, topoproto.TabletAliasString(tabletAlias), message))
+}
@@ -261,6 +261,7 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 		name                         string
 		ersEnabled                   bool
 		convertTabletWithErrantGTIDs bool
+		errantGTIDRecoveryActions    []string
 		analysisEntry                *inst.DetectionAnalysis
 		wantRecoveryFunction         recoveryFunction
 		wantRecoverySkipCode         RecoverySkipCode
@@ -409,6 +410,17 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 			},
 			wantRecoveryFunction: recoverErrantGTIDDetectedFunc,
 			wantRecoverySkipCode: RecoverySkipNoRecoveryAction,
+		}, {
+			name:                         "ErrantGTIDDetected with --errant-gtid-recovery-actions",
+			ersEnabled:                   false,
+			convertTabletWithErrantGTIDs: false,
+			errantGTIDRecoveryActions:    []string{config.ErrantGTIDRecoveryInjectEmptyTransactions},
+			analysisEntry: &inst.DetectionAnalysis{
+				Analysis:         inst.ErrantGTIDDetected,
+				AnalyzedKeyspace: keyspace,
+				AnalyzedShard:    shard,
+			},
+			wantRecoveryFunction: recoverErrantGTIDDetectedFunc,
 		}, {
 			name:       "DeadPrimary with global ERS enabled and keyspace ERS disabled",
 			ersEnabled: true,
@@ -464,6 +476,10 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 			config.SetConvertTabletWithErrantGTIDs(tt.convertTabletWithErrantGTIDs)
 			defer config.SetConvertTabletWithErrantGTIDs(convertErrantVal)
 
+			errantActionsVal := config.GetErrantGTIDRecoveryActions()
+			config.SetErrantGTIDRecoveryActions(tt.errantGTIDRecoveryActions)
+			defer config.SetErrantGTIDRecoveryActions(errantActionsVal)
+
 			gotFunc, recoverySkipCode := getCheckAndRecoverFunctionCode(tt.analysisEntry)
 			require.EqualValues(t, tt.wantRecoveryFunction, gotFunc)
 			require.EqualValues(t, tt.wantRecoverySkipCode.String(), recoverySkipCode.String())
@@ -21,14 +21,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/viperutil/debug"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtorc/inst"
 	"vitess.io/vitess/go/vt/vtorc/logic"
 	"vitess.io/vitess/go/vt/vtorc/process"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 // vtorcAPI struct is created to implement the Handler interface to register
@@ -38,17 +42,21 @@ import (
 type vtorcAPI struct{}
 
 const (
-	problemsAPI                = "/api/problems"
-	errantGTIDsAPI             = "/api/errant-gtids"
-	disableGlobalRecoveriesAPI = "/api/disable-global-recoveries"
-	enableGlobalRecoveriesAPI  = "/api/enable-global-recoveries"
-	detectionAnalysisAPI       = "/api/detection-analysis"
-	databaseStateAPI           = "/api/database-state"
-	configAPI                  = "/api/config"
-	healthAPI                  = "/debug/health"
+	problemsAPI                 = "/api/problems"
+	errantGTIDsAPI              = "/api/errant-gtids"
+	disableGlobalRecoveriesAPI  = "/api/disable-global-recoveries"
+	enableGlobalRecoveriesAPI   = "/api/enable-global-recoveries"
+	recoveryMaintenanceAPI      = "/api/recovery-maintenance"
+	setRecoveryMaintenanceAPI   = "/api/set-recovery-maintenance"
+	clearRecoveryMaintenanceAPI = "/api/clear-recovery-maintenance"
+	detectionAnalysisAPI        = "/api/detection-analysis"
+	databaseStateAPI            = "/api/database-state"
+	configAPI                   = "/api/config"
+	healthAPI                   = "/debug/health"
 
 	shardWithoutKeyspaceFilteringErrorStr = "Filtering by shard without keyspace isn't supported"
 	notAValidValueForSeconds              = "Invalid value for seconds"
+	keyspaceOrTabletRequiredErrorStr      = "Either a keyspace or a tablet is required"
 )
 
 var (
@@ -58,6 +66,9 @@ var (
 		errantGTIDsAPI,
 		disableGlobalRecoveriesAPI,
 		enableGlobalRecoveriesAPI,
+		recoveryMaintenanceAPI,
+		setRecoveryMaintenanceAPI,
+		clearRecoveryMaintenanceAPI,
 		detectionAnalysisAPI,
 		databaseStateAPI,
 		configAPI,
@@ -78,6 +89,12 @@ func (v *vtorcAPI) ServeHTTP(response http.ResponseWriter, request *http.Request
 		disableGlobalRecoveriesAPIHandler(response)
 	case enableGlobalRecoveriesAPI:
 		enableGlobalRecoveriesAPIHandler(response)
+	case recoveryMaintenanceAPI:
+		recoveryMaintenanceAPIHandler(response, request)
+	case setRecoveryMaintenanceAPI:
+		setRecoveryMaintenanceAPIHandler(response, request)
+	case clearRecoveryMaintenanceAPI:
+		clearRecoveryMaintenanceAPIHandler(response, request)
 	case healthAPI:
 		healthAPIHandler(response, request)
 	case problemsAPI:
@@ -100,9 +117,9 @@ func (v *vtorcAPI) ServeHTTP(response http.ResponseWriter, request *http.Request
 // getACLPermissionLevelForAPI returns the acl permission level that is required to run a given API
 func getACLPermissionLevelForAPI(apiEndpoint string) string {
 	switch apiEndpoint {
-	case problemsAPI, errantGTIDsAPI:
+	case problemsAPI, errantGTIDsAPI, recoveryMaintenanceAPI:
 		return acl.MONITORING
-	case disableGlobalRecoveriesAPI, enableGlobalRecoveriesAPI:
+	case disableGlobalRecoveriesAPI, enableGlobalRecoveriesAPI, setRecoveryMaintenanceAPI, clearRecoveryMaintenanceAPI:
 		return acl.ADMIN
 	case detectionAnalysisAPI, configAPI:
 		return acl.MONITORING
@@ -217,6 +234,75 @@ func enableGlobalRecoveriesAPIHandler(response http.ResponseWriter) {
 	writePlainTextResponse(response, "Global recoveries enabled", http.StatusOK)
 }
 
+// recoveryMaintenanceAPIHandler is the handler for the recoveryMaintenanceAPI endpoint
+func recoveryMaintenanceAPIHandler(response http.ResponseWriter, request *http.Request) {
+	// This api also supports filtering by shard and keyspace provided.
+	shard := request.URL.Query().Get("shard")
+	keyspace := request.URL.Query().Get("keyspace")
+	if shard != "" && keyspace == "" {
+		http.Error(response, shardWithoutKeyspaceFilteringErrorStr, http.StatusBadRequest)
+		return
+	}
+	maintenances, err := inst.ReadRecoveryMaintenances(keyspace, shard)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	returnAsJSON(response, http.StatusOK, maintenances)
+}
+
+// setRecoveryMaintenanceAPIHandler is the handler for the setRecoveryMaintenanceAPI endpoint
+func setRecoveryMaintenanceAPIHandler(response http.ResponseWriter, request *http.Request) {
+	keyspace, shard, tabletAlias, ok := parseRecoveryMaintenanceTarget(response, request)
+	if !ok {
+		return
+	}
+	duration, err := time.ParseDuration(request.URL.Query().Get("duration"))
+	if err != nil {
+		http.Error(response, fmt.Sprintf("Invalid value for duration: %v", err), http.StatusBadRequest)
+		return
+	}
+	maintenance, err := logic.SetRecoveryMaintenance(request.Context(), keyspace, shard, tabletAlias, request.URL.Query().Get("reason"), duration)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writePlainTextResponse(response, fmt.Sprintf("Recoveries silenced until %v", protoutil.TimeFromProto(maintenance.ExpireTime).UTC()), http.StatusOK)
+}
+
+// clearRecoveryMaintenanceAPIHandler is the handler for the clearRecoveryMaintenanceAPI endpoint
+func clearRecoveryMaintenanceAPIHandler(response http.ResponseWriter, request *http.Request) {
+	keyspace, shard, tabletAlias, ok := parseRecoveryMaintenanceTarget(response, request)
+	if !ok {
+		return
+	}
+	if err := logic.ClearRecoveryMaintenance(request.Context(), keyspace, shard, tabletAlias); err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writePlainTextResponse(response, "Recovery maintenance cleared", http.StatusOK)
+}
+
+// parseRecoveryMaintenanceTarget parses the keyspace, shard or tablet of a recovery maintenance from the request.
+// It writes an error response and returns false if they are invalid.
+func parseRecoveryMaintenanceTarget(response http.ResponseWriter, request *http.Request) (keyspace string, shard string, tabletAlias *topodatapb.TabletAlias, ok bool) {
+	shard = request.URL.Query().Get("shard")
+	keyspace = request.URL.Query().Get("keyspace")
+	tablet := request.URL.Query().Get("tablet")
+	switch {
+	case tablet != "":
+		var err error
+		if tabletAlias, err = topoproto.ParseTabletAlias(tablet); err != nil {
+			http.Error(response, err.Error(), http.StatusBadRequest)
+			return "", "", nil, false
+		}
+	case keyspace == "":
+		http.Error(response, keyspaceOrTabletRequiredErrorStr, http.StatusBadRequest)
+		return "", "", nil, false
+	}
+	return keyspace, shard, tabletAlias, true
+}
+
 // LegacyDetectionAnalysisJSON is a wrapper to *inst.DetectionAnalysis that
 // provides the AnalyzedInstanceAlias field in the old string-based format.
 type LegacyDetectionAnalysisJSON struct {
@@ -25,6 +25,15 @@ func TestGetACLPermissionLevelForAPI(t *testing.T) {
 		}, {
 			apiEndpoint: enableGlobalRecoveriesAPI,
 			want:        acl.ADMIN,
+		}, {
+			apiEndpoint: recoveryMaintenanceAPI,
+			want:        acl.MONITORING,
+		}, {
+			apiEndpoint: setRecoveryMaintenanceAPI,
+			want:        acl.ADMIN,
+		}, {
+			apiEndpoint: clearRecoveryMaintenanceAPI,
+			want:        acl.ADMIN,
 		}, {
 			apiEndpoint: detectionAnalysisAPI,
 			want:        acl.MONITORING,
@@ -24,6 +24,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"vitess.io/vitess/go/timer"
 )
 
 /*
@@ -53,6 +55,15 @@ type VReplicationConfig struct {
 	EnableHttpLog           bool // Enable the /debug/vrlog endpoint
 	MaxRowJSONBytes         int64
 
+	// Rate limits of the copy (vcopier) and replication (vplayer) phases: 0 means unlimited.
+	CopyMaxRowsPerSecond    int64
+	CopyMaxBytesPerSecond   int64
+	PlayerMaxRowsPerSecond  int64
+	PlayerMaxBytesPerSecond int64
+	// ScheduleWindow is the time-of-day window during which the workflow copies and applies rows, see
+	// timer.ParseScheduleWindow. Empty means always.
+	ScheduleWindow string
+
 	// Config parameters applicable to the source side (vstreamer)
 	// The coresponding Override fields are used to determine if the user has provided a value for the parameter so
 	// that they can be sent in the VStreamer API calls to the source.
@@ -98,6 +109,11 @@ func GetVReplicationConfigDefaults(useCached bool) *VReplicationConfig {
 		TabletTypesStr:          vreplicationTabletTypesStr,
 		EnableHttpLog:           vreplicationEnableHttpLog,
 		MaxRowJSONBytes:         vreplicationMaxRowJSONBytes,
+		CopyMaxRowsPerSecond:    vreplicationCopyMaxRowsPerSecond,
+		CopyMaxBytesPerSecond:   vreplicationCopyMaxBytesPerSecond,
+		PlayerMaxRowsPerSecond:  vreplicationPlayerMaxRowsPerSecond,
+		PlayerMaxBytesPerSecond: vreplicationPlayerMaxBytesPerSecond,
+		ScheduleWindow:          vreplicationScheduleWindow,
 
 		VStreamPacketSizeOverride:              false,
 		VStreamPacketSize:                      VStreamerDefaultPacketSize,
@@ -253,6 +269,40 @@ func NewVReplicationConfig(overrides map[string]string) (*VReplicationConfig, er
 			} else {
 				c.MaxRowJSONBytes = value
 			}
+		case "vreplication-copy-max-rows-per-second":
+			value, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || value < 0 {
+				errors = append(errors, getError(k, v))
+			} else {
+				c.CopyMaxRowsPerSecond = value
+			}
+		case "vreplication-copy-max-bytes-per-second":
+			value, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || value < 0 {
+				errors = append(errors, getError(k, v))
+			} else {
+				c.CopyMaxBytesPerSecond = value
+			}
+		case "vreplication-player-max-rows-per-second":
+			value, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || value < 0 {
+				errors = append(errors, getError(k, v))
+			} else {
+				c.PlayerMaxRowsPerSecond = value
+			}
+		case "vreplication-player-max-bytes-per-second":
+			value, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || value < 0 {
+				errors = append(errors, getError(k, v))
+			} else {
+				c.PlayerMaxBytesPerSecond = value
+			}
+		case "vreplication-schedule-window":
+			if _, err := timer.ParseScheduleWindow(v); err != nil {
+				errors = append(errors, getError(k, v))
+			} else {
+				c.ScheduleWindow = v
+			}
 		default:
 			errors = append(errors, "unknown vreplication config flag: "+k)
 		}
@@ -268,26 +318,31 @@ func NewVReplicationConfig(overrides map[string]string) (*VReplicationConfig, er
 // keys are one of those that are supported.
 func (c VReplicationConfig) Map() map[string]string {
 	return map[string]string{
-		"vreplication-experimental-flags":         strconv.FormatInt(c.ExperimentalFlags, 10),
-		"vreplication-net-read-timeout":           strconv.Itoa(c.NetReadTimeout),
-		"vreplication-net-write-timeout":          strconv.Itoa(c.NetWriteTimeout),
-		"vreplication-copy-phase-duration":        c.CopyPhaseDuration.String(),
-		"vreplication-retry-delay":                c.RetryDelay.String(),
-		"vreplication-max-time-to-retry-on-error": c.MaxTimeToRetryError.String(),
-		"relay-log-max-size":                      strconv.Itoa(c.RelayLogMaxSize),
-		"relay_log_max_size":                      strconv.Itoa(c.RelayLogMaxSize),
-		"relay-log-max-items":                     strconv.Itoa(c.RelayLogMaxItems),
-		"relay_log_max_items":                     strconv.Itoa(c.RelayLogMaxItems),
-		"vreplication-replica-lag-tolerance":      c.ReplicaLagTolerance.String(),
-		"vreplication-heartbeat-update-interval":  strconv.Itoa(c.HeartbeatUpdateInterval),
-		"vreplication-store-compressed-gtid":      strconv.FormatBool(c.StoreCompressedGTID),
-		"vreplication-parallel-insert-workers":    strconv.Itoa(c.ParallelInsertWorkers),
-		"vstream-packet-size":                     strconv.Itoa(c.VStreamPacketSize),
-		"vstream_packet_size":                     strconv.Itoa(c.VStreamPacketSize),
-		"vstream-dynamic-packet-size":             strconv.FormatBool(c.VStreamDynamicPacketSize),
-		"vstream_dynamic_packet_size":             strconv.FormatBool(c.VStreamDynamicPacketSize),
-		"vstream_binlog_rotation_threshold":       strconv.FormatInt(c.VStreamBinlogRotationThreshold, 10),
-		"max-row-json-bytes":                      strconv.FormatInt(c.MaxRowJSONBytes, 10),
+		"vreplication-experimental-flags":          strconv.FormatInt(c.ExperimentalFlags, 10),
+		"vreplication-net-read-timeout":            strconv.Itoa(c.NetReadTimeout),
+		"vreplication-net-write-timeout":           strconv.Itoa(c.NetWriteTimeout),
+		"vreplication-copy-phase-duration":         c.CopyPhaseDuration.String(),
+		"vreplication-retry-delay":                 c.RetryDelay.String(),
+		"vreplication-max-time-to-retry-on-error":  c.MaxTimeToRetryError.String(),
+		"relay-log-max-size":                       strconv.Itoa(c.RelayLogMaxSize),
+		"relay_log_max_size":                       strconv.Itoa(c.RelayLogMaxSize),
+		"relay-log-max-items":                      strconv.Itoa(c.RelayLogMaxItems),
+		"relay_log_max_items":                      strconv.Itoa(c.RelayLogMaxItems),
+		"vreplication-replica-lag-tolerance":       c.ReplicaLagTolerance.String(),
+		"vreplication-heartbeat-update-interval":   strconv.Itoa(c.HeartbeatUpdateInterval),
+		"vreplication-store-compressed-gtid":       strconv.FormatBool(c.StoreCompressedGTID),
+		"vreplication-parallel-insert-workers":     strconv.Itoa(c.ParallelInsertWorkers),
+		"vstream-packet-size":                      strconv.Itoa(c.VStreamPacketSize),
+		"vstream_packet_size":                      strconv.Itoa(c.VStreamPacketSize),
+		"vstream-dynamic-packet-size":              strconv.FormatBool(c.VStreamDynamicPacketSize),
+		"vstream_dynamic_packet_size":              strconv.FormatBool(c.VStreamDynamicPacketSize),
+		"vstream_binlog_rotation_threshold":        strconv.FormatInt(c.VStreamBinlogRotationThreshold, 10),
+		"max-row-json-bytes":                       strconv.FormatInt(c.MaxRowJSONBytes, 10),
+		"vreplication-copy-max-rows-per-second":    strconv.FormatInt(c.CopyMaxRowsPerSecond, 10),
+		"vreplication-copy-max-bytes-per-second":   strconv.FormatInt(c.CopyMaxBytesPerSecond, 10),
+		"vreplication-player-max-rows-per-second":  strconv.FormatInt(c.PlayerMaxRowsPerSecond, 10),
+		"vreplication-player-max-bytes-per-second": strconv.FormatInt(c.PlayerMaxBytesPerSecond, 10),
+		"vreplication-schedule-window":             c.ScheduleWindow,
 	}
 }
 
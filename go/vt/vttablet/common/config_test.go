@@ -200,3 +200,42 @@ func TestVReplicationMaxRowJSONBytesFlagRejectsNegative(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorContains(t, err, "must be non-negative")
 }
+
+func TestRateLimitAndScheduleWindowOverrides(t *testing.T) {
+	InitVReplicationConfigDefaults()
+	cfg, err := NewVReplicationConfig(map[string]string{
+		"vreplication-copy-max-rows-per-second":    "1000",
+		"vreplication-copy-max-bytes-per-second":   "1048576",
+		"vreplication-player-max-rows-per-second":  "500",
+		"vreplication-player-max-bytes-per-second": "524288",
+		"vreplication-schedule-window":             "22:00-06:00 America/New_York",
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1000, cfg.CopyMaxRowsPerSecond)
+	assert.EqualValues(t, 1048576, cfg.CopyMaxBytesPerSecond)
+	assert.EqualValues(t, 500, cfg.PlayerMaxRowsPerSecond)
+	assert.EqualValues(t, 524288, cfg.PlayerMaxBytesPerSecond)
+	assert.Equal(t, "22:00-06:00 America/New_York", cfg.ScheduleWindow)
+	assert.Equal(t, "22:00-06:00 America/New_York", cfg.Map()["vreplication-schedule-window"])
+
+	_, err = NewVReplicationConfig(map[string]string{
+		"vreplication-copy-max-rows-per-second":    "-1",
+		"vreplication-copy-max-bytes-per-second":   "invalid",
+		"vreplication-player-max-rows-per-second":  "-1",
+		"vreplication-player-max-bytes-per-second": "1.5",
+		"vreplication-schedule-window":             "22:00",
+	})
+	require.Error(t, err)
+	assert.Len(t, strings.Split(err.Error(), ", "), 5)
+}
+
+func TestVReplicationScheduleWindowFlag(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	registerFlags(fs)
+
+	err := fs.Parse([]string{"--vreplication-schedule-window=25:00-06:00"})
+	require.ErrorContains(t, err, "invalid schedule window")
+	err = fs.Parse([]string{"--vreplication-copy-max-rows-per-second=-1"})
+	require.ErrorContains(t, err, "must be non-negative")
+}
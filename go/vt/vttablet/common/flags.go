@@ -24,6 +24,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/timer"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/utils"
 )
@@ -39,6 +40,9 @@ type (
 	nonNegativeInt64Flag struct {
 		value *int64
 	}
+	scheduleWindowFlag struct {
+		value *string
+	}
 )
 
 func (f nonNegativeInt64Flag) Set(v string) error {
@@ -64,6 +68,25 @@ func (f nonNegativeInt64Flag) Type() string {
 	return "int"
 }
 
+func (f scheduleWindowFlag) Set(v string) error {
+	if _, err := timer.ParseScheduleWindow(v); err != nil {
+		return err
+	}
+	*f.value = v
+	return nil
+}
+
+func (f scheduleWindowFlag) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return *f.value
+}
+
+func (f scheduleWindowFlag) Type() string {
+	return "string"
+}
+
 var (
 	vreplicationExperimentalFlags   = VReplicationExperimentalFlagOptimizeInserts | VReplicationExperimentalFlagAllowNoBlobBinlogRowImage | VReplicationExperimentalFlagVPlayerBatching
 	vreplicationNetReadTimeout      = 300
@@ -85,6 +108,12 @@ var (
 	vreplicationParallelInsertWorkers = 1
 	vreplicationMaxRowJSONBytes       = int64(0)
 
+	vreplicationCopyMaxRowsPerSecond    = int64(0)
+	vreplicationCopyMaxBytesPerSecond   = int64(0)
+	vreplicationPlayerMaxRowsPerSecond  = int64(0)
+	vreplicationPlayerMaxBytesPerSecond = int64(0)
+	vreplicationScheduleWindow          = ""
+
 	// VStreamerBinlogRotationThreshold is the threshold, above which we rotate binlogs, before taking a GTID snapshot
 	VStreamerBinlogRotationThreshold = int64(64 * 1024 * 1024) // 64MiB
 	VStreamerDefaultPacketSize       = 250000
@@ -136,4 +165,9 @@ func registerFlags(fs *pflag.FlagSet) {
 
 	fs.BoolVar(&vreplicationEnableHttpLog, "vreplication-enable-http-log", vreplicationEnableHttpLog, "Enable the /debug/vrlog HTTP endpoint, which will produce a log of the events replicated on primary tablets in the target keyspace by all VReplication workflows that are in the running/replicating phase.")
 	fs.Var(nonNegativeInt64Flag{value: &vreplicationMaxRowJSONBytes}, "vreplication-max-row-json-bytes", "Maximum combined byte size of JSON columns in a single row during VReplication copy and replay phases. 0 means unlimited.")
+	fs.Var(nonNegativeInt64Flag{value: &vreplicationCopyMaxRowsPerSecond}, "vreplication-copy-max-rows-per-second", "Maximum number of rows per second copied by a VReplication workflow in the copy phase. 0 means unlimited.")
+	fs.Var(nonNegativeInt64Flag{value: &vreplicationCopyMaxBytesPerSecond}, "vreplication-copy-max-bytes-per-second", "Maximum number of bytes of row data per second copied by a VReplication workflow in the copy phase. 0 means unlimited.")
+	fs.Var(nonNegativeInt64Flag{value: &vreplicationPlayerMaxRowsPerSecond}, "vreplication-player-max-rows-per-second", "Maximum number of row changes per second applied by a VReplication workflow from the binary logs. 0 means unlimited.")
+	fs.Var(nonNegativeInt64Flag{value: &vreplicationPlayerMaxBytesPerSecond}, "vreplication-player-max-bytes-per-second", "Maximum number of bytes of row changes per second applied by a VReplication workflow from the binary logs. 0 means unlimited.")
+	fs.Var(scheduleWindowFlag{value: &vreplicationScheduleWindow}, "vreplication-schedule-window", "Daily time window during which VReplication workflows copy and apply rows, as HH:MM-HH:MM followed by an optional time zone name, e.g. '22:00-06:00 America/New_York'. UTC is used when no time zone is given. Empty means always.")
 }
@@ -25,7 +25,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"reflect"
 	"sync"
 	"time"
 
@@ -134,7 +133,7 @@ func (cr *topoCustomRule) apply(wd *topo.WatchData) error {
 		return fmt.Errorf("error unmarshaling query rules: %v, original data '%s' version %v", err, wd.Contents, wd.Version)
 	}
 
-	if !reflect.DeepEqual(cr.qrs, qrs) {
+	if cr.qrs == nil || !cr.qrs.Equal(qrs) {
 		cr.qrs = qrs.Copy()
 		cr.qsc.SetQueryRules(topoCustomRuleSource, qrs)
 		log.Info(fmt.Sprintf("Custom rule version %v fetched from topo and applied to vttablet", wd.Version))
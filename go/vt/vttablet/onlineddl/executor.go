@@ -33,6 +33,7 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/encoding/prototext"
 
 	"vitess.io/vitess/go/constants/sidecar"
@@ -182,6 +183,97 @@ type pendingMigration struct {
 	migrationContext string
 }
 
+// atomicCutOverGroupMember is a migration in an --atomic-cut-over group. The group consists of all
+// --atomic-cut-over migrations which share a migration context.
+type atomicCutOverGroupMember struct {
+	uuid  string
+	table string
+	// groupSize is the number of migrations in the group, as recorded by --atomic-cut-over-group-size
+	// when the migration was submitted
+	groupSize          int
+	status             schema.OnlineDDLStatus
+	ddlAction          string
+	readyToComplete    bool
+	postponeCompletion bool
+	cutoverWindow      *timer.ScheduleWindow
+}
+
+// atomicCutOverGroup lists the migrations of an --atomic-cut-over group, in order of submission.
+type atomicCutOverGroup []*atomicCutOverGroupMember
+
+// failedMember returns the first failed or cancelled migration in the group, if any. Such a migration
+// means the group can never cut-over as a whole.
+func (group atomicCutOverGroup) failedMember() *atomicCutOverGroupMember {
+	for _, member := range group {
+		switch member.status {
+		case schema.OnlineDDLStatusFailed, schema.OnlineDDLStatusCancelled:
+			return member
+		}
+	}
+	return nil
+}
+
+// validateNewMember checks whether a new migration on the given table may join the group, which the
+// migration expects to have groupSize migrations. All members must expect the same size, and the group
+// may not grow beyond it. The migrations of a group all run at once until they cut-over together. Two of
+// them cannot swap the same table, and if there are more of them than --max-concurrent-online-ddl, some
+// never get to run.
+func (group atomicCutOverGroup) validateNewMember(table string, groupSize int, maxConcurrent int) error {
+	if groupSize > maxConcurrent {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "migration rejected: atomic cut-over group of %d migrations is larger than --max-concurrent-online-ddl=%d", groupSize, maxConcurrent)
+	}
+	if len(group) >= groupSize {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "migration rejected: atomic cut-over group already has all of its %d migrations", groupSize)
+	}
+	for _, member := range group {
+		if member.groupSize != groupSize {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "migration rejected: migration %v in same atomic cut-over group expects a group of %d migrations, not %d", member.uuid, member.groupSize, groupSize)
+		}
+		switch member.status {
+		case schema.OnlineDDLStatusComplete, schema.OnlineDDLStatusFailed, schema.OnlineDDLStatusCancelled:
+			continue
+		}
+		if member.table == table {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "migration rejected: migration %v in same atomic cut-over group already changes table %s", member.uuid, table)
+		}
+	}
+	return nil
+}
+
+// size returns the number of migrations the group is expected to have once all of them are submitted
+func (group atomicCutOverGroup) size() int {
+	size := len(group)
+	for _, member := range group {
+		size = max(size, member.groupSize)
+	}
+	return size
+}
+
+// readyToCutOver returns the UUIDs of the group's migrations, if all of them are submitted and able to
+// cut-over at the given time. Completed migrations do not take part in the cut-over. If the group is not
+// ready, the function returns what the group is waiting on: a migration that is not ready, or the rest of
+// the migrations to be submitted. If all migrations have completed, it returns neither.
+func (group atomicCutOverGroup) readyToCutOver(now time.Time) (uuids []string, waitingOn string) {
+	if size := group.size(); len(group) < size {
+		return nil, fmt.Sprintf("submission of %d more migrations", size-len(group))
+	}
+	for _, member := range group {
+		if member.status == schema.OnlineDDLStatusComplete {
+			continue
+		}
+		isReady := member.status == schema.OnlineDDLStatusRunning &&
+			member.ddlAction == sqlparser.AlterStr &&
+			member.readyToComplete &&
+			!member.postponeCompletion &&
+			member.cutoverWindow.IsOpen(now)
+		if !isReady {
+			return nil, "migration " + member.uuid
+		}
+		uuids = append(uuids, member.uuid)
+	}
+	return uuids, ""
+}
+
 func newCancellableMigration(uuid string, message string) *cancellableMigration {
 	return &cancellableMigration{uuid: uuid, message: message}
 }
@@ -362,7 +454,9 @@ func (e *Executor) countOwnedRunningMigrations() (count int) {
 // First, the migration itself must declare --allow-concurrent. But then, there's also some
 // restrictions on which migrations exactly are allowed such concurrency.
 func (e *Executor) allowConcurrentMigration(onlineDDL *schema.OnlineDDL) (action sqlparser.DDLAction, allowConcurrent bool) {
-	if !onlineDDL.StrategySetting().IsAllowConcurrent() {
+	// An --atomic-cut-over migration waits for the rest of its group before cutting over, and so
+	// must be able to run concurrently with the group's other migrations.
+	if !onlineDDL.StrategySetting().IsAllowConcurrent() && !onlineDDL.StrategySetting().IsAtomicCutOver() {
 		return action, false
 	}
 
@@ -756,19 +850,79 @@ func (e *Executor) killTableLockHoldersAndAccessors(ctx context.Context, uuid st
 	return nil
 }
 
-// cutOverVReplMigration stops vreplication, then removes the _vt.vreplication entry for the given migration
-func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, shouldForceCutOver bool) error {
-	if err := e.incrementCutoverAttempts(ctx, s.workflow); err != nil {
-		return vterrors.Wrapf(err, "cutover: failed incrementing cutover attempts")
+// vreplCutOverMigration is a single migration participating in a cut-over operation
+type vreplCutOverMigration struct {
+	s                        *VReplStream
+	onlineDDL                *schema.OnlineDDL
+	vreplTable               string
+	needsShadowTableAnalysis bool
+}
+
+// buildLockTablesWriteQuery returns a LOCK TABLES ... WRITE statement for the given tables
+func buildLockTablesWriteQuery(tableNames ...string) string {
+	clauses := make([]string, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		clauses = append(clauses, sqlparser.BuildParsedQuery(sqlLockTableWriteClause, tableName).Query)
+	}
+	return "LOCK TABLES " + strings.Join(clauses, ", ")
+}
+
+// buildSwapTablesViaSentryQuery returns a single RENAME TABLE statement which swaps each of the given tables
+// with its vreplication table. The sentry table name is used as temporary name for each swap in turn, such
+// that the sentry table must be dropped before the statement is able to complete.
+func buildSwapTablesViaSentryQuery(sentryTableName string, tableNames []string, vreplTableNames []string) string {
+	clauses := make([]string, 0, 3*len(tableNames))
+	for i, tableName := range tableNames {
+		clauses = append(clauses,
+			sqlparser.BuildParsedQuery(sqlRenameTableClause, tableName, sentryTableName).Query,
+			sqlparser.BuildParsedQuery(sqlRenameTableClause, vreplTableNames[i], tableName).Query,
+			sqlparser.BuildParsedQuery(sqlRenameTableClause, sentryTableName, vreplTableNames[i]).Query,
+		)
+	}
+	return "RENAME TABLE " + strings.Join(clauses, ", ")
+}
+
+// cutOverVReplMigration stops vreplication, then removes the _vt.vreplication entry for the given migrations.
+// Normally this is a single migration. With --atomic-cut-over, this is a group of migrations, which are cut-over
+// together: all tables are locked and swapped by a single RENAME statement, so that either all migrations
+// complete, or none do.
+func (e *Executor) cutOverVReplMigration(ctx context.Context, streams []*VReplStream, shouldForceCutOver bool) error {
+	if len(streams) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "cutover: no migrations to cut-over")
+	}
+	workflows := make([]string, 0, len(streams))
+	for _, s := range streams {
+		workflows = append(workflows, s.workflow)
+	}
+	workflowsList := strings.Join(workflows, ",")
+
+	for _, s := range streams {
+		if err := e.incrementCutoverAttempts(ctx, s.workflow); err != nil {
+			return vterrors.Wrapf(err, "cutover: failed incrementing cutover attempts")
+		}
 	}
 
 	tmClient := e.tabletManagerClient()
 	defer tmClient.Close()
 
-	// sanity checks:
-	vreplTable, err := getVreplTable(s)
-	if err != nil {
-		return vterrors.Wrapf(err, "cutover: failed getting vreplication table")
+	migrations := make([]*vreplCutOverMigration, 0, len(streams))
+	for _, s := range streams {
+		// sanity checks:
+		vreplTable, err := getVreplTable(s)
+		if err != nil {
+			return vterrors.Wrapf(err, "cutover: failed getting vreplication table")
+		}
+		// information about source tablet
+		onlineDDL, row, err := e.readMigration(ctx, s.workflow)
+		if err != nil {
+			return vterrors.Wrapf(err, "cutover: failed reading migration")
+		}
+		migrations = append(migrations, &vreplCutOverMigration{
+			s:                        s,
+			onlineDDL:                onlineDDL,
+			vreplTable:               vreplTable,
+			needsShadowTableAnalysis: row["shadow_analyzed_timestamp"].IsNull(),
+		})
 	}
 
 	// get topology client & entities:
@@ -777,14 +931,22 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 		return vterrors.Wrapf(err, "cutover: failed reading vreplication table")
 	}
 
-	// information about source tablet
-	onlineDDL, row, err := e.readMigration(ctx, s.workflow)
-	if err != nil {
-		return vterrors.Wrapf(err, "cutover: failed reading migration")
+	isVreplicationTestSuite := migrations[0].onlineDDL.StrategySetting().IsVreplicationTestSuite()
+	// The cut-over operation is bounded by the strictest threshold among the migrations.
+	cutOverThreshold := migrations[0].onlineDDL.CutOverThreshold
+	tableNames := make([]string, 0, len(migrations))
+	vreplTableNames := make([]string, 0, len(migrations))
+	for _, m := range migrations {
+		cutOverThreshold = min(cutOverThreshold, m.onlineDDL.CutOverThreshold)
+		tableNames = append(tableNames, m.onlineDDL.Table)
+		vreplTableNames = append(vreplTableNames, m.vreplTable)
+	}
+	updateStage := func(stage string, args ...any) {
+		for _, m := range migrations {
+			e.updateMigrationStage(ctx, m.onlineDDL.UUID, stage, args...)
+		}
 	}
-	needsShadowTableAnalysis := row["shadow_analyzed_timestamp"].IsNull()
-	isVreplicationTestSuite := onlineDDL.StrategySetting().IsVreplicationTestSuite()
-	e.updateMigrationStage(ctx, onlineDDL.UUID, "starting cut-over")
+	updateStage("starting cut-over")
 
 	var sentryTableName string
 
@@ -801,6 +963,17 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 		// Target is now in sync with source!
 		return nil
 	}
+	waitForAllPos := func(pos replication.Position, timeout time.Duration) error {
+		// All streams run on this tablet. We wait for them concurrently, such that the total
+		// wait is bounded by the timeout.
+		var eg errgroup.Group
+		for _, m := range migrations {
+			eg.Go(func() error {
+				return waitForPos(m.s, pos, timeout)
+			})
+		}
+		return eg.Wait()
+	}
 
 	if !isVreplicationTestSuite {
 		// A bit early on, we generate a name for the sentry table
@@ -816,8 +989,10 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 		// We create the sentry table before toggling writes, because this involves a WaitForPos, which takes some time. We
 		// don't want to overload the buffering time with this excessive wait.
 
-		if err := e.updateArtifacts(ctx, onlineDDL.UUID, sentryTableName); err != nil {
-			return vterrors.Wrapf(err, "failed updating artifacts with sentry table name")
+		for _, m := range migrations {
+			if err := e.updateArtifacts(ctx, m.onlineDDL.UUID, sentryTableName); err != nil {
+				return vterrors.Wrapf(err, "failed updating artifacts with sentry table name")
+			}
 		}
 
 		dropSentryTableQuery := sqlparser.BuildParsedQuery(sqlDropTableIfExists, sentryTableName)
@@ -833,7 +1008,9 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 			// removing the entry
 			_, err := e.execQuery(ctx, dropSentryTableQuery.Query)
 			if err == nil {
-				e.clearSingleArtifact(ctx, onlineDDL.UUID, sentryTableName)
+				for _, m := range migrations {
+					e.clearSingleArtifact(ctx, m.onlineDDL.UUID, sentryTableName)
+				}
 			}
 			// This was a best effort optimization. Possibly the error is not nil. Which means we
 			// still have a record of the sentry table, and gcArtifacts() will still be able to take
@@ -847,23 +1024,26 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 			}
 			defer preparationsConn.Recycle()
 			// Set large enough `@@lock_wait_timeout` so that it does not interfere with the cut-over operation.
-			// The code will ensure everything that needs to be terminated by `onlineDDL.CutOverThreshold` will be terminated.
-			preparationConnRestoreLockWaitTimeout, err := e.initConnectionLockWaitTimeout(ctx, preparationsConn.Conn, 3*onlineDDL.CutOverThreshold)
+			// The code will ensure everything that needs to be terminated by `cutOverThreshold` will be terminated.
+			preparationConnRestoreLockWaitTimeout, err := e.initConnectionLockWaitTimeout(ctx, preparationsConn.Conn, 3*cutOverThreshold)
 			if err != nil {
 				return vterrors.Wrap(err, "failed setting lock_wait_timeout on locking connection")
 			}
 			defer preparationConnRestoreLockWaitTimeout()
 
-			if needsShadowTableAnalysis {
+			for _, m := range migrations {
+				if !m.needsShadowTableAnalysis {
+					continue
+				}
 				// Run `ANALYZE TABLE` on the vreplication table so that it has up-to-date statistics at cut-over.
 				// The statement will be replicated, so that in case there's a PRS/ERS shortly after cut-over, the
 				// promoted replica will have good statistics.
-				parsed := sqlparser.BuildParsedQuery(sqlAnalyzeTable, vreplTable)
+				parsed := sqlparser.BuildParsedQuery(sqlAnalyzeTable, m.vreplTable)
 				if _, err := preparationsConn.Conn.Exec(ctx, parsed.Query, -1, false); err != nil {
 					// Best effort only. Do not fail the mgiration if this fails.
-					_ = e.updateMigrationMessage(ctx, "failed ANALYZE shadow table", s.workflow)
+					_ = e.updateMigrationMessage(ctx, "failed ANALYZE shadow table", m.s.workflow)
 				} else {
-					_ = e.updateMigrationTimestamp(ctx, "shadow_analyzed_timestamp", s.workflow)
+					_ = e.updateMigrationTimestamp(ctx, "shadow_analyzed_timestamp", m.s.workflow)
 				}
 				// This command will have blocked the table for writes, presumably only for a brief time. But this can cause
 				// vreplication to now lag. Thankfully we're gonna create the sentry table and waitForPos.
@@ -872,7 +1052,7 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 			if _, err := preparationsConn.Conn.Exec(ctx, parsed.Query, 1, false); err != nil {
 				return vterrors.Wrapf(err, "failed creating sentry table")
 			}
-			e.updateMigrationStage(ctx, onlineDDL.UUID, "sentry table created: %s", sentryTableName)
+			updateStage("sentry table created: %s", sentryTableName)
 			return nil
 		}
 		if err := preparation(); err != nil {
@@ -882,15 +1062,15 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 		if err != nil {
 			return vterrors.Wrapf(err, "failed getting primary pos after sentry creation")
 		}
-		e.updateMigrationStage(ctx, onlineDDL.UUID, "waiting for post-sentry pos: %v", replication.EncodePosition(postSentryPos))
+		updateStage("waiting for post-sentry pos: %v", replication.EncodePosition(postSentryPos))
 		// We have not yet locked anything, stopped anything, or done anything that otherwise
 		// impacts query serving so we wait for a multiple of the cutover threshold here, with
 		// that variable primarily serving to limit the max time we later spend waiting for
 		// a position again AFTER we've taken the locks and table access is blocked.
-		if err := waitForPos(s, postSentryPos, 3*onlineDDL.CutOverThreshold); err != nil {
+		if err := waitForAllPos(postSentryPos, 3*cutOverThreshold); err != nil {
 			return vterrors.Wrapf(err, "failed waiting for pos after sentry creation")
 		}
-		e.updateMigrationStage(ctx, onlineDDL.UUID, "post-sentry pos reached")
+		updateStage("post-sentry pos reached")
 	}
 
 	renameWasSuccessful := false
@@ -906,16 +1086,16 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		if _, err := lockConn.Conn.Exec(unlockCtx, sqlUnlockTables, 1, false); err != nil {
-			log.Warn(fmt.Sprintf("Failed to UNLOCK TABLES in OnlineDDL migration %s: %v", onlineDDL.UUID, err))
+			log.Warn(fmt.Sprintf("Failed to UNLOCK TABLES in OnlineDDL migration %s: %v", workflowsList, err))
 		}
 		if err := lockConn.Conn.Kill("closing lock tables connection", 0); err != nil {
-			log.Error(fmt.Sprintf("Failed to kill lock tables connection in OnlineDDL migration %s: %v", onlineDDL.UUID, err))
+			log.Error(fmt.Sprintf("Failed to kill lock tables connection in OnlineDDL migration %s: %v", workflowsList, err))
 		}
 	}()
 
 	// Set large enough `@@lock_wait_timeout` so that it does not interfere with the cut-over operation.
-	// The code will ensure everything that needs to be terminated by `onlineDDL.CutOverThreshold` will be terminated.
-	lockConnRestoreLockWaitTimeout, err := e.initConnectionLockWaitTimeout(ctx, lockConn.Conn, 3*onlineDDL.CutOverThreshold)
+	// The code will ensure everything that needs to be terminated by `cutOverThreshold` will be terminated.
+	lockConnRestoreLockWaitTimeout, err := e.initConnectionLockWaitTimeout(ctx, lockConn.Conn, 3*cutOverThreshold)
 	if err != nil {
 		return vterrors.Wrapf(err, "failed setting lock_wait_timeout on locking connection")
 	}
@@ -936,13 +1116,13 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 		if !renameWasSuccessful {
 			err := renameConn.Conn.Kill("premature exit while renaming tables", 0)
 			if err != nil {
-				log.Warn(fmt.Sprintf("Failed to kill connection being used to rename tables in OnlineDDL migration %s: %v", onlineDDL.UUID, err))
+				log.Warn(fmt.Sprintf("Failed to kill connection being used to rename tables in OnlineDDL migration %s: %v", workflowsList, err))
 			}
 		}
 	}()
 	// Set large enough `@@lock_wait_timeout` so that it does not interfere with the cut-over operation.
-	// The code will ensure everything that needs to be terminated by `onlineDDL.CutOverThreshold` will be terminated.
-	renameConnRestoreLockWaitTimeout, err := e.initConnectionLockWaitTimeout(ctx, renameConn.Conn, 2*onlineDDL.CutOverThreshold)
+	// The code will ensure everything that needs to be terminated by `cutOverThreshold` will be terminated.
+	renameConnRestoreLockWaitTimeout, err := e.initConnectionLockWaitTimeout(ctx, renameConn.Conn, 2*cutOverThreshold)
 	if err != nil {
 		return vterrors.Wrapf(err, "failed setting lock_wait_timeout on rename connection")
 	}
@@ -968,12 +1148,12 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 		log.Info("@@rename_table_preserve_foreign_key supported")
 	}
 
-	renameQuery := sqlparser.BuildParsedQuery(sqlSwapTables, onlineDDL.Table, sentryTableName, vreplTable, onlineDDL.Table, sentryTableName, vreplTable)
+	renameQuery := buildSwapTablesViaSentryQuery(sentryTableName, tableNames, vreplTableNames)
 	waitForRenameProcess := func() error {
 		// This function waits until it finds the RENAME TABLE... query running in MySQL's PROCESSLIST, or until timeout
 		// The function assumes that one of the renamed tables is locked, thus causing the RENAME to block. If nothing
 		// is locked, then the RENAME will be near-instantaneous and it's unlikely that the function will find it.
-		renameWaitCtx, cancel := context.WithTimeout(ctx, onlineDDL.CutOverThreshold)
+		renameWaitCtx, cancel := context.WithTimeout(ctx, cutOverThreshold)
 		defer cancel()
 
 		for {
@@ -986,7 +1166,7 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 			}
 			select {
 			case <-renameWaitCtx.Done():
-				return vterrors.Errorf(vtrpcpb.Code_ABORTED, "timeout for rename query: %s", renameQuery.Query)
+				return vterrors.Errorf(vtrpcpb.Code_ABORTED, "timeout for rename query: %s", renameQuery)
 			case err := <-renameCompleteChan:
 				// We expect the RENAME to run and block, not yet complete. The caller of this function
 				// will only unblock the RENAME after the function is complete
@@ -1001,10 +1181,12 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 	defer bufferingContextCancel()
 	// Preparation is complete. We proceed to cut-over.
 	toggleBuffering := func(bufferQueries bool) error {
-		log.Info(fmt.Sprintf("toggling buffering: %t in migration %v", bufferQueries, onlineDDL.UUID))
-		timeout := onlineDDL.CutOverThreshold + qrBufferExtraTimeout
+		log.Info(fmt.Sprintf("toggling buffering: %t in migration %v", bufferQueries, workflowsList))
+		timeout := cutOverThreshold + qrBufferExtraTimeout
 
-		e.toggleBufferTableFunc(bufferingCtx, onlineDDL.Table, timeout, bufferQueries)
+		for _, tableName := range tableNames {
+			e.toggleBufferTableFunc(bufferingCtx, tableName, timeout, bufferQueries)
+		}
 		if !bufferQueries {
 			grpcCtx, cancel := context.WithTimeout(ctx, grpcTimeout)
 			defer cancel()
@@ -1016,19 +1198,19 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 				return vterrors.Wrapf(err, "refreshing table state")
 			}
 		}
-		log.Info(fmt.Sprintf("toggled buffering: %t in migration %v", bufferQueries, onlineDDL.UUID))
+		log.Info(fmt.Sprintf("toggled buffering: %t in migration %v", bufferQueries, workflowsList))
 		return nil
 	}
 
 	var reenableOnce sync.Once
 	reenableWritesOnce := func() {
 		reenableOnce.Do(func() {
-			log.Info(fmt.Sprintf("re-enabling writes in migration %v", onlineDDL.UUID))
+			log.Info(fmt.Sprintf("re-enabling writes in migration %v", workflowsList))
 			toggleBuffering(false)
-			go log.Info(fmt.Sprintf("cutOverVReplMigration %v: unbuffered queries", s.workflow))
+			go log.Info(fmt.Sprintf("cutOverVReplMigration %v: unbuffered queries", workflowsList))
 		})
 	}
-	e.updateMigrationStage(ctx, onlineDDL.UUID, "buffering queries")
+	updateStage("buffering queries")
 	// stop writes on source:
 	err = toggleBuffering(true)
 	defer reenableWritesOnce()
@@ -1039,17 +1221,19 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 	// query executor, it passed the ACLs and is _about to_ execute. This will be nicer to those queries:
 	// they will be able to complete before the rename, rather than block briefly on the rename only to find
 	// the table no longer exists.
-	e.updateMigrationStage(ctx, onlineDDL.UUID, "graceful wait for buffering")
+	updateStage("graceful wait for buffering")
 	time.Sleep(100 * time.Millisecond)
 
 	if shouldForceCutOver {
-		// We should only proceed with forceful cut over if there is no pending atomic transaction for the table.
-		// This will help in keeping the atomicity guarantee of a prepared transaction.
-		if err := e.checkOnPreparedPool(ctx, onlineDDL.Table, 100*time.Millisecond); err != nil {
-			return vterrors.Wrapf(err, "checking prepared pool for table")
-		}
-		if err := e.killTableLockHoldersAndAccessors(ctx, onlineDDL.UUID, onlineDDL.Table); err != nil {
-			return vterrors.Wrapf(err, "failed killing table lock holders and accessors")
+		for _, m := range migrations {
+			// We should only proceed with forceful cut over if there is no pending atomic transaction for the table.
+			// This will help in keeping the atomicity guarantee of a prepared transaction.
+			if err := e.checkOnPreparedPool(ctx, m.onlineDDL.Table, 100*time.Millisecond); err != nil {
+				return vterrors.Wrapf(err, "checking prepared pool for table")
+			}
+			if err := e.killTableLockHoldersAndAccessors(ctx, m.onlineDDL.UUID, m.onlineDDL.Table); err != nil {
+				return vterrors.Wrapf(err, "failed killing table lock holders and accessors")
+			}
 		}
 	}
 
@@ -1058,49 +1242,53 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 		// Those queries are unaffected by query rules (ACLs) because they don't go through Vitess.
 		// We therefore hard-rename the table into an agreed upon name, and we won't swap it with
 		// the original table. We will actually make the table disappear, creating a void.
-		testSuiteBeforeTableName := onlineDDL.Table + "_before"
-		parsed := sqlparser.BuildParsedQuery(sqlRenameTable, onlineDDL.Table, testSuiteBeforeTableName)
-		if _, err := e.execQuery(ctx, parsed.Query); err != nil {
-			return err
+		for _, m := range migrations {
+			testSuiteBeforeTableName := m.onlineDDL.Table + "_before"
+			parsed := sqlparser.BuildParsedQuery(sqlRenameTable, m.onlineDDL.Table, testSuiteBeforeTableName)
+			if _, err := e.execQuery(ctx, parsed.Query); err != nil {
+				return err
+			}
 		}
-		e.updateMigrationStage(ctx, onlineDDL.UUID, "test suite 'before' table renamed")
+		updateStage("test suite 'before' table renamed")
 	} else {
 		// real production
 
-		e.updateMigrationStage(ctx, onlineDDL.UUID, "locking tables")
-		lockCtx, killWhileRenamingCancel := context.WithTimeout(ctx, onlineDDL.CutOverThreshold)
+		updateStage("locking tables")
+		lockCtx, killWhileRenamingCancel := context.WithTimeout(ctx, cutOverThreshold)
 		defer killWhileRenamingCancel()
-		lockTableQuery := sqlparser.BuildParsedQuery(sqlLockTwoTablesWrite, sentryTableName, onlineDDL.Table)
-		if _, err := lockConn.Conn.Exec(lockCtx, lockTableQuery.Query, 1, false); err != nil {
+		lockTableQuery := buildLockTablesWriteQuery(append([]string{sentryTableName}, tableNames...)...)
+		if _, err := lockConn.Conn.Exec(lockCtx, lockTableQuery, 1, false); err != nil {
 			return vterrors.Wrapf(err, "failed locking tables")
 		}
 
-		e.updateMigrationStage(ctx, onlineDDL.UUID, "renaming tables")
+		updateStage("renaming tables")
 		killWhileRenamingContext, killWhileRenamingCancel := context.WithCancel(ctx)
 		defer killWhileRenamingCancel()
 		// We run the RENAME in a goroutine, so that we can wait for
 		go func() {
 			defer close(renameCompleteChan)
-			_, err := renameConn.Conn.Exec(ctx, renameQuery.Query, 1, false)
+			_, err := renameConn.Conn.Exec(ctx, renameQuery, 1, false)
 			renameCompleteChan <- err
 			killWhileRenamingCancel() // RENAME is done, no need to kill queries anymore
 		}()
 		// the rename should block, because of the LOCK. Wait for it to show up.
-		e.updateMigrationStage(ctx, onlineDDL.UUID, "waiting for RENAME to block")
+		updateStage("waiting for RENAME to block")
 		if err := waitForRenameProcess(); err != nil {
 			return vterrors.Wrapf(err, "failed waiting for rename process")
 		}
-		e.updateMigrationStage(ctx, onlineDDL.UUID, "RENAME found")
+		updateStage("RENAME found")
 
 		if shouldForceCutOver {
-			log.Info(fmt.Sprintf("cutOverVReplMigration %v: force cut-over requested, killing table lock holders and accessors while RENAME is in place", s.workflow))
-			if err := e.killTableLockHoldersAndAccessors(killWhileRenamingContext, onlineDDL.UUID, onlineDDL.Table, lockConn.Conn.ID(), renameConn.Conn.ID()); err != nil {
-				return vterrors.Wrapf(err, "failed killing table lock holders and accessors")
+			log.Info(fmt.Sprintf("cutOverVReplMigration %v: force cut-over requested, killing table lock holders and accessors while RENAME is in place", workflowsList))
+			for _, m := range migrations {
+				if err := e.killTableLockHoldersAndAccessors(killWhileRenamingContext, m.onlineDDL.UUID, m.onlineDDL.Table, lockConn.Conn.ID(), renameConn.Conn.ID()); err != nil {
+					return vterrors.Wrapf(err, "failed killing table lock holders and accessors")
+				}
 			}
 		}
 	}
 
-	e.updateMigrationStage(ctx, onlineDDL.UUID, "reading post-lock pos")
+	updateStage("reading post-lock pos")
 	postWritesPos, err := e.primaryPosition(ctx)
 	if err != nil {
 		return vterrors.Wrapf(err, "failed reading pos after locking")
@@ -1110,76 +1298,84 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 	// that some leftover query finds the table is not actually there anymore...
 	// At any case, there's definitely no more writes to the table since it does not exist. We can
 	// safely take the (GTID) pos now.
-	_ = e.updateMigrationTimestamp(ctx, "liveness_timestamp", s.workflow)
+	for _, m := range migrations {
+		_ = e.updateMigrationTimestamp(ctx, "liveness_timestamp", m.s.workflow)
 
-	// Writes are now disabled on table. Read up-to-date vreplication info, specifically to get latest (and fixed) pos:
-	s, err = e.readVReplStream(ctx, s.workflow, false)
-	if err != nil {
-		return vterrors.Wrapf(err, "failed reading vreplication table after locking")
+		// Writes are now disabled on table. Read up-to-date vreplication info, specifically to get latest (and fixed) pos:
+		m.s, err = e.readVReplStream(ctx, m.s.workflow, false)
+		if err != nil {
+			return vterrors.Wrapf(err, "failed reading vreplication table after locking")
+		}
 	}
 
-	e.updateMigrationStage(ctx, onlineDDL.UUID, "waiting for post-lock pos: %v", replication.EncodePosition(postWritesPos))
-	if err := waitForPos(s, postWritesPos, onlineDDL.CutOverThreshold); err != nil {
-		e.updateMigrationStage(ctx, onlineDDL.UUID, "timeout while waiting for post-lock pos: %v", err)
+	updateStage("waiting for post-lock pos: %v", replication.EncodePosition(postWritesPos))
+	if err := waitForAllPos(postWritesPos, cutOverThreshold); err != nil {
+		updateStage("timeout while waiting for post-lock pos: %v", err)
 		return vterrors.Wrapf(err, "failed waiting for pos after locking")
 	}
-	go log.Info(fmt.Sprintf("cutOverVReplMigration %v: done waiting for position %v", s.workflow, replication.EncodePosition(postWritesPos)))
+	go log.Info(fmt.Sprintf("cutOverVReplMigration %v: done waiting for position %v", workflowsList, replication.EncodePosition(postWritesPos)))
 	// Stop vreplication
-	e.updateMigrationStage(ctx, onlineDDL.UUID, "stopping vreplication")
-	if _, err := e.vreplicationExec(ctx, tablet.Tablet, binlogplayer.StopVReplication(s.id, "stopped for online DDL cutover")); err != nil {
-		return vterrors.Wrapf(err, "failed stopping vreplication")
-	}
-	go log.Info(fmt.Sprintf("cutOverVReplMigration %v: stopped vreplication", s.workflow))
+	updateStage("stopping vreplication")
+	for _, m := range migrations {
+		if _, err := e.vreplicationExec(ctx, tablet.Tablet, binlogplayer.StopVReplication(m.s.id, "stopped for online DDL cutover")); err != nil {
+			return vterrors.Wrapf(err, "failed stopping vreplication")
+		}
+		go log.Info(fmt.Sprintf("cutOverVReplMigration %v: stopped vreplication", m.s.workflow))
 
-	defer func() {
-		if !renameWasSuccessful {
-			// Restarting vreplication
-			if err := e.startVReplication(ctx, tablet.Tablet, s.workflow); err != nil {
-				log.Error(fmt.Sprintf("cutOverVReplMigration %v: failed restarting vreplication after cutover failure: %v", s.workflow, err))
+		defer func() {
+			if !renameWasSuccessful {
+				// Restarting vreplication
+				if err := e.startVReplication(ctx, tablet.Tablet, m.s.workflow); err != nil {
+					log.Error(fmt.Sprintf("cutOverVReplMigration %v: failed restarting vreplication after cutover failure: %v", m.s.workflow, err))
+				}
+				go log.Info(fmt.Sprintf("cutOverVReplMigration %v: started vreplication after cutover failure", m.s.workflow))
 			}
-			go log.Info(fmt.Sprintf("cutOverVReplMigration %v: started vreplication after cutover failure", s.workflow))
-		}
-	}()
+		}()
+	}
 
 	// rename tables atomically (remember, writes on source tables are stopped)
 	{
 		if isVreplicationTestSuite {
 			// this is used in Vitess endtoend testing suite
-			testSuiteAfterTableName := onlineDDL.Table + "_after"
-			parsed := sqlparser.BuildParsedQuery(sqlRenameTable, vreplTable, testSuiteAfterTableName)
-			if _, err := e.execQuery(ctx, parsed.Query); err != nil {
-				return err
+			for _, m := range migrations {
+				testSuiteAfterTableName := m.onlineDDL.Table + "_after"
+				parsed := sqlparser.BuildParsedQuery(sqlRenameTable, m.vreplTable, testSuiteAfterTableName)
+				if _, err := e.execQuery(ctx, parsed.Query); err != nil {
+					return err
+				}
 			}
-			e.updateMigrationStage(ctx, onlineDDL.UUID, "test suite 'after' table renamed")
+			updateStage("test suite 'after' table renamed")
 		} else {
-			e.updateMigrationStage(ctx, onlineDDL.UUID, "validating rename is still in place")
+			updateStage("validating rename is still in place")
 			if err := waitForRenameProcess(); err != nil {
 				return vterrors.Wrapf(err, "failed waiting for rename process before dropping sentry table")
 			}
 
 			// Normal (non-testing) alter table
-			e.updateMigrationStage(ctx, onlineDDL.UUID, "dropping sentry table")
+			updateStage("dropping sentry table")
 
 			{
 				dropTableQuery := sqlparser.BuildParsedQuery(sqlDropTable, sentryTableName)
-				lockCtx, cancel := context.WithTimeout(ctx, onlineDDL.CutOverThreshold)
+				lockCtx, cancel := context.WithTimeout(ctx, cutOverThreshold)
 				defer cancel()
 				if _, err := lockConn.Conn.Exec(lockCtx, dropTableQuery.Query, 1, false); err != nil {
 					return vterrors.Wrapf(err, "failed dropping sentry table")
 				}
 			}
 			{
-				lockCtx, cancel := context.WithTimeout(ctx, onlineDDL.CutOverThreshold)
+				lockCtx, cancel := context.WithTimeout(ctx, cutOverThreshold)
 				defer cancel()
-				e.updateMigrationStage(ctx, onlineDDL.UUID, "unlocking tables")
+				updateStage("unlocking tables")
 				if _, err := lockConn.Conn.Exec(lockCtx, sqlUnlockTables, 1, false); err != nil {
 					return vterrors.Wrapf(err, "failed unlocking tables")
 				}
 			}
 			{
-				lockCtx, cancel := context.WithTimeout(ctx, onlineDDL.CutOverThreshold)
+				lockCtx, cancel := context.WithTimeout(ctx, cutOverThreshold)
 				defer cancel()
-				e.updateMigrationStage(lockCtx, onlineDDL.UUID, "waiting for RENAME to complete")
+				for _, m := range migrations {
+					e.updateMigrationStage(lockCtx, m.onlineDDL.UUID, "waiting for RENAME to complete")
+				}
 				if err := <-renameCompleteChan; err != nil {
 					return vterrors.Wrapf(err, "failed waiting for rename to complete")
 				}
@@ -1187,8 +1383,10 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 			}
 		}
 	}
-	e.updateMigrationStage(ctx, onlineDDL.UUID, "cut-over complete")
-	e.ownedRunningMigrations.Delete(onlineDDL.UUID)
+	updateStage("cut-over complete")
+	for _, m := range migrations {
+		e.ownedRunningMigrations.Delete(m.onlineDDL.UUID)
+	}
 
 	go func() {
 		// Tables are swapped! Let's take the opportunity to ReloadSchema now
@@ -1197,15 +1395,17 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 		// this means ReloadSchema is not in sync with the actual schema change. Users will still need to run tracker if they want to sync.
 		// In the future, we will want to reload the single table, instead of reloading the schema.
 		if err := e.reloadSchema(ctx); err != nil {
-			vterrors.Errorf(vtrpcpb.Code_UNKNOWN, "Error on ReloadSchema while cutting over vreplication migration UUID: %+v", onlineDDL.UUID)
+			vterrors.Errorf(vtrpcpb.Code_UNKNOWN, "Error on ReloadSchema while cutting over vreplication migration UUID: %+v", workflowsList)
 		}
 	}()
 
 	// Tables are now swapped! Migration is successful
-	e.updateMigrationStage(ctx, onlineDDL.UUID, "re-enabling writes")
+	updateStage("re-enabling writes")
 	reenableWritesOnce() // this function is also deferred, in case of early return; but now would be a good time to resume writes, before we publish the migration as "complete"
-	go log.Info(fmt.Sprintf("cutOverVReplMigration %v: marking as complete", s.workflow))
-	_ = e.onSchemaMigrationStatus(ctx, onlineDDL.UUID, schema.OnlineDDLStatusComplete, false, progressPctFull, etaSecondsNow, s.rowsCopied, emptyHint)
+	for _, m := range migrations {
+		go log.Info(fmt.Sprintf("cutOverVReplMigration %v: marking as complete", m.s.workflow))
+		_ = e.onSchemaMigrationStatus(ctx, m.onlineDDL.UUID, schema.OnlineDDLStatusComplete, false, progressPctFull, etaSecondsNow, m.s.rowsCopied, emptyHint)
+	}
 	return nil
 
 	// deferred function will re-enable writes now
@@ -2333,6 +2533,70 @@ func (e *Executor) readFailedCancelledMigrationsInContextBeforeMigration(ctx con
 	return uuids, err
 }
 
+// readAtomicCutOverGroup reads the --atomic-cut-over group of the given migration, i.e. all --atomic-cut-over
+// migrations in the same migration context, including the given migration itself.
+func (e *Executor) readAtomicCutOverGroup(ctx context.Context, onlineDDL *schema.OnlineDDL) (group atomicCutOverGroup, err error) {
+	if onlineDDL.MigrationContext == "" {
+		// only applies to migrations with an explicit context
+		return nil, nil
+	}
+	query, err := sqlparser.ParseAndBind(sqlSelectMigrationsInContext,
+		sqltypes.StringBindVariable(onlineDDL.MigrationContext),
+	)
+	if err != nil {
+		return nil, err
+	}
+	r, err := e.execQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range r.Named().Rows {
+		strategySetting := schema.NewDDLStrategySetting(schema.DDLStrategy(row["strategy"].ToString()), row["options"].ToString())
+		if !strategySetting.IsAtomicCutOver() {
+			continue
+		}
+		// The cut-over window and the group size are validated when the migration is submitted.
+		cutoverWindow, _ := timer.ParseScheduleWindow(row.AsString("cutover_window", ""))
+		groupSize, _ := strategySetting.AtomicCutOverGroupSize()
+		group = append(group, &atomicCutOverGroupMember{
+			uuid:               row["migration_uuid"].ToString(),
+			table:              row["mysql_table"].ToString(),
+			groupSize:          groupSize,
+			status:             schema.OnlineDDLStatus(row["migration_status"].ToString()),
+			ddlAction:          row["ddl_action"].ToString(),
+			readyToComplete:    row.AsBool("ready_to_complete", false),
+			postponeCompletion: row.AsBool("postpone_completion", false),
+			cutoverWindow:      cutoverWindow,
+		})
+	}
+	return group, nil
+}
+
+// validateAtomicCutOverMigration checks that a newly submitted --atomic-cut-over migration is able to
+// cut-over together with the rest of its group. Only a table ALTER that runs via vreplication has a
+// cut-over to take part in; any other migration would complete by itself. The migration must record
+// the size of its group, so that no member cuts over before the whole group is submitted.
+func (e *Executor) validateAtomicCutOverMigration(ctx context.Context, onlineDDL *schema.OnlineDDL, actionStr string, revertedUUID string) error {
+	if actionStr != sqlparser.AlterStr || revertedUUID != "" || onlineDDL.IsView(e.env.Environment().Parser()) {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "migration rejected: --atomic-cut-over only applies to ALTER TABLE migrations, and migration %v is not one", onlineDDL.UUID)
+	}
+	if onlineDDL.MigrationContext == "" {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "migration rejected: --atomic-cut-over requires a migration context, found none in migration %v", onlineDDL.UUID)
+	}
+	groupSize, err := onlineDDL.StrategySetting().AtomicCutOverGroupSize()
+	if err != nil {
+		return vterrors.Wrapf(err, "parsing atomic cut-over group size in migration %v", onlineDDL.UUID)
+	}
+	if groupSize == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "migration rejected: --atomic-cut-over requires --atomic-cut-over-group-size, found none in migration %v", onlineDDL.UUID)
+	}
+	group, err := e.readAtomicCutOverGroup(ctx, onlineDDL)
+	if err != nil {
+		return vterrors.Wrapf(err, "reading atomic cut-over group of migration %v", onlineDDL.UUID)
+	}
+	return group.validateNewMember(onlineDDL.Table, groupSize, maxConcurrentOnlineDDLs)
+}
+
 // failMigration marks a migration as failed
 func (e *Executor) failMigration(ctx context.Context, onlineDDL *schema.OnlineDDL, withError error) error {
 	defer e.triggerNextCheckInterval()
@@ -2694,6 +2958,10 @@ func (e *Executor) executeSpecialAlterDDLActionMigrationIfApplicable(ctx context
 	if specialPlan == nil {
 		return false, nil
 	}
+	if onlineDDL.StrategySetting().IsAtomicCutOver() {
+		// A special plan completes the migration by itself, without a cut-over to take part in.
+		return false, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "migration %v runs via special plan %s, which cannot take part in an atomic cut-over", onlineDDL.UUID, specialPlan.String())
+	}
 
 	switch specialPlan.operation {
 	case instantDDLSpecialOperation:
@@ -2972,6 +3240,18 @@ func (e *Executor) getNonConflictingMigration(ctx context.Context) (*schema.Onli
 				continue
 			}
 		}
+		// Likewise, we fail an --atomic-cut-over migration if any migration in its group has failed,
+		// since the group can no longer cut-over as a whole.
+		if onlineDDL.StrategySetting().IsAtomicCutOver() {
+			group, err := e.readAtomicCutOverGroup(ctx, onlineDDL)
+			if err != nil {
+				return nil, err
+			}
+			if failed := group.failedMember(); failed != nil {
+				_ = e.failMigration(ctx, onlineDDL, fmt.Errorf("migration %v cannot run because migration %v in same atomic cut-over group has failed/was cancelled", onlineDDL.UUID, failed.uuid))
+				continue
+			}
+		}
 		// This migration seems good to go
 		return onlineDDL, err
 	}
@@ -3283,6 +3563,10 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 		if errForceCutOverAfter != nil {
 			forceCutOverAfter = 0
 		}
+		// Likewise, the cut-over window is validated when the migration is submitted. An invalid
+		// window is ignored, so that the migration is not held back forever.
+		cutoverWindow, _ := timer.ParseScheduleWindow(row.AsString("cutover_window", ""))
+		stage := row.AsString("stage", "")
 
 		uuidsFoundRunning[uuid] = true
 
@@ -3363,6 +3647,17 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 						return nil
 					}
 				}
+				if strategySetting.IsAtomicCutOver() {
+					// An --atomic-cut-over migration cannot complete if any migration in its group has failed.
+					group, err := e.readAtomicCutOverGroup(ctx, onlineDDL)
+					if err != nil {
+						return err
+					}
+					if failed := group.failedMember(); failed != nil {
+						cancellable = append(cancellable, newCancellableMigration(uuid, fmt.Sprintf("migration %v in same atomic cut-over group has failed/was cancelled", failed.uuid)))
+						return nil
+					}
+				}
 
 				// Check if the migration is ready to cut-over, and proceed to do so if it is.
 				isReady, err := e.isVReplMigrationReadyToCutOver(ctx, onlineDDL, s)
@@ -3399,13 +3694,58 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 					// override. Even if migration is ready, we do not complete it.
 					return nil
 				}
+				if !cutoverWindow.IsOpen(time.Now()) {
+					// The migration is ready, but may only cut-over within its --cut-over-window. This
+					// overrides a forced cut-over, too.
+					if waitStage := "waiting for cut-over window: " + cutoverWindow.String(); stage != waitStage {
+						_ = e.updateMigrationStage(ctx, uuid, "%s", waitStage)
+					}
+					return nil
+				}
+				if cutoverWindow != nil {
+					// --force-cut-over-after only counts the time the migration was ready while its
+					// --cut-over-window was open, so that it does not force a cut-over as soon as the window opens.
+					now := time.Now()
+					sinceReadyToComplete = cutoverWindow.OpenDuration(now.Add(-sinceReadyToComplete), now)
+				}
+				streams := []*VReplStream{s}
+				if strategySetting.IsAtomicCutOver() {
+					// The migration is ready, but may only cut-over together with all other migrations in its
+					// group, once they are all ready.
+					group, err := e.readAtomicCutOverGroup(ctx, onlineDDL)
+					if err != nil {
+						return err
+					}
+					groupUUIDs, waitingOn := group.readyToCutOver(time.Now())
+					if len(groupUUIDs) == 0 && waitingOn == "" {
+						// All migrations in the group have completed, there is nothing left to cut-over.
+						return nil
+					}
+					if len(groupUUIDs) == 0 {
+						if waitStage := "waiting for atomic cut-over group: " + waitingOn; stage != waitStage {
+							_ = e.updateMigrationStage(ctx, uuid, "%s", waitStage)
+						}
+						return nil
+					}
+					streams = streams[:0]
+					for _, groupUUID := range groupUUIDs {
+						groupStream, err := e.readVReplStream(ctx, groupUUID, true)
+						if err != nil {
+							return err
+						}
+						if groupStream == nil || !groupStream.isRunning() {
+							return nil
+						}
+						streams = append(streams, groupStream)
+					}
+				}
 				shouldCutOver, shouldForceCutOver := shouldCutOverAccordingToBackoff(
 					shouldForceCutOver, forceCutOverAfter, sinceReadyToComplete, sinceLastCutoverAttempt, cutoverAttempts,
 				)
 				if !shouldCutOver {
 					return nil
 				}
-				if err := e.cutOverVReplMigration(ctx, s, shouldForceCutOver); err != nil {
+				if err := e.cutOverVReplMigration(ctx, streams, shouldForceCutOver); err != nil {
 					_ = e.updateMigrationMessage(ctx, uuid, err.Error())
 					log.Error(fmt.Sprintf("cutOverVReplMigration failed %s: err=%v", onlineDDL.UUID, err))
 
@@ -4730,6 +5070,11 @@ func (e *Executor) SubmitMigration(
 	log.Info(fmt.Sprintf("SubmitMigration: request to submit migration %s; action=%s, table=%s", onlineDDL.UUID, actionStr, onlineDDL.Table))
 
 	revertedUUID, _ := onlineDDL.GetRevertUUID(e.env.Environment().Parser()) // Empty value if the migration is not actually a REVERT. Safe to ignore error.
+	if onlineDDL.StrategySetting().IsAtomicCutOver() {
+		if err := e.validateAtomicCutOverMigration(ctx, onlineDDL, actionStr, revertedUUID); err != nil {
+			return nil, err
+		}
+	}
 	retainArtifactsSeconds := int64((retainOnlineDDLTables).Seconds())
 	if retainArtifacts, _ := onlineDDL.StrategySetting().RetainArtifactsDuration(); retainArtifacts != 0 {
 		// Explicit retention indicated by `--retain-artifact` DDL strategy flag for this migration. Override!
@@ -4743,6 +5088,10 @@ func (e *Executor) SubmitMigration(
 	if err != nil {
 		return nil, vterrors.Wrapf(err, "validating cut-over threshold in migration %v", onlineDDL.UUID)
 	}
+	cutoverWindow, err := onlineDDL.StrategySetting().CutOverWindow()
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "parsing cut-over window in migration %v", onlineDDL.UUID)
+	}
 	_, allowConcurrentMigration := e.allowConcurrentMigration(onlineDDL)
 	submitQuery, err := sqlparser.ParseAndBind(sqlInsertMigration,
 		sqltypes.StringBindVariable(onlineDDL.UUID),
@@ -4759,6 +5108,7 @@ func (e *Executor) SubmitMigration(
 		sqltypes.StringBindVariable(e.TabletAliasString()),
 		sqltypes.Int64BindVariable(retainArtifactsSeconds),
 		sqltypes.Int64BindVariable(int64(cutoverThreshold.Seconds())),
+		sqltypes.StringBindVariable(cutoverWindow.String()),
 		sqltypes.BoolBindVariable(onlineDDL.StrategySetting().IsPostponeLaunch()),
 		sqltypes.BoolBindVariable(onlineDDL.StrategySetting().IsPostponeCompletion()),
 		sqltypes.BoolBindVariable(allowConcurrentMigration),
@@ -22,6 +22,10 @@ package onlineddl
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -34,12 +38,15 @@ import (
 	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/dbconnpool"
 	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/vttablet/tmclienttest"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
@@ -272,6 +279,270 @@ func TestGetInOrderCompletionPendingCount(t *testing.T) {
 	}
 }
 
+func TestBuildLockTablesWriteQuery(t *testing.T) {
+	assert.Equal(t, "LOCK TABLES `sentry` WRITE, `t1` WRITE", buildLockTablesWriteQuery("sentry", "t1"))
+	assert.Equal(t, "LOCK TABLES `sentry` WRITE, `t1` WRITE, `t2` WRITE", buildLockTablesWriteQuery("sentry", "t1", "t2"))
+}
+
+func TestBuildSwapTablesViaSentryQuery(t *testing.T) {
+	{
+		query := buildSwapTablesViaSentryQuery("sentry", []string{"t1"}, []string{"v1"})
+		// Same as the classic single table swap
+		assert.Equal(t, "RENAME TABLE `t1` TO `sentry`, `v1` TO `t1`, `sentry` TO `v1`", query)
+	}
+	{
+		query := buildSwapTablesViaSentryQuery("sentry", []string{"t1", "t2"}, []string{"v1", "v2"})
+		assert.Equal(t, "RENAME TABLE `t1` TO `sentry`, `v1` TO `t1`, `sentry` TO `v1`, `t2` TO `sentry`, `v2` TO `t2`, `sentry` TO `v2`", query)
+	}
+}
+
+func TestAtomicCutOverGroup(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	closedWindow, err := timer.ParseScheduleWindow("02:00-04:00")
+	require.NoError(t, err)
+	readyMember := func(uuid string) *atomicCutOverGroupMember {
+		return &atomicCutOverGroupMember{
+			uuid:            uuid,
+			status:          schema.OnlineDDLStatusRunning,
+			ddlAction:       "alter",
+			readyToComplete: true,
+		}
+	}
+	tcases := []struct {
+		name            string
+		group           atomicCutOverGroup
+		expectUUIDs     []string
+		expectWaitingOn string
+		expectFailed    string
+	}{
+		{
+			name:        "all ready",
+			group:       atomicCutOverGroup{readyMember("a"), readyMember("b")},
+			expectUUIDs: []string{"a", "b"},
+		},
+		{
+			name: "completed member excluded",
+			group: atomicCutOverGroup{
+				readyMember("a"),
+				{uuid: "b", status: schema.OnlineDDLStatusComplete, ddlAction: "alter"},
+				readyMember("c"),
+			},
+			expectUUIDs: []string{"a", "c"},
+		},
+		{
+			name: "all complete",
+			group: atomicCutOverGroup{
+				{uuid: "a", status: schema.OnlineDDLStatusComplete, ddlAction: "alter"},
+				{uuid: "b", status: schema.OnlineDDLStatusComplete, ddlAction: "alter"},
+			},
+		},
+		{
+			name: "member not ready",
+			group: atomicCutOverGroup{
+				readyMember("a"),
+				{uuid: "b", status: schema.OnlineDDLStatusRunning, ddlAction: "alter"},
+			},
+			expectWaitingOn: "migration b",
+		},
+		{
+			name: "member queued",
+			group: atomicCutOverGroup{
+				readyMember("a"),
+				{uuid: "b", status: schema.OnlineDDLStatusQueued, ddlAction: "alter"},
+			},
+			expectWaitingOn: "migration b",
+		},
+		{
+			name: "member postponed",
+			group: atomicCutOverGroup{
+				readyMember("a"),
+				{uuid: "b", status: schema.OnlineDDLStatusRunning, ddlAction: "alter", readyToComplete: true, postponeCompletion: true},
+			},
+			expectWaitingOn: "migration b",
+		},
+		{
+			name: "member cut-over window closed",
+			group: atomicCutOverGroup{
+				{uuid: "a", status: schema.OnlineDDLStatusRunning, ddlAction: "alter", readyToComplete: true, cutoverWindow: closedWindow},
+				readyMember("b"),
+			},
+			expectWaitingOn: "migration a",
+		},
+		{
+			name: "members yet to be submitted",
+			group: atomicCutOverGroup{
+				{uuid: "a", groupSize: 3, status: schema.OnlineDDLStatusRunning, ddlAction: "alter", readyToComplete: true},
+			},
+			expectWaitingOn: "submission of 2 more migrations",
+		},
+		{
+			name: "all members submitted",
+			group: atomicCutOverGroup{
+				{uuid: "a", groupSize: 2, status: schema.OnlineDDLStatusRunning, ddlAction: "alter", readyToComplete: true},
+				{uuid: "b", groupSize: 2, status: schema.OnlineDDLStatusRunning, ddlAction: "alter", readyToComplete: true},
+			},
+			expectUUIDs: []string{"a", "b"},
+		},
+		{
+			name: "member failed",
+			group: atomicCutOverGroup{
+				readyMember("a"),
+				{uuid: "b", status: schema.OnlineDDLStatusFailed, ddlAction: "alter"},
+				{uuid: "c", status: schema.OnlineDDLStatusCancelled, ddlAction: "alter"},
+			},
+			expectWaitingOn: "migration b",
+			expectFailed:    "b",
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			uuids, waitingOn := tcase.group.readyToCutOver(now)
+			assert.Equal(t, tcase.expectUUIDs, uuids)
+			assert.Equal(t, tcase.expectWaitingOn, waitingOn)
+			failed := tcase.group.failedMember()
+			if tcase.expectFailed == "" {
+				assert.Nil(t, failed)
+			} else {
+				require.NotNil(t, failed)
+				assert.Equal(t, tcase.expectFailed, failed.uuid)
+			}
+		})
+	}
+}
+
+func TestAtomicCutOverGroupValidateNewMember(t *testing.T) {
+	group := atomicCutOverGroup{
+		{uuid: "a", table: "t1", groupSize: 5, status: schema.OnlineDDLStatusRunning},
+		{uuid: "b", table: "t2", groupSize: 5, status: schema.OnlineDDLStatusQueued},
+		{uuid: "c", table: "t3", groupSize: 5, status: schema.OnlineDDLStatusComplete},
+		{uuid: "d", table: "t4", groupSize: 5, status: schema.OnlineDDLStatusCancelled},
+	}
+	tcases := []struct {
+		name          string
+		table         string
+		groupSize     int
+		maxConcurrent int
+		expectErr     string
+	}{
+		{
+			name:          "new table",
+			table:         "t5",
+			groupSize:     5,
+			maxConcurrent: 5,
+		},
+		{
+			name:          "table of a completed migration",
+			table:         "t3",
+			groupSize:     5,
+			maxConcurrent: 5,
+		},
+		{
+			name:          "table of a pending migration",
+			table:         "t2",
+			groupSize:     5,
+			maxConcurrent: 5,
+			expectErr:     "migration b in same atomic cut-over group already changes table t2",
+		},
+		{
+			name:          "different group size",
+			table:         "t5",
+			groupSize:     6,
+			maxConcurrent: 6,
+			expectErr:     "migration a in same atomic cut-over group expects a group of 5 migrations, not 6",
+		},
+		{
+			name:          "group already complete",
+			table:         "t5",
+			groupSize:     4,
+			maxConcurrent: 5,
+			expectErr:     "atomic cut-over group already has all of its 4 migrations",
+		},
+		{
+			name:          "group larger than max concurrent migrations",
+			table:         "t5",
+			groupSize:     5,
+			maxConcurrent: 4,
+			expectErr:     "atomic cut-over group of 5 migrations is larger than --max-concurrent-online-ddl=4",
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			err := group.validateNewMember(tcase.table, tcase.groupSize, tcase.maxConcurrent)
+			if tcase.expectErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tcase.expectErr)
+			}
+		})
+	}
+}
+
+// TestAtomicCutOverGroupSubmittedOneByOne submits the migrations of an atomic cut-over group one at a time,
+// and checks that the group does not cut-over before all of its migrations are submitted, even if the
+// migrations submitted so far are all ready.
+func TestAtomicCutOverGroupSubmittedOneByOne(t *testing.T) {
+	ctx := t.Context()
+	env := tabletenv.NewEnv(vtenv.NewTestEnv(), tabletenv.NewDefaultConfig(), "ExecutorTest")
+	const migrationContext = "atomic-group"
+	query, err := sqlparser.ParseAndBind(sqlSelectMigrationsInContext, sqltypes.StringBindVariable(migrationContext))
+	require.NoError(t, err)
+
+	fields := sqltypes.MakeTestFields(
+		"migration_uuid|mysql_table|strategy|options|ddl_action|migration_status|ready_to_complete|postpone_completion|cutover_window",
+		"varchar|varchar|varchar|varchar|varchar|varchar|int64|int64|varchar",
+	)
+	var submitted []string
+	executor := &Executor{
+		env: env,
+		execQuery: func(ctx context.Context, q string) (*sqltypes.Result, error) {
+			require.Equal(t, query, q)
+			return sqltypes.MakeTestResult(fields, submitted...), nil
+		},
+	}
+	submit := func(table string, options string) (string, error) {
+		setting := schema.NewDDLStrategySetting(schema.DDLStrategyVitess, options)
+		onlineDDL, err := schema.NewOnlineDDL("ks", table, fmt.Sprintf("alter table %s add column i int", table), setting, migrationContext, "", env.Environment().Parser())
+		require.NoError(t, err)
+		if err := executor.validateAtomicCutOverMigration(ctx, onlineDDL, sqlparser.AlterStr, ""); err != nil {
+			return "", err
+		}
+		// The migration runs right away, and becomes ready to cut-over.
+		submitted = append(submitted, fmt.Sprintf("%s|%s|vitess|%s|alter|running|1|0|", onlineDDL.UUID, table, options))
+		return onlineDDL.UUID, nil
+	}
+	readyToCutOver := func() ([]string, string) {
+		onlineDDL := &schema.OnlineDDL{MigrationContext: migrationContext}
+		group, err := executor.readAtomicCutOverGroup(ctx, onlineDDL)
+		require.NoError(t, err)
+		return group.readyToCutOver(time.Now())
+	}
+
+	const options = "--atomic-cut-over --atomic-cut-over-group-size=3"
+	var uuids []string
+	for i, table := range []string{"t1", "t2", "t3"} {
+		uuid, err := submit(table, options)
+		require.NoError(t, err)
+		uuids = append(uuids, uuid)
+
+		groupUUIDs, waitingOn := readyToCutOver()
+		if i < 2 {
+			assert.Empty(t, groupUUIDs)
+			assert.Equal(t, fmt.Sprintf("submission of %d more migrations", 2-i), waitingOn)
+		} else {
+			assert.Equal(t, uuids, groupUUIDs)
+			assert.Empty(t, waitingOn)
+		}
+	}
+
+	// The group is complete, and does not take any more migrations.
+	_, err = submit("t4", options)
+	assert.ErrorContains(t, err, "atomic cut-over group already has all of its 3 migrations")
+
+	// A migration that doesn't record the size of its group cannot join it.
+	_, err = submit("t4", "--atomic-cut-over")
+	assert.ErrorContains(t, err, "--atomic-cut-over requires --atomic-cut-over-group-size")
+}
+
 func TestInitDBConnectionLockWaitTimeout(t *testing.T) {
 	db := fakesqldb.New(t)
 	defer db.Close()
@@ -359,6 +630,204 @@ func (fakeTabletManagerClient) ReloadSchema(ctx context.Context, tablet *topodat
 	return nil
 }
 
+// fakeCutOverTabletManagerClient records the vreplication queries of a cut-over.
+type fakeCutOverTabletManagerClient struct {
+	fakeTabletManagerClient
+
+	mu                  sync.Mutex
+	vreplicationQueries []string
+}
+
+func (tmc *fakeCutOverTabletManagerClient) VReplicationExec(ctx context.Context, tablet *topodatapb.Tablet, query string) (*querypb.QueryResult, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.vreplicationQueries = append(tmc.vreplicationQueries, query)
+	return &querypb.QueryResult{}, nil
+}
+
+func (tmc *fakeCutOverTabletManagerClient) VReplicationWaitForPos(ctx context.Context, tablet *topodatapb.Tablet, id int32, pos string) error {
+	return nil
+}
+
+func (tmc *fakeCutOverTabletManagerClient) RefreshState(ctx context.Context, tablet *topodatapb.Tablet) error {
+	return nil
+}
+
+func (tmc *fakeCutOverTabletManagerClient) queries() []string {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	return slices.Clone(tmc.vreplicationQueries)
+}
+
+func TestCutOverVReplMigrationMultipleStreams(t *testing.T) {
+	tables := []string{"t1", "t2"}
+	vreplTables := []string{"_vrepl_t1", "_vrepl_t2"}
+	uuids := []string{"uuid1", "uuid2"}
+
+	tcases := []struct {
+		name        string
+		renameError string
+	}{
+		{
+			name: "rename succeeds",
+		},
+		{
+			name:        "rename fails",
+			renameError: "rename failed",
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			ctx := t.Context()
+			db := fakesqldb.New(t)
+			defer db.Close()
+			db.SetNeverFail(true)
+			db.AddQuery("SELECT @@global.gtid_executed", sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+				"gtid_executed",
+				"varchar"),
+				"7b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-3",
+			))
+			if tcase.renameError != "" {
+				db.RejectQueryPattern("rename table .*", tcase.renameError)
+			}
+			params := db.ConnParams()
+
+			tmc := &fakeCutOverTabletManagerClient{}
+			protocolName := t.Name()
+			resetProtocol := tmclienttest.SetProtocol(t.Name(), protocolName)
+			defer resetProtocol()
+			tmclient.RegisterTabletManagerClientFactory(protocolName, func() tmclient.TabletManagerClient {
+				return tmc
+			})
+			alias := &topodatapb.TabletAlias{Cell: "cell", Uid: 1}
+			ts := memorytopo.NewServer(ctx, "cell")
+			err := ts.CreateTablet(ctx, &topodatapb.Tablet{
+				Alias:    alias,
+				Keyspace: "ks",
+				Shard:    "0",
+				Type:     topodatapb.TabletType_PRIMARY,
+			})
+			require.NoError(t, err)
+
+			cfg := tabletenv.NewDefaultConfig()
+			cfg.DB = dbconfigs.NewTestDBConfigs(*params, *params, params.DbName)
+			env := tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "ExecutorTest")
+			var bufferedTables []string
+			toggleBufferTable := func(cancelCtx context.Context, tableName string, timeout time.Duration, bufferQueries bool) {
+				if bufferQueries {
+					bufferedTables = append(bufferedTables, tableName)
+				}
+			}
+			executor := NewExecutor(env, alias, ts, nil, nil, toggleBufferTable, nil, nil)
+			executor.InitDBConfig("ks", "0", params.DbName)
+			executor.pool.Open(cfg.DB.AppWithDB(), cfg.DB.DbaWithDB(), cfg.DB.AppDebugWithDB())
+			defer executor.pool.Close()
+
+			// The migrations and their streams are read via execQuery, while the LOCK and RENAME run on
+			// connections of the pool.
+			results := map[string]*sqltypes.Result{}
+			var streams []*VReplStream
+			for i, uuid := range uuids {
+				query, err := sqlparser.ParseAndBind(sqlSelectMigration, sqltypes.StringBindVariable(uuid))
+				require.NoError(t, err)
+				results[query] = sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+					"migration_uuid|keyspace|mysql_table|strategy|options|migration_status",
+					"varchar|varchar|varchar|varchar|varchar|varchar"),
+					fmt.Sprintf("%s|ks|%s|vitess|--atomic-cut-over|running", uuid, tables[i]),
+				)
+				streams = append(streams, &VReplStream{
+					id:       int32(i + 1),
+					workflow: uuid,
+					bls: &binlogdatapb.BinlogSource{
+						Filter: &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{{Match: vreplTables[i]}}},
+					},
+				})
+				query, err = sqlparser.ParseAndBind(sqlReadVReplStream, sqltypes.StringBindVariable(uuid))
+				require.NoError(t, err)
+				results[query] = sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+					"id|workflow|source|pos|state",
+					"int64|varchar|varchar|varchar|varchar"),
+					fmt.Sprintf("%d|%s|filter:{rules:{match:\"%s\"}}|MySQL56/7b04699f-f5e9-11e9-bf88-9cb6d089e1c3:1-3|Running", i+1, uuid, vreplTables[i]),
+				)
+			}
+			var migrationQueries []string
+			var mu sync.Mutex
+			executor.execQuery = func(ctx context.Context, query string) (*sqltypes.Result, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				migrationQueries = append(migrationQueries, query)
+				if result, ok := results[query]; ok {
+					return result, nil
+				}
+				if strings.Contains(query, "information_schema.processlist") {
+					// The RENAME is found to be waiting on the LOCK.
+					return sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|info", "int64|varchar"), "1|rename"), nil
+				}
+				return &sqltypes.Result{}, nil
+			}
+
+			err = executor.cutOverVReplMigration(ctx, streams, false)
+			if tcase.renameError != "" {
+				assert.ErrorContains(t, err, tcase.renameError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tables, bufferedTables)
+
+			// A single LOCK and a single RENAME cover all tables.
+			var lockQueries, renameQueries []string
+			for _, query := range strings.Split(db.QueryLog(), ";") {
+				switch {
+				case strings.HasPrefix(query, "lock tables "):
+					lockQueries = append(lockQueries, query)
+				case strings.HasPrefix(query, "rename table "):
+					renameQueries = append(renameQueries, query)
+				}
+			}
+			require.Len(t, lockQueries, 1)
+			require.Len(t, renameQueries, 1)
+			sentryTableName, _, ok := strings.Cut(strings.TrimPrefix(lockQueries[0], "lock tables `"), "`")
+			require.True(t, ok)
+			assert.Equal(t, strings.ToLower(buildLockTablesWriteQuery(append([]string{sentryTableName}, tables...)...)), lockQueries[0])
+			assert.Equal(t, strings.ToLower(buildSwapTablesViaSentryQuery(sentryTableName, tables, vreplTables)), renameQueries[0])
+
+			// All streams are stopped, and if the RENAME fails, they are all restarted.
+			var stopped, started []string
+			for _, query := range tmc.queries() {
+				switch {
+				case strings.HasPrefix(query, "update _vt.vreplication set state='Stopped'"):
+					stopped = append(stopped, query)
+				case strings.HasPrefix(query, "UPDATE _vt.vreplication set state='Running'"):
+					started = append(started, query)
+				}
+			}
+			assert.Len(t, stopped, len(streams))
+			var expectStarted []string
+			if tcase.renameError != "" {
+				for _, uuid := range uuids {
+					query, err := sqlparser.ParseAndBind(sqlStartVReplStream, sqltypes.StringBindVariable(params.DbName), sqltypes.StringBindVariable(uuid))
+					require.NoError(t, err)
+					expectStarted = append(expectStarted, query)
+				}
+			}
+			assert.ElementsMatch(t, expectStarted, started)
+
+			// Only a successful RENAME completes the migrations.
+			for _, uuid := range uuids {
+				query, err := sqlparser.ParseAndBind(sqlUpdateMigrationStatus,
+					sqltypes.StringBindVariable(string(schema.OnlineDDLStatusComplete)),
+					sqltypes.StringBindVariable(uuid),
+				)
+				require.NoError(t, err)
+				mu.Lock()
+				completed := slices.Contains(migrationQueries, query)
+				mu.Unlock()
+				assert.Equal(t, tcase.renameError == "", completed)
+			}
+		})
+	}
+}
+
 func TestMigrationMetricsIncrement(t *testing.T) {
 	tcases := []struct {
 		name     string
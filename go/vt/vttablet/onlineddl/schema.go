@@ -33,13 +33,14 @@ const (
 		tablet,
 		retain_artifacts_seconds,
 		cutover_threshold_seconds,
+		cutover_window,
 		postpone_launch,
 		postpone_completion,
 		allow_concurrent,
 		reverted_uuid,
 		is_view
 	) VALUES (
-		%a, %a, %a, %a, %a, %a, %a, %a, %a, NOW(6), %a, %a, %a, %a, %a, %a, %a, %a, %a, %a
+		%a, %a, %a, %a, %a, %a, %a, %a, %a, NOW(6), %a, %a, %a, %a, %a, %a, %a, %a, %a, %a, %a
 	)`
 
 	sqlSelectQueuedMigrations = `SELECT
@@ -332,6 +333,8 @@ const (
 			postpone_completion,
 			force_cutover,
 			cutover_attempts,
+			cutover_window,
+			stage,
 			ifnull(timestampdiff(microsecond, ready_to_complete_timestamp, now(6)), 0) as microseconds_since_ready_to_complete,
 			ifnull(timestampdiff(second, last_cutover_attempt_timestamp, now()), 0) as seconds_since_last_cutover_attempt,
 			timestampdiff(second, started_timestamp, now()) as elapsed_seconds
@@ -383,6 +386,21 @@ const (
 			)
 		ORDER BY id
 	`
+	sqlSelectMigrationsInContext = `SELECT
+			migration_uuid,
+			mysql_table,
+			strategy,
+			options,
+			ddl_action,
+			migration_status,
+			ready_to_complete,
+			postpone_completion,
+			cutover_window
+		FROM _vt.schema_migrations
+		WHERE
+			migration_context=%a
+		ORDER BY id
+	`
 	sqlSelectPendingMigrations = `SELECT
 			migration_uuid,
 			migration_context,
@@ -556,7 +574,8 @@ const (
 		`
 	sqlSwapTables              = "RENAME TABLE `%a` TO `%a`, `%a` TO `%a`, `%a` TO `%a`"
 	sqlRenameTable             = "RENAME TABLE `%a` TO `%a`"
-	sqlLockTwoTablesWrite      = "LOCK TABLES `%a` WRITE, `%a` WRITE"
+	sqlLockTableWriteClause    = "`%a` WRITE"
+	sqlRenameTableClause       = "`%a` TO `%a`"
 	sqlUnlockTables            = "UNLOCK TABLES"
 	sqlCreateSentryTable       = "CREATE TABLE IF NOT EXISTS `%a` (id INT PRIMARY KEY)"
 	sqlFindProcess             = "SELECT id, Info as info FROM information_schema.processlist WHERE id=%a AND Info LIKE %a"
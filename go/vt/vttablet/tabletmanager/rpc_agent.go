@@ -170,6 +170,8 @@ type RPCTM interface {
 
 	RestoreFromBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.RestoreFromBackupRequest) error
 
+	VerifyBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.VerifyBackupRequest) error
+
 	IsBackupRunning() bool
 
 	// HandleRPCPanic is to be called in a defer statement in each
@@ -18,8 +18,13 @@ package tabletmanager
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"os"
 	"time"
 
 	"vitess.io/vitess/go/protoutil"
@@ -217,6 +222,95 @@ func (tm *TabletManager) RestoreFromBackup(ctx context.Context, logger logutil.L
 	return err
 }
 
+// VerifyBackup restores a backup of the tablet's shard into a scratch mysqld,
+// next to the tablet's own mysqld, and checks the restored data. The scratch
+// mysqld and its data are removed afterwards, and the tablet keeps serving.
+func (tm *TabletManager) VerifyBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.VerifyBackupRequest) error {
+	tm.mutex.Lock()
+	if tm._isBackupVerificationRunning {
+		tm.mutex.Unlock()
+		return fmt.Errorf("a backup verification is already running on tablet: %v", tm.tabletAlias)
+	}
+	tm._isBackupVerificationRunning = true
+	tm.mutex.Unlock()
+	defer func() {
+		tm.mutex.Lock()
+		tm._isBackupVerificationRunning = false
+		tm.mutex.Unlock()
+	}()
+
+	tablet := tm.Tablet()
+
+	// Create the logger: tee to console and source.
+	l := logutil.NewTeeLogger(logutil.NewConsoleLogger(), logger)
+
+	// The scratch mysqld gets its own tablet directory, with a random UID so
+	// it does not collide with any tablet on this host, and a free port.
+	uid, err := rand.Int(rand.Reader, big.NewInt(math.MaxUint32))
+	if err != nil {
+		return fmt.Errorf("can't generate random UID for scratch mysqld: %v", err)
+	}
+	port, err := freePort()
+	if err != nil {
+		return fmt.Errorf("can't find a free port for scratch mysqld: %v", err)
+	}
+	mysqld, cnf, err := mysqlctl.CreateScratchMysqldAndMycnf(uint32(uid.Uint64()), port, tm.DBConfigs, tm.Env.CollationEnv())
+	if err != nil {
+		return err
+	}
+	defer mysqld.Close()
+	defer func() {
+		l.Infof("VerifyBackup: removing scratch directory %v", cnf.TabletDir())
+		if err := os.RemoveAll(cnf.TabletDir()); err != nil {
+			l.Warningf("VerifyBackup: failed to remove scratch directory: %v", err)
+		}
+	}()
+
+	l.Infof("VerifyBackup: starting scratch mysqld in %v", cnf.TabletDir())
+	if err := mysqld.Init(ctx, cnf, ""); err != nil {
+		return vterrors.Wrap(err, "failed to initialize scratch mysqld")
+	}
+	defer func() {
+		// Use a new context, so mysqld is stopped even if ctx was cancelled.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), mysqlShutdownTimeout+10*time.Second)
+		defer cancel()
+		if err := mysqld.Shutdown(shutdownCtx, cnf, true, mysqlShutdownTimeout); err != nil {
+			l.Errorf("VerifyBackup: failed to shutdown scratch mysqld: %v", err)
+		}
+	}()
+
+	concurrency := int(request.Concurrency)
+	if concurrency <= 0 {
+		concurrency = restoreConcurrency
+	}
+	params := mysqlctl.RestoreParams{
+		Cnf:                  cnf,
+		Mysqld:               mysqld,
+		Logger:               l,
+		Concurrency:          concurrency,
+		HookExtraEnv:         tm.hookExtraEnv(),
+		DeleteBeforeRestore:  true,
+		DbName:               topoproto.TabletDbName(tablet),
+		Keyspace:             tablet.Keyspace,
+		Shard:                tablet.Shard,
+		Stats:                backupstats.RestoreStats(),
+		MysqlShutdownTimeout: mysqlShutdownTimeout,
+		BackupName:           request.BackupName,
+	}
+	_, err = mysqlctl.VerifyBackup(ctx, params)
+	return err
+}
+
+// freePort returns a TCP port that is free on this host.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
 func (tm *TabletManager) IsBackupRunning() bool {
 	return tm._isBackupRunning
 }
@@ -226,6 +226,8 @@ type TabletManager struct {
 	_lockTablesTimer      *time.Timer
 	// _isBackupRunning tells us whether there is a backup that is currently running
 	_isBackupRunning bool
+	// _isBackupVerificationRunning tells us whether there is a backup being verified
+	_isBackupVerificationRunning bool
 }
 
 // BuildTabletFromInput builds a tablet record from input parameters.
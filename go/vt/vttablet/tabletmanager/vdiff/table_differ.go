@@ -166,33 +166,42 @@ func (td *tableDiffer) initialize(ctx context.Context) error {
 		}
 	}()
 
-	if err := td.stopTargetVReplicationStreams(vctx, dbClient); err != nil {
-		return err
-	}
-	defer func() {
-		// We use a new context as we want to reset the state even
-		// when the parent context has timed out or been canceled.
-		log.Info(fmt.Sprintf("Restarting the %q VReplication workflow for vdiff %s on target tablets in keyspace %q", td.wd.ct.workflow, td.wd.ct.uuid, targetKeyspace))
-		restartCtx, restartCancel := context.WithTimeout(context.Background(), BackgroundOperationTimeout)
-		defer restartCancel()
-		if err := td.restartTargetVReplicationStreams(restartCtx); err != nil {
-			log.Error(fmt.Sprintf("error restarting target streams for vdiff %s: %v", td.wd.ct.uuid, err))
+	// When the workflow doesn't maintain the target anymore, the data is
+	// compared as it is and the streams are left alone.
+	syncStreams := !td.wd.opts.CoreOptions.NoStreamSync
+	if syncStreams {
+		if err := td.stopTargetVReplicationStreams(vctx, dbClient); err != nil {
+			return err
 		}
-	}()
+		defer func() {
+			// We use a new context as we want to reset the state even
+			// when the parent context has timed out or been canceled.
+			log.Info(fmt.Sprintf("Restarting the %q VReplication workflow for vdiff %s on target tablets in keyspace %q", td.wd.ct.workflow, td.wd.ct.uuid, targetKeyspace))
+			restartCtx, restartCancel := context.WithTimeout(context.Background(), BackgroundOperationTimeout)
+			defer restartCancel()
+			if err := td.restartTargetVReplicationStreams(restartCtx); err != nil {
+				log.Error(fmt.Sprintf("error restarting target streams for vdiff %s: %v", td.wd.ct.uuid, err))
+			}
+		}()
+	}
 
 	td.shardStreamsCtx, td.shardStreamsCancel = context.WithCancel(vctx)
 
 	if err := td.selectTablets(vctx); err != nil {
 		return err
 	}
-	if err := td.syncSourceStreams(vctx); err != nil {
-		return err
+	if syncStreams {
+		if err := td.syncSourceStreams(vctx); err != nil {
+			return err
+		}
 	}
 	if err := td.startSourceDataStreams(td.shardStreamsCtx); err != nil {
 		return err
 	}
-	if err := td.syncTargetStreams(vctx); err != nil {
-		return err
+	if syncStreams {
+		if err := td.syncTargetStreams(vctx); err != nil {
+			return err
+		}
 	}
 	if err := td.startTargetDataStream(td.shardStreamsCtx); err != nil {
 		return err
@@ -318,12 +318,36 @@ func (vc *vcopier) copyNext(ctx context.Context, settings binlogplayer.VRSetting
 	if len(copyState) == 0 {
 		return errors.New("unexpected: there are no tables to copy")
 	}
+	if !vc.waitForScheduleWindow(ctx) {
+		return nil
+	}
 	if err := vc.catchup(ctx, copyState); err != nil {
 		return err
 	}
 	return vc.copyTable(ctx, tableToCopy, copyState)
 }
 
+// waitForScheduleWindow blocks until the schedule window of the workflow is open, so that a copy cycle
+// does not start outside of it. It returns false if the context is done.
+func (vc *vcopier) waitForScheduleWindow(ctx context.Context) bool {
+	for !vc.vr.scheduleWindowOpenOrWait(ctx, throttlerapp.VCopierName) {
+		if ctx.Err() != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// copyPhaseDuration returns how long a copy cycle can run: the copy phase duration of the workflow, capped
+// by the time left until its schedule window closes, so that the copy stops when the window closes.
+func (vc *vcopier) copyPhaseDuration() time.Duration {
+	duration := vc.vr.workflowConfig.CopyPhaseDuration
+	if vc.vr.scheduleWindow != nil {
+		duration = min(duration, vc.vr.scheduleWindow.UntilClose(time.Now()))
+	}
+	return duration
+}
+
 // catchup replays events to the subset of the tables that have been copied
 // until replication is caught up. In order to stop, the seconds behind primary has
 // to fall below replicationLagTolerance.
@@ -395,7 +419,7 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 		return fmt.Errorf("plan not found for table: %s, current plans are: %#v", tableName, plan.TargetTables)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, vc.vr.workflowConfig.CopyPhaseDuration)
+	ctx, cancel := context.WithTimeout(ctx, vc.copyPhaseDuration())
 	defer cancel()
 
 	var lastpkpb *querypb.QueryResult
@@ -508,6 +532,9 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 		if len(rows.Rows) == 0 {
 			return nil
 		}
+		if err := vc.vr.waitForRateLimit(ctx, vc.vr.copyRateLimiter, throttlerapp.VCopierName, int64(len(rows.Rows)), rowsSize(rows.Rows)); err != nil {
+			return io.EOF
+		}
 
 		// Clone rows, since pointer values will change while async work is
 		// happening. Can skip this when there's no parallelism.
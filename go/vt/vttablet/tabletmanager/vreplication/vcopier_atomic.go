@@ -34,6 +34,7 @@ import (
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 )
 
 /*
@@ -73,6 +74,10 @@ func (vc *vcopier) copyAll(ctx context.Context, settings binlogplayer.VRSettings
 	defer log.Info("Returning from copyAll for " + settings.WorkflowName)
 	defer vc.vr.dbClient.Rollback()
 
+	if !vc.waitForScheduleWindow(ctx) {
+		return nil
+	}
+
 	state, err := newCopyAllState(vc)
 	if err != nil {
 		return err
@@ -198,6 +203,9 @@ func (vc *vcopier) copyAll(ctx context.Context, settings binlogplayer.VRSettings
 			},
 		}
 		log.Info(fmt.Sprintf("copying table %s with lastpk %v", tableName, lastpkbv))
+		if err := vc.vr.waitForRateLimit(ctx, vc.vr.copyRateLimiter, throttlerapp.VCopierName, int64(len(resp.Rows)), rowsSize(resp.Rows)); err != nil {
+			return io.EOF
+		}
 		// Prepare a vcopierCopyTask for the current batch of work.
 		currCh := make(chan *vcopierCopyTaskResult, 1)
 
@@ -486,11 +486,22 @@ func (vp *vplayer) applyEvents(ctx context.Context, relay *relayLog) error {
 			estimateLag()
 			continue
 		}
+		// Check the schedule window of the workflow.
+		if !vp.vr.scheduleWindowOpenOrWait(ctx, throttlerapp.VPlayerName) {
+			estimateLag()
+			continue
+		}
 
 		items, err := relay.Fetch()
 		if err != nil {
 			return err
 		}
+		if vp.vr.playerRateLimiter != nil {
+			rows, bytes := rowChangesSize(items)
+			if err := vp.vr.waitForRateLimit(ctx, vp.vr.playerRateLimiter, throttlerapp.VPlayerName, rows, bytes); err != nil {
+				return err
+			}
+		}
 
 		// Empty transactions are saved at most once every idleTimeout.
 		// This covers two situations:
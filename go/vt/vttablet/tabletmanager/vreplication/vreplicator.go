@@ -125,6 +125,13 @@ type vreplicator struct {
 
 	throttleUpdatesRateLimiter *timer.RateLimiter
 	workflowConfig             *vttablet.VReplicationConfig
+
+	// copyRateLimiter and playerRateLimiter enforce the rate limits of the workflow, if any.
+	copyRateLimiter   *workflowRateLimiter
+	playerRateLimiter *workflowRateLimiter
+	// scheduleWindow is the time-of-day window during which the workflow copies and applies rows.
+	// It is nil if the workflow can run at any time.
+	scheduleWindow *timer.ScheduleWindow
 }
 
 // newVReplicator creates a new vreplicator. The valid fields from the source are:
@@ -159,6 +166,11 @@ func newVReplicator(id int32, source *binlogdatapb.BinlogSource, sourceVStreamer
 		log.Warn(fmt.Sprintf("The supplied value for vreplication-heartbeat-update-interval:%d seconds is larger than the maximum allowed:%d seconds, vreplication will fallback to %d", workflowConfig.HeartbeatUpdateInterval, vreplicationMinimumHeartbeatUpdateInterval, vreplicationMinimumHeartbeatUpdateInterval))
 	}
 	vttablet.InitVReplicationConfigDefaults()
+	scheduleWindow, err := timer.ParseScheduleWindow(workflowConfig.ScheduleWindow)
+	if err != nil {
+		// The schedule window is validated when the workflow configuration is loaded, so this is not expected.
+		log.Error(fmt.Sprintf("Ignoring the invalid vreplication-schedule-window of workflow %d: %v", id, err))
+	}
 	vr := &vreplicator{
 		vre:             vre,
 		id:              id,
@@ -168,6 +180,10 @@ func newVReplicator(id int32, source *binlogdatapb.BinlogSource, sourceVStreamer
 		dbClient:        newVDBClient(dbClient, stats, workflowConfig.RelayLogMaxItems),
 		mysqld:          mysqld,
 		workflowConfig:  workflowConfig,
+
+		copyRateLimiter:   newWorkflowRateLimiter(workflowConfig.CopyMaxRowsPerSecond, workflowConfig.CopyMaxBytesPerSecond),
+		playerRateLimiter: newWorkflowRateLimiter(workflowConfig.PlayerMaxRowsPerSecond, workflowConfig.PlayerMaxBytesPerSecond),
+		scheduleWindow:    scheduleWindow,
 	}
 	vr.setExistingRowsCopied()
 	return vr
@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+const (
+	// scheduleWindowCheckInterval is the longest time a component waits for the schedule window of the
+	// workflow to open before checking again, so that it can still update its heartbeat and lag.
+	scheduleWindowCheckInterval = 5 * time.Second
+
+	rateLimitedReason           = "workflow rate limit reached"
+	outsideScheduleWindowReason = "outside of the workflow schedule window"
+)
+
+// workflowRateLimiter limits the rows and bytes per second copied or applied by a workflow, as configured
+// in the workflow options. A nil workflowRateLimiter does not limit anything.
+type workflowRateLimiter struct {
+	rows  *rate.Limiter
+	bytes *rate.Limiter
+}
+
+// newWorkflowRateLimiter returns a limiter for the given limits, 0 meaning unlimited. It returns nil if
+// neither is set.
+func newWorkflowRateLimiter(maxRowsPerSecond, maxBytesPerSecond int64) *workflowRateLimiter {
+	if maxRowsPerSecond <= 0 && maxBytesPerSecond <= 0 {
+		return nil
+	}
+	newLimiter := func(limit int64) *rate.Limiter {
+		if limit <= 0 {
+			return nil
+		}
+		// Allow a burst of one second worth of work, so that a batch is not split needlessly.
+		return rate.NewLimiter(rate.Limit(limit), int(limit))
+	}
+	return &workflowRateLimiter{
+		rows:  newLimiter(maxRowsPerSecond),
+		bytes: newLimiter(maxBytesPerSecond),
+	}
+}
+
+// wait blocks until the given rows and bytes can be processed without exceeding the limits. It returns
+// true if it had to wait.
+func (l *workflowRateLimiter) wait(ctx context.Context, rows, bytes int64) (bool, error) {
+	if l == nil {
+		return false, nil
+	}
+	waitedForRows, err := waitN(ctx, l.rows, rows)
+	if err != nil {
+		return false, err
+	}
+	waitedForBytes, err := waitN(ctx, l.bytes, bytes)
+	if err != nil {
+		return false, err
+	}
+	return waitedForRows || waitedForBytes, nil
+}
+
+// waitN waits for n tokens of the limiter, in chunks of at most its burst size. It returns true if it had
+// to wait.
+func waitN(ctx context.Context, limiter *rate.Limiter, n int64) (waited bool, err error) {
+	if limiter == nil {
+		return false, nil
+	}
+	burst := int64(limiter.Burst())
+	for n > 0 {
+		chunk := min(n, burst)
+		reservation := limiter.ReserveN(time.Now(), int(chunk))
+		if delay := reservation.Delay(); delay > 0 {
+			waited = true
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				reservation.Cancel()
+				return waited, ctx.Err()
+			case <-timer.C:
+			}
+		}
+		n -= chunk
+	}
+	return waited, nil
+}
+
+// waitForRateLimit waits until the given rows and bytes can be processed by the given workflow component,
+// and records the time it was throttled if it had to wait.
+func (vr *vreplicator) waitForRateLimit(ctx context.Context, limiter *workflowRateLimiter, appName throttlerapp.Name, rows, bytes int64) error {
+	waited, err := limiter.wait(ctx, rows, bytes)
+	if err != nil {
+		return err
+	}
+	if waited {
+		_ = vr.updateTimeThrottled(appName, rateLimitedReason)
+	}
+	return nil
+}
+
+// scheduleWindowOpenOrWait returns true if the schedule window of the workflow is open. Otherwise it records
+// the time the given workflow component was throttled, waits for the window to open for at most
+// scheduleWindowCheckInterval, and returns false. Callers are expected to check again.
+func (vr *vreplicator) scheduleWindowOpenOrWait(ctx context.Context, appName throttlerapp.Name) bool {
+	untilOpen := vr.scheduleWindow.UntilOpen(time.Now())
+	if untilOpen == 0 {
+		return true
+	}
+	_ = vr.updateTimeThrottled(appName, outsideScheduleWindowReason)
+	timer := time.NewTimer(min(untilOpen, scheduleWindowCheckInterval))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	return false
+}
+
+// rowChangesSize returns the number of row changes in the given events, and the size of their row data.
+func rowChangesSize(events [][]*binlogdatapb.VEvent) (rows, bytes int64) {
+	for _, evs := range events {
+		for _, event := range evs {
+			if event.Type != binlogdatapb.VEventType_ROW || event.RowEvent == nil {
+				continue
+			}
+			for _, change := range event.RowEvent.RowChanges {
+				rows++
+				bytes += int64(len(change.Before.GetValues()) + len(change.After.GetValues()))
+			}
+		}
+	}
+	return rows, bytes
+}
+
+// rowsSize returns the size of the row data of the given rows.
+func rowsSize(rows []*querypb.Row) (bytes int64) {
+	for _, row := range rows {
+		bytes += int64(len(row.Values))
+	}
+	return bytes
+}
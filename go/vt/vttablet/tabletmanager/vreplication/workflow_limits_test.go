@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestWorkflowRateLimiter(t *testing.T) {
+	require.Nil(t, newWorkflowRateLimiter(0, 0))
+	var unlimited *workflowRateLimiter
+	waited, err := unlimited.wait(t.Context(), 1000, 1000)
+	require.NoError(t, err)
+	assert.False(t, waited)
+
+	limiter := newWorkflowRateLimiter(100, 0)
+	require.NotNil(t, limiter)
+	assert.Nil(t, limiter.bytes)
+
+	// The first second worth of rows is allowed right away.
+	waited, err = limiter.wait(t.Context(), 100, 1<<20)
+	require.NoError(t, err)
+	assert.False(t, waited)
+
+	start := time.Now()
+	waited, err = limiter.wait(t.Context(), 20, 0)
+	require.NoError(t, err)
+	assert.True(t, waited)
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+
+	// Batches larger than the limit are waited for in chunks.
+	limiter = newWorkflowRateLimiter(0, 10)
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	_, err = limiter.wait(ctx, 0, 100)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRowChangesSize(t *testing.T) {
+	row := func(values string) *querypb.Row {
+		return &querypb.Row{Lengths: []int64{int64(len(values))}, Values: []byte(values)}
+	}
+	events := [][]*binlogdatapb.VEvent{
+		{
+			{Type: binlogdatapb.VEventType_BEGIN},
+			{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{
+				TableName: "t1",
+				RowChanges: []*binlogdatapb.RowChange{
+					{After: row("abc")},
+					{Before: row("abc"), After: row("abcd")},
+				},
+			}},
+			{Type: binlogdatapb.VEventType_COMMIT},
+		},
+		{
+			{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{
+				TableName:  "t2",
+				RowChanges: []*binlogdatapb.RowChange{{Before: row("ab")}},
+			}},
+			{Type: binlogdatapb.VEventType_HEARTBEAT},
+		},
+	}
+	rows, bytes := rowChangesSize(events)
+	assert.EqualValues(t, 3, rows)
+	assert.EqualValues(t, 12, bytes)
+
+	assert.EqualValues(t, 7, rowsSize([]*querypb.Row{row("abc"), row("abcd")}))
+}
@@ -154,9 +154,11 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 		qre.tsv.Stats().ResultHistogram.Add(int64(len(reply.Rows)))
 	}(time.Now())
 
-	if err = qre.checkPermissions(); err != nil {
+	release, err := qre.checkPermissions()
+	if err != nil {
 		return nil, err
 	}
+	defer release()
 
 	if reqThrottledErr := qre.tsv.queryThrottler.Throttle(qre.ctx, qre.targetTabletType, qre.plan.FullQuery, qre.connID, qre.options); reqThrottledErr != nil {
 		return nil, reqThrottledErr
@@ -349,9 +351,11 @@ func (qre *QueryExecutor) Stream(callback StreamCallback) error {
 		qre.recordUserQuery("Stream", int64(time.Since(start)))
 	}(time.Now())
 
-	if err := qre.checkPermissions(); err != nil {
+	release, err := qre.checkPermissions()
+	if err != nil {
 		return err
 	}
+	defer release()
 
 	if reqThrottledErr := qre.tsv.queryThrottler.Throttle(qre.ctx, qre.targetTabletType, qre.plan.FullQuery, qre.connID, qre.options); reqThrottledErr != nil {
 		return reqThrottledErr
@@ -443,9 +447,11 @@ func (qre *QueryExecutor) MessageStream(callback StreamCallback) error {
 		qre.recordUserQuery("MessageStream", int64(time.Since(start)))
 	}(time.Now())
 
-	if err := qre.checkPermissions(); err != nil {
+	release, err := qre.checkPermissions()
+	if err != nil {
 		return err
 	}
+	defer release()
 
 	done, err := qre.tsv.messager.Subscribe(qre.ctx, qre.plan.TableName().String(), func(r *sqltypes.Result) error {
 		select {
@@ -463,11 +469,13 @@ func (qre *QueryExecutor) MessageStream(callback StreamCallback) error {
 }
 
 // checkPermissions returns an error if the query does not pass all checks
-// (denied query, table ACL).
-func (qre *QueryExecutor) checkPermissions() error {
+// (denied query, table ACL). If the query was admitted by a limit rule, the
+// returned function must be called once the query is done.
+func (qre *QueryExecutor) checkPermissions() (release func(), err error) {
+	release = func() {}
 	// Skip permissions check if the context is local.
 	if tabletenv.IsLocalContext(qre.ctx) {
-		return nil
+		return release, nil
 	}
 
 	// Check if the query relates to a table that is in the denylist.
@@ -479,16 +487,17 @@ func (qre *QueryExecutor) checkPermissions() error {
 		username = ci.Username()
 	}
 
-	action, ruleCancelCtx, timeout, desc := qre.plan.Rules.GetAction(remoteAddr, username, qre.bindVars, qre.marginComments)
+	qr := qre.plan.Rules.GetRule(remoteAddr, username, qre.bindVars, qre.marginComments)
+	action, ruleCancelCtx, timeout, desc := qr.ActionInfo()
 
 	bufferingTimeoutCtx, cancel := context.WithTimeout(qre.ctx, timeout) // aborts buffering at given timeout
 	defer cancel()
 
 	switch action {
 	case rules.QRFail:
-		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "disallowed due to rule: %s", desc)
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "disallowed due to rule: %s", desc)
 	case rules.QRFailRetry:
-		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "disallowed due to rule: %s", desc)
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "disallowed due to rule: %s", desc)
 	case rules.QRBuffer:
 		if ruleCancelCtx != nil {
 			// We buffer up to some timeout. The timeout is determined by ctx.Done().
@@ -498,13 +507,31 @@ func (qre *QueryExecutor) checkPermissions() error {
 				// good! We have buffered the query, and buffering is completed
 			case <-bufferingTimeoutCtx.Done():
 				// Sorry, timeout while waiting for buffering to complete
-				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "buffer timeout after %v in rule: %s", timeout, desc)
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "buffer timeout after %v in rule: %s", timeout, desc)
 			}
 		}
 	default:
 		// no rules against this query. Good to proceed
 	}
 
+	if err := qre.checkACL(username); err != nil {
+		return nil, err
+	}
+
+	switch action {
+	case rules.QRConcurrencyLimit, rules.QRRateLimit:
+		// Queries that are denied by the ACL don't take a slot of the limit.
+		release, err = qr.Limiter().Acquire(qre.ctx)
+		if err != nil {
+			return nil, vterrors.Errorf(vterrors.Code(err), "%s in rule: %s", err.Error(), desc)
+		}
+	}
+	return release, nil
+}
+
+// checkACL returns an error if the caller is not allowed to access the tables
+// of the query.
+func (qre *QueryExecutor) checkACL(username string) error {
 	// Skip the ACL check if the connecting user is an exempted superuser.
 	if qre.tsv.qe.exemptACL != nil && qre.tsv.qe.exemptACL.IsMember(&querypb.VTGateCallerID{Username: username}) {
 		qre.tsv.qe.tableaclExemptCount.Add(1)
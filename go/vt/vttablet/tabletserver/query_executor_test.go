@@ -1428,6 +1428,56 @@ func TestQueryExecutorDenyListQRRetry(t *testing.T) {
 	require.Equalf(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err), "tsv.qe.queryRuleSources.SetRules: %v, want %v", vterrors.Code(err), vtrpcpb.Code_FAILED_PRECONDITION)
 }
 
+func TestQueryExecutorConcurrencyLimitRule(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	query := "select * from test_table where `name` = 1 limit 1000"
+	db.AddQuery(query, &sqltypes.Result{
+		Fields: getTestTableFields(),
+	})
+
+	limitRule := rules.NewQueryRule("limit selects", "limit selects", rules.QRContinue)
+	limitRule.SetUserCond("u2")
+	limitRule.AddTableCond("test_table")
+	require.NoError(t, limitRule.SetConcurrencyLimit(1, 0))
+
+	rulesName := "concurrencyLimitRules"
+	qrs := rules.New()
+	qrs.Add(limitRule)
+
+	callInfo := &fakecallinfo.FakeCallInfo{
+		Remote: "127.0.0.1",
+		User:   "u2",
+	}
+	ctx := callinfo.NewContext(t.Context(), callInfo)
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+	tsv.qe.queryRuleSources.RegisterSource(rulesName)
+	defer tsv.qe.queryRuleSources.UnRegisterSource(rulesName)
+	require.NoError(t, tsv.qe.queryRuleSources.SetRules(rulesName, qrs))
+	defer tsv.StopService()
+
+	// the query is admitted and gives its slot back when it's done
+	qre := newTestQueryExecutor(ctx, tsv, query, 0)
+	_, err := qre.Execute()
+	require.NoError(t, err)
+	assert.Equal(t, rules.LimiterStats{Admitted: 1}, limitRule.Limiter().Stats())
+
+	// the query is rejected while another one holds the only slot
+	release, err := limitRule.Limiter().Acquire(ctx)
+	require.NoError(t, err)
+	qre = newTestQueryExecutor(ctx, tsv, query, 0)
+	_, err = qre.Execute()
+	require.EqualError(t, err, "concurrency limit of 1 exceeded in rule: limit selects")
+	require.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	release()
+
+	qre = newTestQueryExecutor(ctx, tsv, query, 0)
+	_, err = qre.Execute()
+	require.NoError(t, err)
+	assert.Equal(t, rules.LimiterStats{Admitted: 3, Rejected: 1}, limitRule.Limiter().Stats())
+}
+
 func TestReplaceSchemaName(t *testing.T) {
 	db := setUpQueryExecutorTest(t)
 	defer db.Close()
@@ -0,0 +1,122 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/safehtml/template"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logz"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
+)
+
+var (
+	queryrulezHeader = []byte(`<thead>
+		<tr>
+			<th>Source</th>
+			<th>Name</th>
+			<th>Description</th>
+			<th>Action</th>
+			<th>Limit</th>
+			<th>Queue Timeout</th>
+			<th>In Flight</th>
+			<th>Queued</th>
+			<th>Admitted</th>
+			<th>Rejected</th>
+		</tr>
+        </thead>
+	`)
+	queryrulezTmpl = template.Must(template.New("example").Parse(`
+		<tr class="{{.Color}}">
+			<td>{{.Source}}</td>
+			<td>{{.Name}}</td>
+			<td>{{.Description}}</td>
+			<td>{{.Action}}</td>
+			<td>{{.Limit}}</td>
+			<td>{{.QueueTimeout}}</td>
+			<td>{{.InFlight}}</td>
+			<td>{{.Queued}}</td>
+			<td>{{.Admitted}}</td>
+			<td>{{.Rejected}}</td>
+		</tr>
+	`))
+)
+
+// queryrulezRow is used for rendering the state of a limit rule
+// using go's template.
+type queryrulezRow struct {
+	Source       string
+	Name         string
+	Description  string
+	Action       string
+	Limit        string
+	QueueTimeout string
+	rules.LimiterStats
+	Color string
+}
+
+// queryrulezHandler shows the query rules that limit the concurrency or the
+// rate of the queries they match, along with how many queries they admitted
+// and rejected.
+func queryrulezHandler(ruleSources *rules.Map, w http.ResponseWriter, r *http.Request) {
+	if err := acl.CheckAccessHTTP(r, acl.DEBUGGING); err != nil {
+		acl.SendError(w, err)
+		return
+	}
+	logz.StartHTMLTable(w)
+	defer logz.EndHTMLTable(w)
+	w.Write(queryrulezHeader)
+
+	ruleSources.ForEachRule(func(ruleSource string, qr *rules.Rule) {
+		limiter := qr.Limiter()
+		if limiter == nil {
+			return
+		}
+		action, _, _, _ := qr.ActionInfo()
+		row := &queryrulezRow{
+			Source:       ruleSource,
+			Name:         qr.Name,
+			Description:  qr.Description,
+			Action:       action.String(),
+			QueueTimeout: limiter.QueueTimeout().String(),
+			LimiterStats: limiter.Stats(),
+		}
+		if limiter.MaxConcurrency() != 0 {
+			row.Limit = fmt.Sprintf("%d concurrent", limiter.MaxConcurrency())
+		} else {
+			row.Limit = fmt.Sprintf("%v QPS", limiter.MaxQPS())
+			if limiter.Burst() != 0 {
+				row.Limit += fmt.Sprintf(", burst %d", limiter.Burst())
+			}
+		}
+		switch {
+		case row.Rejected > 0:
+			row.Color = "high"
+		case row.Queued > 0:
+			row.Color = "medium"
+		default:
+			row.Color = "low"
+		}
+		if err := queryrulezTmpl.Execute(w, row); err != nil {
+			log.Error(fmt.Sprintf("queryrulez: couldn't execute template: %v", err))
+		}
+	})
+}
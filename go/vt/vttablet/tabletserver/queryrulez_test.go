@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
+)
+
+func TestQueryrulezHandler(t *testing.T) {
+	qrs := rules.New()
+	err := qrs.UnmarshalJSON([]byte(`[{
+		"Description": "deny bad",
+		"Name": "deny_bad",
+		"TableNames": ["bad"],
+		"Action": "FAIL"
+	},{
+		"Description": "limit reports",
+		"Name": "limit_reports",
+		"User": "reports",
+		"Action": "CONCURRENCY_LIMIT",
+		"MaxConcurrency": 1
+	},{
+		"Description": "limit hot",
+		"Name": "limit_hot",
+		"TableNames": ["hot"],
+		"Action": "RATE_LIMIT",
+		"MaxQPS": 10,
+		"Burst": 20,
+		"QueueTimeout": "50ms"
+	}]`))
+	require.NoError(t, err)
+
+	ruleSources := rules.NewMap()
+	ruleSources.RegisterSource("CUSTOM_RULES")
+	require.NoError(t, ruleSources.SetRules("CUSTOM_RULES", qrs))
+
+	// the copies the map holds share the limiter with qrs
+	limiter := qrs.Find("limit_reports").Limiter()
+	release, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+	defer release()
+	_, err = limiter.Acquire(context.Background())
+	require.Error(t, err)
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/queryrulez", nil)
+	queryrulezHandler(ruleSources, resp, req)
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.NotContains(t, string(body), "deny_bad")
+	checkQueryrulezHasRule(t, []string{
+		`<tr class="high">`,
+		`<td>CUSTOM_RULES</td>`,
+		`<td>limit_reports</td>`,
+		`<td>limit reports</td>`,
+		`<td>CONCURRENCY_LIMIT</td>`,
+		`<td>1 concurrent</td>`,
+		`<td>0s</td>`,
+		`<td>1</td>`,
+		`<td>0</td>`,
+		`<td>1</td>`,
+		`<td>1</td>`,
+	}, body)
+	checkQueryrulezHasRule(t, []string{
+		`<tr class="low">`,
+		`<td>CUSTOM_RULES</td>`,
+		`<td>limit_hot</td>`,
+		`<td>limit hot</td>`,
+		`<td>RATE_LIMIT</td>`,
+		`<td>10 QPS, burst 20</td>`,
+		`<td>50ms</td>`,
+		`<td>0</td>`,
+		`<td>0</td>`,
+		`<td>0</td>`,
+		`<td>0</td>`,
+	}, body)
+}
+
+func checkQueryrulezHasRule(t *testing.T, rulePattern []string, page []byte) {
+	matcher := regexp.MustCompile(strings.Join(rulePattern, `\s*`))
+	require.Truef(t, matcher.Match(page), "queryrulez page does not contain\npattern:\n%v\npage:\n%s", strings.Join(rulePattern, `\s*`), string(page))
+}
@@ -25,6 +25,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"google.golang.org/protobuf/proto"
+
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -48,11 +50,14 @@ const (
 	queryThrottlerAppName = "QueryThrottler"
 	// defaultPriority is the default priority value when none is specified
 	defaultPriority = 100 // sqlparser.MaxPriorityValue
+	// delayCheckInterval is how often a delayed query is evaluated again
+	delayCheckInterval = 250 * time.Millisecond
 )
 
 type Stats struct {
 	requestsTotal     *stats.CountersWithMultiLabels
 	requestsThrottled *stats.CountersWithMultiLabels
+	requestsDelayed   *stats.CountersWithMultiLabels
 	totalLatency      *servenv.MultiTimingsWrapper
 	evaluateLatency   *servenv.MultiTimingsWrapper
 }
@@ -95,6 +100,7 @@ func NewQueryThrottler(ctx context.Context, throttler *throttle.Throttler, env t
 		stats: Stats{
 			requestsTotal:     env.Exporter().NewCountersWithMultiLabels(queryThrottlerAppName+"Requests", "query throttler requests", []string{"Strategy", "Workload", "Priority"}),
 			requestsThrottled: env.Exporter().NewCountersWithMultiLabels(queryThrottlerAppName+"Throttled", "query throttler requests throttled", []string{"Strategy", "Workload", "Priority", "MetricName", "MetricValue", "DryRun"}),
+			requestsDelayed:   env.Exporter().NewCountersWithMultiLabels(queryThrottlerAppName+"Delayed", "query throttler requests delayed, by whether they were eventually admitted", []string{"Strategy", "Workload", "Priority", "Admitted"}),
 			totalLatency:      env.Exporter().NewMultiTimings(queryThrottlerAppName+"TotalLatencyNs", "Total time each request takes in query throttling including evaluation, metric checks, and other overhead (nanoseconds)", []string{"Strategy", "Workload", "Priority"}),
 			evaluateLatency:   env.Exporter().NewMultiTimings(queryThrottlerAppName+"EvaluateLatencyNs", "Time each request takes to make the throttling decision (nanoseconds)", []string{"Strategy", "Workload", "Priority"}),
 		},
@@ -204,10 +210,52 @@ func (qt *QueryThrottler) Throttle(ctx context.Context, tabletType topodatapb.Ta
 		return nil
 	}
 
+	// The strategy may ask for the query to be held back rather than rejected right away.
+	if decision.MaxDelay > 0 {
+		admitted := qt.delay(ctx, tStrategy, tabletType, parsedQuery, transactionID, attrs, decision)
+		qt.stats.requestsDelayed.Add([]string{strategyName, workload, priorityStr, strconv.FormatBool(admitted)}, 1)
+		if admitted {
+			return nil
+		}
+	}
+
 	// Normal throttling: return an error to reject the query
 	return vterrors.New(vtrpcpb.Code_RESOURCE_EXHAUSTED, decision.Message)
 }
 
+// delay holds the query back for up to the decision's MaxDelay, checking it again every delayCheckInterval.
+// It returns true as soon as the decision no longer applies, and false if it still does once MaxDelay
+// expires or the context is done. Strategies implementing registry.DelayRecheckable only re-check the
+// cause of the decision, such that a probabilistic decision is not made again on every check.
+func (qt *QueryThrottler) delay(ctx context.Context, strategy registry.ThrottlingStrategyHandler, tabletType topodatapb.TabletType, parsedQuery *sqlparser.ParsedQuery, transactionID int64, attrs registry.QueryAttributes, decision registry.ThrottleDecision) bool {
+	stillThrottled := func() bool {
+		return strategy.Evaluate(ctx, tabletType, parsedQuery, transactionID, attrs).Throttle
+	}
+	if recheckable, ok := strategy.(registry.DelayRecheckable); ok {
+		stillThrottled = func() bool {
+			return recheckable.StillThrottled(decision)
+		}
+	}
+
+	timer := time.NewTimer(decision.MaxDelay)
+	defer timer.Stop()
+	ticker := time.NewTicker(delayCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
+			return false
+		case <-ticker.C:
+			if !stillThrottled() {
+				return true
+			}
+		}
+	}
+}
+
 // startSrvKeyspaceWatch starts watching the SrvKeyspace for event-driven config updates.
 // This method performs two critical operations:
 //  1. Initial Configuration Load (with retry):
@@ -355,6 +403,9 @@ func (qt *QueryThrottler) HandleConfigUpdate(srvks *topodatapb.SrvKeyspace, err
 	if needsStrategyChange {
 		// Create the new strategy (doesn't need lock)
 		newStrategy = selectThrottlingStrategy(newCfg, qt.throttleClient, qt.tabletConfig)
+	} else if updatable, ok := oldStrategyInstance.(registry.ConfigUpdatable); ok {
+		// Same strategy, let it pick up its new strategy-specific config in place.
+		updatable.UpdateConfig(newCfg)
 	}
 
 	// Acquire write lock only for the actual swap operation.
@@ -393,8 +444,7 @@ func selectThrottlingStrategy(cfg *querythrottlerpb.Config, client *throttle.Cli
 }
 
 // isConfigUpdateRequired checks if the new config is different from the old config.
-// This only checks for enabled, strategy name, and dry run because the strategy itself will update the strategy-specific config
-// during runtime by having a separate watcher similar to the one used in QueryThrottler.
+// Changes to the strategy-specific config are applied to strategies implementing registry.ConfigUpdatable.
 func isConfigUpdateRequired(oldCfg, newCfg *querythrottlerpb.Config) bool {
 	if oldCfg.GetEnabled() != newCfg.GetEnabled() {
 		return true
@@ -408,5 +458,9 @@ func isConfigUpdateRequired(oldCfg, newCfg *querythrottlerpb.Config) bool {
 		return true
 	}
 
+	if !proto.Equal(oldCfg.GetTabletStrategyConfig(), newCfg.GetTabletStrategyConfig()) {
+		return true
+	}
+
 	return false
 }
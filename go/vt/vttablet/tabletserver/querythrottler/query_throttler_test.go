@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -39,6 +40,7 @@ import (
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
@@ -55,6 +57,11 @@ func TestSelectThrottlingStrategy(t *testing.T) {
 			giveThrottlingStrategy: querythrottlerpb.ThrottlingStrategy_UNKNOWN,
 			expectedType:           &registry.NoOpStrategy{},
 		},
+		{
+			name:                   "Tablet throttler strategy",
+			giveThrottlingStrategy: querythrottlerpb.ThrottlingStrategy_TABLET_THROTTLER,
+			expectedType:           &TabletThrottlerStrategy{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -592,6 +599,168 @@ func TestQueryThrottler_HandleConfigUpdate__NoChange(t *testing.T) {
 	qt.mu.RUnlock()
 }
 
+// TestQueryThrottler_HandleConfigUpdate__StrategyConfigUpdate tests that a strategy-specific config change is applied
+// to the running strategy without recreating it.
+func TestQueryThrottler_HandleConfigUpdate__StrategyConfigUpdate(t *testing.T) {
+	oldCfg := &querythrottlerpb.Config{Enabled: true, Strategy: querythrottlerpb.ThrottlingStrategy_TABLET_THROTTLER}
+	strategy := selectThrottlingStrategy(oldCfg, &throttle.Client{}, &tabletenv.TabletConfig{})
+	require.IsType(t, &TabletThrottlerStrategy{}, strategy)
+
+	qt := &QueryThrottler{
+		ctx:                     t.Context(),
+		cfg:                     oldCfg,
+		strategyHandlerInstance: strategy,
+		tabletConfig:            &tabletenv.TabletConfig{},
+	}
+
+	srvks := createTestSrvKeyspace(true, querythrottlerpb.ThrottlingStrategy_TABLET_THROTTLER, false)
+	srvks.QueryThrottlerConfig.TabletStrategyConfig = &querythrottlerpb.TabletStrategyConfig{MaxDelayMs: 100}
+	require.True(t, qt.HandleConfigUpdate(srvks, nil))
+
+	require.Same(t, strategy, qt.strategyHandlerInstance, "strategy should not be recreated")
+	require.EqualValues(t, 100, strategy.(*TabletThrottlerStrategy).cfg.Load().GetMaxDelayMs())
+}
+
+// flippingThrottlingStrategy throttles the first n evaluations, and allows all the following ones.
+type flippingThrottlingStrategy struct {
+	mockThrottlingStrategy
+	mu          sync.Mutex
+	evaluations int
+	n           int
+}
+
+func (f *flippingThrottlingStrategy) Evaluate(ctx context.Context, targetTabletType topodatapb.TabletType, parsedQuery *sqlparser.ParsedQuery, transactionID int64, attrs registry.QueryAttributes) registry.ThrottleDecision {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.evaluations++
+	if f.evaluations > f.n {
+		return registry.ThrottleDecision{}
+	}
+	return f.decision
+}
+
+// TestQueryThrottler_Delay tests that queries are held back, rather than rejected, when the decision asks for a delay.
+func TestQueryThrottler_Delay(t *testing.T) {
+	tests := []struct {
+		name          string
+		throttleTimes int
+		maxDelay      time.Duration
+		dryRun        bool
+		expectError   bool
+		expectDelayed map[string]int64
+	}{
+		{
+			name:          "admitted after delay",
+			throttleTimes: 2,
+			maxDelay:      5 * time.Second,
+			expectDelayed: map[string]int64{"true": 1},
+		},
+		{
+			name:          "rejected once max delay expires",
+			throttleTimes: 100,
+			maxDelay:      300 * time.Millisecond,
+			expectError:   true,
+			expectDelayed: map[string]int64{"false": 1},
+		},
+		{
+			name:          "dry-run never delays",
+			throttleTimes: 100,
+			maxDelay:      time.Hour,
+			dryRun:        true,
+			expectDelayed: map[string]int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := tabletenv.NewEnv(vtenv.NewTestEnv(), &tabletenv.TabletConfig{}, "TestThrottler")
+			strategy := &flippingThrottlingStrategy{
+				mockThrottlingStrategy: mockThrottlingStrategy{
+					decision: registry.ThrottleDecision{Throttle: true, Message: "throttled", MaxDelay: tt.maxDelay},
+				},
+				n: tt.throttleTimes,
+			}
+			iqt := &QueryThrottler{
+				ctx: t.Context(),
+				cfg: &querythrottlerpb.Config{Enabled: true, DryRun: tt.dryRun},
+				env: env,
+				stats: Stats{
+					requestsTotal:     env.Exporter().NewCountersWithMultiLabels(queryThrottlerAppName+"Requests", "TestThrottler requests", []string{"Strategy", "Workload", "Priority"}),
+					requestsThrottled: env.Exporter().NewCountersWithMultiLabels(queryThrottlerAppName+"Throttled", "TestThrottler throttled", []string{"Strategy", "Workload", "Priority", "MetricName", "MetricValue", "DryRun"}),
+					requestsDelayed:   env.Exporter().NewCountersWithMultiLabels(queryThrottlerAppName+"Delayed", "TestThrottler delayed", []string{"Strategy", "Workload", "Priority", "Admitted"}),
+					totalLatency:      env.Exporter().NewMultiTimings(queryThrottlerAppName+"TotalLatencyMs", "Total latency of QueryThrottler.Throttle in milliseconds", []string{"Strategy", "Workload", "Priority"}),
+					evaluateLatency:   env.Exporter().NewMultiTimings(queryThrottlerAppName+"EvaluateLatencyMs", "Latency from Throttle entry to completion of Evaluate in milliseconds", []string{"Strategy", "Workload", "Priority"}),
+				},
+				strategyHandlerInstance: strategy,
+			}
+			iqt.stats.requestsDelayed.ResetAll()
+
+			originalLogWarn := log.Warn
+			defer func() {
+				log.Warn = originalLogWarn
+			}()
+			log.Warn = (&testLogCapture{}).captureLog
+
+			err := iqt.Throttle(t.Context(), topodatapb.TabletType_PRIMARY, &sqlparser.ParsedQuery{Query: "SELECT 1"}, 0, nil)
+			if tt.expectError {
+				require.EqualError(t, err, "throttled")
+			} else {
+				require.NoError(t, err)
+			}
+
+			delayed := stats.CounterForDimension(iqt.stats.requestsDelayed, "Admitted")
+			require.Equal(t, tt.expectDelayed, delayed.Counts())
+		})
+	}
+}
+
+// TestQueryThrottler_DelayWhileMetricStaysHigh tests that a delayed query is rejected once its max delay
+// expires if the metric stays above the threshold, even though the throttle percentage is below 100%.
+func TestQueryThrottler_DelayWhileMetricStaysHigh(t *testing.T) {
+	env := tabletenv.NewEnv(vtenv.NewTestEnv(), &tabletenv.TabletConfig{}, "TestThrottler")
+	strategy := newTestTabletThrottlerStrategy(t, &querythrottlerpb.TabletStrategyConfig{
+		TabletRules: map[string]*querythrottlerpb.StatementRuleSet{
+			"*": {StatementRules: map[string]*querythrottlerpb.MetricRuleSet{"*": thresholdRules("lag", 10, 50)}},
+		},
+		Action:     querythrottlerpb.ThrottleAction_DELAY,
+		MaxDelayMs: 600,
+	}, map[string]float64{"lag": 20})
+	iqt := &QueryThrottler{
+		ctx: t.Context(),
+		cfg: &querythrottlerpb.Config{Enabled: true},
+		env: env,
+		stats: Stats{
+			requestsTotal:     env.Exporter().NewCountersWithMultiLabels(queryThrottlerAppName+"Requests", "TestThrottler requests", []string{"Strategy", "Workload", "Priority"}),
+			requestsThrottled: env.Exporter().NewCountersWithMultiLabels(queryThrottlerAppName+"Throttled", "TestThrottler throttled", []string{"Strategy", "Workload", "Priority", "MetricName", "MetricValue", "DryRun"}),
+			requestsDelayed:   env.Exporter().NewCountersWithMultiLabels(queryThrottlerAppName+"Delayed", "TestThrottler delayed", []string{"Strategy", "Workload", "Priority", "Admitted"}),
+			totalLatency:      env.Exporter().NewMultiTimings(queryThrottlerAppName+"TotalLatencyMs", "Total latency of QueryThrottler.Throttle in milliseconds", []string{"Strategy", "Workload", "Priority"}),
+			evaluateLatency:   env.Exporter().NewMultiTimings(queryThrottlerAppName+"EvaluateLatencyMs", "Latency from Throttle entry to completion of Evaluate in milliseconds", []string{"Strategy", "Workload", "Priority"}),
+		},
+		strategyHandlerInstance: strategy,
+	}
+	iqt.stats.requestsDelayed.ResetAll()
+
+	// Each query has a 50% chance of being delayed. A delayed query checks the metric a couple of
+	// times before its max delay expires, and must not be admitted by any of these checks.
+	const queries = 20
+	var wg sync.WaitGroup
+	var rejected atomic.Int64
+	for range queries {
+		wg.Go(func() {
+			err := iqt.Throttle(t.Context(), topodatapb.TabletType_PRIMARY, &sqlparser.ParsedQuery{Query: "SELECT 1"}, 0, nil)
+			if err != nil {
+				assert.ErrorContains(t, err, "Query throttled")
+				rejected.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+
+	delayed := stats.CounterForDimension(iqt.stats.requestsDelayed, "Admitted").Counts()
+	require.NotZero(t, rejected.Load())
+	require.Equal(t, map[string]int64{"false": rejected.Load()}, delayed)
+}
+
 // TestIsConfigUpdateRequired tests the isConfigUpdateRequired function.
 func TestIsConfigUpdateRequired(t *testing.T) {
 	tests := []struct {
@@ -726,6 +895,19 @@ func TestIsConfigUpdateRequired(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name: "Tablet strategy config changed",
+			oldCfg: &querythrottlerpb.Config{
+				Enabled:  true,
+				Strategy: querythrottlerpb.ThrottlingStrategy_TABLET_THROTTLER,
+			},
+			newCfg: &querythrottlerpb.Config{
+				Enabled:              true,
+				Strategy:             querythrottlerpb.ThrottlingStrategy_TABLET_THROTTLER,
+				TabletStrategyConfig: &querythrottlerpb.TabletStrategyConfig{MaxDelayMs: 100},
+			},
+			expected: true,
+		},
 		{
 			name: "All fields false/default - no change",
 			oldCfg: &querythrottlerpb.Config{
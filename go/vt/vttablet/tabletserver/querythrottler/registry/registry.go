@@ -40,7 +40,9 @@ func Register(name querythrottlerpb.ThrottlingStrategy, factory StrategyFactory)
 	}
 
 	factories[name] = factory
-	log.Info(fmt.Sprintf("Registered throttling strategy: %s", name))
+	// Strategies register from init(), before logging is configured, so keep this
+	// out of the default output.
+	log.V(1).Info(fmt.Sprintf("Registered throttling strategy: %s", name))
 }
 
 // Get retrieves a strategy factory by name.
@@ -47,3 +47,18 @@ type ThrottlingStrategyHandler interface {
 	// GetStrategyName returns the name of the strategy.
 	GetStrategyName() string
 }
+
+// ConfigUpdatable is implemented by strategies that can apply changes to their strategy-specific
+// configuration at runtime, without having to be recreated.
+type ConfigUpdatable interface {
+	// UpdateConfig applies the given configuration to the running strategy.
+	UpdateConfig(cfg StrategyConfig)
+}
+
+// DelayRecheckable is implemented by strategies whose decisions can be re-checked while a query is
+// delayed. The decision to throttle a query may be probabilistic, and is made once, by Evaluate. While
+// the query is delayed, StillThrottled only checks whether the cause of that decision still holds.
+type DelayRecheckable interface {
+	// StillThrottled returns whether the given throttle decision still applies.
+	StillThrottled(decision ThrottleDecision) bool
+}
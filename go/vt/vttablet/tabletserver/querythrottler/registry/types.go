@@ -17,6 +17,8 @@ limitations under the License.
 package registry
 
 import (
+	"time"
+
 	querythrottlerpb "vitess.io/vitess/go/vt/proto/querythrottler"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
@@ -53,6 +55,10 @@ type ThrottleDecision struct {
 
 	// ThrottlePercentage contains the percentage chance this query was throttled (0.0-1.0).
 	ThrottlePercentage float64
+
+	// MaxDelay, when non-zero, asks for the query to be held back instead of being rejected right away.
+	// The query is evaluated again periodically, and is only rejected if it is still throttled after MaxDelay.
+	MaxDelay time.Duration
 }
 
 // StrategyConfig defines the configuration interface that strategy implementations
@@ -61,6 +67,7 @@ type StrategyConfig interface {
 	GetEnabled() bool
 	GetDryRun() bool
 	GetStrategy() querythrottlerpb.ThrottlingStrategy
+	GetTabletStrategyConfig() *querythrottlerpb.TabletStrategyConfig
 }
 
 // Deps holds the dependencies required by strategy factories.
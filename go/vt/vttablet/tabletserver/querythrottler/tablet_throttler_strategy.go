@@ -0,0 +1,301 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package querythrottler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+	querythrottlerpb "vitess.io/vitess/go/vt/proto/querythrottler"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/querythrottler/registry"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
+)
+
+const (
+	// anyRuleKey matches any tablet type or statement type in the rule sets.
+	anyRuleKey = "*"
+
+	// tabletThrottlerMetricsRefreshInterval is how often the strategy fetches fresh metric values
+	// from the tablet throttler.
+	tabletThrottlerMetricsRefreshInterval = time.Second
+)
+
+func init() {
+	registry.Register(querythrottlerpb.ThrottlingStrategy_TABLET_THROTTLER, tabletThrottlerStrategyFactory{})
+}
+
+var _ registry.StrategyFactory = tabletThrottlerStrategyFactory{}
+
+// tabletThrottlerStrategyFactory creates TabletThrottlerStrategy instances.
+type tabletThrottlerStrategyFactory struct{}
+
+// New is part of the registry.StrategyFactory interface.
+func (tabletThrottlerStrategyFactory) New(deps registry.Deps, cfg registry.StrategyConfig) (registry.ThrottlingStrategyHandler, error) {
+	if deps.ThrottleClient == nil {
+		return nil, errors.New("TabletThrottlerStrategy requires a throttle client")
+	}
+	s := &TabletThrottlerStrategy{
+		checkMetrics:    deps.ThrottleClient.CheckMetrics,
+		refreshInterval: tabletThrottlerMetricsRefreshInterval,
+	}
+	s.UpdateConfig(cfg)
+	return s, nil
+}
+
+var (
+	_ registry.ThrottlingStrategyHandler = (*TabletThrottlerStrategy)(nil)
+	_ registry.ConfigUpdatable           = (*TabletThrottlerStrategy)(nil)
+	_ registry.DelayRecheckable          = (*TabletThrottlerStrategy)(nil)
+)
+
+// TabletThrottlerStrategy throttles queries based on the metrics collected by the tablet throttler,
+// such as replication lag or threads_running.
+//
+// The rules are looked up by workload name, tablet type and statement type, in that order. Queries of
+// a workload that has its own rules in TabletStrategyConfig.WorkloadRules only use those rules, all other
+// queries use TabletStrategyConfig.TabletRules. For every metric in the matching rule set, the highest
+// threshold the current metric value is above determines the percentage of queries to throttle. That
+// percentage is scaled by the query priority, so that the highest priority queries (priority 0) are never
+// throttled.
+//
+// Metric values are fetched in the background, so that Evaluate never waits on the throttler.
+type TabletThrottlerStrategy struct {
+	// checkMetrics fetches the current metric values. It's overridden in tests.
+	checkMetrics func(ctx context.Context, metricNames base.MetricNames) *throttle.CheckResult
+
+	refreshInterval time.Duration
+
+	// cfg holds the current *querythrottlerpb.TabletStrategyConfig.
+	cfg atomic.Pointer[querythrottlerpb.TabletStrategyConfig]
+	// metrics holds the latest metric values, as a map[string]float64 keyed by metric name.
+	metrics atomic.Pointer[map[string]float64]
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// UpdateConfig is part of the registry.ConfigUpdatable interface.
+func (s *TabletThrottlerStrategy) UpdateConfig(cfg registry.StrategyConfig) {
+	tabletCfg := cfg.GetTabletStrategyConfig()
+	if tabletCfg == nil {
+		tabletCfg = &querythrottlerpb.TabletStrategyConfig{}
+	}
+	s.cfg.Store(tabletCfg)
+}
+
+// Start starts fetching the metric values in the background.
+func (s *TabletThrottlerStrategy) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Go(func() {
+		ticker := time.NewTicker(s.refreshInterval)
+		defer ticker.Stop()
+		for {
+			s.refreshMetrics(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// Stop stops fetching the metric values.
+func (s *TabletThrottlerStrategy) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+	s.cancel = nil
+}
+
+// GetStrategyName returns the name of the strategy.
+func (s *TabletThrottlerStrategy) GetStrategyName() string {
+	return querythrottlerpb.ThrottlingStrategy_TABLET_THROTTLER.String()
+}
+
+// refreshMetrics fetches the values of all the metrics referenced by the current config.
+func (s *TabletThrottlerStrategy) refreshMetrics(ctx context.Context) {
+	metricNames := configMetricNames(s.cfg.Load())
+	if len(metricNames) == 0 {
+		s.metrics.Store(nil)
+		return
+	}
+
+	checkResult := s.checkMetrics(ctx, metricNames)
+	metrics := make(map[string]float64, len(checkResult.Metrics))
+	for name, metricResult := range checkResult.Metrics {
+		if metricResult.Error != nil && !errors.Is(metricResult.Error, base.ErrThresholdExceeded) {
+			// The metric could not be collected, don't make decisions on a bogus value.
+			log.Warn(fmt.Sprintf("TabletThrottlerStrategy: unable to collect metric %s: %v", name, metricResult.Error))
+			continue
+		}
+		metrics[name] = metricResult.Value
+	}
+	s.metrics.Store(&metrics)
+}
+
+// Evaluate determines whether the query should be throttled based on the latest metric values.
+func (s *TabletThrottlerStrategy) Evaluate(ctx context.Context, targetTabletType topodatapb.TabletType, parsedQuery *sqlparser.ParsedQuery, transactionID int64, attrs registry.QueryAttributes) registry.ThrottleDecision {
+	cfg := s.cfg.Load()
+	metrics := s.metrics.Load()
+	if cfg == nil || metrics == nil {
+		return registry.ThrottleDecision{Message: "TabletThrottlerStrategy: no metrics available"}
+	}
+
+	statementType := anyRuleKey
+	if parsedQuery != nil {
+		statementType = sqlparser.Preview(parsedQuery.Query).String()
+	}
+	metricRules := matchMetricRules(cfg, attrs.WorkloadName, targetTabletType.String(), statementType)
+	if metricRules == nil {
+		return registry.ThrottleDecision{Message: "TabletThrottlerStrategy: no matching rules"}
+	}
+
+	// Iterate the metrics in a stable order so that the decision is deterministic.
+	metricNames := make([]string, 0, len(metricRules.GetMetricRules()))
+	for name := range metricRules.GetMetricRules() {
+		metricNames = append(metricNames, name)
+	}
+	slices.Sort(metricNames)
+
+	for _, name := range metricNames {
+		value, ok := (*metrics)[name]
+		if !ok {
+			continue
+		}
+		threshold := matchThreshold(metricRules.GetMetricRules()[name], value)
+		if threshold == nil {
+			continue
+		}
+
+		// Like the transaction throttler, the priority is the chance of the query being subject
+		// to throttling at all.
+		percentage := float64(threshold.GetThrottle()) / 100 * float64(attrs.Priority) / float64(sqlparser.MaxPriorityValue)
+		if rand.Float64() >= percentage {
+			continue
+		}
+
+		decision := registry.ThrottleDecision{
+			Throttle:           true,
+			Message:            fmt.Sprintf("[VTTabletThrottler] Query throttled: workload=%s priority=%d metric=%s value=%.2f threshold=%.2f throttle=%.0f%%", attrs.WorkloadName, attrs.Priority, name, value, threshold.GetAbove(), percentage*100),
+			MetricName:         name,
+			MetricValue:        value,
+			Threshold:          threshold.GetAbove(),
+			ThrottlePercentage: percentage,
+		}
+		if cfg.GetAction() == querythrottlerpb.ThrottleAction_DELAY {
+			decision.MaxDelay = time.Duration(cfg.GetMaxDelayMs()) * time.Millisecond
+		}
+		return decision
+	}
+
+	return registry.ThrottleDecision{Message: "TabletThrottlerStrategy: no threshold exceeded"}
+}
+
+// StillThrottled is part of the registry.DelayRecheckable interface. It returns whether the metric
+// of the given decision is still above the decision's threshold, without rolling the throttle
+// percentage again.
+func (s *TabletThrottlerStrategy) StillThrottled(decision registry.ThrottleDecision) bool {
+	metrics := s.metrics.Load()
+	if metrics == nil {
+		return false
+	}
+	value, ok := (*metrics)[decision.MetricName]
+	return ok && value > decision.Threshold
+}
+
+// matchMetricRules returns the metric rules that apply to the given workload, tablet type and statement type,
+// or nil if there are none.
+func matchMetricRules(cfg *querythrottlerpb.TabletStrategyConfig, workload, tabletType, statementType string) *querythrottlerpb.MetricRuleSet {
+	tabletRules := cfg.GetTabletRules()
+	if workloadRules, ok := cfg.GetWorkloadRules()[workload]; ok {
+		tabletRules = workloadRules.GetTabletRules()
+	}
+
+	statementRules, ok := tabletRules[tabletType]
+	if !ok {
+		statementRules, ok = tabletRules[anyRuleKey]
+		if !ok {
+			return nil
+		}
+	}
+
+	metricRules, ok := statementRules.GetStatementRules()[statementType]
+	if !ok {
+		metricRules = statementRules.GetStatementRules()[anyRuleKey]
+	}
+	return metricRules
+}
+
+// matchThreshold returns the threshold with the highest Above value that the given value is above,
+// or nil if the value is not above any threshold.
+func matchThreshold(rule *querythrottlerpb.MetricRule, value float64) *querythrottlerpb.ThrottleThreshold {
+	var matched *querythrottlerpb.ThrottleThreshold
+	for _, threshold := range rule.GetThresholds() {
+		if value > threshold.GetAbove() && (matched == nil || threshold.GetAbove() > matched.GetAbove()) {
+			matched = threshold
+		}
+	}
+	return matched
+}
+
+// configMetricNames returns the sorted names of all the metrics referenced by the config.
+func configMetricNames(cfg *querythrottlerpb.TabletStrategyConfig) base.MetricNames {
+	names := map[string]struct{}{}
+	addTabletRules := func(tabletRules map[string]*querythrottlerpb.StatementRuleSet) {
+		for _, statementRules := range tabletRules {
+			for _, metricRules := range statementRules.GetStatementRules() {
+				for name := range metricRules.GetMetricRules() {
+					names[name] = struct{}{}
+				}
+			}
+		}
+	}
+	addTabletRules(cfg.GetTabletRules())
+	for _, workloadRules := range cfg.GetWorkloadRules() {
+		addTabletRules(workloadRules.GetTabletRules())
+	}
+
+	metricNames := make(base.MetricNames, 0, len(names))
+	for name := range names {
+		metricNames = append(metricNames, base.MetricName(name))
+	}
+	slices.Sort(metricNames)
+	return metricNames
+}
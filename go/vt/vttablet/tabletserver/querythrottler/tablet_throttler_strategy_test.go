@@ -0,0 +1,321 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package querythrottler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	querythrottlerpb "vitess.io/vitess/go/vt/proto/querythrottler"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/querythrottler/registry"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
+)
+
+// thresholdRules returns a MetricRuleSet throttling the given percentage of queries when the metric is above the given value.
+func thresholdRules(metricName string, above float64, throttlePercentage int32) *querythrottlerpb.MetricRuleSet {
+	return &querythrottlerpb.MetricRuleSet{
+		MetricRules: map[string]*querythrottlerpb.MetricRule{
+			metricName: {Thresholds: []*querythrottlerpb.ThrottleThreshold{{Above: above, Throttle: throttlePercentage}}},
+		},
+	}
+}
+
+// newTestTabletThrottlerStrategy returns a strategy with the given config, whose metrics are the given values.
+func newTestTabletThrottlerStrategy(t *testing.T, cfg *querythrottlerpb.TabletStrategyConfig, metrics map[string]float64) *TabletThrottlerStrategy {
+	strategy, err := tabletThrottlerStrategyFactory{}.New(registry.Deps{ThrottleClient: &throttle.Client{}}, &querythrottlerpb.Config{
+		Enabled:              true,
+		Strategy:             querythrottlerpb.ThrottlingStrategy_TABLET_THROTTLER,
+		TabletStrategyConfig: cfg,
+	})
+	require.NoError(t, err)
+
+	s := strategy.(*TabletThrottlerStrategy)
+	s.checkMetrics = func(ctx context.Context, metricNames base.MetricNames) *throttle.CheckResult {
+		checkResult := &throttle.CheckResult{Metrics: map[string]*throttle.MetricResult{}}
+		for _, name := range metricNames {
+			if value, ok := metrics[name.String()]; ok {
+				checkResult.Metrics[name.String()] = &throttle.MetricResult{Value: value}
+			}
+		}
+		return checkResult
+	}
+	s.refreshMetrics(t.Context())
+	return s
+}
+
+func TestTabletThrottlerStrategy_Registered(t *testing.T) {
+	strategy := registry.CreateStrategy(&querythrottlerpb.Config{
+		Enabled:  true,
+		Strategy: querythrottlerpb.ThrottlingStrategy_TABLET_THROTTLER,
+	}, registry.Deps{ThrottleClient: &throttle.Client{}})
+	require.IsType(t, &TabletThrottlerStrategy{}, strategy)
+	require.Equal(t, "TABLET_THROTTLER", strategy.GetStrategyName())
+
+	// Without a throttle client we fall back to NoOp.
+	strategy = registry.CreateStrategy(&querythrottlerpb.Config{
+		Enabled:  true,
+		Strategy: querythrottlerpb.ThrottlingStrategy_TABLET_THROTTLER,
+	}, registry.Deps{})
+	require.IsType(t, &registry.NoOpStrategy{}, strategy)
+}
+
+func TestTabletThrottlerStrategy_Evaluate(t *testing.T) {
+	cfg := &querythrottlerpb.TabletStrategyConfig{
+		TabletRules: map[string]*querythrottlerpb.StatementRuleSet{
+			"PRIMARY": {StatementRules: map[string]*querythrottlerpb.MetricRuleSet{
+				"INSERT": thresholdRules("lag", 5, 100),
+				"*":      thresholdRules("threads_running", 100, 100),
+			}},
+			"*": {StatementRules: map[string]*querythrottlerpb.MetricRuleSet{
+				"SELECT": thresholdRules("loadavg", 2, 100),
+			}},
+		},
+		WorkloadRules: map[string]*querythrottlerpb.WorkloadRuleSet{
+			"batch": {TabletRules: map[string]*querythrottlerpb.StatementRuleSet{
+				"PRIMARY": {StatementRules: map[string]*querythrottlerpb.MetricRuleSet{
+					"*": thresholdRules("lag", 1, 100),
+				}},
+			}},
+		},
+	}
+	metrics := map[string]float64{
+		"lag":             3,
+		"threads_running": 150,
+		"loadavg":         1,
+	}
+
+	tests := []struct {
+		name           string
+		tabletType     topodatapb.TabletType
+		query          string
+		workload       string
+		priority       int
+		expectThrottle bool
+		expectMetric   string
+	}{
+		{
+			name:       "lag below insert threshold",
+			tabletType: topodatapb.TabletType_PRIMARY,
+			query:      "insert into t values (1)",
+			workload:   "oltp",
+			priority:   100,
+		},
+		{
+			name:           "statement wildcard on primary",
+			tabletType:     topodatapb.TabletType_PRIMARY,
+			query:          "update t set a = 1",
+			workload:       "oltp",
+			priority:       100,
+			expectThrottle: true,
+			expectMetric:   "threads_running",
+		},
+		{
+			name:       "tablet type wildcard below threshold",
+			tabletType: topodatapb.TabletType_REPLICA,
+			query:      "select * from t",
+			workload:   "oltp",
+			priority:   100,
+		},
+		{
+			name:       "no rules for statement type",
+			tabletType: topodatapb.TabletType_REPLICA,
+			query:      "update t set a = 1",
+			workload:   "oltp",
+			priority:   100,
+		},
+		{
+			name:           "workload rules override tablet rules",
+			tabletType:     topodatapb.TabletType_PRIMARY,
+			query:          "insert into t values (1)",
+			workload:       "batch",
+			priority:       100,
+			expectThrottle: true,
+			expectMetric:   "lag",
+		},
+		{
+			name:       "workload rules replace tablet rules",
+			tabletType: topodatapb.TabletType_REPLICA,
+			query:      "select * from t",
+			workload:   "batch",
+			priority:   100,
+		},
+		{
+			name:       "highest priority is never throttled",
+			tabletType: topodatapb.TabletType_PRIMARY,
+			query:      "update t set a = 1",
+			workload:   "oltp",
+			priority:   0,
+		},
+	}
+
+	s := newTestTabletThrottlerStrategy(t, cfg, metrics)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := s.Evaluate(t.Context(), tt.tabletType, &sqlparser.ParsedQuery{Query: tt.query}, 0, registry.QueryAttributes{WorkloadName: tt.workload, Priority: tt.priority})
+			require.Equal(t, tt.expectThrottle, decision.Throttle, decision.Message)
+			require.Equal(t, tt.expectMetric, decision.MetricName)
+			if tt.expectThrottle {
+				require.Equal(t, metrics[tt.expectMetric], decision.MetricValue)
+				require.Zero(t, decision.MaxDelay)
+			}
+		})
+	}
+}
+
+func TestTabletThrottlerStrategy_HighestThreshold(t *testing.T) {
+	cfg := &querythrottlerpb.TabletStrategyConfig{
+		TabletRules: map[string]*querythrottlerpb.StatementRuleSet{
+			"*": {StatementRules: map[string]*querythrottlerpb.MetricRuleSet{
+				"*": {MetricRules: map[string]*querythrottlerpb.MetricRule{
+					"lag": {Thresholds: []*querythrottlerpb.ThrottleThreshold{
+						{Above: 1, Throttle: 0},
+						{Above: 10, Throttle: 100},
+						{Above: 5, Throttle: 0},
+					}},
+				}},
+			}},
+		},
+	}
+	attrs := registry.QueryAttributes{WorkloadName: "oltp", Priority: 100}
+	query := &sqlparser.ParsedQuery{Query: "select 1"}
+
+	s := newTestTabletThrottlerStrategy(t, cfg, map[string]float64{"lag": 7})
+	require.False(t, s.Evaluate(t.Context(), topodatapb.TabletType_PRIMARY, query, 0, attrs).Throttle)
+
+	s = newTestTabletThrottlerStrategy(t, cfg, map[string]float64{"lag": 11})
+	decision := s.Evaluate(t.Context(), topodatapb.TabletType_PRIMARY, query, 0, attrs)
+	require.True(t, decision.Throttle)
+	require.Equal(t, 10.0, decision.Threshold)
+	require.Equal(t, 1.0, decision.ThrottlePercentage)
+}
+
+func TestTabletThrottlerStrategy_DelayAction(t *testing.T) {
+	cfg := &querythrottlerpb.TabletStrategyConfig{
+		TabletRules: map[string]*querythrottlerpb.StatementRuleSet{
+			"*": {StatementRules: map[string]*querythrottlerpb.MetricRuleSet{"*": thresholdRules("lag", 1, 100)}},
+		},
+		Action:     querythrottlerpb.ThrottleAction_DELAY,
+		MaxDelayMs: 1500,
+	}
+	s := newTestTabletThrottlerStrategy(t, cfg, map[string]float64{"lag": 2})
+
+	decision := s.Evaluate(t.Context(), topodatapb.TabletType_PRIMARY, &sqlparser.ParsedQuery{Query: "select 1"}, 0, registry.QueryAttributes{Priority: 100})
+	require.True(t, decision.Throttle)
+	require.Equal(t, 1500*time.Millisecond, decision.MaxDelay)
+}
+
+func TestTabletThrottlerStrategy_StillThrottled(t *testing.T) {
+	cfg := &querythrottlerpb.TabletStrategyConfig{
+		TabletRules: map[string]*querythrottlerpb.StatementRuleSet{
+			"*": {StatementRules: map[string]*querythrottlerpb.MetricRuleSet{"*": thresholdRules("lag", 10, 1)}},
+		},
+		Action:     querythrottlerpb.ThrottleAction_DELAY,
+		MaxDelayMs: 1500,
+	}
+	metrics := map[string]float64{"lag": 20}
+	s := newTestTabletThrottlerStrategy(t, cfg, metrics)
+	decision := registry.ThrottleDecision{Throttle: true, MetricName: "lag", Threshold: 10, ThrottlePercentage: 0.01}
+
+	// The throttle percentage is not rolled again: a decision made with a 1% chance still
+	// applies for as long as the metric is above the threshold.
+	for range 100 {
+		require.True(t, s.StillThrottled(decision))
+	}
+
+	metrics["lag"] = 5
+	s.refreshMetrics(t.Context())
+	require.False(t, s.StillThrottled(decision))
+
+	delete(metrics, "lag")
+	s.refreshMetrics(t.Context())
+	require.False(t, s.StillThrottled(decision))
+}
+
+func TestTabletThrottlerStrategy_UpdateConfig(t *testing.T) {
+	s := newTestTabletThrottlerStrategy(t, nil, map[string]float64{"lag": 2})
+	query := &sqlparser.ParsedQuery{Query: "select 1"}
+	attrs := registry.QueryAttributes{Priority: 100}
+
+	// No rules, no metrics to fetch.
+	require.Nil(t, s.metrics.Load())
+	require.False(t, s.Evaluate(t.Context(), topodatapb.TabletType_PRIMARY, query, 0, attrs).Throttle)
+
+	s.UpdateConfig(&querythrottlerpb.Config{TabletStrategyConfig: &querythrottlerpb.TabletStrategyConfig{
+		TabletRules: map[string]*querythrottlerpb.StatementRuleSet{
+			"*": {StatementRules: map[string]*querythrottlerpb.MetricRuleSet{"*": thresholdRules("lag", 1, 100)}},
+		},
+	}})
+	s.refreshMetrics(t.Context())
+	require.True(t, s.Evaluate(t.Context(), topodatapb.TabletType_PRIMARY, query, 0, attrs).Throttle)
+}
+
+func TestTabletThrottlerStrategy_IgnoresMetricErrors(t *testing.T) {
+	cfg := &querythrottlerpb.TabletStrategyConfig{
+		TabletRules: map[string]*querythrottlerpb.StatementRuleSet{
+			"*": {StatementRules: map[string]*querythrottlerpb.MetricRuleSet{
+				"*": {MetricRules: map[string]*querythrottlerpb.MetricRule{
+					"lag":     {Thresholds: []*querythrottlerpb.ThrottleThreshold{{Above: 1, Throttle: 100}}},
+					"loadavg": {Thresholds: []*querythrottlerpb.ThrottleThreshold{{Above: 1, Throttle: 100}}},
+				}},
+			}},
+		},
+	}
+	s := newTestTabletThrottlerStrategy(t, cfg, nil)
+	s.checkMetrics = func(ctx context.Context, metricNames base.MetricNames) *throttle.CheckResult {
+		require.Equal(t, base.MetricNames{"lag", "loadavg"}, metricNames)
+		return &throttle.CheckResult{Metrics: map[string]*throttle.MetricResult{
+			"lag":     {Value: 5, Error: base.ErrThresholdExceeded},
+			"loadavg": {Value: 5, Error: errors.New("unable to read loadavg")},
+		}}
+	}
+	s.refreshMetrics(t.Context())
+	require.Equal(t, map[string]float64{"lag": 5}, *s.metrics.Load())
+}
+
+func TestTabletThrottlerStrategy_StartStop(t *testing.T) {
+	cfg := &querythrottlerpb.TabletStrategyConfig{
+		TabletRules: map[string]*querythrottlerpb.StatementRuleSet{
+			"*": {StatementRules: map[string]*querythrottlerpb.MetricRuleSet{"*": thresholdRules("lag", 1, 100)}},
+		},
+	}
+	s := newTestTabletThrottlerStrategy(t, cfg, nil)
+	s.refreshInterval = 10 * time.Millisecond
+
+	var checks atomic.Int64
+	s.checkMetrics = func(ctx context.Context, metricNames base.MetricNames) *throttle.CheckResult {
+		checks.Add(1)
+		return &throttle.CheckResult{}
+	}
+
+	s.Start()
+	s.Start()
+	require.Eventually(t, func() bool { return checks.Load() >= 3 }, 5*time.Second, 10*time.Millisecond)
+	s.Stop()
+	s.Stop()
+
+	stopped := checks.Load()
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, stopped, checks.Load(), "metrics should not be fetched after Stop")
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// Limiter admits the queries that match a QRConcurrencyLimit or
+// QRRateLimit rule. Copies of a Rule share the same Limiter, so the
+// limits apply across all the query plans the rule was filtered into.
+type Limiter struct {
+	maxConcurrency int64
+	maxQPS         float64
+	burst          int
+	queueTimeout   time.Duration
+
+	sem  *semaphore.Weighted
+	rate *rate.Limiter
+
+	inFlight atomic.Int64
+	queued   atomic.Int64
+	admitted atomic.Int64
+	rejected atomic.Int64
+}
+
+// LimiterStats is a snapshot of the counters of a Limiter.
+type LimiterStats struct {
+	InFlight int64
+	Queued   int64
+	Admitted int64
+	Rejected int64
+}
+
+// NewConcurrencyLimiter creates a Limiter that admits up to maxConcurrency
+// queries at a time. Queries over the limit wait up to queueTimeout for a
+// slot to free up, or are rejected right away if queueTimeout is 0.
+func NewConcurrencyLimiter(maxConcurrency int64, queueTimeout time.Duration) *Limiter {
+	return &Limiter{
+		maxConcurrency: maxConcurrency,
+		queueTimeout:   queueTimeout,
+		sem:            semaphore.NewWeighted(maxConcurrency),
+	}
+}
+
+// NewRateLimiter creates a Limiter that admits up to maxQPS queries per
+// second, with bursts of up to burst queries. A burst smaller than 1 is
+// derived from maxQPS. Queries over the limit wait up to queueTimeout for
+// their turn, or are rejected right away if queueTimeout is 0.
+func NewRateLimiter(maxQPS float64, burst int, queueTimeout time.Duration) *Limiter {
+	lim := &Limiter{
+		maxQPS:       maxQPS,
+		burst:        burst,
+		queueTimeout: queueTimeout,
+	}
+	if burst < 1 {
+		burst = max(1, int(maxQPS))
+	}
+	lim.rate = rate.NewLimiter(rate.Limit(maxQPS), burst)
+	return lim
+}
+
+// Acquire admits a query, waiting for up to the queue timeout of the
+// Limiter if it's over its limit. The returned function must be called
+// once the query is done.
+func (lim *Limiter) Acquire(ctx context.Context) (release func(), err error) {
+	if lim.sem != nil {
+		err = lim.acquireConcurrency(ctx)
+	} else {
+		err = lim.acquireRate(ctx)
+	}
+	if err != nil {
+		lim.rejected.Add(1)
+		return nil, err
+	}
+	lim.admitted.Add(1)
+	lim.inFlight.Add(1)
+	var released atomic.Bool
+	return func() {
+		if !released.CompareAndSwap(false, true) {
+			return
+		}
+		lim.inFlight.Add(-1)
+		if lim.sem != nil {
+			lim.sem.Release(1)
+		}
+	}, nil
+}
+
+func (lim *Limiter) acquireConcurrency(ctx context.Context) error {
+	if lim.sem.TryAcquire(1) {
+		return nil
+	}
+	if lim.queueTimeout > 0 {
+		lim.queued.Add(1)
+		defer lim.queued.Add(-1)
+
+		ctx, cancel := context.WithTimeout(ctx, lim.queueTimeout)
+		defer cancel()
+		if lim.sem.Acquire(ctx, 1) == nil {
+			return nil
+		}
+	}
+	return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "concurrency limit of %d exceeded", lim.maxConcurrency)
+}
+
+func (lim *Limiter) acquireRate(ctx context.Context) error {
+	if lim.rate.Allow() {
+		return nil
+	}
+	if lim.queueTimeout > 0 {
+		r := lim.rate.Reserve()
+		delay := r.Delay()
+		if deadline, ok := ctx.Deadline(); r.OK() && delay <= lim.queueTimeout && (!ok || time.Until(deadline) >= delay) {
+			lim.queued.Add(1)
+			defer lim.queued.Add(-1)
+
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				return nil
+			case <-ctx.Done():
+			}
+		}
+		r.Cancel()
+	}
+	return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "rate limit of %v QPS exceeded", lim.maxQPS)
+}
+
+// Stats returns a snapshot of the counters of the Limiter.
+func (lim *Limiter) Stats() LimiterStats {
+	return LimiterStats{
+		InFlight: lim.inFlight.Load(),
+		Queued:   lim.queued.Load(),
+		Admitted: lim.admitted.Load(),
+		Rejected: lim.rejected.Load(),
+	}
+}
+
+// MaxConcurrency returns the maximum number of concurrent queries, or 0
+// if the Limiter is not a concurrency limiter.
+func (lim *Limiter) MaxConcurrency() int64 {
+	return lim.maxConcurrency
+}
+
+// MaxQPS returns the maximum number of queries per second, or 0 if the
+// Limiter is not a rate limiter.
+func (lim *Limiter) MaxQPS() float64 {
+	return lim.maxQPS
+}
+
+// Burst returns the configured burst of a rate limiter.
+func (lim *Limiter) Burst() int {
+	return lim.burst
+}
+
+// QueueTimeout returns how long a query waits to be admitted before it
+// is rejected.
+func (lim *Limiter) QueueTimeout() time.Duration {
+	return lim.queueTimeout
+}
+
+// Equal returns true if other is configured with the same limits as this
+// Limiter. The current state of the limiters is not compared.
+func (lim *Limiter) Equal(other *Limiter) bool {
+	if lim == nil || other == nil {
+		return lim == nil && other == nil
+	}
+	return lim.maxConcurrency == other.maxConcurrency &&
+		lim.maxQPS == other.maxQPS &&
+		lim.burst == other.burst &&
+		lim.queueTimeout == other.queueTimeout
+}
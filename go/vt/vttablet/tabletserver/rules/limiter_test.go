@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	ctx := context.Background()
+	lim := NewConcurrencyLimiter(2, 0)
+
+	release1, err := lim.Acquire(ctx)
+	require.NoError(t, err)
+	release2, err := lim.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, LimiterStats{InFlight: 2, Admitted: 2}, lim.Stats())
+
+	_, err = lim.Acquire(ctx)
+	require.EqualError(t, err, "concurrency limit of 2 exceeded")
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+
+	// releasing twice only frees one slot
+	release1()
+	release1()
+	release3, err := lim.Acquire(ctx)
+	require.NoError(t, err)
+	_, err = lim.Acquire(ctx)
+	require.Error(t, err)
+
+	release2()
+	release3()
+	assert.Equal(t, LimiterStats{Admitted: 3, Rejected: 2}, lim.Stats())
+}
+
+func TestConcurrencyLimiterQueue(t *testing.T) {
+	ctx := context.Background()
+	lim := NewConcurrencyLimiter(1, time.Minute)
+
+	release, err := lim.Acquire(ctx)
+	require.NoError(t, err)
+
+	admitted := make(chan error)
+	go func() {
+		release, err := lim.Acquire(ctx)
+		if err == nil {
+			release()
+		}
+		admitted <- err
+	}()
+	assert.Eventually(t, func() bool {
+		return lim.Stats().Queued == 1
+	}, 5*time.Second, time.Millisecond)
+	release()
+	require.NoError(t, <-admitted)
+
+	// the query gives up when its context is done
+	release, err = lim.Acquire(ctx)
+	require.NoError(t, err)
+	defer release()
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = lim.Acquire(cancelled)
+	require.Error(t, err)
+	assert.Equal(t, LimiterStats{InFlight: 1, Admitted: 3, Rejected: 1}, lim.Stats())
+}
+
+func TestRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	lim := NewRateLimiter(1, 2, 0)
+
+	for range 2 {
+		release, err := lim.Acquire(ctx)
+		require.NoError(t, err)
+		release()
+	}
+	_, err := lim.Acquire(ctx)
+	require.EqualError(t, err, "rate limit of 1 QPS exceeded")
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	assert.Equal(t, LimiterStats{Admitted: 2, Rejected: 1}, lim.Stats())
+}
+
+func TestRateLimiterQueue(t *testing.T) {
+	ctx := context.Background()
+	lim := NewRateLimiter(100, 1, time.Second)
+
+	// queries wait for their turn instead of failing
+	start := time.Now()
+	for range 3 {
+		release, err := lim.Acquire(ctx)
+		require.NoError(t, err)
+		release()
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+
+	// but not longer than the queue timeout
+	lim = NewRateLimiter(0.1, 1, 10*time.Millisecond)
+	release, err := lim.Acquire(ctx)
+	require.NoError(t, err)
+	release()
+	_, err = lim.Acquire(ctx)
+	require.EqualError(t, err, "rate limit of 0.1 QPS exceeded")
+}
+
+func TestLimiterEqual(t *testing.T) {
+	assert.True(t, NewConcurrencyLimiter(1, time.Second).Equal(NewConcurrencyLimiter(1, time.Second)))
+	assert.False(t, NewConcurrencyLimiter(1, time.Second).Equal(NewConcurrencyLimiter(1, 0)))
+	assert.False(t, NewConcurrencyLimiter(1, 0).Equal(NewRateLimiter(1, 0, 0)))
+	assert.False(t, NewRateLimiter(1, 0, 0).Equal(nil))
+
+	var lim *Limiter
+	assert.True(t, lim.Equal(nil))
+}
@@ -19,6 +19,7 @@ package rules
 import (
 	"encoding/json"
 	"errors"
+	"slices"
 	"sync"
 
 	"vitess.io/vitess/go/vt/log"
@@ -102,3 +103,24 @@ func (qri *Map) MarshalJSON() ([]byte, error) {
 	defer qri.mu.Unlock()
 	return json.Marshal(qri.queryRulesMap)
 }
+
+// ForEachRule calls f for every rule of every source, ordered by source name.
+func (qri *Map) ForEachRule(f func(ruleSource string, qr *Rule)) {
+	qri.mu.Lock()
+	sources := make([]string, 0, len(qri.queryRulesMap))
+	for ruleSource := range qri.queryRulesMap {
+		sources = append(sources, ruleSource)
+	}
+	slices.Sort(sources)
+	ruleSets := make([]*Rules, 0, len(sources))
+	for _, ruleSource := range sources {
+		ruleSets = append(ruleSets, qri.queryRulesMap[ruleSource])
+	}
+	qri.mu.Unlock()
+
+	for i, ruleSet := range ruleSets {
+		ruleSet.ForEach(func(qr *Rule) {
+			f(sources[i], qr)
+		})
+	}
+}
@@ -182,3 +182,22 @@ func TestMapJSON(t *testing.T) {
 	}`)
 	assert.Equal(t, want, got, "MapJSON")
 }
+
+func TestMapForEachRule(t *testing.T) {
+	setupRules()
+	qri := NewMap()
+	qri.RegisterSource(denyListQueryRules)
+	_ = qri.SetRules(denyListQueryRules, denyRules)
+	qri.RegisterSource(customQueryRules)
+	_ = qri.SetRules(customQueryRules, otherRules)
+
+	var got []string
+	qri.ForEachRule(func(ruleSource string, qr *Rule) {
+		got = append(got, ruleSource+"/"+qr.Name)
+	})
+	want := []string{
+		"CUSTOM_QUERY_RULES/customrule_ban_bindvar",
+		"DENYLIST_QUERY_RULES/denied_table",
+	}
+	assert.Equal(t, want, got)
+}
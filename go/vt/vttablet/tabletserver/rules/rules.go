@@ -182,12 +182,30 @@ func (qrs *Rules) GetAction(
 	timeout time.Duration,
 	desc string,
 ) {
+	return qrs.GetRule(ip, user, bindVars, marginComments).ActionInfo()
+}
+
+// GetRule runs the input against the rules engine and returns the first rule
+// that fires, or nil if no rule does.
+func (qrs *Rules) GetRule(
+	ip,
+	user string,
+	bindVars map[string]*querypb.BindVariable,
+	marginComments sqlparser.MarginComments,
+) *Rule {
 	for _, qr := range qrs.rules {
 		if act := qr.GetAction(ip, user, bindVars, marginComments); act != QRContinue {
-			return act, qr.cancelCtx, qr.timeout, qr.Description
+			return qr
 		}
 	}
-	return QRContinue, nil, 0, ""
+	return nil
+}
+
+// ForEach calls f for every rule, in order.
+func (qrs *Rules) ForEach(f func(qr *Rule)) {
+	for _, qr := range qrs.rules {
+		f(qr)
+	}
 }
 
 // -----------------------------------------------
@@ -226,6 +244,10 @@ type Rule struct {
 
 	// a rule can timeout.
 	timeout time.Duration
+
+	// limiter admits the queries of QRConcurrencyLimit and QRRateLimit
+	// rules. It is shared by all the copies of the rule.
+	limiter *Limiter
 }
 
 type namedRegexp struct {
@@ -271,6 +293,7 @@ func (qr *Rule) Equal(other *Rule) bool {
 		qr.leadingComment.Equal(other.leadingComment) &&
 		qr.trailingComment.Equal(other.trailingComment) &&
 		qr.timeout == other.timeout &&
+		qr.limiter.Equal(other.limiter) &&
 		reflect.DeepEqual(qr.plans, other.plans) &&
 		reflect.DeepEqual(qr.tableNames, other.tableNames) &&
 		reflect.DeepEqual(qr.bindVarConds, other.bindVarConds) &&
@@ -290,6 +313,7 @@ func (qr *Rule) Copy() (newqr *Rule) {
 		act:             qr.act,
 		cancelCtx:       qr.cancelCtx,
 		timeout:         qr.timeout,
+		limiter:         qr.limiter,
 	}
 	if qr.plans != nil {
 		newqr.plans = make([]planbuilder.PlanType, len(qr.plans))
@@ -341,10 +365,72 @@ func (qr *Rule) MarshalJSON() ([]byte, error) {
 	if qr.timeout != 0 {
 		safeEncode(b, `,"Timeout":`, qr.timeout)
 	}
+	if qr.limiter != nil {
+		if qr.limiter.maxConcurrency != 0 {
+			safeEncode(b, `,"MaxConcurrency":`, qr.limiter.maxConcurrency)
+		}
+		if qr.limiter.maxQPS != 0 {
+			safeEncode(b, `,"MaxQPS":`, qr.limiter.maxQPS)
+		}
+		if qr.limiter.burst != 0 {
+			safeEncode(b, `,"Burst":`, qr.limiter.burst)
+		}
+		if qr.limiter.queueTimeout != 0 {
+			safeEncode(b, `,"QueueTimeout":`, qr.limiter.queueTimeout.String())
+		}
+	}
 	_, _ = b.WriteString("}")
 	return b.Bytes(), nil
 }
 
+// ActionInfo returns the action of the rule along with what's needed to
+// carry it out. A nil Rule continues.
+func (qr *Rule) ActionInfo() (action Action, cancelCtx context.Context, timeout time.Duration, desc string) {
+	if qr == nil {
+		return QRContinue, nil, 0, ""
+	}
+	return qr.act, qr.cancelCtx, qr.timeout, qr.Description
+}
+
+// Limiter returns the Limiter that admits the queries of a
+// QRConcurrencyLimit or QRRateLimit rule, or nil for other rules.
+func (qr *Rule) Limiter() *Limiter {
+	return qr.limiter
+}
+
+// SetConcurrencyLimit makes the rule admit up to maxConcurrency matching
+// queries at a time. Queries over the limit wait up to queueTimeout, or
+// fail right away if queueTimeout is 0.
+func (qr *Rule) SetConcurrencyLimit(maxConcurrency int64, queueTimeout time.Duration) error {
+	if maxConcurrency <= 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxConcurrency must be positive for %s: %d", QRConcurrencyLimit, maxConcurrency)
+	}
+	if queueTimeout < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "QueueTimeout must not be negative: %v", queueTimeout)
+	}
+	qr.act = QRConcurrencyLimit
+	qr.limiter = NewConcurrencyLimiter(maxConcurrency, queueTimeout)
+	return nil
+}
+
+// SetRateLimit makes the rule admit up to maxQPS matching queries per second,
+// with bursts of up to burst queries. Queries over the limit wait up to
+// queueTimeout, or fail right away if queueTimeout is 0.
+func (qr *Rule) SetRateLimit(maxQPS float64, burst int, queueTimeout time.Duration) error {
+	if maxQPS <= 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxQPS must be positive for %s: %v", QRRateLimit, maxQPS)
+	}
+	if burst < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Burst must not be negative: %d", burst)
+	}
+	if queueTimeout < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "QueueTimeout must not be negative: %v", queueTimeout)
+	}
+	qr.act = QRRateLimit
+	qr.limiter = NewRateLimiter(maxQPS, burst, queueTimeout)
+	return nil
+}
+
 // SetIPCond adds a regular expression condition for the client IP.
 // It has to be a full match (not substring).
 func (qr *Rule) SetIPCond(pattern string) (err error) {
@@ -570,23 +656,31 @@ const (
 	QRFail
 	QRFailRetry
 	QRBuffer
+	QRConcurrencyLimit
+	QRRateLimit
 )
 
-// MarshalJSON marshals to JSON.
-func (act Action) MarshalJSON() ([]byte, error) {
-	// If we add more actions, we'll need to use a map.
-	var str string
+// String returns the name of the action.
+func (act Action) String() string {
 	switch act {
 	case QRFail:
-		str = "FAIL"
+		return "FAIL"
 	case QRFailRetry:
-		str = "FAIL_RETRY"
+		return "FAIL_RETRY"
 	case QRBuffer:
-		str = "BUFFER"
+		return "BUFFER"
+	case QRConcurrencyLimit:
+		return "CONCURRENCY_LIMIT"
+	case QRRateLimit:
+		return "RATE_LIMIT"
 	default:
-		str = "INVALID"
+		return "INVALID"
 	}
-	return json.Marshal(str)
+}
+
+// MarshalJSON marshals to JSON.
+func (act Action) MarshalJSON() ([]byte, error) {
+	return json.Marshal(act.String())
 }
 
 // BindVarCond represents a bind var condition.
@@ -868,12 +962,19 @@ func MapStrOperator(strop string) (op Operator, err error) {
 // BuildQueryRule builds a query rule from a ruleInfo.
 func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 	qr = NewQueryRule("", "", QRFail)
+	var (
+		maxConcurrency int64
+		maxQPS         float64
+		burst          int64
+		queueTimeout   time.Duration
+	)
 	for k, v := range ruleInfo {
 		var sv string
 		var lv []any
+		var nv float64
 		var ok bool
 		switch k {
-		case "Name", "Description", "RequestIP", "User", "Query", "Action", "LeadingComment", "TrailingComment":
+		case "Name", "Description", "RequestIP", "User", "Query", "Action", "LeadingComment", "TrailingComment", "QueueTimeout":
 			sv, ok = v.(string)
 			if !ok {
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want string for %s", k)
@@ -883,6 +984,17 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 			if !ok {
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want list for %s", k)
 			}
+		case "MaxConcurrency", "MaxQPS", "Burst":
+			switch n := v.(type) {
+			case json.Number:
+				nv, err = n.Float64()
+				ok = err == nil
+			case float64:
+				nv, ok = n, true
+			}
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want number for %s", k)
+			}
 		default:
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unrecognized tag %s", k)
 		}
@@ -955,9 +1067,50 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 				qr.act = QRFailRetry
 			case "BUFFER":
 				qr.act = QRBuffer
+			case "CONCURRENCY_LIMIT":
+				qr.act = QRConcurrencyLimit
+			case "RATE_LIMIT":
+				qr.act = QRRateLimit
 			default:
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid Action %s", sv)
 			}
+		case "MaxConcurrency":
+			maxConcurrency = int64(nv)
+			if float64(maxConcurrency) != nv {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want integer for MaxConcurrency: %v", nv)
+			}
+		case "MaxQPS":
+			maxQPS = nv
+		case "Burst":
+			burst = int64(nv)
+			if float64(burst) != nv {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want integer for Burst: %v", nv)
+			}
+		case "QueueTimeout":
+			queueTimeout, err = time.ParseDuration(sv)
+			if err != nil {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid QueueTimeout: %v", sv)
+			}
+		}
+	}
+	switch qr.act {
+	case QRConcurrencyLimit:
+		if maxQPS != 0 || burst != 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxQPS and Burst are only valid for %s", QRRateLimit)
+		}
+		if err := qr.SetConcurrencyLimit(maxConcurrency, queueTimeout); err != nil {
+			return nil, err
+		}
+	case QRRateLimit:
+		if maxConcurrency != 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxConcurrency is only valid for %s", QRConcurrencyLimit)
+		}
+		if err := qr.SetRateLimit(maxQPS, int(burst), queueTimeout); err != nil {
+			return nil, err
+		}
+	default:
+		if maxConcurrency != 0 || maxQPS != 0 || burst != 0 || queueTimeout != 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "MaxConcurrency, MaxQPS, Burst and QueueTimeout are only valid for %s and %s", QRConcurrencyLimit, QRRateLimit)
 		}
 	}
 	return qr, nil
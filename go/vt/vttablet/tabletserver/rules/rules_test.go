@@ -470,6 +470,9 @@ func TestAction(t *testing.T) {
 		Trailing: "other trailing comments",
 	}
 
+	assert.Same(t, qr1, qrs.GetRule("123", "user1", bv, mc))
+	assert.Nil(t, qrs.GetRule("1234", "user1", bv, mc))
+
 	action, cancelCtx, timeout, desc := qrs.GetAction("123", "user1", bv, mc)
 	assert.Equalf(t, action, QRFail, "expected fail, got %v", action)
 	assert.Equalf(t, timeout, time.Duration(0), "expected zero timeout")
@@ -547,6 +550,40 @@ func TestImport(t *testing.T) {
 	assert.Equalf(t, want, got, "qrs:\n%s, want\n%s", got, want)
 }
 
+func TestImportLimits(t *testing.T) {
+	qrs := New()
+	jsondata := `[{
+		"Description": "runaway reports",
+		"Name": "limit_reports",
+		"User": "reports",
+		"Action": "CONCURRENCY_LIMIT",
+		"MaxConcurrency": 4,
+		"QueueTimeout": "100ms"
+	},{
+		"Description": "hot table",
+		"Name": "limit_hot",
+		"TableNames": ["hot"],
+		"Action": "RATE_LIMIT",
+		"MaxQPS": 2.5,
+		"Burst": 5
+	}]`
+	err := qrs.UnmarshalJSON([]byte(jsondata))
+	require.NoError(t, err)
+	got := marshalled(qrs)
+	want := compacted(jsondata)
+	assert.Equal(t, want, got)
+
+	// Copies share the limiter so that the limit applies across query plans.
+	cpy := qrs.Copy()
+	assert.True(t, cpy.Equal(qrs))
+	assert.Same(t, qrs.rules[0].Limiter(), cpy.rules[0].Limiter())
+
+	other := New()
+	err = other.UnmarshalJSON([]byte(strings.Replace(jsondata, `"MaxConcurrency": 4`, `"MaxConcurrency": 5`, 1)))
+	require.NoError(t, err)
+	assert.False(t, other.Equal(qrs))
+}
+
 type ValidJSONCase struct {
 	input string
 	op    Operator
@@ -644,6 +681,16 @@ var invalidjsons = []InvalidJSONCase{
 	{`[{"BindVarConds": [{"Name": "a", "OnAbsent": true, "OnMismatch": true, "Operator": "NOMATCH", "Value": "["}]}]`, "processing [: error parsing regexp: missing closing ]: `[$`"},
 	{`[{"Action": 1 }]`, "want string for Action"},
 	{`[{"Action": "foo" }]`, "invalid Action foo"},
+	{`[{"MaxQPS": "1" }]`, "want number for MaxQPS"},
+	{`[{"Action": "CONCURRENCY_LIMIT" }]`, "MaxConcurrency must be positive for CONCURRENCY_LIMIT: 0"},
+	{`[{"Action": "CONCURRENCY_LIMIT", "MaxConcurrency": 1.5 }]`, "want integer for MaxConcurrency: 1.5"},
+	{`[{"Action": "CONCURRENCY_LIMIT", "MaxConcurrency": 1, "MaxQPS": 1 }]`, "MaxQPS and Burst are only valid for RATE_LIMIT"},
+	{`[{"Action": "RATE_LIMIT", "MaxQPS": -1 }]`, "MaxQPS must be positive for RATE_LIMIT: -1"},
+	{`[{"Action": "RATE_LIMIT", "MaxQPS": 1, "Burst": -1 }]`, "Burst must not be negative: -1"},
+	{`[{"Action": "RATE_LIMIT", "MaxQPS": 1, "MaxConcurrency": 1 }]`, "MaxConcurrency is only valid for CONCURRENCY_LIMIT"},
+	{`[{"Action": "RATE_LIMIT", "MaxQPS": 1, "QueueTimeout": "1" }]`, "invalid QueueTimeout: 1"},
+	{`[{"Action": "RATE_LIMIT", "MaxQPS": 1, "QueueTimeout": "-1s" }]`, "QueueTimeout must not be negative: -1s"},
+	{`[{"Action": "FAIL", "QueueTimeout": "1s" }]`, "MaxConcurrency, MaxQPS, Burst and QueueTimeout are only valid for CONCURRENCY_LIMIT and RATE_LIMIT"},
 }
 
 func TestInvalidJSON(t *testing.T) {
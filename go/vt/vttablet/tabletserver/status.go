@@ -73,6 +73,7 @@ const (
       <a href="{{.Prefix}}/debug/tablet_plans">Schema&nbsp;Query&nbsp;Plans</a></br>
       <a href="{{.Prefix}}/debug/query_stats">Schema&nbsp;Query&nbsp;Stats</a></br>
       <a href="{{.Prefix}}/queryz">Query&nbsp;Stats</a></br>
+      <a href="{{.Prefix}}/queryrulez">Query&nbsp;Rule&nbsp;Limits</a></br>
     </td>
     <td width="25%" border="">
       <a href="{{.Prefix}}/debug/consolidations">Consolidations</a></br>
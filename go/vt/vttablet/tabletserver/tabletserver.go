@@ -254,6 +254,7 @@ func NewTabletServer(ctx context.Context, env *vtenv.Environment, name string, c
 	tsv.registerHealthzHealthHandler()
 	tsv.registerDebugHealthHandler()
 	tsv.registerQueryzHandler()
+	tsv.registerQueryrulezHandler()
 	tsv.registerQuerylogzHandler()
 	tsv.registerTxlogzHandler()
 	tsv.registerQueryListHandlers([]*QueryList{tsv.statelessql, tsv.statefulql, tsv.olapql})
@@ -2106,6 +2107,12 @@ func (tsv *TabletServer) registerQueryzHandler() {
 	})
 }
 
+func (tsv *TabletServer) registerQueryrulezHandler() {
+	tsv.exporter.HandleFunc("/queryrulez", func(w http.ResponseWriter, r *http.Request) {
+		queryrulezHandler(tsv.qe.queryRuleSources, w, r)
+	})
+}
+
 func (tsv *TabletServer) registerQuerylogzHandler() {
 	tsv.exporter.HandleFunc("/querylogz", func(w http.ResponseWriter, r *http.Request) {
 		ch := tabletenv.StatsLogger.Subscribe("querylogz")
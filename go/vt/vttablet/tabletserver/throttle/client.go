@@ -122,6 +122,16 @@ func (c *Client) ThrottleCheckOK(ctx context.Context, overrideAppName throttlera
 	return checkResult, true
 }
 
+// CheckMetrics checks the throttler for the given metrics and returns the full check result,
+// including the current value of each metric. Unlike ThrottleCheckOK, the result is never
+// served from cache, so callers are expected to limit the rate at which they call this function.
+func (c *Client) CheckMetrics(ctx context.Context, metricNames base.MetricNames) *CheckResult {
+	if c == nil || c.throttler == nil {
+		return emptyCheckResult
+	}
+	return c.throttler.Check(ctx, c.appName.String(), metricNames, &c.flags)
+}
+
 // ThrottleCheckOKOrWait checks the throttler; if throttler is satisfied, the function returns 'true' immediately,
 // otherwise it briefly sleeps and returns 'false'.
 // Non-empty appName overrides the default appName.
@@ -66,6 +66,8 @@ const (
 	TableGCName   Name = "tablegc"
 	OnlineDDLName Name = "online-ddl"
 
+	LookupVindexVerifyName Name = "lookup-vindex-verify"
+
 	VReplicationName      Name = "vreplication"
 	VStreamerName         Name = "vstreamer"
 	VPlayerName           Name = "vplayer"
@@ -1070,6 +1070,21 @@ func (mr *MockTabletManagerClientMockRecorder) ValidateVReplicationPermissions(c
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateVReplicationPermissions", reflect.TypeOf((*MockTabletManagerClient)(nil).ValidateVReplicationPermissions), ctx, tablet, request)
 }
 
+// VerifyBackup mocks base method.
+func (m *MockTabletManagerClient) VerifyBackup(ctx context.Context, tablet *topodata.Tablet, req *tabletmanagerdata.VerifyBackupRequest) (logutil.EventStream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyBackup", ctx, tablet, req)
+	ret0, _ := ret[0].(logutil.EventStream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyBackup indicates an expected call of VerifyBackup.
+func (mr *MockTabletManagerClientMockRecorder) VerifyBackup(ctx, tablet, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyBackup", reflect.TypeOf((*MockTabletManagerClient)(nil).VerifyBackup), ctx, tablet, req)
+}
+
 // WaitForPosition mocks base method.
 func (m *MockTabletManagerClient) WaitForPosition(ctx context.Context, tablet *topodata.Tablet, pos string) error {
 	m.ctrl.T.Helper()
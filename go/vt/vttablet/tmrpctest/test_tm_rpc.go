@@ -1466,6 +1466,8 @@ var (
 	testBackupAllowPrimary      = false
 	testBackupCalled            = false
 	testRestoreFromBackupCalled = false
+	testVerifyBackupName        = "2025-01-01.000000.cell1-100"
+	testVerifyBackupCalled      = false
 )
 
 func (fra *fakeRPCTM) Backup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.BackupRequest) error {
@@ -1515,6 +1517,16 @@ func (fra *fakeRPCTM) RestoreFromBackup(ctx context.Context, logger logutil.Logg
 	return nil
 }
 
+func (fra *fakeRPCTM) VerifyBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.VerifyBackupRequest) error {
+	if fra.panics {
+		panic(errors.New("test-triggered panic"))
+	}
+	compare(fra.t, "VerifyBackup args", request.BackupName, testVerifyBackupName)
+	logStuff(logger, 10)
+	testVerifyBackupCalled = true
+	return nil
+}
+
 func (fra *fakeRPCTM) CheckThrottler(ctx context.Context, req *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
 	if fra.panics {
 		panic(errors.New("test-triggered panic"))
@@ -1554,6 +1566,29 @@ func tmRPCTestRestoreFromBackupPanic(ctx context.Context, t *testing.T, client t
 	expectHandleRPCPanic(t, "RestoreFromBackup", true /*verbose*/, err)
 }
 
+func tmRPCTestVerifyBackup(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	req := &tabletmanagerdatapb.VerifyBackupRequest{BackupName: testVerifyBackupName}
+	stream, err := client.VerifyBackup(ctx, tablet, req)
+	if err != nil {
+		t.Fatalf("VerifyBackup failed: %v", err)
+	}
+	err = compareLoggedStuff(t, "VerifyBackup", stream, 10)
+	compareError(t, "VerifyBackup", err, true, testVerifyBackupCalled)
+}
+
+func tmRPCTestVerifyBackupPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	req := &tabletmanagerdatapb.VerifyBackupRequest{BackupName: testVerifyBackupName}
+	stream, err := client.VerifyBackup(ctx, tablet, req)
+	if err != nil {
+		t.Fatalf("VerifyBackup failed: %v", err)
+	}
+	e, err := stream.Recv()
+	if err == nil {
+		t.Fatalf("Unexpected VerifyBackup logs: %v", e)
+	}
+	expectHandleRPCPanic(t, "VerifyBackup", true /*verbose*/, err)
+}
+
 func tmRPCTestCheckThrottler(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CheckThrottlerRequest) {
 	_, err := client.CheckThrottler(ctx, tablet, req)
 	expectHandleRPCPanic(t, "CheckThrottler", false /*verbose*/, err)
@@ -1649,6 +1684,7 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	// Backup / restore related methods
 	tmRPCTestBackup(ctx, t, client, tablet)
 	tmRPCTestRestoreFromBackup(ctx, t, client, tablet, restoreFromBackupRequest)
+	tmRPCTestVerifyBackup(ctx, t, client, tablet)
 
 	// Throttler related methods
 	tmRPCTestCheckThrottler(ctx, t, client, tablet, checkThrottlerRequest)
@@ -1711,6 +1747,7 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	// Backup / restore related methods
 	tmRPCTestBackupPanic(ctx, t, client, tablet)
 	tmRPCTestRestoreFromBackupPanic(ctx, t, client, tablet, restoreFromBackupRequest)
+	tmRPCTestVerifyBackupPanic(ctx, t, client, tablet)
 
 	client.Close()
 }